@@ -14,6 +14,7 @@ import (
 
 func main() {
 	var mw *walk.MainWindow
+	var lv *walk.LogView
 
 	if err := (MainWindow{
 		AssignTo: &mw,
@@ -21,22 +22,23 @@ func main() {
 		MinSize:  Size{320, 240},
 		Size:     Size{400, 600},
 		Layout:   VBox{MarginsZero: true},
+		Children: []Widget{
+			LogView{
+				AssignTo: &lv,
+				MaxLines: 5000,
+			},
+		},
 	}.Create()); err != nil {
 		log.Fatal(err)
 	}
 
-	lv, err := NewLogView(mw)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	lv.PostAppendText("XXX")
+	lv.AppendLine(walk.LogLevelInfo, "XXX")
 	log.SetOutput(lv)
 
 	go func() {
 		for i := 0; i < 10000; i++ {
 			time.Sleep(100 * time.Millisecond)
-			log.Println("Text" + "\r\n")
+			log.Println("Text")
 		}
 	}()
 
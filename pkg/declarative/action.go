@@ -19,15 +19,17 @@ type Shortcut struct {
 }
 
 type Action struct {
-	AssignTo    **walk.Action
-	Text        string
-	Image       interface{}
-	Checked     Property
-	Enabled     Property
-	Visible     Property
-	Shortcut    Shortcut
-	OnTriggered walk.EventHandler
-	Checkable   bool
+	AssignTo          **walk.Action
+	Text              string
+	Image             interface{}
+	Checked           Property
+	Enabled           Property
+	Visible           Property
+	Shortcut          Shortcut
+	OnTriggered       walk.EventHandler
+	CanExecute        func() bool
+	Checkable         bool
+	RequiresElevation bool
 }
 
 func (a Action) createAction(builder *Builder, menu *walk.Menu) (*walk.Action, error) {
@@ -53,6 +55,9 @@ func (a Action) createAction(builder *Builder, menu *walk.Menu) (*walk.Action, e
 	if err := setActionBoolOrCondition(action.SetVisible, action.SetVisibleCondition, a.Visible, "Action.Visible", builder); err != nil {
 		return nil, err
 	}
+	if a.CanExecute != nil {
+		action.SetCanExecute(a.CanExecute)
+	}
 
 	if err := action.SetCheckable(a.Checkable || action.CheckedCondition() != nil); err != nil {
 		return nil, err
@@ -63,6 +68,10 @@ func (a Action) createAction(builder *Builder, menu *walk.Menu) (*walk.Action, e
 		return nil, err
 	}
 
+	if err := action.SetRequiresElevation(a.RequiresElevation); err != nil {
+		return nil, err
+	}
+
 	if a.OnTriggered != nil {
 		action.Triggered().Attach(a.OnTriggered)
 	}
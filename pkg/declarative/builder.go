@@ -13,6 +13,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"gopkg.in/Knetic/govaluate.v3"
 
@@ -22,14 +23,30 @@ import (
 var (
 	conditionsByName = make(map[string]walk.Condition)
 	propertyRE       *regexp.Regexp
+	indexedPathRE    *regexp.Regexp
+	strictNames      bool
 )
 
 func init() {
 	walk.AppendToWalkInit(func() {
 		propertyRE = regexp.MustCompile("[A-Za-z]+[0-9A-Za-z]*(\\.[A-Za-z]+[0-9A-Za-z]*)+")
+		indexedPathRE = regexp.MustCompile(`^[A-Za-z_]\w*(\[\d+\])*(\.[A-Za-z_]\w*(\[\d+\])*)*$`)
 	})
 }
 
+// StrictNames returns whether building fails for duplicate widget names, see
+// SetStrictNames.
+func StrictNames() bool {
+	return strictNames
+}
+
+// SetStrictNames sets whether building fails for a widget whose name is used
+// by a widget built before, or already in the form the widgets are added to.
+// By default, a warning is logged instead.
+func SetStrictNames(v bool) {
+	strictNames = v
+}
+
 func MustRegisterCondition(name string, condition walk.Condition) {
 	if name == "" {
 		panic(`name == ""`)
@@ -58,6 +75,8 @@ type Builder struct {
 	col                      int
 	widgetValue              reflect.Value
 	parent                   walk.Container
+	existingForm             walk.Form       // the form of the parent passed to NewBuilder
+	existingNames            map[string]bool // of the widgets of existingForm, collected on demand
 	declWidgets              []declWidget
 	name2Window              map[string]walk.Window
 	name2DataBinder          map[string]*walk.DataBinder
@@ -69,14 +88,17 @@ type Builder struct {
 
 func NewBuilder(parent walk.Container) *Builder {
 	var dpi int
+	var existingForm walk.Form
 
 	if parent != nil {
 		dpi = parent.DPI()
+		existingForm = parent.Form()
 	}
 
 	return &Builder{
 		dpi:                      dpi,
 		parent:                   parent,
+		existingForm:             existingForm,
 		name2Window:              make(map[string]walk.Window),
 		name2DataBinder:          make(map[string]*walk.DataBinder),
 		knownCompositeConditions: make(map[string]walk.Condition),
@@ -129,6 +151,16 @@ func (b *Builder) InitWidget(d Widget, w walk.Window, customInit func() error) e
 	if b.dpi == 0 {
 		b.dpi = w.DPI()
 	}
+	if b.level == 0 {
+		prefetch(d, w.Font(), b.dpi)
+
+		// Widgets added to an existing container are laid out once, after
+		// all of their windows have been created, even if building fails.
+		if parent := b.parent; parent != nil && !parent.Suspended() {
+			parent.SetSuspended(true)
+			defer parent.SetSuspended(false)
+		}
+	}
 	oldWidgetValue := b.widgetValue
 	b.widgetValue = reflect.ValueOf(d)
 	b.level++
@@ -151,6 +183,10 @@ func (b *Builder) InitWidget(d Widget, w walk.Window, customInit func() error) e
 
 	// Widget
 	if name := b.string("Name"); name != "" {
+		if err := b.checkNameUnique(name); err != nil {
+			return err
+		}
+
 		w.SetName(name)
 		b.name2Window[name] = w
 	}
@@ -193,6 +229,32 @@ func (b *Builder) InitWidget(d Widget, w walk.Window, customInit func() error) e
 		w.BoundsChanged().Attach(handler)
 	}
 
+	if handler := b.eventHandler("OnDisposed"); handler != nil {
+		w.Disposing().Attach(handler)
+	}
+
+	if handler := b.dragEventHandler("OnDragEnter"); handler != nil {
+		w.DragEnter().Attach(handler)
+	}
+
+	if handler := b.eventHandler("OnDragLeave"); handler != nil {
+		w.DragLeave().Attach(handler)
+	}
+
+	if handler := b.dragEventHandler("OnDragOver"); handler != nil {
+		w.DragOver().Attach(handler)
+	}
+
+	if handler := b.dragEventHandler("OnDrop"); handler != nil {
+		w.Drop().Attach(handler)
+	}
+
+	if b.bool("AcceptsDrops") {
+		if err := w.SetAcceptsDrops(true); err != nil {
+			return err
+		}
+	}
+
 	if handler := b.keyEventHandler("OnKeyDown"); handler != nil {
 		w.KeyDown().Attach(handler)
 	}
@@ -248,6 +310,13 @@ func (b *Builder) InitWidget(d Widget, w walk.Window, customInit func() error) e
 			return err
 		}
 
+		widget.AsWidgetBase().SetWheelRequiresFocus(b.bool("WheelRequiresFocus"))
+
+		if b.bool("AutoSubmit") {
+			widget.AsWidgetBase().SetAutoSubmit(true)
+			widget.AsWidgetBase().SetAutoSubmitDelay(b.duration("AutoSubmitDelay"))
+		}
+
 		if field := b.widgetValue.FieldByName("GraphicsEffects"); field.IsValid() {
 			for _, effect := range field.Interface().([]walk.WidgetGraphicsEffect) {
 				widget.GraphicsEffects().Add(effect)
@@ -291,28 +360,8 @@ func (b *Builder) InitWidget(d Widget, w walk.Window, customInit func() error) e
 					columnSpan = 1
 				}
 
-				if b.rows > 0 && column == 0 && row == 0 {
-					if b.row+rowSpan > b.rows {
-						b.col++
-						b.row = 0
-					}
-
-					column = b.col
-					row = b.row
-
-					b.row += rowSpan
-				}
-
-				if b.columns > 0 && row == 0 && column == 0 {
-					if b.col+columnSpan > b.columns {
-						b.row++
-						b.col = 0
-					}
-
-					row = b.row
-					column = b.col
-
-					b.col += columnSpan
+				if (b.rows > 0 || b.columns > 0) && column == 0 && row == 0 {
+					row, column = b.nextFreeGridCell(l, rowSpan, columnSpan)
 				}
 
 				r := walk.Rectangle{column, row, columnSpan, rowSpan}
@@ -370,7 +419,7 @@ func (b *Builder) InitWidget(d Widget, w walk.Window, customInit func() error) e
 				rows := b.rows
 				columns := b.columns
 				defer func() {
-					b.rows, b.columns, b.row, b.col = rows, columns, rowBackup+rowSpan, columnBackup+columnSpan
+					b.rows, b.columns, b.row, b.col = rows, columns, rowBackup, columnBackup
 				}()
 
 				b.rows = g.Rows
@@ -388,6 +437,14 @@ func (b *Builder) InitWidget(d Widget, w walk.Window, customInit func() error) e
 			}
 		}
 
+		if a, ok := layout.(Anchor); ok {
+			if l, ok := wc.Layout().(*walk.AnchorLayout); ok {
+				if err := a.applyAnchors(l, b); err != nil {
+					return err
+				}
+			}
+		}
+
 		dataBinder := b.widgetValue.FieldByName("DataBinder").Interface().(DataBinder)
 
 		if dataBinder.AssignTo != nil || dataBinder.DataSource != nil {
@@ -414,6 +471,22 @@ func (b *Builder) InitWidget(d Widget, w walk.Window, customInit func() error) e
 		}
 	}
 
+	// Lifecycle
+	if handler := b.eventHandler("OnCreated"); handler != nil {
+		// Runs once the whole tree is built and its properties are bound.
+		b.Defer(func() error {
+			handler()
+			return nil
+		})
+	}
+
+	if handler := b.eventHandler("OnFirstVisible"); handler != nil {
+		b.Defer(func() error {
+			walk.OnceVisible(w, handler)
+			return nil
+		})
+	}
+
 	b.parent = oldParent
 
 	if b.level == 1 {
@@ -452,6 +525,89 @@ func (b *Builder) InitWidget(d Widget, w walk.Window, customInit func() error) e
 	return nil
 }
 
+// checkNameUnique logs a warning, or with StrictNames returns an error, if a
+// widget built before, or already in the form that the widgets are added to,
+// has name. Names identify widgets in expressions, layouts and persisted
+// state, so they should not be ambiguous.
+func (b *Builder) checkNameUnique(name string) error {
+	var err error
+	if _, ok := b.name2Window[name]; ok {
+		err = fmt.Errorf(`duplicate widget name "%s"`, name)
+	} else if b.existingFormHasName(name) {
+		err = fmt.Errorf(`duplicate widget name "%s": already used in the form`, name)
+	}
+
+	if err == nil || strictNames {
+		return err
+	}
+
+	log.Printf("walk - %s", err.Error())
+
+	return nil
+}
+
+// existingFormHasName returns whether a widget of the existing form has name.
+// The names are collected once, before the first named widget is built, so
+// they don't include the widgets of the Builder.
+func (b *Builder) existingFormHasName(name string) bool {
+	if b.existingForm == nil {
+		return false
+	}
+
+	if b.existingNames == nil {
+		b.existingNames = make(map[string]bool)
+
+		walk.WalkDescendants(b.existingForm, func(w walk.Widget) walk.TreeTraversalDirective {
+			if name := w.Name(); name != "" {
+				b.existingNames[name] = true
+			}
+
+			return walk.TraverseContinue
+		})
+	}
+
+	return b.existingNames[name]
+}
+
+// nextFreeGridCell returns the first cell, in the flow order of the Grid being
+// built, where a widget spanning rowSpan rows and columnSpan columns doesn't
+// overlap any widget already placed, and advances the flow past it.
+func (b *Builder) nextFreeGridCell(l *walk.GridLayout, rowSpan, columnSpan int) (row, column int) {
+	for {
+		if b.columns > 0 {
+			if b.col > 0 && b.col+columnSpan > b.columns {
+				b.row++
+				b.col = 0
+				continue
+			}
+		} else if b.row > 0 && b.row+rowSpan > b.rows {
+			b.col++
+			b.row = 0
+			continue
+		}
+
+		if l.RangeFree(walk.Rectangle{X: b.col, Y: b.row, Width: columnSpan, Height: rowSpan}) {
+			break
+		}
+
+		if b.columns > 0 {
+			b.col++
+		} else {
+			b.row++
+		}
+	}
+
+	row, column = b.row, b.col
+
+	if b.columns > 0 {
+		b.col += columnSpan
+	} else {
+		b.row += rowSpan
+	}
+
+	return
+}
+
 func (b *Builder) initAccessibility(d Widget, w walk.Window) error {
 	accessibility := b.widgetValue.FieldByName("Accessibility")
 
@@ -522,6 +678,26 @@ func (b *Builder) bool(fieldName string) bool {
 	return false
 }
 
+func (b *Builder) dragEventHandler(fieldName string) walk.DragEventHandler {
+	fieldValue := b.widgetValue.FieldByName(fieldName)
+
+	if fieldValue.IsValid() {
+		return fieldValue.Interface().(walk.DragEventHandler)
+	}
+
+	return nil
+}
+
+func (b *Builder) duration(fieldName string) time.Duration {
+	fieldValue := b.widgetValue.FieldByName(fieldName)
+
+	if fieldValue.IsValid() {
+		return fieldValue.Interface().(time.Duration)
+	}
+
+	return 0
+}
+
 func (b *Builder) eventHandler(fieldName string) walk.EventHandler {
 	fieldValue := b.widgetValue.FieldByName(fieldName)
 
@@ -636,6 +812,16 @@ func (b *Builder) initProperties() error {
 					// something in the data source.
 					src = val.expression
 
+					if val.converter != nil {
+						cp, ok := prop.(walk.ConvertibleProperty)
+						if !ok {
+							panic(sf.Name + " is not a convertible property")
+						}
+						if err := cp.SetConverter(val.converter); err != nil {
+							return err
+						}
+					}
+
 					if val.validator != nil {
 						validator, err := val.validator.Create()
 						if err != nil {
@@ -682,6 +868,12 @@ func (b *Builder) conditionOrProperty(data Property) interface{} {
 			return nil
 		}
 
+		if strings.Contains(val.expression, "[") && indexedPathRE.MatchString(val.expression) {
+			// Index expressions like "Items[2].Name" can only refer to
+			// something in the data source.
+			return nil
+		}
+
 		e := &expression{
 			text:           val.expression,
 			subExprsByPath: subExpressions(make(map[string]walk.Expression)),
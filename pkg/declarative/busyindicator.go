@@ -0,0 +1,78 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type BusyIndicator struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// BusyIndicator
+
+	AssignTo         **walk.BusyIndicator
+	OnRunningChanged walk.EventHandler
+	Running          Property
+}
+
+func (bi BusyIndicator) Create(builder *Builder) error {
+	w, err := walk.NewBusyIndicator(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if bi.AssignTo != nil {
+		*bi.AssignTo = w
+	}
+
+	return builder.InitWidget(bi, w, func() error {
+		if bi.OnRunningChanged != nil {
+			w.RunningChanged().Attach(bi.OnRunningChanged)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"time"
+
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type CalendarView struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// CalendarView
+
+	AssignTo           **walk.CalendarView
+	BoldDateFunc       func(date time.Time) bool
+	Date               Property
+	MaxDate            time.Time
+	MaxSelectionCount  int
+	MinDate            time.Time
+	MultiSelection     bool
+	OnDateChanged      walk.EventHandler
+	OnSelectionChanged walk.CalendarViewSelectionEventHandler
+}
+
+func (cv CalendarView) Create(builder *Builder) error {
+	var w *walk.CalendarView
+	var err error
+
+	if cv.MultiSelection {
+		w, err = walk.NewCalendarViewWithMultiSelection(builder.Parent())
+	} else {
+		w, err = walk.NewCalendarView(builder.Parent())
+	}
+	if err != nil {
+		return err
+	}
+
+	if cv.AssignTo != nil {
+		*cv.AssignTo = w
+	}
+
+	return builder.InitWidget(cv, w, func() error {
+		if err := w.SetRange(cv.MinDate, cv.MaxDate); err != nil {
+			return err
+		}
+
+		if cv.MaxSelectionCount > 0 {
+			if err := w.SetMaxSelectionCount(cv.MaxSelectionCount); err != nil {
+				return err
+			}
+		}
+
+		if cv.BoldDateFunc != nil {
+			w.SetBoldDateFunc(cv.BoldDateFunc)
+		}
+
+		if cv.OnDateChanged != nil {
+			w.DateChanged().Attach(cv.OnDateChanged)
+		}
+
+		if cv.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(cv.OnSelectionChanged)
+		}
+
+		return nil
+	})
+}
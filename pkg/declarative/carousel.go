@@ -0,0 +1,100 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"time"
+
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type Carousel struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// Carousel
+
+	AssignTo              **walk.Carousel
+	AutoAdvanceInterval   time.Duration
+	CurrentIndex          Property
+	Images                []interface{}
+	NoWrap                bool
+	OnCurrentIndexChanged walk.EventHandler
+}
+
+func (c Carousel) Create(builder *Builder) error {
+	w, err := walk.NewCarousel(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if c.AssignTo != nil {
+		*c.AssignTo = w
+	}
+
+	return builder.InitWidget(c, w, func() error {
+		images := make([]walk.Image, 0, len(c.Images))
+		for _, src := range c.Images {
+			img, err := walk.ImageFrom(src)
+			if err != nil {
+				return err
+			}
+
+			images = append(images, img)
+		}
+		w.SetImages(images)
+
+		w.SetWraps(!c.NoWrap)
+
+		if err := w.SetAutoAdvanceInterval(c.AutoAdvanceInterval); err != nil {
+			return err
+		}
+
+		if c.OnCurrentIndexChanged != nil {
+			w.CurrentIndexChanged().Attach(c.OnCurrentIndexChanged)
+		}
+
+		return nil
+	})
+}
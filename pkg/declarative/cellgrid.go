@@ -0,0 +1,102 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type CellGrid struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// CellGrid
+
+	AssignTo             **walk.CellGrid
+	DefaultColumnWidth   int
+	FrozenColumns        int
+	FrozenRows           int
+	Model                walk.CellGridModel
+	OnCurrentCellChanged walk.EventHandler
+	OnSelectionChanged   walk.EventHandler
+}
+
+func (cg CellGrid) Create(builder *Builder) error {
+	w, err := walk.NewCellGrid(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if cg.AssignTo != nil {
+		*cg.AssignTo = w
+	}
+
+	return builder.InitWidget(cg, w, func() error {
+		if cg.DefaultColumnWidth > 0 {
+			if err := w.SetDefaultColumnWidth(cg.DefaultColumnWidth); err != nil {
+				return err
+			}
+		}
+
+		if err := w.SetFrozenRows(cg.FrozenRows); err != nil {
+			return err
+		}
+		if err := w.SetFrozenColumns(cg.FrozenColumns); err != nil {
+			return err
+		}
+
+		if cg.Model != nil {
+			w.SetModel(cg.Model)
+		}
+
+		if cg.OnCurrentCellChanged != nil {
+			w.CurrentCellChanged().Attach(cg.OnCurrentCellChanged)
+		}
+		if cg.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(cg.OnSelectionChanged)
+		}
+
+		return nil
+	})
+}
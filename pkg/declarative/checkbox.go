@@ -8,6 +8,8 @@
 package declarative
 
 import (
+	"time"
+
 	"github.com/miu200521358/walk/pkg/walk"
 )
 
@@ -24,6 +26,9 @@ type CheckBox struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,6 +51,7 @@ type CheckBox struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Button
 
@@ -56,11 +62,14 @@ type CheckBox struct {
 
 	// CheckBox
 
-	AssignTo            **walk.CheckBox
-	CheckState          Property
-	OnCheckStateChanged walk.EventHandler
-	TextOnLeftSide      bool
-	Tristate            bool
+	AssignTo                    **walk.CheckBox
+	AutoSubmit                  bool
+	AutoSubmitDelay             time.Duration
+	CheckState                  Property
+	OnCheckStateChanged         walk.EventHandler
+	OnCheckStateChangedDetailed walk.ValueChangedEventHandler
+	TextOnLeftSide              bool
+	Tristate                    bool
 }
 
 func (cb CheckBox) Create(builder *Builder) error {
@@ -99,6 +108,9 @@ func (cb CheckBox) Create(builder *Builder) error {
 		if cb.OnCheckStateChanged != nil {
 			w.CheckStateChanged().Attach(cb.OnCheckStateChanged)
 		}
+		if cb.OnCheckStateChangedDetailed != nil {
+			w.CheckStateChangedDetailed().Attach(cb.OnCheckStateChangedDetailed)
+		}
 
 		return nil
 	})
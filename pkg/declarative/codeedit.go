@@ -0,0 +1,105 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type CodeEdit struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// CodeEdit
+
+	AssignTo               **walk.CodeEdit
+	BracketMatchColor      walk.Color
+	CurrentLineColor       walk.Color
+	NoBracketMatching      bool
+	NoCurrentLineHighlight bool
+	NoLineNumbers          bool
+	OnTextChanged          walk.EventHandler
+	ReadOnly               Property
+	Text                   Property
+	TokenStyles            map[walk.CodeTokenKind]walk.CodeTokenStyle
+	Tokenizer              walk.CodeTokenizer
+}
+
+func (ce CodeEdit) Create(builder *Builder) error {
+	w, err := walk.NewCodeEdit(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if ce.AssignTo != nil {
+		*ce.AssignTo = w
+	}
+
+	return builder.InitWidget(ce, w, func() error {
+		for kind, style := range ce.TokenStyles {
+			w.SetTokenStyle(kind, style)
+		}
+
+		if ce.Tokenizer != nil {
+			w.SetTokenizer(ce.Tokenizer)
+		}
+
+		if ce.CurrentLineColor != 0 {
+			w.SetCurrentLineColor(ce.CurrentLineColor)
+		}
+		if ce.BracketMatchColor != 0 {
+			w.SetBracketMatchColor(ce.BracketMatchColor)
+		}
+
+		w.SetLineNumbersVisible(!ce.NoLineNumbers)
+		w.SetHighlightCurrentLine(!ce.NoCurrentLineHighlight)
+		w.SetMatchBrackets(!ce.NoBracketMatching)
+
+		if ce.OnTextChanged != nil {
+			w.TextChanged().Attach(ce.OnTextChanged)
+		}
+
+		return nil
+	})
+}
@@ -9,6 +9,7 @@ package declarative
 
 import (
 	"errors"
+	"time"
 )
 
 import (
@@ -28,6 +29,9 @@ type ComboBox struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -50,15 +54,20 @@ type ComboBox struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// ComboBox
 
 	AssignTo              **walk.ComboBox
+	AutoSubmit            bool
+	AutoSubmitDelay       time.Duration
 	BindingMember         string
 	CurrentIndex          Property
 	DisplayMember         string
 	Editable              bool
+	FilterEnabled         bool
 	Format                string
+	ItemStyler            walk.ListItemStyler
 	MaxLength             int
 	Model                 interface{}
 	OnCurrentIndexChanged walk.EventHandler
@@ -77,9 +86,17 @@ func (cb ComboBox) Create(builder *Builder) error {
 
 	var w *walk.ComboBox
 	var err error
-	if cb.Editable {
+	switch {
+	case cb.Editable && cb.ItemStyler != nil:
+		w, err = walk.NewOwnerDrawComboBox(builder.Parent())
+
+	case cb.Editable:
 		w, err = walk.NewComboBox(builder.Parent())
-	} else {
+
+	case cb.ItemStyler != nil:
+		w, err = walk.NewOwnerDrawDropDownBox(builder.Parent())
+
+	default:
 		w, err = walk.NewDropDownBox(builder.Parent())
 	}
 	if err != nil {
@@ -91,6 +108,9 @@ func (cb ComboBox) Create(builder *Builder) error {
 	}
 
 	return builder.InitWidget(cb, w, func() error {
+		if cb.ItemStyler != nil {
+			w.SetItemStyler(cb.ItemStyler)
+		}
 		w.SetPersistent(cb.Persistent)
 		w.SetFormat(cb.Format)
 		w.SetPrecision(cb.Precision)
@@ -107,6 +127,12 @@ func (cb ComboBox) Create(builder *Builder) error {
 			return err
 		}
 
+		if cb.FilterEnabled {
+			if err := w.SetFilterEnabled(true); err != nil {
+				return err
+			}
+		}
+
 		if cb.OnCurrentIndexChanged != nil {
 			w.CurrentIndexChanged().Attach(cb.OnCurrentIndexChanged)
 		}
@@ -12,9 +12,20 @@ import (
 	"github.com/miu200521358/win"
 )
 
+type BorderStyle int
+
+const (
+	BorderNone    = BorderStyle(walk.BorderNone)
+	BorderSingle  = BorderStyle(walk.BorderSingle)
+	BorderSunken  = BorderStyle(walk.BorderSunken)
+	BorderRaised  = BorderStyle(walk.BorderRaised)
+	BorderRounded = BorderStyle(walk.BorderRounded)
+)
+
 type Composite struct {
 	// Window
 
+	AcceptsDrops       bool
 	Accessibility      Accessibility
 	Background         Brush
 	ContextMenuItems   []MenuItem
@@ -25,6 +36,13 @@ type Composite struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnDragEnter        walk.DragEventHandler
+	OnDragLeave        walk.EventHandler
+	OnDragOver         walk.DragEventHandler
+	OnDrop             walk.DragEventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -47,6 +65,7 @@ type Composite struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Container
 
@@ -56,10 +75,13 @@ type Composite struct {
 
 	// Composite
 
-	AssignTo    **walk.Composite
-	Border      bool
-	Expressions func() map[string]walk.Expression
-	Functions   map[string]func(args ...interface{}) (interface{}, error)
+	AssignTo     **walk.Composite
+	Border       bool
+	BorderColor  walk.Color
+	BorderRadius int
+	BorderStyle  BorderStyle
+	Expressions  func() map[string]walk.Expression
+	Functions    map[string]func(args ...interface{}) (interface{}, error)
 }
 
 func (c Composite) Create(builder *Builder) error {
@@ -83,6 +105,14 @@ func (c Composite) Create(builder *Builder) error {
 	})
 
 	return builder.InitWidget(c, w, func() error {
+		if err := w.SetBorder(walk.Border{
+			Style:  walk.BorderStyle(c.BorderStyle),
+			Color:  c.BorderColor,
+			Radius: c.BorderRadius,
+		}); err != nil {
+			return err
+		}
+
 		if c.Expressions != nil {
 			for name, expr := range c.Expressions() {
 				builder.expressions[name] = expr
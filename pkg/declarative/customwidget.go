@@ -32,6 +32,9 @@ type CustomWidget struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -54,6 +57,7 @@ type CustomWidget struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// CustomWidget
 
@@ -28,6 +28,9 @@ type DateEdit struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -50,6 +53,7 @@ type DateEdit struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// DateEdit
 
@@ -0,0 +1,95 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type Dial struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// Dial
+
+	AssignTo       **walk.Dial
+	Increment      float64
+	NoSnapping     bool
+	OnValueChanged walk.EventHandler
+	Unit           walk.AngleUnit
+	Value          Property
+}
+
+func (d Dial) Create(builder *Builder) error {
+	w, err := walk.NewDial(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if d.AssignTo != nil {
+		*d.AssignTo = w
+	}
+
+	return builder.InitWidget(d, w, func() error {
+		w.SetPersistent(d.Persistent)
+
+		if err := w.SetUnit(d.Unit); err != nil {
+			return err
+		}
+
+		if d.NoSnapping {
+			w.SetIncrement(0)
+		} else if d.Increment > 0 {
+			if err := w.SetIncrement(d.Increment); err != nil {
+				return err
+			}
+		}
+
+		if d.OnValueChanged != nil {
+			w.ValueChanged().Attach(d.OnValueChanged)
+		}
+
+		return nil
+	})
+}
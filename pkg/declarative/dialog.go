@@ -14,6 +14,7 @@ import (
 type Dialog struct {
 	// Window
 
+	AcceptsDrops       bool
 	Accessibility      Accessibility
 	Background         Brush
 	ContextMenuItems   []MenuItem
@@ -24,6 +25,13 @@ type Dialog struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnDragEnter        walk.DragEventHandler
+	OnDragLeave        walk.EventHandler
+	OnDragOver         walk.DragEventHandler
+	OnDrop             walk.DragEventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -57,6 +65,7 @@ type Dialog struct {
 	CancelButton  **walk.PushButton
 	DefaultButton **walk.PushButton
 	FixedSize     bool
+	Sheet         bool
 }
 
 func (d Dialog) Create(owner walk.Form) error {
@@ -76,6 +85,12 @@ func (d Dialog) Create(owner walk.Form) error {
 		*d.AssignTo = w
 	}
 
+	if d.Sheet {
+		if err := w.SetSheet(true); err != nil {
+			return err
+		}
+	}
+
 	fi := formInfo{
 		// Window
 		Background:         d.Background,
@@ -86,7 +101,12 @@ func (d Dialog) Create(owner walk.Form) error {
 		MaxSize:            d.MaxSize,
 		MinSize:            d.MinSize,
 		Name:               d.Name,
+		AcceptsDrops:       d.AcceptsDrops,
 		OnBoundsChanged:    d.OnBoundsChanged,
+		OnDragEnter:        d.OnDragEnter,
+		OnDragLeave:        d.OnDragLeave,
+		OnDragOver:         d.OnDragOver,
+		OnDrop:             d.OnDrop,
 		OnKeyDown:          d.OnKeyDown,
 		OnKeyPress:         d.OnKeyPress,
 		OnKeyUp:            d.OnKeyUp,
@@ -0,0 +1,109 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"time"
+
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type DurationEdit struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// DurationEdit
+
+	AssignTo           **walk.DurationEdit
+	Duration           Property
+	FPS                int
+	Format             walk.DurationFormat
+	MaxDuration        time.Duration
+	MinDuration        time.Duration
+	OnDurationChanged  walk.EventHandler
+	ReadOnly           Property
+	SpinButtonsVisible bool
+	TextColor          walk.Color
+	WheelValueMode     WheelValueMode
+}
+
+func (de DurationEdit) Create(builder *Builder) error {
+	w, err := walk.NewDurationEdit(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if de.AssignTo != nil {
+		*de.AssignTo = w
+	}
+
+	return builder.InitWidget(de, w, func() error {
+		if de.FPS > 0 {
+			if err := w.SetFPS(de.FPS); err != nil {
+				return err
+			}
+		}
+
+		if err := w.SetFormat(de.Format); err != nil {
+			return err
+		}
+
+		if err := w.SetRange(de.MinDuration, de.MaxDuration); err != nil {
+			return err
+		}
+
+		w.SetTextColor(de.TextColor)
+		w.SetWheelValueMode(walk.WheelValueMode(de.WheelValueMode))
+
+		if err := w.SetSpinButtonsVisible(de.SpinButtonsVisible); err != nil {
+			return err
+		}
+
+		if de.OnDurationChanged != nil {
+			w.DurationChanged().Attach(de.OnDurationChanged)
+		}
+
+		return nil
+	})
+}
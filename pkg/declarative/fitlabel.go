@@ -0,0 +1,91 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type FitLabel struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// FitLabel
+
+	AssignTo         **walk.FitLabel
+	MiddleEllipsis   bool
+	MinFontPointSize int
+	Text             Property
+	TextAlignment    Alignment1D
+	TextColor        walk.Color
+}
+
+func (fl FitLabel) Create(builder *Builder) error {
+	w, err := walk.NewFitLabel(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if fl.AssignTo != nil {
+		*fl.AssignTo = w
+	}
+
+	return builder.InitWidget(fl, w, func() error {
+		if fl.MinFontPointSize > 0 {
+			if err := w.SetMinFontPointSize(fl.MinFontPointSize); err != nil {
+				return err
+			}
+		}
+
+		w.SetMiddleEllipsis(fl.MiddleEllipsis)
+
+		if err := w.SetTextAlignment(walk.Alignment1D(fl.TextAlignment)); err != nil {
+			return err
+		}
+
+		w.SetTextColor(fl.TextColor)
+
+		return nil
+	})
+}
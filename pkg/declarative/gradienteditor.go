@@ -0,0 +1,86 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type GradientEditor struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// GradientEditor
+
+	AssignTo              **walk.GradientEditor
+	OnCurrentIndexChanged walk.EventHandler
+	OnStopsChanged        walk.EventHandler
+	Stops                 *walk.GradientStops
+}
+
+func (ge GradientEditor) Create(builder *Builder) error {
+	w, err := walk.NewGradientEditor(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if ge.AssignTo != nil {
+		*ge.AssignTo = w
+	}
+
+	return builder.InitWidget(ge, w, func() error {
+		if ge.Stops != nil {
+			w.SetStops(ge.Stops)
+		}
+
+		if ge.OnCurrentIndexChanged != nil {
+			w.CurrentIndexChanged().Attach(ge.OnCurrentIndexChanged)
+		}
+		if ge.OnStopsChanged != nil {
+			w.StopsChanged().Attach(ge.OnStopsChanged)
+		}
+
+		return nil
+	})
+}
@@ -24,6 +24,9 @@ type GroupBox struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,6 +49,7 @@ type GroupBox struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Container
 
@@ -55,10 +59,14 @@ type GroupBox struct {
 
 	// GroupBox
 
-	AssignTo  **walk.GroupBox
-	Checkable bool
-	Checked   Property
-	Title     string
+	AssignTo      **walk.GroupBox
+	BorderColor   walk.Color
+	BorderRadius  int
+	BorderStyle   BorderStyle
+	Checkable     bool
+	Checked       Property
+	HeaderWidgets []Widget
+	Title         string
 }
 
 func (gb GroupBox) Create(builder *Builder) error {
@@ -84,6 +92,28 @@ func (gb GroupBox) Create(builder *Builder) error {
 
 		w.SetCheckable(gb.Checkable)
 
+		if len(gb.HeaderWidgets) > 0 {
+			oldParent := builder.parent
+			builder.parent = w.HeaderContainer()
+			defer func() {
+				builder.parent = oldParent
+			}()
+
+			for _, hw := range gb.HeaderWidgets {
+				if err := hw.Create(builder); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := w.SetBorder(walk.Border{
+			Style:  walk.BorderStyle(gb.BorderStyle),
+			Color:  gb.BorderColor,
+			Radius: gb.BorderRadius,
+		}); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
@@ -0,0 +1,116 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type HeaderBarColumn struct {
+	Alignment Alignment1D
+	Title     string
+	Width     int
+}
+
+type HeaderBar struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// HeaderBar
+
+	AssignTo             **walk.HeaderBar
+	Columns              []HeaderBarColumn
+	NotSortable          bool
+	OnColumnClicked      walk.IntEventHandler
+	OnColumnWidthChanged walk.IntEventHandler
+	OnColumnsReordered   walk.EventHandler
+	OnSortChanged        walk.EventHandler
+}
+
+func (hb HeaderBar) Create(builder *Builder) error {
+	w, err := walk.NewHeaderBar(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if hb.AssignTo != nil {
+		*hb.AssignTo = w
+	}
+
+	return builder.InitWidget(hb, w, func() error {
+		for i, column := range hb.Columns {
+			width := column.Width
+			if width == 0 {
+				width = 100
+			}
+
+			if err := w.AddColumn(column.Title, width); err != nil {
+				return err
+			}
+
+			if column.Alignment != AlignDefault {
+				if err := w.SetColumnAlignment(i, walk.Alignment1D(column.Alignment)); err != nil {
+					return err
+				}
+			}
+		}
+
+		w.SetSortable(!hb.NotSortable)
+
+		if hb.OnColumnClicked != nil {
+			w.ColumnClicked().Attach(hb.OnColumnClicked)
+		}
+		if hb.OnColumnWidthChanged != nil {
+			w.ColumnWidthChanged().Attach(hb.OnColumnWidthChanged)
+		}
+		if hb.OnColumnsReordered != nil {
+			w.ColumnsReordered().Attach(hb.OnColumnsReordered)
+		}
+		if hb.OnSortChanged != nil {
+			w.SortChanged().Attach(hb.OnSortChanged)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type Histogram struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// Histogram
+
+	AssignTo            **walk.Histogram
+	Bins                []float64
+	LogScale            bool
+	OnHoverIndexChanged walk.EventHandler
+	OnSelectionChanged  walk.EventHandler
+}
+
+func (h Histogram) Create(builder *Builder) error {
+	w, err := walk.NewHistogram(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if h.AssignTo != nil {
+		*h.AssignTo = w
+	}
+
+	return builder.InitWidget(h, w, func() error {
+		w.SetLogScale(h.LogScale)
+
+		if h.Bins != nil {
+			w.SetBins(h.Bins)
+		}
+
+		if h.OnHoverIndexChanged != nil {
+			w.HoverIndexChanged().Attach(h.OnHoverIndexChanged)
+		}
+
+		if h.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(h.OnSelectionChanged)
+		}
+
+		return nil
+	})
+}
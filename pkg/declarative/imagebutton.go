@@ -0,0 +1,112 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type ImageButton struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// ImageButton
+
+	AssignTo         **walk.ImageButton
+	Checkable        bool
+	Checked          Property
+	CheckedImage     interface{}
+	DisabledImage    interface{}
+	HotImage         interface{}
+	Image            Property
+	ImageSize        Size
+	OnCheckedChanged walk.EventHandler
+	OnClicked        walk.EventHandler
+	PressedImage     interface{}
+}
+
+func (ib ImageButton) Create(builder *Builder) error {
+	w, err := walk.NewImageButton(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if ib.AssignTo != nil {
+		*ib.AssignTo = w
+	}
+
+	return builder.InitWidget(ib, w, func() error {
+		w.SetCheckable(ib.Checkable)
+
+		if err := w.SetImageSize(ib.ImageSize.toW()); err != nil {
+			return err
+		}
+
+		for state, src := range map[walk.ImageButtonState]interface{}{
+			walk.ImageButtonChecked:  ib.CheckedImage,
+			walk.ImageButtonDisabled: ib.DisabledImage,
+			walk.ImageButtonHot:      ib.HotImage,
+			walk.ImageButtonPressed:  ib.PressedImage,
+		} {
+			img, err := walk.ImageFrom(src)
+			if err != nil {
+				return err
+			}
+
+			if err := w.SetImage(state, img); err != nil {
+				return err
+			}
+		}
+
+		if ib.OnCheckedChanged != nil {
+			w.CheckedChanged().Attach(ib.OnCheckedChanged)
+		}
+
+		if ib.OnClicked != nil {
+			w.Clicked().Attach(ib.OnClicked)
+		}
+
+		return nil
+	})
+}
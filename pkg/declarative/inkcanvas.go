@@ -0,0 +1,105 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type InkMode int
+
+const (
+	InkModeDraw   = InkMode(walk.InkModeDraw)
+	InkModeErase  = InkMode(walk.InkModeErase)
+	InkModeSelect = InkMode(walk.InkModeSelect)
+)
+
+type InkCanvas struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// InkCanvas
+
+	AssignTo           **walk.InkCanvas
+	Image              Property
+	InkColor           walk.Color
+	InkWidth           float64
+	Mode               InkMode
+	OnModeChanged      walk.EventHandler
+	OnSelectionChanged walk.EventHandler
+	OnStrokesChanged   walk.EventHandler
+}
+
+func (ic InkCanvas) Create(builder *Builder) error {
+	w, err := walk.NewInkCanvas(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if ic.AssignTo != nil {
+		*ic.AssignTo = w
+	}
+
+	return builder.InitWidget(ic, w, func() error {
+		w.SetInkColor(ic.InkColor)
+		if ic.InkWidth > 0 {
+			w.SetInkWidth(ic.InkWidth)
+		}
+		w.SetMode(walk.InkMode(ic.Mode))
+
+		if ic.OnModeChanged != nil {
+			w.ModeChanged().Attach(ic.OnModeChanged)
+		}
+
+		if ic.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(ic.OnSelectionChanged)
+		}
+
+		if ic.OnStrokesChanged != nil {
+			w.StrokesChanged().Attach(ic.OnStrokesChanged)
+		}
+
+		return nil
+	})
+}
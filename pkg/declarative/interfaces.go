@@ -26,6 +26,7 @@ type Property interface{}
 type bindData struct {
 	expression string
 	validator  Validator
+	converter  walk.BindingConverter
 }
 
 func Bind(expression string, validators ...Validator) Property {
@@ -44,6 +45,16 @@ func Bind(expression string, validators ...Validator) Property {
 	return bd
 }
 
+// BindWithConverter is like Bind, but converter converts the values exchanged
+// between the data source field and the property, e.g. to show an enum as a
+// string or a value in other units. Validators validate the property value.
+func BindWithConverter(expression string, converter walk.BindingConverter, validators ...Validator) Property {
+	bd := Bind(expression, validators...).(bindData)
+	bd.converter = converter
+
+	return bd
+}
+
 type SysDLLIcon struct {
 	FileName string
 	Index    int
@@ -119,6 +130,7 @@ func (ToolTipErrorPresenter) Create() (walk.ErrorPresenter, error) {
 type formInfo struct {
 	// Window
 
+	AcceptsDrops       bool
 	Accessibility      Accessibility
 	Background         Brush
 	ContextMenuItems   []MenuItem
@@ -129,6 +141,13 @@ type formInfo struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnDragEnter        walk.DragEventHandler
+	OnDragLeave        walk.EventHandler
+	OnDragOver         walk.DragEventHandler
+	OnDrop             walk.DragEventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
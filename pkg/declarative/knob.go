@@ -0,0 +1,108 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type Knob struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// Knob
+
+	AssignTo       **walk.Knob
+	MaxValue       float64
+	MinValue       float64
+	NoSnapping     bool
+	OnValueChanged walk.EventHandler
+	PageStep       float64
+	Step           float64
+	Value          Property
+	WheelValueMode WheelValueMode
+}
+
+func (k Knob) Create(builder *Builder) error {
+	w, err := walk.NewKnob(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if k.AssignTo != nil {
+		*k.AssignTo = w
+	}
+
+	return builder.InitWidget(k, w, func() error {
+		w.SetPersistent(k.Persistent)
+
+		if k.MinValue != 0 || k.MaxValue != 0 {
+			if err := w.SetRange(k.MinValue, k.MaxValue); err != nil {
+				return err
+			}
+		}
+
+		if k.Step > 0 {
+			if err := w.SetStep(k.Step); err != nil {
+				return err
+			}
+		}
+
+		if k.PageStep > 0 {
+			if err := w.SetPageStep(k.PageStep); err != nil {
+				return err
+			}
+		}
+
+		w.SetSnap(!k.NoSnapping)
+
+		w.SetWheelValueMode(walk.WheelValueMode(k.WheelValueMode))
+
+		if k.OnValueChanged != nil {
+			w.ValueChanged().Attach(k.OnValueChanged)
+		}
+
+		return nil
+	})
+}
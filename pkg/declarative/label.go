@@ -33,6 +33,9 @@ type Label struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -55,15 +58,19 @@ type Label struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Label
 
-	AssignTo      **walk.Label
-	EllipsisMode  EllipsisMode
-	NoPrefix      bool
-	Text          Property
-	TextAlignment Alignment1D
-	TextColor     walk.Color
+	AssignTo        **walk.Label
+	EllipsisMode    EllipsisMode
+	NoPrefix        bool
+	Selectable      bool
+	Text            Property
+	TextAlignment   Alignment1D
+	TextAlignment2D Alignment2D
+	TextColor       walk.Color
+	WordWrap        bool
 }
 
 func (l Label) Create(builder *Builder) error {
@@ -90,6 +97,20 @@ func (l Label) Create(builder *Builder) error {
 			return err
 		}
 
+		if l.TextAlignment2D != AlignHVDefault {
+			if err := w.SetTextAlignment2D(walk.Alignment2D(l.TextAlignment2D)); err != nil {
+				return err
+			}
+		}
+
+		if err := w.SetWordWrap(l.WordWrap); err != nil {
+			return err
+		}
+
+		if err := w.SetSelectable(l.Selectable); err != nil {
+			return err
+		}
+
 		w.SetTextColor(l.TextColor)
 
 		return nil
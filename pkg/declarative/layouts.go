@@ -124,13 +124,15 @@ func (vb VBox) Create() (walk.Layout, error) {
 }
 
 type Grid struct {
-	Rows        int
-	Columns     int
-	Margins     Margins
-	Alignment   Alignment2D
-	Spacing     int
-	MarginsZero bool
-	SpacingZero bool
+	Rows            int
+	Columns         int
+	Margins         Margins
+	Alignment       Alignment2D
+	Spacing         int
+	MarginsZero     bool
+	SpacingZero     bool
+	MinRowHeights   []int
+	MinColumnWidths []int
 }
 
 func (g Grid) Create() (walk.Layout, error) {
@@ -152,15 +154,30 @@ func (g Grid) Create() (walk.Layout, error) {
 		return nil, err
 	}
 
+	for row, height := range g.MinRowHeights {
+		if err := l.SetMinRowHeight(row, height); err != nil {
+			return nil, err
+		}
+	}
+
+	for column, width := range g.MinColumnWidths {
+		if err := l.SetMinColumnWidth(column, width); err != nil {
+			return nil, err
+		}
+	}
+
 	return l, nil
 }
 
 type Flow struct {
-	Margins     Margins
-	Alignment   Alignment2D
-	Spacing     int
-	MarginsZero bool
-	SpacingZero bool
+	Margins        Margins
+	Alignment      Alignment2D
+	RowAlignment   Alignment1D
+	Spacing        int
+	RowSpacing     int // Spacing is used if 0, unless RowSpacingZero is true
+	MarginsZero    bool
+	SpacingZero    bool
+	RowSpacingZero bool
 }
 
 func (f Flow) Create() (walk.Layout, error) {
@@ -174,9 +191,116 @@ func (f Flow) Create() (walk.Layout, error) {
 		return nil, err
 	}
 
+	if f.RowSpacing > 0 || f.RowSpacingZero {
+		if err := l.SetRowSpacing(f.RowSpacing); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := l.SetAlignment(walk.Alignment2D(f.Alignment)); err != nil {
 		return nil, err
 	}
 
+	if err := l.SetRowAlignment(walk.Alignment1D(f.RowAlignment)); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+type AnchorEdge int
+
+const (
+	AnchorLeft   = AnchorEdge(walk.AnchorLeft)
+	AnchorTop    = AnchorEdge(walk.AnchorTop)
+	AnchorRight  = AnchorEdge(walk.AnchorRight)
+	AnchorBottom = AnchorEdge(walk.AnchorBottom)
+)
+
+// AnchorTarget attaches an edge of a widget to the same edge of its container,
+// or, if Sibling is the Name of another child, to Edge of that child.
+type AnchorTarget struct {
+	Sibling string
+	Edge    AnchorEdge
+	Offset  int
+}
+
+type Anchors struct {
+	Left   *AnchorTarget
+	Top    *AnchorTarget
+	Right  *AnchorTarget
+	Bottom *AnchorTarget
+}
+
+// Anchor creates a walk.AnchorLayout. Anchors maps the Name of children to
+// their anchors, which are applied once all children have been created.
+type Anchor struct {
+	Anchors     map[string]Anchors
+	Margins     Margins
+	MarginsZero bool
+}
+
+func (a Anchor) Create() (walk.Layout, error) {
+	l := walk.NewAnchorLayout()
+
+	if err := setLayoutMargins(l, a.Margins, a.MarginsZero); err != nil {
+		return nil, err
+	}
+
 	return l, nil
 }
+
+func (a Anchor) applyAnchors(l *walk.AnchorLayout, builder *Builder) error {
+	widget := func(name string) (walk.Widget, error) {
+		if w, ok := builder.name2Window[name].(walk.Widget); ok {
+			return w, nil
+		}
+
+		return nil, errors.New("unknown widget: " + name)
+	}
+
+	target := func(t *AnchorTarget) (*walk.AnchorTarget, error) {
+		if t == nil {
+			return nil, nil
+		}
+
+		if t.Sibling == "" {
+			return walk.AnchorToParent(t.Offset), nil
+		}
+
+		sibling, err := widget(t.Sibling)
+		if err != nil {
+			return nil, err
+		}
+
+		return walk.AnchorToSibling(sibling, walk.AnchorEdge(t.Edge), t.Offset), nil
+	}
+
+	for name, anchors := range a.Anchors {
+		w, err := widget(name)
+		if err != nil {
+			return err
+		}
+
+		var wa walk.Anchors
+		for _, edge := range []struct {
+			src *AnchorTarget
+			dst **walk.AnchorTarget
+		}{
+			{anchors.Left, &wa.Left},
+			{anchors.Top, &wa.Top},
+			{anchors.Right, &wa.Right},
+			{anchors.Bottom, &wa.Bottom},
+		} {
+			if *edge.dst, err = target(edge.src); err != nil {
+				return err
+			}
+		}
+
+		if err := l.SetAnchors(w, wa); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
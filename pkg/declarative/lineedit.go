@@ -19,9 +19,17 @@ const (
 	CaseModeLower CaseMode = CaseMode(walk.CaseModeLower)
 )
 
+type CompletionMode int
+
+const (
+	CompletionPrefix    CompletionMode = CompletionMode(walk.CompletionPrefix)
+	CompletionSubstring CompletionMode = CompletionMode(walk.CompletionSubstring)
+)
+
 type LineEdit struct {
 	// Window
 
+	AcceptsDrops       bool
 	Accessibility      Accessibility
 	Background         Brush
 	ContextMenuItems   []MenuItem
@@ -32,6 +40,13 @@ type LineEdit struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnDragEnter        walk.DragEventHandler
+	OnDragLeave        walk.EventHandler
+	OnDragOver         walk.DragEventHandler
+	OnDrop             walk.DragEventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -54,20 +69,26 @@ type LineEdit struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// LineEdit
 
-	AssignTo          **walk.LineEdit
-	CaseMode          CaseMode
-	CueBanner         string
-	MaxLength         int
-	OnEditingFinished walk.EventHandler
-	OnTextChanged     walk.EventHandler
-	PasswordMode      bool
-	ReadOnly          Property
-	Text              Property
-	TextAlignment     Alignment1D
-	TextColor         walk.Color
+	AssignTo              **walk.LineEdit
+	CaseMode              CaseMode
+	CompletionMode        CompletionMode
+	CueBanner             string
+	MaxLength             int
+	OnEditingFinished     walk.EventHandler
+	OnSuggestionActivated walk.StringEventHandler
+	OnTextChanged         walk.EventHandler
+	OnTextChangedDetailed walk.ValueChangedEventHandler
+	PasswordMode          bool
+	ReadOnly              Property
+	SuggestionModel       interface{}
+	SuggestionProvider    walk.SuggestionProvider
+	Text                  Property
+	TextAlignment         Alignment1D
+	TextColor             walk.Color
 }
 
 func (le LineEdit) Create(builder *Builder) error {
@@ -99,12 +120,31 @@ func (le LineEdit) Create(builder *Builder) error {
 			return err
 		}
 
+		w.SetCompletionMode(walk.CompletionMode(le.CompletionMode))
+
+		if le.SuggestionModel != nil {
+			if err := w.SetSuggestionModel(le.SuggestionModel); err != nil {
+				return err
+			}
+		}
+		if le.SuggestionProvider != nil {
+			if err := w.SetSuggestionProvider(le.SuggestionProvider); err != nil {
+				return err
+			}
+		}
+
 		if le.OnEditingFinished != nil {
 			w.EditingFinished().Attach(le.OnEditingFinished)
 		}
+		if le.OnSuggestionActivated != nil {
+			w.SuggestionActivated().Attach(le.OnSuggestionActivated)
+		}
 		if le.OnTextChanged != nil {
 			w.TextChanged().Attach(le.OnTextChanged)
 		}
+		if le.OnTextChangedDetailed != nil {
+			w.TextChangedDetailed().Attach(le.OnTextChangedDetailed)
+		}
 
 		return nil
 	})
@@ -17,6 +17,7 @@ import (
 type ListBox struct {
 	// Window
 
+	AcceptsDrops       bool
 	Accessibility      Accessibility
 	Background         Brush
 	ContextMenuItems   []MenuItem
@@ -27,6 +28,13 @@ type ListBox struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnDragEnter        walk.DragEventHandler
+	OnDragLeave        walk.EventHandler
+	OnDragOver         walk.DragEventHandler
+	OnDrop             walk.DragEventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -49,6 +57,7 @@ type ListBox struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// ListBox
 
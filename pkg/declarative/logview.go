@@ -0,0 +1,90 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type LogView struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// LogView
+
+	AssignTo            **walk.LogView
+	FollowTail          Property
+	MaxLines            int
+	OnFollowTailChanged walk.EventHandler
+	OnSelectionChanged  walk.EventHandler
+}
+
+func (lv LogView) Create(builder *Builder) error {
+	w, err := walk.NewLogView(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if lv.AssignTo != nil {
+		*lv.AssignTo = w
+	}
+
+	return builder.InitWidget(lv, w, func() error {
+		if lv.MaxLines > 0 {
+			if err := w.SetMaxLines(lv.MaxLines); err != nil {
+				return err
+			}
+		}
+
+		if lv.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(lv.OnSelectionChanged)
+		}
+
+		if lv.OnFollowTailChanged != nil {
+			w.FollowTailChanged().Attach(lv.OnFollowTailChanged)
+		}
+
+		return nil
+	})
+}
@@ -12,6 +12,7 @@ import "github.com/miu200521358/walk/pkg/walk"
 type MainWindow struct {
 	// Window
 
+	AcceptsDrops       bool
 	Accessibility      Accessibility
 	Background         Brush
 	ContextMenuItems   []MenuItem
@@ -22,6 +23,13 @@ type MainWindow struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnDragEnter        walk.DragEventHandler
+	OnDragLeave        walk.EventHandler
+	OnDragOver         walk.DragEventHandler
+	OnDrop             walk.DragEventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -84,7 +92,12 @@ func (mw MainWindow) Create() error {
 		MaxSize:            mw.MaxSize,
 		MinSize:            mw.MinSize,
 		Name:               mw.Name,
+		AcceptsDrops:       mw.AcceptsDrops,
 		OnBoundsChanged:    mw.OnBoundsChanged,
+		OnDragEnter:        mw.OnDragEnter,
+		OnDragLeave:        mw.OnDragLeave,
+		OnDragOver:         mw.OnDragOver,
+		OnDrop:             mw.OnDrop,
 		OnKeyDown:          mw.OnKeyDown,
 		OnKeyPress:         mw.OnKeyPress,
 		OnKeyUp:            mw.OnKeyUp,
@@ -0,0 +1,92 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"fmt"
+
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type Minimap struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// Minimap
+
+	AssignTo    **walk.Minimap
+	Orientation Orientation
+
+	// Source is the Name of the widget to show an overview of. It must
+	// implement walk.MinimapSource.
+	Source string
+}
+
+func (mm Minimap) Create(builder *Builder) error {
+	w, err := walk.NewMinimapWithOrientation(builder.Parent(), walk.Orientation(mm.Orientation))
+	if err != nil {
+		return err
+	}
+
+	if mm.AssignTo != nil {
+		*mm.AssignTo = w
+	}
+
+	return builder.InitWidget(mm, w, func() error {
+		if mm.Source != "" {
+			builder.Defer(func() error {
+				source, ok := builder.name2Window[mm.Source].(walk.MinimapSource)
+				if !ok {
+					return fmt.Errorf("Minimap.Source: no walk.MinimapSource named %q", mm.Source)
+				}
+
+				w.SetSource(source)
+
+				return nil
+			})
+		}
+
+		return nil
+	})
+}
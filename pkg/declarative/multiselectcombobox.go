@@ -0,0 +1,92 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type MultiSelectComboBox struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// MultiSelectComboBox
+
+	AssignTo         **walk.MultiSelectComboBox
+	CheckedIndexes   Property
+	EmptyText        string
+	Model            interface{}
+	OnCheckedChanged walk.EventHandler
+	Separator        string
+}
+
+func (cb MultiSelectComboBox) Create(builder *Builder) error {
+	w, err := walk.NewMultiSelectComboBox(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if cb.AssignTo != nil {
+		*cb.AssignTo = w
+	}
+
+	return builder.InitWidget(cb, w, func() error {
+		w.SetPersistent(cb.Persistent)
+
+		if cb.Separator != "" {
+			w.SetSeparator(cb.Separator)
+		}
+		w.SetEmptyText(cb.EmptyText)
+
+		if err := w.SetModel(cb.Model); err != nil {
+			return err
+		}
+
+		if cb.OnCheckedChanged != nil {
+			w.CheckedChanged().Attach(cb.OnCheckedChanged)
+		}
+
+		return nil
+	})
+}
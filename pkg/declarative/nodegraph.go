@@ -0,0 +1,97 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type NodeGraph struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// NodeGraph
+
+	AssignTo            **walk.NodeGraph
+	ConnectionValidator walk.NodeGraphConnectionValidator
+	OnConnectionAdded   walk.NodeGraphConnectionEventHandler
+	OnConnectionRemoved walk.NodeGraphConnectionEventHandler
+	OnSelectionChanged  walk.EventHandler
+	Snapper             *walk.Snapper
+	Zoom                Property
+}
+
+func (ng NodeGraph) Create(builder *Builder) error {
+	w, err := walk.NewNodeGraph(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if ng.AssignTo != nil {
+		*ng.AssignTo = w
+	}
+
+	return builder.InitWidget(ng, w, func() error {
+		w.SetPersistent(ng.Persistent)
+		w.SetConnectionValidator(ng.ConnectionValidator)
+
+		if ng.Snapper != nil {
+			w.SetSnapper(ng.Snapper)
+		}
+
+		if ng.OnConnectionAdded != nil {
+			w.ConnectionAdded().Attach(ng.OnConnectionAdded)
+		}
+
+		if ng.OnConnectionRemoved != nil {
+			w.ConnectionRemoved().Attach(ng.OnConnectionRemoved)
+		}
+
+		if ng.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(ng.OnSelectionChanged)
+		}
+
+		return nil
+	})
+}
@@ -8,9 +8,21 @@
 package declarative
 
 import (
+	"time"
+
 	"github.com/miu200521358/walk/pkg/walk"
 )
 
+type WheelValueMode int
+
+const (
+	WheelValueDefault     = WheelValueMode(walk.WheelValueDefault)
+	WheelValueAlways      = WheelValueMode(walk.WheelValueAlways)
+	WheelValueWithCtrl    = WheelValueMode(walk.WheelValueWithCtrl)
+	WheelValueNever       = WheelValueMode(walk.WheelValueNever)
+	WheelValueWhenFocused = WheelValueMode(walk.WheelValueWhenFocused)
+)
+
 type NumberEdit struct {
 	// Window
 
@@ -24,6 +36,9 @@ type NumberEdit struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,21 +61,27 @@ type NumberEdit struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// NumberEdit
 
-	AssignTo           **walk.NumberEdit
-	Decimals           int
-	Increment          float64
-	MaxValue           float64
-	MinValue           float64
-	Prefix             Property
-	OnValueChanged     walk.EventHandler
-	ReadOnly           Property
-	SpinButtonsVisible bool
-	Suffix             Property
-	TextColor          walk.Color
-	Value              Property
+	AssignTo               **walk.NumberEdit
+	AutoSubmit             bool
+	AutoSubmitDelay        time.Duration
+	Decimals               int
+	Increment              float64
+	MaxValue               float64
+	MinValue               float64
+	Prefix                 Property
+	OnValueChanged         walk.EventHandler
+	OnValueChangedDetailed walk.ValueChangedEventHandler
+	ReadOnly               Property
+	SpinButtonsVisible     bool
+	Suffix                 Property
+	TextColor              walk.Color
+	Value                  Property
+	WheelValueMode         WheelValueMode
+	Wrapped                bool
 }
 
 func (ne NumberEdit) Create(builder *Builder) error {
@@ -75,6 +96,8 @@ func (ne NumberEdit) Create(builder *Builder) error {
 
 	return builder.InitWidget(ne, w, func() error {
 		w.SetTextColor(ne.TextColor)
+		w.SetWrapped(ne.Wrapped)
+		w.SetWheelValueMode(walk.WheelValueMode(ne.WheelValueMode))
 
 		if err := w.SetDecimals(ne.Decimals); err != nil {
 			return err
@@ -102,6 +125,9 @@ func (ne NumberEdit) Create(builder *Builder) error {
 		if ne.OnValueChanged != nil {
 			w.ValueChanged().Attach(ne.OnValueChanged)
 		}
+		if ne.OnValueChangedDetailed != nil {
+			w.ValueChangedDetailed().Attach(ne.OnValueChangedDetailed)
+		}
 
 		return nil
 	})
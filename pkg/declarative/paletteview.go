@@ -0,0 +1,99 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type PaletteView struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// PaletteView
+
+	AssignTo           **walk.PaletteView
+	OnColorDropped     walk.PaletteColorDroppedEventHandler
+	OnItemActivated    walk.EventHandler
+	OnSelectionChanged walk.EventHandler
+	Palette            *walk.Palette
+	SwatchSize         int
+}
+
+func (pv PaletteView) Create(builder *Builder) error {
+	w, err := walk.NewPaletteView(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if pv.AssignTo != nil {
+		*pv.AssignTo = w
+	}
+
+	return builder.InitWidget(pv, w, func() error {
+		if pv.SwatchSize > 0 {
+			if err := w.SetSwatchSize(pv.SwatchSize); err != nil {
+				return err
+			}
+		}
+
+		if pv.Palette != nil {
+			w.SetPalette(pv.Palette)
+		}
+
+		if pv.OnColorDropped != nil {
+			w.ColorDropped().Attach(pv.OnColorDropped)
+		}
+
+		if pv.OnItemActivated != nil {
+			w.ItemActivated().Attach(pv.OnItemActivated)
+		}
+
+		if pv.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(pv.OnSelectionChanged)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"time"
+
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type PasswordEdit struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// PasswordEdit
+
+	AssignTo            **walk.PasswordEdit
+	ClipboardClearDelay time.Duration // 0 means the default
+	CueBanner           string
+	MaxLength           int
+	NoRevealButton      bool
+	OnEditingFinished   walk.EventHandler
+	OnRevealedChanged   walk.EventHandler
+	OnTextChanged       walk.EventHandler
+	ReadOnly            Property
+	Revealed            Property
+	Text                Property
+	TextColor           walk.Color
+}
+
+func (pe PasswordEdit) Create(builder *Builder) error {
+	w, err := walk.NewPasswordEdit(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if pe.AssignTo != nil {
+		*pe.AssignTo = w
+	}
+
+	return builder.InitWidget(pe, w, func() error {
+		w.SetTextColor(pe.TextColor)
+
+		if pe.CueBanner != "" {
+			if err := w.SetCueBanner(pe.CueBanner); err != nil {
+				return err
+			}
+		}
+		w.SetMaxLength(pe.MaxLength)
+
+		if pe.ClipboardClearDelay > 0 {
+			w.SetClipboardClearDelay(pe.ClipboardClearDelay)
+		}
+
+		if err := w.SetRevealButtonVisible(!pe.NoRevealButton); err != nil {
+			return err
+		}
+
+		if pe.OnEditingFinished != nil {
+			w.EditingFinished().Attach(pe.OnEditingFinished)
+		}
+		if pe.OnRevealedChanged != nil {
+			w.RevealedChanged().Attach(pe.OnRevealedChanged)
+		}
+		if pe.OnTextChanged != nil {
+			w.TextChanged().Attach(pe.OnTextChanged)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type PreferencesPage struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// PreferencesPage
+
+	AssignTo            **walk.PreferencesPage
+	OnAppearanceChanged walk.EventHandler
+}
+
+func (pp PreferencesPage) Create(builder *Builder) error {
+	w, err := walk.NewPreferencesPage(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if pp.AssignTo != nil {
+		*pp.AssignTo = w
+	}
+
+	return builder.InitWidget(pp, w, func() error {
+		if pp.OnAppearanceChanged != nil {
+			handle := walk.AppearanceChanged().Attach(pp.OnAppearanceChanged)
+			w.Disposing().Attach(func() {
+				walk.AppearanceChanged().Detach(handle)
+			})
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+var fontType = reflect.TypeOf(Font{})
+
+// prefetch starts decoding the image files that d and its descendants refer
+// to by name, and creating and measuring their fonts and texts, in the
+// background, so that happens while their windows are being created instead
+// of one by one in between. font is the font d inherits, dpi that of the
+// windows.
+func prefetch(d Widget, font *walk.Font, dpi int) {
+	pc := &prefetchCollector{
+		seenImages: make(map[string]bool),
+		font2Texts: make(map[*walk.Font][]string),
+		seenTexts:  make(map[*walk.Font]map[string]bool),
+	}
+	pc.collect(reflect.ValueOf(d), "", font)
+
+	walk.Resources.PrefetchImages(pc.images...)
+
+	for _, font := range pc.fonts {
+		walk.PrefetchFont(font, dpi, pc.font2Texts[font]...)
+	}
+}
+
+// prefetchCollector collects what prefetch starts on.
+type prefetchCollector struct {
+	seenImages map[string]bool
+	images     []string
+	fonts      []*walk.Font // in the order found
+	font2Texts map[*walk.Font][]string
+	seenTexts  map[*walk.Font]map[string]bool
+}
+
+// collect collects the strings held by fields named like Image, Icon or
+// Images anywhere below v as image names, and those of fields named Text
+// along with the font they are drawn with. Pointers are not followed, so
+// AssignTo targets and models are skipped.
+func (pc *prefetchCollector) collect(v reflect.Value, fieldName string, font *walk.Font) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			pc.collect(v.Elem(), fieldName, font)
+		}
+
+	case reflect.String:
+		name := v.String()
+		if name == "" {
+			break
+		}
+
+		if isImageFieldName(fieldName) && !pc.seenImages[name] {
+			pc.seenImages[name] = true
+			pc.images = append(pc.images, name)
+		} else if fieldName == "Text" {
+			pc.addText(font, name)
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+
+		// Widgets without a Font of their own inherit that of their parent.
+		if f := v.FieldByName("Font"); f.IsValid() && f.Type() == fontType {
+			if wf, err := f.Interface().(Font).Create(); err == nil && wf != nil {
+				font = wf
+			}
+		}
+
+		pc.addFont(font)
+
+		for i := 0; i < v.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" && f.Type != fontType {
+				pc.collect(v.Field(i), f.Name, font)
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			pc.collect(v.Index(i), fieldName, font)
+		}
+	}
+}
+
+func (pc *prefetchCollector) addFont(font *walk.Font) {
+	if _, ok := pc.seenTexts[font]; !ok {
+		pc.seenTexts[font] = make(map[string]bool)
+		pc.fonts = append(pc.fonts, font)
+	}
+}
+
+func (pc *prefetchCollector) addText(font *walk.Font, text string) {
+	pc.addFont(font)
+
+	if !pc.seenTexts[font][text] {
+		pc.seenTexts[font][text] = true
+		pc.font2Texts[font] = append(pc.font2Texts[font], text)
+	}
+}
+
+func isImageFieldName(name string) bool {
+	return strings.HasSuffix(name, "Image") || strings.HasSuffix(name, "Images") || strings.HasSuffix(name, "Icon")
+}
@@ -24,6 +24,9 @@ type PushButton struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,6 +49,7 @@ type PushButton struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Button
 
@@ -55,8 +59,9 @@ type PushButton struct {
 
 	// PushButton
 
-	AssignTo       **walk.PushButton
-	ImageAboveText bool
+	AssignTo          **walk.PushButton
+	ImageAboveText    bool
+	RequiresElevation bool
 }
 
 func (pb PushButton) Create(builder *Builder) error {
@@ -74,6 +79,8 @@ func (pb PushButton) Create(builder *Builder) error {
 			return err
 		}
 
+		w.SetRequiresElevation(pb.RequiresElevation)
+
 		if pb.OnClicked != nil {
 			w.Clicked().Attach(pb.OnClicked)
 		}
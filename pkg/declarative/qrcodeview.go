@@ -0,0 +1,96 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type QRErrorCorrectionLevel int
+
+const (
+	QRErrorCorrectionMedium   = QRErrorCorrectionLevel(walk.QRErrorCorrectionMedium)
+	QRErrorCorrectionLow      = QRErrorCorrectionLevel(walk.QRErrorCorrectionLow)
+	QRErrorCorrectionQuartile = QRErrorCorrectionLevel(walk.QRErrorCorrectionQuartile)
+	QRErrorCorrectionHigh     = QRErrorCorrectionLevel(walk.QRErrorCorrectionHigh)
+)
+
+type QRCodeView struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// QRCodeView
+
+	AssignTo             **walk.QRCodeView
+	ErrorCorrectionLevel QRErrorCorrectionLevel
+
+	// QuietZone is the width of the light margin around the code, in
+	// modules. 0 keeps the 4 modules the standard requires.
+	QuietZone int
+
+	Text Property
+}
+
+func (qv QRCodeView) Create(builder *Builder) error {
+	w, err := walk.NewQRCodeView(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if qv.AssignTo != nil {
+		*qv.AssignTo = w
+	}
+
+	return builder.InitWidget(qv, w, func() error {
+		if err := w.SetErrorCorrectionLevel(walk.QRErrorCorrectionLevel(qv.ErrorCorrectionLevel)); err != nil {
+			return err
+		}
+
+		if qv.QuietZone > 0 {
+			w.SetQuietZone(qv.QuietZone)
+		}
+
+		return nil
+	})
+}
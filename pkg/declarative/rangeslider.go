@@ -0,0 +1,102 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type RangeSlider struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// RangeSlider
+
+	AssignTo       **walk.RangeSlider
+	LineSize       int
+	Lower          Property
+	MaxValue       int
+	MinValue       int
+	OnRangeChanged walk.IntRangeEventHandler
+	Orientation    Orientation
+	PageSize       int
+	TickFrequency  int
+	Upper          Property
+	WheelValueMode WheelValueMode
+}
+
+func (rs RangeSlider) Create(builder *Builder) error {
+	w, err := walk.NewRangeSliderWithOrientation(builder.Parent(), walk.Orientation(rs.Orientation))
+	if err != nil {
+		return err
+	}
+
+	if rs.AssignTo != nil {
+		*rs.AssignTo = w
+	}
+
+	return builder.InitWidget(rs, w, func() error {
+		w.SetPersistent(rs.Persistent)
+		if rs.LineSize > 0 {
+			w.SetLineSize(rs.LineSize)
+		}
+		if rs.PageSize > 0 {
+			w.SetPageSize(rs.PageSize)
+		}
+		w.SetTickFrequency(rs.TickFrequency)
+		w.SetWheelValueMode(walk.WheelValueMode(rs.WheelValueMode))
+
+		if rs.MaxValue > rs.MinValue {
+			if err := w.SetRange(rs.MinValue, rs.MaxValue); err != nil {
+				return err
+			}
+		}
+
+		if rs.OnRangeChanged != nil {
+			w.RangeChanged().Attach(rs.OnRangeChanged)
+		}
+
+		return nil
+	})
+}
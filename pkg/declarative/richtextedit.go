@@ -0,0 +1,104 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/win"
+
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type RichTextEdit struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// RichTextEdit
+
+	AssignTo            **walk.RichTextEdit
+	FindReplaceEnabled  bool
+	HScroll             bool
+	HighlightAllMatches bool
+	OnSelectionChanged  walk.EventHandler
+	OnTextChanged       walk.EventHandler
+	RTF                 string
+	ReadOnly            Property
+	Text                Property
+}
+
+func (rte RichTextEdit) Create(builder *Builder) error {
+	style := uint32(win.WS_VSCROLL)
+	if rte.HScroll {
+		style |= win.WS_HSCROLL
+	}
+
+	w, err := walk.NewRichTextEditWithStyle(builder.Parent(), style)
+	if err != nil {
+		return err
+	}
+
+	if rte.AssignTo != nil {
+		*rte.AssignTo = w
+	}
+
+	return builder.InitWidget(rte, w, func() error {
+		w.SetFindReplaceEnabled(rte.FindReplaceEnabled)
+		w.SetHighlightAllMatches(rte.HighlightAllMatches)
+
+		if rte.RTF != "" {
+			if err := w.SetRTF(rte.RTF); err != nil {
+				return err
+			}
+		}
+
+		if rte.OnTextChanged != nil {
+			w.TextChanged().Attach(rte.OnTextChanged)
+		}
+
+		if rte.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(rte.OnSelectionChanged)
+		}
+
+		return nil
+	})
+}
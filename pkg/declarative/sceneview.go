@@ -0,0 +1,93 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type SceneView struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// SceneView
+
+	AssignTo           **walk.SceneView
+	OnItemsMoved       walk.SceneItemsDragEventHandler
+	OnSelectionChanged walk.EventHandler
+	OnViewChanged      walk.EventHandler
+	Snapper            *walk.Snapper
+	Zoom               Property
+}
+
+func (sv SceneView) Create(builder *Builder) error {
+	w, err := walk.NewSceneView(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if sv.AssignTo != nil {
+		*sv.AssignTo = w
+	}
+
+	return builder.InitWidget(sv, w, func() error {
+		if sv.Snapper != nil {
+			w.SetSnapper(sv.Snapper)
+		}
+
+		if sv.OnItemsMoved != nil {
+			w.ItemsMoved().Attach(sv.OnItemsMoved)
+		}
+
+		if sv.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(sv.OnSelectionChanged)
+		}
+
+		if sv.OnViewChanged != nil {
+			w.ViewChanged().Attach(sv.OnViewChanged)
+		}
+
+		return nil
+	})
+}
@@ -11,6 +11,14 @@ import (
 	"github.com/miu200521358/walk/pkg/walk"
 )
 
+type ScrollBarPolicy int
+
+const (
+	ScrollBarAuto   = ScrollBarPolicy(walk.ScrollBarAuto)
+	ScrollBarAlways = ScrollBarPolicy(walk.ScrollBarAlways)
+	ScrollBarNever  = ScrollBarPolicy(walk.ScrollBarNever)
+)
+
 type ScrollView struct {
 	// Window
 
@@ -24,6 +32,9 @@ type ScrollView struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,6 +57,7 @@ type ScrollView struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Container
 
@@ -55,9 +67,16 @@ type ScrollView struct {
 
 	// ScrollView
 
-	AssignTo        **walk.ScrollView
-	HorizontalFixed bool
-	VerticalFixed   bool
+	AssignTo            **walk.ScrollView
+	FitWidth            bool
+	HorizontalFixed     bool // Deprecated: use HorizontalScrollBar instead
+	HorizontalScrollBar ScrollBarPolicy
+	OnScrolled          walk.ScrollEventHandler
+	ScrollX             int // in 1/96"
+	ScrollY             int // in 1/96"
+	SmoothScrolling     bool
+	VerticalFixed       bool // Deprecated: use VerticalScrollBar instead
+	VerticalScrollBar   ScrollBarPolicy
 }
 
 func (sv ScrollView) Create(builder *Builder) error {
@@ -76,9 +95,33 @@ func (sv ScrollView) Create(builder *Builder) error {
 		return nil
 	})
 
-	w.SetScrollbars(!sv.HorizontalFixed, !sv.VerticalFixed)
+	h, v := walk.ScrollBarPolicy(sv.HorizontalScrollBar), walk.ScrollBarPolicy(sv.VerticalScrollBar)
+	if sv.HorizontalFixed {
+		h = walk.ScrollBarNever
+	}
+	if sv.VerticalFixed {
+		v = walk.ScrollBarNever
+	}
+	w.SetScrollBarPolicies(h, v)
+
+	w.SetFitWidth(sv.FitWidth)
 
 	return builder.InitWidget(sv, w, func() error {
+		w.SetSmoothScrolling(sv.SmoothScrolling)
+
+		if sv.OnScrolled != nil {
+			w.Scrolled().Attach(sv.OnScrolled)
+		}
+
+		if sv.ScrollX != 0 || sv.ScrollY != 0 {
+			walk.OnceVisible(w, func() {
+				// Wait for the layout, so the content can be scrolled.
+				w.Synchronize(func() {
+					w.SetScrollPositionPixels(walk.Point{w.IntFrom96DPI(sv.ScrollX), w.IntFrom96DPI(sv.ScrollY)})
+				})
+			})
+		}
+
 		return nil
 	})
 }
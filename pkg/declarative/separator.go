@@ -24,6 +24,9 @@ type HSeparator struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,6 +49,7 @@ type HSeparator struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Separator
 
@@ -78,6 +82,9 @@ type VSeparator struct {
 	MinSize          Size
 	Name             string
 	OnBoundsChanged  walk.EventHandler
+	OnCreated        walk.EventHandler
+	OnDisposed       walk.EventHandler
+	OnFirstVisible   walk.EventHandler
 	OnKeyDown        walk.KeyEventHandler
 	OnKeyPress       walk.KeyEventHandler
 	OnKeyUp          walk.KeyEventHandler
@@ -97,6 +104,7 @@ type VSeparator struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Separator
 
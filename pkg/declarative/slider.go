@@ -8,6 +8,8 @@
 package declarative
 
 import (
+	"time"
+
 	"github.com/miu200521358/walk/pkg/walk"
 )
 
@@ -24,6 +26,9 @@ type Slider struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,19 +51,30 @@ type Slider struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Slider
 
-	AssignTo       **walk.Slider
-	LineSize       int
-	MaxValue       int
-	MinValue       int
-	Orientation    Orientation
-	OnValueChanged walk.EventHandler
-	PageSize       int
-	ToolTipsHidden bool
-	Tracking       bool
-	Value          Property
+	AssignTo               **walk.Slider
+	AutoSubmit             bool
+	AutoSubmitDelay        time.Duration
+	BuddyLabelsVisible     bool
+	LineSize               int
+	MaxLabelText           string
+	MaxValue               int
+	MinLabelText           string
+	MinValue               int
+	Orientation            Orientation
+	OnRangeChanged         walk.EventHandler
+	OnValueChanged         walk.EventHandler
+	OnValueChangedDetailed walk.ValueChangedEventHandler
+	PageSize               int
+	RangeEditable          bool
+	ToolTipsHidden         bool
+	Tracking               bool
+	Value                  Property
+	WheelValueMode         WheelValueMode
+	Wrapped                bool
 }
 
 func (sl Slider) Create(builder *Builder) error {
@@ -83,14 +99,27 @@ func (sl Slider) Create(builder *Builder) error {
 			w.SetPageSize(sl.PageSize)
 		}
 		w.SetTracking(sl.Tracking)
+		w.SetWrapped(sl.Wrapped)
+		w.SetWheelValueMode(walk.WheelValueMode(sl.WheelValueMode))
 
 		if sl.MaxValue > sl.MinValue {
 			w.SetRange(sl.MinValue, sl.MaxValue)
 		}
 
+		w.SetMinLabelText(sl.MinLabelText)
+		w.SetMaxLabelText(sl.MaxLabelText)
+		w.SetBuddyLabelsVisible(sl.BuddyLabelsVisible)
+		w.SetRangeEditable(sl.RangeEditable)
+
+		if sl.OnRangeChanged != nil {
+			w.RangeChanged().Attach(sl.OnRangeChanged)
+		}
 		if sl.OnValueChanged != nil {
 			w.ValueChanged().Attach(sl.OnValueChanged)
 		}
+		if sl.OnValueChangedDetailed != nil {
+			w.ValueChangedDetailed().Attach(sl.OnValueChangedDetailed)
+		}
 
 		return nil
 	})
@@ -24,6 +24,9 @@ type HSplitter struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,6 +49,7 @@ type HSplitter struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Container
 
@@ -54,8 +58,11 @@ type HSplitter struct {
 
 	// Splitter
 
-	AssignTo    **walk.Splitter
-	HandleWidth int
+	AssignTo           **walk.Splitter
+	Fractions          []float64
+	HandleWidth        int
+	OnFractionsChanged walk.EventHandler
+	Snapper            *walk.Snapper
 }
 
 func (s HSplitter) Create(builder *Builder) error {
@@ -81,6 +88,20 @@ func (s HSplitter) Create(builder *Builder) error {
 			}
 		}
 
+		if s.Snapper != nil {
+			w.SetSnapper(s.Snapper)
+		}
+
+		if s.Fractions != nil {
+			if err := w.SetFractions(s.Fractions); err != nil {
+				return err
+			}
+		}
+
+		if s.OnFractionsChanged != nil {
+			w.FractionsChanged().Attach(s.OnFractionsChanged)
+		}
+
 		return nil
 	})
 }
@@ -97,6 +118,9 @@ type VSplitter struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -117,6 +141,7 @@ type VSplitter struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// Container
 
@@ -125,8 +150,11 @@ type VSplitter struct {
 
 	// Splitter
 
-	AssignTo    **walk.Splitter
-	HandleWidth int
+	AssignTo           **walk.Splitter
+	Fractions          []float64
+	HandleWidth        int
+	OnFractionsChanged walk.EventHandler
+	Snapper            *walk.Snapper
 }
 
 func (s VSplitter) Create(builder *Builder) error {
@@ -152,6 +180,20 @@ func (s VSplitter) Create(builder *Builder) error {
 			}
 		}
 
+		if s.Snapper != nil {
+			w.SetSnapper(s.Snapper)
+		}
+
+		if s.Fractions != nil {
+			if err := w.SetFractions(s.Fractions); err != nil {
+				return err
+			}
+		}
+
+		if s.OnFractionsChanged != nil {
+			w.FractionsChanged().Attach(s.OnFractionsChanged)
+		}
+
 		return nil
 	})
 }
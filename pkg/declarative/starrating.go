@@ -0,0 +1,97 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type StarRating struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// StarRating
+
+	AssignTo        **walk.StarRating
+	HalfStars       bool
+	MaxStars        int
+	OnRatingChanged walk.EventHandler
+	Rating          Property
+	ReadOnly        bool
+}
+
+func (sr StarRating) Create(builder *Builder) error {
+	w, err := walk.NewStarRating(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if sr.AssignTo != nil {
+		*sr.AssignTo = w
+	}
+
+	return builder.InitWidget(sr, w, func() error {
+		w.SetPersistent(sr.Persistent)
+
+		if sr.MaxStars > 0 {
+			if err := w.SetMaxStars(sr.MaxStars); err != nil {
+				return err
+			}
+		}
+
+		if err := w.SetHalfStars(sr.HalfStars); err != nil {
+			return err
+		}
+
+		if err := w.SetReadOnly(sr.ReadOnly); err != nil {
+			return err
+		}
+
+		if sr.OnRatingChanged != nil {
+			w.RatingChanged().Attach(sr.OnRatingChanged)
+		}
+
+		return nil
+	})
+}
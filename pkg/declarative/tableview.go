@@ -15,6 +15,7 @@ import (
 type TableView struct {
 	// Window
 
+	AcceptsDrops       bool
 	Accessibility      Accessibility
 	Background         Brush
 	ContextMenuItems   []MenuItem
@@ -25,6 +26,13 @@ type TableView struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnDragEnter        walk.DragEventHandler
+	OnDragLeave        walk.EventHandler
+	OnDragOver         walk.DragEventHandler
+	OnDrop             walk.DragEventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -47,6 +55,7 @@ type TableView struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// TableView
 
@@ -54,6 +63,7 @@ type TableView struct {
 	AssignTo                    **walk.TableView
 	CellStyler                  walk.CellStyler
 	CheckBoxes                  bool
+	ColumnChooserEnabled        bool
 	Columns                     []TableViewColumn
 	ColumnsOrderable            Property
 	ColumnsSizable              Property
@@ -163,6 +173,7 @@ func (tv TableView) Create(builder *Builder) error {
 
 		w.SetAlternatingRowBG(tv.AlternatingRowBG)
 		w.SetCheckBoxes(tv.CheckBoxes)
+		w.SetColumnChooserEnabled(tv.ColumnChooserEnabled)
 		w.SetItemStateChangedEventDelay(tv.ItemStateChangedEventDelay)
 		if err := w.SetLastColumnStretched(tv.LastColumnStretched); err != nil {
 			return err
@@ -33,6 +33,7 @@ type TableViewColumn struct {
 	StyleCell  func(style *walk.CellStyle)
 	LessFunc   func(i, j int) bool
 	FormatFunc func(value interface{}) string
+	CellStyler walk.CellStyler
 }
 
 func (tvc TableViewColumn) Create(tv *walk.TableView) error {
@@ -65,6 +66,9 @@ func (tvc TableViewColumn) Create(tv *walk.TableView) error {
 	}
 	w.SetLessFunc(tvc.LessFunc)
 	w.SetFormatFunc(tvc.FormatFunc)
+	if tvc.CellStyler != nil {
+		w.SetCellStyler(tvc.CellStyler)
+	}
 
 	return tv.Columns().Add(w)
 }
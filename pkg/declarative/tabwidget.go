@@ -11,6 +11,15 @@ import (
 	"github.com/miu200521358/walk/pkg/walk"
 )
 
+type TabPosition int
+
+const (
+	TabPositionTop    TabPosition = TabPosition(walk.TabPositionTop)
+	TabPositionBottom TabPosition = TabPosition(walk.TabPositionBottom)
+	TabPositionLeft   TabPosition = TabPosition(walk.TabPositionLeft)
+	TabPositionRight  TabPosition = TabPosition(walk.TabPositionRight)
+)
+
 type TabWidget struct {
 	// Window
 
@@ -24,6 +33,9 @@ type TabWidget struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,14 +58,22 @@ type TabWidget struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// TabWidget
 
 	AssignTo              **walk.TabWidget
 	ContentMargins        Margins
 	ContentMarginsZero    bool
+	NewPageButtonVisible  bool
 	OnCurrentIndexChanged walk.EventHandler
+	OnNewPageRequested    walk.EventHandler
+	OnPageClosing         walk.TabPageClosingEventHandler
+	OnPagesReordered      walk.EventHandler
 	Pages                 []TabPage
+	TabPosition           TabPosition
+	TabsClosable          bool
+	TabsReorderable       bool
 }
 
 func (tw TabWidget) Create(builder *Builder) error {
@@ -86,9 +106,26 @@ func (tw TabWidget) Create(builder *Builder) error {
 			}
 		}
 
+		if err := w.SetTabPosition(walk.TabPosition(tw.TabPosition)); err != nil {
+			return err
+		}
+
+		w.SetTabsClosable(tw.TabsClosable)
+		w.SetTabsReorderable(tw.TabsReorderable)
+		w.SetNewPageButtonVisible(tw.NewPageButtonVisible)
+
 		if tw.OnCurrentIndexChanged != nil {
 			w.CurrentIndexChanged().Attach(tw.OnCurrentIndexChanged)
 		}
+		if tw.OnNewPageRequested != nil {
+			w.NewPageRequested().Attach(tw.OnNewPageRequested)
+		}
+		if tw.OnPageClosing != nil {
+			w.PageClosing().Attach(tw.OnPageClosing)
+		}
+		if tw.OnPagesReordered != nil {
+			w.PagesReordered().Attach(tw.OnPagesReordered)
+		}
 
 		return nil
 	})
@@ -16,6 +16,7 @@ import (
 type TextEdit struct {
 	// Window
 
+	AcceptsDrops       bool
 	Accessibility      Accessibility
 	Background         Brush
 	ContextMenuItems   []MenuItem
@@ -26,6 +27,13 @@ type TextEdit struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnDragEnter        walk.DragEventHandler
+	OnDragLeave        walk.EventHandler
+	OnDragOver         walk.DragEventHandler
+	OnDrop             walk.DragEventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -48,19 +56,22 @@ type TextEdit struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// TextEdit
 
-	AssignTo      **walk.TextEdit
-	CompactHeight bool
-	HScroll       bool
-	MaxLength     int
-	OnTextChanged walk.EventHandler
-	ReadOnly      Property
-	Text          Property
-	TextAlignment Alignment1D
-	TextColor     walk.Color
-	VScroll       bool
+	AssignTo            **walk.TextEdit
+	CompactHeight       bool
+	FindReplaceEnabled  bool
+	HScroll             bool
+	HighlightAllMatches bool
+	MaxLength           int
+	OnTextChanged       walk.EventHandler
+	ReadOnly            Property
+	Text                Property
+	TextAlignment       Alignment1D
+	TextColor           walk.Color
+	VScroll             bool
 }
 
 func (te TextEdit) Create(builder *Builder) error {
@@ -84,6 +95,8 @@ func (te TextEdit) Create(builder *Builder) error {
 	return builder.InitWidget(te, w, func() error {
 		w.SetCompactHeight(te.CompactHeight)
 		w.SetTextColor(te.TextColor)
+		w.SetFindReplaceEnabled(te.FindReplaceEnabled)
+		w.SetHighlightAllMatches(te.HighlightAllMatches)
 
 		if err := w.SetTextAlignment(walk.Alignment1D(te.TextAlignment)); err != nil {
 			return err
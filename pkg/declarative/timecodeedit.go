@@ -0,0 +1,91 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type TimecodeEdit struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// TimecodeEdit
+
+	AssignTo           **walk.TimecodeEdit
+	FPS                Property
+	Frames             Property
+	MaxFrames          Property
+	OnFramesChanged    walk.EventHandler
+	ReadOnly           Property
+	SpinButtonsVisible bool
+	TextColor          walk.Color
+	WheelValueMode     WheelValueMode
+}
+
+func (te TimecodeEdit) Create(builder *Builder) error {
+	w, err := walk.NewTimecodeEdit(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if te.AssignTo != nil {
+		*te.AssignTo = w
+	}
+
+	return builder.InitWidget(te, w, func() error {
+		w.SetTextColor(te.TextColor)
+		w.SetWheelValueMode(walk.WheelValueMode(te.WheelValueMode))
+
+		if err := w.SetSpinButtonsVisible(te.SpinButtonsVisible); err != nil {
+			return err
+		}
+
+		if te.OnFramesChanged != nil {
+			w.FramesChanged().Attach(te.OnFramesChanged)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type Timeline struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// Timeline
+
+	AssignTo           **walk.Timeline
+	Frame              Property
+	FrameCount         Property
+	FrameWidth         float64
+	OnFrameChanged     walk.EventHandler
+	OnKeyframesMoved   walk.TimelineKeyframesMovedEventHandler
+	OnSelectionChanged walk.EventHandler
+	Tracks             []string
+}
+
+func (t Timeline) Create(builder *Builder) error {
+	w, err := walk.NewTimeline(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if t.AssignTo != nil {
+		*t.AssignTo = w
+	}
+
+	return builder.InitWidget(t, w, func() error {
+		if t.FrameWidth > 0 {
+			if err := w.SetFrameWidth(t.FrameWidth); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range t.Tracks {
+			w.AddTrack(name)
+		}
+
+		if t.OnFrameChanged != nil {
+			w.FrameChanged().Attach(t.OnFrameChanged)
+		}
+
+		if t.OnKeyframesMoved != nil {
+			w.KeyframesMoved().Attach(t.OnKeyframesMoved)
+		}
+
+		if t.OnSelectionChanged != nil {
+			w.SelectionChanged().Attach(t.OnSelectionChanged)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package declarative
+
+import (
+	"github.com/miu200521358/walk/pkg/walk"
+)
+
+type TokenEdit struct {
+	// Window
+
+	Accessibility      Accessibility
+	Background         Brush
+	ContextMenuItems   []MenuItem
+	DoubleBuffering    bool
+	Enabled            Property
+	Font               Font
+	MaxSize            Size
+	MinSize            Size
+	Name               string
+	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
+	OnKeyDown          walk.KeyEventHandler
+	OnKeyPress         walk.KeyEventHandler
+	OnKeyUp            walk.KeyEventHandler
+	OnMouseDown        walk.MouseEventHandler
+	OnMouseMove        walk.MouseEventHandler
+	OnMouseUp          walk.MouseEventHandler
+	OnSizeChanged      walk.EventHandler
+	Persistent         bool
+	RightToLeftReading bool
+	ToolTipText        Property
+	Visible            Property
+
+	// Widget
+
+	Alignment          Alignment2D
+	AlwaysConsumeSpace bool
+	Column             int
+	ColumnSpan         int
+	GraphicsEffects    []walk.WidgetGraphicsEffect
+	Row                int
+	RowSpan            int
+	StretchFactor      int
+	WheelRequiresFocus bool
+
+	// TokenEdit
+
+	AssignTo        **walk.TokenEdit
+	CaseSensitive   bool
+	CueBanner       string
+	Model           walk.ListModel
+	OnTokensChanged walk.EventHandler
+	ReadOnly        Property
+	Tokens          Property
+}
+
+func (te TokenEdit) Create(builder *Builder) error {
+	w, err := walk.NewTokenEdit(builder.Parent())
+	if err != nil {
+		return err
+	}
+
+	if te.AssignTo != nil {
+		*te.AssignTo = w
+	}
+
+	return builder.InitWidget(te, w, func() error {
+		w.SetCaseSensitive(te.CaseSensitive)
+		w.SetModel(te.Model)
+
+		if err := w.SetCueBanner(te.CueBanner); err != nil {
+			return err
+		}
+
+		if te.OnTokensChanged != nil {
+			w.TokensChanged().Attach(te.OnTokensChanged)
+		}
+
+		return nil
+	})
+}
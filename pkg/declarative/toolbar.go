@@ -33,6 +33,9 @@ type ToolBar struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -55,15 +58,18 @@ type ToolBar struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// ToolBar
 
 	Actions     []*walk.Action // Deprecated, use Items instead
 	AssignTo    **walk.ToolBar
 	ButtonStyle ToolBarButtonStyle
+	Columns     int
 	Items       []MenuItem
 	MaxTextRows int
 	Orientation Orientation
+	Wrapping    bool
 }
 
 func (tb ToolBar) Create(builder *Builder) error {
@@ -91,6 +97,16 @@ func (tb ToolBar) Create(builder *Builder) error {
 			return err
 		}
 
+		if tb.Columns > 1 {
+			if err := w.SetColumns(tb.Columns); err != nil {
+				return err
+			}
+		}
+
+		if err := w.SetWrapping(tb.Wrapping); err != nil {
+			return err
+		}
+
 		if len(tb.Items) > 0 {
 			builder.deferBuildActions(w.Actions(), tb.Items)
 		} else {
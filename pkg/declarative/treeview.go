@@ -14,6 +14,7 @@ import (
 type TreeView struct {
 	// Window
 
+	AcceptsDrops       bool
 	Accessibility      Accessibility
 	Background         Brush
 	ContextMenuItems   []MenuItem
@@ -24,6 +25,13 @@ type TreeView struct {
 	MinSize            Size
 	Name               string
 	OnBoundsChanged    walk.EventHandler
+	OnCreated          walk.EventHandler
+	OnDisposed         walk.EventHandler
+	OnDragEnter        walk.DragEventHandler
+	OnDragLeave        walk.EventHandler
+	OnDragOver         walk.DragEventHandler
+	OnDrop             walk.DragEventHandler
+	OnFirstVisible     walk.EventHandler
 	OnKeyDown          walk.KeyEventHandler
 	OnKeyPress         walk.KeyEventHandler
 	OnKeyUp            walk.KeyEventHandler
@@ -46,6 +54,7 @@ type TreeView struct {
 	Row                int
 	RowSpan            int
 	StretchFactor      int
+	WheelRequiresFocus bool
 
 	// TreeView
 
@@ -69,7 +78,7 @@ func (tv TreeView) Create(builder *Builder) error {
 
 	return builder.InitWidget(tv, w, func() error {
 		if tv.ItemHeight > 0 {
-			w.SetItemHeight(w.IntFrom96DPI(tv.ItemHeight)) // VERIFY: Item height should resize on DPI change.
+			w.SetItemHeight(w.IntFrom96DPI(tv.ItemHeight))
 		}
 
 		if err := w.SetModel(tv.Model); err != nil {
@@ -36,6 +36,13 @@ func (re Regexp) Create() (walk.Validator, error) {
 	return walk.NewRegexpValidator(re.Pattern)
 }
 
+type Required struct {
+}
+
+func (Required) Create() (walk.Validator, error) {
+	return walk.RequiredValidator(), nil
+}
+
 type SelRequired struct {
 }
 
@@ -43,6 +50,16 @@ func (SelRequired) Create() (walk.Validator, error) {
 	return walk.SelectionRequiredValidator(), nil
 }
 
+// Custom validates with a function, e.g. to compare a value with others of
+// the form.
+type Custom struct {
+	Func func(v interface{}) error
+}
+
+func (c Custom) Create() (walk.Validator, error) {
+	return walk.ValidatorFunc(c.Func), nil
+}
+
 type dMultiValidator struct {
 	validators []Validator
 }
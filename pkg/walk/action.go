@@ -34,6 +34,7 @@ type Action struct {
 	defaultConditionChangedHandle int
 	enabledCondition              Condition
 	enabledConditionChangedHandle int
+	canExecute                    func() bool
 	visibleCondition              Condition
 	visibleConditionChangedHandle int
 	refCount                      int
@@ -44,6 +45,7 @@ type Action struct {
 	checked                       bool
 	defawlt                       bool
 	exclusive                     bool
+	requiresElevation             bool
 	id                            uint16
 }
 
@@ -83,6 +85,7 @@ func (a *Action) release() {
 	a.refCount--
 
 	if a.refCount == 0 {
+		a.SetCanExecute(nil)
 		a.SetEnabledCondition(nil)
 		a.SetVisibleCondition(nil)
 
@@ -447,6 +450,8 @@ func (a *Action) raiseTriggered() {
 	}
 
 	a.triggeredPublisher.Publish()
+
+	invalidateCommandsIfAny()
 }
 
 func (a *Action) addChangedHandler(handler actionChangedHandler) {
@@ -0,0 +1,465 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+// AnchorEdge identifies an edge of a widget or container.
+type AnchorEdge int
+
+const (
+	AnchorLeft AnchorEdge = iota
+	AnchorTop
+	AnchorRight
+	AnchorBottom
+)
+
+// AnchorTarget attaches an edge of a widget to an edge of its container or of
+// a sibling.
+type AnchorTarget struct {
+	// Sibling is the widget to attach to, or nil for the container. When
+	// attaching to the container, the anchored edge is always attached to the
+	// same edge of the container, inside its margins, and Edge is ignored.
+	Sibling Widget
+
+	// Edge is the edge of Sibling to attach to. It must be on the same axis
+	// as the anchored edge.
+	Edge AnchorEdge
+
+	// Offset is the distance from the target edge in 1/96". Positive values
+	// move left and top edges right and down, and right and bottom edges left
+	// and up, so they always add space between the widget and its target.
+	Offset int
+}
+
+// Anchors describes how the edges of a widget are attached in an
+// AnchorLayout. A nil edge is not attached.
+//
+// If both edges of an axis are attached, the widget is stretched between
+// them. If only one is attached, the widget keeps its ideal size on that axis
+// and moves with the attached edge. If neither is attached, the widget keeps
+// its ideal size at the near edge of the container.
+type Anchors struct {
+	Left   *AnchorTarget
+	Top    *AnchorTarget
+	Right  *AnchorTarget
+	Bottom *AnchorTarget
+}
+
+// AnchorToParent returns an AnchorTarget that attaches an edge to the same
+// edge of the container, at offset in 1/96".
+func AnchorToParent(offset int) *AnchorTarget {
+	return &AnchorTarget{Offset: offset}
+}
+
+// AnchorToSibling returns an AnchorTarget that attaches an edge to edge of
+// sibling, at offset in 1/96".
+func AnchorToSibling(sibling Widget, edge AnchorEdge, offset int) *AnchorTarget {
+	return &AnchorTarget{Sibling: sibling, Edge: edge, Offset: offset}
+}
+
+// AnchorLayout positions each widget of a container by attaching its edges to
+// the edges of the container or of siblings, e.g. for dialog-style forms with
+// fixed placement that still need to stretch or move widgets on resize.
+//
+// Widgets without anchors are placed at the top left edge of the container.
+type AnchorLayout struct {
+	LayoutBase
+	hwnd2Anchors map[win.HWND]Anchors
+}
+
+func NewAnchorLayout() *AnchorLayout {
+	l := &AnchorLayout{
+		LayoutBase: LayoutBase{
+			margins96dpi: Margins{9, 9, 9, 9},
+		},
+		hwnd2Anchors: make(map[win.HWND]Anchors),
+	}
+	l.layout = l
+
+	return l
+}
+
+// Anchors returns the anchors of widget.
+func (l *AnchorLayout) Anchors(widget Widget) Anchors {
+	return l.hwnd2Anchors[widget.Handle()]
+}
+
+// SetAnchors sets the anchors of widget, which must be a child of the
+// container of the layout. Siblings referenced by anchors must be children of
+// the same container.
+func (l *AnchorLayout) SetAnchors(widget Widget, anchors Anchors) error {
+	if l.container == nil {
+		return newError("container required")
+	}
+
+	handle := widget.Handle()
+
+	if !l.container.Children().containsHandle(handle) {
+		return newError("unknown widget")
+	}
+
+	for i, target := range []*AnchorTarget{anchors.Left, anchors.Top, anchors.Right, anchors.Bottom} {
+		if target == nil || target.Sibling == nil {
+			continue
+		}
+
+		if target.Sibling.Handle() == handle {
+			return newError("widget can't be anchored to itself")
+		}
+		if !l.container.Children().containsHandle(target.Sibling.Handle()) {
+			return newError("unknown sibling")
+		}
+		if target.Edge < AnchorLeft || target.Edge > AnchorBottom {
+			return newError("invalid edge")
+		}
+		if target.Edge%2 != AnchorEdge(i%2) {
+			return newError("edge must be on the same axis")
+		}
+	}
+
+	l.hwnd2Anchors[handle] = anchors
+
+	l.container.RequestLayout()
+
+	return nil
+}
+
+func (l *AnchorLayout) CreateLayoutItem(ctx *LayoutContext) ContainerLayoutItem {
+	li := &anchorLayoutItem{
+		hwnd2Anchors: make(map[win.HWND][4]anchorLayoutTarget),
+	}
+
+	for hwnd, anchors := range l.hwnd2Anchors {
+		var targets [4]anchorLayoutTarget
+
+		for i, target := range []*AnchorTarget{anchors.Left, anchors.Top, anchors.Right, anchors.Bottom} {
+			if target == nil {
+				continue
+			}
+
+			t := anchorLayoutTarget{
+				attached:    true,
+				edge:        AnchorEdge(i),
+				offset96dpi: target.Offset,
+			}
+			if target.Sibling != nil {
+				t.sibling = target.Sibling.Handle()
+				t.edge = target.Edge
+			}
+
+			targets[i] = t
+		}
+
+		li.hwnd2Anchors[hwnd] = targets
+	}
+
+	return li
+}
+
+type anchorLayoutTarget struct {
+	attached    bool
+	sibling     win.HWND // 0 for the container
+	edge        AnchorEdge
+	offset96dpi int
+}
+
+type anchorLayoutItem struct {
+	ContainerLayoutItemBase
+	hwnd2Anchors map[win.HWND][4]anchorLayoutTarget
+}
+
+// anchorLayoutSpan is the position and size of an item on one axis, in native
+// pixels.
+type anchorLayoutSpan struct {
+	pos  int
+	size int
+}
+
+func (*anchorLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | ShrinkableVert | GrowableHorz | GrowableVert | GreedyHorz | GreedyVert
+}
+
+func (*anchorLayoutItem) HasHeightForWidth() bool {
+	return false
+}
+
+func (li *anchorLayoutItem) HeightForWidth(width int) int {
+	return li.MinSize().Height
+}
+
+func (li *anchorLayoutItem) MinSize() Size {
+	return li.requiredSize(li.MinSizeEffectiveForChild)
+}
+
+func (li *anchorLayoutItem) MinSizeForSize(size Size) Size {
+	return li.MinSize()
+}
+
+func (li *anchorLayoutItem) IdealSize() Size {
+	return li.requiredSize(li.preferredSize)
+}
+
+func (li *anchorLayoutItem) PerformLayout() []LayoutResultItem {
+	items := li.itemsToLayout()
+
+	horz := li.spans(items, false, li.geometry.ClientSize.Width)
+	vert := li.spans(items, true, li.geometry.ClientSize.Height)
+
+	resultItems := make([]LayoutResultItem, len(items))
+
+	for i, item := range items {
+		resultItems[i] = LayoutResultItem{
+			Item:   item,
+			Bounds: Rectangle{horz[i].pos, vert[i].pos, horz[i].size, vert[i].size},
+		}
+	}
+
+	return resultItems
+}
+
+func (li *anchorLayoutItem) itemsToLayout() []LayoutItem {
+	var items []LayoutItem
+
+	for _, item := range li.children {
+		if shouldLayoutItem(item) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// preferredSize returns the ideal size of item, but at least its minimum size,
+// in native pixels.
+func (li *anchorLayoutItem) preferredSize(item LayoutItem) Size {
+	min := li.MinSizeEffectiveForChild(item)
+
+	if is, ok := item.(IdealSizer); ok {
+		return maxSize(min, is.IdealSize())
+	}
+
+	return min
+}
+
+// anchors returns the near and far anchor of item on an axis.
+func (li *anchorLayoutItem) anchors(item LayoutItem, vertical bool) (near, far anchorLayoutTarget) {
+	targets := li.hwnd2Anchors[item.Handle()]
+
+	if vertical {
+		return targets[AnchorTop], targets[AnchorBottom]
+	}
+
+	return targets[AnchorLeft], targets[AnchorRight]
+}
+
+// anchorItemIndex returns the index of the item with handle in items, or -1.
+func anchorItemIndex(items []LayoutItem, handle win.HWND) int {
+	for i, item := range items {
+		if item.Handle() == handle {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// spans calculates the spans of items on an axis for a container of the given
+// extent. Anchors to invisible siblings, or that form a cycle, are ignored.
+func (li *anchorLayoutItem) spans(items []LayoutItem, vertical bool, extent int) []anchorLayoutSpan {
+	margins := MarginsFrom96DPI(li.margins96dpi, li.ctx.dpi)
+
+	marginNear, marginFar := margins.HNear, margins.HFar
+	if vertical {
+		marginNear, marginFar = margins.VNear, margins.VFar
+	}
+
+	spans := make([]anchorLayoutSpan, len(items))
+	done := make([]bool, len(items))
+	visiting := make([]bool, len(items))
+
+	var resolve func(i int) anchorLayoutSpan
+
+	edgePos := func(target anchorLayoutTarget, far bool) (int, bool) {
+		if !target.attached {
+			return 0, false
+		}
+
+		offset := IntFrom96DPI(target.offset96dpi, li.ctx.dpi)
+		if far {
+			offset = -offset
+		}
+
+		if target.sibling == 0 {
+			if far {
+				return extent - marginFar + offset, true
+			}
+
+			return marginNear + offset, true
+		}
+
+		j := anchorItemIndex(items, target.sibling)
+		if j < 0 || visiting[j] {
+			return 0, false
+		}
+
+		span := resolve(j)
+		if target.edge == AnchorRight || target.edge == AnchorBottom {
+			return span.pos + span.size + offset, true
+		}
+
+		return span.pos + offset, true
+	}
+
+	resolve = func(i int) anchorLayoutSpan {
+		if done[i] {
+			return spans[i]
+		}
+
+		visiting[i] = true
+
+		item := items[i]
+
+		min, pref := li.MinSizeEffectiveForChild(item), li.preferredSize(item)
+		minSize, prefSize := min.Width, pref.Width
+		if vertical {
+			minSize, prefSize = min.Height, pref.Height
+		}
+
+		nearTarget, farTarget := li.anchors(item, vertical)
+		near, hasNear := edgePos(nearTarget, false)
+		far, hasFar := edgePos(farTarget, true)
+
+		var span anchorLayoutSpan
+
+		switch {
+		case hasNear && hasFar:
+			span = anchorLayoutSpan{near, maxi(far-near, minSize)}
+
+		case hasNear:
+			span = anchorLayoutSpan{near, prefSize}
+
+		case hasFar:
+			span = anchorLayoutSpan{far - prefSize, prefSize}
+
+		default:
+			span = anchorLayoutSpan{marginNear, prefSize}
+		}
+
+		visiting[i] = false
+		done[i] = true
+		spans[i] = span
+
+		return span
+	}
+
+	for i := range items {
+		resolve(i)
+	}
+
+	return spans
+}
+
+// requiredSize returns the size the container needs so that no item is
+// smaller than sizeOf returns for it, and all gaps between items and their
+// targets keep their offsets.
+func (li *anchorLayoutItem) requiredSize(sizeOf func(item LayoutItem) Size) Size {
+	items := li.itemsToLayout()
+
+	margins := MarginsFrom96DPI(li.margins96dpi, li.ctx.dpi)
+
+	required := func(vertical bool) int {
+		marginNear, marginFar := margins.HNear, margins.HFar
+		if vertical {
+			marginNear, marginFar = margins.VNear, margins.VFar
+		}
+
+		sizes := make([]int, len(items))
+		for i, item := range items {
+			s := sizeOf(item)
+			if vertical {
+				sizes[i] = s.Height
+			} else {
+				sizes[i] = s.Width
+			}
+		}
+
+		// nearExtent and farExtent return the space needed between an item
+		// and the near and far edge of the container, following its anchors.
+		// An edge that isn't attached, or whose anchors form a cycle, only
+		// needs the margin.
+		var nearExtent, farExtent func(i int, visiting map[int]bool) int
+
+		extent := func(i int, far bool, visiting map[int]bool) int {
+			nearTarget, farTarget := li.anchors(items[i], vertical)
+
+			target, margin := nearTarget, marginNear
+			if far {
+				target, margin = farTarget, marginFar
+			}
+
+			if !target.attached {
+				return margin
+			}
+
+			offset := IntFrom96DPI(target.offset96dpi, li.ctx.dpi)
+
+			if target.sibling == 0 {
+				return margin + offset
+			}
+
+			j := anchorItemIndex(items, target.sibling)
+			if j < 0 || visiting[j] {
+				return margin
+			}
+
+			visiting[i] = true
+			defer delete(visiting, i)
+
+			targetFar := target.edge == AnchorRight || target.edge == AnchorBottom
+
+			var e int
+			if far {
+				e = farExtent(j, visiting)
+				if !targetFar {
+					e += sizes[j]
+				}
+			} else {
+				e = nearExtent(j, visiting)
+				if targetFar {
+					e += sizes[j]
+				}
+			}
+
+			return e + offset
+		}
+
+		nearExtent = func(i int, visiting map[int]bool) int {
+			return extent(i, false, visiting)
+		}
+
+		farExtent = func(i int, visiting map[int]bool) int {
+			return extent(i, true, visiting)
+		}
+
+		var total int
+
+		for i := range items {
+			near := nearExtent(i, make(map[int]bool))
+			far := farExtent(i, make(map[int]bool))
+
+			total = maxi(total, near+sizes[i]+far)
+		}
+
+		return total
+	}
+
+	return Size{required(false), required(true)}
+}
@@ -0,0 +1,343 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"time"
+)
+
+// animationFrameInterval is the time between the frames of the frame clock,
+// for about 60 frames per second.
+const animationFrameInterval = 16 * time.Millisecond
+
+// Animation calls an update function with the eased progress of an
+// animation, frame by frame on the UI thread, from when it is started until
+// its duration elapsed. The update function usually interpolates a value and
+// applies it to a widget, see NewFloatAnimation, NewColorAnimation,
+// NewPointAnimation and NewPropertyAnimation.
+//
+// All running animations of a thread are driven by the same frame clock, so
+// the frames of simultaneous animations line up. An Animation is disposed with
+// its owner.
+type Animation struct {
+	owner                Window
+	duration             time.Duration
+	easing               Easing
+	update               func(progress float64)
+	started              time.Time
+	running              bool
+	finishedPublisher    EventPublisher
+	ownerDisposingHandle int
+	disposed             bool
+}
+
+// NewAnimation returns a new Animation that calls update with the progress,
+// from 0 at the start to 1 at the end, mapped through easing. A nil easing
+// means EasingEaseOut.
+//
+// The Animation must be started with Start. It must be created on the UI
+// thread of owner and is disposed with it.
+func NewAnimation(owner Window, duration time.Duration, easing Easing, update func(progress float64)) (*Animation, error) {
+	if owner == nil {
+		return nil, newError("owner required")
+	}
+	if duration < 0 {
+		return nil, newError("duration must not be negative")
+	}
+	if update == nil {
+		return nil, newError("update required")
+	}
+
+	if easing == nil {
+		easing = EasingEaseOut
+	}
+
+	a := &Animation{
+		owner:    owner,
+		duration: duration,
+		easing:   easing,
+		update:   update,
+	}
+
+	a.ownerDisposingHandle = owner.Disposing().Attach(a.Dispose)
+
+	return a, nil
+}
+
+// NewFloatAnimation returns a new Animation that calls apply with values
+// from from to to.
+func NewFloatAnimation(owner Window, from, to float64, duration time.Duration, easing Easing, apply func(value float64)) (*Animation, error) {
+	return NewAnimation(owner, duration, easing, func(progress float64) {
+		apply(interpolateFloat(from, to, progress))
+	})
+}
+
+// NewColorAnimation returns a new Animation that calls apply with colors
+// blended from from to to.
+func NewColorAnimation(owner Window, from, to Color, duration time.Duration, easing Easing, apply func(value Color)) (*Animation, error) {
+	return NewAnimation(owner, duration, easing, func(progress float64) {
+		apply(from.Blend(to, math.Max(0, math.Min(progress, 1))))
+	})
+}
+
+// NewPointAnimation returns a new Animation that calls apply with points
+// moving from from to to.
+func NewPointAnimation(owner Window, from, to Point, duration time.Duration, easing Easing, apply func(value Point)) (*Animation, error) {
+	return NewAnimation(owner, duration, easing, func(progress float64) {
+		apply(interpolatePoint(from, to, progress))
+	})
+}
+
+// NewPropertyAnimation returns a new Animation that sets the property with
+// name of window to values from from to to, which must both be float64, int,
+// Color or Point.
+func NewPropertyAnimation(window Window, name string, from, to interface{}, duration time.Duration, easing Easing) (*Animation, error) {
+	property := window.AsWindowBase().Property(name)
+	if property == nil {
+		return nil, newError("unknown property: " + name)
+	}
+	if property.ReadOnly() {
+		return nil, newError("read-only property: " + name)
+	}
+
+	var interpolate func(progress float64) interface{}
+
+	switch from := from.(type) {
+	case float64:
+		if to, ok := to.(float64); ok {
+			interpolate = func(progress float64) interface{} {
+				return interpolateFloat(from, to, progress)
+			}
+		}
+
+	case int:
+		if to, ok := to.(int); ok {
+			interpolate = func(progress float64) interface{} {
+				return int(math.Round(interpolateFloat(float64(from), float64(to), progress)))
+			}
+		}
+
+	case Color:
+		if to, ok := to.(Color); ok {
+			interpolate = func(progress float64) interface{} {
+				return from.Blend(to, math.Max(0, math.Min(progress, 1)))
+			}
+		}
+
+	case Point:
+		if to, ok := to.(Point); ok {
+			interpolate = func(progress float64) interface{} {
+				return interpolatePoint(from, to, progress)
+			}
+		}
+	}
+
+	if interpolate == nil {
+		return nil, newError("from and to must both be float64, int, Color or Point")
+	}
+
+	return NewAnimation(window, duration, easing, func(progress float64) {
+		property.Set(interpolate(progress))
+	})
+}
+
+func interpolateFloat(from, to, progress float64) float64 {
+	return from + (to-from)*progress
+}
+
+func interpolatePoint(from, to Point, progress float64) Point {
+	return Point{
+		X: int(math.Round(interpolateFloat(float64(from.X), float64(to.X), progress))),
+		Y: int(math.Round(interpolateFloat(float64(from.Y), float64(to.Y), progress))),
+	}
+}
+
+// Duration returns the time the Animation takes from start to end.
+func (a *Animation) Duration() time.Duration {
+	return a.duration
+}
+
+// SetDuration sets the time the Animation takes from start to end. A running
+// Animation keeps its progress, but continues at the speed of the new
+// duration.
+func (a *Animation) SetDuration(duration time.Duration) error {
+	if duration < 0 {
+		return newError("duration must not be negative")
+	}
+
+	if a.running && a.duration > 0 {
+		elapsed := time.Since(a.started)
+		a.started = time.Now().Add(-time.Duration(float64(elapsed) * float64(duration) / float64(a.duration)))
+	}
+
+	a.duration = duration
+
+	return nil
+}
+
+// Easing returns the function that maps the elapsed fraction of the duration
+// to the progress passed to the update function.
+func (a *Animation) Easing() Easing {
+	return a.easing
+}
+
+// SetEasing sets the function that maps the elapsed fraction of the duration
+// to the progress passed to the update function. A nil easing means
+// EasingEaseOut.
+func (a *Animation) SetEasing(easing Easing) {
+	if easing == nil {
+		easing = EasingEaseOut
+	}
+
+	a.easing = easing
+}
+
+// Running returns whether the Animation is started and not finished yet.
+func (a *Animation) Running() bool {
+	return a.running
+}
+
+// Start starts the Animation from the beginning, or starts it over if it is
+// running. The update function is called with the start value right away.
+func (a *Animation) Start() error {
+	if a.disposed {
+		return newError("animation is disposed")
+	}
+
+	a.started = time.Now()
+
+	if !a.running {
+		if err := a.owner.AsWindowBase().group.frameClock().add(a); err != nil {
+			return err
+		}
+
+		a.running = true
+	}
+
+	a.update(a.easing(0))
+
+	return nil
+}
+
+// Stop stops the Animation where it is, without calling the update function
+// again or publishing Finished.
+func (a *Animation) Stop() {
+	if !a.running {
+		return
+	}
+
+	a.running = false
+
+	a.owner.AsWindowBase().group.frameClock().remove(a)
+}
+
+// Finish stops the Animation, calls the update function with the end value
+// and publishes Finished, as if the duration had elapsed.
+func (a *Animation) Finish() {
+	if !a.running {
+		return
+	}
+
+	a.Stop()
+
+	a.update(a.easing(1))
+
+	a.finishedPublisher.Publish()
+}
+
+// Finished returns the event that is published when the Animation reached its
+// end.
+func (a *Animation) Finished() *Event {
+	return a.finishedPublisher.Event()
+}
+
+// step updates the Animation for the frame at now.
+func (a *Animation) step(now time.Time) {
+	if a.duration <= 0 || now.Sub(a.started) >= a.duration {
+		a.Finish()
+		return
+	}
+
+	a.update(a.easing(float64(now.Sub(a.started)) / float64(a.duration)))
+}
+
+// Dispose stops the Animation for good. It is called when the owner is
+// disposed.
+func (a *Animation) Dispose() {
+	if a.disposed {
+		return
+	}
+
+	a.Stop()
+
+	a.disposed = true
+
+	a.owner.Disposing().Detach(a.ownerDisposingHandle)
+}
+
+// IsDisposed returns if Dispose was called.
+func (a *Animation) IsDisposed() bool {
+	return a.disposed
+}
+
+// animationFrameClock steps the running animations of a thread, while there
+// are any.
+type animationFrameClock struct {
+	animations []*Animation
+	timer      *UITimer
+}
+
+// frameClock returns the animationFrameClock of the thread of g.
+func (g *WindowGroup) frameClock() *animationFrameClock {
+	if g.animationFrameClock == nil {
+		g.animationFrameClock = new(animationFrameClock)
+	}
+
+	return g.animationFrameClock
+}
+
+func (fc *animationFrameClock) add(a *Animation) error {
+	if fc.timer == nil {
+		timer, err := NewUITimer(animationFrameInterval, fc.tick)
+		if err != nil {
+			return err
+		}
+
+		fc.timer = timer
+	}
+
+	fc.animations = append(fc.animations, a)
+
+	return nil
+}
+
+func (fc *animationFrameClock) remove(a *Animation) {
+	for i, animation := range fc.animations {
+		if animation == a {
+			fc.animations = append(fc.animations[:i], fc.animations[i+1:]...)
+			break
+		}
+	}
+
+	if len(fc.animations) == 0 && fc.timer != nil {
+		fc.timer.Dispose()
+		fc.timer = nil
+	}
+}
+
+func (fc *animationFrameClock) tick() {
+	now := time.Now()
+
+	// Animations may finish, start or stop others while stepping.
+	for _, a := range append([]*Animation(nil), fc.animations...) {
+		if a.running {
+			a.step(now)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"sort"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// IDs of the themes known to every application.
+const (
+	ThemeSystem = "system"
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+)
+
+// Keys of the appearance preferences in App().Settings().
+const (
+	appearanceThemeKey      = "Appearance/Theme"
+	appearanceFontFamilyKey = "Appearance/FontFamily"
+	appearanceFontScaleKey  = "Appearance/FontScale"
+	appearanceLanguageKey   = "Appearance/Language"
+)
+
+// AppearanceChoice is a theme or language that can be chosen for the
+// Appearance, with the ID stored in the settings and the Name shown to the
+// user.
+type AppearanceChoice struct {
+	ID   string
+	Name string
+}
+
+// Appearance holds the preferences of the user for the look of the
+// application, as edited with a PreferencesPage.
+type Appearance struct {
+	// Theme is the ID of the theme, one of ThemeSystem, ThemeLight,
+	// ThemeDark or one added with RegisterTheme.
+	Theme string
+
+	// FontFamily is the family of the UI font, empty for the default.
+	FontFamily string
+
+	// FontScale scales the size of all fonts of the application, 1 or 0 for
+	// 100%. See Application.SetFontScale.
+	FontScale float64
+
+	// Language is the ID of a language added with RegisterLanguage, empty
+	// for the language the strings are written in.
+	Language string
+}
+
+var (
+	appearance                 = Appearance{Theme: ThemeSystem, FontScale: 1}
+	appearanceChangedPublisher EventPublisher
+	appearanceThemes           = []AppearanceChoice{
+		{ThemeSystem, "System"},
+		{ThemeLight, "Light"},
+		{ThemeDark, "Dark"},
+	}
+	appearanceLanguages    []AppearanceChoice
+	appearanceTranslations = make(map[string]TranslationFunction)
+)
+
+// RegisterTheme adds a theme that can be chosen for the Appearance or with
+// SetAppTheme, in addition to ThemeSystem, ThemeLight and ThemeDark. While it
+// is chosen, the widgets are drawn with the colors of theme.
+func RegisterTheme(id, name string, theme *Theme) {
+	if theme != nil {
+		themePalettes[id] = theme
+	}
+
+	for i, choice := range appearanceThemes {
+		if choice.ID == id {
+			appearanceThemes[i].Name = name
+			return
+		}
+	}
+
+	appearanceThemes = append(appearanceThemes, AppearanceChoice{id, name})
+}
+
+// Themes returns the themes that can be chosen for the Appearance.
+func Themes() []AppearanceChoice {
+	return append([]AppearanceChoice(nil), appearanceThemes...)
+}
+
+// RegisterLanguage adds a language that can be chosen for the Appearance.
+// When it is chosen, translation becomes the TranslationFunc.
+func RegisterLanguage(id, name string, translation TranslationFunction) {
+	appearanceTranslations[id] = translation
+
+	for i, language := range appearanceLanguages {
+		if language.ID == id {
+			appearanceLanguages[i].Name = name
+			return
+		}
+	}
+
+	appearanceLanguages = append(appearanceLanguages, AppearanceChoice{id, name})
+}
+
+// Languages returns the languages that can be chosen for the Appearance.
+func Languages() []AppearanceChoice {
+	return append([]AppearanceChoice(nil), appearanceLanguages...)
+}
+
+// CurrentAppearance returns the Appearance last set with SetAppearance or
+// loaded with LoadAppearance.
+func CurrentAppearance() Appearance {
+	return appearance
+}
+
+// LoadAppearance reads the Appearance from App().Settings() and makes it
+// the current one, as SetAppearance, without saving it again. Call it once,
+// after loading the settings and registering themes and languages, but
+// before creating forms, so they start in the preferred appearance.
+func LoadAppearance() error {
+	settings := App().Settings()
+	if settings == nil {
+		return newError("App().Settings() must not be nil")
+	}
+
+	a := appearance
+
+	if theme, ok := settings.Get(appearanceThemeKey); ok {
+		a.Theme = theme
+	}
+	if family, ok := settings.Get(appearanceFontFamilyKey); ok {
+		a.FontFamily = family
+	}
+	if s, ok := settings.Get(appearanceFontScaleKey); ok {
+		if scale, err := strconv.ParseFloat(s, 64); err == nil {
+			a.FontScale = scale
+		}
+	}
+	if language, ok := settings.Get(appearanceLanguageKey); ok {
+		a.Language = language
+	}
+
+	return applyAppearance(a)
+}
+
+// SetAppearance makes a the current Appearance and stores it in
+// App().Settings(), if there are any.
+//
+// The UI font of all forms is replaced, the fonts are scaled as with
+// Application.SetFontScale, the theme is applied as with SetAppTheme and the
+// TranslationFunc is switched to the chosen language.
+// Strings that were already translated don't change, attach to
+// AppearanceChanged to update them.
+func SetAppearance(a Appearance) error {
+	if err := applyAppearance(a); err != nil {
+		return err
+	}
+
+	settings := App().Settings()
+	if settings == nil {
+		return nil
+	}
+
+	if err := settings.Put(appearanceThemeKey, a.Theme); err != nil {
+		return err
+	}
+	if err := settings.Put(appearanceFontFamilyKey, a.FontFamily); err != nil {
+		return err
+	}
+	if err := settings.Put(appearanceFontScaleKey, strconv.FormatFloat(a.FontScale, 'f', -1, 64)); err != nil {
+		return err
+	}
+
+	return settings.Put(appearanceLanguageKey, a.Language)
+}
+
+// AppearanceChanged returns the event that is published when the current
+// Appearance changed.
+func AppearanceChanged() *Event {
+	return appearanceChangedPublisher.Event()
+}
+
+func applyAppearance(a Appearance) error {
+	if a.FontScale <= 0 {
+		a.FontScale = 1
+	}
+
+	if a == appearance {
+		return nil
+	}
+
+	familyChanged := a.FontFamily != appearance.FontFamily
+	themeChanged := a.Theme != appearance.Theme
+
+	appearance = a
+
+	if themeChanged {
+		updateAppTheme()
+	}
+
+	if translation, ok := appearanceTranslations[a.Language]; ok || a.Language == "" {
+		SetTranslationFunc(translation)
+	}
+
+	setFontScale(a.FontScale)
+
+	if familyChanged && defaultFont != nil {
+		font, err := a.Font()
+		if err != nil {
+			return err
+		}
+
+		forEachForm(func(form Form) {
+			form.SetFont(font)
+		})
+	}
+
+	appearanceChangedPublisher.Publish()
+
+	return nil
+}
+
+// Font returns the UI font of the Appearance, the default font with the
+// FontFamily. Like all fonts, it is drawn scaled by the FontScale.
+func (a Appearance) Font() (*Font, error) {
+	family := a.FontFamily
+	if family == "" {
+		family = defaultFont.Family()
+	}
+
+	return NewFont(family, defaultFont.PointSize(), defaultFont.Style())
+}
+
+// forEachForm calls f for each Form of the current thread.
+func forEachForm(f func(form Form)) {
+	tid := win.GetCurrentThreadId()
+
+	for hwnd := win.GetWindow(win.GetDesktopWindow(), win.GW_CHILD); hwnd != 0; hwnd = win.GetWindow(hwnd, win.GW_HWNDNEXT) {
+		if win.GetWindowThreadProcessId(hwnd, nil) != tid {
+			continue
+		}
+
+		if form, ok := windowFromHandle(hwnd).(Form); ok {
+			f(form)
+		}
+	}
+}
+
+var (
+	enumFontFamiliesExW = syscall.NewLazyDLL("gdi32.dll").NewProc("EnumFontFamiliesExW")
+
+	fontFamiliesCallbackPtr uintptr
+	fontFamilies            map[string]bool
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		fontFamiliesCallbackPtr = syscall.NewCallback(fontFamiliesCallback)
+	})
+}
+
+// FontFamilies returns the sorted names of the font families installed on
+// the system, without those for vertical text.
+func FontFamilies() []string {
+	hdc := win.GetDC(0)
+	defer win.ReleaseDC(0, hdc)
+
+	lf := win.LOGFONT{LfCharSet: win.DEFAULT_CHARSET}
+
+	fontFamilies = make(map[string]bool)
+	defer func() {
+		fontFamilies = nil
+	}()
+
+	enumFontFamiliesExW.Call(uintptr(hdc), uintptr(unsafe.Pointer(&lf)), fontFamiliesCallbackPtr, 0, 0)
+
+	families := make([]string, 0, len(fontFamilies))
+	for family := range fontFamilies {
+		families = append(families, family)
+	}
+
+	sort.Strings(families)
+
+	return families
+}
+
+func fontFamiliesCallback(lf *win.LOGFONT, tm uintptr, fontType uint32, lParam uintptr) uintptr {
+	if family := syscall.UTF16ToString(lf.LfFaceName[:]); family != "" && family[0] != '@' {
+		fontFamilies[family] = true
+	}
+
+	return 1
+}
@@ -42,6 +42,12 @@ type Application struct {
 	exiting            bool
 	exitCode           int
 	panickingPublisher ErrorEventPublisher
+
+	watchdogThreshold     time.Duration
+	unresponsivePublisher StringEventPublisher
+
+	wheelRouting   WheelRouting
+	wheelValueMode WheelValueMode
 }
 
 var appSingleton *Application = new(Application)
@@ -106,6 +112,89 @@ func (app *Application) Panicking() *ErrorEvent {
 	return app.panickingPublisher.Event()
 }
 
+// WatchdogThreshold returns how long a message loop may be blocked, before
+// Unresponsive is published, or 0 if message loops aren't watched.
+func (app *Application) WatchdogThreshold() time.Duration {
+	app.mutex.RLock()
+	defer app.mutex.RUnlock()
+	return app.watchdogThreshold
+}
+
+// SetWatchdogThreshold makes message loops started afterwards be watched by
+// a HangDetector with threshold. Each time a loop is blocked for longer, the
+// stack of its goroutine is logged and Unresponsive is published. Pass 0 to
+// stop watching new message loops.
+func (app *Application) SetWatchdogThreshold(threshold time.Duration) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.watchdogThreshold = threshold
+}
+
+// Unresponsive returns the event that is published with the stack of the
+// goroutine running a blocked message loop, when watched as configured with
+// SetWatchdogThreshold. Like HangDetector.HangDetected, it is published on
+// the goroutine of the watchdog.
+func (app *Application) Unresponsive() *StringEvent {
+	app.mutex.RLock()
+	defer app.mutex.RUnlock()
+	return app.unresponsivePublisher.Event()
+}
+
+// WheelRouting returns which widget receives mouse wheel messages, for forms
+// that use WheelRoutingDefault.
+func (app *Application) WheelRouting() WheelRouting {
+	app.mutex.RLock()
+	defer app.mutex.RUnlock()
+	return app.wheelRouting
+}
+
+// SetWheelRouting sets which widget receives mouse wheel messages, for forms
+// that use WheelRoutingDefault. WheelRoutingDefault leaves it to Windows.
+func (app *Application) SetWheelRouting(routing WheelRouting) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.wheelRouting = routing
+}
+
+// WheelValueMode returns whether the mouse wheel changes the values of
+// widgets like NumberEdit, Slider or Knob that use WheelValueDefault.
+func (app *Application) WheelValueMode() WheelValueMode {
+	app.mutex.RLock()
+	defer app.mutex.RUnlock()
+	return app.wheelValueMode
+}
+
+// SetWheelValueMode sets whether the mouse wheel changes the values of
+// widgets like NumberEdit, Slider or Knob that use WheelValueDefault.
+// WheelValueDefault means WheelValueWhenFocused, while WheelValueAlways must
+// be set explicitly.
+func (app *Application) SetWheelValueMode(mode WheelValueMode) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.wheelValueMode = mode
+}
+
+// FontScale returns the factor the size of all fonts is multiplied with.
+func (app *Application) FontScale() float64 {
+	return fontScale
+}
+
+// SetFontScale multiplies the size of all fonts with scale, e.g. 1.25 to
+// enlarge text without changing the display scaling of Windows. It applies to
+// the default font as well as to fonts set explicitly, including those of
+// declarative widgets, and the windows of the calling thread are updated and
+// laid out again right away.
+//
+// It sets the FontScale of the current Appearance and publishes
+// AppearanceChanged, but unlike SetAppearance doesn't store it in
+// App().Settings(). It must be called on the UI thread.
+func (app *Application) SetFontScale(scale float64) error {
+	a := CurrentAppearance()
+	a.FontScale = scale
+
+	return applyAppearance(a)
+}
+
 // ActiveForm returns the currently active form for the caller's thread.
 // It returns nil if no form is active or the caller's thread does not
 // have any windows associated with it. It should be called from within
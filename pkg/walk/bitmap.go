@@ -594,6 +594,43 @@ func hBitmapFromWindow(window Window) (win.HBITMAP, error) {
 	return hBmp, nil
 }
 
+// hBitmapFromWindowClient creates a new win.HBITMAP holding the client area of window, including
+// its children, but excluding owned windows.
+func hBitmapFromWindowClient(window Window) (win.HBITMAP, error) {
+	hdcMem := win.CreateCompatibleDC(0)
+	if hdcMem == 0 {
+		return 0, newError("CreateCompatibleDC failed")
+	}
+	defer win.DeleteDC(hdcMem)
+
+	var r win.RECT
+	if !win.GetClientRect(window.Handle(), &r) {
+		return 0, newError("GetClientRect failed")
+	}
+
+	hdc := win.GetDC(window.Handle())
+	width, height := r.Right-r.Left, r.Bottom-r.Top
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	hBmp := win.CreateCompatibleBitmap(hdc, width, height)
+	win.ReleaseDC(window.Handle(), hdc)
+	if hBmp == 0 {
+		return 0, newError("CreateCompatibleBitmap failed")
+	}
+
+	hOld := win.SelectObject(hdcMem, win.HGDIOBJ(hBmp))
+	flags := win.PRF_CHILDREN | win.PRF_CLIENT | win.PRF_ERASEBKGND
+	window.SendMessage(win.WM_PRINT, uintptr(hdcMem), uintptr(flags))
+
+	win.SelectObject(hdcMem, hOld)
+
+	return hBmp, nil
+}
+
 // hBitmapFromIcon creates a new win.HBITMAP with given size in native pixels and DPI, and paints
 // the icon on it stretched.
 func hBitmapFromIcon(icon *Icon, size Size, dpi int) (win.HBITMAP, error) {
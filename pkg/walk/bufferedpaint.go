@@ -0,0 +1,79 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+// paintBuffered handles WM_PAINT for hwnd by calling paint to draw the whole
+// client area, cb in native pixels, on a bitmap for dpi, which is then copied
+// to the window at once, so it doesn't flicker. The bitmap is mirrored like
+// the window, so paint draws in logical coordinates in right-to-left layouts
+// too.
+//
+// It returns whether hwnd has been painted; if not, WM_PAINT should be passed
+// on.
+func paintBuffered(hwnd win.HWND, dpi int, paint func(canvas *Canvas, cb Rectangle) error) bool {
+	return paintBufferedWithLayout(hwnd, dpi, true, paint)
+}
+
+// paintBufferedUnmirrored is like paintBuffered, but copies the bitmap to
+// the window unmirrored in right-to-left layouts, e.g. for content that must
+// not be flipped, like a QR code.
+func paintBufferedUnmirrored(hwnd win.HWND, dpi int, paint func(canvas *Canvas, cb Rectangle) error) bool {
+	return paintBufferedWithLayout(hwnd, dpi, false, paint)
+}
+
+func paintBufferedWithLayout(hwnd win.HWND, dpi int, mirrored bool, paint func(canvas *Canvas, cb Rectangle) error) bool {
+	var ps win.PAINTSTRUCT
+
+	hdc := win.BeginPaint(hwnd, &ps)
+	if hdc == 0 {
+		return false
+	}
+	defer win.EndPaint(hwnd, &ps)
+
+	var rc win.RECT
+	if !win.GetClientRect(hwnd, &rc) {
+		return false
+	}
+
+	cb := rectangleFromRECT(rc)
+	if cb.Width <= 0 || cb.Height <= 0 {
+		return true
+	}
+
+	bitmap, err := NewBitmapForDPI(cb.Size(), dpi)
+	if err != nil {
+		return false
+	}
+	defer bitmap.Dispose()
+
+	canvas, err := NewCanvasFromImage(bitmap)
+	if err != nil {
+		return false
+	}
+	defer canvas.Dispose()
+
+	if mirrored {
+		canvas.adoptLayout(hdc)
+	}
+
+	if err := paint(canvas, cb); err != nil {
+		return false
+	}
+
+	if !mirrored {
+		if layout := dcLayout(hdc); layout&layoutRTL != 0 {
+			setLayout.Call(uintptr(hdc), uintptr(layout|layoutBitmapOrientationPreserved))
+		}
+	}
+
+	return win.BitBlt(hdc, 0, 0, int32(cb.Width), int32(cb.Height), canvas.hdc, 0, 0, win.SRCCOPY)
+}
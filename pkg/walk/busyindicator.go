@@ -0,0 +1,210 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+
+	"github.com/miu200521358/win"
+)
+
+const busyIndicatorWindowClass = `\o/ Walk_BusyIndicator_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(busyIndicatorWindowClass)
+	})
+}
+
+const (
+	busySpinnerDots     = 12
+	busySpinnerInterval = 80 // milliseconds per step
+	busySpinnerTimerId  = 1
+)
+
+// BusyIndicator is a spinner that shows indeterminate activity.
+//
+// The spinner only animates while it is running and visible.
+type BusyIndicator struct {
+	WidgetBase
+	running                 bool
+	timerActive             bool
+	phase                   int
+	runningChangedPublisher EventPublisher
+}
+
+// NewBusyIndicator creates and initializes a new, running BusyIndicator.
+func NewBusyIndicator(parent Container) (*BusyIndicator, error) {
+	bi := new(BusyIndicator)
+
+	if err := InitWidget(
+		bi,
+		parent,
+		busyIndicatorWindowClass,
+		win.WS_VISIBLE,
+		0); err != nil {
+		return nil, err
+	}
+
+	bi.MustRegisterProperty("Running", NewBoolProperty(
+		func() bool {
+			return bi.Running()
+		},
+		func(b bool) error {
+			bi.SetRunning(b)
+			return nil
+		},
+		bi.runningChangedPublisher.Event()))
+
+	bi.SetRunning(true)
+
+	return bi, nil
+}
+
+func (bi *BusyIndicator) Dispose() {
+	bi.updateTimer(false)
+
+	bi.WidgetBase.Dispose()
+}
+
+// Running returns whether the BusyIndicator is animating.
+func (bi *BusyIndicator) Running() bool {
+	return bi.running
+}
+
+// SetRunning sets whether the BusyIndicator is animating. A BusyIndicator
+// that is not running paints nothing.
+func (bi *BusyIndicator) SetRunning(running bool) {
+	if running == bi.running {
+		return
+	}
+
+	bi.running = running
+
+	bi.updateTimer(running && bi.Visible())
+	bi.Invalidate()
+
+	bi.runningChangedPublisher.Publish()
+}
+
+// RunningChanged returns the event that is published when the BusyIndicator
+// was started or stopped.
+func (bi *BusyIndicator) RunningChanged() *Event {
+	return bi.runningChangedPublisher.Event()
+}
+
+func (bi *BusyIndicator) updateTimer(active bool) {
+	if active == bi.timerActive || bi.hWnd == 0 {
+		return
+	}
+
+	if active {
+		if 0 == win.SetTimer(bi.hWnd, busySpinnerTimerId, busySpinnerInterval, 0) {
+			lastError("SetTimer")
+			return
+		}
+	} else {
+		win.KillTimer(bi.hWnd, busySpinnerTimerId)
+	}
+
+	bi.timerActive = active
+}
+
+func (bi *BusyIndicator) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, bi.DPI(), bi.paint) {
+			return 0
+		}
+
+	case win.WM_TIMER:
+		if wParam == busySpinnerTimerId {
+			bi.phase = (bi.phase + 1) % busySpinnerDots
+			bi.Invalidate()
+			return 0
+		}
+
+	case win.WM_SHOWWINDOW:
+		bi.updateTimer(bi.running && wParam != 0)
+	}
+
+	return bi.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (bi *BusyIndicator) paint(canvas *Canvas, bounds Rectangle) error {
+	bg, _ := bi.backgroundEffective()
+	if bg == nil {
+		bg = sysColorBtnFaceBrush
+	}
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	if !bi.running {
+		return nil
+	}
+
+	bgColor := Color(win.GetSysColor(win.COLOR_BTNFACE))
+	if scb, ok := bg.(*SolidColorBrush); ok {
+		bgColor = scb.Color()
+	}
+
+	radius := mini(bounds.Width, bounds.Height) / 2
+
+	return drawBusySpinner(canvas, Point{bounds.Width / 2, bounds.Height / 2}, radius, bi.phase, Color(win.GetSysColor(win.COLOR_HIGHLIGHT)), bgColor)
+}
+
+func (bi *BusyIndicator) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &dialLayoutItem{
+		idealSize: SizeFrom96DPI(Size{24, 24}, ctx.dpi),
+		minSize:   SizeFrom96DPI(Size{16, 16}, ctx.dpi),
+	}
+}
+
+// drawBusySpinner draws a ring of dots around center, fading from fg at the
+// dot of phase to bg behind it.
+func drawBusySpinner(canvas *Canvas, center Point, radius, phase int, fg, bg Color) error {
+	dotRadius := maxi(1, radius/5)
+	ring := float64(radius - dotRadius)
+	if ring <= 0 {
+		return nil
+	}
+
+	blend := func(a, b byte, t float64) byte {
+		return byte(math.Round(float64(a)*t + float64(b)*(1-t)))
+	}
+
+	for i := 0; i < busySpinnerDots; i++ {
+		// The dot of phase is the head, the others trail behind it.
+		age := (phase - i + busySpinnerDots) % busySpinnerDots
+		t := 1 - float64(age)/busySpinnerDots
+
+		color := RGB(blend(fg.R(), bg.R(), t), blend(fg.G(), bg.G(), t), blend(fg.B(), bg.B(), t))
+
+		brush, err := NewSolidColorBrush(color)
+		if err != nil {
+			return err
+		}
+
+		angle := 2 * math.Pi * float64(i) / busySpinnerDots
+		x := center.X + int(math.Round(math.Sin(angle)*ring))
+		y := center.Y - int(math.Round(math.Cos(angle)*ring))
+
+		err = canvas.FillEllipsePixels(brush, Rectangle{x - dotRadius, y - dotRadius, 2 * dotRadius, 2 * dotRadius})
+		brush.Dispose()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
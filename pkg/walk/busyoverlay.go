@@ -0,0 +1,292 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+const busyOverlayWindowClass = `\o/ Walk_BusyOverlay_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(busyOverlayWindowClass)
+	})
+}
+
+// BusyOverlay dims a Container and blocks mouse and keyboard input to it,
+// while showing a spinner and a message. It is created by Busy.
+type BusyOverlay struct {
+	WindowBase
+	container         Container
+	snapshot          *Bitmap
+	message           string
+	phase             int
+	prevFocus         win.HWND
+	clippedSiblings   []win.HWND
+	sizeChangedHandle int
+}
+
+// Busy covers container with a BusyOverlay showing message, until Close is
+// called on the returned overlay.
+//
+// The overlay shows a dimmed snapshot of container taken when Busy is called.
+// Use Run to close it, once work running in the background completed.
+func Busy(container Container, message string) (*BusyOverlay, error) {
+	bo := &BusyOverlay{container: container, message: message}
+
+	// Take the snapshot before the overlay exists, so it doesn't capture
+	// itself.
+	if hBmp, err := hBitmapFromWindowClient(container); err == nil {
+		bo.snapshot, _ = newBitmapFromHBITMAP(hBmp, container.DPI())
+	}
+
+	if err := InitWindow(
+		bo,
+		container,
+		busyOverlayWindowClass,
+		win.WS_CHILD|win.WS_CLIPSIBLINGS,
+		0); err != nil {
+		if bo.snapshot != nil {
+			bo.snapshot.Dispose()
+		}
+		return nil, err
+	}
+
+	bo.SetFont(container.Font())
+
+	bo.clippedSiblings = clipSiblings(bo.hWnd)
+	bo.fitToContainer()
+
+	bo.sizeChangedHandle = container.SizeChanged().Attach(func() {
+		// The snapshot no longer matches, so dim without it.
+		bo.disposeSnapshot()
+		bo.fitToContainer()
+	})
+
+	if 0 == win.SetTimer(bo.hWnd, busySpinnerTimerId, busySpinnerInterval, 0) {
+		lastError("SetTimer")
+	}
+
+	bo.prevFocus = win.GetFocus()
+	win.SetFocus(bo.hWnd)
+
+	return bo, nil
+}
+
+// Message returns the message shown by the BusyOverlay.
+func (bo *BusyOverlay) Message() string {
+	return bo.message
+}
+
+// SetMessage sets the message shown by the BusyOverlay.
+//
+// Like all methods of the overlay, it must be called from the UI goroutine,
+// use Synchronize when reporting progress from background work.
+func (bo *BusyOverlay) SetMessage(message string) {
+	bo.message = message
+	bo.Invalidate()
+}
+
+// Run calls work in a new goroutine. When it returns, the BusyOverlay is
+// closed and done, if not nil, is called with the result of work, both on the
+// UI goroutine by way of Synchronize.
+func (bo *BusyOverlay) Run(work func() error, done func(err error)) {
+	container := bo.container
+
+	go func() {
+		err := work()
+
+		container.Synchronize(func() {
+			bo.Close()
+
+			if done != nil {
+				done(err)
+			}
+		})
+	}()
+}
+
+// Close removes the BusyOverlay and returns the keyboard focus to where it
+// was before. Calling Close more than once has no effect.
+func (bo *BusyOverlay) Close() {
+	if bo.hWnd == 0 {
+		return
+	}
+
+	win.KillTimer(bo.hWnd, busySpinnerTimerId)
+
+	bo.container.SizeChanged().Detach(bo.sizeChangedHandle)
+
+	focused := win.GetFocus() == bo.hWnd
+
+	unclipSiblings(bo.clippedSiblings)
+	bo.clippedSiblings = nil
+	bo.disposeSnapshot()
+
+	bo.Dispose()
+
+	if focused && bo.prevFocus != 0 {
+		win.SetFocus(bo.prevFocus)
+	}
+}
+
+func (bo *BusyOverlay) disposeSnapshot() {
+	if bo.snapshot != nil {
+		bo.snapshot.Dispose()
+		bo.snapshot = nil
+	}
+}
+
+// clipSiblings makes the other children of the parent of hwnd clip it, so
+// they don't paint over it while it is shown. It returns the siblings whose
+// style was changed, for unclipSiblings.
+func clipSiblings(hwnd win.HWND) []win.HWND {
+	var clipped []win.HWND
+
+	for sibling := win.GetWindow(win.GetParent(hwnd), win.GW_CHILD); sibling != 0; sibling = win.GetWindow(sibling, win.GW_HWNDNEXT) {
+		if sibling == hwnd {
+			continue
+		}
+
+		style := uint32(win.GetWindowLong(sibling, win.GWL_STYLE))
+		if style&win.WS_CLIPSIBLINGS != 0 {
+			continue
+		}
+
+		win.SetWindowLong(sibling, win.GWL_STYLE, int32(style|win.WS_CLIPSIBLINGS))
+		clipped = append(clipped, sibling)
+	}
+
+	return clipped
+}
+
+func unclipSiblings(clipped []win.HWND) {
+	for _, hwnd := range clipped {
+		style := uint32(win.GetWindowLong(hwnd, win.GWL_STYLE))
+		win.SetWindowLong(hwnd, win.GWL_STYLE, int32(style&^win.WS_CLIPSIBLINGS))
+	}
+}
+
+func (bo *BusyOverlay) fitToContainer() {
+	var r win.RECT
+	if !win.GetClientRect(bo.container.Handle(), &r) {
+		return
+	}
+
+	win.SetWindowPos(
+		bo.hWnd,
+		win.HWND_TOP,
+		0,
+		0,
+		r.Right-r.Left,
+		r.Bottom-r.Top,
+		win.SWP_SHOWWINDOW|win.SWP_NOACTIVATE)
+
+	bo.Invalidate()
+}
+
+func (bo *BusyOverlay) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, bo.DPI(), bo.paint) {
+			return 0
+		}
+
+	case win.WM_TIMER:
+		if wParam == busySpinnerTimerId {
+			bo.phase = (bo.phase + 1) % busySpinnerDots
+			bo.Invalidate()
+			return 0
+		}
+
+	case win.WM_SETCURSOR:
+		win.SetCursor(CursorWait().handle())
+		return 1
+
+	case win.WM_GETDLGCODE:
+		// Swallow Tab and friends, so the focus can't move to the covered
+		// widgets.
+		return win.DLGC_WANTALLKEYS
+
+	case win.WM_KEYDOWN, win.WM_KEYUP, win.WM_CHAR, win.WM_MOUSEWHEEL,
+		win.WM_LBUTTONDOWN, win.WM_LBUTTONUP, win.WM_LBUTTONDBLCLK,
+		win.WM_RBUTTONDOWN, win.WM_RBUTTONUP, win.WM_MBUTTONDOWN, win.WM_MBUTTONUP:
+		return 0
+	}
+
+	return bo.WindowBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (bo *BusyOverlay) paint(canvas *Canvas, bounds Rectangle) error {
+	dimBrush, err := NewSolidColorBrush(RGB(0, 0, 0))
+	if err != nil {
+		return err
+	}
+	defer dimBrush.Dispose()
+
+	if err := canvas.FillRectanglePixels(dimBrush, bounds); err != nil {
+		return err
+	}
+
+	if bo.snapshot != nil {
+		if err := canvas.DrawBitmapWithOpacityPixels(bo.snapshot, Rectangle{0, 0, bo.snapshot.size.Width, bo.snapshot.size.Height}, 160); err != nil {
+			return err
+		}
+	}
+
+	dpi := bo.DPI()
+	font := bo.Font()
+
+	spinner := IntFrom96DPI(32, dpi)
+	padding := IntFrom96DPI(12, dpi)
+
+	textBounds, _, err := canvas.MeasureTextPixels(bo.message, font, Rectangle{Width: bounds.Width / 2, Height: bounds.Height}, TextWordbreak)
+	if err != nil {
+		return err
+	}
+
+	panel := Rectangle{Width: spinner + 2*padding, Height: spinner + 2*padding}
+	if bo.message != "" {
+		panel.Width += textBounds.Width + padding
+		panel.Height = maxi(panel.Height, textBounds.Height+2*padding)
+	}
+	panel.X = (bounds.Width - panel.Width) / 2
+	panel.Y = (bounds.Height - panel.Height) / 2
+
+	panelColor := Color(win.GetSysColor(win.COLOR_WINDOW))
+
+	panelBrush, err := NewSolidColorBrush(panelColor)
+	if err != nil {
+		return err
+	}
+	defer panelBrush.Dispose()
+
+	corner := SizeFrom96DPI(Size{8, 8}, dpi)
+
+	if err := canvas.FillRoundedRectanglePixels(panelBrush, panel, corner); err != nil {
+		return err
+	}
+
+	center := Point{panel.X + padding + spinner/2, panel.Y + panel.Height/2}
+	if err := drawBusySpinner(canvas, center, spinner/2, bo.phase, Color(win.GetSysColor(win.COLOR_HIGHLIGHT)), panelColor); err != nil {
+		return err
+	}
+
+	if bo.message == "" {
+		return nil
+	}
+
+	textBounds.X = panel.X + 2*padding + spinner
+	textBounds.Y = panel.Y + (panel.Height-textBounds.Height)/2
+
+	return canvas.DrawTextPixels(bo.message, font, Color(win.GetSysColor(win.COLOR_WINDOWTEXT)), textBounds, TextWordbreak)
+}
@@ -0,0 +1,405 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// Month calendar control styles, messages and notifications.
+const (
+	mcsDayState    = 0x0001
+	mcsMultiSelect = 0x0002
+
+	mcmFirst          = 0x1000
+	mcmGetCurSel      = mcmFirst + 1
+	mcmSetCurSel      = mcmFirst + 2
+	mcmGetMaxSelCount = mcmFirst + 3
+	mcmSetMaxSelCount = mcmFirst + 4
+	mcmGetSelRange    = mcmFirst + 5
+	mcmSetSelRange    = mcmFirst + 6
+	mcmGetMonthRange  = mcmFirst + 7
+	mcmSetDayState    = mcmFirst + 8
+	mcmGetMinReqRect  = mcmFirst + 9
+	mcmGetRange       = mcmFirst + 17
+	mcmSetRange       = mcmFirst + 18
+
+	gmrDayState = 1
+
+	mcnSelChange   = ^uint32(748) // MCN_FIRST - 3
+	mcnGetDayState = ^uint32(746) // MCN_FIRST - 1
+)
+
+type nmDayState struct {
+	Hdr         win.NMHDR
+	Start       win.SYSTEMTIME
+	CDayState   int32
+	PrgDayState *uint32
+}
+
+// CalendarView displays a month calendar grid to pick a date, or with
+// multi-selection, a contiguous range of dates from.
+//
+// Dates for which the BoldDateFunc returns true are displayed in bold, e.g.
+// to mark days that have appointments.
+type CalendarView struct {
+	WidgetBase
+	boldDateFunc              func(date time.Time) bool
+	selStart                  time.Time
+	selEnd                    time.Time
+	dateChangedPublisher      EventPublisher
+	selectionChangedPublisher CalendarViewSelectionEventPublisher
+}
+
+func newCalendarView(parent Container, style uint32) (*CalendarView, error) {
+	cv := new(CalendarView)
+
+	if err := InitWidget(
+		cv,
+		parent,
+		"SysMonthCal32",
+		win.WS_TABSTOP|win.WS_VISIBLE|mcsDayState|style,
+		0); err != nil {
+		return nil, err
+	}
+
+	cv.selStart, cv.selEnd = cv.selectionRange()
+
+	cv.GraphicsEffects().Add(InteractionEffect)
+	cv.GraphicsEffects().Add(FocusEffect)
+
+	cv.MustRegisterProperty("Date", NewProperty(
+		func() interface{} {
+			return cv.Date()
+		},
+		func(v interface{}) error {
+			return cv.SetDate(assertTimeOr(v, time.Time{}))
+		},
+		cv.dateChangedPublisher.Event()))
+
+	return cv, nil
+}
+
+// NewCalendarView creates and initializes a new CalendarView that allows to
+// select a single date.
+func NewCalendarView(parent Container) (*CalendarView, error) {
+	return newCalendarView(parent, 0)
+}
+
+// NewCalendarViewWithMultiSelection creates and initializes a new
+// CalendarView that allows to select a range of dates.
+func NewCalendarViewWithMultiSelection(parent Container) (*CalendarView, error) {
+	return newCalendarView(parent, mcsMultiSelect)
+}
+
+func (*CalendarView) systemTimeToTime(st *win.SYSTEMTIME) time.Time {
+	return time.Date(int(st.WYear), time.Month(st.WMonth), int(st.WDay), 0, 0, 0, 0, time.Local)
+}
+
+func (*CalendarView) timeToSystemTime(t time.Time) win.SYSTEMTIME {
+	return win.SYSTEMTIME{
+		WYear:      uint16(t.Year()),
+		WMonth:     uint16(t.Month()),
+		WDayOfWeek: uint16(t.Weekday()),
+		WDay:       uint16(t.Day()),
+	}
+}
+
+// MultiSelection returns whether a range of dates can be selected.
+func (cv *CalendarView) MultiSelection() bool {
+	return cv.hasStyleBits(mcsMultiSelect)
+}
+
+func (cv *CalendarView) selectionRange() (start, end time.Time) {
+	var st [2]win.SYSTEMTIME
+
+	if cv.MultiSelection() {
+		if 0 == cv.SendMessage(mcmGetSelRange, 0, uintptr(unsafe.Pointer(&st[0]))) {
+			return
+		}
+	} else {
+		if 0 == cv.SendMessage(mcmGetCurSel, 0, uintptr(unsafe.Pointer(&st[0]))) {
+			return
+		}
+		st[1] = st[0]
+	}
+
+	return cv.systemTimeToTime(&st[0]), cv.systemTimeToTime(&st[1])
+}
+
+// Date returns the first selected date.
+func (cv *CalendarView) Date() time.Time {
+	start, _ := cv.selectionRange()
+
+	return start
+}
+
+// SetDate selects date only.
+func (cv *CalendarView) SetDate(date time.Time) error {
+	return cv.SetSelection(date, date)
+}
+
+// DateChanged returns the event that is published when the first selected
+// date changed.
+func (cv *CalendarView) DateChanged() *Event {
+	return cv.dateChangedPublisher.Event()
+}
+
+// Selection returns all selected dates, in ascending order.
+func (cv *CalendarView) Selection() []time.Time {
+	start, end := cv.selectionRange()
+	if start.IsZero() {
+		return nil
+	}
+
+	var dates []time.Time
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+
+	return dates
+}
+
+// SetSelection selects the dates from start through end. Unless the
+// CalendarView allows multi-selection, start and end must be the same date.
+func (cv *CalendarView) SetSelection(start, end time.Time) error {
+	stStart, stEnd := cv.timeToSystemTime(start), cv.timeToSystemTime(end)
+	start, end = cv.systemTimeToTime(&stStart), cv.systemTimeToTime(&stEnd)
+
+	if end.Before(start) {
+		return newError("invalid selection")
+	}
+
+	if cv.MultiSelection() {
+		st := [2]win.SYSTEMTIME{stStart, stEnd}
+
+		if 0 == cv.SendMessage(mcmSetSelRange, 0, uintptr(unsafe.Pointer(&st[0]))) {
+			return newError("SendMessage(MCM_SETSELRANGE)")
+		}
+	} else {
+		if !start.Equal(end) {
+			return newError("multi-selection not enabled")
+		}
+
+		if 0 == cv.SendMessage(mcmSetCurSel, 0, uintptr(unsafe.Pointer(&stStart))) {
+			return newError("SendMessage(MCM_SETCURSEL)")
+		}
+	}
+
+	cv.updateSelection()
+
+	return nil
+}
+
+// MaxSelectionCount returns the maximum number of days that can be selected.
+func (cv *CalendarView) MaxSelectionCount() int {
+	return int(cv.SendMessage(mcmGetMaxSelCount, 0, 0))
+}
+
+// SetMaxSelectionCount sets the maximum number of days that can be selected.
+// This requires multi-selection.
+func (cv *CalendarView) SetMaxSelectionCount(count int) error {
+	if !cv.MultiSelection() {
+		return newError("multi-selection not enabled")
+	}
+
+	if count < 1 {
+		return newError("count must be positive")
+	}
+
+	if 0 == cv.SendMessage(mcmSetMaxSelCount, uintptr(count), 0) {
+		return newError("SendMessage(MCM_SETMAXSELCOUNT)")
+	}
+
+	return nil
+}
+
+// SelectionChanged returns the event that is published when the selected
+// dates changed.
+func (cv *CalendarView) SelectionChanged() *CalendarViewSelectionEvent {
+	return cv.selectionChangedPublisher.Event()
+}
+
+// updateSelection publishes the changed events, if the selection differs
+// from how it was last seen.
+func (cv *CalendarView) updateSelection() {
+	start, end := cv.selectionRange()
+	if start.Equal(cv.selStart) && end.Equal(cv.selEnd) {
+		return
+	}
+
+	dateChanged := !start.Equal(cv.selStart)
+
+	cv.selStart, cv.selEnd = start, end
+
+	if dateChanged {
+		cv.dateChangedPublisher.Publish()
+	}
+
+	cv.selectionChangedPublisher.Publish(cv.Selection())
+}
+
+// Range returns the earliest and latest date that can be selected. A zero
+// time means there is no such limit.
+func (cv *CalendarView) Range() (min, max time.Time) {
+	var st [2]win.SYSTEMTIME
+
+	ret := cv.SendMessage(mcmGetRange, 0, uintptr(unsafe.Pointer(&st[0])))
+
+	if ret&win.GDTR_MIN > 0 {
+		min = cv.systemTimeToTime(&st[0])
+	}
+
+	if ret&win.GDTR_MAX > 0 {
+		max = cv.systemTimeToTime(&st[1])
+	}
+
+	return
+}
+
+// SetRange sets the earliest and latest date that can be selected. A zero
+// time means there is no such limit.
+func (cv *CalendarView) SetRange(min, max time.Time) error {
+	if !min.IsZero() && !max.IsZero() {
+		if min.Year() > max.Year() ||
+			min.Year() == max.Year() && min.Month() > max.Month() ||
+			min.Year() == max.Year() && min.Month() == max.Month() && min.Day() > max.Day() {
+			return newError("invalid range")
+		}
+	}
+
+	var st [2]win.SYSTEMTIME
+	var wParam uintptr
+
+	if !min.IsZero() {
+		wParam |= win.GDTR_MIN
+		st[0] = cv.timeToSystemTime(min)
+	}
+
+	if !max.IsZero() {
+		wParam |= win.GDTR_MAX
+		st[1] = cv.timeToSystemTime(max)
+	}
+
+	if 0 == cv.SendMessage(mcmSetRange, wParam, uintptr(unsafe.Pointer(&st[0]))) {
+		return newError("SendMessage(MCM_SETRANGE)")
+	}
+
+	// The control clamps the selection into the range, without notifying.
+	cv.updateSelection()
+
+	return nil
+}
+
+// BoldDateFunc returns the function that decides which dates are displayed
+// in bold.
+func (cv *CalendarView) BoldDateFunc() func(date time.Time) bool {
+	return cv.boldDateFunc
+}
+
+// SetBoldDateFunc sets the function that decides which dates are displayed
+// in bold. It is called for each date of the months being displayed, when
+// they come into view.
+func (cv *CalendarView) SetBoldDateFunc(f func(date time.Time) bool) {
+	cv.boldDateFunc = f
+
+	cv.RefreshBoldDates()
+}
+
+// RefreshBoldDates queries the BoldDateFunc again for the displayed months,
+// e.g. after the data it is based on changed.
+func (cv *CalendarView) RefreshBoldDates() {
+	var st [2]win.SYSTEMTIME
+
+	count := int(cv.SendMessage(mcmGetMonthRange, gmrDayState, uintptr(unsafe.Pointer(&st[0]))))
+	if count <= 0 {
+		return
+	}
+
+	states := cv.dayStates(&st[0], count)
+
+	cv.SendMessage(mcmSetDayState, uintptr(count), uintptr(unsafe.Pointer(&states[0])))
+}
+
+// dayStates returns a bit mask per month, starting with the month of start,
+// with bit n - 1 set for each day n to be displayed in bold.
+func (cv *CalendarView) dayStates(start *win.SYSTEMTIME, count int) []uint32 {
+	states := make([]uint32, count)
+
+	if cv.boldDateFunc == nil {
+		return states
+	}
+
+	first := time.Date(int(start.WYear), time.Month(start.WMonth), 1, 0, 0, 0, 0, time.Local)
+
+	for i := range states {
+		month := first.AddDate(0, i, 0)
+
+		for d := month; d.Month() == month.Month(); d = d.AddDate(0, 0, 1) {
+			if cv.boldDateFunc(d) {
+				states[i] |= 1 << uint(d.Day()-1)
+			}
+		}
+	}
+
+	return states
+}
+
+func (cv *CalendarView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_NOTIFY:
+		switch uint32(((*win.NMHDR)(unsafe.Pointer(lParam))).Code) {
+		case mcnSelChange:
+			// Also sent when scrolling to another month moves the selection.
+			cv.updateSelection()
+
+		case mcnGetDayState:
+			nmds := (*nmDayState)(unsafe.Pointer(lParam))
+
+			if nmds.CDayState > 0 && nmds.PrgDayState != nil {
+				states := cv.dayStates(&nmds.Start, int(nmds.CDayState))
+				buf := (*[1 << 10]uint32)(unsafe.Pointer(nmds.PrgDayState))[:len(states):len(states)]
+
+				copy(buf, states)
+			}
+
+			return 0
+		}
+	}
+
+	return cv.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (cv *CalendarView) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	var r win.RECT
+	cv.SendMessage(mcmGetMinReqRect, 0, uintptr(unsafe.Pointer(&r)))
+
+	return &calendarViewLayoutItem{
+		idealSize: Size{int(r.Right - r.Left), int(r.Bottom - r.Top)},
+	}
+}
+
+type calendarViewLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+}
+
+func (*calendarViewLayoutItem) LayoutFlags() LayoutFlags {
+	return 0
+}
+
+func (li *calendarViewLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *calendarViewLayoutItem) MinSize() Size {
+	return li.idealSize
+}
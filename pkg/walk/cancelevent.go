@@ -15,11 +15,17 @@ type cancelEventHandlerInfo struct {
 type CancelEventHandler func(canceled *bool)
 
 type CancelEvent struct {
-	handlers []cancelEventHandlerInfo
+	handlers   []*cancelEventHandlerInfo
+	publishing int // Number of Publish calls in progress
 }
 
 func (e *CancelEvent) Attach(handler CancelEventHandler) int {
-	handlerInfo := cancelEventHandlerInfo{handler, false}
+	handlerInfo := &cancelEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*cancelEventHandlerInfo(nil), e.handlers...)
+	}
 
 	for i, h := range e.handlers {
 		if h.handler == nil {
@@ -42,6 +48,20 @@ func (e *CancelEvent) Once(handler CancelEventHandler) {
 	e.handlers[i].once = true
 }
 
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *CancelEvent) Connect(handler CancelEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *CancelEvent) AttachOwned(owner Window, handler CancelEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
 type CancelEventPublisher struct {
 	event CancelEvent
 }
@@ -51,13 +71,20 @@ func (p *CancelEventPublisher) Event() *CancelEvent {
 }
 
 func (p *CancelEventPublisher) Publish(canceled *bool) {
-	for i, h := range p.event.handlers {
-		if h.handler != nil {
-			h.handler(canceled)
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
 
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
 			if h.once {
-				p.event.Detach(i)
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
 			}
+
+			handler(canceled)
 		}
 	}
 }
@@ -63,6 +63,8 @@ type Canvas struct {
 	recordingMetafile   *Metafile
 	measureTextMetafile *Metafile
 	doNotDispose        bool
+	restoreLayout       bool
+	layout              uint32 // to restore by Dispose
 }
 
 func NewCanvasFromImage(image Image) (*Canvas, error) {
@@ -138,10 +140,17 @@ func (c *Canvas) init() (*Canvas, error) {
 		return nil, newError("SetBrushOrgEx failed")
 	}
 
+	c.preserveBitmapOrientation()
+
 	return c, nil
 }
 
 func (c *Canvas) Dispose() {
+	if c.restoreLayout && c.hdc != 0 {
+		setLayout.Call(uintptr(c.hdc), uintptr(c.layout))
+		c.restoreLayout = false
+	}
+
 	if !c.doNotDispose && c.hdc != 0 {
 		if c.bitmap != nil {
 			win.SelectObject(c.hdc, win.HGDIOBJ(c.hBmpStock))
@@ -0,0 +1,638 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"time"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const carouselTimerId = 1
+
+const (
+	carouselHitNone     = -1
+	carouselHitPrevious = -2
+	carouselHitNext     = -3
+	// Hits >= 0 are page indicators.
+)
+
+// Carousel is a widget that shows one of a number of Images at a time.
+//
+// Previous and next buttons and page indicators are drawn over the image.
+// Dragging the image horizontally swipes to the neighboring image, as do the
+// left and right arrow keys. With an auto-advance interval, the Carousel
+// moves to the next image periodically, except while the mouse is over it.
+//
+// The Carousel doesn't take ownership of its images.
+type Carousel struct {
+	*CustomWidget
+	images                       []Image
+	currentIndex                 int
+	wraps                        bool
+	interval                     time.Duration
+	timerActive                  bool
+	hover                        bool
+	hotHit                       int
+	pressed                      bool
+	pressHit                     int
+	pressX                       int
+	dragging                     bool
+	dragOffset                   int // in native pixels
+	trackingMouseEvent           bool
+	currentIndexChangedPublisher EventPublisher
+}
+
+// NewCarousel creates and initializes a new, empty Carousel that wraps
+// around at its ends.
+func NewCarousel(parent Container) (*Carousel, error) {
+	c := &Carousel{
+		currentIndex: -1,
+		wraps:        true,
+		hotHit:       carouselHitNone,
+		pressHit:     carouselHitNone,
+	}
+
+	cw, err := NewCustomWidgetPixels(parent, win.WS_TABSTOP, func(canvas *Canvas, updateBounds Rectangle) error {
+		return c.paint(canvas)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			cw.Dispose()
+		}
+	}()
+
+	c.CustomWidget = cw
+
+	if err := InitWrapperWindow(c); err != nil {
+		return nil, err
+	}
+
+	c.SetPaintMode(PaintBuffered)
+	c.SetInvalidatesOnResize(true)
+
+	c.GraphicsEffects().Add(InteractionEffect)
+	c.GraphicsEffects().Add(FocusEffect)
+
+	c.MustRegisterProperty("CurrentIndex", NewProperty(
+		func() interface{} {
+			return c.CurrentIndex()
+		},
+		func(v interface{}) error {
+			return c.SetCurrentIndex(assertIntOr(v, -1))
+		},
+		c.currentIndexChangedPublisher.Event()))
+
+	succeeded = true
+
+	return c, nil
+}
+
+func (c *Carousel) Dispose() {
+	c.updateTimer(false)
+
+	c.CustomWidget.Dispose()
+}
+
+// Images returns the images of the Carousel.
+func (c *Carousel) Images() []Image {
+	return append([]Image(nil), c.images...)
+}
+
+// SetImages sets the images of the Carousel and shows the first one.
+func (c *Carousel) SetImages(images []Image) {
+	c.images = append([]Image(nil), images...)
+
+	index := -1
+	if len(c.images) > 0 {
+		index = 0
+	}
+
+	c.dragging = false
+	c.setCurrentIndex(index)
+
+	c.Invalidate()
+}
+
+// CurrentIndex returns the index of the image shown, or -1 if there are no
+// images.
+func (c *Carousel) CurrentIndex() int {
+	return c.currentIndex
+}
+
+// SetCurrentIndex shows the image at index.
+func (c *Carousel) SetCurrentIndex(index int) error {
+	if index < 0 || index >= len(c.images) {
+		if index == -1 && len(c.images) == 0 {
+			return nil
+		}
+
+		return newError("index out of range")
+	}
+
+	c.setCurrentIndex(index)
+
+	return nil
+}
+
+func (c *Carousel) setCurrentIndex(index int) {
+	// Manual navigation restarts the auto-advance interval.
+	if c.timerActive {
+		c.updateTimer(false)
+	}
+	c.updateTimer(c.shouldAdvance())
+
+	if index == c.currentIndex {
+		return
+	}
+
+	c.currentIndex = index
+
+	c.Invalidate()
+
+	c.currentIndexChangedPublisher.Publish()
+}
+
+// CurrentIndexChanged returns the event that is published when another image
+// is shown.
+func (c *Carousel) CurrentIndexChanged() *Event {
+	return c.currentIndexChangedPublisher.Event()
+}
+
+// Next shows the next image, if there is one.
+func (c *Carousel) Next() {
+	if index, ok := c.neighbor(1); ok {
+		c.setCurrentIndex(index)
+	}
+}
+
+// Previous shows the previous image, if there is one.
+func (c *Carousel) Previous() {
+	if index, ok := c.neighbor(-1); ok {
+		c.setCurrentIndex(index)
+	}
+}
+
+// Wraps returns whether the last image is followed by the first one.
+func (c *Carousel) Wraps() bool {
+	return c.wraps
+}
+
+// SetWraps sets whether the last image is followed by the first one.
+func (c *Carousel) SetWraps(wraps bool) {
+	c.wraps = wraps
+
+	c.Invalidate()
+}
+
+// AutoAdvanceInterval returns the interval the Carousel moves to the next
+// image in, 0 means it doesn't.
+func (c *Carousel) AutoAdvanceInterval() time.Duration {
+	return c.interval
+}
+
+// SetAutoAdvanceInterval sets the interval the Carousel moves to the next
+// image in, 0 means it doesn't.
+func (c *Carousel) SetAutoAdvanceInterval(interval time.Duration) error {
+	if interval < 0 {
+		return newError("interval must not be negative")
+	}
+
+	c.interval = interval
+
+	c.updateTimer(false)
+	c.updateTimer(c.shouldAdvance())
+
+	return nil
+}
+
+// neighbor returns the index of the image in direction, 1 for next and -1
+// for previous.
+func (c *Carousel) neighbor(direction int) (int, bool) {
+	n := len(c.images)
+	if n < 2 {
+		return 0, false
+	}
+
+	index := c.currentIndex + direction
+	if index < 0 || index >= n {
+		if !c.wraps {
+			return 0, false
+		}
+
+		index = (index + n) % n
+	}
+
+	return index, true
+}
+
+func (c *Carousel) shouldAdvance() bool {
+	return c.canAdvance() && c.Visible()
+}
+
+func (c *Carousel) canAdvance() bool {
+	return c.interval > 0 && len(c.images) > 1 && !c.hover && !c.dragging
+}
+
+func (c *Carousel) updateTimer(active bool) {
+	if active == c.timerActive || c.hWnd == 0 {
+		return
+	}
+
+	if active {
+		if 0 == win.SetTimer(c.hWnd, carouselTimerId, uint32(c.interval/time.Millisecond), 0) {
+			lastError("SetTimer")
+			return
+		}
+	} else {
+		win.KillTimer(c.hWnd, carouselTimerId)
+	}
+
+	c.timerActive = active
+}
+
+func (c *Carousel) buttonBounds(hit int) Rectangle {
+	cb := c.ClientBoundsPixels()
+	dpi := c.DPI()
+
+	size := IntFrom96DPI(28, dpi)
+	margin := IntFrom96DPI(8, dpi)
+
+	x := cb.X + margin
+	if hit == carouselHitNext {
+		x = cb.X + cb.Width - margin - size
+	}
+
+	return Rectangle{x, cb.Y + (cb.Height-size)/2, size, size}
+}
+
+func (c *Carousel) dotBounds(index int) Rectangle {
+	cb := c.ClientBoundsPixels()
+	dpi := c.DPI()
+
+	size := IntFrom96DPI(8, dpi)
+	spacing := IntFrom96DPI(6, dpi)
+	margin := IntFrom96DPI(10, dpi)
+
+	n := len(c.images)
+	width := n*size + (n-1)*spacing
+
+	return Rectangle{cb.X + (cb.Width-width)/2 + index*(size+spacing), cb.Y + cb.Height - margin - size, size, size}
+}
+
+func (c *Carousel) hitTest(x, y int) int {
+	contains := func(r Rectangle) bool {
+		return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+	}
+
+	if len(c.images) < 2 {
+		return carouselHitNone
+	}
+
+	if _, ok := c.neighbor(-1); ok && contains(c.buttonBounds(carouselHitPrevious)) {
+		return carouselHitPrevious
+	}
+	if _, ok := c.neighbor(1); ok && contains(c.buttonBounds(carouselHitNext)) {
+		return carouselHitNext
+	}
+
+	// Make the small page indicators easier to hit.
+	pad := IntFrom96DPI(3, c.DPI())
+
+	for i := range c.images {
+		r := c.dotBounds(i)
+		if contains(Rectangle{r.X - pad, r.Y - pad, r.Width + 2*pad, r.Height + 2*pad}) {
+			return i
+		}
+	}
+
+	return carouselHitNone
+}
+
+func (c *Carousel) activate(hit int) {
+	switch {
+	case hit == carouselHitPrevious:
+		c.Previous()
+
+	case hit == carouselHitNext:
+		c.Next()
+
+	case hit >= 0:
+		c.SetCurrentIndex(hit)
+	}
+}
+
+func (c *Carousel) setHotHit(hit int) {
+	if hit != c.hotHit {
+		c.hotHit = hit
+		c.Invalidate()
+	}
+}
+
+func (c *Carousel) endDrag() {
+	c.dragging = false
+	c.dragOffset = 0
+	c.updateTimer(c.shouldAdvance())
+	c.Invalidate()
+}
+
+func (c *Carousel) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_TIMER:
+		if wParam == carouselTimerId {
+			if _, ok := c.neighbor(1); ok {
+				c.Next()
+			} else {
+				c.updateTimer(false)
+			}
+			return 0
+		}
+
+	case win.WM_SHOWWINDOW:
+		// Visible doesn't reflect the new state yet.
+		c.updateTimer(wParam != 0 && c.canAdvance())
+
+	case win.WM_MOUSEMOVE:
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+		if !c.trackingMouseEvent {
+			var tme win.TRACKMOUSEEVENT
+			tme.CbSize = uint32(unsafe.Sizeof(tme))
+			tme.DwFlags = win.TME_LEAVE
+			tme.HwndTrack = hwnd
+
+			c.trackingMouseEvent = win.TrackMouseEvent(&tme)
+
+			c.hover = true
+			c.updateTimer(false)
+		}
+
+		if c.pressed {
+			dx := x - c.pressX
+
+			if !c.dragging && len(c.images) > 1 {
+				if d := int(win.GetSystemMetrics(win.SM_CXDRAG)); dx*dx > d*d {
+					c.dragging = true
+					c.updateTimer(false)
+				}
+			}
+
+			if c.dragging {
+				c.dragOffset = dx
+				c.Invalidate()
+				break
+			}
+		}
+
+		c.setHotHit(c.hitTest(x, y))
+
+	case win.WM_MOUSELEAVE:
+		c.trackingMouseEvent = false
+		c.hover = false
+		c.setHotHit(carouselHitNone)
+		c.updateTimer(c.shouldAdvance())
+
+	case win.WM_LBUTTONDOWN:
+		if !c.Enabled() {
+			break
+		}
+
+		c.SetFocus()
+
+		c.pressed = true
+		c.pressX = int(win.GET_X_LPARAM(lParam))
+		c.pressHit = c.hitTest(c.pressX, int(win.GET_Y_LPARAM(lParam)))
+
+	case win.WM_LBUTTONUP:
+		if !c.pressed {
+			break
+		}
+		c.pressed = false
+
+		if c.dragging {
+			threshold := c.ClientBoundsPixels().Width / 4
+			offset := c.dragOffset
+
+			c.endDrag()
+
+			if offset < -threshold {
+				c.Next()
+			} else if offset > threshold {
+				c.Previous()
+			}
+		} else if hit := c.hitTest(int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))); hit == c.pressHit {
+			c.activate(hit)
+		}
+
+		c.pressHit = carouselHitNone
+
+	case win.WM_CAPTURECHANGED:
+		c.pressed = false
+
+		if c.dragging {
+			c.endDrag()
+		}
+
+	case win.WM_KEYDOWN:
+		if !c.Enabled() || len(c.images) == 0 {
+			break
+		}
+
+		switch Key(wParam) {
+		case KeyLeft:
+			c.Previous()
+
+		case KeyRight:
+			c.Next()
+
+		case KeyHome:
+			c.SetCurrentIndex(0)
+
+		case KeyEnd:
+			c.SetCurrentIndex(len(c.images) - 1)
+
+		case KeyEscape:
+			if c.dragging {
+				c.endDrag()
+			}
+		}
+	}
+
+	return c.CustomWidget.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (c *Carousel) paint(canvas *Canvas) error {
+	cb := c.ClientBoundsPixels()
+
+	bg, _ := c.backgroundEffective()
+	if bg == nil {
+		bg = sysColorBtnFaceBrush
+	}
+
+	if err := canvas.FillRectanglePixels(bg, cb); err != nil {
+		return err
+	}
+
+	if c.currentIndex < 0 {
+		return nil
+	}
+
+	offset := c.dragOffset
+	if c.dragging {
+		// Resist dragging past the ends.
+		if _, ok := c.neighbor(-1); !ok && offset > 0 {
+			offset /= 3
+		} else if _, ok := c.neighbor(1); !ok && offset < 0 {
+			offset /= 3
+		}
+	}
+
+	if err := c.paintImage(canvas, c.images[c.currentIndex], cb, offset); err != nil {
+		return err
+	}
+
+	if offset < 0 {
+		if index, ok := c.neighbor(1); ok {
+			if err := c.paintImage(canvas, c.images[index], cb, offset+cb.Width); err != nil {
+				return err
+			}
+		}
+	} else if offset > 0 {
+		if index, ok := c.neighbor(-1); ok {
+			if err := c.paintImage(canvas, c.images[index], cb, offset-cb.Width); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(c.images) < 2 {
+		return nil
+	}
+
+	if _, ok := c.neighbor(-1); ok {
+		if err := c.paintButton(canvas, carouselHitPrevious); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := c.neighbor(1); ok {
+		if err := c.paintButton(canvas, carouselHitNext); err != nil {
+			return err
+		}
+	}
+
+	return c.paintDots(canvas)
+}
+
+// paintImage draws image scaled to fit bounds, keeping its aspect ratio,
+// moved horizontally by offset.
+func (c *Carousel) paintImage(canvas *Canvas, image Image, bounds Rectangle, offset int) error {
+	if image == nil {
+		return nil
+	}
+
+	s := SizeFrom96DPI(image.Size(), c.DPI())
+	if s.Width <= 0 || s.Height <= 0 {
+		return nil
+	}
+
+	scale := math.Min(float64(bounds.Width)/float64(s.Width), float64(bounds.Height)/float64(s.Height))
+
+	r := Rectangle{Width: int(float64(s.Width) * scale), Height: int(float64(s.Height) * scale)}
+	r.X = bounds.X + offset + (bounds.Width-r.Width)/2
+	r.Y = bounds.Y + (bounds.Height-r.Height)/2
+
+	return canvas.DrawImageStretchedPixels(image, r)
+}
+
+func (c *Carousel) paintButton(canvas *Canvas, hit int) error {
+	r := c.buttonBounds(hit)
+
+	fill := win.COLOR_BTNFACE
+	if c.hotHit == hit {
+		fill = win.COLOR_WINDOW
+	}
+
+	brush, err := NewSolidColorBrush(Color(win.GetSysColor(fill)))
+	if err != nil {
+		return err
+	}
+	defer brush.Dispose()
+
+	if err := canvas.FillEllipsePixels(brush, r); err != nil {
+		return err
+	}
+
+	outlinePen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer outlinePen.Dispose()
+
+	if err := canvas.DrawEllipsePixels(outlinePen, r); err != nil {
+		return err
+	}
+
+	textBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_BTNTEXT)))
+	if err != nil {
+		return err
+	}
+	defer textBrush.Dispose()
+
+	chevronPen, err := NewGeometricPen(PenSolid|PenCapRound|PenJoinRound, IntFrom96DPI(2, c.DPI()), textBrush)
+	if err != nil {
+		return err
+	}
+	defer chevronPen.Dispose()
+
+	cx, cy := r.X+r.Width/2, r.Y+r.Height/2
+	d := r.Width / 6
+
+	points := []Point{{cx + d/2, cy - d}, {cx - d/2, cy}, {cx + d/2, cy + d}}
+	if hit == carouselHitNext {
+		for i := range points {
+			points[i].X = 2*cx - points[i].X
+		}
+	}
+
+	return canvas.DrawPolylinePixels(chevronPen, points)
+}
+
+func (c *Carousel) paintDots(canvas *Canvas) error {
+	for i := range c.images {
+		color := win.COLOR_BTNSHADOW
+		if i == c.currentIndex {
+			color = win.COLOR_HIGHLIGHT
+		} else if i == c.hotHit {
+			color = win.COLOR_BTNTEXT
+		}
+
+		brush, err := NewSolidColorBrush(Color(win.GetSysColor(color)))
+		if err != nil {
+			return err
+		}
+
+		err = canvas.FillEllipsePixels(brush, c.dotBounds(i))
+		brush.Dispose()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
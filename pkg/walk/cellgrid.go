@@ -0,0 +1,1419 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const cellGridWindowClass = `\o/ Walk_CellGrid_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClassWithStyle(cellGridWindowClass, win.CS_DBLCLKS)
+	})
+}
+
+// CellGrid is a spreadsheet-like widget that shows the cells of a
+// CellGridModel, for matrix-like data that doesn't fit the row model of a
+// TableView.
+//
+// The user can select ranges of cells with the mouse and keyboard, extend
+// them with Shift and add more with Ctrl. Dragging the handle at the corner of
+// the selection fills the cells it is dragged over. Ctrl+C and Ctrl+V copy and
+// paste rectangular ranges as tab separated text, which spreadsheet
+// applications understand.
+type CellGrid struct {
+	WidgetBase
+	model                       CellGridModel
+	cellsChangedHandle          int
+	defaultColumnWidth          int         // in 1/96"
+	columnWidths                map[int]int // in 1/96", by column
+	rowHeightFont               *Font
+	rowHeightDPI                int
+	rowHeight                   int // in native pixels
+	frozenRows                  int
+	frozenColumns               int
+	scrollRow                   int // first scrollable row shown
+	scrollColumn                int // first scrollable column shown
+	currentRow                  int
+	currentColumn               int
+	anchorRow                   int
+	anchorColumn                int
+	selection                   []CellRange
+	selecting                   bool
+	fillDragging                bool
+	fillTarget                  CellRange
+	selectionChangedPublisher   EventPublisher
+	currentCellChangedPublisher EventPublisher
+}
+
+// NewCellGrid creates and initializes a new CellGrid without a model.
+func NewCellGrid(parent Container) (*CellGrid, error) {
+	cg := &CellGrid{defaultColumnWidth: 64, columnWidths: make(map[int]int)}
+
+	if err := InitWidget(
+		cg,
+		parent,
+		cellGridWindowClass,
+		win.WS_TABSTOP|win.WS_VISIBLE|win.WS_HSCROLL|win.WS_VSCROLL,
+		0); err != nil {
+		return nil, err
+	}
+
+	cg.GraphicsEffects().Add(InteractionEffect)
+	cg.GraphicsEffects().Add(FocusEffect)
+
+	return cg, nil
+}
+
+func (cg *CellGrid) Dispose() {
+	if cg.model != nil {
+		cg.model.CellsChanged().Detach(cg.cellsChangedHandle)
+		cg.model = nil
+	}
+
+	cg.WidgetBase.Dispose()
+}
+
+func (cg *CellGrid) applyFont(font *Font) {
+	cg.WidgetBase.applyFont(font)
+
+	cg.updateScrollBars()
+	cg.Invalidate()
+}
+
+// Model returns the model of the CellGrid.
+func (cg *CellGrid) Model() CellGridModel {
+	return cg.model
+}
+
+// SetModel sets the model of the CellGrid.
+func (cg *CellGrid) SetModel(model CellGridModel) {
+	if cg.model != nil {
+		cg.model.CellsChanged().Detach(cg.cellsChangedHandle)
+	}
+
+	cg.model = model
+
+	if model != nil {
+		cg.cellsChangedHandle = model.CellsChanged().Attach(cg.onCellsChanged)
+	}
+
+	cg.scrollRow, cg.scrollColumn = cg.frozenRows, cg.frozenColumns
+	cg.setCurrentCell(0, 0, false)
+
+	cg.onCellsChanged()
+}
+
+func (cg *CellGrid) onCellsChanged() {
+	rows, columns := cg.rowCount(), cg.columnCount()
+
+	for _, r := range cg.selection {
+		if r.lastRow() >= rows || r.lastColumn() >= columns {
+			cg.setCurrentCell(mini(cg.currentRow, rows-1), mini(cg.currentColumn, columns-1), false)
+			break
+		}
+	}
+
+	cg.updateScrollBars()
+	cg.Invalidate()
+}
+
+func (cg *CellGrid) rowCount() int {
+	if cg.model == nil {
+		return 0
+	}
+
+	return cg.model.RowCount()
+}
+
+func (cg *CellGrid) columnCount() int {
+	if cg.model == nil {
+		return 0
+	}
+
+	return cg.model.ColumnCount()
+}
+
+// FrozenRows returns the number of leading rows that don't scroll.
+func (cg *CellGrid) FrozenRows() int {
+	return cg.frozenRows
+}
+
+// SetFrozenRows sets the number of leading rows that don't scroll.
+func (cg *CellGrid) SetFrozenRows(count int) error {
+	if count < 0 {
+		return newError("count must >= 0")
+	}
+
+	cg.frozenRows = count
+	cg.scrollRow = count
+
+	cg.updateScrollBars()
+	cg.Invalidate()
+
+	return nil
+}
+
+// FrozenColumns returns the number of leading columns that don't scroll.
+func (cg *CellGrid) FrozenColumns() int {
+	return cg.frozenColumns
+}
+
+// SetFrozenColumns sets the number of leading columns that don't scroll.
+func (cg *CellGrid) SetFrozenColumns(count int) error {
+	if count < 0 {
+		return newError("count must >= 0")
+	}
+
+	cg.frozenColumns = count
+	cg.scrollColumn = count
+
+	cg.updateScrollBars()
+	cg.Invalidate()
+
+	return nil
+}
+
+// DefaultColumnWidth returns the width of columns without a width of their
+// own in 1/96".
+func (cg *CellGrid) DefaultColumnWidth() int {
+	return cg.defaultColumnWidth
+}
+
+// SetDefaultColumnWidth sets the width of columns without a width of their
+// own in 1/96".
+func (cg *CellGrid) SetDefaultColumnWidth(width int) error {
+	if width < 1 {
+		return newError("width must >= 1")
+	}
+
+	cg.defaultColumnWidth = width
+
+	cg.updateScrollBars()
+	cg.Invalidate()
+
+	return nil
+}
+
+// ColumnWidth returns the width of column in 1/96".
+func (cg *CellGrid) ColumnWidth(column int) int {
+	if width, ok := cg.columnWidths[column]; ok {
+		return width
+	}
+
+	return cg.defaultColumnWidth
+}
+
+// SetColumnWidth sets the width of column in 1/96".
+func (cg *CellGrid) SetColumnWidth(column, width int) error {
+	if width < 1 {
+		return newError("width must >= 1")
+	}
+
+	cg.columnWidths[column] = width
+
+	cg.updateScrollBars()
+	cg.Invalidate()
+
+	return nil
+}
+
+// CurrentCell returns the row and column of the cell that has the keyboard
+// focus.
+func (cg *CellGrid) CurrentCell() (row, column int) {
+	return cg.currentRow, cg.currentColumn
+}
+
+// SetCurrentCell makes the cell at row and column current and the only one
+// selected.
+func (cg *CellGrid) SetCurrentCell(row, column int) error {
+	if row < 0 || row >= cg.rowCount() || column < 0 || column >= cg.columnCount() {
+		return newError("invalid cell")
+	}
+
+	cg.setCurrentCell(row, column, false)
+	cg.EnsureCellVisible(row, column)
+
+	return nil
+}
+
+// setCurrentCell makes the cell at row and column current, and either
+// extends the last selected range from the anchor cell to it, or selects only
+// it and makes it the anchor.
+func (cg *CellGrid) setCurrentCell(row, column int, extend bool) {
+	row, column = maxi(row, 0), maxi(column, 0)
+
+	changed := row != cg.currentRow || column != cg.currentColumn
+	cg.currentRow, cg.currentColumn = row, column
+
+	r := cellRangeFromCells(row, column, row, column)
+	if extend && len(cg.selection) > 0 {
+		cg.selection[len(cg.selection)-1] = cellRangeFromCells(cg.anchorRow, cg.anchorColumn, row, column)
+	} else {
+		cg.anchorRow, cg.anchorColumn = row, column
+		cg.selection = []CellRange{r}
+	}
+
+	cg.Invalidate()
+
+	if changed {
+		cg.currentCellChangedPublisher.Publish()
+	}
+	cg.selectionChangedPublisher.Publish()
+}
+
+// selectRange makes the cell at row and column current and the anchor of r,
+// which is either added to the selected ranges or replaces them.
+func (cg *CellGrid) selectRange(row, column int, r CellRange, add bool) {
+	changed := row != cg.currentRow || column != cg.currentColumn
+	cg.currentRow, cg.currentColumn = row, column
+	cg.anchorRow, cg.anchorColumn = row, column
+
+	if add {
+		cg.selection = append(cg.selection, r)
+	} else {
+		cg.selection = []CellRange{r}
+	}
+
+	cg.Invalidate()
+
+	if changed {
+		cg.currentCellChangedPublisher.Publish()
+	}
+	cg.selectionChangedPublisher.Publish()
+}
+
+// Selection returns the selected ranges of cells.
+func (cg *CellGrid) Selection() []CellRange {
+	return append([]CellRange(nil), cg.selection...)
+}
+
+// SetSelection selects the ranges of cells and makes the first cell of the
+// last range current.
+func (cg *CellGrid) SetSelection(ranges []CellRange) error {
+	rows, columns := cg.rowCount(), cg.columnCount()
+
+	for _, r := range ranges {
+		if r.IsEmpty() || r.Row < 0 || r.Column < 0 || r.lastRow() >= rows || r.lastColumn() >= columns {
+			return newError("invalid range")
+		}
+	}
+
+	if len(ranges) == 0 {
+		cg.setCurrentCell(cg.currentRow, cg.currentColumn, false)
+		return nil
+	}
+
+	last := len(ranges) - 1
+
+	cg.selection = append([]CellRange(nil), ranges[:last]...)
+	cg.selectRange(ranges[last].Row, ranges[last].Column, ranges[last], true)
+
+	return nil
+}
+
+// SelectAll selects all cells.
+func (cg *CellGrid) SelectAll() {
+	if rows, columns := cg.rowCount(), cg.columnCount(); rows > 0 && columns > 0 {
+		cg.SetSelection([]CellRange{{0, 0, rows, columns}})
+	}
+}
+
+// IsCellSelected returns if the cell at row and column is selected.
+func (cg *CellGrid) IsCellSelected(row, column int) bool {
+	for _, r := range cg.selection {
+		if r.Contains(row, column) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SelectionChanged returns the event that is published when the selected
+// ranges of cells changed.
+func (cg *CellGrid) SelectionChanged() *Event {
+	return cg.selectionChangedPublisher.Event()
+}
+
+// CurrentCellChanged returns the event that is published when another cell
+// became current.
+func (cg *CellGrid) CurrentCellChanged() *Event {
+	return cg.currentCellChangedPublisher.Event()
+}
+
+// lastSelectedRange returns the range that was selected last, which copying,
+// pasting and filling operate on.
+func (cg *CellGrid) lastSelectedRange() (CellRange, bool) {
+	if len(cg.selection) == 0 || cg.rowCount() == 0 || cg.columnCount() == 0 {
+		return CellRange{}, false
+	}
+
+	return cg.selection[len(cg.selection)-1], true
+}
+
+func (cg *CellGrid) cellTextSetter() (CellTextSetter, error) {
+	if setter, ok := cg.model.(CellTextSetter); ok {
+		return setter, nil
+	}
+
+	return nil, newError("model must implement CellTextSetter")
+}
+
+// Copy copies the cells of the range that was selected last to the
+// clipboard, as lines of tab separated text.
+func (cg *CellGrid) Copy() error {
+	r, ok := cg.lastSelectedRange()
+	if !ok {
+		return nil
+	}
+
+	var sb strings.Builder
+
+	for row := r.Row; row <= r.lastRow(); row++ {
+		for column := r.Column; column <= r.lastColumn(); column++ {
+			if column > r.Column {
+				sb.WriteByte('\t')
+			}
+
+			sb.WriteString(cg.model.CellText(row, column))
+		}
+
+		sb.WriteString("\r\n")
+	}
+
+	return Clipboard().SetText(sb.String())
+}
+
+// Paste sets the cells from the lines of tab separated text in the
+// clipboard, starting at the first cell of the range that was selected last.
+// A single value is pasted into all cells of that range. Cells beyond the
+// last row or column are dropped.
+func (cg *CellGrid) Paste() error {
+	r, ok := cg.lastSelectedRange()
+	if !ok {
+		return nil
+	}
+
+	setter, err := cg.cellTextSetter()
+	if err != nil {
+		return err
+	}
+
+	text, err := Clipboard().Text()
+	if err != nil {
+		return err
+	}
+
+	text = strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+
+	var values [][]string
+	for _, line := range strings.Split(text, "\n") {
+		values = append(values, strings.Split(line, "\t"))
+	}
+
+	if len(values) == 1 && len(values[0]) == 1 {
+		for row := r.Row; row <= r.lastRow(); row++ {
+			for column := r.Column; column <= r.lastColumn(); column++ {
+				if err := setter.SetCellText(row, column, values[0][0]); err != nil {
+					return err
+				}
+			}
+		}
+
+		cg.Invalidate()
+
+		return nil
+	}
+
+	rows, columns := cg.rowCount(), cg.columnCount()
+	pasted := CellRange{Row: r.Row, Column: r.Column}
+
+	for i, line := range values {
+		row := r.Row + i
+		if row >= rows {
+			break
+		}
+
+		for j, value := range line {
+			column := r.Column + j
+			if column >= columns {
+				break
+			}
+
+			if err := setter.SetCellText(row, column, value); err != nil {
+				return err
+			}
+
+			pasted.ColumnCount = maxi(pasted.ColumnCount, j+1)
+		}
+
+		pasted.RowCount = i + 1
+	}
+
+	cg.Invalidate()
+
+	return cg.SetSelection([]CellRange{pasted})
+}
+
+// ClearSelectedCells sets the text of all selected cells to "".
+func (cg *CellGrid) ClearSelectedCells() error {
+	if _, ok := cg.lastSelectedRange(); !ok {
+		return nil
+	}
+
+	setter, err := cg.cellTextSetter()
+	if err != nil {
+		return err
+	}
+
+	for _, r := range cg.selection {
+		for row := r.Row; row <= r.lastRow(); row++ {
+			for column := r.Column; column <= r.lastColumn(); column++ {
+				if err := setter.SetCellText(row, column, ""); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	cg.Invalidate()
+
+	return nil
+}
+
+// Fill fills the cells of target, which must contain source, outside of
+// source, like dragging the fill handle does, and selects target.
+//
+// If the model implements CellFiller, it does the filling, otherwise the
+// cells of source are repeated.
+func (cg *CellGrid) Fill(source, target CellRange) error {
+	if source.IsEmpty() || !target.Contains(source.Row, source.Column) || !target.Contains(source.lastRow(), source.lastColumn()) {
+		return newError("target must contain source")
+	}
+	if target.Row < 0 || target.Column < 0 || target.lastRow() >= cg.rowCount() || target.lastColumn() >= cg.columnCount() {
+		return newError("invalid range")
+	}
+
+	if filler, ok := cg.model.(CellFiller); ok {
+		if err := filler.FillCells(source, target); err != nil {
+			return err
+		}
+	} else {
+		setter, err := cg.cellTextSetter()
+		if err != nil {
+			return err
+		}
+
+		mod := func(a, b int) int {
+			return (a%b + b) % b
+		}
+
+		for row := target.Row; row <= target.lastRow(); row++ {
+			for column := target.Column; column <= target.lastColumn(); column++ {
+				if source.Contains(row, column) {
+					continue
+				}
+
+				text := cg.model.CellText(
+					source.Row+mod(row-source.Row, source.RowCount),
+					source.Column+mod(column-source.Column, source.ColumnCount))
+
+				if err := setter.SetCellText(row, column, text); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	cg.Invalidate()
+
+	return cg.SetSelection([]CellRange{target})
+}
+
+// gridSpan is a row or column shown in a CellGrid.
+type gridSpan struct {
+	index int
+	pos   int // in native pixels
+	size  int // in native pixels
+}
+
+func (cg *CellGrid) rowHeightPixels() int {
+	font, dpi := cg.Font(), cg.DPI()
+
+	if font != cg.rowHeightFont || dpi != cg.rowHeightDPI {
+		cg.rowHeight = IntFrom96DPI(20, dpi)
+
+		if canvas, err := cg.CreateCanvas(); err == nil {
+			if bounds, _, err := canvas.MeasureTextPixels("Xg", font, Rectangle{Width: 1000, Height: 1000}, TextSingleLine); err == nil {
+				cg.rowHeight = maxi(1, bounds.Height+IntFrom96DPI(6, dpi))
+			}
+			canvas.Dispose()
+		}
+
+		cg.rowHeightFont, cg.rowHeightDPI = font, dpi
+	}
+
+	return cg.rowHeight
+}
+
+func (cg *CellGrid) rowHeaderWidthPixels() int {
+	return cg.IntFrom96DPI(48)
+}
+
+func (cg *CellGrid) columnWidthPixels(column int) int {
+	return cg.IntFrom96DPI(cg.ColumnWidth(column))
+}
+
+// spanSize returns the size of the row or column at index.
+func (cg *CellGrid) spanSize(orientation Orientation, index int) int {
+	if orientation == Horizontal {
+		return cg.columnWidthPixels(index)
+	}
+
+	return cg.rowHeightPixels()
+}
+
+// axis returns the count, number of frozen and first scrollable shown
+// columns or rows, and where they start and end in native pixels.
+func (cg *CellGrid) axis(orientation Orientation) (count, frozen, scroll, start, end int) {
+	cb := cg.ClientBoundsPixels()
+
+	if orientation == Horizontal {
+		return cg.columnCount(), cg.frozenColumns, cg.scrollColumn, cg.rowHeaderWidthPixels(), cb.Width
+	}
+
+	return cg.rowCount(), cg.frozenRows, cg.scrollRow, cg.rowHeightPixels(), cb.Height
+}
+
+// visibleSpans returns the columns or rows shown, frozen ones first.
+func (cg *CellGrid) visibleSpans(orientation Orientation) []gridSpan {
+	count, frozen, scroll, pos, end := cg.axis(orientation)
+
+	var spans []gridSpan
+
+	i := 0
+	if frozen == 0 {
+		i = scroll
+	}
+
+	for i < count && pos < end {
+		size := cg.spanSize(orientation, i)
+		spans = append(spans, gridSpan{i, pos, size})
+		pos += size
+
+		if i++; i == frozen {
+			i = maxi(i, scroll)
+		}
+	}
+
+	return spans
+}
+
+// frozenExtent returns the size of the frozen columns or rows.
+func (cg *CellGrid) frozenExtent(orientation Orientation) int {
+	count, frozen, _, _, _ := cg.axis(orientation)
+
+	var extent int
+	for i := 0; i < mini(frozen, count); i++ {
+		extent += cg.spanSize(orientation, i)
+	}
+
+	return extent
+}
+
+// maxScroll returns the first scrollable column or row shown, when scrolled
+// to the end.
+func (cg *CellGrid) maxScroll(orientation Orientation) int {
+	count, frozen, _, start, end := cg.axis(orientation)
+
+	space := end - start - cg.frozenExtent(orientation)
+
+	i := count
+	for i > frozen && space >= cg.spanSize(orientation, i-1) {
+		space -= cg.spanSize(orientation, i-1)
+		i--
+	}
+
+	if i == count {
+		i = count - 1
+	}
+
+	return maxi(i, frozen)
+}
+
+// spanAt returns the column or row at pos, or -1 if pos is within the header.
+// With clamp, the first or last shown one is returned for positions outside.
+func (cg *CellGrid) spanAt(orientation Orientation, pos int, clamp bool) int {
+	spans := cg.visibleSpans(orientation)
+	if len(spans) == 0 {
+		return -1
+	}
+
+	_, _, _, start, _ := cg.axis(orientation)
+	if pos < start {
+		if clamp {
+			return spans[0].index
+		}
+
+		return -1
+	}
+
+	for _, span := range spans {
+		if pos < span.pos+span.size {
+			return span.index
+		}
+	}
+
+	if clamp {
+		return spans[len(spans)-1].index
+	}
+
+	return -1
+}
+
+// spanBounds returns where the column or row at index is shown.
+func (cg *CellGrid) spanBounds(orientation Orientation, index int) (gridSpan, bool) {
+	for _, span := range cg.visibleSpans(orientation) {
+		if span.index == index {
+			return span, true
+		}
+	}
+
+	return gridSpan{}, false
+}
+
+func (cg *CellGrid) cellBounds(row, column int) (Rectangle, bool) {
+	rs, ok := cg.spanBounds(Vertical, row)
+	if !ok {
+		return Rectangle{}, false
+	}
+
+	cs, ok := cg.spanBounds(Horizontal, column)
+	if !ok {
+		return Rectangle{}, false
+	}
+
+	return Rectangle{cs.pos, rs.pos, cs.size, rs.size}, true
+}
+
+// rangeBounds returns the bounds of the shown cells of r.
+func (cg *CellGrid) rangeBounds(r CellRange) (Rectangle, bool) {
+	var x1, y1, x2, y2 int
+	var anyRow, anyColumn bool
+
+	for _, span := range cg.visibleSpans(Vertical) {
+		if span.index >= r.Row && span.index <= r.lastRow() {
+			if !anyRow {
+				y1 = span.pos
+				anyRow = true
+			}
+			y2 = span.pos + span.size
+		}
+	}
+
+	for _, span := range cg.visibleSpans(Horizontal) {
+		if span.index >= r.Column && span.index <= r.lastColumn() {
+			if !anyColumn {
+				x1 = span.pos
+				anyColumn = true
+			}
+			x2 = span.pos + span.size
+		}
+	}
+
+	return Rectangle{x1, y1, x2 - x1, y2 - y1}, anyRow && anyColumn
+}
+
+// fillHandleBounds returns the bounds of the handle at the bottom right
+// corner of the selection, which fills cells when dragged.
+func (cg *CellGrid) fillHandleBounds() (Rectangle, bool) {
+	if len(cg.selection) != 1 {
+		return Rectangle{}, false
+	}
+
+	b, ok := cg.rangeBounds(cg.selection[0])
+	if !ok {
+		return Rectangle{}, false
+	}
+
+	size := cg.IntFrom96DPI(6)
+
+	return Rectangle{b.X + b.Width - size/2 - 1, b.Y + b.Height - size/2 - 1, size, size}, true
+}
+
+func (cg *CellGrid) fillHandleAt(x, y int) bool {
+	b, ok := cg.fillHandleBounds()
+
+	return ok && x >= b.X && x < b.X+b.Width && y >= b.Y && y < b.Y+b.Height
+}
+
+// fillTargetFor returns the range the fill handle dragged to the cell at row
+// and column fills, extending the selection along one axis only.
+func (cg *CellGrid) fillTargetFor(row, column int) CellRange {
+	source := cg.selection[0]
+
+	var dr, dc int
+	if row > source.lastRow() {
+		dr = row - source.lastRow()
+	} else if row < source.Row {
+		dr = row - source.Row
+	}
+	if column > source.lastColumn() {
+		dc = column - source.lastColumn()
+	} else if column < source.Column {
+		dc = column - source.Column
+	}
+
+	target := source
+	if absi(dr) >= absi(dc) {
+		if dr > 0 {
+			target.RowCount += dr
+		} else {
+			target.Row += dr
+			target.RowCount -= dr
+		}
+	} else {
+		if dc > 0 {
+			target.ColumnCount += dc
+		} else {
+			target.Column += dc
+			target.ColumnCount -= dc
+		}
+	}
+
+	return target
+}
+
+func absi(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+func (cg *CellGrid) updateScrollBars() {
+	for _, orientation := range []Orientation{Horizontal, Vertical} {
+		count, frozen, _, _, _ := cg.axis(orientation)
+
+		sb := int32(win.SB_VERT)
+		if orientation == Horizontal {
+			sb = win.SB_HORZ
+		}
+
+		var si win.SCROLLINFO
+		si.CbSize = uint32(unsafe.Sizeof(si))
+		si.FMask = win.SIF_PAGE | win.SIF_RANGE
+		si.NMax = int32(count - frozen - 1)
+		si.NPage = uint32(maxi(count-cg.maxScroll(orientation), 1))
+
+		win.SetScrollInfo(cg.hWnd, sb, &si, true)
+
+		_, _, scroll, _, _ := cg.axis(orientation)
+		cg.setScroll(orientation, scroll)
+	}
+}
+
+// setScroll sets the first scrollable column or row shown.
+func (cg *CellGrid) setScroll(orientation Orientation, scroll int) {
+	_, frozen, _, _, _ := cg.axis(orientation)
+
+	scroll = maxi(mini(scroll, cg.maxScroll(orientation)), frozen)
+
+	sb := int32(win.SB_VERT)
+	if orientation == Horizontal {
+		sb = win.SB_HORZ
+	}
+
+	var si win.SCROLLINFO
+	si.CbSize = uint32(unsafe.Sizeof(si))
+	si.FMask = win.SIF_POS
+	si.NPos = int32(scroll - frozen)
+
+	win.SetScrollInfo(cg.hWnd, sb, &si, true)
+
+	p := &cg.scrollRow
+	if orientation == Horizontal {
+		p = &cg.scrollColumn
+	}
+
+	if scroll != *p {
+		*p = scroll
+		cg.Invalidate()
+	}
+}
+
+func (cg *CellGrid) scroll(orientation Orientation, cmd uint16) {
+	_, frozen, scroll, start, end := cg.axis(orientation)
+	page := maxi(1, (end-start-cg.frozenExtent(orientation))/maxi(1, cg.spanSize(orientation, scroll))-1)
+
+	switch cmd {
+	case win.SB_LINEUP:
+		cg.setScroll(orientation, scroll-1)
+
+	case win.SB_LINEDOWN:
+		cg.setScroll(orientation, scroll+1)
+
+	case win.SB_PAGEUP:
+		cg.setScroll(orientation, scroll-page)
+
+	case win.SB_PAGEDOWN:
+		cg.setScroll(orientation, scroll+page)
+
+	case win.SB_TOP:
+		cg.setScroll(orientation, frozen)
+
+	case win.SB_BOTTOM:
+		cg.setScroll(orientation, cg.maxScroll(orientation))
+
+	case win.SB_THUMBTRACK:
+		sb := int32(win.SB_VERT)
+		if orientation == Horizontal {
+			sb = win.SB_HORZ
+		}
+
+		var si win.SCROLLINFO
+		si.CbSize = uint32(unsafe.Sizeof(si))
+		si.FMask = win.SIF_TRACKPOS
+
+		win.GetScrollInfo(cg.hWnd, sb, &si)
+
+		cg.setScroll(orientation, frozen+int(si.NTrackPos))
+	}
+}
+
+// EnsureCellVisible scrolls the CellGrid, so the cell at row and column is
+// shown completely, unless it is frozen.
+func (cg *CellGrid) EnsureCellVisible(row, column int) {
+	cg.ensureVisible(Vertical, row)
+	cg.ensureVisible(Horizontal, column)
+}
+
+func (cg *CellGrid) ensureVisible(orientation Orientation, index int) {
+	_, frozen, scroll, start, end := cg.axis(orientation)
+	if index < frozen {
+		return
+	}
+
+	if index < scroll {
+		cg.setScroll(orientation, index)
+		return
+	}
+
+	space := end - start - cg.frozenExtent(orientation)
+
+	var extent int
+	for i := scroll; i <= index; i++ {
+		extent += cg.spanSize(orientation, i)
+	}
+	if extent <= space {
+		return
+	}
+
+	first, extent := index, cg.spanSize(orientation, index)
+	for first-1 >= frozen && extent+cg.spanSize(orientation, first-1) <= space {
+		first--
+		extent += cg.spanSize(orientation, first)
+	}
+
+	cg.setScroll(orientation, first)
+}
+
+// autoScroll scrolls by one column or row, when the mouse at x, y is dragged
+// beyond the edge of the scrollable cells.
+func (cg *CellGrid) autoScroll(x, y int) {
+	for _, orientation := range []Orientation{Horizontal, Vertical} {
+		_, _, scroll, start, end := cg.axis(orientation)
+
+		pos := y
+		if orientation == Horizontal {
+			pos = x
+		}
+
+		if pos >= end {
+			cg.setScroll(orientation, scroll+1)
+		} else if pos < start+cg.frozenExtent(orientation) {
+			cg.setScroll(orientation, scroll-1)
+		}
+	}
+}
+
+func (cg *CellGrid) columnTitle(column int) string {
+	if hm, ok := cg.model.(CellGridHeaderModel); ok {
+		return hm.ColumnTitle(column)
+	}
+
+	var title []byte
+	for column++; column > 0; column = (column - 1) / 26 {
+		title = append([]byte{byte('A' + (column-1)%26)}, title...)
+	}
+
+	return string(title)
+}
+
+func (cg *CellGrid) rowTitle(row int) string {
+	if hm, ok := cg.model.(CellGridHeaderModel); ok {
+		return hm.RowTitle(row)
+	}
+
+	return strconv.Itoa(row + 1)
+}
+
+func (cg *CellGrid) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, cg.DPI(), cg.paint) {
+			return 0
+		}
+
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_SIZE:
+		cg.updateScrollBars()
+		cg.Invalidate()
+
+	case win.WM_HSCROLL:
+		cg.scroll(Horizontal, win.LOWORD(uint32(wParam)))
+		return 0
+
+	case win.WM_VSCROLL:
+		cg.scroll(Vertical, win.LOWORD(uint32(wParam)))
+		return 0
+
+	case win.WM_MOUSEWHEEL:
+		delta := int(int16(win.HIWORD(uint32(wParam))))
+
+		lines := -delta * 3 / 120
+		if lines == 0 && delta != 0 {
+			lines = -delta / absi(delta)
+		}
+
+		if win.LOWORD(uint32(wParam))&win.MK_SHIFT != 0 {
+			cg.setScroll(Horizontal, cg.scrollColumn+lines)
+		} else {
+			cg.setScroll(Vertical, cg.scrollRow+lines)
+		}
+		return 0
+
+	case win.WM_SETCURSOR:
+		if win.LOWORD(uint32(lParam)) == win.HTCLIENT {
+			var pt win.POINT
+			win.GetCursorPos(&pt)
+			win.ScreenToClient(hwnd, &pt)
+
+			if cg.fillDragging || cg.fillHandleAt(int(pt.X), int(pt.Y)) {
+				win.SetCursor(CursorCross().handle())
+				return 1
+			}
+		}
+
+	case win.WM_LBUTTONDOWN:
+		if !cg.Enabled() {
+			break
+		}
+
+		cg.SetFocus()
+
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+		if cg.fillHandleAt(x, y) {
+			cg.fillDragging = true
+			cg.fillTarget = cg.selection[0]
+			win.SetCapture(hwnd)
+			break
+		}
+
+		row, column := cg.spanAt(Vertical, y, false), cg.spanAt(Horizontal, x, false)
+		rows, columns := cg.rowCount(), cg.columnCount()
+		if rows == 0 || columns == 0 {
+			break
+		}
+
+		switch {
+		case row == -1 && column == -1:
+			cg.SelectAll()
+
+		case row == -1:
+			// A column header selects whole columns.
+			first := column
+			if ShiftDown() {
+				first = cg.anchorColumn
+			}
+
+			cg.selectRange(0, first, cellRangeFromCells(0, first, rows-1, column), ControlDown())
+
+		case column == -1:
+			// A row header selects whole rows.
+			first := row
+			if ShiftDown() {
+				first = cg.anchorRow
+			}
+
+			cg.selectRange(first, 0, cellRangeFromCells(first, 0, row, columns-1), ControlDown())
+
+		case ControlDown():
+			cg.selectRange(row, column, cellRangeFromCells(row, column, row, column), true)
+
+		default:
+			cg.setCurrentCell(row, column, ShiftDown())
+		}
+
+		if row > -1 && column > -1 {
+			cg.selecting = true
+			win.SetCapture(hwnd)
+		}
+
+	case win.WM_MOUSEMOVE:
+		if !cg.selecting && !cg.fillDragging {
+			break
+		}
+
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+		cg.autoScroll(x, y)
+
+		row, column := cg.spanAt(Vertical, y, true), cg.spanAt(Horizontal, x, true)
+		if row < 0 || column < 0 {
+			break
+		}
+
+		if cg.fillDragging {
+			if target := cg.fillTargetFor(row, column); target != cg.fillTarget {
+				cg.fillTarget = target
+				cg.Invalidate()
+			}
+		} else if row != cg.currentRow || column != cg.currentColumn {
+			cg.setCurrentCell(row, column, true)
+		}
+
+	case win.WM_LBUTTONUP:
+		if !cg.selecting && !cg.fillDragging {
+			break
+		}
+
+		fillDragging, target := cg.fillDragging, cg.fillTarget
+		win.ReleaseCapture()
+
+		if fillDragging && target != cg.selection[0] {
+			cg.Fill(cg.selection[0], target)
+		}
+
+	case win.WM_CAPTURECHANGED:
+		if cg.fillDragging {
+			cg.Invalidate()
+		}
+
+		cg.selecting = false
+		cg.fillDragging = false
+
+	case win.WM_KEYDOWN:
+		if !cg.Enabled() || cg.rowCount() == 0 || cg.columnCount() == 0 {
+			break
+		}
+
+		if cg.handleKeyDown(Key(wParam)) {
+			return 0
+		}
+	}
+
+	return cg.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+// handleKeyDown moves the current cell or handles the clipboard shortcuts,
+// and returns if key was handled.
+func (cg *CellGrid) handleKeyDown(key Key) bool {
+	row, column := cg.currentRow, cg.currentColumn
+	rows, columns := cg.rowCount(), cg.columnCount()
+
+	_, _, _, start, end := cg.axis(Vertical)
+	page := maxi(1, (end-start-cg.frozenExtent(Vertical))/cg.rowHeightPixels()-1)
+
+	ctrl := ControlDown()
+
+	switch key {
+	case KeyLeft:
+		if column--; ctrl {
+			column = 0
+		}
+
+	case KeyRight:
+		if column++; ctrl {
+			column = columns - 1
+		}
+
+	case KeyUp:
+		if row--; ctrl {
+			row = 0
+		}
+
+	case KeyDown:
+		if row++; ctrl {
+			row = rows - 1
+		}
+
+	case KeyPrior:
+		row -= page
+
+	case KeyNext:
+		row += page
+
+	case KeyHome:
+		if column = 0; ctrl {
+			row = 0
+		}
+
+	case KeyEnd:
+		if column = columns - 1; ctrl {
+			row = rows - 1
+		}
+
+	case KeyA:
+		if !ctrl {
+			return false
+		}
+
+		cg.SelectAll()
+		return true
+
+	case KeyC:
+		if !ctrl {
+			return false
+		}
+
+		cg.Copy()
+		return true
+
+	case KeyV:
+		if !ctrl {
+			return false
+		}
+
+		cg.Paste()
+		return true
+
+	case KeyDelete:
+		cg.ClearSelectedCells()
+		return true
+
+	default:
+		return false
+	}
+
+	row = maxi(0, mini(row, rows-1))
+	column = maxi(0, mini(column, columns-1))
+
+	cg.setCurrentCell(row, column, ShiftDown())
+	cg.EnsureCellVisible(row, column)
+
+	return true
+}
+
+func (cg *CellGrid) paint(canvas *Canvas, bounds Rectangle) error {
+	windowBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_WINDOW)))
+	if err != nil {
+		return err
+	}
+	defer windowBrush.Dispose()
+
+	if err := canvas.FillRectanglePixels(windowBrush, bounds); err != nil {
+		return err
+	}
+
+	gridPen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNFACE)))
+	if err != nil {
+		return err
+	}
+	defer gridPen.Dispose()
+
+	shadowPen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer shadowPen.Dispose()
+
+	highlightColor := Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
+
+	highlightBrush, err := NewSolidColorBrush(highlightColor)
+	if err != nil {
+		return err
+	}
+	defer highlightBrush.Dispose()
+
+	highlightPen, err := NewCosmeticPen(PenSolid, highlightColor)
+	if err != nil {
+		return err
+	}
+	defer highlightPen.Dispose()
+
+	font := cg.Font()
+	textColor := Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+	highlightTextColor := Color(win.GetSysColor(win.COLOR_HIGHLIGHTTEXT))
+	headerTextColor := Color(win.GetSysColor(win.COLOR_BTNTEXT))
+	padding := cg.IntFrom96DPI(3)
+	textFormat := TextLeft | TextVCenter | TextSingleLine | TextEndEllipsis
+
+	rowSpans, columnSpans := cg.visibleSpans(Vertical), cg.visibleSpans(Horizontal)
+	headerWidth, headerHeight := cg.rowHeaderWidthPixels(), cg.rowHeightPixels()
+
+	for _, rs := range rowSpans {
+		for _, cs := range columnSpans {
+			b := Rectangle{cs.pos, rs.pos, cs.size, rs.size}
+			color := textColor
+
+			if cg.IsCellSelected(rs.index, cs.index) && (rs.index != cg.currentRow || cs.index != cg.currentColumn) {
+				if err := canvas.FillRectanglePixels(highlightBrush, b); err != nil {
+					return err
+				}
+				color = highlightTextColor
+			}
+
+			if text := cg.model.CellText(rs.index, cs.index); text != "" {
+				tb := Rectangle{b.X + padding, b.Y, b.Width - 2*padding, b.Height}
+				if err := canvas.DrawTextPixels(text, font, color, tb, textFormat); err != nil {
+					return err
+				}
+			}
+
+			right, bottom := b.X+b.Width-1, b.Y+b.Height-1
+			if err := canvas.DrawLinePixels(gridPen, Point{right, b.Y}, Point{right, bottom + 1}); err != nil {
+				return err
+			}
+			if err := canvas.DrawLinePixels(gridPen, Point{b.X, bottom}, Point{right + 1, bottom}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Headers
+	headerBrush := sysColorBtnFaceBrush
+
+	drawHeader := func(b Rectangle, title string, format DrawTextFormat) error {
+		if err := canvas.FillRectanglePixels(headerBrush, b); err != nil {
+			return err
+		}
+
+		tb := Rectangle{b.X + padding, b.Y, b.Width - 2*padding, b.Height}
+		if err := canvas.DrawTextPixels(title, font, headerTextColor, tb, format); err != nil {
+			return err
+		}
+
+		right, bottom := b.X+b.Width-1, b.Y+b.Height-1
+		if err := canvas.DrawLinePixels(shadowPen, Point{right, b.Y}, Point{right, bottom + 1}); err != nil {
+			return err
+		}
+
+		return canvas.DrawLinePixels(shadowPen, Point{b.X, bottom}, Point{right + 1, bottom})
+	}
+
+	headerFormat := TextCenter | TextVCenter | TextSingleLine | TextEndEllipsis
+
+	for _, cs := range columnSpans {
+		if err := drawHeader(Rectangle{cs.pos, 0, cs.size, headerHeight}, cg.columnTitle(cs.index), headerFormat); err != nil {
+			return err
+		}
+	}
+	for _, rs := range rowSpans {
+		if err := drawHeader(Rectangle{0, rs.pos, headerWidth, rs.size}, cg.rowTitle(rs.index), headerFormat); err != nil {
+			return err
+		}
+	}
+	if err := drawHeader(Rectangle{0, 0, headerWidth, headerHeight}, "", headerFormat); err != nil {
+		return err
+	}
+
+	// Frozen separators
+	if cg.frozenRows > 0 {
+		y := headerHeight + cg.frozenExtent(Vertical) - 1
+		if err := canvas.DrawLinePixels(shadowPen, Point{0, y}, Point{bounds.Width, y}); err != nil {
+			return err
+		}
+	}
+	if cg.frozenColumns > 0 {
+		x := headerWidth + cg.frozenExtent(Horizontal) - 1
+		if err := canvas.DrawLinePixels(shadowPen, Point{x, 0}, Point{x, bounds.Height}); err != nil {
+			return err
+		}
+	}
+
+	// Current cell, fill handle and fill target
+	if b, ok := cg.cellBounds(cg.currentRow, cg.currentColumn); ok && cg.rowCount() > 0 && cg.columnCount() > 0 {
+		if err := canvas.DrawRectanglePixels(highlightPen, b); err != nil {
+			return err
+		}
+		if err := canvas.DrawRectanglePixels(highlightPen, Rectangle{b.X + 1, b.Y + 1, b.Width - 2, b.Height - 2}); err != nil {
+			return err
+		}
+	}
+
+	if cg.fillDragging {
+		if b, ok := cg.rangeBounds(cg.fillTarget); ok {
+			dashPen, err := NewCosmeticPen(PenDash, Color(win.GetSysColor(win.COLOR_WINDOWTEXT)))
+			if err != nil {
+				return err
+			}
+			defer dashPen.Dispose()
+
+			if err := canvas.DrawRectanglePixels(dashPen, b); err != nil {
+				return err
+			}
+		}
+	}
+
+	if b, ok := cg.fillHandleBounds(); ok {
+		if err := canvas.FillRectanglePixels(highlightBrush, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cg *CellGrid) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	column := IntFrom96DPI(cg.defaultColumnWidth, ctx.dpi)
+	row := IntFrom96DPI(20, ctx.dpi)
+	header := IntFrom96DPI(48, ctx.dpi)
+	vsbw := int(win.GetSystemMetricsForDpi(win.SM_CXVSCROLL, uint32(ctx.dpi)))
+	hsbh := int(win.GetSystemMetricsForDpi(win.SM_CYHSCROLL, uint32(ctx.dpi)))
+
+	return &cellGridLayoutItem{
+		idealSize: Size{header + 5*column + vsbw, 11*row + hsbh},
+		minSize:   Size{header + column + vsbw, 2*row + hsbh},
+	}
+}
+
+type cellGridLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+	minSize   Size // in native pixels
+}
+
+func (*cellGridLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | GrowableHorz | GreedyHorz | ShrinkableVert | GrowableVert | GreedyVert
+}
+
+func (li *cellGridLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *cellGridLayoutItem) MinSize() Size {
+	return li.minSize
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// CellRange is a rectangular range of cells of a CellGrid.
+type CellRange struct {
+	Row         int
+	Column      int
+	RowCount    int
+	ColumnCount int
+}
+
+// cellRangeFromCells returns the range spanning the cells row1, column1 and
+// row2, column2 in any order.
+func cellRangeFromCells(row1, column1, row2, column2 int) CellRange {
+	if row2 < row1 {
+		row1, row2 = row2, row1
+	}
+	if column2 < column1 {
+		column1, column2 = column2, column1
+	}
+
+	return CellRange{row1, column1, row2 - row1 + 1, column2 - column1 + 1}
+}
+
+// Contains returns if the cell at row and column lies within the range.
+func (r CellRange) Contains(row, column int) bool {
+	return row >= r.Row && row < r.Row+r.RowCount && column >= r.Column && column < r.Column+r.ColumnCount
+}
+
+// IsEmpty returns if the range contains no cells.
+func (r CellRange) IsEmpty() bool {
+	return r.RowCount <= 0 || r.ColumnCount <= 0
+}
+
+func (r CellRange) lastRow() int {
+	return r.Row + r.RowCount - 1
+}
+
+func (r CellRange) lastColumn() int {
+	return r.Column + r.ColumnCount - 1
+}
+
+// CellGridModel is the interface that a model must implement to support the
+// CellGrid widget.
+type CellGridModel interface {
+	// RowCount returns the number of rows in the model.
+	RowCount() int
+
+	// ColumnCount returns the number of columns in the model.
+	ColumnCount() int
+
+	// CellText returns the text to display for the cell at row and column.
+	CellText(row, column int) string
+
+	// CellsChanged returns the event that the model should publish when the
+	// number of rows or columns, or the text of any cell changed.
+	CellsChanged() *Event
+}
+
+// CellTextSetter is the interface that a CellGridModel must implement to
+// support pasting into, filling and clearing cells of a CellGrid.
+type CellTextSetter interface {
+	// SetCellText sets the text of the cell at row and column.
+	SetCellText(row, column int, text string) error
+}
+
+// CellFiller is an optional interface that a CellGridModel can implement to
+// customize what dragging the fill handle of a CellGrid does, e.g. to continue
+// a series of numbers. Without it, the cells of source are repeated.
+type CellFiller interface {
+	// FillCells fills the cells of target, which contains source, outside of
+	// source, based on the cells of source.
+	FillCells(source, target CellRange) error
+}
+
+// CellGridHeaderModel is an optional interface that a CellGridModel can
+// implement to provide the titles of the row and column headers. Without it,
+// rows are numbered from 1 and columns are lettered from A.
+type CellGridHeaderModel interface {
+	// RowTitle returns the title of the header of row.
+	RowTitle(row int) string
+
+	// ColumnTitle returns the title of the header of column.
+	ColumnTitle(column int) string
+}
+
+// CellGridModelBase implements the CellsChanged method of the CellGridModel
+// interface.
+type CellGridModelBase struct {
+	cellsChangedPublisher EventPublisher
+}
+
+func (cgmb *CellGridModelBase) CellsChanged() *Event {
+	return cgmb.cellsChangedPublisher.Event()
+}
+
+func (cgmb *CellGridModelBase) PublishCellsChanged() {
+	cgmb.cellsChangedPublisher.Publish()
+}
@@ -0,0 +1,698 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/miu200521358/win"
+)
+
+const chartWindowClass = `\o/ Walk_Chart_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClassWithStyle(chartWindowClass, win.CS_DBLCLKS)
+	})
+}
+
+// ChartRange is a rectangular range of chart data coordinates.
+type ChartRange struct {
+	XMin, XMax float64
+	YMin, YMax float64
+}
+
+func (r ChartRange) valid() bool {
+	return r.XMax > r.XMin && r.YMax > r.YMin
+}
+
+// Chart is a widget that draws the line, bar and scatter series of a
+// ChartModel, together with axes, tick labels and a legend.
+//
+// The visible range of data coordinates, the view, follows the data of the
+// model, until it is changed by calling SetView or by the user. Turning the
+// mouse wheel zooms around the mouse pointer, only horizontally with the Shift
+// key or only vertically with the Ctrl key held down. Dragging pans the view
+// and double-clicking resets it.
+type Chart struct {
+	WidgetBase
+	model                ChartModel
+	seriesResetHandle    int
+	seriesChangedHandle  int
+	legendVisible        bool
+	view                 ChartRange
+	viewSet              bool
+	dragging             bool
+	dragOrigin           win.POINT
+	dragView             ChartRange
+	plotBounds           Rectangle // in native pixels
+	viewChangedPublisher EventPublisher
+}
+
+// NewChart creates and initializes a new Chart.
+func NewChart(parent Container) (*Chart, error) {
+	c := &Chart{legendVisible: true}
+
+	if err := InitWidget(
+		c,
+		parent,
+		chartWindowClass,
+		win.WS_TABSTOP|win.WS_VISIBLE,
+		0); err != nil {
+		return nil, err
+	}
+
+	c.GraphicsEffects().Add(InteractionEffect)
+	c.GraphicsEffects().Add(FocusEffect)
+
+	return c, nil
+}
+
+func (c *Chart) Dispose() {
+	c.SetModel(nil)
+
+	c.WidgetBase.Dispose()
+}
+
+// Model returns the ChartModel that provides the data of the Chart.
+func (c *Chart) Model() ChartModel {
+	return c.model
+}
+
+// SetModel sets the ChartModel that provides the data of the Chart.
+func (c *Chart) SetModel(model ChartModel) {
+	if c.model != nil {
+		c.model.SeriesReset().Detach(c.seriesResetHandle)
+		c.model.SeriesChanged().Detach(c.seriesChangedHandle)
+	}
+
+	c.model = model
+
+	if model != nil {
+		c.seriesResetHandle = model.SeriesReset().Attach(c.onModelChanged)
+		c.seriesChangedHandle = model.SeriesChanged().Attach(func(series int) {
+			c.onModelChanged()
+		})
+	}
+
+	c.onModelChanged()
+}
+
+func (c *Chart) onModelChanged() {
+	if !c.viewSet {
+		c.viewChangedPublisher.Publish()
+	}
+
+	c.Invalidate()
+}
+
+// LegendVisible returns whether the legend is drawn.
+func (c *Chart) LegendVisible() bool {
+	return c.legendVisible
+}
+
+// SetLegendVisible sets whether the legend is drawn.
+func (c *Chart) SetLegendVisible(visible bool) {
+	if visible == c.legendVisible {
+		return
+	}
+
+	c.legendVisible = visible
+
+	c.Invalidate()
+}
+
+// DataRange returns the range that encloses all points of the model.
+func (c *Chart) DataRange() ChartRange {
+	r := ChartRange{math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)}
+
+	extend := func(x, y float64) {
+		r.XMin = math.Min(r.XMin, x)
+		r.XMax = math.Max(r.XMax, x)
+		r.YMin = math.Min(r.YMin, y)
+		r.YMax = math.Max(r.YMax, y)
+	}
+
+	if c.model != nil {
+		barSpacing := c.barSpacing()
+
+		for s := c.model.SeriesCount() - 1; s >= 0; s-- {
+			bar := c.model.SeriesKind(s) == ChartBar
+
+			for i := c.model.PointCount(s) - 1; i >= 0; i-- {
+				p := c.model.Point(s, i)
+				if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+					continue
+				}
+
+				if bar {
+					extend(p.X-barSpacing/2, 0)
+					extend(p.X+barSpacing/2, p.Y)
+				} else {
+					extend(p.X, p.Y)
+				}
+			}
+		}
+	}
+
+	if r.XMin > r.XMax {
+		return ChartRange{0, 1, 0, 1}
+	}
+
+	if r.XMin == r.XMax {
+		r.XMin--
+		r.XMax++
+	}
+
+	if r.YMin == r.YMax {
+		r.YMin--
+		r.YMax++
+	} else {
+		padding := (r.YMax - r.YMin) * 0.05
+		if r.YMin != 0 {
+			r.YMin -= padding
+		}
+		if r.YMax != 0 {
+			r.YMax += padding
+		}
+	}
+
+	return r
+}
+
+// barSpacing returns the smallest distance along the x axis between two
+// points of the bar series, which determines the width of the bars.
+func (c *Chart) barSpacing() float64 {
+	spacing := math.Inf(1)
+
+	for s := c.model.SeriesCount() - 1; s >= 0; s-- {
+		if c.model.SeriesKind(s) != ChartBar {
+			continue
+		}
+
+		for i := c.model.PointCount(s) - 1; i > 0; i-- {
+			if d := math.Abs(c.model.Point(s, i).X - c.model.Point(s, i-1).X); d > 0 && d < spacing {
+				spacing = d
+			}
+		}
+	}
+
+	if math.IsInf(spacing, 1) {
+		return 1
+	}
+
+	return spacing
+}
+
+// View returns the range of data coordinates that is visible.
+func (c *Chart) View() ChartRange {
+	if c.viewSet {
+		return c.view
+	}
+
+	return c.DataRange()
+}
+
+// SetView sets the range of data coordinates that is visible.
+func (c *Chart) SetView(view ChartRange) error {
+	if !view.valid() {
+		return newError("invalid view")
+	}
+
+	if c.viewSet && view == c.view {
+		return nil
+	}
+
+	c.view = view
+	c.viewSet = true
+
+	c.Invalidate()
+
+	c.viewChangedPublisher.Publish()
+
+	return nil
+}
+
+// ResetView makes the view follow the data of the model again.
+func (c *Chart) ResetView() {
+	if !c.viewSet {
+		return
+	}
+
+	c.viewSet = false
+
+	c.Invalidate()
+
+	c.viewChangedPublisher.Publish()
+}
+
+// ViewChanged returns the event that is published when the view changed.
+func (c *Chart) ViewChanged() *Event {
+	return c.viewChangedPublisher.Event()
+}
+
+func (c *Chart) zoom(pt win.POINT, factor float64, horizontal, vertical bool) {
+	view := c.View()
+	pb := c.plotBounds
+	if pb.Width <= 0 || pb.Height <= 0 {
+		return
+	}
+
+	if horizontal {
+		x := view.XMin + float64(int(pt.X)-pb.X)/float64(pb.Width)*(view.XMax-view.XMin)
+		view.XMin = x - (x-view.XMin)*factor
+		view.XMax = x + (view.XMax-x)*factor
+	}
+
+	if vertical {
+		y := view.YMax - float64(int(pt.Y)-pb.Y)/float64(pb.Height)*(view.YMax-view.YMin)
+		view.YMin = y - (y-view.YMin)*factor
+		view.YMax = y + (view.YMax-y)*factor
+	}
+
+	c.SetView(view)
+}
+
+func (c *Chart) pan(pt win.POINT) {
+	view := c.dragView
+	pb := c.plotBounds
+	if pb.Width <= 0 || pb.Height <= 0 {
+		return
+	}
+
+	dx := float64(pt.X-c.dragOrigin.X) / float64(pb.Width) * (view.XMax - view.XMin)
+	dy := float64(pt.Y-c.dragOrigin.Y) / float64(pb.Height) * (view.YMax - view.YMin)
+
+	view.XMin -= dx
+	view.XMax -= dx
+	view.YMin += dy
+	view.YMax += dy
+
+	c.SetView(view)
+}
+
+func (c *Chart) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, c.DPI(), c.paint) {
+			return 0
+		}
+
+	case win.WM_LBUTTONDOWN:
+		c.SetFocus()
+		win.SetCapture(hwnd)
+		c.dragging = true
+		c.dragOrigin = win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}
+		c.dragView = c.View()
+
+	case win.WM_MOUSEMOVE:
+		if c.dragging {
+			c.pan(win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)})
+		}
+
+	case win.WM_LBUTTONUP:
+		if c.dragging {
+			c.dragging = false
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		c.dragging = false
+
+	case win.WM_LBUTTONDBLCLK:
+		c.ResetView()
+
+	case win.WM_MOUSEWHEEL:
+		delta := float64(int16(win.HIWORD(uint32(wParam))))
+		if delta == 0 {
+			break
+		}
+
+		pt := win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}
+		win.ScreenToClient(hwnd, &pt)
+
+		keys := win.LOWORD(uint32(wParam))
+		horizontal := keys&win.MK_CONTROL == 0
+		vertical := keys&win.MK_SHIFT == 0
+
+		c.zoom(pt, math.Pow(0.8, delta/120), horizontal, vertical)
+
+		return 0
+	}
+
+	return c.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (c *Chart) paint(canvas *Canvas, bounds Rectangle) error {
+	dpi := c.DPI()
+	font := c.Font()
+
+	bg, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_WINDOW)))
+	if err != nil {
+		return err
+	}
+	defer bg.Dispose()
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	textColor := Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+	if !c.Enabled() {
+		textColor = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	view := c.View()
+
+	textsize, _, err := canvas.MeasureTextPixels("0", font, bounds, TextSingleLine)
+	if err != nil {
+		return err
+	}
+	lineHeight := textsize.Height
+
+	padding := IntFrom96DPI(6, dpi)
+	tickLength := IntFrom96DPI(4, dpi)
+
+	yTicks, yStep := chartTicks(view.YMin, view.YMax, maxi(2, bounds.Height/(3*lineHeight)))
+
+	var yLabelWidth int
+	for _, v := range yTicks {
+		r, _, err := canvas.MeasureTextPixels(formatChartTick(v, yStep), font, bounds, TextSingleLine)
+		if err != nil {
+			return err
+		}
+
+		yLabelWidth = maxi(yLabelWidth, r.Width)
+	}
+
+	pb := Rectangle{
+		X: bounds.X + padding + yLabelWidth + tickLength,
+		Y: bounds.Y + padding + lineHeight/2,
+	}
+	pb.Width = bounds.Width - (pb.X - bounds.X) - padding
+	pb.Height = bounds.Height - (pb.Y - bounds.Y) - padding - lineHeight - tickLength
+	if pb.Width <= 0 || pb.Height <= 0 {
+		return nil
+	}
+
+	c.plotBounds = pb
+
+	toPixels := func(p ChartPoint) Point {
+		return Point{
+			pb.X + int(math.Round((p.X-view.XMin)/(view.XMax-view.XMin)*float64(pb.Width))),
+			pb.Y + pb.Height - int(math.Round((p.Y-view.YMin)/(view.YMax-view.YMin)*float64(pb.Height))),
+		}
+	}
+
+	gridColor := Color(win.GetSysColor(win.COLOR_3DLIGHT))
+	axisColor := Color(win.GetSysColor(win.COLOR_BTNSHADOW))
+
+	gridPen, err := NewCosmeticPen(PenDot, gridColor)
+	if err != nil {
+		return err
+	}
+	defer gridPen.Dispose()
+
+	axisPen, err := NewCosmeticPen(PenSolid, axisColor)
+	if err != nil {
+		return err
+	}
+	defer axisPen.Dispose()
+
+	for _, v := range yTicks {
+		y := toPixels(ChartPoint{view.XMin, v}).Y
+
+		if err := canvas.DrawLinePixels(gridPen, Point{pb.X, y}, Point{pb.X + pb.Width, y}); err != nil {
+			return err
+		}
+		if err := canvas.DrawLinePixels(axisPen, Point{pb.X - tickLength, y}, Point{pb.X, y}); err != nil {
+			return err
+		}
+
+		labelBounds := Rectangle{bounds.X + padding, y - lineHeight/2, yLabelWidth, lineHeight}
+		if err := canvas.DrawTextPixels(formatChartTick(v, yStep), font, textColor, labelBounds, TextRight|TextSingleLine|TextNoClip); err != nil {
+			return err
+		}
+	}
+
+	var xLabelWidth int
+	for _, v := range []float64{view.XMin, view.XMax} {
+		_, step := chartTicks(view.XMin, view.XMax, 10)
+		r, _, err := canvas.MeasureTextPixels(formatChartTick(v, step), font, bounds, TextSingleLine)
+		if err != nil {
+			return err
+		}
+
+		xLabelWidth = maxi(xLabelWidth, r.Width)
+	}
+
+	xTicks, xStep := chartTicks(view.XMin, view.XMax, maxi(2, pb.Width/(xLabelWidth+2*padding)))
+
+	for _, v := range xTicks {
+		x := toPixels(ChartPoint{v, view.YMin}).X
+
+		if err := canvas.DrawLinePixels(gridPen, Point{x, pb.Y}, Point{x, pb.Y + pb.Height}); err != nil {
+			return err
+		}
+		if err := canvas.DrawLinePixels(axisPen, Point{x, pb.Y + pb.Height}, Point{x, pb.Y + pb.Height + tickLength}); err != nil {
+			return err
+		}
+
+		labelBounds := Rectangle{x - xLabelWidth, pb.Y + pb.Height + tickLength, 2 * xLabelWidth, lineHeight}
+		if err := canvas.DrawTextPixels(formatChartTick(v, xStep), font, textColor, labelBounds, TextCenter|TextSingleLine|TextNoClip); err != nil {
+			return err
+		}
+	}
+
+	if c.model != nil {
+		saved := win.SaveDC(canvas.hdc)
+		win.IntersectClipRect(canvas.hdc, int32(pb.X), int32(pb.Y), int32(pb.X+pb.Width+1), int32(pb.Y+pb.Height+1))
+
+		err := c.paintSeries(canvas, view, toPixels)
+
+		win.RestoreDC(canvas.hdc, saved)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := canvas.DrawRectanglePixels(axisPen, Rectangle{pb.X, pb.Y, pb.Width + 1, pb.Height + 1}); err != nil {
+		return err
+	}
+
+	if c.legendVisible && c.model != nil && c.model.SeriesCount() > 0 {
+		return c.paintLegend(canvas, pb, lineHeight, textColor, bg, axisPen)
+	}
+
+	return nil
+}
+
+func (c *Chart) paintSeries(canvas *Canvas, view ChartRange, toPixels func(p ChartPoint) Point) error {
+	dpi := c.DPI()
+
+	var barSeries []int
+	for s := 0; s < c.model.SeriesCount(); s++ {
+		if c.model.SeriesKind(s) == ChartBar {
+			barSeries = append(barSeries, s)
+		}
+	}
+
+	groupWidth := c.barSpacing() * 0.8
+
+	for bi, s := range barSeries {
+		brush, err := NewSolidColorBrush(c.model.SeriesColor(s))
+		if err != nil {
+			return err
+		}
+
+		barWidth := groupWidth / float64(len(barSeries))
+
+		for i := 0; i < c.model.PointCount(s); i++ {
+			p := c.model.Point(s, i)
+			x := p.X - groupWidth/2 + float64(bi)*barWidth
+
+			p1 := toPixels(ChartPoint{x, math.Max(p.Y, 0)})
+			p2 := toPixels(ChartPoint{x + barWidth, math.Min(p.Y, 0)})
+
+			if err := canvas.FillRectanglePixels(brush, Rectangle{p1.X, p1.Y, maxi(1, p2.X-p1.X), maxi(1, p2.Y-p1.Y)}); err != nil {
+				brush.Dispose()
+				return err
+			}
+		}
+
+		brush.Dispose()
+	}
+
+	for s := 0; s < c.model.SeriesCount(); s++ {
+		kind := c.model.SeriesKind(s)
+		if kind == ChartBar {
+			continue
+		}
+
+		brush, err := NewSolidColorBrush(c.model.SeriesColor(s))
+		if err != nil {
+			return err
+		}
+
+		err = func() error {
+			defer brush.Dispose()
+
+			count := c.model.PointCount(s)
+
+			if kind == ChartScatter {
+				r := IntFrom96DPI(3, dpi)
+
+				for i := 0; i < count; i++ {
+					p := toPixels(c.model.Point(s, i))
+
+					if err := canvas.FillEllipsePixels(brush, Rectangle{p.X - r, p.Y - r, 2 * r, 2 * r}); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			}
+
+			pen, err := NewGeometricPen(PenSolid|PenCapRound|PenJoinRound, IntFrom96DPI(2, dpi), brush)
+			if err != nil {
+				return err
+			}
+			defer pen.Dispose()
+
+			points := make([]Point, count)
+			for i := range points {
+				points[i] = toPixels(c.model.Point(s, i))
+			}
+
+			return canvas.DrawPolylinePixels(pen, points)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Chart) paintLegend(canvas *Canvas, pb Rectangle, lineHeight int, textColor Color, bg Brush, borderPen Pen) error {
+	dpi := c.DPI()
+	font := c.Font()
+
+	padding := IntFrom96DPI(4, dpi)
+	swatch := lineHeight * 2 / 3
+
+	var textWidth int
+	for s := 0; s < c.model.SeriesCount(); s++ {
+		r, _, err := canvas.MeasureTextPixels(c.model.SeriesName(s), font, pb, TextSingleLine)
+		if err != nil {
+			return err
+		}
+
+		textWidth = maxi(textWidth, r.Width)
+	}
+
+	width := 3*padding + swatch + textWidth
+	height := 2*padding + c.model.SeriesCount()*lineHeight
+
+	lb := Rectangle{pb.X + pb.Width - width - padding, pb.Y + padding, width, height}
+
+	if err := canvas.FillRectanglePixels(bg, lb); err != nil {
+		return err
+	}
+	if err := canvas.DrawRectanglePixels(borderPen, lb); err != nil {
+		return err
+	}
+
+	for s := 0; s < c.model.SeriesCount(); s++ {
+		y := lb.Y + padding + s*lineHeight
+
+		brush, err := NewSolidColorBrush(c.model.SeriesColor(s))
+		if err != nil {
+			return err
+		}
+
+		swatchBounds := Rectangle{lb.X + padding, y + (lineHeight-swatch)/2, swatch, swatch}
+		if c.model.SeriesKind(s) == ChartScatter {
+			err = canvas.FillEllipsePixels(brush, swatchBounds)
+		} else {
+			err = canvas.FillRectanglePixels(brush, swatchBounds)
+		}
+		brush.Dispose()
+		if err != nil {
+			return err
+		}
+
+		textBounds := Rectangle{lb.X + 2*padding + swatch, y, textWidth, lineHeight}
+		if err := canvas.DrawTextPixels(c.model.SeriesName(s), font, textColor, textBounds, TextLeft|TextVCenter|TextSingleLine); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (*Chart) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return NewGreedyLayoutItem()
+}
+
+// chartTicks returns the positions of at most about maxCount ticks between min
+// and max, placed at multiples of 1, 2 or 5 times a power of 10.
+func chartTicks(min, max float64, maxCount int) (ticks []float64, step float64) {
+	if !(max > min) || maxCount < 1 {
+		return nil, 0
+	}
+
+	raw := (max - min) / float64(maxCount)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+
+	switch norm := raw / magnitude; {
+	case norm <= 1:
+		step = magnitude
+
+	case norm <= 2:
+		step = 2 * magnitude
+
+	case norm <= 5:
+		step = 5 * magnitude
+
+	default:
+		step = 10 * magnitude
+	}
+
+	first := math.Ceil(min/step) * step
+	for i := 0; i <= maxCount*2+1; i++ {
+		v := first + float64(i)*step
+		if v > max+step*1e-9 {
+			break
+		}
+
+		ticks = append(ticks, v)
+	}
+
+	return ticks, step
+}
+
+// formatChartTick formats the tick value v with as many decimals as step
+// requires.
+func formatChartTick(v, step float64) string {
+	decimals := 0
+	if step > 0 && step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step) - 1e-9))
+	}
+
+	if math.Abs(v) < step*1e-6 {
+		v = 0
+	}
+
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
@@ -25,7 +25,10 @@ var checkBoxCheckSize Size // in native pixels
 
 type CheckBox struct {
 	Button
-	checkStateChangedPublisher EventPublisher
+	checkStateChangedPublisher         EventPublisher
+	checkStateChangedDetailedPublisher ValueChangedEventPublisher
+	lastCheckState                     CheckState // published last
+	clickSource                        ValueChangeSource
 }
 
 func NewCheckBox(parent Container) (*CheckBox, error) {
@@ -42,6 +45,9 @@ func NewCheckBox(parent Container) (*CheckBox, error) {
 
 	cb.Button.init()
 
+	cb.clickSource = ValueChangeSourceKeyboard
+	cb.lastCheckState = cb.CheckState()
+
 	cb.SetBackground(nullBrushSingleton)
 
 	cb.GraphicsEffects().Add(InteractionEffect)
@@ -72,7 +78,7 @@ func (cb *CheckBox) SetTextOnLeftSide(textLeft bool) error {
 func (cb *CheckBox) setChecked(checked bool) {
 	cb.Button.setChecked(checked)
 
-	cb.checkStateChangedPublisher.Publish()
+	cb.publishCheckStateChanged(ValueChangeSourceProgrammatic)
 }
 
 func (cb *CheckBox) Tristate() bool {
@@ -102,13 +108,31 @@ func (cb *CheckBox) SetCheckState(state CheckState) {
 	cb.SendMessage(win.BM_SETCHECK, uintptr(state), 0)
 
 	cb.checkedChangedPublisher.Publish()
-	cb.checkStateChangedPublisher.Publish()
+	cb.publishCheckStateChanged(ValueChangeSourceProgrammatic)
 }
 
 func (cb *CheckBox) CheckStateChanged() *Event {
 	return cb.checkStateChangedPublisher.Event()
 }
 
+// CheckStateChangedDetailed returns an Event that is published along with
+// CheckStateChanged if CheckState actually changed, with the old and new
+// CheckState and how it was changed, e.g. to record changes for undo.
+func (cb *CheckBox) CheckStateChangedDetailed() *ValueChangedEvent {
+	return cb.checkStateChangedDetailedPublisher.Event()
+}
+
+func (cb *CheckBox) publishCheckStateChanged(source ValueChangeSource) {
+	cb.checkStateChangedPublisher.Publish()
+
+	if state := cb.CheckState(); state != cb.lastCheckState {
+		oldState := cb.lastCheckState
+		cb.lastCheckState = state
+
+		cb.checkStateChangedDetailedPublisher.Publish(ValueChange{OldValue: oldState, NewValue: state, Source: source})
+	}
+}
+
 func (cb *CheckBox) SaveState() error {
 	return cb.WriteState(strconv.Itoa(int(cb.CheckState())))
 }
@@ -135,8 +159,14 @@ func (cb *CheckBox) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 		switch win.HIWORD(uint32(wParam)) {
 		case win.BN_CLICKED:
 			cb.checkedChangedPublisher.Publish()
-			cb.checkStateChangedPublisher.Publish()
+			cb.publishCheckStateChanged(cb.clickSource)
+
+			// Clicks by mnemonic don't come with a key down.
+			cb.clickSource = ValueChangeSourceKeyboard
 		}
+
+	case win.WM_LBUTTONDOWN:
+		cb.clickSource = ValueChangeSourceMouse
 	}
 
 	return cb.Button.WndProc(hwnd, msg, wParam, lParam)
@@ -8,7 +8,11 @@
 package walk
 
 import (
+	"bytes"
+	"image"
+	"image/png"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"github.com/miu200521358/win"
@@ -58,6 +62,13 @@ func clipboardWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
 
 var clipboard ClipboardService
 
+var (
+	registerClipboardFormat     = syscall.NewLazyDLL("user32.dll").NewProc("RegisterClipboardFormatW")
+	getClipboardSequenceNumber  = syscall.NewLazyDLL("user32.dll").NewProc("GetClipboardSequenceNumber")
+	clipboardSecretFormatNames  = []string{"ExcludeClipboardContentFromMonitorProcessing", "CanIncludeInClipboardHistory", "CanUploadToCloudClipboard"}
+	clipboardSecretFormatValues = []uint32{0, 0, 0}
+)
+
 // Clipboard returns an object that provides access to the system clipboard.
 func Clipboard() *ClipboardService {
 	return &clipboard
@@ -67,6 +78,7 @@ func Clipboard() *ClipboardService {
 type ClipboardService struct {
 	hwnd                     win.HWND
 	contentsChangedPublisher EventPublisher
+	secretClearTimer         *UITimer
 }
 
 // ContentsChanged returns an Event that you can attach to for handling
@@ -127,31 +139,409 @@ func (c *ClipboardService) SetText(s string) error {
 			return err
 		}
 
-		hMem := win.GlobalAlloc(win.GMEM_MOVEABLE, uintptr(len(utf16)*2))
-		if hMem == 0 {
-			return lastError("GlobalAlloc")
+		return c.setData(win.CF_UNICODETEXT, unsafe.Pointer(&utf16[0]), uintptr(len(utf16)*2))
+	})
+}
+
+// SetSecretText sets the current text data of the clipboard to a secret, e.g.
+// a password or an access token.
+//
+// The text is kept out of the clipboard history and cloud clipboard of
+// Windows and marked for clipboard monitors to ignore. If clearAfter is
+// greater than 0, the clipboard is cleared after that time, unless its
+// contents were replaced in the meantime.
+//
+// It must be called on the UI thread.
+func (c *ClipboardService) SetSecretText(s string, clearAfter time.Duration) error {
+	err := c.withOpenClipboard(func() error {
+		if !win.EmptyClipboard() {
+			return lastError("EmptyClipboard")
 		}
 
-		p := win.GlobalLock(hMem)
-		if p == nil {
-			return lastError("GlobalLock()")
+		utf16, err := syscall.UTF16FromString(s)
+		if err != nil {
+			return err
+		}
+		defer zeroUTF16(utf16)
+
+		if err := c.setData(win.CF_UNICODETEXT, unsafe.Pointer(&utf16[0]), uintptr(len(utf16)*2)); err != nil {
+			return err
+		}
+
+		for i, name := range clipboardSecretFormatNames {
+			format, _, _ := registerClipboardFormat.Call(uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(name))))
+			if format == 0 {
+				continue
+			}
+
+			if err := c.setData(uint32(format), unsafe.Pointer(&clipboardSecretFormatValues[i]), 4); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.secretClearTimer != nil {
+		c.secretClearTimer.Dispose()
+		c.secretClearTimer = nil
+	}
+
+	if clearAfter <= 0 {
+		return nil
+	}
+
+	seq, _, _ := getClipboardSequenceNumber.Call()
+
+	var t *UITimer
+	t, err = NewUITimer(clearAfter, func() {
+		t.Dispose()
+		if c.secretClearTimer == t {
+			c.secretClearTimer = nil
+		}
+
+		if cur, _, _ := getClipboardSequenceNumber.Call(); cur == seq {
+			c.Clear()
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	c.secretClearTimer = t
+
+	return nil
+}
+
+// ContainsFormat returns whether the clipboard currently contains data in
+// format, e.g. one registered with RegisterDataFormat.
+func (c *ClipboardService) ContainsFormat(format DataFormat) (available bool, err error) {
+	err = c.withOpenClipboard(func() error {
+		available = win.IsClipboardFormatAvailable(uint32(format))
+
+		return nil
+	})
+
+	return
+}
+
+// Data returns the raw bytes of the clipboard data in format, or nil if the
+// clipboard contains none. Windows may round up the size of the data, so
+// formats of variable length should encode it.
+func (c *ClipboardService) Data(format DataFormat) (data []byte, err error) {
+	err = c.withOpenClipboard(func() error {
+		data, err = c.data(format)
+
+		return err
+	})
+
+	return
+}
+
+// SetData replaces the contents of the clipboard with data in format, e.g.
+// one registered with RegisterDataFormat.
+func (c *ClipboardService) SetData(format DataFormat, data []byte) error {
+	return c.withOpenClipboard(func() error {
+		if !win.EmptyClipboard() {
+			return lastError("EmptyClipboard")
 		}
 
-		win.MoveMemory(p, unsafe.Pointer(&utf16[0]), uintptr(len(utf16)*2))
+		return c.setBytes(format, data)
+	})
+}
 
-		win.GlobalUnlock(hMem)
+// HTML returns the HTML fragment of the clipboard, or "" if it contains
+// none.
+func (c *ClipboardService) HTML() (html string, err error) {
+	data, err := c.Data(DataFormatHTML)
+	if err != nil || data == nil {
+		return "", err
+	}
 
-		if 0 == win.SetClipboardData(win.CF_UNICODETEXT, win.HANDLE(hMem)) {
-			// We need to free hMem.
-			defer win.GlobalFree(hMem)
+	html, ok := htmlFragment(data)
+	if !ok {
+		return "", newError("invalid HTML Format data")
+	}
 
-			return lastError("SetClipboardData")
+	return html, nil
+}
+
+// SetHTML replaces the contents of the clipboard with an HTML fragment, e.g.
+// for rich text editors and browsers. plainText is set as text for
+// applications that don't understand HTML, unless it is "".
+func (c *ClipboardService) SetHTML(fragment, plainText string) error {
+	return c.withOpenClipboard(func() error {
+		if !win.EmptyClipboard() {
+			return lastError("EmptyClipboard")
 		}
 
-		// The system now owns the memory referred to by hMem.
+		if err := c.setBytes(DataFormatHTML, htmlFormatData(fragment)); err != nil {
+			return err
+		}
+
+		if plainText == "" {
+			return nil
+		}
+
+		return c.setBytes(DataFormatText, utf16Bytes(plainText))
+	})
+}
+
+// Files returns the paths of the files on the clipboard, e.g. copied in
+// Windows Explorer, or nil if it contains none.
+func (c *ClipboardService) Files() (files []string, err error) {
+	err = c.withOpenClipboard(func() error {
+		if !win.IsClipboardFormatAvailable(win.CF_HDROP) {
+			return nil
+		}
+
+		hDrop := win.HDROP(win.GetClipboardData(win.CF_HDROP))
+		if hDrop == 0 {
+			return lastError("GetClipboardData")
+		}
+
+		files = filesFromHDROP(hDrop)
 
 		return nil
 	})
+
+	return
+}
+
+// SetFiles replaces the contents of the clipboard with the paths of files, so
+// they can be pasted in Windows Explorer.
+func (c *ClipboardService) SetFiles(paths []string) error {
+	return c.withOpenClipboard(func() error {
+		if !win.EmptyClipboard() {
+			return lastError("EmptyClipboard")
+		}
+
+		return c.setBytes(DataFormatFiles, dropFilesData(paths))
+	})
+}
+
+// Image returns the image of the clipboard, or nil if it contains none.
+//
+// The "PNG" format, which browsers and image editors provide, is preferred
+// because it keeps transparency.
+func (c *ClipboardService) Image() (*Bitmap, error) {
+	var im image.Image
+
+	err := c.withOpenClipboard(func() error {
+		data, err := c.data(RegisterDataFormat("PNG"))
+		if err != nil {
+			return err
+		}
+		if data != nil {
+			im, err = png.Decode(bytes.NewReader(data))
+			return err
+		}
+
+		if !win.IsClipboardFormatAvailable(win.CF_BITMAP) {
+			return nil
+		}
+
+		hBmp := win.HBITMAP(win.GetClipboardData(win.CF_BITMAP))
+		if hBmp == 0 {
+			return lastError("GetClipboardData")
+		}
+
+		im, err = imageFromClipboardBitmap(hBmp)
+
+		return err
+	})
+	if err != nil || im == nil {
+		return nil, err
+	}
+
+	return NewBitmapFromImageForDPI(im, 96)
+}
+
+// SetImage replaces the contents of the clipboard with an image.
+//
+// The image is set in the "PNG" format, to keep its transparency, and as
+// device independent bitmap on white for other applications.
+func (c *ClipboardService) SetImage(im Image) error {
+	bmp, err := BitmapFrom(im, 96)
+	if err != nil {
+		return err
+	}
+	if bmp == nil {
+		return newError("im must not be nil")
+	}
+
+	rgba, err := bmp.ToImage()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, rgba); err != nil {
+		return err
+	}
+
+	return c.withOpenClipboard(func() error {
+		if !win.EmptyClipboard() {
+			return lastError("EmptyClipboard")
+		}
+
+		if err := c.setBytes(RegisterDataFormat("PNG"), buf.Bytes()); err != nil {
+			return err
+		}
+
+		return c.setBytes(win.CF_DIB, dibFromImage(rgba))
+	})
+}
+
+// imageFromClipboardBitmap returns the pixels of hBmp, which is owned by the
+// clipboard. Bitmaps without alpha channel are made opaque.
+func imageFromClipboardBitmap(hBmp win.HBITMAP) (*image.NRGBA, error) {
+	var bm win.BITMAP
+	if win.GetObject(win.HGDIOBJ(hBmp), unsafe.Sizeof(bm), unsafe.Pointer(&bm)) == 0 {
+		return nil, newError("GetObject failed")
+	}
+
+	width, height := int(bm.BmWidth), int(bm.BmHeight)
+	if width <= 0 || height <= 0 {
+		return nil, newError("invalid clipboard bitmap size")
+	}
+
+	var bi win.BITMAPINFO
+	bi.BmiHeader.BiSize = uint32(unsafe.Sizeof(bi.BmiHeader))
+	bi.BmiHeader.BiWidth = int32(width)
+	bi.BmiHeader.BiHeight = -int32(height)
+	bi.BmiHeader.BiPlanes = 1
+	bi.BmiHeader.BiBitCount = 32
+	bi.BmiHeader.BiCompression = win.BI_RGB
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	hdc := win.GetDC(0)
+	defer win.ReleaseDC(0, hdc)
+
+	if win.GetDIBits(hdc, hBmp, 0, uint32(height), &img.Pix[0], &bi, win.DIB_RGB_COLORS) == 0 {
+		return nil, newError("GetDIBits failed")
+	}
+
+	hasAlpha := false
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+2] = img.Pix[i+2], img.Pix[i]
+		if img.Pix[i+3] != 0 {
+			hasAlpha = true
+		}
+	}
+
+	if !hasAlpha {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 0xFF
+		}
+	}
+
+	return img, nil
+}
+
+// dibFromImage returns img in the CF_DIB format, composited on white.
+func dibFromImage(img *image.RGBA) []byte {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	var bih win.BITMAPINFOHEADER
+	bih.BiSize = uint32(unsafe.Sizeof(bih))
+	bih.BiWidth = int32(width)
+	bih.BiHeight = int32(height)
+	bih.BiPlanes = 1
+	bih.BiBitCount = 32
+	bih.BiCompression = win.BI_RGB
+
+	data := make([]byte, unsafe.Sizeof(bih), int(unsafe.Sizeof(bih))+4*width*height)
+	copy(data, (*[unsafe.Sizeof(bih)]byte)(unsafe.Pointer(&bih))[:])
+
+	// Rows are stored bottom-up and the pixels of img are premultiplied.
+	for y := height - 1; y >= 0; y-- {
+		row := img.Pix[y*img.Stride : y*img.Stride+4*width]
+		for i := 0; i < len(row); i += 4 {
+			r, g, b, a := row[i], row[i+1], row[i+2], row[i+3]
+			data = append(data, b+(0xFF-a), g+(0xFF-a), r+(0xFF-a), 0xFF)
+		}
+	}
+
+	return data
+}
+
+// data returns a copy of the data in format on the open clipboard, or nil.
+func (c *ClipboardService) data(format DataFormat) ([]byte, error) {
+	if !win.IsClipboardFormatAvailable(uint32(format)) {
+		return nil, nil
+	}
+
+	hMem := win.HGLOBAL(win.GetClipboardData(uint32(format)))
+	if hMem == 0 {
+		return nil, lastError("GetClipboardData")
+	}
+
+	size, _, _ := globalSize.Call(uintptr(hMem))
+
+	p := win.GlobalLock(hMem)
+	if p == nil {
+		return nil, lastError("GlobalLock()")
+	}
+	defer win.GlobalUnlock(hMem)
+
+	data := make([]byte, size)
+	copy(data, (*[1 << 30]byte)(p)[:size:size])
+
+	return data, nil
+}
+
+// setBytes places data on the open clipboard in format.
+func (c *ClipboardService) setBytes(format DataFormat, data []byte) error {
+	if len(data) == 0 {
+		// Empty global memory can't be locked.
+		data = []byte{0}
+	}
+
+	return c.setData(uint32(format), unsafe.Pointer(&data[0]), uintptr(len(data)))
+}
+
+// setData copies size bytes at p into global memory and places it on the
+// open clipboard in format.
+func (c *ClipboardService) setData(format uint32, p unsafe.Pointer, size uintptr) error {
+	hMem := win.GlobalAlloc(win.GMEM_MOVEABLE, size)
+	if hMem == 0 {
+		return lastError("GlobalAlloc")
+	}
+
+	dst := win.GlobalLock(hMem)
+	if dst == nil {
+		defer win.GlobalFree(hMem)
+
+		return lastError("GlobalLock()")
+	}
+
+	win.MoveMemory(dst, p, size)
+
+	win.GlobalUnlock(hMem)
+
+	if 0 == win.SetClipboardData(format, win.HANDLE(hMem)) {
+		// We need to free hMem.
+		defer win.GlobalFree(hMem)
+
+		return lastError("SetClipboardData")
+	}
+
+	// The system now owns the memory referred to by hMem.
+
+	return nil
+}
+
+// zeroUTF16 overwrites buf, so a secret doesn't linger in memory longer than
+// needed.
+func zeroUTF16(buf []uint16) {
+	for i := range buf {
+		buf[i] = 0
+	}
 }
 
 func (c *ClipboardService) withOpenClipboard(f func() error) error {
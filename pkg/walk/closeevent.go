@@ -15,11 +15,17 @@ type closeEventHandlerInfo struct {
 type CloseEventHandler func(canceled *bool, reason CloseReason)
 
 type CloseEvent struct {
-	handlers []closeEventHandlerInfo
+	handlers   []*closeEventHandlerInfo
+	publishing int // Number of Publish calls in progress
 }
 
 func (e *CloseEvent) Attach(handler CloseEventHandler) int {
-	handlerInfo := closeEventHandlerInfo{handler, false}
+	handlerInfo := &closeEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*closeEventHandlerInfo(nil), e.handlers...)
+	}
 
 	for i, h := range e.handlers {
 		if h.handler == nil {
@@ -42,6 +48,20 @@ func (e *CloseEvent) Once(handler CloseEventHandler) {
 	e.handlers[i].once = true
 }
 
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *CloseEvent) Connect(handler CloseEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *CloseEvent) AttachOwned(owner Window, handler CloseEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
 type CloseEventPublisher struct {
 	event CloseEvent
 }
@@ -51,13 +71,20 @@ func (p *CloseEventPublisher) Event() *CloseEvent {
 }
 
 func (p *CloseEventPublisher) Publish(canceled *bool, reason CloseReason) {
-	for i, h := range p.event.handlers {
-		if h.handler != nil {
-			h.handler(canceled, reason)
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
 
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
 			if h.once {
-				p.event.Detach(i)
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
 			}
+
+			handler(canceled, reason)
 		}
 	}
 }
@@ -0,0 +1,1054 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const codeEditWindowClass = `\o/ Walk_CodeEdit_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(codeEditWindowClass)
+	})
+}
+
+const (
+	codeEditHighlightTimerId = 1
+	codeEditHighlightDelay   = 100 // milliseconds
+)
+
+// Text Object Model bits, which let formatting bypass the undo stack of the
+// RichEdit control.
+const (
+	tomSuspend = -9999995
+	tomResume  = -9999994
+
+	textDocumentQueryInterface = 0
+	textDocumentRelease        = 2
+	textDocumentUndo           = 22
+)
+
+var iidITextDocument = win.IID{Data1: 0x8CC497C0, Data2: 0xA1DF, Data3: 0x11CE, Data4: [8]byte{0x80, 0x98, 0x00, 0xAA, 0x00, 0x47, 0xBE, 0x5D}}
+
+var (
+	msfteditOnce sync.Once
+	msfteditErr  error
+)
+
+// loadMsftedit loads the library that registers the RICHEDIT50W class.
+func loadMsftedit() error {
+	msfteditOnce.Do(func() {
+		if _, err := syscall.LoadLibrary("Msftedit.dll"); err != nil {
+			msfteditErr = wrapErrorNoPanic(err)
+		}
+	})
+
+	return msfteditErr
+}
+
+// comCall calls the method at index in the vtable of the COM object obj.
+func comCall(obj uintptr, index int, args ...uintptr) uintptr {
+	vtbl := *(*uintptr)(unsafe.Pointer(obj))
+	method := *(*uintptr)(unsafe.Pointer(vtbl + uintptr(index)*unsafe.Sizeof(vtbl)))
+
+	ret, _, _ := syscall.SyscallN(method, append([]uintptr{obj}, args...)...)
+
+	return ret
+}
+
+type codeEditRange struct {
+	start, end int // in UTF-16 code units
+}
+
+type codeEditDecoration struct {
+	codeEditRange
+	color Color
+}
+
+type codeEditGutterState struct {
+	firstLine, firstLineY, lineCount, caretLine int
+}
+
+// CodeEdit is a multi line text editor for source code.
+//
+// Its text is highlighted by a pluggable CodeTokenizer. Line numbers are
+// shown in a gutter, the line of the caret is highlighted and brackets are
+// matched with their counterpart.
+//
+// Positions in the text, e.g. of the selection, count UTF-16 code units, with
+// line breaks counted as a single unit.
+type CodeEdit struct {
+	WidgetBase
+	edit                     *codeRichEdit
+	textDocument             uintptr // ITextDocument of edit
+	tokenizer                CodeTokenizer
+	styles                   map[CodeTokenKind]CodeTokenStyle
+	text                     []uint16        // as of last highlighting
+	literals                 []codeEditRange // string and comment tokens of text
+	textDirty                bool
+	decorations              []codeEditDecoration
+	lineNumbersVisible       bool
+	highlightCurrentLine     bool
+	currentLineColor         Color
+	matchBrackets            bool
+	bracketMatchColor        Color
+	gutterWidth              int // in native pixels
+	digitWidth               int // in native pixels
+	gutterState              codeEditGutterState
+	readOnlyChangedPublisher EventPublisher
+	textChangedPublisher     EventPublisher
+}
+
+// NewCodeEdit creates and initializes a new CodeEdit without tokenizer,
+// using a monospace font.
+func NewCodeEdit(parent Container) (*CodeEdit, error) {
+	if err := loadMsftedit(); err != nil {
+		return nil, err
+	}
+
+	ce := &CodeEdit{
+		styles:               defaultCodeTokenStyles(),
+		lineNumbersVisible:   true,
+		highlightCurrentLine: true,
+		currentLineColor:     RGB(0xFF, 0xFA, 0xE3),
+		matchBrackets:        true,
+		bracketMatchColor:    RGB(0xB4, 0xDC, 0xFF),
+	}
+
+	if err := InitWidget(
+		ce,
+		parent,
+		codeEditWindowClass,
+		win.WS_VISIBLE,
+		win.WS_EX_CONTROLPARENT|win.WS_EX_CLIENTEDGE); err != nil {
+		return nil, err
+	}
+
+	var succeeded bool
+	defer func() {
+		if !succeeded {
+			ce.Dispose()
+		}
+	}()
+
+	ce.edit = &codeRichEdit{ce: ce}
+
+	if err := InitWidget(
+		ce.edit,
+		ce,
+		win.MSFTEDIT_CLASS,
+		win.WS_TABSTOP|win.WS_VISIBLE|win.WS_VSCROLL|win.WS_HSCROLL|win.ES_MULTILINE|win.ES_AUTOVSCROLL|win.ES_AUTOHSCROLL|win.ES_WANTRETURN|win.ES_NOHIDESEL,
+		0); err != nil {
+		return nil, err
+	}
+
+	// No word wrap, so each line of text is a single line on screen.
+	ce.edit.SendMessage(win.EM_SETTARGETDEVICE, 0, 1)
+	ce.edit.SendMessage(win.EM_EXLIMITTEXT, 0, 0x7FFFFFFE)
+	ce.edit.SendMessage(win.EM_SETEDITSTYLE, win.SES_EXTENDBACKCOLOR, win.SES_EXTENDBACKCOLOR)
+	ce.edit.SendMessage(win.EM_SETEVENTMASK, 0, win.ENM_CHANGE|win.ENM_SELCHANGE|win.ENM_UPDATE|win.ENM_SCROLL)
+
+	ce.queryTextDocument()
+
+	if font, err := NewFont("Consolas", 10, 0); err == nil {
+		ce.SetFont(font)
+	} else {
+		ce.edit.applyFont(ce.Font())
+	}
+
+	ce.GraphicsEffects().Add(InteractionEffect)
+	ce.GraphicsEffects().Add(FocusEffect)
+
+	ce.MustRegisterProperty("ReadOnly", NewProperty(
+		func() interface{} {
+			return ce.ReadOnly()
+		},
+		func(v interface{}) error {
+			return ce.SetReadOnly(v.(bool))
+		},
+		ce.readOnlyChangedPublisher.Event()))
+
+	ce.MustRegisterProperty("Text", NewProperty(
+		func() interface{} {
+			return ce.Text()
+		},
+		func(v interface{}) error {
+			return ce.SetText(assertStringOr(v, ""))
+		},
+		ce.textChangedPublisher.Event()))
+
+	ce.highlight()
+
+	succeeded = true
+
+	return ce, nil
+}
+
+func (ce *CodeEdit) Dispose() {
+	if ce.hWnd != 0 {
+		win.KillTimer(ce.hWnd, codeEditHighlightTimerId)
+	}
+
+	if ce.textDocument != 0 {
+		comCall(ce.textDocument, textDocumentRelease)
+		ce.textDocument = 0
+	}
+
+	ce.WidgetBase.Dispose()
+}
+
+func (ce *CodeEdit) queryTextDocument() {
+	var unk uintptr
+	if ce.edit.SendMessage(win.EM_GETOLEINTERFACE, 0, uintptr(unsafe.Pointer(&unk))) == 0 || unk == 0 {
+		return
+	}
+	defer comCall(unk, textDocumentRelease)
+
+	var doc uintptr
+	hr := win.HRESULT(int32(comCall(unk, textDocumentQueryInterface, uintptr(unsafe.Pointer(&iidITextDocument)), uintptr(unsafe.Pointer(&doc)))))
+	if win.SUCCEEDED(hr) {
+		ce.textDocument = doc
+	}
+}
+
+func (ce *CodeEdit) applyEnabled(enabled bool) {
+	ce.WidgetBase.applyEnabled(enabled)
+
+	if ce.edit == nil {
+		return
+	}
+
+	ce.edit.applyEnabled(enabled)
+}
+
+func (ce *CodeEdit) applyFont(font *Font) {
+	ce.WidgetBase.applyFont(font)
+
+	if ce.edit == nil {
+		return
+	}
+
+	ce.edit.applyFont(font)
+
+	ce.digitWidth = 0
+	ce.updateGutter(true)
+}
+
+// SetFocus sets the keyboard input focus to the CodeEdit.
+func (ce *CodeEdit) SetFocus() error {
+	if win.SetFocus(ce.edit.hWnd) == 0 {
+		return lastError("SetFocus")
+	}
+
+	return nil
+}
+
+// Text returns the text of the CodeEdit, with lines separated by "\r\n".
+func (ce *CodeEdit) Text() string {
+	return strings.ReplaceAll(syscall.UTF16ToString(ce.edit.documentText()), "\r", "\r\n")
+}
+
+// SetText sets the text of the CodeEdit.
+func (ce *CodeEdit) SetText(text string) error {
+	if text == ce.Text() {
+		return nil
+	}
+
+	if err := ce.edit.setText(text); err != nil {
+		return err
+	}
+
+	ce.highlight()
+
+	return nil
+}
+
+// TextChanged returns the event that is published when the text of the
+// CodeEdit changed.
+func (ce *CodeEdit) TextChanged() *Event {
+	return ce.textChangedPublisher.Event()
+}
+
+// ReadOnly returns whether the CodeEdit is in read-only mode.
+func (ce *CodeEdit) ReadOnly() bool {
+	return ce.edit.hasStyleBits(win.ES_READONLY)
+}
+
+// SetReadOnly sets whether the CodeEdit is in read-only mode.
+func (ce *CodeEdit) SetReadOnly(readOnly bool) error {
+	if readOnly == ce.ReadOnly() {
+		return nil
+	}
+
+	if 0 == ce.edit.SendMessage(win.EM_SETREADONLY, uintptr(win.BoolToBOOL(readOnly)), 0) {
+		return newError("SendMessage(EM_SETREADONLY)")
+	}
+
+	ce.readOnlyChangedPublisher.Publish()
+
+	return nil
+}
+
+// Tokenizer returns the CodeTokenizer the text of the CodeEdit is highlighted
+// with.
+func (ce *CodeEdit) Tokenizer() CodeTokenizer {
+	return ce.tokenizer
+}
+
+// SetTokenizer sets the CodeTokenizer the text of the CodeEdit is highlighted
+// with. With a nil tokenizer, all text is plain.
+func (ce *CodeEdit) SetTokenizer(tokenizer CodeTokenizer) {
+	ce.tokenizer = tokenizer
+
+	ce.highlight()
+}
+
+// TokenStyle returns the style tokens of kind are drawn with.
+func (ce *CodeEdit) TokenStyle(kind CodeTokenKind) (style CodeTokenStyle, ok bool) {
+	style, ok = ce.styles[kind]
+	return
+}
+
+// SetTokenStyle sets the style tokens of kind are drawn with.
+func (ce *CodeEdit) SetTokenStyle(kind CodeTokenKind, style CodeTokenStyle) {
+	ce.styles[kind] = style
+
+	ce.highlight()
+}
+
+// ResetTokenStyle makes tokens of kind be drawn like plain text.
+func (ce *CodeEdit) ResetTokenStyle(kind CodeTokenKind) {
+	delete(ce.styles, kind)
+
+	ce.highlight()
+}
+
+// LineNumbersVisible returns whether line numbers are shown.
+func (ce *CodeEdit) LineNumbersVisible() bool {
+	return ce.lineNumbersVisible
+}
+
+// SetLineNumbersVisible sets whether line numbers are shown.
+func (ce *CodeEdit) SetLineNumbersVisible(visible bool) {
+	ce.lineNumbersVisible = visible
+
+	ce.updateGutter(true)
+}
+
+// HighlightCurrentLine returns whether the line of the caret is highlighted.
+func (ce *CodeEdit) HighlightCurrentLine() bool {
+	return ce.highlightCurrentLine
+}
+
+// SetHighlightCurrentLine sets whether the line of the caret is highlighted.
+func (ce *CodeEdit) SetHighlightCurrentLine(highlight bool) {
+	ce.highlightCurrentLine = highlight
+
+	ce.updateDecorations()
+}
+
+// CurrentLineColor returns the background Color of the line of the caret.
+func (ce *CodeEdit) CurrentLineColor() Color {
+	return ce.currentLineColor
+}
+
+// SetCurrentLineColor sets the background Color of the line of the caret.
+func (ce *CodeEdit) SetCurrentLineColor(c Color) {
+	ce.currentLineColor = c
+
+	ce.updateDecorations()
+}
+
+// MatchBrackets returns whether a bracket next to the caret is highlighted
+// together with its counterpart.
+func (ce *CodeEdit) MatchBrackets() bool {
+	return ce.matchBrackets
+}
+
+// SetMatchBrackets sets whether a bracket next to the caret is highlighted
+// together with its counterpart.
+//
+// Brackets in string literals and comments are not matched.
+func (ce *CodeEdit) SetMatchBrackets(match bool) {
+	ce.matchBrackets = match
+
+	ce.updateDecorations()
+}
+
+// BracketMatchColor returns the background Color of matched brackets.
+func (ce *CodeEdit) BracketMatchColor() Color {
+	return ce.bracketMatchColor
+}
+
+// SetBracketMatchColor sets the background Color of matched brackets.
+func (ce *CodeEdit) SetBracketMatchColor(c Color) {
+	ce.bracketMatchColor = c
+
+	ce.updateDecorations()
+}
+
+// TextSelection returns the range of the selected text.
+func (ce *CodeEdit) TextSelection() (start, end int) {
+	cr := ce.edit.selection()
+	return int(cr.CpMin), int(cr.CpMax)
+}
+
+// SetTextSelection selects the text from start to end. An end of -1 selects
+// to the end of the text.
+func (ce *CodeEdit) SetTextSelection(start, end int) {
+	ce.edit.setSelection(win.CHARRANGE{CpMin: int32(start), CpMax: int32(end)})
+}
+
+// ReplaceSelectedText replaces the selected text with text.
+func (ce *CodeEdit) ReplaceSelectedText(text string, canUndo bool) {
+	ce.edit.SendMessage(win.EM_REPLACESEL,
+		uintptr(win.BoolToBOOL(canUndo)),
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(text))))
+}
+
+// CaretPosition returns the zero based line and column of the caret.
+func (ce *CodeEdit) CaretPosition() (line, column int) {
+	caret := int(ce.edit.selection().CpMax)
+	line = ce.edit.lineFromChar(caret)
+
+	return line, caret - ce.edit.lineIndex(line)
+}
+
+// ScrollToCaret scrolls the caret into view.
+func (ce *CodeEdit) ScrollToCaret() {
+	ce.edit.SendMessage(win.EM_SCROLLCARET, 0, 0)
+}
+
+// highlight tokenizes the whole text and applies the token styles.
+func (ce *CodeEdit) highlight() {
+	win.KillTimer(ce.hWnd, codeEditHighlightTimerId)
+
+	ce.text = ce.edit.documentText()
+	ce.textDirty = false
+	ce.literals = ce.literals[:0]
+
+	var source string
+	var offsets []int
+	var tokens []CodeToken
+	if ce.tokenizer != nil {
+		source, offsets = codeEditSource(ce.text)
+		tokens = ce.tokenizer.Tokenize(source)
+	}
+
+	toUTF16 := func(offset int) int {
+		if offset < 0 {
+			offset = 0
+		} else if offset > len(source) {
+			offset = len(source)
+		}
+		return offsets[offset]
+	}
+
+	ce.withoutUndoAndRedraw(func(sel win.CHARRANGE) {
+		plain := win.CHARFORMAT2{}
+		plain.CbSize = uint32(unsafe.Sizeof(plain))
+		plain.DwMask = win.CFM_COLOR | win.CFM_BOLD | win.CFM_ITALIC | win.CFM_BACKCOLOR
+		plain.DwEffects = win.CFE_AUTOCOLOR | win.CFE_AUTOBACKCOLOR
+		ce.formatRange(codeEditRange{0, -1}, &plain)
+
+		for _, token := range tokens {
+			r := codeEditRange{toUTF16(token.Start), toUTF16(token.End)}
+			if r.end <= r.start {
+				continue
+			}
+
+			if token.Kind == CodeTokenString || token.Kind == CodeTokenComment {
+				ce.literals = append(ce.literals, r)
+			}
+
+			style, ok := ce.styles[token.Kind]
+			if !ok {
+				continue
+			}
+
+			cf := win.CHARFORMAT2{}
+			cf.CbSize = uint32(unsafe.Sizeof(cf))
+			cf.DwMask = win.CFM_COLOR | win.CFM_BOLD | win.CFM_ITALIC
+			cf.CrTextColor = win.COLORREF(style.Color)
+			if style.Bold {
+				cf.DwEffects |= win.CFE_BOLD
+			}
+			if style.Italic {
+				cf.DwEffects |= win.CFE_ITALIC
+			}
+			ce.formatRange(r, &cf)
+		}
+
+		// Resetting the format above cleared all decorations.
+		ce.decorations = nil
+		ce.applyDecorations(ce.wantedDecorations(sel))
+	})
+
+	ce.updateGutter(false)
+}
+
+// codeEditSource converts the text of a RichEdit control to the form passed
+// to a CodeTokenizer and maps each byte offset of it to a UTF-16 offset.
+func codeEditSource(text []uint16) (string, []int) {
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	offsets := make([]int, 0, len(text)+1)
+
+	for i := 0; i < len(text); {
+		r, n := rune(text[i]), 1
+		if utf16.IsSurrogate(r) && i+1 < len(text) {
+			if d := utf16.DecodeRune(r, rune(text[i+1])); d != utf8.RuneError {
+				r, n = d, 2
+			}
+		}
+		if r == '\r' {
+			r = '\n'
+		}
+		if !utf8.ValidRune(r) {
+			r = utf8.RuneError
+		}
+
+		sb.WriteRune(r)
+		for j := utf8.RuneLen(r); j > 0; j-- {
+			offsets = append(offsets, i)
+		}
+
+		i += n
+	}
+
+	offsets = append(offsets, len(text))
+
+	return sb.String(), offsets
+}
+
+func (ce *CodeEdit) withoutUndoAndRedraw(f func(sel win.CHARRANGE)) {
+	re := ce.edit
+
+	sel := re.selection()
+
+	var scrollPos win.POINT
+	re.SendMessage(win.EM_GETSCROLLPOS, 0, uintptr(unsafe.Pointer(&scrollPos)))
+
+	eventMask := re.SendMessage(win.EM_SETEVENTMASK, 0, 0)
+	re.SendMessage(win.WM_SETREDRAW, 0, 0)
+
+	ce.suspendUndo(true)
+	f(sel)
+	ce.suspendUndo(false)
+
+	re.setSelection(sel)
+	re.SendMessage(win.EM_SETSCROLLPOS, 0, uintptr(unsafe.Pointer(&scrollPos)))
+
+	re.SendMessage(win.WM_SETREDRAW, 1, 0)
+	re.SendMessage(win.EM_SETEVENTMASK, 0, eventMask)
+
+	re.Invalidate()
+}
+
+func (ce *CodeEdit) suspendUndo(suspend bool) {
+	if ce.textDocument == 0 {
+		// Without TOM, formatting ends up on the undo stack.
+		return
+	}
+
+	count := int32(tomResume)
+	if suspend {
+		count = tomSuspend
+	}
+
+	comCall(ce.textDocument, textDocumentUndo, uintptr(count), 0)
+}
+
+func (ce *CodeEdit) formatRange(r codeEditRange, cf *win.CHARFORMAT2) {
+	ce.edit.setSelection(win.CHARRANGE{CpMin: int32(r.start), CpMax: int32(r.end)})
+	ce.edit.SendMessage(win.EM_SETCHARFORMAT, win.SCF_SELECTION, uintptr(unsafe.Pointer(cf)))
+}
+
+// wantedDecorations returns the current line and bracket highlights for the
+// selection sel.
+func (ce *CodeEdit) wantedDecorations(sel win.CHARRANGE) []codeEditDecoration {
+	var decorations []codeEditDecoration
+
+	if ce.highlightCurrentLine && sel.CpMin == sel.CpMax {
+		start := ce.edit.lineIndex(ce.edit.lineFromChar(int(sel.CpMax)))
+		length := int(ce.edit.SendMessage(win.EM_LINELENGTH, uintptr(start), 0))
+
+		// Includes the line break, so the highlight extends to the right edge.
+		decorations = append(decorations, codeEditDecoration{codeEditRange{start, start + length + 1}, ce.currentLineColor})
+	}
+
+	if ce.matchBrackets && sel.CpMin == sel.CpMax && !ce.textDirty {
+		caret := int(sel.CpMax)
+
+		for _, pos := range [2]int{caret - 1, caret} {
+			if match := ce.matchingBracket(pos); match >= 0 {
+				decorations = append(decorations,
+					codeEditDecoration{codeEditRange{pos, pos + 1}, ce.bracketMatchColor},
+					codeEditDecoration{codeEditRange{match, match + 1}, ce.bracketMatchColor})
+				break
+			}
+		}
+	}
+
+	return decorations
+}
+
+func (ce *CodeEdit) applyDecorations(decorations []codeEditDecoration) {
+	cf := win.CHARFORMAT2{}
+	cf.CbSize = uint32(unsafe.Sizeof(cf))
+	cf.DwMask = win.CFM_BACKCOLOR
+	cf.DwEffects = win.CFE_AUTOBACKCOLOR
+
+	for _, d := range ce.decorations {
+		ce.formatRange(d.codeEditRange, &cf)
+	}
+
+	cf.DwEffects = 0
+
+	for _, d := range decorations {
+		cf.CrBackColor = win.COLORREF(d.color)
+		ce.formatRange(d.codeEditRange, &cf)
+	}
+
+	ce.decorations = decorations
+}
+
+func (ce *CodeEdit) updateDecorations() {
+	if ce.edit == nil {
+		return
+	}
+
+	if !ce.textDirty && len(ce.text) != ce.edit.textLength() {
+		// The text changed, but EN_CHANGE is still to come.
+		ce.textDirty = true
+	}
+
+	decorations := ce.wantedDecorations(ce.edit.selection())
+
+	if len(decorations) == len(ce.decorations) {
+		equal := true
+		for i, d := range decorations {
+			if d != ce.decorations[i] {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			return
+		}
+	}
+
+	ce.withoutUndoAndRedraw(func(win.CHARRANGE) {
+		ce.applyDecorations(decorations)
+	})
+}
+
+// matchingBracket returns the position of the bracket matching the one at
+// pos, or -1.
+func (ce *CodeEdit) matchingBracket(pos int) int {
+	const brackets = "()[]{}"
+
+	if pos < 0 || pos >= len(ce.text) || ce.text[pos] >= utf8.RuneSelf {
+		return -1
+	}
+
+	k := strings.IndexByte(brackets, byte(ce.text[pos]))
+	if k < 0 || ce.inLiteral(pos) {
+		return -1
+	}
+
+	same, other := uint16(brackets[k]), uint16(brackets[k^1])
+	dir := 1
+	if k%2 == 1 {
+		dir = -1
+	}
+
+	depth := 0
+	for i := pos; i >= 0 && i < len(ce.text); i += dir {
+		switch ce.text[i] {
+		case same:
+			if !ce.inLiteral(i) {
+				depth++
+			}
+
+		case other:
+			if !ce.inLiteral(i) {
+				if depth--; depth == 0 {
+					return i
+				}
+			}
+		}
+	}
+
+	return -1
+}
+
+// inLiteral returns whether pos is inside a string literal or comment.
+func (ce *CodeEdit) inLiteral(pos int) bool {
+	i := sort.Search(len(ce.literals), func(i int) bool {
+		return ce.literals[i].end > pos
+	})
+
+	return i < len(ce.literals) && ce.literals[i].start <= pos
+}
+
+// insertIndent inserts the leading white space of the previous line at the
+// caret, after a line break was typed.
+func (ce *CodeEdit) insertIndent() {
+	line := ce.edit.lineFromChar(int(ce.edit.selection().CpMax))
+	if line == 0 {
+		return
+	}
+
+	start := ce.edit.lineIndex(line - 1)
+	length := int(ce.edit.SendMessage(win.EM_LINELENGTH, uintptr(start), 0))
+
+	prev := ce.edit.textRange(start, start+length)
+
+	indent := prev[:len(prev)-len(strings.TrimLeft(prev, " \t"))]
+	if indent != "" {
+		ce.ReplaceSelectedText(indent, true)
+	}
+}
+
+func (ce *CodeEdit) digitWidthPixels() int {
+	if ce.digitWidth == 0 {
+		ce.digitWidth = ce.calculateTextSizeImpl("0").Width
+	}
+
+	return ce.digitWidth
+}
+
+// updateGutter resizes the gutter to fit the line numbers and repaints it,
+// if what it shows changed.
+func (ce *CodeEdit) updateGutter(force bool) {
+	if ce.edit == nil {
+		return
+	}
+
+	first := int(ce.edit.SendMessage(win.EM_GETFIRSTVISIBLELINE, 0, 0))
+	caret, _ := ce.CaretPosition()
+
+	state := codeEditGutterState{
+		firstLine:  first,
+		firstLineY: ce.lineY(first),
+		lineCount:  int(ce.edit.SendMessage(win.EM_GETLINECOUNT, 0, 0)),
+		caretLine:  caret,
+	}
+
+	width := 0
+	if ce.lineNumbersVisible {
+		digits := len(strconv.Itoa(state.lineCount))
+		if digits < 2 {
+			digits = 2
+		}
+
+		width = digits*ce.digitWidthPixels() + 2*IntFrom96DPI(6, ce.DPI())
+	}
+
+	if width != ce.gutterWidth {
+		ce.gutterWidth = width
+		ce.layoutEdit()
+
+		force = true
+	}
+
+	if !force && state == ce.gutterState {
+		return
+	}
+
+	ce.gutterState = state
+
+	if ce.gutterWidth > 0 {
+		rc := win.RECT{Right: int32(ce.gutterWidth), Bottom: int32(ce.ClientBoundsPixels().Height)}
+		win.InvalidateRect(ce.hWnd, &rc, false)
+	}
+}
+
+func (ce *CodeEdit) layoutEdit() {
+	if ce.edit == nil {
+		return
+	}
+
+	cb := ce.ClientBoundsPixels()
+
+	ce.edit.SetBoundsPixels(Rectangle{ce.gutterWidth, 0, cb.Width - ce.gutterWidth, cb.Height})
+}
+
+// lineY returns the y coordinate of line in the client area of the CodeEdit.
+func (ce *CodeEdit) lineY(line int) int {
+	var pt win.POINT
+	ce.edit.SendMessage(win.EM_POSFROMCHAR, uintptr(unsafe.Pointer(&pt)), uintptr(ce.edit.lineIndex(line)))
+
+	return int(pt.Y)
+}
+
+func (ce *CodeEdit) paintGutter(canvas *Canvas, bounds Rectangle) error {
+	bg, err := NewSystemColorBrush(SysColorBtnFace)
+	if err != nil {
+		return err
+	}
+	defer bg.Dispose()
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	font := ce.Font()
+	pad := IntFrom96DPI(6, ce.DPI())
+	lineHeight := ce.calculateTextSizeImpl("0").Height
+	grayText := Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	windowText := Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+
+	const format = TextRight | TextSingleLine | TextNoPrefix
+
+	for line := ce.gutterState.firstLine; line < ce.gutterState.lineCount; line++ {
+		y := ce.lineY(line)
+		if y >= bounds.Height {
+			break
+		}
+
+		color := grayText
+		if line == ce.gutterState.caretLine {
+			color = windowText
+		}
+
+		rc := Rectangle{0, y, bounds.Width - pad, lineHeight}
+		if err := canvas.DrawTextPixels(strconv.Itoa(line+1), font, color, rc, format); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (*CodeEdit) NeedsWmSize() bool {
+	return true
+}
+
+// WndProc is the window procedure of the CodeEdit.
+//
+// When implementing your own WndProc to add or modify behavior, call the
+// WndProc of the embedded CodeEdit for messages you don't handle yourself.
+func (ce *CodeEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_COMMAND:
+		if ce.edit == nil || win.HWND(lParam) != ce.edit.hWnd {
+			break
+		}
+
+		switch win.HIWORD(uint32(wParam)) {
+		case win.EN_CHANGE:
+			// Highlighting is deferred while typing.
+			ce.textDirty = true
+			if 0 == win.SetTimer(ce.hWnd, codeEditHighlightTimerId, codeEditHighlightDelay, 0) {
+				lastError("SetTimer")
+			}
+
+			ce.updateGutter(false)
+
+			ce.textChangedPublisher.Publish()
+
+		case win.EN_UPDATE, win.EN_VSCROLL:
+			ce.updateGutter(false)
+		}
+
+		return 0
+
+	case win.WM_NOTIFY:
+		nmh := (*win.NMHDR)(unsafe.Pointer(lParam))
+		if ce.edit == nil || nmh.HwndFrom != ce.edit.hWnd {
+			break
+		}
+
+		if nmh.Code == win.EN_SELCHANGE {
+			ce.updateDecorations()
+			ce.updateGutter(false)
+		}
+
+		return 0
+
+	case win.WM_TIMER:
+		if wParam == codeEditHighlightTimerId {
+			ce.highlight()
+			return 0
+		}
+
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		var ps win.PAINTSTRUCT
+
+		hdc := win.BeginPaint(hwnd, &ps)
+		if hdc == 0 {
+			break
+		}
+		defer win.EndPaint(hwnd, &ps)
+
+		bounds := Rectangle{Width: ce.gutterWidth, Height: ce.ClientBoundsPixels().Height}
+		if bounds.Width <= 0 || bounds.Height <= 0 {
+			return 0
+		}
+
+		bitmap, err := NewBitmapForDPI(bounds.Size(), ce.DPI())
+		if err != nil {
+			break
+		}
+		defer bitmap.Dispose()
+
+		canvas, err := NewCanvasFromImage(bitmap)
+		if err != nil {
+			break
+		}
+		defer canvas.Dispose()
+
+		canvas.adoptLayout(hdc)
+
+		if err := ce.paintGutter(canvas, bounds); err != nil {
+			break
+		}
+
+		if !win.BitBlt(hdc, 0, 0, int32(bounds.Width), int32(bounds.Height), canvas.hdc, 0, 0, win.SRCCOPY) {
+			break
+		}
+
+		return 0
+
+	case win.WM_LBUTTONDOWN:
+		ce.SetFocus()
+
+	case win.WM_WINDOWPOSCHANGED:
+		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
+
+		if wp.Flags&win.SWP_NOSIZE != 0 {
+			break
+		}
+
+		ce.layoutEdit()
+		ce.updateGutter(true)
+	}
+
+	return ce.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (ce *CodeEdit) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &logViewLayoutItem{
+		idealSize: SizeFrom96DPI(Size{300, 150}, ctx.dpi),
+		minSize:   SizeFrom96DPI(Size{50, 30}, ctx.dpi),
+	}
+}
+
+// codeRichEdit is the RichEdit control a CodeEdit edits its text in.
+type codeRichEdit struct {
+	WidgetBase
+	ce *CodeEdit
+}
+
+func (re *codeRichEdit) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return re.ce.CreateLayoutItem(ctx)
+}
+
+func (re *codeRichEdit) documentText() []uint16 {
+	gtl := win.GETTEXTLENGTHEX{Flags: win.GTL_NUMCHARS | win.GTL_PRECISE, Codepage: 1200}
+	n := int(re.SendMessage(win.EM_GETTEXTLENGTHEX, uintptr(unsafe.Pointer(&gtl)), 0))
+
+	buf := make([]uint16, n+1)
+	gt := win.GETTEXTEX{Cb: uint32(len(buf) * 2), Flags: win.GT_DEFAULT, Codepage: 1200}
+	n = int(re.SendMessage(win.EM_GETTEXTEX, uintptr(unsafe.Pointer(&gt)), uintptr(unsafe.Pointer(&buf[0]))))
+
+	return buf[:n]
+}
+
+func (re *codeRichEdit) textLength() int {
+	gtl := win.GETTEXTLENGTHEX{Flags: win.GTL_NUMCHARS | win.GTL_PRECISE, Codepage: 1200}
+	return int(re.SendMessage(win.EM_GETTEXTLENGTHEX, uintptr(unsafe.Pointer(&gtl)), 0))
+}
+
+func (re *codeRichEdit) textRange(start, end int) string {
+	if end <= start {
+		return ""
+	}
+
+	buf := make([]uint16, end-start+1)
+	tr := win.TEXTRANGE{Chrg: win.CHARRANGE{CpMin: int32(start), CpMax: int32(end)}, LpstrText: &buf[0]}
+	n := int(re.SendMessage(win.EM_GETTEXTRANGE, 0, uintptr(unsafe.Pointer(&tr))))
+
+	return string(utf16.Decode(buf[:n]))
+}
+
+func (re *codeRichEdit) selection() win.CHARRANGE {
+	var cr win.CHARRANGE
+	re.SendMessage(win.EM_EXGETSEL, 0, uintptr(unsafe.Pointer(&cr)))
+	return cr
+}
+
+func (re *codeRichEdit) setSelection(cr win.CHARRANGE) {
+	re.SendMessage(win.EM_EXSETSEL, 0, uintptr(unsafe.Pointer(&cr)))
+}
+
+func (re *codeRichEdit) lineFromChar(pos int) int {
+	return int(re.SendMessage(win.EM_EXLINEFROMCHAR, 0, uintptr(pos)))
+}
+
+func (re *codeRichEdit) lineIndex(line int) int {
+	return int(re.SendMessage(win.EM_LINEINDEX, uintptr(line), 0))
+}
+
+func (re *codeRichEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_GETDLGCODE:
+		if wParam == win.VK_RETURN || wParam == win.VK_TAB && !re.ce.ReadOnly() {
+			return win.DLGC_WANTALLKEYS
+		}
+
+		return win.DLGC_HASSETSEL | win.DLGC_WANTARROWS | win.DLGC_WANTCHARS
+
+	case win.WM_KEYDOWN:
+		key := Key(wParam)
+
+		if ControlDown() && key == KeyV || ShiftDown() && key == KeyInsert {
+			re.SendMessage(win.WM_PASTE, 0, 0)
+			return 0
+		}
+
+		if ControlDown() {
+			switch key {
+			case KeyE, KeyJ, KeyL, KeyR, Key1, Key2, Key5:
+				// Paragraph formatting doesn't apply to source code.
+				return 0
+			}
+		}
+
+	case win.WM_PASTE:
+		// Only plain text, the formatting is up to the tokenizer.
+		re.SendMessage(win.EM_PASTESPECIAL, win.CF_UNICODETEXT, 0)
+		return 0
+
+	case win.WM_CHAR:
+		if wParam == '\r' {
+			ret := re.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+			re.ce.insertIndent()
+			return ret
+		}
+	}
+
+	return re.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
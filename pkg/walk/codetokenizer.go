@@ -0,0 +1,234 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"strings"
+)
+
+// CodeTokenKind classifies a token for syntax highlighting.
+type CodeTokenKind int
+
+const (
+	CodeTokenPlain CodeTokenKind = iota
+	CodeTokenKeyword
+	CodeTokenType
+	CodeTokenString
+	CodeTokenNumber
+	CodeTokenComment
+	CodeTokenOperator
+)
+
+// CodeToken is a run of text that is highlighted in the style of its kind.
+//
+// Start and End are byte offsets into the text passed to the CodeTokenizer.
+type CodeToken struct {
+	Start int
+	End   int
+	Kind  CodeTokenKind
+}
+
+// CodeTokenizer splits source code into tokens for syntax highlighting.
+type CodeTokenizer interface {
+	// Tokenize returns the tokens of text, ordered by Start and not
+	// overlapping. Lines of text are separated by '\n'. Text not covered by
+	// any token is plain.
+	Tokenize(text string) []CodeToken
+}
+
+// CodeTokenizerFunc adapts an ordinary function to the CodeTokenizer
+// interface.
+type CodeTokenizerFunc func(text string) []CodeToken
+
+func (f CodeTokenizerFunc) Tokenize(text string) []CodeToken {
+	return f(text)
+}
+
+// CodeTokenStyle describes how tokens of a kind are drawn.
+type CodeTokenStyle struct {
+	Color  Color
+	Bold   bool
+	Italic bool
+}
+
+func defaultCodeTokenStyles() map[CodeTokenKind]CodeTokenStyle {
+	return map[CodeTokenKind]CodeTokenStyle{
+		CodeTokenKeyword: {Color: RGB(0x00, 0x00, 0xFF)},
+		CodeTokenType:    {Color: RGB(0x2B, 0x91, 0xAF)},
+		CodeTokenString:  {Color: RGB(0xA3, 0x15, 0x15)},
+		CodeTokenNumber:  {Color: RGB(0x09, 0x86, 0x58)},
+		CodeTokenComment: {Color: RGB(0x00, 0x80, 0x00), Italic: true},
+	}
+}
+
+// CLikeTokenizer is a CodeTokenizer for languages with a lexical structure
+// like C, e.g. Go, Java or JavaScript.
+type CLikeTokenizer struct {
+	// LineComment starts a comment that extends to the end of the line.
+	LineComment string
+
+	// BlockCommentStart and BlockCommentEnd delimit comments that may span
+	// multiple lines.
+	BlockCommentStart string
+	BlockCommentEnd   string
+
+	// Quotes lists the characters delimiting single line string literals,
+	// in which a backslash escapes the next character.
+	Quotes string
+
+	// RawQuotes lists the characters delimiting string literals without
+	// escapes, that may span multiple lines.
+	RawQuotes string
+
+	words map[string]CodeTokenKind
+}
+
+// NewCLikeTokenizer returns a new CLikeTokenizer that highlights the words
+// in keywords and types, with C style comments and string literals.
+func NewCLikeTokenizer(keywords, types []string) *CLikeTokenizer {
+	t := &CLikeTokenizer{
+		LineComment:       "//",
+		BlockCommentStart: "/*",
+		BlockCommentEnd:   "*/",
+		Quotes:            `"'`,
+		words:             make(map[string]CodeTokenKind, len(keywords)+len(types)),
+	}
+
+	for _, w := range types {
+		t.words[w] = CodeTokenType
+	}
+	for _, w := range keywords {
+		t.words[w] = CodeTokenKeyword
+	}
+
+	return t
+}
+
+// NewGoTokenizer returns a new CLikeTokenizer for the Go programming
+// language.
+func NewGoTokenizer() *CLikeTokenizer {
+	t := NewCLikeTokenizer(
+		[]string{
+			"break", "case", "chan", "const", "continue", "default", "defer",
+			"else", "fallthrough", "for", "func", "go", "goto", "if", "import",
+			"interface", "map", "package", "range", "return", "select",
+			"struct", "switch", "type", "var", "true", "false", "nil", "iota",
+		},
+		[]string{
+			"any", "bool", "byte", "complex64", "complex128", "error",
+			"float32", "float64", "int", "int8", "int16", "int32", "int64",
+			"rune", "string", "uint", "uint8", "uint16", "uint32", "uint64",
+			"uintptr",
+		})
+
+	t.RawQuotes = "`"
+
+	return t
+}
+
+func isCodeIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isCodeDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (t *CLikeTokenizer) Tokenize(text string) []CodeToken {
+	var tokens []CodeToken
+
+	add := func(start, end int, kind CodeTokenKind) {
+		if n := len(tokens); kind == CodeTokenOperator && n > 0 && tokens[n-1].Kind == kind && tokens[n-1].End == start {
+			tokens[n-1].End = end
+			return
+		}
+
+		tokens = append(tokens, CodeToken{start, end, kind})
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+
+		switch {
+		case t.LineComment != "" && strings.HasPrefix(rest, t.LineComment):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			add(i, i+end, CodeTokenComment)
+			i += end
+
+		case t.BlockCommentStart != "" && strings.HasPrefix(rest, t.BlockCommentStart):
+			end := len(rest)
+			if t.BlockCommentEnd != "" {
+				if j := strings.Index(rest[len(t.BlockCommentStart):], t.BlockCommentEnd); j >= 0 {
+					end = len(t.BlockCommentStart) + j + len(t.BlockCommentEnd)
+				}
+			}
+			add(i, i+end, CodeTokenComment)
+			i += end
+
+		case strings.IndexByte(t.RawQuotes, c) >= 0:
+			end := len(rest)
+			if j := strings.IndexByte(rest[1:], c); j >= 0 {
+				end = j + 2
+			}
+			add(i, i+end, CodeTokenString)
+			i += end
+
+		case strings.IndexByte(t.Quotes, c) >= 0:
+			j := 1
+			for j < len(rest) && rest[j] != c && rest[j] != '\n' {
+				if rest[j] == '\\' && j+1 < len(rest) && rest[j+1] != '\n' {
+					j++
+				}
+				j++
+			}
+			if j < len(rest) && rest[j] == c {
+				j++
+			}
+			add(i, i+j, CodeTokenString)
+			i += j
+
+		case isCodeDigit(c) || c == '.' && len(rest) > 1 && isCodeDigit(rest[1]):
+			j := 1
+			for j < len(rest) {
+				d := rest[j]
+				if isCodeIdentStart(d) && d < 0x80 || isCodeDigit(d) || d == '.' {
+					j++
+				} else if (d == '+' || d == '-') && strings.IndexByte("eEpP", rest[j-1]) >= 0 && !strings.HasPrefix(rest, "0x") && !strings.HasPrefix(rest, "0X") {
+					j++
+				} else {
+					break
+				}
+			}
+			add(i, i+j, CodeTokenNumber)
+			i += j
+
+		case isCodeIdentStart(c):
+			j := 1
+			for j < len(rest) && (isCodeIdentStart(rest[j]) || isCodeDigit(rest[j])) {
+				j++
+			}
+			if kind, ok := t.words[rest[:j]]; ok {
+				add(i, i+j, kind)
+			}
+			i += j
+
+		case strings.IndexByte("+-*/%=<>!&|^~?:", c) >= 0:
+			add(i, i+1, CodeTokenOperator)
+			i++
+
+		default:
+			i++
+		}
+	}
+
+	return tokens
+}
@@ -7,6 +7,10 @@
 
 package walk
 
+import (
+	"math"
+)
+
 type Color uint32
 
 func RGB(r, g, b byte) Color {
@@ -24,3 +28,169 @@ func (c Color) G() byte {
 func (c Color) B() byte {
 	return byte((c >> 16) & 0xff)
 }
+
+// colorComponent converts v in the range [0, 1] to a color component.
+func colorComponent(v float64) byte {
+	return byte(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// hueToRGB returns the red, green and blue components in the range [0, 1]
+// of a fully saturated color with hue in degrees, scaled to chroma.
+func hueToRGB(hue, chroma float64) (r, g, b float64) {
+	h := math.Mod(hue/60, 6)
+	if h < 0 {
+		h += 6
+	}
+	x := chroma * (1 - math.Abs(math.Mod(h, 2)-1))
+
+	switch {
+	case h < 1:
+		return chroma, x, 0
+	case h < 2:
+		return x, chroma, 0
+	case h < 3:
+		return 0, chroma, x
+	case h < 4:
+		return 0, x, chroma
+	case h < 5:
+		return x, 0, chroma
+	default:
+		return chroma, 0, x
+	}
+}
+
+// hueMaxMin returns the hue in degrees of c, together with the largest and
+// smallest of its components in the range [0, 1].
+func (c Color) hueMaxMin() (hue, max, min float64) {
+	r, g, b := float64(c.R())/255, float64(c.G())/255, float64(c.B())/255
+
+	max = math.Max(r, math.Max(g, b))
+	min = math.Min(r, math.Min(g, b))
+
+	switch d := max - min; {
+	case d == 0:
+		hue = 0
+	case max == r:
+		hue = 60 * math.Mod((g-b)/d, 6)
+	case max == g:
+		hue = 60 * ((b-r)/d + 2)
+	default:
+		hue = 60 * ((r-g)/d + 4)
+	}
+
+	if hue < 0 {
+		hue += 360
+	}
+
+	return
+}
+
+// ColorFromHSV returns the Color for hue in degrees and saturation and value
+// in the range [0, 1].
+func ColorFromHSV(hue, saturation, value float64) Color {
+	chroma := value * saturation
+	r, g, b := hueToRGB(hue, chroma)
+	m := value - chroma
+
+	return RGB(colorComponent(r+m), colorComponent(g+m), colorComponent(b+m))
+}
+
+// HSV returns the hue in degrees and the saturation and value in the range
+// [0, 1] of c.
+func (c Color) HSV() (hue, saturation, value float64) {
+	hue, max, min := c.hueMaxMin()
+
+	if max > 0 {
+		saturation = (max - min) / max
+	}
+
+	return hue, saturation, max
+}
+
+// ColorFromHSL returns the Color for hue in degrees and saturation and
+// lightness in the range [0, 1].
+func ColorFromHSL(hue, saturation, lightness float64) Color {
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	r, g, b := hueToRGB(hue, chroma)
+	m := lightness - chroma/2
+
+	return RGB(colorComponent(r+m), colorComponent(g+m), colorComponent(b+m))
+}
+
+// HSL returns the hue in degrees and the saturation and lightness in the
+// range [0, 1] of c.
+func (c Color) HSL() (hue, saturation, lightness float64) {
+	hue, max, min := c.hueMaxMin()
+
+	lightness = (max + min) / 2
+
+	if d := max - min; d > 0 {
+		saturation = d / (1 - math.Abs(2*lightness-1))
+	}
+
+	return hue, saturation, lightness
+}
+
+// Lighter returns c with its HSL lightness increased by amount, which is in
+// the range [0, 1].
+func (c Color) Lighter(amount float64) Color {
+	h, s, l := c.HSL()
+
+	return ColorFromHSL(h, s, math.Min(1, l+amount))
+}
+
+// Darker returns c with its HSL lightness decreased by amount, which is in
+// the range [0, 1].
+func (c Color) Darker(amount float64) Color {
+	h, s, l := c.HSL()
+
+	return ColorFromHSL(h, s, math.Max(0, l-amount))
+}
+
+// Blend returns the color that results from drawing other with the given
+// opacity, in the range [0, 1], over c.
+func (c Color) Blend(other Color, opacity float64) Color {
+	mix := func(a, b byte) byte {
+		return colorComponent((float64(a) + (float64(b)-float64(a))*opacity) / 255)
+	}
+
+	return RGB(mix(c.R(), other.R()), mix(c.G(), other.G()), mix(c.B(), other.B()))
+}
+
+// Luminance returns the relative luminance of c as defined by WCAG, in the
+// range [0, 1].
+func (c Color) Luminance() float64 {
+	linear := func(v byte) float64 {
+		f := float64(v) / 255
+		if f <= 0.03928 {
+			return f / 12.92
+		}
+
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*linear(c.R()) + 0.7152*linear(c.G()) + 0.0722*linear(c.B())
+}
+
+// ContrastRatio returns the WCAG contrast ratio between c and other, in the
+// range [1, 21]. Text should have a ratio of at least 4.5 to its background.
+func (c Color) ContrastRatio(other Color) float64 {
+	l1, l2 := c.Luminance(), other.Luminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// ContrastingForeground returns black or white, whichever has the higher
+// contrast ratio to c, e.g. for text drawn on a background of color c.
+func (c Color) ContrastingForeground() Color {
+	black, white := RGB(0, 0, 0), RGB(255, 255, 255)
+
+	if c.ContrastRatio(black) >= c.ContrastRatio(white) {
+		return black
+	}
+
+	return white
+}
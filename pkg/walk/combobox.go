@@ -43,6 +43,14 @@ type ComboBox struct {
 	editOrigWndProcPtr           uintptr
 	editing                      bool
 	persistent                   bool
+	filterEnabled                bool
+	filterIndexes                []int // model indexes of the list items, nil if unfiltered
+	filterText                   string
+	filtering                    bool
+	matchHighlightColor          Color
+	listHWnd                     win.HWND
+	listOrigWndProcPtr           uintptr
+	styler                       ListItemStyler
 }
 
 var comboBoxEditWndProcPtr uintptr
@@ -102,7 +110,17 @@ func comboBoxEditWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uint
 }
 
 func NewComboBox(parent Container) (*ComboBox, error) {
-	cb, err := newComboBoxWithStyle(parent, win.CBS_AUTOHSCROLL|win.CBS_DROPDOWN)
+	return newEditableComboBox(parent, 0)
+}
+
+// NewOwnerDrawComboBox creates an editable ComboBox, whose drop down list
+// items are drawn by the ListItemStyler set with SetItemStyler.
+func NewOwnerDrawComboBox(parent Container) (*ComboBox, error) {
+	return newEditableComboBox(parent, win.CBS_OWNERDRAWVARIABLE|win.CBS_HASSTRINGS)
+}
+
+func newEditableComboBox(parent Container, style uint32) (*ComboBox, error) {
+	cb, err := newComboBoxWithStyle(parent, win.CBS_AUTOHSCROLL|win.CBS_DROPDOWN|style)
 	if err != nil {
 		return nil, err
 	}
@@ -119,8 +137,15 @@ func NewDropDownBox(parent Container) (*ComboBox, error) {
 	return newComboBoxWithStyle(parent, win.CBS_DROPDOWNLIST)
 }
 
+// NewOwnerDrawDropDownBox creates a non-editable ComboBox, whose items are
+// drawn by the ListItemStyler set with SetItemStyler, in the drop down list as
+// well as in the selection field.
+func NewOwnerDrawDropDownBox(parent Container) (*ComboBox, error) {
+	return newComboBoxWithStyle(parent, win.CBS_DROPDOWNLIST|win.CBS_OWNERDRAWVARIABLE|win.CBS_HASSTRINGS)
+}
+
 func newComboBoxWithStyle(parent Container, style uint32) (*ComboBox, error) {
-	cb := &ComboBox{prevCurIndex: -1, selChangeIndex: -1, precision: 2}
+	cb := &ComboBox{prevCurIndex: -1, selChangeIndex: -1, precision: 2, matchHighlightColor: RGB(0xFF, 0x8C, 0x00)}
 
 	if err := InitWidget(
 		cb,
@@ -239,12 +264,102 @@ func newComboBoxWithStyle(parent Container, style uint32) (*ComboBox, error) {
 func (cb *ComboBox) applyFont(font *Font) {
 	cb.WidgetBase.applyFont(font)
 
+	cb.updateItemHeights()
+
 	if cb.model != nil {
 		cb.maxItemTextWidth = cb.calculateMaxItemTextWidth()
 		cb.RequestLayout()
 	}
 }
 
+// ItemStyler returns the ListItemStyler that draws the items.
+func (cb *ComboBox) ItemStyler() ListItemStyler {
+	return cb.styler
+}
+
+// SetItemStyler sets the ListItemStyler that draws the items, e.g. with an
+// icon and a dimmed secondary line of text. It only has an effect on a
+// ComboBox created with NewOwnerDrawComboBox or NewOwnerDrawDropDownBox.
+func (cb *ComboBox) SetItemStyler(styler ListItemStyler) {
+	cb.styler = styler
+
+	cb.updateItemHeights()
+	cb.Invalidate()
+}
+
+func (cb *ComboBox) ownerDraw() bool {
+	return cb.hasStyleBits(win.CBS_OWNERDRAWVARIABLE)
+}
+
+// itemWidth returns the width in native pixels the items are drawn with.
+func (cb *ComboBox) itemWidth() int {
+	return int(int32(cb.SendMessage(win.CB_GETDROPPEDWIDTH, 0, 0)))
+}
+
+func (cb *ComboBox) updateItemHeights() {
+	if cb.styler == nil || !cb.ownerDraw() {
+		return
+	}
+
+	var selectionField int32 = -1
+	if !cb.Editable() {
+		cb.SendMessage(win.CB_SETITEMHEIGHT, uintptr(selectionField), uintptr(cb.styler.DefaultItemHeight()))
+	}
+
+	width := cb.itemWidth()
+	count := int(int32(cb.SendMessage(win.CB_GETCOUNT, 0, 0)))
+	for i := 0; i < count; i++ {
+		cb.SendMessage(win.CB_SETITEMHEIGHT, uintptr(i), uintptr(cb.styler.ItemHeight(cb.modelIndex(i), width)))
+	}
+
+	cb.RequestLayout()
+}
+
+func (cb *ComboBox) drawItem(dis *win.DRAWITEMSTRUCT) {
+	index := cb.modelIndex(int(int32(dis.ItemID)))
+
+	style := ListItemStyle{
+		index:          index,
+		hoverIndex:     -1,
+		rc:             dis.RcItem,
+		bounds:         rectangleFromRECT(dis.RcItem),
+		dpi:            cb.DPI(),
+		state:          dis.ItemState,
+		hwnd:           cb.hWnd,
+		hdc:            dis.HDC,
+		Font:           cb.Font(),
+		selectionField: dis.ItemState&win.ODS_COMBOBOXEDIT != 0,
+	}
+	defer func() {
+		if style.canvas != nil {
+			style.canvas.Dispose()
+		}
+	}()
+
+	if dis.ItemState&win.ODS_SELECTED != 0 {
+		style.BackgroundColor = Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
+		style.TextColor = Color(win.GetSysColor(win.COLOR_HIGHLIGHTTEXT))
+	} else {
+		style.BackgroundColor = Color(win.GetSysColor(win.COLOR_WINDOW))
+		style.TextColor = Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+	}
+	if dis.ItemState&win.ODS_DISABLED != 0 {
+		style.TextColor = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+	style.defaultTextColor = style.TextColor
+	style.LineColor = style.TextColor
+
+	style.DrawBackground()
+
+	if index > -1 && cb.model != nil && index < cb.model.ItemCount() {
+		cb.styler.StyleItem(&style)
+	}
+
+	if style.selectionField && dis.ItemState&win.ODS_FOCUS != 0 {
+		win.DrawFocusRect(dis.HDC, &dis.RcItem)
+	}
+}
+
 func (cb *ComboBox) Editable() bool {
 	return !cb.hasStyleBits(win.CBS_DROPDOWNLIST)
 }
@@ -268,7 +383,10 @@ func (cb *ComboBox) itemString(index int) string {
 }
 
 func (cb *ComboBox) insertItemAt(index int) error {
-	str := cb.itemString(index)
+	return cb.insertItemString(index, cb.itemString(index))
+}
+
+func (cb *ComboBox) insertItemString(index int, str string) error {
 	lp := uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(str)))
 
 	if win.CB_ERR == cb.SendMessage(win.CB_INSERTSTRING, uintptr(index), lp) {
@@ -291,6 +409,8 @@ func (cb *ComboBox) resetItems() error {
 	defer cb.SetSuspended(false)
 
 	cb.selChangeIndex = -1
+	cb.filterIndexes = nil
+	cb.filterText = ""
 
 	if win.FALSE == cb.SendMessage(win.CB_RESETCONTENT, 0, 0) {
 		return newError("SendMessage(CB_RESETCONTENT)")
@@ -331,6 +451,11 @@ func (cb *ComboBox) attachModel() {
 	cb.itemsResetHandlerHandle = cb.model.ItemsReset().Attach(itemsResetHandler)
 
 	itemChangedHandler := func(index int) {
+		if cb.filterIndexes != nil {
+			cb.applyFilter(false)
+			return
+		}
+
 		if win.CB_ERR == cb.SendMessage(win.CB_DELETESTRING, uintptr(index), 0) {
 			newError("SendMessage(CB_DELETESTRING)")
 		}
@@ -342,12 +467,22 @@ func (cb *ComboBox) attachModel() {
 	cb.itemChangedHandlerHandle = cb.model.ItemChanged().Attach(itemChangedHandler)
 
 	cb.itemsInsertedHandlerHandle = cb.model.ItemsInserted().Attach(func(from, to int) {
+		if cb.filterIndexes != nil {
+			cb.applyFilter(false)
+			return
+		}
+
 		for i := from; i <= to; i++ {
 			cb.insertItemAt(i)
 		}
 	})
 
 	cb.itemsRemovedHandlerHandle = cb.model.ItemsRemoved().Attach(func(from, to int) {
+		if cb.filterIndexes != nil {
+			cb.applyFilter(false)
+			return
+		}
+
 		for i := to; i >= from; i-- {
 			cb.removeItem(i)
 		}
@@ -554,10 +689,13 @@ func (cb *ComboBox) calculateMaxItemTextWidth() int {
 }
 
 func (cb *ComboBox) CurrentIndex() int {
-	return int(int32(cb.SendMessage(win.CB_GETCURSEL, 0, 0)))
+	return cb.modelIndex(int(int32(cb.SendMessage(win.CB_GETCURSEL, 0, 0))))
 }
 
 func (cb *ComboBox) SetCurrentIndex(value int) error {
+	// Indexes refer to the model, so the list must contain all items.
+	cb.clearFilter()
+
 	index := int(int32(cb.SendMessage(win.CB_SETCURSEL, uintptr(value), 0)))
 
 	if index != value {
@@ -656,6 +794,30 @@ func (cb *ComboBox) RestoreState() error {
 
 func (cb *ComboBox) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	switch msg {
+	case win.WM_MEASUREITEM:
+		if cb.styler == nil {
+			break
+		}
+
+		mis := (*win.MEASUREITEMSTRUCT)(unsafe.Pointer(lParam))
+
+		if index := int(int32(mis.ItemID)); index < 0 {
+			mis.ItemHeight = uint32(cb.styler.DefaultItemHeight())
+		} else {
+			mis.ItemHeight = uint32(cb.styler.ItemHeight(cb.modelIndex(index), cb.itemWidth()))
+		}
+
+		return win.TRUE
+
+	case win.WM_DRAWITEM:
+		if cb.styler == nil {
+			break
+		}
+
+		cb.drawItem((*win.DRAWITEMSTRUCT)(unsafe.Pointer(lParam)))
+
+		return win.TRUE
+
 	case win.WM_COMMAND:
 		code := win.HIWORD(uint32(wParam))
 		selIndex := cb.CurrentIndex()
@@ -666,6 +828,15 @@ func (cb *ComboBox) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 			cb.selChangeIndex = -1
 			cb.textChangedPublisher.Publish()
 
+			if cb.filterEnabled {
+				cb.applyFilter(true)
+			}
+
+		case win.CBN_CLOSEUP:
+			if cb.filterIndexes != nil {
+				cb.onDropDownClosed()
+			}
+
 		case win.CBN_SELCHANGE:
 			cb.selChangeIndex = selIndex
 			cb.currentIndexChangedPublisher.Publish()
@@ -741,6 +912,11 @@ func (cb *ComboBox) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 	w := maxi(defaultSize.Width, cb.maxItemTextWidth+int(win.GetSystemMetricsForDpi(win.SM_CXVSCROLL, uint32(ctx.dpi)))+8)
 	h := defaultSize.Height + 1
 
+	if cb.styler != nil && cb.ownerDraw() && !cb.Editable() {
+		var selectionField int32 = -1
+		h = maxi(h, int(cb.SendMessage(win.CB_GETITEMHEIGHT, uintptr(selectionField), 0))+6)
+	}
+
 	return &comboBoxLayoutItem{
 		layoutFlags: layoutFlags,
 		idealSize:   Size{w, h},
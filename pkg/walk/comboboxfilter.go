@@ -0,0 +1,293 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// comboBoxInfo is the COMBOBOXINFO struct.
+type comboBoxInfo struct {
+	cbSize      uint32
+	rcItem      win.RECT
+	rcButton    win.RECT
+	stateButton uint32
+	hwndCombo   win.HWND
+	hwndItem    win.HWND
+	hwndList    win.HWND
+}
+
+// comboBoxListTextInset is the distance of the item texts from the left edge
+// of the drop down list, in native pixels.
+const comboBoxListTextInset = 2
+
+var comboBoxListWndProcPtr uintptr
+
+func init() {
+	AppendToWalkInit(func() {
+		comboBoxListWndProcPtr = syscall.NewCallback(comboBoxListWndProc)
+	})
+}
+
+func comboBoxListWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	cb := (*ComboBox)(unsafe.Pointer(win.GetWindowLongPtr(hwnd, win.GWLP_USERDATA)))
+
+	if msg == win.WM_PAINT {
+		result := win.CallWindowProc(cb.listOrigWndProcPtr, hwnd, msg, wParam, lParam)
+
+		cb.paintFilterMatches()
+
+		return result
+	}
+
+	return win.CallWindowProc(cb.listOrigWndProcPtr, hwnd, msg, wParam, lParam)
+}
+
+// FilterEnabled returns if the drop down list of the ComboBox narrows to the
+// items containing the text, while the user types.
+func (cb *ComboBox) FilterEnabled() bool {
+	return cb.filterEnabled
+}
+
+// SetFilterEnabled sets if the drop down list of the ComboBox narrows to the
+// items of the model containing the text, while the user types. The drop down
+// list opens as needed and the matches are highlighted in it.
+//
+// Filtering requires an editable ComboBox.
+func (cb *ComboBox) SetFilterEnabled(enabled bool) error {
+	if enabled == cb.filterEnabled {
+		return nil
+	}
+
+	if enabled {
+		if !cb.Editable() {
+			return newError("filtering requires an editable ComboBox")
+		}
+
+		if cb.listHWnd == 0 {
+			cbi := comboBoxInfo{cbSize: uint32(unsafe.Sizeof(comboBoxInfo{}))}
+			if win.FALSE == cb.SendMessage(win.CB_GETCOMBOBOXINFO, 0, uintptr(unsafe.Pointer(&cbi))) {
+				return newError("SendMessage(CB_GETCOMBOBOXINFO)")
+			}
+
+			cb.listHWnd = cbi.hwndList
+
+			win.SetWindowLongPtr(cb.listHWnd, win.GWLP_USERDATA, uintptr(unsafe.Pointer(cb)))
+			cb.listOrigWndProcPtr = win.SetWindowLongPtr(cb.listHWnd, win.GWLP_WNDPROC, comboBoxListWndProcPtr)
+		}
+	} else {
+		cb.clearFilter()
+	}
+
+	cb.filterEnabled = enabled
+
+	return nil
+}
+
+// MatchHighlightColor returns the color of the frames around the matches of
+// the text in the filtered drop down list.
+func (cb *ComboBox) MatchHighlightColor() Color {
+	return cb.matchHighlightColor
+}
+
+// SetMatchHighlightColor sets the color of the frames around the matches of
+// the text in the filtered drop down list.
+func (cb *ComboBox) SetMatchHighlightColor(c Color) {
+	cb.matchHighlightColor = c
+
+	if cb.listHWnd != 0 {
+		win.InvalidateRect(cb.listHWnd, nil, true)
+	}
+}
+
+// modelIndex returns the index into the model of the item at listIndex of the
+// list.
+func (cb *ComboBox) modelIndex(listIndex int) int {
+	if cb.filterIndexes == nil || listIndex < 0 || listIndex >= len(cb.filterIndexes) {
+		return listIndex
+	}
+
+	return cb.filterIndexes[listIndex]
+}
+
+// applyFilter fills the list with the items of the model containing the
+// text and opens the drop down list if show is true.
+func (cb *ComboBox) applyFilter(show bool) {
+	if cb.model == nil || cb.filtering {
+		return
+	}
+
+	text := cb.Text()
+	if text == "" {
+		cb.clearFilter()
+		return
+	}
+
+	cb.filtering = true
+	defer func() {
+		cb.filtering = false
+	}()
+
+	indexes := []int{}
+	for i, n := 0, cb.model.ItemCount(); i < n; i++ {
+		if len(findInText(utf16.Encode([]rune(cb.itemString(i))), text, 0)) > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+
+	cb.filterIndexes = indexes
+	cb.filterText = text
+
+	start, end := cb.TextSelection()
+
+	cb.fillList()
+
+	dropped := 0 != cb.SendMessage(win.CB_GETDROPPEDSTATE, 0, 0)
+	if show && len(indexes) > 0 && !dropped {
+		cb.SendMessage(win.CB_SHOWDROPDOWN, win.TRUE, 0)
+
+		// Opening the drop down list hides the cursor.
+		win.SetCursor(win.LoadCursor(0, win.MAKEINTRESOURCE(win.IDC_ARROW)))
+	} else if len(indexes) == 0 && dropped {
+		cb.SendMessage(win.CB_SHOWDROPDOWN, win.FALSE, 0)
+	}
+
+	// Filling and opening the list replace the text.
+	cb.setText(text)
+	cb.SetTextSelection(start, end)
+}
+
+// clearFilter fills the list with all items of the model again, keeping the
+// text and current item.
+func (cb *ComboBox) clearFilter() {
+	if cb.filterIndexes == nil || cb.filtering {
+		return
+	}
+
+	cb.filtering = true
+	defer func() {
+		cb.filtering = false
+	}()
+
+	text := cb.Text()
+	start, end := cb.TextSelection()
+	index := cb.CurrentIndex()
+
+	cb.filterIndexes = nil
+	cb.filterText = ""
+
+	cb.fillList()
+
+	if index > -1 {
+		cb.SendMessage(win.CB_SETCURSEL, uintptr(index), 0)
+	}
+
+	cb.setText(text)
+	cb.SetTextSelection(start, end)
+}
+
+// fillList fills the list with the items of the model that pass the filter.
+func (cb *ComboBox) fillList() {
+	defer suspendUpdates(cb)()
+
+	cb.SendMessage(win.CB_RESETCONTENT, 0, 0)
+
+	if cb.filterIndexes == nil {
+		for i, n := 0, cb.model.ItemCount(); i < n; i++ {
+			cb.insertItemAt(i)
+		}
+		return
+	}
+
+	for i, index := range cb.filterIndexes {
+		cb.insertItemString(i, cb.itemString(index))
+	}
+}
+
+// onDropDownClosed restores the unfiltered list, after the selection in the
+// drop down list was processed.
+func (cb *ComboBox) onDropDownClosed() {
+	cb.Synchronize(func() {
+		if cb.IsDisposed() || 0 != cb.SendMessage(win.CB_GETDROPPEDSTATE, 0, 0) {
+			return
+		}
+
+		cb.clearFilter()
+	})
+}
+
+// paintFilterMatches draws frames around the matches of the text in the
+// visible items of the drop down list, after it painted itself.
+func (cb *ComboBox) paintFilterMatches() {
+	// The positions of the texts of styled items are unknown.
+	if cb.filterIndexes == nil || cb.filterText == "" || cb.styler != nil {
+		return
+	}
+
+	hdc := win.GetDC(cb.listHWnd)
+	if hdc == 0 {
+		return
+	}
+	defer win.ReleaseDC(cb.listHWnd, hdc)
+
+	canvas, err := newCanvasFromHDC(hdc)
+	if err != nil {
+		return
+	}
+	defer canvas.Dispose()
+
+	pen, err := NewCosmeticPen(PenSolid, cb.matchHighlightColor)
+	if err != nil {
+		return
+	}
+	defer pen.Dispose()
+
+	hFont := win.HGDIOBJ(win.SendMessage(cb.listHWnd, win.WM_GETFONT, 0, 0))
+	if hFont != 0 {
+		defer win.SelectObject(hdc, win.SelectObject(hdc, hFont))
+	}
+
+	var clientRect win.RECT
+	win.GetClientRect(cb.listHWnd, &clientRect)
+
+	extent := func(text []uint16) int32 {
+		if len(text) == 0 {
+			return 0
+		}
+
+		var size win.SIZE
+		win.GetTextExtentPoint32(hdc, &text[0], int32(len(text)), &size)
+
+		return size.CX
+	}
+
+	top := int(win.SendMessage(cb.listHWnd, win.LB_GETTOPINDEX, 0, 0))
+
+	for i := top; i < len(cb.filterIndexes); i++ {
+		var rc win.RECT
+		if win.LB_ERR == int(int32(win.SendMessage(cb.listHWnd, win.LB_GETITEMRECT, uintptr(i), uintptr(unsafe.Pointer(&rc))))) {
+			break
+		}
+		if rc.Top >= clientRect.Bottom {
+			break
+		}
+
+		text := utf16.Encode([]rune(cb.itemString(cb.filterIndexes[i])))
+
+		for _, m := range findInText(text, cb.filterText, 0) {
+			x := rc.Left + comboBoxListTextInset + extent(text[:m.Start])
+			width := extent(text[m.Start:m.End])
+
+			canvas.DrawRectanglePixels(pen, Rectangle{int(x), int(rc.Top), int(width), int(rc.Bottom - rc.Top)})
+		}
+	}
+}
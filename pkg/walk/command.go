@@ -0,0 +1,101 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+var (
+	commandInvalidatedPublisher EventPublisher
+	commandCount                int
+)
+
+// CommandInvalidated returns the event that makes all commands re-evaluate
+// their CanExecute predicates.
+//
+// It is published by InvalidateCommands, after a command has been executed,
+// before a menu opens and when a form is activated.
+func CommandInvalidated() *Event {
+	return commandInvalidatedPublisher.Event()
+}
+
+// InvalidateCommands makes all commands re-evaluate their CanExecute
+// predicates and update the menu items, tool bar buttons and shortcuts of
+// their Actions, e.g. after the selection or the document changed.
+//
+// It must be called on the UI thread.
+func InvalidateCommands() {
+	commandInvalidatedPublisher.Publish()
+}
+
+// NewCommand returns a new Action that calls execute when it is triggered,
+// and which is enabled while canExecute returns true. The Action can be added
+// to any number of menus, tool bars and ShortcutActions at the same time.
+//
+// canExecute may be nil for commands that can always be executed.
+func NewCommand(execute func(), canExecute func() bool) *Action {
+	a := NewAction()
+
+	if execute != nil {
+		a.Triggered().Attach(execute)
+	}
+
+	a.SetCanExecute(canExecute)
+
+	return a
+}
+
+// CanExecute returns the predicate that decides whether the Action is
+// enabled, or nil.
+func (a *Action) CanExecute() func() bool {
+	return a.canExecute
+}
+
+// SetCanExecute sets the predicate that decides whether the Action is enabled.
+// It replaces the EnabledCondition of the Action and is re-evaluated whenever
+// CommandInvalidated is published.
+func (a *Action) SetCanExecute(canExecute func() bool) {
+	if (a.canExecute == nil) != (canExecute == nil) {
+		if canExecute == nil {
+			commandCount--
+		} else {
+			commandCount++
+		}
+	}
+
+	a.canExecute = canExecute
+
+	if canExecute == nil {
+		a.SetEnabledCondition(nil)
+		return
+	}
+
+	a.SetEnabledCondition(NewDelegateCondition(canExecute, CommandInvalidated()))
+}
+
+// Execute triggers the Action, if its CanExecute predicate, evaluated anew,
+// allows it, and returns whether it did.
+func (a *Action) Execute() bool {
+	enabled := a.Enabled()
+	if a.canExecute != nil {
+		enabled = a.canExecute()
+	}
+
+	if !enabled {
+		return false
+	}
+
+	a.raiseTriggered()
+
+	return true
+}
+
+// invalidateCommandsIfAny publishes CommandInvalidated, if there are Actions
+// with a CanExecute predicate.
+func invalidateCommandsIfAny() {
+	if commandCount > 0 {
+		InvalidateCommands()
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// Command is a change that can be undone and redone, e.g. an edit of a
+// document or of the values of a form.
+type Command interface {
+	// Undo reverts the change.
+	Undo() error
+
+	// Redo applies the change again after Undo.
+	Redo() error
+}
+
+// CommandStack records the Commands that were done, so they can be undone in
+// reverse order and redone.
+type CommandStack struct {
+	undoCommands     []Command
+	redoCommands     []Command
+	changedPublisher EventPublisher
+}
+
+// NewCommandStack returns a new, empty CommandStack.
+func NewCommandStack() *CommandStack {
+	return new(CommandStack)
+}
+
+// Push records cmd, which must already be done, as the one to undo next.
+//
+// The Commands that were undone can't be redone anymore.
+func (cs *CommandStack) Push(cmd Command) {
+	cs.undoCommands = append(cs.undoCommands, cmd)
+	cs.redoCommands = nil
+
+	cs.changedPublisher.Publish()
+}
+
+// CanUndo returns whether there is a Command to undo.
+func (cs *CommandStack) CanUndo() bool {
+	return len(cs.undoCommands) > 0
+}
+
+// CanRedo returns whether there is a Command to redo.
+func (cs *CommandStack) CanRedo() bool {
+	return len(cs.redoCommands) > 0
+}
+
+// Undo undoes the last Command that was done or redone, if any.
+func (cs *CommandStack) Undo() error {
+	n := len(cs.undoCommands)
+	if n == 0 {
+		return nil
+	}
+
+	cmd := cs.undoCommands[n-1]
+	if err := cmd.Undo(); err != nil {
+		return err
+	}
+
+	cs.undoCommands = cs.undoCommands[:n-1]
+	cs.redoCommands = append(cs.redoCommands, cmd)
+
+	cs.changedPublisher.Publish()
+
+	return nil
+}
+
+// Redo redoes the last Command that was undone, if any.
+func (cs *CommandStack) Redo() error {
+	n := len(cs.redoCommands)
+	if n == 0 {
+		return nil
+	}
+
+	cmd := cs.redoCommands[n-1]
+	if err := cmd.Redo(); err != nil {
+		return err
+	}
+
+	cs.redoCommands = cs.redoCommands[:n-1]
+	cs.undoCommands = append(cs.undoCommands, cmd)
+
+	cs.changedPublisher.Publish()
+
+	return nil
+}
+
+// Clear forgets all Commands, e.g. after a document was saved or reloaded.
+func (cs *CommandStack) Clear() {
+	cs.undoCommands = nil
+	cs.redoCommands = nil
+
+	cs.changedPublisher.Publish()
+}
+
+// Changed returns the event that is published when Commands are pushed,
+// undone, redone or cleared, e.g. to update the enabled state of undo and
+// redo actions.
+func (cs *CommandStack) Changed() *Event {
+	return cs.changedPublisher.Event()
+}
@@ -199,3 +199,48 @@ func (dlg *FileDialog) ShowBrowseFolder(owner Form) (accepted bool, err error) {
 	accepted = dlg.FilePath != ""
 	return
 }
+
+// ColorDialog wraps the common color dialog of Windows.
+type ColorDialog struct {
+	Color        Color
+	CustomColors [16]Color
+	FullOpen     bool
+}
+
+// ShowChoose shows the dialog, initialized with dlg.Color. If the user
+// accepts, the chosen color is stored in dlg.Color.
+func (dlg *ColorDialog) ShowChoose(owner Form) (accepted bool, err error) {
+	var custColors [16]win.COLORREF
+	for i, c := range dlg.CustomColors {
+		custColors[i] = win.COLORREF(c)
+	}
+
+	cc := win.CHOOSECOLOR{
+		RgbResult:    win.COLORREF(dlg.Color),
+		LpCustColors: &custColors,
+		Flags:        win.CC_RGBINIT,
+	}
+	cc.LStructSize = uint32(unsafe.Sizeof(cc))
+	if owner != nil {
+		cc.HwndOwner = owner.Handle()
+	}
+	if dlg.FullOpen {
+		cc.Flags |= win.CC_FULLOPEN
+	}
+
+	if !win.ChooseColor(&cc) {
+		if errno := win.CommDlgExtendedError(); errno != 0 {
+			err = newError(fmt.Sprintf("Error %d", errno))
+		}
+		return
+	}
+
+	dlg.Color = Color(cc.RgbResult)
+	for i, c := range custColors {
+		dlg.CustomColors[i] = Color(c)
+	}
+
+	accepted = true
+
+	return
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// Connection is a handler attached to an event by Connect or AttachOwned.
+//
+// Unlike the handle returned by Attach, it can't detach another handler
+// that later took the place of its own, so it is safe to detach it more than
+// once, e.g. both explicitly and when its owner is disposed.
+type Connection struct {
+	detach func()
+}
+
+// Connected returns whether the handler is still attached.
+func (c *Connection) Connected() bool {
+	return c.detach != nil
+}
+
+// Detach detaches the handler from the event. Calling it again has no effect.
+func (c *Connection) Detach() {
+	if detach := c.detach; detach != nil {
+		c.detach = nil
+		detach()
+	}
+}
+
+// BindTo binds the Connection to the lifetime of owner, so the handler is
+// detached when owner is disposed, e.g. a dialog that handles an event of
+// the application or of a model that outlives it.
+func (c *Connection) BindTo(owner Window) *Connection {
+	if c.detach == nil || owner == nil {
+		return c
+	}
+
+	if owner.IsDisposed() {
+		c.Detach()
+		return c
+	}
+
+	detach := c.detach
+	handle := owner.Disposing().Attach(c.Detach)
+
+	c.detach = func() {
+		detach()
+
+		if !owner.IsDisposed() {
+			owner.Disposing().Detach(handle)
+		}
+	}
+
+	return c
+}
@@ -27,11 +27,15 @@ type Container interface {
 
 type ContainerBase struct {
 	WidgetBase
-	layout      Layout
-	children    *WidgetList
-	dataBinder  *DataBinder
-	nextChildID int32
-	persistent  bool
+	layout                  Layout
+	children                *WidgetList
+	dataBinder              *DataBinder
+	nextChildID             int32
+	persistent              bool
+	layoutAnimationDuration time.Duration
+	layoutAnimationEasing   Easing
+	layoutAnimator          *layoutAnimator
+	border                  Border
 }
 
 func (cb *ContainerBase) AsWidgetBase() *WidgetBase {
@@ -208,6 +212,12 @@ func (cb *ContainerBase) doPaint() error {
 	}
 	defer canvas.Dispose()
 
+	if cb.border.Style != BorderNone {
+		if err := cb.drawBorder(canvas); err != nil {
+			return err
+		}
+	}
+
 	for _, wb := range cb.children.items {
 		widget := wb.window.(Widget)
 
@@ -272,13 +282,13 @@ func (cb *ContainerBase) doPaint() error {
 
 func (cb *ContainerBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	switch msg {
-	case win.WM_CTLCOLOREDIT, win.WM_CTLCOLORSTATIC:
+	case win.WM_CTLCOLOREDIT, win.WM_CTLCOLORSTATIC, win.WM_CTLCOLORLISTBOX:
 		if hBrush := cb.handleWMCTLCOLOR(wParam, lParam); hBrush != 0 {
 			return hBrush
 		}
 
 	case win.WM_PAINT:
-		if FocusEffect == nil && InteractionEffect == nil && ValidationErrorEffect == nil {
+		if FocusEffect == nil && InteractionEffect == nil && ValidationErrorEffect == nil && cb.border.Style == BorderNone {
 			break
 		}
 
@@ -378,11 +388,11 @@ func (cb *ContainerBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintp
 	case win.WM_WINDOWPOSCHANGED:
 		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
 
-		if wp.Flags&win.SWP_NOSIZE != 0 || cb.Layout() == nil {
+		if wp.Flags&win.SWP_NOSIZE != 0 {
 			break
 		}
 
-		if cb.background == nullBrushSingleton {
+		if cb.border.Style != BorderNone || cb.Layout() != nil && cb.background == nullBrushSingleton {
 			cb.Invalidate()
 		}
 	}
@@ -457,12 +467,10 @@ func (cb *ContainerBase) focusFirstCandidateDescendant() {
 	}
 
 	if textSel, ok := window.(textSelectable); ok {
-		time.AfterFunc(time.Millisecond, func() {
-			window.Synchronize(func() {
-				if window.Focused() {
-					textSel.SetTextSelection(0, -1)
-				}
-			})
+		afterUI(window, time.Millisecond, func() {
+			if window.Focused() {
+				textSel.SetTextSelection(0, -1)
+			}
 		})
 	}
 }
@@ -0,0 +1,185 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+
+	"github.com/miu200521358/win"
+)
+
+// BorderStyle specifies how the Border of a container is drawn.
+type BorderStyle int
+
+const (
+	BorderNone BorderStyle = iota
+	BorderSingle
+	BorderSunken
+	BorderRaised
+	BorderRounded
+)
+
+// Border describes a frame that walk draws along the edges of the client area
+// of a container. The layout of the container keeps its children clear of it.
+type Border struct {
+	Style BorderStyle
+
+	// Color is the color of BorderSingle and BorderRounded. The zero value
+	// stands for the system color of 3D shadows, or ThemeRoleBorder of the
+	// application theme, so the border follows the theme.
+	Color Color
+
+	// Radius is the corner radius of BorderRounded in 1/96" units.
+	Radius int
+}
+
+// Border returns the Border drawn along the edges of the container.
+func (cb *ContainerBase) Border() Border {
+	return cb.border
+}
+
+// SetBorder sets the Border drawn along the edges of the container.
+func (cb *ContainerBase) SetBorder(border Border) error {
+	if border.Style < BorderNone || border.Style > BorderRounded {
+		return newError("invalid border style")
+	}
+	if border.Radius < 0 {
+		return newError("radius must be >= 0")
+	}
+
+	if border == cb.border {
+		return nil
+	}
+
+	cb.border = border
+
+	cb.Invalidate()
+	cb.RequestLayout()
+
+	return nil
+}
+
+// insets96dpi returns the space the Border takes from each edge of the client
+// area, in 1/96" units.
+func (b Border) insets96dpi() Margins {
+	var inset int
+
+	switch b.Style {
+	case BorderSingle:
+		inset = 1
+
+	case BorderSunken, BorderRaised:
+		inset = 2
+
+	case BorderRounded:
+		// Keep the corners of children inside the arcs.
+		inset = 1 + int(math.Ceil(float64(b.Radius)*(1-math.Sqrt2/2)))
+	}
+
+	return Margins{inset, inset, inset, inset}
+}
+
+// drawBorder draws the Border of the container on canvas.
+func (cb *ContainerBase) drawBorder(canvas *Canvas) error {
+	border := cb.border
+	bounds := cb.ClientBoundsPixels()
+
+	if bounds.Width <= 0 || bounds.Height <= 0 {
+		return nil
+	}
+
+	color := border.Color
+	if color == 0 {
+		color = themeSysColor(win.COLOR_BTNSHADOW)
+	}
+
+	switch border.Style {
+	case BorderSingle, BorderRounded:
+		brush, err := NewSolidColorBrush(color)
+		if err != nil {
+			return err
+		}
+		defer brush.Dispose()
+
+		pen, err := NewGeometricPen(PenSolid|PenInsideFrame, cb.IntFrom96DPI(1), brush)
+		if err != nil {
+			return err
+		}
+		defer pen.Dispose()
+
+		if border.Style == BorderSingle {
+			return canvas.DrawRectanglePixels(pen, bounds)
+		}
+
+		diameter := 2 * cb.IntFrom96DPI(border.Radius)
+
+		return canvas.DrawRoundedRectanglePixels(pen, bounds, Size{Width: diameter, Height: diameter})
+
+	case BorderSunken, BorderRaised:
+		shadow := themeSysColor(win.COLOR_BTNSHADOW)
+		darkShadow := Color(win.GetSysColor(win.COLOR_3DDKSHADOW))
+		light := Color(win.GetSysColor(win.COLOR_3DLIGHT))
+		highlight := Color(win.GetSysColor(win.COLOR_BTNHIGHLIGHT))
+
+		// Sunken frames are dark at the top left, raised ones at the bottom
+		// right.
+		outerTopLeft, outerBottomRight := shadow, highlight
+		innerTopLeft, innerBottomRight := darkShadow, light
+		if border.Style == BorderRaised {
+			outerTopLeft, outerBottomRight = light, darkShadow
+			innerTopLeft, innerBottomRight = highlight, shadow
+		}
+
+		width := cb.IntFrom96DPI(1)
+
+		if err := drawBevelPixels(canvas, bounds, width, outerTopLeft, outerBottomRight); err != nil {
+			return err
+		}
+
+		bounds.X += width
+		bounds.Y += width
+		bounds.Width -= 2 * width
+		bounds.Height -= 2 * width
+
+		return drawBevelPixels(canvas, bounds, width, innerTopLeft, innerBottomRight)
+	}
+
+	return nil
+}
+
+// drawBevelPixels draws a frame of width along the edges of bounds, with
+// topLeft used for the top and left edges and bottomRight for the others.
+func drawBevelPixels(canvas *Canvas, bounds Rectangle, width int, topLeft, bottomRight Color) error {
+	topLeftBrush, err := NewSolidColorBrush(topLeft)
+	if err != nil {
+		return err
+	}
+	defer topLeftBrush.Dispose()
+
+	bottomRightBrush, err := NewSolidColorBrush(bottomRight)
+	if err != nil {
+		return err
+	}
+	defer bottomRightBrush.Dispose()
+
+	for _, edge := range []struct {
+		brush  Brush
+		bounds Rectangle
+	}{
+		{topLeftBrush, Rectangle{X: bounds.X, Y: bounds.Y, Width: bounds.Width, Height: width}},
+		{topLeftBrush, Rectangle{X: bounds.X, Y: bounds.Y, Width: width, Height: bounds.Height}},
+		{bottomRightBrush, Rectangle{X: bounds.X, Y: bounds.Y + bounds.Height - width, Width: bounds.Width, Height: width}},
+		{bottomRightBrush, Rectangle{X: bounds.X + bounds.Width - width, Y: bounds.Y, Width: width, Height: bounds.Height}},
+	} {
+		if err := canvas.FillRectanglePixels(edge.brush, edge.bounds); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+
+	credUIFlagsDoNotPersist        = 0x2
+	credUIFlagsExcludeCertificates = 0x8
+	credUIFlagsShowSaveCheckBox    = 0x40
+	credUIFlagsAlwaysShowUI        = 0x80
+	credUIFlagsGenericCredentials  = 0x40000
+	credUIMaxUserNameLength        = 513
+	credUIMaxPasswordLength        = 256
+
+	errorNotFound  = 1168
+	errorCancelled = 1223
+)
+
+var (
+	libadvapi32                 = syscall.NewLazyDLL("advapi32.dll")
+	credWriteW                  = libadvapi32.NewProc("CredWriteW")
+	credReadW                   = libadvapi32.NewProc("CredReadW")
+	credDeleteW                 = libadvapi32.NewProc("CredDeleteW")
+	credFree                    = libadvapi32.NewProc("CredFree")
+	credUIPromptForCredentialsW = syscall.NewLazyDLL("credui.dll").NewProc("CredUIPromptForCredentialsW")
+)
+
+// credential mirrors CREDENTIALW.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWrittenLow     uint32
+	LastWrittenHigh    uint32
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credUIInfo mirrors CREDUI_INFOW.
+type credUIInfo struct {
+	CbSize         uint32
+	HwndParent     win.HWND
+	PszMessageText *uint16
+	PszCaptionText *uint16
+	HbmBanner      win.HBITMAP
+}
+
+// ErrCredentialNotFound is returned by LoadCredential and DeleteCredential
+// if there is no credential for the target in the Windows Credential Manager.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// Credential is a secret of the current user, e.g. a password or an access
+// token for a service, kept in the Windows Credential Manager instead of in
+// plain settings.
+type Credential struct {
+	// Target identifies the credential, e.g. "MyApp/api.example.com".
+	Target string
+
+	// UserName is the account the secret belongs to. It may be empty.
+	UserName string
+
+	// Secret is protected by Windows with the logon credentials of the user.
+	// It holds at most 2560 bytes.
+	Secret []byte
+
+	// Comment is shown in the Credential Manager of the Control Panel.
+	Comment string
+}
+
+// StoreCredential stores cred in the Windows Credential Manager, as a generic
+// credential that persists for the current user on this computer. A
+// credential with the same target is replaced.
+func StoreCredential(cred *Credential) error {
+	if cred.Target == "" {
+		return newError("credential target cannot be empty")
+	}
+
+	target, err := syscall.UTF16PtrFromString(cred.Target)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	c := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(cred.Secret)),
+		Persist:            credPersistLocalMachine,
+	}
+
+	if len(cred.Secret) > 0 {
+		c.CredentialBlob = &cred.Secret[0]
+	}
+
+	if cred.UserName != "" {
+		if c.UserName, err = syscall.UTF16PtrFromString(cred.UserName); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	if cred.Comment != "" {
+		if c.Comment, err = syscall.UTF16PtrFromString(cred.Comment); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	if ret, _, errno := credWriteW.Call(uintptr(unsafe.Pointer(&c)), 0); ret == 0 {
+		return newError("CredWrite: " + errno.Error())
+	}
+
+	return nil
+}
+
+// LoadCredential returns the credential for target from the Windows
+// Credential Manager, or ErrCredentialNotFound if there is none.
+func LoadCredential(target string) (*Credential, error) {
+	t, err := syscall.UTF16PtrFromString(target)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	var c *credential
+	if ret, _, errno := credReadW.Call(uintptr(unsafe.Pointer(t)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&c))); ret == 0 {
+		if errno == syscall.Errno(errorNotFound) {
+			return nil, ErrCredentialNotFound
+		}
+
+		return nil, newError("CredRead: " + errno.Error())
+	}
+	defer credFree.Call(uintptr(unsafe.Pointer(c)))
+
+	cred := &Credential{
+		Target:   target,
+		UserName: win.UTF16PtrToString(c.UserName),
+		Comment:  win.UTF16PtrToString(c.Comment),
+	}
+
+	if c.CredentialBlobSize > 0 {
+		blob := unsafe.Slice(c.CredentialBlob, c.CredentialBlobSize)
+
+		cred.Secret = make([]byte, len(blob))
+		copy(cred.Secret, blob)
+	}
+
+	return cred, nil
+}
+
+// DeleteCredential removes the credential for target from the Windows
+// Credential Manager, or returns ErrCredentialNotFound if there is none.
+func DeleteCredential(target string) error {
+	t, err := syscall.UTF16PtrFromString(target)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if ret, _, errno := credDeleteW.Call(uintptr(unsafe.Pointer(t)), credTypeGeneric, 0); ret == 0 {
+		if errno == syscall.Errno(errorNotFound) {
+			return ErrCredentialNotFound
+		}
+
+		return newError("CredDelete: " + errno.Error())
+	}
+
+	return nil
+}
+
+// CredentialDialog asks the user for a user name and password with the
+// standard credentials dialog of Windows.
+//
+// The dialog doesn't store anything itself. If Save is true after it was
+// accepted, store the credential with StoreCredential.
+type CredentialDialog struct {
+	// Title is the caption of the dialog.
+	Title string
+
+	// Message is shown above the input fields, e.g. what the credentials
+	// are needed for.
+	Message string
+
+	// Target names the service, e.g. "api.example.com". It is shown as the
+	// default message if Message is empty.
+	Target string
+
+	// UserName is the initial user name and, after the dialog was
+	// accepted, the one entered.
+	UserName string
+
+	// Password is the password entered, after the dialog was accepted.
+	Password string
+
+	// SaveCheckBoxVisible shows a "Remember my credentials" check box.
+	SaveCheckBoxVisible bool
+
+	// Save is the initial and, after the dialog was accepted, final state of
+	// the save check box.
+	Save bool
+}
+
+// ShowModal shows the dialog modal to owner. It returns false if the user
+// canceled it.
+func (dlg *CredentialDialog) ShowModal(owner Form) (accepted bool, err error) {
+	info := credUIInfo{
+		CbSize: uint32(unsafe.Sizeof(credUIInfo{})),
+	}
+	if owner != nil {
+		info.HwndParent = owner.Handle()
+	}
+	if dlg.Title != "" {
+		info.PszCaptionText = syscall.StringToUTF16Ptr(dlg.Title)
+	}
+	if dlg.Message != "" {
+		info.PszMessageText = syscall.StringToUTF16Ptr(dlg.Message)
+	}
+
+	target := dlg.Target
+	if target == "" {
+		target = dlg.Title
+	}
+
+	userName := make([]uint16, credUIMaxUserNameLength+1)
+	copy(userName[:credUIMaxUserNameLength], syscall.StringToUTF16(dlg.UserName))
+
+	password := make([]uint16, credUIMaxPasswordLength+1)
+	defer zeroUTF16(password)
+
+	flags := uint32(credUIFlagsGenericCredentials | credUIFlagsDoNotPersist | credUIFlagsExcludeCertificates | credUIFlagsAlwaysShowUI)
+	if dlg.SaveCheckBoxVisible {
+		flags |= credUIFlagsShowSaveCheckBox
+	}
+
+	save := win.BoolToBOOL(dlg.Save)
+
+	ret, _, _ := credUIPromptForCredentialsW.Call(
+		uintptr(unsafe.Pointer(&info)),
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(target))),
+		0,
+		0,
+		uintptr(unsafe.Pointer(&userName[0])),
+		uintptr(len(userName)),
+		uintptr(unsafe.Pointer(&password[0])),
+		uintptr(len(password)),
+		uintptr(unsafe.Pointer(&save)),
+		uintptr(flags))
+
+	switch ret {
+	case 0:
+		dlg.UserName = syscall.UTF16ToString(userName)
+		dlg.Password = syscall.UTF16ToString(password)
+		dlg.Save = save != 0
+
+		return true, nil
+
+	case errorCancelled:
+		return false, nil
+	}
+
+	return false, newError("CredUIPromptForCredentials: " + syscall.Errno(ret).Error())
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -37,10 +38,14 @@ type DataBinder struct {
 	submittedPublisher         EventPublisher
 	resetPublisher             EventPublisher
 	autoSubmitDelay            time.Duration
-	autoSubmitTimer            *time.Timer
+	autoSubmitTimer            *UITimer
+	widget2AutoSubmitTimer     map[Widget]*UITimer
+	validationState            *ValidationState
+	validationStateHandle      int
 	autoSubmit                 bool
 	autoSubmitSuspended        bool
 	canSubmit                  bool
+	bindingsInvalid            bool
 	inReset                    bool
 	dirty                      bool
 }
@@ -72,6 +77,34 @@ func (db *DataBinder) SetAutoSubmitDelay(delay time.Duration) {
 	db.autoSubmitDelay = delay
 }
 
+// AutoSubmit returns whether the properties of the Widget bound to the data
+// source of a DataBinder are submitted as they change.
+func (wb *WidgetBase) AutoSubmit() bool {
+	return wb.autoSubmit
+}
+
+// SetAutoSubmit sets whether the properties of the Widget bound to the data
+// source of a DataBinder are submitted as they change, e.g. while the user
+// types into a NumberEdit or drags a Slider, even if the DataBinder itself
+// doesn't AutoSubmit. Values that fail validation are not submitted.
+func (wb *WidgetBase) SetAutoSubmit(autoSubmit bool) {
+	wb.autoSubmit = autoSubmit
+}
+
+// AutoSubmitDelay returns for how long changes of the properties of the
+// Widget have to pause before they are submitted, if it has AutoSubmit set.
+func (wb *WidgetBase) AutoSubmitDelay() time.Duration {
+	return wb.autoSubmitDelay
+}
+
+// SetAutoSubmitDelay sets for how long changes of the properties of the
+// Widget have to pause before they are submitted, if it has AutoSubmit set,
+// so that e.g. an expensive model update doesn't run on every keystroke. With
+// 0 they are submitted right away.
+func (wb *WidgetBase) SetAutoSubmitDelay(delay time.Duration) {
+	wb.autoSubmitDelay = delay
+}
+
 func (db *DataBinder) AutoSubmitSuspended() bool {
 	return db.autoSubmitSuspended
 }
@@ -85,7 +118,7 @@ func (db *DataBinder) SetAutoSubmitSuspended(suspended bool) {
 
 	if suspended {
 		if db.autoSubmitTimer != nil {
-			db.autoSubmitTimer.Stop()
+			db.autoSubmitTimer.Pause()
 		}
 	} else {
 		db.Submit()
@@ -144,8 +177,25 @@ func (db *DataBinder) SetBoundWidgets(boundWidgets []Widget) {
 		prop.Changed().Detach(handle)
 	}
 
+	for _, t := range db.widget2AutoSubmitTimer {
+		t.Dispose()
+	}
+	db.widget2AutoSubmitTimer = nil
+
+	if db.validationState != nil {
+		db.validationState.Changed().Detach(db.validationStateHandle)
+		db.validationState = nil
+	}
+
 	db.boundWidgets = boundWidgets
 
+	if len(boundWidgets) > 0 {
+		if vs := boundWidgets[0].AsWidgetBase().validationState(); vs != nil {
+			db.validationState = vs
+			db.validationStateHandle = vs.Changed().Attach(db.updateCanSubmit)
+		}
+	}
+
 	db.property2Widget = make(map[Property]Widget)
 	db.property2ChangedHandle = make(map[Property]int)
 
@@ -164,16 +214,21 @@ func (db *DataBinder) SetBoundWidgets(boundWidgets []Widget) {
 			db.property2ChangedHandle[prop] = prop.Changed().Attach(func() {
 				db.dirty = true
 
-				if db.autoSubmit && !db.autoSubmitSuspended {
+				if widget.AsWidgetBase().autoSubmit && !db.autoSubmitSuspended && !db.inReset {
+					db.autoSubmitWidget(widget)
+				} else if db.autoSubmit && !db.autoSubmitSuspended {
 					if db.autoSubmitDelay > 0 {
-						if db.autoSubmitTimer == nil {
-							db.autoSubmitTimer = time.AfterFunc(db.autoSubmitDelay, func() {
-								widget.Synchronize(func() {
-									db.Submit()
-								})
-							})
+						if db.autoSubmitTimer == nil || db.autoSubmitTimer.IsDisposed() {
+							if t, err := NewUITimer(db.autoSubmitDelay, func() {
+								db.autoSubmitTimer.Pause()
+								db.Submit()
+							}); err == nil {
+								t.SetOwner(widget)
+								db.autoSubmitTimer = t
+							}
 						} else {
-							db.autoSubmitTimer.Reset(db.autoSubmitDelay)
+							db.autoSubmitTimer.SetInterval(db.autoSubmitDelay)
+							db.autoSubmitTimer.Restart()
 						}
 					} else {
 						v := reflect.ValueOf(db.dataSource)
@@ -198,6 +253,73 @@ func (db *DataBinder) SetBoundWidgets(boundWidgets []Widget) {
 	}
 }
 
+// autoSubmitWidget submits the properties of widget, which has AutoSubmit
+// set, right away or once its AutoSubmitDelay passed without further changes.
+func (db *DataBinder) autoSubmitWidget(widget Widget) {
+	delay := widget.AsWidgetBase().autoSubmitDelay
+	if delay <= 0 {
+		db.submitWidget(widget)
+		return
+	}
+
+	if t := db.widget2AutoSubmitTimer[widget]; t != nil && !t.IsDisposed() {
+		t.SetInterval(delay)
+		t.Restart()
+		return
+	}
+
+	t, err := NewUITimer(delay, func() {
+		db.widget2AutoSubmitTimer[widget].Pause()
+		db.submitWidget(widget)
+	})
+	if err != nil {
+		db.submitWidget(widget)
+		return
+	}
+	t.SetOwner(widget)
+
+	if db.widget2AutoSubmitTimer == nil {
+		db.widget2AutoSubmitTimer = make(map[Widget]*UITimer)
+	}
+	db.widget2AutoSubmitTimer[widget] = t
+}
+
+// submitWidget submits the valid properties of widget to the data source.
+func (db *DataBinder) submitWidget(widget Widget) {
+	if db.dataSource == nil {
+		return
+	}
+
+	db.validateProperties()
+
+	v := reflect.ValueOf(db.dataSource)
+
+	var submitted bool
+	for _, prop := range db.properties {
+		if db.property2Widget[prop] != widget {
+			continue
+		}
+		if validator := prop.Validator(); validator != nil && validator.Validate(prop.Get()) != nil {
+			continue
+		}
+
+		field := db.fieldBoundToProperty(v, prop)
+		if field == nil {
+			continue
+		}
+
+		if err := db.submitProperty(prop, field); err != nil {
+			continue
+		}
+
+		submitted = true
+	}
+
+	if submitted {
+		db.submittedPublisher.Publish()
+	}
+}
+
 func (db *DataBinder) Expression(path string) Expression {
 	if db.path2Expression == nil {
 		db.path2Expression = make(map[string]Expression)
@@ -217,24 +339,58 @@ func (db *DataBinder) Expression(path string) Expression {
 func (db *DataBinder) validateProperties() {
 	var hasError bool
 
+	// Errors are shown by the ValidationState of the form, unless the
+	// DataBinder has an ErrorPresenter of its own.
+	present := db.errorPresenter == nil
+
 	for _, prop := range db.properties {
 		validator := prop.Validator()
-		if validator == nil {
+		converter := propertyConverter(prop)
+		if validator == nil && converter == nil {
 			continue
 		}
 
-		err := validator.Validate(prop.Get())
+		var err error
+		if validator != nil {
+			err = validator.Validate(prop.Get())
+		}
+		if err == nil && converter != nil {
+			_, err = converter.FromProperty(prop.Get())
+		}
 		if err != nil {
 			hasError = true
 		}
 
-		if db.errorPresenter != nil {
-			widget := db.property2Widget[prop]
+		widget := db.property2Widget[prop]
+
+		if db.validationState != nil {
+			db.validationState.setError(widget, prop, err, present)
+		}
 
+		if db.errorPresenter != nil {
 			db.errorPresenter.PresentError(err, widget)
 		}
 	}
 
+	db.bindingsInvalid = hasError
+
+	db.updateCanSubmit()
+}
+
+// updateCanSubmit updates CanSubmit from the validation errors of the
+// bindings and of the validators the bound widgets registered themselves.
+func (db *DataBinder) updateCanSubmit() {
+	hasError := db.bindingsInvalid
+
+	if !hasError && db.validationState != nil {
+		for _, widget := range db.boundWidgets {
+			if db.validationState.Error(widget) != nil {
+				hasError = true
+				break
+			}
+		}
+	}
+
 	if hasError == db.canSubmit {
 		db.canSubmit = !hasError
 		db.canSubmitChangedPublisher.Publish()
@@ -264,8 +420,16 @@ func (db *DataBinder) Reset() error {
 	}()
 
 	if err := db.forEach(func(prop Property, field DataField) error {
+		value := field.Get()
+		if converter := propertyConverter(prop); converter != nil {
+			var err error
+			if value, err = converter.ToProperty(value); err != nil {
+				return err
+			}
+		}
+
 		if f64, ok := prop.Get().(float64); ok {
-			switch v := field.Get().(type) {
+			switch v := value.(type) {
 			case float32:
 				f64 = float64(v)
 
@@ -306,14 +470,14 @@ func (db *DataBinder) Reset() error {
 				f64 = float64(v)
 
 			default:
-				return newError(fmt.Sprintf("Field '%s': Can't convert %T to float64.", prop.Source().(string), field.Get()))
+				return newError(fmt.Sprintf("Field '%s': Can't convert %T to float64.", prop.Source().(string), value))
 			}
 
 			if err := prop.Set(f64); err != nil {
 				return err
 			}
 		} else {
-			if err := prop.Set(field.Get()); err != nil {
+			if err := prop.Set(value); err != nil {
 				return err
 			}
 		}
@@ -365,6 +529,15 @@ func (db *DataBinder) submitProperty(prop Property, field DataField) error {
 	}
 
 	value := prop.Get()
+	if err, ok := value.(error); ok {
+		return err
+	}
+	if converter := propertyConverter(prop); converter != nil {
+		var err error
+		if value, err = converter.FromProperty(value); err != nil {
+			return err
+		}
+	}
 	if value == nil {
 		if _, ok := db.property2Widget[prop].(*RadioButton); ok {
 			return nil
@@ -372,13 +545,19 @@ func (db *DataBinder) submitProperty(prop Property, field DataField) error {
 
 		return field.Set(field.Zero())
 	}
-	if err, ok := value.(error); ok {
-		return err
-	}
 
 	return field.Set(value)
 }
 
+// propertyConverter returns the BindingConverter of prop, or nil.
+func propertyConverter(prop Property) BindingConverter {
+	if cp, ok := prop.(ConvertibleProperty); ok {
+		return cp.Converter()
+	}
+
+	return nil
+}
+
 func (db *DataBinder) forEach(f func(prop Property, field DataField) error) error {
 	dsv := reflect.ValueOf(db.dataSource)
 	if dsv.Kind() == reflect.Ptr && dsv.IsNil() {
@@ -455,12 +634,25 @@ func reflectValueFromPath(root reflect.Value, path string) (parent, value reflec
 		var name string
 		name, path = nextPathPart(path)
 
+		var indices []int
+		if name, indices, err = splitPathIndices(name); err != nil {
+			return parent, value, fmt.Errorf("%s, path: '%s'", err, fullPath)
+		}
+
 		var p reflect.Value
 		for value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr {
 			p = value
 			value = value.Elem()
 		}
 
+		if name == "" {
+			// An index into the root, as in "[2].Name".
+			if parent, value, err = indexValue(value, indices); err != nil {
+				return parent, value, fmt.Errorf("%s, path: '%s'", err, fullPath)
+			}
+			continue
+		}
+
 		switch value.Kind() {
 		case reflect.Map:
 			parent = value
@@ -526,11 +718,72 @@ func reflectValueFromPath(root reflect.Value, path string) (parent, value reflec
 				}
 			}
 		}
+
+		if len(indices) > 0 {
+			if parent, value, err = indexValue(value, indices); err != nil {
+				return parent, value, fmt.Errorf("%s, path: '%s'", err, fullPath)
+			}
+		}
 	}
 
 	return parent, value, nil
 }
 
+// splitPathIndices splits a path part like "Items[2][0]" into its name and
+// the indices that follow it.
+func splitPathIndices(part string) (name string, indices []int, err error) {
+	i := strings.IndexByte(part, '[')
+	if i == -1 {
+		return part, nil, nil
+	}
+
+	name, rest := part[:i], part[i:]
+
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end == -1 {
+			return "", nil, fmt.Errorf("bad index expression: '%s'", part)
+		}
+
+		index, err := strconv.Atoi(rest[1:end])
+		if err != nil || index < 0 {
+			return "", nil, fmt.Errorf("bad index: '%s'", rest[1:end])
+		}
+
+		indices = append(indices, index)
+		rest = rest[end+1:]
+	}
+
+	return name, indices, nil
+}
+
+// indexValue applies indices to the slice or array value, returning the last
+// slice or array indexed as parent.
+func indexValue(value reflect.Value, indices []int) (parent, elem reflect.Value, err error) {
+	elem = value
+
+	for _, index := range indices {
+		for elem.Kind() == reflect.Interface || elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+
+		switch elem.Kind() {
+		case reflect.Slice, reflect.Array:
+			if index >= elem.Len() {
+				return parent, elem, fmt.Errorf("index out of range: %d, length: %d", index, elem.Len())
+			}
+
+			parent = elem
+			elem = elem.Index(index)
+
+		default:
+			return parent, elem, fmt.Errorf("can't index %s", elem.Kind())
+		}
+	}
+
+	return parent, elem, nil
+}
+
 func nextPathPart(p string) (next, remaining string) {
 	for i, r := range p {
 		if r == '.' {
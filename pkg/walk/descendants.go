@@ -0,0 +1,109 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// TreeTraversalDirective tells WalkDescendants how to go on after visiting a
+// widget.
+type TreeTraversalDirective int
+
+const (
+	// TraverseContinue visits the children of the widget, then its siblings.
+	TraverseContinue TreeTraversalDirective = iota
+
+	// TraverseSkipChildren goes on with the siblings of the widget.
+	TraverseSkipChildren
+
+	// TraverseStop ends the traversal.
+	TraverseStop
+)
+
+// WalkDescendants calls visit for each descendant widget of container, depth
+// first in the order of the widget tree, e.g. to disable all inputs of a
+// panel. The pages of a TabWidget count as its children.
+//
+// visit must not add or remove widgets of the tree while it is traversed.
+func WalkDescendants(container Container, visit func(w Widget) TreeTraversalDirective) {
+	if container == nil {
+		return
+	}
+
+	root := container.AsWindowBase().window
+
+	var stopped bool
+	walkDescendants(container, func(w Window) bool {
+		if stopped {
+			return false
+		}
+		if w == root {
+			return true
+		}
+
+		widget, ok := w.(Widget)
+		if !ok {
+			return false
+		}
+
+		switch visit(widget) {
+		case TraverseSkipChildren:
+			return false
+
+		case TraverseStop:
+			stopped = true
+			return false
+		}
+
+		return true
+	})
+}
+
+// DescendantsWhere returns the descendant widgets of container for which
+// match returns true.
+func DescendantsWhere(container Container, match func(w Widget) bool) []Widget {
+	var widgets []Widget
+
+	WalkDescendants(container, func(w Widget) TreeTraversalDirective {
+		if match(w) {
+			widgets = append(widgets, w)
+		}
+
+		return TraverseContinue
+	})
+
+	return widgets
+}
+
+// DescendantsOfType returns the descendant widgets of container that have
+// type T, which may also be an interface, e.g. all *LineEdit or all
+// Container.
+func DescendantsOfType[T any](container Container) []T {
+	var matches []T
+
+	WalkDescendants(container, func(w Widget) TreeTraversalDirective {
+		if t, ok := w.(T); ok {
+			matches = append(matches, t)
+		}
+
+		return TraverseContinue
+	})
+
+	return matches
+}
+
+// DescendantsWithProperty returns the descendant widgets of container that
+// have a property named propertyName, e.g. "Modified", whose value match
+// returns true for. A nil match accepts any value.
+func DescendantsWithProperty(container Container, propertyName string, match func(value interface{}) bool) []Widget {
+	return DescendantsWhere(container, func(w Widget) bool {
+		prop := w.AsWindowBase().Property(propertyName)
+		if prop == nil {
+			return false
+		}
+
+		return match == nil || match(prop.Get())
+	})
+}
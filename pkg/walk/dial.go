@@ -0,0 +1,416 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/miu200521358/win"
+)
+
+// AngleUnit specifies the unit in which an angle is expressed.
+type AngleUnit int
+
+const (
+	AngleDegrees AngleUnit = iota
+	AngleRadians
+)
+
+// Dial is a circular widget for entering an angle, e.g. a rotation.
+//
+// The angle is measured counter-clockwise, with 0 pointing to the right, and
+// is kept in the range [0, 360) degrees. It can be changed by dragging the
+// pointer or with the arrow, page and home keys. If an increment is set,
+// the angle snaps to multiples of it, unless the Shift key is held down
+// while dragging.
+type Dial struct {
+	*CustomWidget
+	degrees               float64
+	unit                  AngleUnit
+	increment             float64 // in degrees
+	dragging              bool
+	persistent            bool
+	valueChangedPublisher EventPublisher
+}
+
+// NewDial creates and initializes a new Dial.
+func NewDial(parent Container) (*Dial, error) {
+	d := &Dial{increment: 1}
+
+	cw, err := NewCustomWidgetPixels(parent, win.WS_TABSTOP, func(canvas *Canvas, updateBounds Rectangle) error {
+		return d.paint(canvas)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			cw.Dispose()
+		}
+	}()
+
+	d.CustomWidget = cw
+
+	if err := InitWrapperWindow(d); err != nil {
+		return nil, err
+	}
+
+	d.SetInvalidatesOnResize(true)
+
+	d.GraphicsEffects().Add(InteractionEffect)
+	d.GraphicsEffects().Add(FocusEffect)
+
+	d.MustRegisterProperty("Value", NewProperty(
+		func() interface{} {
+			return d.Value()
+		},
+		func(v interface{}) error {
+			return d.SetValue(assertFloat64Or(v, 0))
+		},
+		d.valueChangedPublisher.Event()))
+
+	succeeded = true
+
+	return d, nil
+}
+
+// Unit returns the unit of Value and SetValue.
+func (d *Dial) Unit() AngleUnit {
+	return d.unit
+}
+
+// SetUnit sets the unit of Value and SetValue.
+func (d *Dial) SetUnit(unit AngleUnit) error {
+	if unit != AngleDegrees && unit != AngleRadians {
+		return newError("invalid unit")
+	}
+
+	d.unit = unit
+
+	return nil
+}
+
+// Value returns the angle in the unit of the Dial.
+func (d *Dial) Value() float64 {
+	if d.unit == AngleRadians {
+		return d.Radians()
+	}
+
+	return d.degrees
+}
+
+// SetValue sets the angle in the unit of the Dial.
+func (d *Dial) SetValue(value float64) error {
+	if d.unit == AngleRadians {
+		return d.SetRadians(value)
+	}
+
+	return d.SetDegrees(value)
+}
+
+// Degrees returns the angle in degrees.
+func (d *Dial) Degrees() float64 {
+	return d.degrees
+}
+
+// SetDegrees sets the angle in degrees.
+func (d *Dial) SetDegrees(degrees float64) error {
+	if math.IsNaN(degrees) || math.IsInf(degrees, 0) {
+		return newError("invalid angle")
+	}
+
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+
+	if degrees == d.degrees {
+		return nil
+	}
+
+	d.degrees = degrees
+
+	d.Invalidate()
+
+	d.valueChangedPublisher.Publish()
+
+	return nil
+}
+
+// Radians returns the angle in radians.
+func (d *Dial) Radians() float64 {
+	return d.degrees * math.Pi / 180
+}
+
+// SetRadians sets the angle in radians.
+func (d *Dial) SetRadians(radians float64) error {
+	return d.SetDegrees(radians * 180 / math.Pi)
+}
+
+// Increment returns the step in degrees the angle snaps to, 0 meaning no
+// snapping.
+func (d *Dial) Increment() float64 {
+	return d.increment
+}
+
+// SetIncrement sets the step in degrees the angle snaps to, 0 meaning no
+// snapping.
+//
+// The increment is also used as the step for the arrow keys.
+func (d *Dial) SetIncrement(increment float64) error {
+	if increment < 0 || increment >= 360 {
+		return newError("increment must >= 0 && < 360")
+	}
+
+	d.increment = increment
+
+	return nil
+}
+
+// ValueChanged returns an Event that can be used to track changes to Value.
+func (d *Dial) ValueChanged() *Event {
+	return d.valueChangedPublisher.Event()
+}
+
+func (d *Dial) Persistent() bool {
+	return d.persistent
+}
+
+func (d *Dial) SetPersistent(value bool) {
+	d.persistent = value
+}
+
+func (d *Dial) SaveState() error {
+	return d.WriteState(strconv.FormatFloat(d.degrees, 'f', -1, 64))
+}
+
+func (d *Dial) RestoreState() error {
+	s, err := d.ReadState()
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+
+	degrees, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+
+	return d.SetDegrees(degrees)
+}
+
+func (d *Dial) snap(degrees float64) float64 {
+	if d.increment == 0 {
+		return degrees
+	}
+
+	return math.Round(degrees/d.increment) * d.increment
+}
+
+func (d *Dial) step() float64 {
+	if d.increment == 0 {
+		return 1
+	}
+
+	return d.increment
+}
+
+func (d *Dial) setDegreesFromPoint(x, y int32, snap bool) {
+	bounds := d.ClientBoundsPixels()
+
+	dx := float64(x) - float64(bounds.Width)/2
+	dy := float64(bounds.Height)/2 - float64(y)
+	if dx == 0 && dy == 0 {
+		return
+	}
+
+	degrees := math.Atan2(dy, dx) * 180 / math.Pi
+	if snap {
+		degrees = d.snap(degrees)
+	}
+
+	d.SetDegrees(degrees)
+}
+
+func (d *Dial) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_LBUTTONDOWN:
+		if !d.Enabled() {
+			break
+		}
+
+		d.SetFocus()
+		win.SetCapture(hwnd)
+		d.dragging = true
+		d.setDegreesFromPoint(win.GET_X_LPARAM(lParam), win.GET_Y_LPARAM(lParam), wParam&win.MK_SHIFT == 0)
+
+	case win.WM_MOUSEMOVE:
+		if d.dragging {
+			d.setDegreesFromPoint(win.GET_X_LPARAM(lParam), win.GET_Y_LPARAM(lParam), wParam&win.MK_SHIFT == 0)
+		}
+
+	case win.WM_LBUTTONUP:
+		if d.dragging {
+			d.dragging = false
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		d.dragging = false
+
+	case win.WM_KEYDOWN:
+		if !d.Enabled() {
+			break
+		}
+
+		switch Key(wParam) {
+		case KeyRight, KeyUp:
+			d.SetDegrees(d.snap(d.degrees + d.step()))
+
+		case KeyLeft, KeyDown:
+			d.SetDegrees(d.snap(d.degrees - d.step()))
+
+		case KeyPrior:
+			d.SetDegrees(d.snap(d.degrees + math.Max(15, d.step())))
+
+		case KeyNext:
+			d.SetDegrees(d.snap(d.degrees - math.Max(15, d.step())))
+
+		case KeyHome:
+			d.SetDegrees(0)
+		}
+	}
+
+	return d.CustomWidget.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (d *Dial) paint(canvas *Canvas) error {
+	bounds := d.ClientBoundsPixels()
+
+	dpi := d.DPI()
+	margin := IntFrom96DPI(2, dpi)
+
+	diameter := bounds.Width
+	if bounds.Height < diameter {
+		diameter = bounds.Height
+	}
+	diameter -= 2 * margin
+	if diameter <= 0 {
+		return nil
+	}
+
+	face := Rectangle{
+		(bounds.Width - diameter) / 2,
+		(bounds.Height - diameter) / 2,
+		diameter,
+		diameter,
+	}
+
+	faceColor := Color(win.GetSysColor(win.COLOR_WINDOW))
+	lineColor := Color(win.GetSysColor(win.COLOR_BTNSHADOW))
+	pointerColor := Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
+	if !d.Enabled() {
+		faceColor = Color(win.GetSysColor(win.COLOR_BTNFACE))
+		pointerColor = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	faceBrush, err := NewSolidColorBrush(faceColor)
+	if err != nil {
+		return err
+	}
+	defer faceBrush.Dispose()
+
+	if err := canvas.FillEllipsePixels(faceBrush, face); err != nil {
+		return err
+	}
+
+	linePen, err := NewCosmeticPen(PenSolid, lineColor)
+	if err != nil {
+		return err
+	}
+	defer linePen.Dispose()
+
+	if err := canvas.DrawEllipsePixels(linePen, face); err != nil {
+		return err
+	}
+
+	cx := float64(face.X) + float64(diameter)/2
+	cy := float64(face.Y) + float64(diameter)/2
+	radius := float64(diameter) / 2
+
+	pointAt := func(degrees, r float64) Point {
+		rad := degrees * math.Pi / 180
+
+		return Point{
+			int(math.Round(cx + math.Cos(rad)*r)),
+			int(math.Round(cy - math.Sin(rad)*r)),
+		}
+	}
+
+	// Tick marks every 45 degrees.
+	tickLength := float64(IntFrom96DPI(4, dpi))
+	for deg := 0.0; deg < 360; deg += 45 {
+		if err := canvas.DrawLinePixels(linePen, pointAt(deg, radius-tickLength), pointAt(deg, radius)); err != nil {
+			return err
+		}
+	}
+
+	pointerBrush, err := NewSolidColorBrush(pointerColor)
+	if err != nil {
+		return err
+	}
+	defer pointerBrush.Dispose()
+
+	pointerPen, err := NewGeometricPen(PenSolid|PenCapRound, IntFrom96DPI(2, dpi), pointerBrush)
+	if err != nil {
+		return err
+	}
+	defer pointerPen.Dispose()
+
+	center := Point{int(math.Round(cx)), int(math.Round(cy))}
+	if err := canvas.DrawLinePixels(pointerPen, center, pointAt(d.degrees, radius-tickLength-float64(margin))); err != nil {
+		return err
+	}
+
+	hub := IntFrom96DPI(3, dpi)
+
+	return canvas.FillEllipsePixels(pointerBrush, Rectangle{center.X - hub, center.Y - hub, 2 * hub, 2 * hub})
+}
+
+func (d *Dial) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &dialLayoutItem{
+		idealSize: SizeFrom96DPI(Size{48, 48}, ctx.dpi),
+		minSize:   SizeFrom96DPI(Size{24, 24}, ctx.dpi),
+	}
+}
+
+type dialLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+	minSize   Size // in native pixels
+}
+
+func (*dialLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | ShrinkableVert
+}
+
+func (li *dialLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *dialLayoutItem) MinSize() Size {
+	return li.minSize
+}
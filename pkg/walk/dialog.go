@@ -48,6 +48,7 @@ type Dialog struct {
 	defaultButton        *PushButton
 	cancelButton         *PushButton
 	centerInOwnerWhenRun bool
+	sheet                bool
 }
 
 func NewDialog(owner Form) (*Dialog, error) {
@@ -138,6 +139,45 @@ func (dlg *Dialog) SetCancelButton(button *PushButton) error {
 	return nil
 }
 
+// Sheet returns whether the Dialog is shown as a sheet, see SetSheet.
+func (dlg *Dialog) Sheet() bool {
+	return dlg.sheet
+}
+
+// SetSheet sets whether the Dialog is shown as a sheet, i.e. as a card without
+// title bar, centered in the client area of its owner, which is dimmed while
+// the Dialog is shown. Run and Show work the same in both modes.
+//
+// SetSheet must be called before the Dialog is shown and requires an owner.
+func (dlg *Dialog) SetSheet(sheet bool) error {
+	if sheet == dlg.sheet {
+		return nil
+	}
+	if sheet && dlg.owner == nil {
+		return newError("owner required")
+	}
+	if dlg.Visible() {
+		return newError("dialog already shown")
+	}
+
+	const frameStyles = win.WS_CAPTION | win.WS_SYSMENU
+
+	style := uint32(win.GetWindowLong(dlg.hWnd, win.GWL_STYLE))
+	if sheet {
+		style = style&^frameStyles | win.WS_POPUP | win.WS_BORDER
+	} else {
+		style = style&^(win.WS_POPUP|win.WS_BORDER) | frameStyles
+	}
+	win.SetWindowLong(dlg.hWnd, win.GWL_STYLE, int32(style))
+
+	win.SetWindowPos(dlg.hWnd, 0, 0, 0, 0, 0,
+		win.SWP_FRAMECHANGED|win.SWP_NOMOVE|win.SWP_NOSIZE|win.SWP_NOZORDER|win.SWP_NOACTIVATE)
+
+	dlg.sheet = sheet
+
+	return nil
+}
+
 func (dlg *Dialog) Result() int {
 	return dlg.result
 }
@@ -158,12 +198,19 @@ func (dlg *Dialog) Close(result int) {
 
 func (dlg *Dialog) Show() {
 	var willRestore bool
-	if dlg.Persistent() {
+	if dlg.Persistent() && !dlg.sheet {
 		state, _ := dlg.ReadState()
 		willRestore = state != ""
 	}
 
-	if !willRestore {
+	if dlg.sheet {
+		size := dlg.SizePixels()
+		if layout := dlg.Layout(); layout != nil {
+			size = maxSize(dlg.clientComposite.MinSizeHint(), dlg.MinSizePixels())
+		}
+
+		dlg.showSheet(size)
+	} else if !willRestore {
 		var size Size
 		if layout := dlg.Layout(); layout != nil {
 			size = maxSize(dlg.clientComposite.MinSizeHint(), dlg.MinSizePixels())
@@ -0,0 +1,180 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+const sheetBackdropWindowClass = `\o/ Walk_SheetBackdrop_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(sheetBackdropWindowClass)
+	})
+}
+
+// sheetBackdrop dims the client area of the owner of a Dialog shown as a
+// sheet, by covering it with a darkened snapshot.
+type sheetBackdrop struct {
+	WindowBase
+	owner           Form
+	snapshot        *Bitmap
+	clippedSiblings []win.HWND
+}
+
+func newSheetBackdrop(owner Form) (*sheetBackdrop, error) {
+	sb := &sheetBackdrop{owner: owner}
+
+	// Take the snapshot before the backdrop exists, so it doesn't capture
+	// itself.
+	if hBmp, err := hBitmapFromWindowClient(owner); err == nil {
+		sb.snapshot, _ = newBitmapFromHBITMAP(hBmp, owner.DPI())
+	}
+
+	if err := InitWindow(
+		sb,
+		owner,
+		sheetBackdropWindowClass,
+		win.WS_CHILD|win.WS_CLIPSIBLINGS,
+		0); err != nil {
+		sb.disposeSnapshot()
+		return nil, err
+	}
+
+	sb.clippedSiblings = clipSiblings(sb.hWnd)
+	sb.fitToOwner()
+
+	return sb, nil
+}
+
+func (sb *sheetBackdrop) Dispose() {
+	unclipSiblings(sb.clippedSiblings)
+	sb.clippedSiblings = nil
+	sb.disposeSnapshot()
+
+	sb.WindowBase.Dispose()
+}
+
+func (sb *sheetBackdrop) disposeSnapshot() {
+	if sb.snapshot != nil {
+		sb.snapshot.Dispose()
+		sb.snapshot = nil
+	}
+}
+
+func (sb *sheetBackdrop) fitToOwner() {
+	var r win.RECT
+	if !win.GetClientRect(sb.owner.Handle(), &r) {
+		return
+	}
+
+	win.SetWindowPos(
+		sb.hWnd,
+		win.HWND_TOP,
+		0,
+		0,
+		r.Right-r.Left,
+		r.Bottom-r.Top,
+		win.SWP_SHOWWINDOW|win.SWP_NOACTIVATE)
+
+	sb.Invalidate()
+}
+
+func (sb *sheetBackdrop) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		var ps win.PAINTSTRUCT
+
+		hdc := win.BeginPaint(hwnd, &ps)
+		if hdc == 0 {
+			break
+		}
+		defer win.EndPaint(hwnd, &ps)
+
+		canvas, err := newCanvasFromHDC(hdc)
+		if err != nil {
+			break
+		}
+		defer canvas.Dispose()
+
+		if err := sb.paint(canvas, sb.ClientBoundsPixels()); err != nil {
+			break
+		}
+
+		return 0
+	}
+
+	return sb.WindowBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (sb *sheetBackdrop) paint(canvas *Canvas, bounds Rectangle) error {
+	dimBrush, err := NewSolidColorBrush(RGB(0, 0, 0))
+	if err != nil {
+		return err
+	}
+	defer dimBrush.Dispose()
+
+	if err := canvas.FillRectanglePixels(dimBrush, bounds); err != nil {
+		return err
+	}
+
+	if sb.snapshot == nil {
+		return nil
+	}
+
+	return canvas.DrawBitmapWithOpacityPixels(sb.snapshot, Rectangle{0, 0, sb.snapshot.size.Width, sb.snapshot.size.Height}, 140)
+}
+
+// showSheet shows the Dialog as a card of size in native pixels, centered in
+// the client area of its owner, which is dimmed until the Dialog is disposed.
+func (dlg *Dialog) showSheet(size Size) {
+	owner := dlg.owner
+
+	backdrop, err := newSheetBackdrop(owner)
+	if err != nil {
+		// The sheet still works, just without dimming.
+		backdrop = nil
+	}
+
+	fit := func() {
+		if backdrop != nil {
+			backdrop.fitToOwner()
+		}
+
+		var r win.RECT
+		if !win.GetClientRect(owner.Handle(), &r) {
+			return
+		}
+
+		origin := win.POINT{X: r.Left, Y: r.Top}
+		win.ClientToScreen(owner.Handle(), &origin)
+
+		dlg.SetBoundsPixels(Rectangle{
+			X:      int(origin.X) + (int(r.Right-r.Left)-size.Width)/2,
+			Y:      int(origin.Y) + (int(r.Bottom-r.Top)-size.Height)/2,
+			Width:  size.Width,
+			Height: size.Height,
+		})
+	}
+
+	fit()
+
+	boundsChangedHandle := owner.BoundsChanged().Attach(fit)
+
+	dlg.Disposing().Once(func() {
+		owner.BoundsChanged().Detach(boundsChangedHandle)
+
+		if backdrop != nil {
+			backdrop.Dispose()
+		}
+	})
+}
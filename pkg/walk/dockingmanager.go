@@ -0,0 +1,1125 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const (
+	toolWindowFrameWindowClass = `\o/ Walk_ToolWindowFrame_Class \o/`
+	dockGuideWindowClass       = `\o/ Walk_DockGuide_Class \o/`
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(toolWindowFrameWindowClass)
+		MustRegisterWindowClass(dockGuideWindowClass)
+	})
+}
+
+// DockArea specifies where a ToolWindow is placed by a DockingManager.
+type DockArea int
+
+const (
+	DockAreaLeft DockArea = iota
+	DockAreaTop
+	DockAreaRight
+	DockAreaBottom
+	DockAreaFloating
+)
+
+const dockAreaCount = int(DockAreaFloating)
+
+// DockingManager arranges ToolWindows around the content of a MainWindow.
+//
+// ToolWindows can be docked to the left, top, right or bottom edge of the
+// MainWindow, where tool windows that share an edge are tabbed together, or
+// they can float in their own frame. Dragging a tab out of a dock area floats
+// the ToolWindow. While a floating frame is being moved, docking guides are
+// shown over the MainWindow and dropping the frame onto one of them docks the
+// ToolWindows of the frame to the respective edge. Double-clicking the caption
+// of a floating frame docks its ToolWindows back to where they came from.
+// With a Snapper set, moved frames snap to the edges of the MainWindow and of
+// the other floating frames.
+//
+// If the MainWindow is persistent, the arrangement of the ToolWindows is
+// saved and restored through the Settings of the application, keyed by the
+// names of the ToolWindows.
+type DockingManager struct {
+	mainWindow             *MainWindow
+	site                   *dockSite
+	center                 *Composite
+	hSplitter              *Splitter
+	vSplitter              *Splitter
+	areas                  [dockAreaCount]*TabWidget
+	toolWindows            []*ToolWindow
+	frames                 []*toolWindowFrame
+	guides                 []*dockGuide
+	snapper                *Snapper
+	layoutChangedPublisher EventPublisher
+}
+
+// NewDockingManager returns a new DockingManager for mw.
+//
+// The children and the layout of mw are moved into the center of the dock
+// site, which is available through the Center method afterwards.
+func NewDockingManager(mw *MainWindow) (*DockingManager, error) {
+	if mw == nil {
+		return nil, newError("mw must not be nil")
+	}
+
+	dm := &DockingManager{mainWindow: mw}
+
+	children := mw.Children()
+	content := make([]Widget, children.Len())
+	for i := range content {
+		content[i] = children.At(i)
+	}
+
+	c, err := NewComposite(mw)
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			c.Dispose()
+		}
+	}()
+
+	dm.site = &dockSite{Composite: c, manager: dm}
+	if err := InitWrapperWindow(dm.site); err != nil {
+		return nil, err
+	}
+	dm.site.SetName("dockSite")
+	dm.site.SetPersistent(true)
+
+	siteLayout := NewHBoxLayout()
+	siteLayout.SetMargins(Margins{})
+	siteLayout.SetSpacing(0)
+	if err := dm.site.SetLayout(siteLayout); err != nil {
+		return nil, err
+	}
+
+	if dm.hSplitter, err = NewHSplitter(dm.site); err != nil {
+		return nil, err
+	}
+	dm.hSplitter.SetName("hSplitter")
+	dm.hSplitter.SetPersistent(true)
+
+	if dm.areas[DockAreaLeft], err = NewTabWidget(dm.hSplitter); err != nil {
+		return nil, err
+	}
+
+	if dm.vSplitter, err = NewVSplitter(dm.hSplitter); err != nil {
+		return nil, err
+	}
+	dm.vSplitter.SetName("vSplitter")
+
+	if dm.areas[DockAreaRight], err = NewTabWidget(dm.hSplitter); err != nil {
+		return nil, err
+	}
+
+	if dm.areas[DockAreaTop], err = NewTabWidget(dm.vSplitter); err != nil {
+		return nil, err
+	}
+
+	if dm.center, err = NewComposite(dm.vSplitter); err != nil {
+		return nil, err
+	}
+	dm.center.SetName("center")
+
+	if dm.areas[DockAreaBottom], err = NewTabWidget(dm.vSplitter); err != nil {
+		return nil, err
+	}
+
+	hLayout := dm.hSplitter.Layout().(*splitterLayout)
+	hLayout.SetStretchFactor(dm.vSplitter, 4)
+	vLayout := dm.vSplitter.Layout().(*splitterLayout)
+	vLayout.SetStretchFactor(dm.center, 4)
+
+	for _, tw := range dm.areas {
+		tw.pageDragOutHandler = dm.onPageDraggedOut
+		tw.SetVisible(false)
+	}
+
+	layout := mw.Layout()
+	if layout == nil {
+		layout = NewVBoxLayout()
+	}
+
+	rootLayout := NewHBoxLayout()
+	rootLayout.SetMargins(Margins{})
+	rootLayout.SetSpacing(0)
+	if err := mw.SetLayout(rootLayout); err != nil {
+		return nil, err
+	}
+
+	for _, widget := range content {
+		if err := widget.SetParent(dm.center); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := dm.center.SetLayout(layout); err != nil {
+		return nil, err
+	}
+
+	mw.Disposing().Attach(dm.dispose)
+
+	succeeded = true
+
+	return dm, nil
+}
+
+// MainWindow returns the MainWindow the DockingManager arranges ToolWindows
+// for.
+func (dm *DockingManager) MainWindow() *MainWindow {
+	return dm.mainWindow
+}
+
+// Center returns the Composite that holds the main content of the MainWindow.
+func (dm *DockingManager) Center() *Composite {
+	return dm.center
+}
+
+// ToolWindows returns the ToolWindows of the DockingManager in the order they
+// were created.
+func (dm *DockingManager) ToolWindows() []*ToolWindow {
+	return append([]*ToolWindow(nil), dm.toolWindows...)
+}
+
+// ToolWindow returns the ToolWindow with the specified name or nil.
+func (dm *DockingManager) ToolWindow(name string) *ToolWindow {
+	for _, tw := range dm.toolWindows {
+		if tw.name == name {
+			return tw
+		}
+	}
+
+	return nil
+}
+
+// LayoutChanged returns the event that is published when a ToolWindow was
+// docked, floated, shown or hidden.
+func (dm *DockingManager) LayoutChanged() *Event {
+	return dm.layoutChangedPublisher.Event()
+}
+
+// Snapper returns the Snapper that floating frames snap with while they are
+// moved, or nil.
+func (dm *DockingManager) Snapper() *Snapper {
+	return dm.snapper
+}
+
+// SetSnapper sets the Snapper that floating frames snap with while they are
+// moved, unless Alt is held down. It works in screen coordinates and its
+// targets are replaced with the bounds of the MainWindow and the other
+// floating frames.
+func (dm *DockingManager) SetSnapper(snapper *Snapper) {
+	dm.snapper = snapper
+}
+
+// NewToolWindow creates a new ToolWindow and places it in area.
+//
+// The name identifies the ToolWindow when its placement is persisted, so it
+// must be unique within the DockingManager.
+func (dm *DockingManager) NewToolWindow(name, title string, area DockArea) (*ToolWindow, error) {
+	if name == "" || strings.ContainsAny(name, ",;") {
+		return nil, newError("invalid tool window name")
+	}
+	if dm.ToolWindow(name) != nil {
+		return nil, newError("duplicate tool window name: " + name)
+	}
+	if area < DockAreaLeft || area > DockAreaFloating {
+		return nil, newError("invalid dock area")
+	}
+
+	page, err := NewTabPage()
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			page.Dispose()
+		}
+	}()
+
+	page.SetName(name)
+	if err := page.SetTitle(title); err != nil {
+		return nil, err
+	}
+	if err := page.SetLayout(NewVBoxLayout()); err != nil {
+		return nil, err
+	}
+
+	tw := &ToolWindow{
+		manager:    dm,
+		name:       name,
+		page:       page,
+		area:       area,
+		dockedArea: area,
+		visible:    true,
+	}
+	if area == DockAreaFloating {
+		tw.dockedArea = DockAreaRight
+	}
+
+	if err := dm.place(tw, area, nil); err != nil {
+		return nil, err
+	}
+
+	dm.toolWindows = append(dm.toolWindows, tw)
+
+	succeeded = true
+
+	return tw, nil
+}
+
+func (dm *DockingManager) toolWindowForPage(page *TabPage) *ToolWindow {
+	for _, tw := range dm.toolWindows {
+		if tw.page == page {
+			return tw
+		}
+	}
+
+	return nil
+}
+
+// place moves tw into area. If area is DockAreaFloating, tw is added to frame
+// or to a new frame, if frame is nil.
+func (dm *DockingManager) place(tw *ToolWindow, area DockArea, frame *toolWindowFrame) error {
+	dm.detach(tw)
+
+	var tabWidget *TabWidget
+
+	if area == DockAreaFloating {
+		if frame == nil {
+			f, err := dm.newFrame(tw.floatingBounds)
+			if err != nil {
+				return err
+			}
+
+			frame = f
+		}
+
+		tw.frame = frame
+		tabWidget = frame.tabWidget
+	} else {
+		tabWidget = dm.areas[area]
+		tw.dockedArea = area
+	}
+
+	if err := tabWidget.Pages().Add(tw.page); err != nil {
+		return err
+	}
+	if err := tabWidget.SetCurrentIndex(tabWidget.Pages().Index(tw.page)); err != nil {
+		return err
+	}
+
+	tw.area = area
+	tw.visible = true
+
+	if frame != nil {
+		frame.updateTitle()
+
+		if !frame.Visible() {
+			frame.Show()
+		}
+	}
+
+	dm.updateAreas()
+
+	dm.layoutChangedPublisher.Publish()
+
+	return nil
+}
+
+// detach removes tw from the dock area or frame it is in, if any.
+func (dm *DockingManager) detach(tw *ToolWindow) {
+	if frame := tw.frame; frame != nil {
+		tw.floatingBounds = frame.BoundsPixels()
+		tw.frame = nil
+
+		frame.tabWidget.Pages().Remove(tw.page)
+
+		if frame.tabWidget.Pages().Len() == 0 {
+			dm.removeFrame(frame)
+		} else {
+			frame.updateTitle()
+		}
+
+		return
+	}
+
+	if tabWidget := tw.page.tabWidget; tabWidget != nil {
+		tabWidget.Pages().Remove(tw.page)
+	}
+}
+
+func (dm *DockingManager) updateAreas() {
+	for _, tw := range dm.areas {
+		tw.SetVisible(tw.Pages().Len() > 0)
+	}
+}
+
+func (dm *DockingManager) newFrame(bounds Rectangle) (*toolWindowFrame, error) {
+	frame, err := newToolWindowFrame(dm)
+	if err != nil {
+		return nil, err
+	}
+
+	if bounds.Width <= 0 || bounds.Height <= 0 {
+		dpi := dm.mainWindow.DPI()
+		mwb := dm.mainWindow.BoundsPixels()
+		offset := IntFrom96DPI(48, dpi)
+
+		bounds = Rectangle{
+			mwb.X + offset,
+			mwb.Y + offset,
+			IntFrom96DPI(300, dpi),
+			IntFrom96DPI(400, dpi),
+		}
+	}
+
+	if err := frame.SetBoundsPixels(bounds); err != nil {
+		frame.Dispose()
+		return nil, err
+	}
+
+	dm.frames = append(dm.frames, frame)
+
+	return frame, nil
+}
+
+func (dm *DockingManager) removeFrame(frame *toolWindowFrame) {
+	for i, f := range dm.frames {
+		if f == frame {
+			dm.frames = append(dm.frames[:i], dm.frames[i+1:]...)
+			break
+		}
+	}
+
+	frame.Dispose()
+}
+
+// dockFrame docks all ToolWindows of frame to area.
+func (dm *DockingManager) dockFrame(frame *toolWindowFrame, area DockArea) {
+	pages := frame.tabWidget.Pages()
+
+	toolWindows := make([]*ToolWindow, 0, pages.Len())
+	for i := 0; i < pages.Len(); i++ {
+		if tw := dm.toolWindowForPage(pages.At(i)); tw != nil {
+			toolWindows = append(toolWindows, tw)
+		}
+	}
+
+	for _, tw := range toolWindows {
+		target := area
+		if target == DockAreaFloating {
+			target = tw.dockedArea
+		}
+
+		dm.place(tw, target, nil)
+	}
+}
+
+func (dm *DockingManager) onPageDraggedOut(page *TabPage) {
+	tw := dm.toolWindowForPage(page)
+	if tw == nil {
+		return
+	}
+
+	if tw.frame != nil && tw.frame.tabWidget.Pages().Len() == 1 {
+		tw.frame.beginMove()
+		return
+	}
+
+	var pt win.POINT
+	if !win.GetCursorPos(&pt) {
+		return
+	}
+
+	dpi := dm.mainWindow.DPI()
+
+	size := page.tabWidget.SizePixels()
+	minSize := SizeFrom96DPI(Size{200, 150}, dpi)
+	size = maxSize(size, minSize)
+
+	tw.floatingBounds = Rectangle{
+		int(pt.X) - IntFrom96DPI(40, dpi),
+		int(pt.Y) - int(win.GetSystemMetrics(win.SM_CYSMCAPTION))/2,
+		size.Width,
+		size.Height,
+	}
+
+	if err := dm.place(tw, DockAreaFloating, nil); err != nil {
+		return
+	}
+
+	tw.frame.beginMove()
+}
+
+func (dm *DockingManager) onFrameMoving(frame *toolWindowFrame, rc *win.RECT) {
+	if dm.guides == nil {
+		dm.showGuides()
+	}
+
+	dm.snapFrame(frame, rc)
+
+	var pt win.POINT
+	if !win.GetCursorPos(&pt) {
+		return
+	}
+
+	for _, g := range dm.guides {
+		g.setHot(g.contains(pt))
+	}
+}
+
+// snapFrame moves rc, the screen bounds of frame, so it snaps to the
+// MainWindow and the other floating frames.
+func (dm *DockingManager) snapFrame(frame *toolWindowFrame, rc *win.RECT) {
+	if dm.snapper == nil || AltDown() {
+		return
+	}
+
+	windowBounds := func(hwnd win.HWND) (SceneRect, bool) {
+		var r win.RECT
+		if !win.IsWindowVisible(hwnd) || !win.GetWindowRect(hwnd, &r) {
+			return SceneRect{}, false
+		}
+
+		return SceneRect{float64(r.Left), float64(r.Top), float64(r.Right - r.Left), float64(r.Bottom - r.Top)}, true
+	}
+
+	var targets []SceneRect
+
+	if r, ok := windowBounds(dm.mainWindow.hWnd); ok {
+		targets = append(targets, r)
+	}
+
+	for _, f := range dm.frames {
+		if f == frame {
+			continue
+		}
+
+		if r, ok := windowBounds(f.hWnd); ok {
+			targets = append(targets, r)
+		}
+	}
+
+	dm.snapper.SetTargets(targets)
+
+	r := SceneRect{float64(rc.Left), float64(rc.Top), float64(rc.Right - rc.Left), float64(rc.Bottom - rc.Top)}
+	snapped, _ := dm.snapper.SnapRect(r)
+
+	dx := int32(math.Round(snapped.X - r.X))
+	dy := int32(math.Round(snapped.Y - r.Y))
+
+	rc.Left += dx
+	rc.Right += dx
+	rc.Top += dy
+	rc.Bottom += dy
+}
+
+func (dm *DockingManager) onFrameMoved(frame *toolWindowFrame) {
+	if dm.guides == nil {
+		return
+	}
+
+	target := DockAreaFloating
+	for _, g := range dm.guides {
+		if g.hot {
+			target = g.area
+		}
+
+		g.Dispose()
+	}
+	dm.guides = nil
+
+	if target != DockAreaFloating {
+		// We are called from within the WndProc of frame, which may be
+		// disposed of as a result of docking.
+		frame.Synchronize(func() {
+			dm.dockFrame(frame, target)
+		})
+	}
+}
+
+func (dm *DockingManager) showGuides() {
+	var rc win.RECT
+	if !win.GetClientRect(dm.site.hWnd, &rc) {
+		return
+	}
+
+	pt := win.POINT{X: rc.Left, Y: rc.Top}
+	win.ClientToScreen(dm.site.hWnd, &pt)
+
+	site := Rectangle{int(pt.X), int(pt.Y), int(rc.Right - rc.Left), int(rc.Bottom - rc.Top)}
+
+	dpi := dm.mainWindow.DPI()
+	size := IntFrom96DPI(32, dpi)
+	margin := IntFrom96DPI(8, dpi)
+
+	cx := site.X + (site.Width-size)/2
+	cy := site.Y + (site.Height-size)/2
+
+	positions := [dockAreaCount]Point{
+		DockAreaLeft:   {site.X + margin, cy},
+		DockAreaTop:    {cx, site.Y + margin},
+		DockAreaRight:  {site.X + site.Width - size - margin, cy},
+		DockAreaBottom: {cx, site.Y + site.Height - size - margin},
+	}
+
+	for i, p := range positions {
+		g, err := newDockGuide(dm.mainWindow, DockArea(i), Rectangle{p.X, p.Y, size, size})
+		if err != nil {
+			continue
+		}
+
+		dm.guides = append(dm.guides, g)
+	}
+}
+
+func (dm *DockingManager) dispose() {
+	for _, g := range dm.guides {
+		g.Dispose()
+	}
+	dm.guides = nil
+
+	for _, frame := range dm.frames {
+		frame.Dispose()
+	}
+	dm.frames = nil
+
+	for _, tw := range dm.toolWindows {
+		if tw.page.tabWidget == nil {
+			tw.page.Dispose()
+		}
+	}
+}
+
+// state returns the placement of all ToolWindows, formatted as
+// "name,area,dockedArea,visible,x,y,width,height" entries separated by ";".
+//
+// Entries are ordered by dock area and tab, so restoring them in order
+// recreates the tab order. The bounds are those of the floating frame.
+func (dm *DockingManager) state() string {
+	var entries []string
+
+	add := func(tw *ToolWindow, bounds Rectangle) {
+		visible := 0
+		if tw.visible {
+			visible = 1
+		}
+
+		entries = append(entries, fmt.Sprintf("%s,%d,%d,%d,%d,%d,%d,%d",
+			tw.name, tw.area, tw.dockedArea, visible,
+			bounds.X, bounds.Y, bounds.Width, bounds.Height))
+	}
+
+	addPages := func(pages *TabPageList, bounds func(tw *ToolWindow) Rectangle) {
+		for i := 0; i < pages.Len(); i++ {
+			if tw := dm.toolWindowForPage(pages.At(i)); tw != nil {
+				add(tw, bounds(tw))
+			}
+		}
+	}
+
+	for _, tabWidget := range dm.areas {
+		addPages(tabWidget.Pages(), func(tw *ToolWindow) Rectangle {
+			return tw.floatingBounds
+		})
+	}
+
+	for _, frame := range dm.frames {
+		bounds := frame.BoundsPixels()
+
+		addPages(frame.tabWidget.Pages(), func(tw *ToolWindow) Rectangle {
+			return bounds
+		})
+	}
+
+	for _, tw := range dm.toolWindows {
+		if !tw.visible {
+			add(tw, tw.floatingBounds)
+		}
+	}
+
+	return strings.Join(entries, ";")
+}
+
+// restoreState places the ToolWindows as described by state, which must have
+// been returned by the state method. Consecutive floating entries with equal
+// bounds are restored into the same frame.
+func (dm *DockingManager) restoreState(state string) error {
+	var lastFrame *toolWindowFrame
+	var lastBounds Rectangle
+
+	for _, entry := range strings.Split(state, ";") {
+		fields := strings.Split(entry, ",")
+		if len(fields) != 8 {
+			continue
+		}
+
+		tw := dm.ToolWindow(fields[0])
+		if tw == nil {
+			continue
+		}
+
+		var values [7]int
+		for i := range values {
+			v, err := strconv.Atoi(fields[i+1])
+			if err != nil {
+				return wrapError(err)
+			}
+
+			values[i] = v
+		}
+
+		area := DockArea(values[0])
+		dockedArea := DockArea(values[1])
+		if area < DockAreaLeft || area > DockAreaFloating || dockedArea < DockAreaLeft || dockedArea >= DockAreaFloating {
+			continue
+		}
+
+		bounds := Rectangle{values[3], values[4], values[5], values[6]}
+
+		tw.dockedArea = dockedArea
+		tw.floatingBounds = bounds
+
+		if values[2] == 0 {
+			if err := tw.SetVisible(false); err != nil {
+				return err
+			}
+			tw.area = area
+			continue
+		}
+
+		var frame *toolWindowFrame
+		if area == DockAreaFloating && lastFrame != nil && bounds == lastBounds {
+			frame = lastFrame
+		}
+
+		if err := dm.place(tw, area, frame); err != nil {
+			return err
+		}
+
+		if area == DockAreaFloating {
+			lastFrame = tw.frame
+			lastBounds = bounds
+		} else {
+			lastFrame = nil
+		}
+	}
+
+	return nil
+}
+
+// ToolWindow is a panel that is arranged by a DockingManager.
+//
+// Widgets are added to the TabPage returned by Content.
+type ToolWindow struct {
+	manager        *DockingManager
+	name           string
+	page           *TabPage
+	area           DockArea
+	dockedArea     DockArea
+	floatingBounds Rectangle
+	visible        bool
+	frame          *toolWindowFrame
+}
+
+// Manager returns the DockingManager of the ToolWindow.
+func (tw *ToolWindow) Manager() *DockingManager {
+	return tw.manager
+}
+
+// Name returns the name that identifies the ToolWindow.
+func (tw *ToolWindow) Name() string {
+	return tw.name
+}
+
+// Title returns the text that is displayed in the tab and floating frame of
+// the ToolWindow.
+func (tw *ToolWindow) Title() string {
+	return tw.page.Title()
+}
+
+// SetTitle sets the text that is displayed in the tab and floating frame of
+// the ToolWindow.
+func (tw *ToolWindow) SetTitle(title string) error {
+	if err := tw.page.SetTitle(title); err != nil {
+		return err
+	}
+
+	if tw.frame != nil {
+		tw.frame.updateTitle()
+	}
+
+	return nil
+}
+
+// Content returns the TabPage that holds the widgets of the ToolWindow.
+func (tw *ToolWindow) Content() *TabPage {
+	return tw.page
+}
+
+// Area returns the dock area of the ToolWindow, which is DockAreaFloating if
+// it floats in its own frame.
+func (tw *ToolWindow) Area() DockArea {
+	return tw.area
+}
+
+// Floating returns whether the ToolWindow floats in its own frame.
+func (tw *ToolWindow) Floating() bool {
+	return tw.area == DockAreaFloating
+}
+
+// Dock docks the ToolWindow to area.
+//
+// Docking to DockAreaFloating is equivalent to calling Float.
+func (tw *ToolWindow) Dock(area DockArea) error {
+	if area < DockAreaLeft || area > DockAreaFloating {
+		return newError("invalid dock area")
+	}
+
+	if area == DockAreaFloating {
+		return tw.Float()
+	}
+
+	return tw.manager.place(tw, area, nil)
+}
+
+// Float moves the ToolWindow into its own frame.
+func (tw *ToolWindow) Float() error {
+	if tw.frame != nil && tw.frame.tabWidget.Pages().Len() == 1 {
+		return nil
+	}
+
+	return tw.manager.place(tw, DockAreaFloating, nil)
+}
+
+// Visible returns whether the ToolWindow is shown.
+func (tw *ToolWindow) Visible() bool {
+	return tw.visible
+}
+
+// SetVisible shows or hides the ToolWindow.
+//
+// A ToolWindow that is shown again returns to the area it was hidden from.
+func (tw *ToolWindow) SetVisible(visible bool) error {
+	if visible == tw.visible {
+		return nil
+	}
+
+	if visible {
+		return tw.manager.place(tw, tw.area, nil)
+	}
+
+	tw.manager.detach(tw)
+	tw.visible = false
+
+	tw.manager.updateAreas()
+
+	tw.manager.layoutChangedPublisher.Publish()
+
+	return nil
+}
+
+// Activate shows the ToolWindow and brings its tab to the front.
+func (tw *ToolWindow) Activate() error {
+	if err := tw.SetVisible(true); err != nil {
+		return err
+	}
+
+	tabWidget := tw.page.tabWidget
+	if err := tabWidget.SetCurrentIndex(tabWidget.Pages().Index(tw.page)); err != nil {
+		return err
+	}
+
+	if tw.frame != nil {
+		win.SetWindowPos(tw.frame.hWnd, win.HWND_TOP, 0, 0, 0, 0, win.SWP_NOMOVE|win.SWP_NOSIZE)
+	}
+
+	return nil
+}
+
+// dockSite is the Composite of a MainWindow that holds the dock areas and
+// persists the placement of ToolWindows.
+type dockSite struct {
+	*Composite
+	manager *DockingManager
+}
+
+func (ds *dockSite) SaveState() error {
+	if err := ds.WriteState(ds.manager.state()); err != nil {
+		return err
+	}
+
+	return ds.Composite.SaveState()
+}
+
+func (ds *dockSite) RestoreState() error {
+	state, err := ds.ReadState()
+	if err != nil {
+		return err
+	}
+
+	if state != "" {
+		if err := ds.manager.restoreState(state); err != nil {
+			return err
+		}
+	}
+
+	return ds.Composite.RestoreState()
+}
+
+// toolWindowFrame is the top-level window that holds floating ToolWindows.
+type toolWindowFrame struct {
+	FormBase
+	manager   *DockingManager
+	tabWidget *TabWidget
+}
+
+func newToolWindowFrame(dm *DockingManager) (*toolWindowFrame, error) {
+	frame := &toolWindowFrame{
+		FormBase: FormBase{
+			owner: dm.mainWindow,
+		},
+		manager: dm,
+	}
+
+	if err := InitWindow(
+		frame,
+		dm.mainWindow,
+		toolWindowFrameWindowClass,
+		win.WS_CAPTION|win.WS_SYSMENU|win.WS_THICKFRAME,
+		win.WS_EX_TOOLWINDOW); err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			frame.Dispose()
+		}
+	}()
+
+	layout := NewVBoxLayout()
+	layout.SetMargins(Margins{})
+	if err := frame.SetLayout(layout); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if frame.tabWidget, err = NewTabWidget(frame); err != nil {
+		return nil, err
+	}
+	frame.tabWidget.pageDragOutHandler = dm.onPageDraggedOut
+	frame.tabWidget.CurrentIndexChanged().Attach(frame.updateTitle)
+
+	frame.Closing().Attach(func(canceled *bool, reason CloseReason) {
+		*canceled = true
+
+		frame.Synchronize(func() {
+			pages := frame.tabWidget.Pages()
+
+			toolWindows := make([]*ToolWindow, 0, pages.Len())
+			for i := 0; i < pages.Len(); i++ {
+				if tw := dm.toolWindowForPage(pages.At(i)); tw != nil {
+					toolWindows = append(toolWindows, tw)
+				}
+			}
+
+			for _, tw := range toolWindows {
+				tw.SetVisible(false)
+			}
+		})
+	})
+
+	succeeded = true
+
+	return frame, nil
+}
+
+func (f *toolWindowFrame) updateTitle() {
+	if index := f.tabWidget.CurrentIndex(); index > -1 {
+		f.SetTitle(f.tabWidget.Pages().At(index).Title())
+	}
+}
+
+// beginMove lets the user move the frame with the mouse, as if its caption
+// had been grabbed.
+func (f *toolWindowFrame) beginMove() {
+	win.ReleaseCapture()
+	f.SendMessage(win.WM_SYSCOMMAND, win.SC_MOVE|win.HTCAPTION, 0)
+}
+
+func (f *toolWindowFrame) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_MOVING:
+		f.manager.onFrameMoving(f, (*win.RECT)(unsafe.Pointer(lParam)))
+
+	case win.WM_EXITSIZEMOVE:
+		f.manager.onFrameMoved(f)
+
+	case win.WM_NCLBUTTONDBLCLK:
+		if wParam == win.HTCAPTION {
+			f.Synchronize(func() {
+				f.manager.dockFrame(f, DockAreaFloating)
+			})
+
+			return 0
+		}
+	}
+
+	return f.FormBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+// dockGuide is a popup window that marks a drop target for docking a
+// floating frame to an edge of the MainWindow.
+type dockGuide struct {
+	WindowBase
+	area   DockArea
+	bounds Rectangle
+	hot    bool
+}
+
+func newDockGuide(owner Form, area DockArea, bounds Rectangle) (*dockGuide, error) {
+	g := &dockGuide{area: area, bounds: bounds}
+
+	if err := InitWindow(
+		g,
+		owner,
+		dockGuideWindowClass,
+		win.WS_POPUP|win.WS_DISABLED,
+		win.WS_EX_TOOLWINDOW|win.WS_EX_TOPMOST|win.WS_EX_NOACTIVATE); err != nil {
+		return nil, err
+	}
+
+	win.SetWindowPos(
+		g.hWnd,
+		win.HWND_TOPMOST,
+		int32(bounds.X),
+		int32(bounds.Y),
+		int32(bounds.Width),
+		int32(bounds.Height),
+		win.SWP_NOACTIVATE|win.SWP_SHOWWINDOW)
+
+	return g, nil
+}
+
+func (g *dockGuide) contains(pt win.POINT) bool {
+	x, y := int(pt.X), int(pt.Y)
+
+	return x >= g.bounds.X && x < g.bounds.X+g.bounds.Width &&
+		y >= g.bounds.Y && y < g.bounds.Y+g.bounds.Height
+}
+
+func (g *dockGuide) setHot(hot bool) {
+	if hot == g.hot {
+		return
+	}
+
+	g.hot = hot
+	g.Invalidate()
+}
+
+func (g *dockGuide) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_PAINT:
+		var ps win.PAINTSTRUCT
+
+		hdc := win.BeginPaint(hwnd, &ps)
+		if hdc == 0 {
+			break
+		}
+		defer win.EndPaint(hwnd, &ps)
+
+		canvas, err := newCanvasFromHDC(hdc)
+		if err != nil {
+			break
+		}
+		defer canvas.Dispose()
+
+		if err := g.paint(canvas); err != nil {
+			break
+		}
+
+		return 0
+	}
+
+	return g.WindowBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (g *dockGuide) paint(canvas *Canvas) error {
+	bounds := g.ClientBoundsPixels()
+
+	bgColor := RGB(240, 240, 240)
+	if g.hot {
+		bgColor = RGB(204, 228, 247)
+	}
+
+	bg, err := NewSolidColorBrush(bgColor)
+	if err != nil {
+		return err
+	}
+	defer bg.Dispose()
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	pen, err := NewCosmeticPen(PenSolid, RGB(0, 120, 215))
+	if err != nil {
+		return err
+	}
+	defer pen.Dispose()
+
+	if err := canvas.DrawRectanglePixels(pen, bounds); err != nil {
+		return err
+	}
+
+	bar, err := NewSolidColorBrush(RGB(0, 120, 215))
+	if err != nil {
+		return err
+	}
+	defer bar.Dispose()
+
+	inner := Rectangle{bounds.X + 4, bounds.Y + 4, bounds.Width - 8, bounds.Height - 8}
+
+	switch g.area {
+	case DockAreaLeft:
+		inner.Width /= 3
+
+	case DockAreaTop:
+		inner.Height /= 3
+
+	case DockAreaRight:
+		inner.X += inner.Width - inner.Width/3
+		inner.Width /= 3
+
+	case DockAreaBottom:
+		inner.Y += inner.Height - inner.Height/3
+		inner.Height /= 3
+	}
+
+	return canvas.FillRectanglePixels(bar, inner)
+}
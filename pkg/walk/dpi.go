@@ -0,0 +1,97 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// DPIScalable is the set of types ScaleToDPI can scale.
+type DPIScalable interface {
+	int | float64 | Margins | Point | Rectangle | Size
+}
+
+// ScaleToDPI converts value from native pixels at fromDPI to native pixels at
+// toDPI. With 96 as fromDPI it converts from 1/96" units, with 96 as toDPI to
+// them.
+//
+// Custom widgets that keep sizes in native pixels can rescale them with it
+// from a DPIChanged handler or an ApplyDPI override.
+func ScaleToDPI[T DPIScalable](value T, fromDPI, toDPI int) T {
+	if fromDPI == toDPI || fromDPI <= 0 {
+		return value
+	}
+
+	scale := float64(toDPI) / float64(fromDPI)
+
+	var scaled interface{}
+	switch v := interface{}(value).(type) {
+	case int:
+		scaled = scaleInt(v, scale)
+
+	case float64:
+		scaled = v * scale
+
+	case Margins:
+		scaled = scaleMargins(v, scale)
+
+	case Point:
+		scaled = scalePoint(v, scale)
+
+	case Rectangle:
+		scaled = scaleRectangle(v, scale)
+
+	case Size:
+		scaled = scaleSize(v, scale)
+	}
+
+	return scaled.(T)
+}
+
+// DPIChanged returns the event that is published after the window has been
+// rescaled for a new DPI, e.g. when its form was moved to another monitor.
+func (wb *WindowBase) DPIChanged() *DPIChangedEvent {
+	return wb.dpiChangedPublisher.Event()
+}
+
+type dpiChange struct {
+	wb     *WindowBase
+	oldDPI int
+}
+
+// pendingDPIChanges collects the windows rescaled while a form handles
+// WM_DPICHANGED, so their DPIChanged events are only published once all of
+// them are rescaled and the form has its new bounds.
+var pendingDPIChanges []dpiChange
+
+// dpiApplied records that the window has been rescaled for dpi, and publishes
+// DPIChanged, if that is a change.
+func (wb *WindowBase) dpiApplied(dpi int) {
+	if wb.dpi == dpi {
+		return
+	}
+
+	oldDPI := wb.dpi
+	wb.dpi = dpi
+
+	if pendingDPIChanges != nil {
+		pendingDPIChanges = append(pendingDPIChanges, dpiChange{wb, oldDPI})
+		return
+	}
+
+	wb.dpiChangedPublisher.Publish(oldDPI, dpi)
+}
+
+// publishPendingDPIChanges publishes the DPIChanged events collected in
+// pendingDPIChanges, in the order the windows were rescaled.
+func publishPendingDPIChanges() {
+	changes := pendingDPIChanges
+	pendingDPIChanges = nil
+
+	for _, change := range changes {
+		if change.wb.hWnd != 0 {
+			change.wb.dpiChangedPublisher.Publish(change.oldDPI, change.wb.dpi)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+type dpiChangedEventHandlerInfo struct {
+	handler DPIChangedEventHandler
+	once    bool
+}
+
+type DPIChangedEventHandler func(oldDPI, newDPI int)
+
+type DPIChangedEvent struct {
+	handlers   []*dpiChangedEventHandlerInfo
+	publishing int // Number of Publish calls in progress
+}
+
+func (e *DPIChangedEvent) Attach(handler DPIChangedEventHandler) int {
+	handlerInfo := &dpiChangedEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*dpiChangedEventHandlerInfo(nil), e.handlers...)
+	}
+
+	for i, h := range e.handlers {
+		if h.handler == nil {
+			e.handlers[i] = handlerInfo
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handlerInfo)
+
+	return len(e.handlers) - 1
+}
+
+func (e *DPIChangedEvent) Detach(handle int) {
+	e.handlers[handle].handler = nil
+}
+
+func (e *DPIChangedEvent) Once(handler DPIChangedEventHandler) {
+	i := e.Attach(handler)
+	e.handlers[i].once = true
+}
+
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *DPIChangedEvent) Connect(handler DPIChangedEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *DPIChangedEvent) AttachOwned(owner Window, handler DPIChangedEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
+type DPIChangedEventPublisher struct {
+	event DPIChangedEvent
+}
+
+func (p *DPIChangedEventPublisher) Event() *DPIChangedEvent {
+	return &p.event
+}
+
+func (p *DPIChangedEventPublisher) Publish(oldDPI, newDPI int) {
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
+
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
+			if h.once {
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
+			}
+
+			handler(oldDPI, newDPI)
+		}
+	}
+}
+
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+func (p *DPIChangedEventPublisher) PublishAsync(oldDPI, newDPI int) {
+	publishAsync(func() {
+		p.Publish(oldDPI, newDPI)
+	})
+}
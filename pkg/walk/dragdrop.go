@@ -0,0 +1,458 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// DropEffect is what happens to the dragged data when it is dropped.
+type DropEffect uint32
+
+const (
+	DropEffectNone DropEffect = 0
+	DropEffectCopy DropEffect = 1
+	DropEffectMove DropEffect = 2
+	DropEffectLink DropEffect = 4
+)
+
+// DataFormat identifies a format of dragged data, i.e. a clipboard format.
+type DataFormat uint16
+
+const (
+	DataFormatText  DataFormat = win.CF_UNICODETEXT
+	DataFormatFiles DataFormat = win.CF_HDROP
+)
+
+// DataFormatHTML is the "HTML Format" that browsers and office applications
+// drag HTML fragments in.
+var DataFormatHTML DataFormat
+
+func init() {
+	AppendToWalkInit(func() {
+		DataFormatHTML = RegisterDataFormat("HTML Format")
+	})
+}
+
+// RegisterDataFormat returns the DataFormat registered under name, e.g. a
+// custom format of the application, like "MyApp.Nodes". All processes that
+// register the same name get the same DataFormat.
+func RegisterDataFormat(name string) DataFormat {
+	ret, _, _ := registerClipboardFormat.Call(uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(name))))
+
+	return DataFormat(ret)
+}
+
+// DragData is the data dragged over or dropped on a window, which may come
+// from another application. It is only valid while the event it came with is
+// handled.
+type DragData struct {
+	dataObject *win.IDataObject
+}
+
+// HasFormat returns whether the data is available in format.
+func (dd *DragData) HasFormat(format DataFormat) bool {
+	fe := newFormatEtc(format)
+
+	return dataObjectQueryGetData(dd.dataObject, &fe) == win.S_OK
+}
+
+// Text returns the data as text, if available.
+func (dd *DragData) Text() (text string, ok bool) {
+	ok = dd.withData(DataFormatText, func(p unsafe.Pointer, size uintptr) {
+		text = win.UTF16PtrToString((*uint16)(p))
+	})
+
+	return
+}
+
+// Files returns the paths of the dragged files, if any.
+func (dd *DragData) Files() (files []string) {
+	dd.withHGlobal(DataFormatFiles, func(hMem win.HGLOBAL) {
+		files = filesFromHDROP(win.HDROP(hMem))
+	})
+
+	return
+}
+
+func filesFromHDROP(hDrop win.HDROP) (files []string) {
+	n := win.DragQueryFile(hDrop, 0xFFFFFFFF, nil, 0)
+	for i := uint(0); i < n; i++ {
+		bufSize := win.DragQueryFile(hDrop, i, nil, 0) + 1
+		buf := make([]uint16, bufSize)
+		if win.DragQueryFile(hDrop, i, &buf[0], bufSize) > 0 {
+			files = append(files, syscall.UTF16ToString(buf))
+		}
+	}
+
+	return
+}
+
+// HTML returns the dragged HTML fragment, if available.
+func (dd *DragData) HTML() (html string, ok bool) {
+	data, ok := dd.Data(DataFormatHTML)
+	if !ok {
+		return "", false
+	}
+
+	return htmlFragment(data)
+}
+
+// htmlFragment returns the fragment of data in the "HTML Format".
+func htmlFragment(data []byte) (string, bool) {
+	start, end := htmlFormatOffset(data, "StartFragment:"), htmlFormatOffset(data, "EndFragment:")
+	if start < 0 || end < start || end > len(data) {
+		return "", false
+	}
+
+	return string(data[start:end]), true
+}
+
+// Data returns the raw bytes of the data in format, if available, e.g. for a
+// format registered with RegisterDataFormat.
+func (dd *DragData) Data(format DataFormat) (data []byte, ok bool) {
+	ok = dd.withData(format, func(p unsafe.Pointer, size uintptr) {
+		data = make([]byte, size)
+		copy(data, (*[1 << 30]byte)(p)[:size:size])
+	})
+
+	return
+}
+
+func (dd *DragData) withHGlobal(format DataFormat, f func(hMem win.HGLOBAL)) bool {
+	fe := newFormatEtc(format)
+
+	var medium stgMedium
+	if dataObjectGetData(dd.dataObject, &fe, &medium) != win.S_OK {
+		return false
+	}
+	defer releaseStgMedium.Call(uintptr(unsafe.Pointer(&medium)))
+
+	if medium.Tymed != tymedHGlobal || medium.HGlobal == 0 {
+		return false
+	}
+
+	f(medium.HGlobal)
+
+	return true
+}
+
+func (dd *DragData) withData(format DataFormat, f func(p unsafe.Pointer, size uintptr)) (ok bool) {
+	dd.withHGlobal(format, func(hMem win.HGLOBAL) {
+		size, _, _ := globalSize.Call(uintptr(hMem))
+
+		p := win.GlobalLock(hMem)
+		if p == nil {
+			return
+		}
+		defer win.GlobalUnlock(hMem)
+
+		f(p, size)
+		ok = true
+	})
+
+	return
+}
+
+// htmlFormatOffset returns the byte offset following the header field name of
+// data in the "HTML Format", or -1.
+func htmlFormatOffset(data []byte, name string) int {
+	i := bytes.Index(data, []byte(name))
+	if i < 0 {
+		return -1
+	}
+
+	value := data[i+len(name):]
+	if j := bytes.IndexAny(value, "\r\n"); j >= 0 {
+		value = value[:j]
+	}
+
+	offset, err := strconv.Atoi(string(bytes.TrimSpace(value)))
+	if err != nil {
+		return -1
+	}
+
+	return offset
+}
+
+// DragEventArgs describes data being dragged over or dropped on a window.
+type DragEventArgs struct {
+	// Data is the dragged data.
+	Data *DragData
+
+	// Point is the position of the mouse in native pixels, relative to the
+	// client area of the window.
+	Point Point
+
+	// Modifiers are the modifier keys pressed.
+	Modifiers Modifiers
+
+	// AllowedEffects are the effects the source of the data allows.
+	AllowedEffects DropEffect
+
+	// Effect is the effect the drop would have, initially the one the
+	// modifier keys suggest. Handlers set it to DropEffectNone to refuse the
+	// data, which shows the "no drop" cursor, or to another allowed effect.
+	// After Drop, it is reported to the source, e.g. so it deletes moved
+	// data.
+	Effect DropEffect
+}
+
+// AcceptsDrops returns whether data can be dropped on the window with
+// drag and drop.
+func (wb *WindowBase) AcceptsDrops() bool {
+	return wb.dropTarget != nil
+}
+
+// SetAcceptsDrops sets whether data, like files, text or HTML from this or
+// another application, can be dropped on the window with drag and drop.
+//
+// While the data is dragged over the window or its children, DragEnter,
+// DragOver and DragLeave are published, and Drop once it is dropped. A child
+// that accepts drops itself receives them instead.
+func (wb *WindowBase) SetAcceptsDrops(accepts bool) error {
+	if accepts == (wb.dropTarget != nil) {
+		return nil
+	}
+
+	if !accepts {
+		wb.revokeDropTarget()
+		return nil
+	}
+
+	dt := newDropTarget(wb)
+
+	if hr := registerDragDrop(wb.hWnd, dt); hr != win.S_OK {
+		return newError(fmt.Sprintf("RegisterDragDrop failed: 0x%x", hr))
+	}
+
+	wb.dropTarget = dt
+
+	return nil
+}
+
+func (wb *WindowBase) revokeDropTarget() {
+	if wb.dropTarget == nil {
+		return
+	}
+
+	revokeDragDrop.Call(uintptr(wb.hWnd))
+
+	wb.dropTarget = nil
+}
+
+// DragEnter returns the event that is published when data is dragged into
+// the window. See SetAcceptsDrops.
+func (wb *WindowBase) DragEnter() *DragEvent {
+	return wb.dragEnterPublisher.Event()
+}
+
+// DragOver returns the event that is published when data is dragged within
+// the window, or the modifier keys change. It is not published if the
+// handlers of DragEnter refused the data.
+func (wb *WindowBase) DragOver() *DragEvent {
+	return wb.dragOverPublisher.Event()
+}
+
+// DragLeave returns the event that is published when the dragged data leaves
+// the window, or the drag is canceled.
+func (wb *WindowBase) DragLeave() *Event {
+	return wb.dragLeavePublisher.Event()
+}
+
+// Drop returns the event that is published when the data is dropped on the
+// window, unless it was refused.
+func (wb *WindowBase) Drop() *DragEvent {
+	return wb.dropPublisher.Event()
+}
+
+// suggestedDropEffect returns the effect that the modifier keys of keyState
+// choose among allowed, like in Windows Explorer: Ctrl copies, Shift moves
+// and Ctrl+Shift or Alt links. Without modifiers, data is copied if possible.
+func suggestedDropEffect(keyState uint32, allowed DropEffect) DropEffect {
+	var effect DropEffect
+
+	switch {
+	case keyState&win.MK_CONTROL != 0 && keyState&win.MK_SHIFT != 0, keyState&mkAlt != 0:
+		effect = DropEffectLink
+
+	case keyState&win.MK_CONTROL != 0:
+		effect = DropEffectCopy
+
+	case keyState&win.MK_SHIFT != 0:
+		effect = DropEffectMove
+	}
+
+	if effect&allowed != 0 {
+		return effect
+	}
+
+	for _, effect := range []DropEffect{DropEffectCopy, DropEffectMove, DropEffectLink} {
+		if effect&allowed != 0 {
+			return effect
+		}
+	}
+
+	return DropEffectNone
+}
+
+func modifiersFromKeyState(keyState uint32) Modifiers {
+	var mods Modifiers
+
+	if keyState&win.MK_SHIFT != 0 {
+		mods |= ModShift
+	}
+	if keyState&win.MK_CONTROL != 0 {
+		mods |= ModControl
+	}
+	if keyState&mkAlt != 0 {
+		mods |= ModAlt
+	}
+
+	return mods
+}
+
+// DragSourceData is the data a window provides when it starts a drag and
+// drop operation with DoDragDrop. It can hold the same content in several
+// formats, so each drop target picks the one it understands best.
+type DragSourceData struct {
+	formats     []DataFormat
+	format2Data map[DataFormat][]byte
+}
+
+// NewDragSourceData returns a new, empty DragSourceData.
+func NewDragSourceData() *DragSourceData {
+	return &DragSourceData{format2Data: make(map[DataFormat][]byte)}
+}
+
+// SetText sets the data as text.
+func (d *DragSourceData) SetText(text string) {
+	d.SetData(DataFormatText, utf16Bytes(text))
+}
+
+// SetHTML sets the data as HTML fragment, e.g. for rich text editors and
+// browsers.
+func (d *DragSourceData) SetHTML(fragment string) {
+	d.SetData(DataFormatHTML, htmlFormatData(fragment))
+}
+
+// htmlFormatData returns fragment in the "HTML Format", i.e. in a minimal
+// document with a header of the offsets of both.
+func htmlFormatData(fragment string) []byte {
+	const header = "Version:0.9\r\nStartHTML:%010d\r\nEndHTML:%010d\r\nStartFragment:%010d\r\nEndFragment:%010d\r\n"
+	const prefix = "<html><body>\r\n<!--StartFragment-->"
+	const suffix = "<!--EndFragment-->\r\n</body></html>"
+
+	headerLen := len(fmt.Sprintf(header, 0, 0, 0, 0))
+	startFragment := headerLen + len(prefix)
+	endFragment := startFragment + len(fragment)
+	endHTML := endFragment + len(suffix)
+
+	data := fmt.Sprintf(header, headerLen, endHTML, startFragment, endFragment) + prefix + fragment + suffix
+
+	return append([]byte(data), 0)
+}
+
+// SetFiles sets the data as paths of files, e.g. to drag them to Windows
+// Explorer.
+func (d *DragSourceData) SetFiles(paths []string) {
+	d.SetData(DataFormatFiles, dropFilesData(paths))
+}
+
+// dropFilesData returns paths in the CF_HDROP format.
+func dropFilesData(paths []string) []byte {
+	// DROPFILES followed by the wide paths, each terminated by 0, and a
+	// final 0.
+	var dropFiles struct {
+		pFiles uint32
+		pt     win.POINT
+		fNC    win.BOOL
+		fWide  win.BOOL
+	}
+	dropFiles.pFiles = uint32(unsafe.Sizeof(dropFiles))
+	dropFiles.fWide = win.TRUE
+
+	data := make([]byte, unsafe.Sizeof(dropFiles))
+	copy(data, (*[unsafe.Sizeof(dropFiles)]byte)(unsafe.Pointer(&dropFiles))[:])
+
+	for _, path := range paths {
+		data = append(data, utf16Bytes(path)...)
+	}
+	return append(data, 0, 0)
+}
+
+// SetData sets the raw bytes of the data in format, e.g. a format registered
+// with RegisterDataFormat.
+func (d *DragSourceData) SetData(format DataFormat, data []byte) {
+	if _, ok := d.format2Data[format]; !ok {
+		d.formats = append(d.formats, format)
+	}
+
+	d.format2Data[format] = data
+}
+
+// utf16Bytes returns s encoded as UTF-16 with a terminating 0.
+func utf16Bytes(s string) []byte {
+	u := utf16.Encode([]rune(s + "\x00"))
+
+	data := make([]byte, 2*len(u))
+	for i, c := range u {
+		data[2*i] = byte(c)
+		data[2*i+1] = byte(c >> 8)
+	}
+
+	return data
+}
+
+// DoDragDrop lets the user drag data to a window of this or another
+// application, allowing the effects in allowed, and returns the effect of
+// the drop, or DropEffectNone if it was canceled or refused. It blocks until
+// the mouse button is released, so call it when a drag gesture starts, e.g.
+// from a MouseMove handler while the left button is down:
+//
+//	if button == walk.LeftButton {
+//		data := walk.NewDragSourceData()
+//		data.SetText(item.Name)
+//		if effect, _ := walk.DoDragDrop(data, walk.DropEffectCopy|walk.DropEffectMove); effect == walk.DropEffectMove {
+//			model.Remove(item)
+//		}
+//	}
+func DoDragDrop(data *DragSourceData, allowed DropEffect) (DropEffect, error) {
+	if data == nil || len(data.formats) == 0 {
+		return DropEffectNone, newError("no data to drag")
+	}
+
+	obj := newDataObject(data)
+	defer obj.release()
+
+	src := newDropSource()
+
+	var effect uint32
+	hr, _, _ := doDragDrop.Call(
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(src)),
+		uintptr(allowed),
+		uintptr(unsafe.Pointer(&effect)))
+
+	switch hr {
+	case dragDropSDrop:
+		return DropEffect(effect), nil
+
+	case dragDropSCancel:
+		return DropEffectNone, nil
+	}
+
+	return DropEffectNone, newError(fmt.Sprintf("DoDragDrop failed: 0x%x", uint32(hr)))
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows && (386 || arm)
+// +build windows
+// +build 386 arm
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+// The POINTL argument of the IDropTarget methods is passed by value, which
+// takes an argument slot per coordinate on 32-bit Windows.
+
+func dropTarget_DragEnter(dt *dropTarget, dataObject *win.IDataObject, keyState uint32, x, y int32, effect *uint32) uintptr {
+	return dt.dragEnter(dataObject, keyState, win.POINT{X: x, Y: y}, effect)
+}
+
+func dropTarget_DragOver(dt *dropTarget, keyState uint32, x, y int32, effect *uint32) uintptr {
+	return dt.dragOver(keyState, win.POINT{X: x, Y: y}, effect)
+}
+
+func dropTarget_Drop(dt *dropTarget, dataObject *win.IDataObject, keyState uint32, x, y int32, effect *uint32) uintptr {
+	return dt.drop(dataObject, keyState, win.POINT{X: x, Y: y}, effect)
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows && (amd64 || arm64)
+// +build windows
+// +build amd64 arm64
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+// The POINTL argument of the IDropTarget methods is passed by value, which
+// takes a single argument slot on 64-bit Windows.
+
+func dropTarget_DragEnter(dt *dropTarget, dataObject *win.IDataObject, keyState uint32, pt uintptr, effect *uint32) uintptr {
+	return dt.dragEnter(dataObject, keyState, pointFromPOINTL(pt), effect)
+}
+
+func dropTarget_DragOver(dt *dropTarget, keyState uint32, pt uintptr, effect *uint32) uintptr {
+	return dt.dragOver(keyState, pointFromPOINTL(pt), effect)
+}
+
+func dropTarget_Drop(dt *dropTarget, dataObject *win.IDataObject, keyState uint32, pt uintptr, effect *uint32) uintptr {
+	return dt.drop(dataObject, keyState, pointFromPOINTL(pt), effect)
+}
+
+func pointFromPOINTL(pt uintptr) win.POINT {
+	return win.POINT{X: int32(uint32(pt)), Y: int32(uint32(pt >> 32))}
+}
@@ -0,0 +1,481 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const (
+	mkAlt = 0x20
+
+	dvAspectContent = 1
+	tymedHGlobal    = 1
+	dataDirGet      = 1
+
+	dragDropSDrop              = 0x00040100
+	dragDropSCancel            = 0x00040101
+	dragDropSUseDefaultCursors = 0x00040102
+	dvEFormatEtc               = 0x80040064
+	dvETymed                   = 0x80040069
+	oleEAdviseNotSupported     = 0x80040003
+)
+
+var (
+	iidIDataObject = win.IID{Data1: 0x0000010E, Data2: 0x0000, Data3: 0x0000, Data4: [8]byte{0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+	iidIDropSource = win.IID{Data1: 0x00000121, Data2: 0x0000, Data3: 0x0000, Data4: [8]byte{0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+	iidIDropTarget = win.IID{Data1: 0x00000122, Data2: 0x0000, Data3: 0x0000, Data4: [8]byte{0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+)
+
+var (
+	doDragDrop            = libole32.NewProc("DoDragDrop")
+	registerDragDropProc  = libole32.NewProc("RegisterDragDrop")
+	revokeDragDrop        = libole32.NewProc("RevokeDragDrop")
+	releaseStgMedium      = libole32.NewProc("ReleaseStgMedium")
+	shCreateStdEnumFmtEtc = syscall.NewLazyDLL("shell32.dll").NewProc("SHCreateStdEnumFmtEtc")
+	globalSize            = syscall.NewLazyDLL("kernel32.dll").NewProc("GlobalSize")
+)
+
+// formatEtc mirrors FORMATETC.
+type formatEtc struct {
+	CfFormat DataFormat
+	Ptd      uintptr
+	DwAspect uint32
+	Lindex   int32
+	Tymed    uint32
+}
+
+func newFormatEtc(format DataFormat) formatEtc {
+	return formatEtc{CfFormat: format, DwAspect: dvAspectContent, Lindex: -1, Tymed: tymedHGlobal}
+}
+
+// stgMedium mirrors STGMEDIUM for TYMED_HGLOBAL.
+type stgMedium struct {
+	Tymed          uint32
+	HGlobal        win.HGLOBAL
+	PUnkForRelease uintptr
+}
+
+func dataObjectGetData(obj *win.IDataObject, fe *formatEtc, medium *stgMedium) win.HRESULT {
+	ret, _, _ := syscall.Syscall(obj.LpVtbl.GetData, 3,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(fe)),
+		uintptr(unsafe.Pointer(medium)))
+
+	return win.HRESULT(ret)
+}
+
+func dataObjectQueryGetData(obj *win.IDataObject, fe *formatEtc) win.HRESULT {
+	ret, _, _ := syscall.Syscall(obj.LpVtbl.QueryGetData, 2,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(fe)),
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func iunknownAddRef(obj unsafe.Pointer) {
+	vtbl := *(**win.IUnknownVtbl)(obj)
+	syscall.Syscall(vtbl.AddRef, 1, uintptr(obj), 0, 0)
+}
+
+func iunknownRelease(obj unsafe.Pointer) {
+	vtbl := *(**win.IUnknownVtbl)(obj)
+	syscall.Syscall(vtbl.Release, 1, uintptr(obj), 0, 0)
+}
+
+// comObjects keeps the objects implemented in Go alive while COM holds
+// references to them.
+var comObjects = make(map[unsafe.Pointer]interface{})
+
+// dropTarget implements IDropTarget for a window that accepts drops.
+type dropTarget struct {
+	lpVtbl   *dropTargetVtbl
+	refCount int32
+	window   *WindowBase
+	data     *DragData
+	accepted bool
+}
+
+type dropTargetVtbl struct {
+	win.IUnknownVtbl
+	DragEnter uintptr
+	DragOver  uintptr
+	DragLeave uintptr
+	Drop      uintptr
+}
+
+var dropTargetVtblInstance *dropTargetVtbl
+
+func init() {
+	AppendToWalkInit(func() {
+		dropTargetVtblInstance = &dropTargetVtbl{
+			IUnknownVtbl: win.IUnknownVtbl{
+				QueryInterface: syscall.NewCallback(dropTarget_QueryInterface),
+				AddRef:         syscall.NewCallback(dropTarget_AddRef),
+				Release:        syscall.NewCallback(dropTarget_Release),
+			},
+			DragEnter: syscall.NewCallback(dropTarget_DragEnter),
+			DragOver:  syscall.NewCallback(dropTarget_DragOver),
+			DragLeave: syscall.NewCallback(dropTarget_DragLeave),
+			Drop:      syscall.NewCallback(dropTarget_Drop),
+		}
+
+		dropSourceVtblInstance = &dropSourceVtbl{
+			IUnknownVtbl: win.IUnknownVtbl{
+				QueryInterface: syscall.NewCallback(dropSource_QueryInterface),
+				AddRef:         syscall.NewCallback(dropSource_AddRef),
+				Release:        syscall.NewCallback(dropSource_Release),
+			},
+			QueryContinueDrag: syscall.NewCallback(dropSource_QueryContinueDrag),
+			GiveFeedback:      syscall.NewCallback(dropSource_GiveFeedback),
+		}
+
+		dataObjectVtblInstance = &win.IDataObjectVtbl{
+			IUnknownVtbl: win.IUnknownVtbl{
+				QueryInterface: syscall.NewCallback(dataObject_QueryInterface),
+				AddRef:         syscall.NewCallback(dataObject_AddRef),
+				Release:        syscall.NewCallback(dataObject_Release),
+			},
+			GetData:               syscall.NewCallback(dataObject_GetData),
+			GetDataHere:           syscall.NewCallback(dataObject_GetDataHere),
+			QueryGetData:          syscall.NewCallback(dataObject_QueryGetData),
+			GetCanonicalFormatEtc: syscall.NewCallback(dataObject_GetCanonicalFormatEtc),
+			SetData:               syscall.NewCallback(dataObject_SetData),
+			EnumFormatEtc:         syscall.NewCallback(dataObject_EnumFormatEtc),
+			DAdvise:               syscall.NewCallback(dataObject_DAdvise),
+			DUnadvise:             syscall.NewCallback(dataObject_DUnadvise),
+			EnumDAdvise:           syscall.NewCallback(dataObject_EnumDAdvise),
+		}
+	})
+}
+
+func newDropTarget(window *WindowBase) *dropTarget {
+	return &dropTarget{lpVtbl: dropTargetVtblInstance, window: window}
+}
+
+func registerDragDrop(hwnd win.HWND, dt *dropTarget) win.HRESULT {
+	hr, _, _ := registerDragDropProc.Call(uintptr(hwnd), uintptr(unsafe.Pointer(dt)))
+
+	return win.HRESULT(hr)
+}
+
+func dropTarget_QueryInterface(dt *dropTarget, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &iidIDropTarget) {
+		*ppvObject = unsafe.Pointer(dt)
+		dropTarget_AddRef(dt)
+		return win.S_OK
+	}
+
+	*ppvObject = nil
+	return win.E_NOINTERFACE
+}
+
+func dropTarget_AddRef(dt *dropTarget) uintptr {
+	dt.refCount++
+	comObjects[unsafe.Pointer(dt)] = dt
+
+	return uintptr(dt.refCount)
+}
+
+func dropTarget_Release(dt *dropTarget) uintptr {
+	dt.refCount--
+	if dt.refCount == 0 {
+		delete(comObjects, unsafe.Pointer(dt))
+	}
+
+	return uintptr(dt.refCount)
+}
+
+func (dt *dropTarget) newArgs(keyState uint32, screenPt win.POINT, allowed DropEffect) *DragEventArgs {
+	win.ScreenToClient(dt.window.hWnd, &screenPt)
+
+	return &DragEventArgs{
+		Data:           dt.data,
+		Point:          Point{int(screenPt.X), int(screenPt.Y)},
+		Modifiers:      modifiersFromKeyState(keyState),
+		AllowedEffects: allowed,
+		Effect:         suggestedDropEffect(keyState, allowed),
+	}
+}
+
+func (dt *dropTarget) dragEnter(dataObject *win.IDataObject, keyState uint32, pt win.POINT, effect *uint32) uintptr {
+	iunknownAddRef(unsafe.Pointer(dataObject))
+	dt.data = &DragData{dataObject: dataObject}
+
+	args := dt.newArgs(keyState, pt, DropEffect(*effect))
+	dt.window.dragEnterPublisher.Publish(args)
+
+	dt.accepted = args.Effect&args.AllowedEffects != 0
+	*effect = uint32(args.Effect & args.AllowedEffects)
+
+	return win.S_OK
+}
+
+func (dt *dropTarget) dragOver(keyState uint32, pt win.POINT, effect *uint32) uintptr {
+	if !dt.accepted || dt.data == nil {
+		*effect = uint32(DropEffectNone)
+		return win.S_OK
+	}
+
+	args := dt.newArgs(keyState, pt, DropEffect(*effect))
+	dt.window.dragOverPublisher.Publish(args)
+
+	*effect = uint32(args.Effect & args.AllowedEffects)
+
+	return win.S_OK
+}
+
+func dropTarget_DragLeave(dt *dropTarget) uintptr {
+	dt.window.dragLeavePublisher.Publish()
+
+	dt.releaseData()
+
+	return win.S_OK
+}
+
+func (dt *dropTarget) drop(dataObject *win.IDataObject, keyState uint32, pt win.POINT, effect *uint32) uintptr {
+	defer dt.releaseData()
+
+	if !dt.accepted || dt.data == nil {
+		*effect = uint32(DropEffectNone)
+		return win.S_OK
+	}
+
+	args := dt.newArgs(keyState, pt, DropEffect(*effect))
+	// The source may hand a different object to Drop than to DragEnter.
+	args.Data = &DragData{dataObject: dataObject}
+	dt.window.dropPublisher.Publish(args)
+
+	*effect = uint32(args.Effect & args.AllowedEffects)
+
+	return win.S_OK
+}
+
+func (dt *dropTarget) releaseData() {
+	if dt.data != nil {
+		iunknownRelease(unsafe.Pointer(dt.data.dataObject))
+		dt.data.dataObject = nil
+		dt.data = nil
+	}
+
+	dt.accepted = false
+}
+
+// dropSource implements IDropSource for DoDragDrop.
+type dropSource struct {
+	lpVtbl   *dropSourceVtbl
+	refCount int32
+}
+
+type dropSourceVtbl struct {
+	win.IUnknownVtbl
+	QueryContinueDrag uintptr
+	GiveFeedback      uintptr
+}
+
+var dropSourceVtblInstance *dropSourceVtbl
+
+func newDropSource() *dropSource {
+	ds := &dropSource{lpVtbl: dropSourceVtblInstance}
+	dropSource_AddRef(ds)
+
+	return ds
+}
+
+func dropSource_QueryInterface(ds *dropSource, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &iidIDropSource) {
+		*ppvObject = unsafe.Pointer(ds)
+		dropSource_AddRef(ds)
+		return win.S_OK
+	}
+
+	*ppvObject = nil
+	return win.E_NOINTERFACE
+}
+
+func dropSource_AddRef(ds *dropSource) uintptr {
+	ds.refCount++
+	comObjects[unsafe.Pointer(ds)] = ds
+
+	return uintptr(ds.refCount)
+}
+
+func dropSource_Release(ds *dropSource) uintptr {
+	ds.refCount--
+	if ds.refCount == 0 {
+		delete(comObjects, unsafe.Pointer(ds))
+	}
+
+	return uintptr(ds.refCount)
+}
+
+func dropSource_QueryContinueDrag(ds *dropSource, escapePressed win.BOOL, keyState uint32) uintptr {
+	if escapePressed != 0 {
+		return uintptr(dragDropSCancel)
+	}
+
+	if keyState&(win.MK_LBUTTON|win.MK_RBUTTON) == 0 {
+		return uintptr(dragDropSDrop)
+	}
+
+	return win.S_OK
+}
+
+func dropSource_GiveFeedback(ds *dropSource, effect uint32) uintptr {
+	// The system shows the copy, move, link and "no drop" cursors.
+	return uintptr(dragDropSUseDefaultCursors)
+}
+
+// dataObject implements IDataObject for the DragSourceData of DoDragDrop.
+type dataObject struct {
+	lpVtbl   *win.IDataObjectVtbl
+	refCount int32
+	data     *DragSourceData
+}
+
+var dataObjectVtblInstance *win.IDataObjectVtbl
+
+func newDataObject(data *DragSourceData) *dataObject {
+	obj := &dataObject{lpVtbl: dataObjectVtblInstance, data: data}
+	dataObject_AddRef(obj)
+
+	return obj
+}
+
+func (obj *dataObject) release() {
+	dataObject_Release(obj)
+}
+
+func dataObject_QueryInterface(obj *dataObject, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &iidIDataObject) {
+		*ppvObject = unsafe.Pointer(obj)
+		dataObject_AddRef(obj)
+		return win.S_OK
+	}
+
+	*ppvObject = nil
+	return win.E_NOINTERFACE
+}
+
+func dataObject_AddRef(obj *dataObject) uintptr {
+	obj.refCount++
+	comObjects[unsafe.Pointer(obj)] = obj
+
+	return uintptr(obj.refCount)
+}
+
+func dataObject_Release(obj *dataObject) uintptr {
+	obj.refCount--
+	if obj.refCount == 0 {
+		delete(comObjects, unsafe.Pointer(obj))
+	}
+
+	return uintptr(obj.refCount)
+}
+
+func (obj *dataObject) dataFor(fe *formatEtc) ([]byte, uintptr) {
+	if fe.DwAspect != dvAspectContent || fe.Lindex != -1 {
+		return nil, dvEFormatEtc
+	}
+
+	data, ok := obj.data.format2Data[fe.CfFormat]
+	if !ok {
+		return nil, dvEFormatEtc
+	}
+
+	if fe.Tymed&tymedHGlobal == 0 {
+		return nil, dvETymed
+	}
+
+	return data, win.S_OK
+}
+
+func dataObject_GetData(obj *dataObject, fe *formatEtc, medium *stgMedium) uintptr {
+	data, hr := obj.dataFor(fe)
+	if hr != win.S_OK {
+		return hr
+	}
+
+	// The receiver owns the medium and frees it with ReleaseStgMedium.
+	hMem := win.GlobalAlloc(win.GMEM_MOVEABLE, uintptr(len(data)))
+	if hMem == 0 {
+		return win.E_OUTOFMEMORY
+	}
+
+	p := win.GlobalLock(hMem)
+	if p == nil {
+		win.GlobalFree(hMem)
+		return win.E_OUTOFMEMORY
+	}
+	if len(data) > 0 {
+		copy((*[1 << 30]byte)(p)[:len(data):len(data)], data)
+	}
+	win.GlobalUnlock(hMem)
+
+	medium.Tymed = tymedHGlobal
+	medium.HGlobal = hMem
+	medium.PUnkForRelease = 0
+
+	return win.S_OK
+}
+
+func dataObject_GetDataHere(obj *dataObject, fe *formatEtc, medium *stgMedium) uintptr {
+	return win.E_NOTIMPL
+}
+
+func dataObject_QueryGetData(obj *dataObject, fe *formatEtc) uintptr {
+	_, hr := obj.dataFor(fe)
+
+	return hr
+}
+
+func dataObject_GetCanonicalFormatEtc(obj *dataObject, feIn, feOut *formatEtc) uintptr {
+	feOut.Ptd = 0
+
+	return win.E_NOTIMPL
+}
+
+func dataObject_SetData(obj *dataObject, fe *formatEtc, medium *stgMedium, release win.BOOL) uintptr {
+	return win.E_NOTIMPL
+}
+
+func dataObject_EnumFormatEtc(obj *dataObject, direction uint32, ppEnum *unsafe.Pointer) uintptr {
+	if direction != dataDirGet {
+		*ppEnum = nil
+		return win.E_NOTIMPL
+	}
+
+	fes := make([]formatEtc, len(obj.data.formats))
+	for i, format := range obj.data.formats {
+		fes[i] = newFormatEtc(format)
+	}
+
+	// The shell copies the array into an enumerator of its own.
+	hr, _, _ := shCreateStdEnumFmtEtc.Call(
+		uintptr(len(fes)),
+		uintptr(unsafe.Pointer(&fes[0])),
+		uintptr(unsafe.Pointer(ppEnum)))
+
+	return hr
+}
+
+func dataObject_DAdvise(obj *dataObject, fe *formatEtc, advf uint32, sink, connection uintptr) uintptr {
+	return oleEAdviseNotSupported
+}
+
+func dataObject_DUnadvise(obj *dataObject, connection uint32) uintptr {
+	return oleEAdviseNotSupported
+}
+
+func dataObject_EnumDAdvise(obj *dataObject, ppEnum *unsafe.Pointer) uintptr {
+	return oleEAdviseNotSupported
+}
@@ -21,8 +21,9 @@ type dropFilesEventHandlerInfo struct {
 type DropFilesEventHandler func([]string)
 
 type DropFilesEvent struct {
-	hWnd     win.HWND
-	handlers []dropFilesEventHandlerInfo
+	hWnd       win.HWND
+	handlers   []*dropFilesEventHandlerInfo
+	publishing int // Number of Publish calls in progress
 }
 
 func (e *DropFilesEvent) Attach(handler DropFilesEventHandler) int {
@@ -30,7 +31,12 @@ func (e *DropFilesEvent) Attach(handler DropFilesEventHandler) int {
 		win.DragAcceptFiles(e.hWnd, true)
 	}
 
-	handlerInfo := dropFilesEventHandlerInfo{handler, false}
+	handlerInfo := &dropFilesEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*dropFilesEventHandlerInfo(nil), e.handlers...)
+	}
 
 	for i, h := range e.handlers {
 		if h.handler == nil {
@@ -61,6 +67,20 @@ func (e *DropFilesEvent) Once(handler DropFilesEventHandler) {
 	e.handlers[i].once = true
 }
 
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *DropFilesEvent) Connect(handler DropFilesEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *DropFilesEvent) AttachOwned(owner Window, handler DropFilesEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
 type DropFilesEventPublisher struct {
 	event DropFilesEvent
 }
@@ -83,13 +103,20 @@ func (p *DropFilesEventPublisher) Publish(hDrop win.HDROP) {
 	}
 	win.DragFinish(hDrop)
 
-	for i, h := range p.event.handlers {
-		if h.handler != nil {
-			h.handler(files)
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
 
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
 			if h.once {
-				p.event.Detach(i)
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
 			}
+
+			handler(files)
 		}
 	}
 }
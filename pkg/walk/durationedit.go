@@ -0,0 +1,808 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const durationEditWindowClass = `\o/ Walk_DurationEdit_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(durationEditWindowClass)
+	})
+}
+
+// DurationFormat specifies the units a DurationEdit displays a time span in.
+type DurationFormat int
+
+const (
+	// DurationFormatMinutesSeconds displays minutes, seconds and
+	// milliseconds as MM:SS.fff.
+	DurationFormatMinutesSeconds DurationFormat = iota
+
+	// DurationFormatSeconds displays seconds and milliseconds as SSSS.fff.
+	DurationFormatSeconds
+
+	// DurationFormatFrames displays a number of frames at the frame rate of
+	// the DurationEdit.
+	DurationFormatFrames
+)
+
+// durationSegment describes a group of digits of a DurationEdit text.
+type durationSegment struct {
+	digits int
+	limit  int  // exclusive upper bound of the segment value
+	unit   int  // ticks per step of the segment value
+	sep    byte // separator following the segment, 0 for the last one
+}
+
+func durationSegmentsForFormat(format DurationFormat) []durationSegment {
+	switch format {
+	case DurationFormatSeconds:
+		return []durationSegment{{4, 10000, 1000, '.'}, {3, 1000, 1, 0}}
+
+	case DurationFormatFrames:
+		return []durationSegment{{6, 1000000, 1, 0}}
+	}
+
+	return []durationSegment{{2, 100, 60000, ':'}, {2, 60, 1000, '.'}, {3, 1000, 1, 0}}
+}
+
+// DurationEdit is a widget that is suited to edit a time.Duration.
+//
+// Its text is split into segments of fixed width like a masked edit, e.g.
+// MM:SS.fff. Typing overwrites digits and skips separators, typing a
+// separator moves on to the next segment and the segment at the caret can be
+// incremented and decremented using the KeyUp and KeyDown keys, the mouse
+// wheel or the spin buttons. Values are clamped to the range of the
+// DurationEdit.
+type DurationEdit struct {
+	WidgetBase
+	edit       *durationLineEdit
+	hWndUpDown win.HWND
+}
+
+// NewDurationEdit returns a new DurationEdit widget as child of parent.
+//
+// The initial format is DurationFormatMinutesSeconds and the frame rate,
+// used with DurationFormatFrames, is 30 frames per second.
+func NewDurationEdit(parent Container) (*DurationEdit, error) {
+	de := new(DurationEdit)
+
+	if err := InitWidget(
+		de,
+		parent,
+		durationEditWindowClass,
+		win.WS_VISIBLE,
+		win.WS_EX_CONTROLPARENT); err != nil {
+		return nil, err
+	}
+
+	var succeeded bool
+	defer func() {
+		if !succeeded {
+			de.Dispose()
+		}
+	}()
+
+	var err error
+	if de.edit, err = newDurationLineEdit(de); err != nil {
+		return nil, err
+	}
+
+	de.edit.applyFont(de.Font())
+
+	if err = de.edit.setTicks(0, true); err != nil {
+		return nil, err
+	}
+
+	de.GraphicsEffects().Add(InteractionEffect)
+	de.GraphicsEffects().Add(FocusEffect)
+
+	de.MustRegisterProperty("Duration", NewProperty(
+		func() interface{} {
+			return de.Duration()
+		},
+		func(v interface{}) error {
+			d, _ := v.(time.Duration)
+			return de.SetDuration(d)
+		},
+		de.edit.durationChangedPublisher.Event()))
+
+	de.MustRegisterProperty("ReadOnly", NewProperty(
+		func() interface{} {
+			return de.ReadOnly()
+		},
+		func(v interface{}) error {
+			return de.SetReadOnly(v.(bool))
+		},
+		de.edit.readOnlyChangedPublisher.Event()))
+
+	succeeded = true
+
+	return de, nil
+}
+
+func (de *DurationEdit) applyEnabled(enabled bool) {
+	de.WidgetBase.applyEnabled(enabled)
+
+	if de.edit == nil {
+		return
+	}
+
+	de.edit.applyEnabled(enabled)
+}
+
+func (de *DurationEdit) applyFont(font *Font) {
+	de.WidgetBase.applyFont(font)
+
+	if de.edit == nil {
+		return
+	}
+
+	de.edit.applyFont(font)
+}
+
+// Format returns the units the DurationEdit displays its value in.
+func (de *DurationEdit) Format() DurationFormat {
+	return de.edit.format
+}
+
+// SetFormat sets the units the DurationEdit displays its value in.
+//
+// The value is retained as close as the new units allow.
+func (de *DurationEdit) SetFormat(format DurationFormat) error {
+	if format < DurationFormatMinutesSeconds || format > DurationFormatFrames {
+		return newError("invalid format")
+	}
+
+	if format == de.edit.format {
+		return nil
+	}
+
+	d := de.Duration()
+
+	de.edit.format = format
+	de.edit.segments = durationSegmentsForFormat(format)
+
+	if err := de.edit.setTicks(de.edit.clamp(de.edit.durationToTicks(d)), true); err != nil {
+		return err
+	}
+
+	de.RequestLayout()
+
+	return nil
+}
+
+// FPS returns the number of frames per second, used with
+// DurationFormatFrames.
+func (de *DurationEdit) FPS() int {
+	return de.edit.fps
+}
+
+// SetFPS sets the number of frames per second, used with
+// DurationFormatFrames.
+func (de *DurationEdit) SetFPS(fps int) error {
+	if fps < 1 || fps > 100 {
+		return newError("fps must >= 1 && <= 100")
+	}
+
+	if fps == de.edit.fps {
+		return nil
+	}
+
+	d := de.Duration()
+
+	de.edit.fps = fps
+
+	return de.edit.setTicks(de.edit.clamp(de.edit.durationToTicks(d)), true)
+}
+
+// Range returns the minimum and maximum value of the DurationEdit.
+//
+// A maximum of 0 means the limit is the largest value the format can
+// display.
+func (de *DurationEdit) Range() (min, max time.Duration) {
+	return de.edit.minValue, de.edit.maxValue
+}
+
+// SetRange sets the minimum and maximum value of the DurationEdit.
+//
+// If the current value is out of range, it will be adjusted.
+func (de *DurationEdit) SetRange(min, max time.Duration) error {
+	if min < 0 || max < 0 {
+		return newError("min and max must >= 0")
+	}
+	if max > 0 && min > max {
+		return newError("invalid range")
+	}
+
+	de.edit.minValue, de.edit.maxValue = min, max
+
+	if t := de.edit.clamp(de.edit.ticks); t != de.edit.ticks {
+		return de.edit.setTicks(t, true)
+	}
+
+	return nil
+}
+
+// Duration returns the value of the DurationEdit.
+func (de *DurationEdit) Duration() time.Duration {
+	return de.edit.ticksToDuration(de.edit.ticks)
+}
+
+// SetDuration sets the value of the DurationEdit to the nearest value the
+// format can display, clamped to the range of the DurationEdit.
+func (de *DurationEdit) SetDuration(d time.Duration) error {
+	return de.edit.setTicks(de.edit.clamp(de.edit.durationToTicks(d)), true)
+}
+
+// DurationChanged returns an Event that can be used to track changes to
+// Duration.
+func (de *DurationEdit) DurationChanged() *Event {
+	return de.edit.durationChangedPublisher.Event()
+}
+
+// SetFocus sets the keyboard input focus to the DurationEdit.
+func (de *DurationEdit) SetFocus() error {
+	if win.SetFocus(de.edit.hWnd) == 0 {
+		return lastError("SetFocus")
+	}
+
+	return nil
+}
+
+// ReadOnly returns whether the DurationEdit is in read-only mode.
+func (de *DurationEdit) ReadOnly() bool {
+	return de.edit.ReadOnly()
+}
+
+// SetReadOnly sets whether the DurationEdit is in read-only mode.
+func (de *DurationEdit) SetReadOnly(readOnly bool) error {
+	if readOnly != de.ReadOnly() {
+		de.invalidateBorderInParent()
+	}
+
+	return de.edit.SetReadOnly(readOnly)
+}
+
+// WheelValueMode returns whether the mouse wheel changes Duration.
+func (de *DurationEdit) WheelValueMode() WheelValueMode {
+	return de.edit.wheelValueMode
+}
+
+// SetWheelValueMode sets whether the mouse wheel changes Duration.
+// WheelValueDefault uses App().WheelValueMode().
+func (de *DurationEdit) SetWheelValueMode(mode WheelValueMode) {
+	de.edit.wheelValueMode = mode
+	de.edit.wheelDelta = 0
+}
+
+// SpinButtonsVisible returns whether the DurationEdit appears with spin
+// buttons.
+func (de *DurationEdit) SpinButtonsVisible() bool {
+	return de.hWndUpDown != 0
+}
+
+// SetSpinButtonsVisible sets whether the DurationEdit appears with spin
+// buttons.
+//
+// The spin buttons increment or decrement the segment at the caret.
+func (de *DurationEdit) SetSpinButtonsVisible(visible bool) error {
+	return setUpDownVisible(&de.hWndUpDown, de.hWnd, de.edit.hWnd, visible)
+}
+
+// Background returns the background Brush of the DurationEdit.
+//
+// By default this is nil.
+func (de *DurationEdit) Background() Brush {
+	return de.edit.Background()
+}
+
+// SetBackground sets the background Brush of the DurationEdit.
+func (de *DurationEdit) SetBackground(bg Brush) {
+	de.edit.SetBackground(bg)
+}
+
+// TextColor returns the Color used to draw the text of the DurationEdit.
+func (de *DurationEdit) TextColor() Color {
+	return de.edit.TextColor()
+}
+
+// SetTextColor sets the Color used to draw the text of the DurationEdit.
+func (de *DurationEdit) SetTextColor(c Color) {
+	de.edit.SetTextColor(c)
+}
+
+func (de *DurationEdit) SetToolTipText(s string) error {
+	return de.edit.SetToolTipText(s)
+}
+
+func (*DurationEdit) NeedsWmSize() bool {
+	return true
+}
+
+// WndProc is the window procedure of the DurationEdit.
+//
+// When implementing your own WndProc to add or modify behavior, call the
+// WndProc of the embedded DurationEdit for messages you don't handle yourself.
+func (de *DurationEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_NOTIFY:
+		switch ((*win.NMHDR)(unsafe.Pointer(lParam))).Code {
+		case win.UDN_DELTAPOS:
+			nmud := (*win.NMUPDOWN)(unsafe.Pointer(lParam))
+			de.edit.incrementSegment(-int(nmud.IDelta))
+		}
+
+	case win.WM_CTLCOLOREDIT, win.WM_CTLCOLORSTATIC:
+		if hBrush := de.handleWMCTLCOLOR(wParam, lParam); hBrush != 0 {
+			return hBrush
+		}
+
+	case win.WM_WINDOWPOSCHANGED:
+		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
+
+		if wp.Flags&win.SWP_NOSIZE != 0 {
+			break
+		}
+
+		if de.edit == nil {
+			break
+		}
+
+		cb := de.ClientBoundsPixels()
+		if err := de.edit.SetBoundsPixels(cb); err != nil {
+			break
+		}
+
+		if de.hWndUpDown != 0 {
+			win.SendMessage(de.hWndUpDown, win.UDM_SETBUDDY, uintptr(de.edit.hWnd), 0)
+		}
+	}
+
+	return de.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (de *DurationEdit) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &numberEditLayoutItem{
+		idealSize: de.dialogBaseUnitsToPixels(Size{56, 12}),
+		minSize:   de.dialogBaseUnitsToPixels(Size{40, 12}),
+	}
+}
+
+type durationLineEdit struct {
+	*LineEdit
+	format                   DurationFormat
+	segments                 []durationSegment
+	ticks                    int // milliseconds or frames, depending on format
+	fps                      int
+	minValue                 time.Duration
+	maxValue                 time.Duration
+	wheelValueMode           WheelValueMode
+	wheelDelta               int
+	durationChangedPublisher EventPublisher
+}
+
+func newDurationLineEdit(parent Widget) (*durationLineEdit, error) {
+	dle := &durationLineEdit{
+		segments: durationSegmentsForFormat(DurationFormatMinutesSeconds),
+		fps:      30,
+	}
+
+	var err error
+	if dle.LineEdit, err = newLineEdit(parent, win.WS_EX_CLIENTEDGE); err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			dle.Dispose()
+		}
+	}()
+
+	if err := dle.LineEdit.setAndClearStyleBits(win.ES_RIGHT, win.ES_LEFT|win.ES_CENTER); err != nil {
+		return nil, err
+	}
+
+	if err := InitWrapperWindow(dle); err != nil {
+		return nil, err
+	}
+
+	succeeded = true
+
+	return dle, nil
+}
+
+func (dle *durationLineEdit) TextColor() Color {
+	return dle.LineEdit.TextColor()
+}
+
+func (dle *durationLineEdit) SetTextColor(c Color) {
+	dle.LineEdit.SetTextColor(c)
+}
+
+func (dle *durationLineEdit) durationToTicks(d time.Duration) int {
+	if dle.format == DurationFormatFrames {
+		return DurationToFrames(d, dle.fps)
+	}
+
+	return int((d + time.Millisecond/2) / time.Millisecond)
+}
+
+func (dle *durationLineEdit) ticksToDuration(ticks int) time.Duration {
+	if dle.format == DurationFormatFrames {
+		return FramesToDuration(ticks, dle.fps)
+	}
+
+	return time.Duration(ticks) * time.Millisecond
+}
+
+func (dle *durationLineEdit) maxTicksDisplayable() int {
+	var ticks int
+	for _, seg := range dle.segments {
+		ticks += (seg.limit - 1) * seg.unit
+	}
+
+	return ticks
+}
+
+func (dle *durationLineEdit) clamp(ticks int) int {
+	max := dle.maxTicksDisplayable()
+	if dle.maxValue > 0 {
+		// Round down, so the clamped value never exceeds the maximum.
+		if t := dle.durationToTicks(dle.maxValue); dle.ticksToDuration(t) > dle.maxValue {
+			max = mini(max, t-1)
+		} else {
+			max = mini(max, t)
+		}
+	}
+
+	min := dle.durationToTicks(dle.minValue)
+	if dle.ticksToDuration(min) < dle.minValue {
+		min++
+	}
+
+	if ticks > max {
+		ticks = max
+	}
+	if ticks < min {
+		ticks = min
+	}
+
+	return ticks
+}
+
+func (dle *durationLineEdit) formatTicks(ticks int) string {
+	var sb strings.Builder
+
+	for _, seg := range dle.segments {
+		fmt.Fprintf(&sb, "%0*d", seg.digits, ticks/seg.unit%seg.limit)
+
+		if seg.sep != 0 {
+			sb.WriteByte(seg.sep)
+		}
+	}
+
+	return sb.String()
+}
+
+func (dle *durationLineEdit) textLen() int {
+	n := len(dle.segments) - 1
+	for _, seg := range dle.segments {
+		n += seg.digits
+	}
+
+	return n
+}
+
+// segmentStart returns the text position of the first digit of segment seg.
+func (dle *durationLineEdit) segmentStart(seg int) int {
+	var pos int
+	for i := 0; i < seg; i++ {
+		pos += dle.segments[i].digits + 1
+	}
+
+	return pos
+}
+
+// segmentAt returns the index of the segment at text position pos. The
+// position of a separator belongs to the segment before it.
+func (dle *durationLineEdit) segmentAt(pos int) int {
+	for seg := range dle.segments {
+		if pos <= dle.segmentStart(seg)+dle.segments[seg].digits {
+			return seg
+		}
+	}
+
+	return len(dle.segments) - 1
+}
+
+// isSeparatorAt returns whether text position pos holds a separator.
+func (dle *durationLineEdit) isSeparatorAt(pos int) bool {
+	seg := dle.segmentAt(pos)
+
+	return pos == dle.segmentStart(seg)+dle.segments[seg].digits && seg < len(dle.segments)-1
+}
+
+func (dle *durationLineEdit) setTicks(ticks int, setText bool) error {
+	if setText {
+		start, end := dle.TextSelection()
+
+		if err := dle.SetText(dle.formatTicks(ticks)); err != nil {
+			return err
+		}
+
+		dle.SetTextSelection(start, end)
+	}
+
+	if ticks == dle.ticks {
+		return nil
+	}
+
+	dle.ticks = ticks
+
+	dle.durationChangedPublisher.Publish()
+
+	return nil
+}
+
+func (dle *durationLineEdit) caretSegment() int {
+	start, _ := dle.TextSelection()
+
+	return dle.segmentAt(start)
+}
+
+func (dle *durationLineEdit) selectSegment(seg int) {
+	start := dle.segmentStart(seg)
+	dle.SetTextSelection(start, start+dle.segments[seg].digits)
+}
+
+func (dle *durationLineEdit) incrementSegment(steps int) {
+	if dle.ReadOnly() || steps == 0 {
+		return
+	}
+
+	seg := dle.caretSegment()
+
+	dle.setTicks(dle.clamp(dle.ticks+steps*dle.segments[seg].unit), true)
+	dle.selectSegment(seg)
+}
+
+// ticksFromText parses the digits in text, clamping each segment to its
+// valid range.
+func (dle *durationLineEdit) ticksFromText(text []byte) int {
+	var ticks int
+	for seg, s := range dle.segments {
+		start := dle.segmentStart(seg)
+
+		var v int
+		for _, c := range text[start : start+s.digits] {
+			v = v*10 + int(c-'0')
+		}
+		if v >= s.limit {
+			v = s.limit - 1
+		}
+
+		ticks += v * s.unit
+	}
+
+	return dle.clamp(ticks)
+}
+
+// parseText parses text typed or pasted in free form, e.g. "1:30" or "90.5".
+func (dle *durationLineEdit) parseText(text string) (int, bool) {
+	text = strings.TrimSpace(text)
+
+	if dle.format == DurationFormatFrames {
+		var frames int
+		if _, err := fmt.Sscan(text, &frames); err != nil || frames < 0 {
+			return 0, false
+		}
+
+		return frames, true
+	}
+
+	var minutes int
+	if i := strings.IndexByte(text, ':'); i >= 0 {
+		if _, err := fmt.Sscan(text[:i], &minutes); err != nil || minutes < 0 {
+			return 0, false
+		}
+
+		text = text[i+1:]
+	}
+
+	var seconds float64
+	if _, err := fmt.Sscan(text, &seconds); err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return minutes*60000 + int(seconds*1000+0.5), true
+}
+
+// overwriteDigit replaces the digit at pos with digit and moves the caret
+// behind it, skipping separators.
+func (dle *durationLineEdit) overwriteDigit(pos int, digit byte) {
+	if dle.isSeparatorAt(pos) {
+		pos++
+	}
+	if pos >= dle.textLen() {
+		return
+	}
+
+	text := []byte(dle.formatTicks(dle.ticks))
+	text[pos] = digit
+
+	dle.setTicks(dle.ticksFromText(text), true)
+
+	pos++
+	if dle.isSeparatorAt(pos) {
+		pos++
+	}
+	dle.SetTextSelection(pos, pos)
+}
+
+// moveCaret moves the caret by one position in direction dir, skipping
+// separators.
+func (dle *durationLineEdit) moveCaret(dir int) {
+	start, end := dle.TextSelection()
+
+	pos := start + dir
+	if dir > 0 && end > start {
+		pos = end
+	} else if dir < 0 && end > start {
+		pos = start
+	}
+
+	if dle.isSeparatorAt(pos) && pos > 0 && pos < dle.textLen() {
+		pos += dir
+	}
+
+	if pos < 0 {
+		pos = 0
+	} else if n := dle.textLen(); pos > n {
+		pos = n
+	}
+
+	dle.SetTextSelection(pos, pos)
+}
+
+func (dle *durationLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_CHAR:
+		if dle.ReadOnly() {
+			break
+		}
+
+		if AltDown() || ControlDown() {
+			break
+		}
+
+		start, _ := dle.TextSelection()
+
+		switch char := byte(wParam); {
+		case char >= '0' && char <= '9':
+			dle.overwriteDigit(start, char)
+
+		case char == ':' || char == '.' || char == ',' || char == ' ':
+			if seg := dle.segmentAt(start); seg < len(dle.segments)-1 {
+				dle.selectSegment(seg + 1)
+			}
+
+		case Key(wParam) == KeyBack:
+			if dle.isSeparatorAt(start - 1) {
+				start--
+			}
+			if start > 0 {
+				dle.overwriteDigit(start-1, '0')
+				dle.SetTextSelection(start-1, start-1)
+			}
+		}
+
+		return 0
+
+	case win.WM_KEYDOWN:
+		switch Key(wParam) {
+		case KeyDelete:
+			if dle.ReadOnly() {
+				break
+			}
+
+			start, _ := dle.TextSelection()
+			dle.overwriteDigit(start, '0')
+			return 0
+
+		case KeyLeft, KeyRight:
+			if ShiftDown() {
+				break
+			}
+
+			if ControlDown() {
+				// Jump segment-wise.
+				seg := dle.caretSegment()
+				if Key(wParam) == KeyLeft && seg > 0 {
+					seg--
+				} else if Key(wParam) == KeyRight && seg < len(dle.segments)-1 {
+					seg++
+				}
+				dle.selectSegment(seg)
+				return 0
+			}
+
+			if Key(wParam) == KeyLeft {
+				dle.moveCaret(-1)
+			} else {
+				dle.moveCaret(1)
+			}
+			return 0
+
+		case KeyDown:
+			dle.incrementSegment(-1)
+			return 0
+
+		case KeyUp:
+			dle.incrementSegment(1)
+			return 0
+
+		case KeyPrior:
+			dle.incrementSegment(10)
+			return 0
+
+		case KeyNext:
+			dle.incrementSegment(-10)
+			return 0
+		}
+
+	case win.WM_KILLFOCUS:
+		invalidateWrapperBorderInParent(dle.hWnd)
+
+	case win.WM_SETFOCUS:
+		invalidateWrapperBorderInParent(dle.hWnd)
+
+	case win.WM_LBUTTONDBLCLK:
+		dle.selectSegment(dle.caretSegment())
+		return 0
+
+	case win.WM_MOUSEWHEEL:
+		if dle.ReadOnly() || !wheelChangesValue(dle.wheelValueMode, dle.Focused()) {
+			break
+		}
+
+		if notches := wheelNotches(&dle.wheelDelta, wParam); notches != 0 {
+			dle.incrementSegment(notches)
+		}
+		return 0
+
+	case win.WM_PASTE:
+		if dle.ReadOnly() {
+			break
+		}
+
+		ret := dle.LineEdit.WndProc(hwnd, msg, wParam, lParam)
+		if ticks, ok := dle.parseText(dle.Text()); ok {
+			dle.setTicks(dle.clamp(ticks), true)
+		} else {
+			dle.setTicks(dle.ticks, true)
+		}
+		dle.SetTextSelection(0, dle.textLen())
+		return ret
+
+	case win.WM_CUT, win.WM_CLEAR:
+		return 0
+	}
+
+	return dle.LineEdit.WndProc(hwnd, msg, wParam, lParam)
+}
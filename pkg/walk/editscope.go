@@ -0,0 +1,239 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// EditScope records the changes users make to the values of widgets, e.g. of
+// a form, on a CommandStack, so undo reverts the last field edit as a whole
+// instead of each keystroke or step of a Slider.
+//
+// Changes of a widget are grouped until the focus leaves it or another
+// widget is changed. Between BeginUserEdit and EndUserEdit, changes of
+// several widgets are grouped into one Command instead. Changes made by
+// calling methods like SetValue, including those of data binding, are not
+// recorded.
+//
+// The CheckBox, LineEdit, NumberEdit and Slider widgets are supported.
+type EditScope struct {
+	stack     *CommandStack
+	changes   []*editScopeChange
+	userEdits int // Number of BeginUserEdit calls without EndUserEdit
+	applying  bool
+	tracked   map[Window]bool
+}
+
+type editScopeChange struct {
+	window   Window
+	oldValue interface{}
+	newValue interface{}
+	setValue func(value interface{}) error
+}
+
+// NewEditScope returns a new EditScope that records the changes of the
+// supported descendants of container on stack. Descendants added later can
+// be recorded with Track.
+func NewEditScope(container Container, stack *CommandStack) *EditScope {
+	es := &EditScope{stack: stack, tracked: make(map[Window]bool)}
+
+	WalkDescendants(container, func(w Widget) TreeTraversalDirective {
+		es.Track(w)
+
+		return TraverseContinue
+	})
+
+	return es
+}
+
+// CommandStack returns the CommandStack the EditScope records changes on.
+func (es *EditScope) CommandStack() *CommandStack {
+	return es.stack
+}
+
+// Track records the changes of w from now on, until it is disposed. It
+// returns false if w is not supported.
+func (es *EditScope) Track(w Window) bool {
+	if es.tracked[w] {
+		return true
+	}
+
+	var changed *ValueChangedEvent
+	var focusWindow Window
+	var setValue func(value interface{}) error
+
+	switch w := w.(type) {
+	case *CheckBox:
+		changed, focusWindow = w.CheckStateChangedDetailed(), w
+		setValue = func(value interface{}) error {
+			w.SetCheckState(value.(CheckState))
+			return nil
+		}
+
+	case *LineEdit:
+		changed, focusWindow = w.TextChangedDetailed(), w
+		setValue = func(value interface{}) error {
+			return w.SetText(value.(string))
+		}
+
+	case *NumberEdit:
+		changed, focusWindow = w.ValueChangedDetailed(), w.edit
+		setValue = func(value interface{}) error {
+			return w.SetValue(value.(float64))
+		}
+
+	case *Slider:
+		changed, focusWindow = w.ValueChangedDetailed(), w
+		setValue = func(value interface{}) error {
+			w.SetValue(value.(int))
+			return nil
+		}
+
+	default:
+		return false
+	}
+
+	es.tracked[w] = true
+
+	changed.AttachOwned(w, func(change ValueChange) {
+		es.record(w, change, setValue)
+	})
+
+	focusWindow.FocusedChanged().AttachOwned(w, func() {
+		if !focusWindow.Focused() && es.userEdits == 0 {
+			es.Commit()
+		}
+	})
+
+	w.Disposing().Once(func() {
+		delete(es.tracked, w)
+	})
+
+	return true
+}
+
+// BeginUserEdit starts grouping the changes of all widgets into one Command,
+// until the matching EndUserEdit. Calls may be nested.
+func (es *EditScope) BeginUserEdit() {
+	if es.userEdits == 0 {
+		es.Commit()
+	}
+
+	es.userEdits++
+}
+
+// EndUserEdit ends the group of changes the matching BeginUserEdit started.
+func (es *EditScope) EndUserEdit() {
+	if es.userEdits == 0 {
+		return
+	}
+
+	es.userEdits--
+
+	if es.userEdits == 0 {
+		es.Commit()
+	}
+}
+
+// Commit pushes the changes recorded since the last Command, if any, as a
+// Command on the CommandStack.
+func (es *EditScope) Commit() {
+	if len(es.changes) == 0 {
+		return
+	}
+
+	cmd := &editScopeCommand{scope: es, changes: es.changes}
+	es.changes = nil
+
+	es.stack.Push(cmd)
+}
+
+// Undo commits the pending changes and undoes the last Command of the
+// CommandStack, e.g. when the user presses Ctrl+Z.
+func (es *EditScope) Undo() error {
+	es.Commit()
+
+	return es.stack.Undo()
+}
+
+// Redo redoes the last undone Command of the CommandStack. Pending changes
+// are committed first, so there is none to redo then.
+func (es *EditScope) Redo() error {
+	es.Commit()
+
+	return es.stack.Redo()
+}
+
+func (es *EditScope) record(w Window, change ValueChange, setValue func(value interface{}) error) {
+	if es.applying || change.Source == ValueChangeSourceProgrammatic {
+		return
+	}
+
+	if es.userEdits == 0 && len(es.changes) > 0 && es.changes[0].window != w {
+		es.Commit()
+	}
+
+	for i, c := range es.changes {
+		if c.window == w {
+			c.newValue = change.NewValue
+
+			// A value changed back, e.g. a CheckBox clicked twice, is no
+			// change.
+			if c.newValue == c.oldValue {
+				es.changes = append(es.changes[:i], es.changes[i+1:]...)
+			}
+
+			return
+		}
+	}
+
+	es.changes = append(es.changes, &editScopeChange{
+		window:   w,
+		oldValue: change.OldValue,
+		newValue: change.NewValue,
+		setValue: setValue,
+	})
+}
+
+// editScopeCommand is the Command of the changes an EditScope grouped.
+type editScopeCommand struct {
+	scope   *EditScope
+	changes []*editScopeChange
+}
+
+func (cmd *editScopeCommand) Undo() error {
+	return cmd.apply(func(i int) (*editScopeChange, interface{}) {
+		c := cmd.changes[len(cmd.changes)-1-i]
+		return c, c.oldValue
+	})
+}
+
+func (cmd *editScopeCommand) Redo() error {
+	return cmd.apply(func(i int) (*editScopeChange, interface{}) {
+		c := cmd.changes[i]
+		return c, c.newValue
+	})
+}
+
+func (cmd *editScopeCommand) apply(change func(i int) (*editScopeChange, interface{})) error {
+	cmd.scope.applying = true
+	defer func() {
+		cmd.scope.applying = false
+	}()
+
+	for i := range cmd.changes {
+		c, value := change(i)
+
+		if c.window.IsDisposed() {
+			continue
+		}
+
+		if err := c.setValue(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
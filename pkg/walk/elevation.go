@@ -0,0 +1,282 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	seeMaskNoCloseProcess = 0x40
+	seeMaskNoAsync        = 0x100
+
+	// elevatedFuncArg is the first argument of a process started by
+	// RunElevatedFunc, followed by the name of the function.
+	elevatedFuncArg = "-walk-elevated-func"
+)
+
+var shellExecuteEx = syscall.NewLazyDLL("shell32.dll").NewProc("ShellExecuteExW")
+
+// shellExecuteInfo mirrors SHELLEXECUTEINFOW.
+type shellExecuteInfo struct {
+	CbSize         uint32
+	FMask          uint32
+	Hwnd           win.HWND
+	LpVerb         *uint16
+	LpFile         *uint16
+	LpParameters   *uint16
+	LpDirectory    *uint16
+	NShow          int32
+	HInstApp       uintptr
+	LpIDList       uintptr
+	LpClass        *uint16
+	HkeyClass      uintptr
+	DwHotKey       uint32
+	HIconOrMonitor uintptr
+	HProcess       windows.Handle
+}
+
+// ErrElevationCanceled is returned if the user declined the prompt of the
+// User Account Control to run something elevated.
+var ErrElevationCanceled = errors.New("elevation canceled by the user")
+
+var (
+	elevated         int // 0 unknown, 1 elevated and -1 not
+	name2ElevateFunc map[string]func(args []string) error
+	shieldIcon       *Icon
+)
+
+// IsElevated returns whether the process runs with the full rights of an
+// administrator.
+func IsElevated() bool {
+	if elevated == 0 {
+		elevated = -1
+		if windows.GetCurrentProcessToken().IsElevated() {
+			elevated = 1
+		}
+	}
+
+	return elevated == 1
+}
+
+// ShieldIcon returns the small shield icon of the User Account Control that
+// marks commands which require elevation.
+func ShieldIcon() *Icon {
+	if shieldIcon == nil {
+		shieldIcon = &Icon{res: win.MAKEINTRESOURCE(win.IDI_SHIELD), size96dpi: Size{16, 16}, isStock: true}
+	}
+
+	return shieldIcon
+}
+
+// ElevatedProcess is a process started elevated by RunElevated or
+// RunElevatedFunc.
+type ElevatedProcess struct {
+	handle windows.Handle
+}
+
+// Pid returns the process id of the process.
+func (p *ElevatedProcess) Pid() int {
+	pid, _ := windows.GetProcessId(p.handle)
+
+	return int(pid)
+}
+
+// Wait waits for the process to exit and returns its exit code. As it
+// blocks, call it from a goroutine, e.g. the work of FormBase.RunTask.
+func (p *ElevatedProcess) Wait() (exitCode int, err error) {
+	if p.handle == 0 {
+		return 0, newError("process already waited for")
+	}
+	defer func() {
+		windows.CloseHandle(p.handle)
+		p.handle = 0
+	}()
+
+	if _, err := windows.WaitForSingleObject(p.handle, windows.INFINITE); err != nil {
+		return 0, err
+	}
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(p.handle, &code); err != nil {
+		return 0, err
+	}
+
+	return int(code), nil
+}
+
+// RunElevated starts the program name with args elevated, after the user
+// confirmed the prompt of the User Account Control, which is modal to owner.
+// It returns ErrElevationCanceled if the user declined.
+func RunElevated(owner Form, name string, args ...string) (*ElevatedProcess, error) {
+	var hwnd win.HWND
+	if owner != nil {
+		hwnd = owner.Handle()
+	}
+
+	escaped := make([]string, len(args))
+	for i, arg := range args {
+		escaped[i] = syscall.EscapeArg(arg)
+	}
+
+	dir, _ := os.Getwd()
+
+	sei := shellExecuteInfo{
+		FMask:        seeMaskNoCloseProcess | seeMaskNoAsync,
+		Hwnd:         hwnd,
+		LpVerb:       syscall.StringToUTF16Ptr("runas"),
+		LpFile:       syscall.StringToUTF16Ptr(name),
+		LpParameters: syscall.StringToUTF16Ptr(strings.Join(escaped, " ")),
+		LpDirectory:  syscall.StringToUTF16Ptr(dir),
+		NShow:        win.SW_SHOWNORMAL,
+	}
+	sei.CbSize = uint32(unsafe.Sizeof(sei))
+
+	if ret, _, errno := shellExecuteEx.Call(uintptr(unsafe.Pointer(&sei))); ret == 0 {
+		if errno == syscall.Errno(errorCancelled) {
+			return nil, ErrElevationCanceled
+		}
+
+		return nil, newError("ShellExecuteEx: " + errno.Error())
+	}
+
+	return &ElevatedProcess{handle: sei.HProcess}, nil
+}
+
+// RelaunchElevated starts the program again elevated, with the same
+// arguments. If it returns nil, the application should exit, so the elevated
+// instance takes over.
+func RelaunchElevated(owner Form) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return wrapError(err)
+	}
+
+	p, err := RunElevated(owner, exe, os.Args[1:]...)
+	if err != nil {
+		return err
+	}
+
+	windows.CloseHandle(p.handle)
+
+	return nil
+}
+
+// RegisterElevatedFunc registers fn as the function RunElevatedFunc calls by
+// name in an elevated instance of the program.
+//
+// Functions must be registered in every instance, before
+// HandleElevatedFunc is called.
+func RegisterElevatedFunc(name string, fn func(args []string) error) {
+	if name2ElevateFunc == nil {
+		name2ElevateFunc = make(map[string]func(args []string) error)
+	}
+
+	name2ElevateFunc[name] = fn
+}
+
+// RunElevatedFunc calls the function registered as name with args in a new,
+// elevated instance of the program, e.g. for the occasional operation that
+// needs the rights of an administrator. If the process already is elevated,
+// the function is called right away and nil is returned for the process.
+//
+// The exit code of the instance is 0 if the function returned nil, 1
+// otherwise.
+func RunElevatedFunc(owner Form, name string, args ...string) (*ElevatedProcess, error) {
+	fn, ok := name2ElevateFunc[name]
+	if !ok {
+		return nil, newError("unknown elevated func: " + name)
+	}
+
+	if IsElevated() {
+		return nil, fn(args)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return RunElevated(owner, exe, append([]string{elevatedFuncArg, name}, args...)...)
+}
+
+// HandleElevatedFunc must be called at the start of main, after the functions
+// were registered with RegisterElevatedFunc. If the process was started by
+// RunElevatedFunc, it calls the function and exits the process, otherwise it
+// returns.
+func HandleElevatedFunc() {
+	if len(os.Args) < 3 || os.Args[1] != elevatedFuncArg {
+		return
+	}
+
+	fn, ok := name2ElevateFunc[os.Args[2]]
+	if !ok || fn(os.Args[3:]) != nil {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+// RequiresElevation returns whether the Action runs something that needs the
+// rights of an administrator.
+func (a *Action) RequiresElevation() bool {
+	return a.requiresElevation
+}
+
+// SetRequiresElevation sets whether the Action runs something that needs the
+// rights of an administrator. Unless the process is elevated, the Action
+// shows the shield icon instead of its image in menus and tool bars.
+func (a *Action) SetRequiresElevation(value bool) (err error) {
+	if value != a.requiresElevation {
+		a.requiresElevation = value
+
+		if err = a.raiseChanged(); err != nil {
+			a.requiresElevation = !value
+			a.raiseChanged()
+		}
+	}
+
+	return
+}
+
+// displayImage returns the image shown for the Action.
+func (a *Action) displayImage() Image {
+	if a.requiresElevation && !IsElevated() {
+		return ShieldIcon()
+	}
+
+	return a.image
+}
+
+// RequiresElevation returns whether clicking the PushButton runs something
+// that needs the rights of an administrator.
+func (pb *PushButton) RequiresElevation() bool {
+	return pb.requiresElevation
+}
+
+// SetRequiresElevation sets whether clicking the PushButton runs something
+// that needs the rights of an administrator. Unless the process is elevated,
+// the button shows the shield icon.
+func (pb *PushButton) SetRequiresElevation(value bool) {
+	if value == pb.requiresElevation {
+		return
+	}
+
+	pb.requiresElevation = value
+
+	pb.SendMessage(win.BCM_SETSHIELD, 0, uintptr(win.BoolToBOOL(value && !IsElevated())))
+
+	pb.RequestLayout()
+}
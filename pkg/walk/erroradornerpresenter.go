@@ -0,0 +1,99 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// ErrorAdornerPresenter is an ErrorPresenter that marks every invalid widget
+// at once with ValidationErrorEffect and shows the error message in a tool
+// tip with an error icon while the mouse hovers the widget.
+//
+// Unlike ToolTipErrorPresenter, it doesn't pop up balloons, so it suits
+// forms that validate while the user types.
+type ErrorAdornerPresenter struct {
+	toolTip      *ToolTip
+	widget2error map[Widget]error
+}
+
+func NewErrorAdornerPresenter() (*ErrorAdornerPresenter, error) {
+	tt, err := NewToolTip()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tt.SetErrorTitle(tr("Invalid Input")); err != nil {
+		tt.Dispose()
+		return nil, err
+	}
+
+	return &ErrorAdornerPresenter{
+		toolTip:      tt,
+		widget2error: make(map[Widget]error),
+	}, nil
+}
+
+func (eap *ErrorAdornerPresenter) Dispose() {
+	if eap.toolTip == nil {
+		return
+	}
+
+	for widget := range eap.widget2error {
+		eap.removeAdorner(widget)
+	}
+
+	eap.toolTip.Dispose()
+	eap.toolTip = nil
+}
+
+func (eap *ErrorAdornerPresenter) PresentError(err error, widget Widget) {
+	if eap.toolTip == nil || widget == nil {
+		return
+	}
+
+	if err == nil {
+		if _, ok := eap.widget2error[widget]; ok {
+			eap.removeAdorner(widget)
+			delete(eap.widget2error, widget)
+		}
+
+		return
+	}
+
+	if _, ok := eap.widget2error[widget]; !ok {
+		if e := eap.toolTip.AddTool(widget); e != nil {
+			return
+		}
+
+		if ValidationErrorEffect != nil {
+			if effects := widget.GraphicsEffects(); !effects.Contains(ValidationErrorEffect) {
+				effects.Add(ValidationErrorEffect)
+			}
+		}
+	}
+
+	eap.widget2error[widget] = err
+
+	text := err.Error()
+	if ve, ok := err.(*ValidationError); ok {
+		text = ve.title + "\n" + ve.message
+	}
+
+	eap.toolTip.SetText(widget, text)
+}
+
+func (eap *ErrorAdornerPresenter) removeAdorner(widget Widget) {
+	eap.toolTip.RemoveTool(widget)
+
+	if widget.IsDisposed() || ValidationErrorEffect == nil {
+		return
+	}
+
+	// Invalidates while the effect is still active, so the border is
+	// repainted without it.
+	widget.AsWidgetBase().invalidateBorderInParent()
+
+	widget.GraphicsEffects().Remove(ValidationErrorEffect)
+}
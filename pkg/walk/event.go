@@ -7,6 +7,10 @@
 
 package walk
 
+import (
+	"github.com/miu200521358/win"
+)
+
 type eventHandlerInfo struct {
 	handler EventHandler
 	once    bool
@@ -15,11 +19,17 @@ type eventHandlerInfo struct {
 type EventHandler func()
 
 type Event struct {
-	handlers []eventHandlerInfo
+	handlers   []*eventHandlerInfo
+	publishing int // Number of Publish calls in progress
 }
 
 func (e *Event) Attach(handler EventHandler) int {
-	handlerInfo := eventHandlerInfo{handler, false}
+	handlerInfo := &eventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*eventHandlerInfo(nil), e.handlers...)
+	}
 
 	for i, h := range e.handlers {
 		if h.handler == nil {
@@ -42,6 +52,20 @@ func (e *Event) Once(handler EventHandler) {
 	e.handlers[i].once = true
 }
 
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *Event) Connect(handler EventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *Event) AttachOwned(owner Window, handler EventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
 type EventPublisher struct {
 	event Event
 }
@@ -67,13 +91,43 @@ func (p *EventPublisher) Publish() {
 		}()
 	}
 
-	for i, h := range p.event.handlers {
-		if h.handler != nil {
-			h.handler()
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
 
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
 			if h.once {
-				p.event.Detach(i)
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
 			}
+
+			handler()
 		}
 	}
 }
+
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+//
+// This is useful to notify about changes made while handling a window
+// message, e.g. from a model, without handlers running in the middle of it.
+func (p *EventPublisher) PublishAsync() {
+	publishAsync(p.Publish)
+}
+
+// publishAsync calls publish once the message loop of the calling thread
+// finished processing the current message, or right away if there are no
+// windows on the calling thread.
+func publishAsync(publish func()) {
+	group := wgm.Group(win.GetCurrentThreadId())
+	if group == nil {
+		publish()
+		return
+	}
+
+	group.Synchronize(publish)
+	group.wake()
+}
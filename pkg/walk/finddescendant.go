@@ -0,0 +1,49 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// FindDescendant returns the descendant of root, e.g. a Form, with name, see
+// Window.SetName, or nil if there is none. If several have name, the first in
+// the order of the widget tree is returned.
+func FindDescendant(root Window, name string) Window {
+	return findDescendant(root, name)
+}
+
+// FindAs returns the descendant of root with name, e.g. a widget created by a
+// declarative tree, if it exists and has type T.
+//
+//	if te, ok := walk.FindAs[*walk.TextEdit](form, "notes"); ok {
+//		te.SetText("")
+//	}
+func FindAs[T Window](root Window, name string) (T, bool) {
+	t, ok := findDescendant(root, name).(T)
+
+	return t, ok
+}
+
+func findDescendant(root Window, name string) Window {
+	if root == nil || name == "" {
+		return nil
+	}
+
+	var found Window
+	walkDescendants(root, func(w Window) bool {
+		if found != nil {
+			return false
+		}
+
+		if w.Name() == name {
+			found = w
+			return false
+		}
+
+		return true
+	})
+
+	return found
+}
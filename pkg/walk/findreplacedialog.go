@@ -0,0 +1,208 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// Flags of the FINDREPLACE struct.
+const (
+	frDown       = 0x00000001
+	frWholeWord  = 0x00000002
+	frMatchCase  = 0x00000004
+	frReplace    = 0x00000010
+	frReplaceAll = 0x00000020
+	frDialogTerm = 0x00000040
+)
+
+// findReplaceBufferLen is the capacity of the text buffers of the find and
+// replace dialog, in UTF-16 code units.
+const findReplaceBufferLen = 256
+
+var (
+	libcomdlg32  = syscall.NewLazyDLL("comdlg32.dll")
+	findTextW    = libcomdlg32.NewProc("FindTextW")
+	replaceTextW = libcomdlg32.NewProc("ReplaceTextW")
+
+	// findReplaceMsg is sent to the owner of a find and replace dialog, when
+	// the user issued a command.
+	findReplaceMsg uint32
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		findReplaceMsg = win.RegisterWindowMessage(syscall.StringToUTF16Ptr("commdlg_FindReplace"))
+	})
+}
+
+// findReplace is the FINDREPLACE struct.
+type findReplace struct {
+	lStructSize      uint32
+	hwndOwner        win.HWND
+	hInstance        win.HINSTANCE
+	flags            uint32
+	lpstrFindWhat    *uint16
+	lpstrReplaceWith *uint16
+	wFindWhatLen     uint16
+	wReplaceWithLen  uint16
+	lCustData        uintptr
+	lpfnHook         uintptr
+	lpTemplateName   *uint16
+}
+
+type findReplaceCommand int
+
+const (
+	findReplaceCommandFindNext findReplaceCommand = iota
+	findReplaceCommandReplace
+	findReplaceCommandReplaceAll
+	findReplaceCommandClose
+)
+
+type findReplaceRequest struct {
+	command     findReplaceCommand
+	what        string
+	replacement string
+	opts        FindOptions
+	backward    bool
+}
+
+// findReplaceDialog is the modeless stock find or replace dialog of
+// Windows, that notifies its owner with findReplaceMsg.
+type findReplaceDialog struct {
+	owner   Window
+	hWnd    win.HWND
+	fr      *findReplace
+	replace bool
+}
+
+func newFindReplaceDialog(owner Window, replace bool, what string, opts FindOptions) (*findReplaceDialog, error) {
+	// The dialog keeps using the struct and buffers while it exists, so they
+	// must not live in memory managed by Go.
+	structSize := unsafe.Sizeof(findReplace{})
+	mem := win.GlobalAlloc(win.GPTR, structSize+2*findReplaceBufferLen*2)
+	if mem == 0 {
+		return nil, newError("GlobalAlloc failed")
+	}
+
+	fr := (*findReplace)(unsafe.Pointer(mem))
+	fr.lStructSize = uint32(structSize)
+	fr.hwndOwner = owner.Handle()
+	fr.flags = frDown
+	fr.lpstrFindWhat = (*uint16)(unsafe.Pointer(uintptr(mem) + structSize))
+	fr.lpstrReplaceWith = (*uint16)(unsafe.Pointer(uintptr(mem) + structSize + findReplaceBufferLen*2))
+	fr.wFindWhatLen = findReplaceBufferLen
+	fr.wReplaceWithLen = findReplaceBufferLen
+
+	if opts&FindMatchCase != 0 {
+		fr.flags |= frMatchCase
+	}
+	if opts&FindWholeWord != 0 {
+		fr.flags |= frWholeWord
+	}
+
+	findWhat := unsafe.Slice(fr.lpstrFindWhat, findReplaceBufferLen)
+	copy(findWhat[:findReplaceBufferLen-1], syscall.StringToUTF16(what))
+	findWhat[findReplaceBufferLen-1] = 0
+
+	proc := findTextW
+	if replace {
+		proc = replaceTextW
+	}
+
+	hWnd, _, _ := proc.Call(uintptr(unsafe.Pointer(fr)))
+	if hWnd == 0 {
+		win.GlobalFree(mem)
+
+		if replace {
+			return nil, newError("ReplaceText failed")
+		}
+		return nil, newError("FindText failed")
+	}
+
+	dlg := &findReplaceDialog{
+		owner:   owner,
+		hWnd:    win.HWND(hWnd),
+		fr:      fr,
+		replace: replace,
+	}
+
+	owner.AsWindowBase().group.addModelessDialog(dlg.hWnd)
+
+	return dlg, nil
+}
+
+// Activate brings the dialog to the front.
+func (dlg *findReplaceDialog) Activate() {
+	if dlg.hWnd != 0 {
+		win.SetActiveWindow(dlg.hWnd)
+	}
+}
+
+// request returns the command the dialog notified its owner of.
+func (dlg *findReplaceDialog) request() findReplaceRequest {
+	fr := dlg.fr
+
+	req := findReplaceRequest{
+		what:        win.UTF16PtrToString(fr.lpstrFindWhat),
+		replacement: win.UTF16PtrToString(fr.lpstrReplaceWith),
+		backward:    fr.flags&frDown == 0,
+	}
+
+	if fr.flags&frMatchCase != 0 {
+		req.opts |= FindMatchCase
+	}
+	if fr.flags&frWholeWord != 0 {
+		req.opts |= FindWholeWord
+	}
+
+	switch {
+	case fr.flags&frDialogTerm != 0:
+		req.command = findReplaceCommandClose
+
+		// The dialog destroys itself.
+		dlg.owner.AsWindowBase().group.removeModelessDialog(dlg.hWnd)
+		dlg.hWnd = 0
+
+	case fr.flags&frReplaceAll != 0:
+		req.command = findReplaceCommandReplaceAll
+
+	case fr.flags&frReplace != 0:
+		req.command = findReplaceCommandReplace
+
+	default:
+		req.command = findReplaceCommandFindNext
+	}
+
+	return req
+}
+
+// Dispose destroys the dialog, if it still exists, and releases its memory.
+func (dlg *findReplaceDialog) Dispose() {
+	group := dlg.owner.AsWindowBase().group
+
+	if hWnd := dlg.hWnd; hWnd != 0 {
+		// Destroying the dialog notifies the owner, which disposes it again.
+		dlg.hWnd = 0
+		group.removeModelessDialog(hWnd)
+		win.DestroyWindow(hWnd)
+	}
+
+	if fr := dlg.fr; fr != nil {
+		dlg.fr = nil
+
+		// The dialog may still access the struct while notifying its owner.
+		group.Synchronize(func() {
+			win.GlobalFree(win.HGLOBAL(unsafe.Pointer(fr)))
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// FitLabel is a single line label whose font shrinks, down to a minimum point
+// size, so its text fits the available width.
+//
+// If the text doesn't even fit at the minimum size, it is cut off with an
+// ellipsis, either at its end or, with MiddleEllipsis, in its middle, which
+// keeps both the start and the end of e.g. file paths visible. The full text
+// is then shown as tool tip.
+type FitLabel struct {
+	*CustomWidget
+	minPointSize         int
+	middleEllipsis       bool
+	textAlignment        Alignment1D
+	textColor            Color
+	fitFont              *Font
+	fitText              string
+	textChangedPublisher EventPublisher
+}
+
+// NewFitLabel creates and initializes a new FitLabel with a minimum font size
+// of 7 points.
+func NewFitLabel(parent Container) (*FitLabel, error) {
+	fl := &FitLabel{
+		minPointSize:  7,
+		textAlignment: AlignNear,
+	}
+
+	cw, err := NewCustomWidgetPixels(parent, 0, func(canvas *Canvas, updateBounds Rectangle) error {
+		return fl.paint(canvas)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			cw.Dispose()
+		}
+	}()
+
+	fl.CustomWidget = cw
+
+	if err := InitWrapperWindow(fl); err != nil {
+		return nil, err
+	}
+
+	fl.SetBackground(nullBrushSingleton)
+
+	fl.MustRegisterProperty("Text", NewProperty(
+		func() interface{} {
+			return fl.Text()
+		},
+		func(v interface{}) error {
+			return fl.SetText(assertStringOr(v, ""))
+		},
+		fl.textChangedPublisher.Event()))
+
+	succeeded = true
+
+	return fl, nil
+}
+
+func (fl *FitLabel) Text() string {
+	return fl.text()
+}
+
+func (fl *FitLabel) SetText(text string) error {
+	if text == fl.Text() {
+		return nil
+	}
+
+	if err := fl.setText(text); err != nil {
+		return err
+	}
+
+	fl.updateFit()
+	fl.RequestLayout()
+
+	fl.textChangedPublisher.Publish()
+
+	return nil
+}
+
+// MinFontPointSize returns the point size the font of the FitLabel shrinks
+// to at most.
+func (fl *FitLabel) MinFontPointSize() int {
+	return fl.minPointSize
+}
+
+// SetMinFontPointSize sets the point size the font of the FitLabel shrinks to
+// at most. A size not smaller than the size of the font disables shrinking.
+func (fl *FitLabel) SetMinFontPointSize(size int) error {
+	if size < 1 {
+		return newError("size must be positive")
+	}
+
+	fl.minPointSize = size
+
+	fl.updateFit()
+
+	return nil
+}
+
+// MiddleEllipsis returns whether text that doesn't fit is cut off in its
+// middle, rather than at its end.
+func (fl *FitLabel) MiddleEllipsis() bool {
+	return fl.middleEllipsis
+}
+
+// SetMiddleEllipsis sets whether text that doesn't fit is cut off in its
+// middle, rather than at its end.
+func (fl *FitLabel) SetMiddleEllipsis(middle bool) {
+	fl.middleEllipsis = middle
+
+	fl.updateFit()
+}
+
+func (fl *FitLabel) TextAlignment() Alignment1D {
+	return fl.textAlignment
+}
+
+func (fl *FitLabel) SetTextAlignment(alignment Alignment1D) error {
+	if alignment == AlignDefault {
+		alignment = AlignNear
+	}
+
+	if alignment == fl.textAlignment {
+		return nil
+	}
+
+	fl.textAlignment = alignment
+
+	fl.Invalidate()
+	fl.RequestLayout()
+
+	return nil
+}
+
+func (fl *FitLabel) TextColor() Color {
+	return fl.textColor
+}
+
+func (fl *FitLabel) SetTextColor(c Color) {
+	fl.textColor = c
+
+	fl.Invalidate()
+}
+
+// FittedFont returns the font the text of the FitLabel is currently drawn
+// with.
+func (fl *FitLabel) FittedFont() *Font {
+	if fl.fitFont == nil {
+		return fl.Font()
+	}
+
+	return fl.fitFont
+}
+
+func (fl *FitLabel) applyFont(font *Font) {
+	fl.CustomWidget.applyFont(font)
+
+	fl.updateFit()
+}
+
+// updateFit picks the largest font size the text fits the client width with
+// and elides the text, if it doesn't fit at all.
+func (fl *FitLabel) updateFit() {
+	if fl.hWnd == 0 {
+		return
+	}
+
+	defer fl.Invalidate()
+
+	font := fl.Font()
+	text := fl.Text()
+
+	fl.fitFont = font
+	fl.fitText = text
+
+	width := fl.ClientBoundsPixels().Width
+	if text == "" || width <= 0 {
+		return
+	}
+
+	canvas, err := fl.CreateCanvas()
+	if err != nil {
+		return
+	}
+	defer canvas.Dispose()
+
+	fits := func(font *Font, text string) bool {
+		bounds, _, err := canvas.MeasureTextPixels(text, font, Rectangle{Width: 0x3FFFFFFF, Height: 0x3FFFFFFF}, TextSingleLine|TextNoPrefix)
+		return err == nil && bounds.Width <= width
+	}
+
+	for size := font.PointSize(); ; size-- {
+		if f, err := NewFont(font.Family(), size, font.Style()); err == nil {
+			fl.fitFont = f
+		}
+
+		if fits(fl.fitFont, text) {
+			fl.SetToolTipText("")
+			return
+		}
+
+		if size <= fl.minPointSize {
+			break
+		}
+	}
+
+	fl.SetToolTipText(text)
+
+	if !fl.middleEllipsis {
+		// The end ellipsis is left to DrawText.
+		return
+	}
+
+	runes := []rune(text)
+
+	elide := func(keep int) string {
+		head := (keep + 1) / 2
+		return string(runes[:head]) + "…" + string(runes[len(runes)-(keep-head):])
+	}
+
+	// Find the largest number of runes to keep by bisection.
+	lo, hi := 0, len(runes)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+
+		if fits(fl.fitFont, elide(mid)) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	fl.fitText = elide(lo)
+}
+
+func (fl *FitLabel) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_WINDOWPOSCHANGED:
+		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
+
+		if wp.Flags&win.SWP_NOSIZE != 0 {
+			break
+		}
+
+		fl.updateFit()
+	}
+
+	return fl.CustomWidget.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (fl *FitLabel) paint(canvas *Canvas) error {
+	format := TextSingleLine | TextVCenter | TextNoPrefix | TextEndEllipsis
+
+	switch fl.textAlignment {
+	case AlignCenter:
+		format |= TextCenter
+
+	case AlignFar:
+		format |= TextRight
+	}
+
+	color := fl.textColor
+	if !fl.Enabled() {
+		color = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	return canvas.DrawTextPixels(fl.fitText, fl.FittedFont(), color, fl.ClientBoundsPixels(), format)
+}
+
+func (fl *FitLabel) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	var layoutFlags LayoutFlags = ShrinkableHorz
+	if fl.textAlignment != AlignNear {
+		layoutFlags |= GrowableHorz
+	}
+
+	return &staticLayoutItem{
+		layoutFlags: layoutFlags,
+		idealSize:   calculateTextSize(fl.Text(), fl.Font(), ctx.dpi, 0, fl.hWnd),
+	}
+}
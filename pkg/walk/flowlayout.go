@@ -8,12 +8,23 @@
 package walk
 
 import (
+	"math"
+
 	"github.com/miu200521358/win"
 )
 
+// FlowLayout lays out the widgets of a container in rows from left to right,
+// starting a new row whenever the next widget doesn't fit the width of the
+// container, e.g. for lists of tags or tool buttons that reflow.
+//
+// With more than one widget, its height depends on its width, so containers
+// that support height for width, like a vertical BoxLayout or a ScrollView
+// with FitWidth, make room for as many rows as needed.
 type FlowLayout struct {
 	LayoutBase
 	hwnd2StretchFactor map[win.HWND]int
+	rowAlignment       Alignment1D
+	rowSpacing96dpi    int // -1 while rows are spaced like the widgets of a row
 }
 
 func NewFlowLayout() *FlowLayout {
@@ -23,12 +34,71 @@ func NewFlowLayout() *FlowLayout {
 			spacing96dpi: 3,
 		},
 		hwnd2StretchFactor: make(map[win.HWND]int),
+		rowSpacing96dpi:    -1,
 	}
 	l.layout = l
 
 	return l
 }
 
+// RowAlignment returns how the widgets of each row are placed horizontally,
+// see SetRowAlignment.
+func (l *FlowLayout) RowAlignment() Alignment1D {
+	return l.rowAlignment
+}
+
+// SetRowAlignment sets how the widgets of each row are placed horizontally.
+//
+// With AlignDefault, they share the whole width of the row, growing up to
+// their maximum sizes. With AlignNear, AlignCenter or AlignFar, they keep
+// their minimum sizes and are placed at the left, in the center or at the
+// right of the row.
+func (l *FlowLayout) SetRowAlignment(alignment Alignment1D) error {
+	if alignment != l.rowAlignment {
+		if alignment > AlignFar {
+			return newError("invalid Alignment value")
+		}
+
+		l.rowAlignment = alignment
+
+		if l.container != nil {
+			l.container.RequestLayout()
+		}
+	}
+
+	return nil
+}
+
+// RowSpacing returns the vertical spacing between rows in 1/96 inch. Unless
+// set by SetRowSpacing, it is the same as Spacing.
+func (l *FlowLayout) RowSpacing() int {
+	if l.rowSpacing96dpi < 0 {
+		return l.spacing96dpi
+	}
+
+	return l.rowSpacing96dpi
+}
+
+// SetRowSpacing sets the vertical spacing between rows in 1/96 inch, while
+// Spacing remains the horizontal spacing between the widgets of a row.
+func (l *FlowLayout) SetRowSpacing(value int) error {
+	if value == l.rowSpacing96dpi {
+		return nil
+	}
+
+	if value < 0 {
+		return newError("spacing cannot be negative")
+	}
+
+	l.rowSpacing96dpi = value
+
+	if l.container != nil {
+		l.container.RequestLayout()
+	}
+
+	return nil
+}
+
 func (l *FlowLayout) StretchFactor(widget Widget) int {
 	if factor, ok := l.hwnd2StretchFactor[widget.Handle()]; ok {
 		return factor
@@ -64,6 +134,8 @@ func (l *FlowLayout) CreateLayoutItem(ctx *LayoutContext) ContainerLayoutItem {
 	li := &flowLayoutItem{
 		size2MinSize:       make(map[Size]Size),
 		hwnd2StretchFactor: make(map[win.HWND]int),
+		rowAlignment:       l.rowAlignment,
+		rowSpacing96dpi:    l.RowSpacing(),
 	}
 
 	for hwnd, sf := range l.hwnd2StretchFactor {
@@ -77,6 +149,8 @@ type flowLayoutItem struct {
 	ContainerLayoutItemBase
 	size2MinSize       map[Size]Size // in native pixels
 	hwnd2StretchFactor map[win.HWND]int
+	rowAlignment       Alignment1D
+	rowSpacing96dpi    int
 }
 
 type flowLayoutSection struct {
@@ -98,6 +172,32 @@ func (li *flowLayoutItem) MinSize() Size {
 	return li.MinSizeForSize(li.geometry.ClientSize)
 }
 
+// IdealSize returns the size needed to lay out all items in a single row.
+func (li *flowLayoutItem) IdealSize() Size {
+	return li.MinSizeForSize(Size{Width: math.MaxInt32 / 2})
+}
+
+// HasHeightForWidth returns whether the width can change the height, which
+// it can if there is more than one item to wrap into rows, or an item whose
+// own height depends on its width.
+func (li *flowLayoutItem) HasHeightForWidth() bool {
+	var count int
+
+	for _, item := range li.children {
+		if !shouldLayoutItem(item) {
+			continue
+		}
+
+		if hfw, ok := item.(HeightForWidther); ok && hfw.HasHeightForWidth() {
+			return true
+		}
+
+		count++
+	}
+
+	return count > 1
+}
+
 func (li *flowLayoutItem) HeightForWidth(width int) int {
 	return li.MinSizeForSize(Size{width, li.geometry.ClientSize.Height}).Height
 }
@@ -108,6 +208,7 @@ func (li *flowLayoutItem) MinSizeForSize(size Size) Size {
 	}
 
 	spacing := IntFrom96DPI(li.spacing96dpi, li.ctx.dpi)
+	rowSpacing := IntFrom96DPI(li.rowSpacing96dpi, li.ctx.dpi)
 	margins := MarginsFrom96DPI(li.margins96dpi, li.ctx.dpi)
 
 	bounds := Rectangle{Width: size.Width}
@@ -138,7 +239,7 @@ func (li *flowLayoutItem) MinSizeForSize(size Size) Size {
 			margins96dpi.VFar = 0
 		}
 
-		layoutItems := boxLayoutItems(li, items, Horizontal, li.alignment, bounds, margins96dpi, li.spacing96dpi, li.hwnd2StretchFactor)
+		layoutItems := boxLayoutItems(li, items, Horizontal, li.alignment, li.rowBounds(section, bounds), margins96dpi, li.spacing96dpi, li.hwnd2StretchFactor)
 
 		var maxSecondary int
 
@@ -155,13 +256,13 @@ func (li *flowLayoutItem) MinSizeForSize(size Size) Size {
 
 		s.Height += maxSecondary
 
-		bounds.Y += maxSecondary + spacing
+		bounds.Y += maxSecondary + rowSpacing
 	}
 
 	s.Width = maxPrimary
 
 	s.Width += margins.HNear + margins.HFar
-	s.Height += margins.VNear + margins.VFar + (len(sections)-1)*spacing
+	s.Height += margins.VNear + margins.VFar + (len(sections)-1)*rowSpacing
 
 	if s.Width > 0 && s.Height > 0 {
 		li.size2MinSize[size] = s
@@ -171,7 +272,7 @@ func (li *flowLayoutItem) MinSizeForSize(size Size) Size {
 }
 
 func (li *flowLayoutItem) PerformLayout() []LayoutResultItem {
-	spacing := IntFrom96DPI(li.spacing96dpi, li.ctx.dpi)
+	rowSpacing := IntFrom96DPI(li.rowSpacing96dpi, li.ctx.dpi)
 	bounds := Rectangle{Width: li.geometry.ClientSize.Width, Height: li.geometry.ClientSize.Height}
 
 	sections := li.sectionsForPrimarySize(bounds.Width)
@@ -194,7 +295,7 @@ func (li *flowLayoutItem) PerformLayout() []LayoutResultItem {
 			margins96dpi.VFar = 0
 		}
 
-		layoutItems := boxLayoutItems(li, items, Horizontal, li.alignment, bounds, margins96dpi, li.spacing96dpi, li.hwnd2StretchFactor)
+		layoutItems := boxLayoutItems(li, items, Horizontal, li.alignment, li.rowBounds(section, bounds), margins96dpi, li.spacing96dpi, li.hwnd2StretchFactor)
 
 		margins := MarginsFrom96DPI(margins96dpi, li.ctx.dpi)
 
@@ -212,14 +313,36 @@ func (li *flowLayoutItem) PerformLayout() []LayoutResultItem {
 
 		bounds.Height = maxSecondary + margins.VNear + margins.VFar
 
-		resultItems = append(resultItems, boxLayoutItems(li, items, Horizontal, li.alignment, bounds, margins96dpi, li.spacing96dpi, li.hwnd2StretchFactor)...)
+		resultItems = append(resultItems, boxLayoutItems(li, items, Horizontal, li.alignment, li.rowBounds(section, bounds), margins96dpi, li.spacing96dpi, li.hwnd2StretchFactor)...)
 
-		bounds.Y += bounds.Height + spacing
+		bounds.Y += bounds.Height + rowSpacing
 	}
 
 	return resultItems
 }
 
+// rowBounds returns the part of bounds, which span the width of the
+// container, that the items of section are laid out in, according to the row
+// alignment.
+func (li *flowLayoutItem) rowBounds(section flowLayoutSection, bounds Rectangle) Rectangle {
+	spaceLeft := section.primarySpaceLeft
+	if li.rowAlignment == AlignDefault || spaceLeft <= 0 {
+		return bounds
+	}
+
+	switch li.rowAlignment {
+	case AlignCenter:
+		bounds.X += spaceLeft / 2
+
+	case AlignFar:
+		bounds.X += spaceLeft
+	}
+
+	bounds.Width -= spaceLeft
+
+	return bounds
+}
+
 // sectionsForPrimarySize calculates sections for primary width in native pixels.
 func (li *flowLayoutItem) sectionsForPrimarySize(primarySize int) []flowLayoutSection {
 	margins := MarginsFrom96DPI(li.margins96dpi, li.ctx.dpi)
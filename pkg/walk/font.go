@@ -8,6 +8,7 @@
 package walk
 
 import (
+	"math"
 	"syscall"
 )
 
@@ -28,6 +29,9 @@ const (
 var (
 	defaultFont *Font
 	knownFonts  = make(map[fontInfo]*Font)
+
+	// fontScale scales the size of all fonts, see Application.SetFontScale.
+	fontScale = 1.0
 )
 
 func init() {
@@ -111,9 +115,20 @@ func newFontFromLOGFONT(lf *win.LOGFONT, dpi int) (*Font, error) {
 }
 
 func (f *Font) createForDPI(dpi int) (win.HFONT, error) {
+	hFont, err := f.createForDPIAndScale(dpi, fontScale)
+	if err != nil {
+		return 0, newError("CreateFontIndirect failed")
+	}
+
+	return hFont, nil
+}
+
+// createForDPIAndScale creates a handle of the Font for dpi, with its size
+// multiplied by scale. Unlike createForDPI, it may be called from any thread.
+func (f *Font) createForDPIAndScale(dpi int, scale float64) (win.HFONT, error) {
 	var lf win.LOGFONT
 
-	lf.LfHeight = -win.MulDiv(int32(f.pointSize), int32(dpi), 72)
+	lf.LfHeight = -int32(math.Round(float64(f.pointSize) * scale * float64(dpi) / 72))
 	if f.style&FontBold > 0 {
 		lf.LfWeight = win.FW_BOLD
 	} else {
@@ -140,12 +155,59 @@ func (f *Font) createForDPI(dpi int) (win.HFONT, error) {
 
 	hFont := win.CreateFontIndirect(&lf)
 	if hFont == 0 {
-		return 0, newError("CreateFontIndirect failed")
+		return 0, newErrorNoPanic("CreateFontIndirect failed")
 	}
 
 	return hFont, nil
 }
 
+// setFontScale makes scale the fontScale and applies the fonts in their new
+// size to all windows of the current thread, which also updates their
+// layout.
+func setFontScale(scale float64) {
+	if scale <= 0 {
+		scale = 1
+	}
+
+	if scale == fontScale {
+		return
+	}
+
+	fontScale = scale
+
+	// The handles of the old size are still selected into the windows, so
+	// they are only deleted after the new ones replaced them.
+	var oldHFonts []win.HFONT
+	for _, font := range knownFonts {
+		for _, hFont := range font.dpi2hFont {
+			oldHFonts = append(oldHFonts, hFont)
+		}
+
+		font.dpi2hFont = nil
+	}
+
+	fontInfoAndDPI2DialogBaseUnits = make(map[fontInfoAndDPI]Size)
+	clearPrefetchedTextSizes()
+
+	tid := win.GetCurrentThreadId()
+
+	for hwnd, wb := range hwnd2WindowBase {
+		if win.GetWindowThreadProcessId(hwnd, nil) != tid {
+			continue
+		}
+
+		wb.calcTextSizeInfo2TextSize = make(map[calcTextSizeInfo]Size)
+
+		if af, ok := wb.window.(applyFonter); ok {
+			af.applyFont(wb.window.Font())
+		}
+	}
+
+	for _, hFont := range oldHFonts {
+		win.DeleteObject(win.HGDIOBJ(hFont))
+	}
+}
+
 // Bold returns if text drawn using the Font appears with
 // greater weight than normal.
 func (f *Font) Bold() bool {
@@ -186,6 +248,12 @@ func (f *Font) handleForDPI(dpi int) win.HFONT {
 		return handle
 	}
 
+	takeMeasurePrefetches(fontInfoAndDPI{fontInfo{f.family, f.pointSize, f.style}, dpi})
+
+	if handle, ok := f.dpi2hFont[dpi]; ok {
+		return handle
+	}
+
 	hFont, err := f.createForDPI(dpi)
 	if err != nil {
 		return 0
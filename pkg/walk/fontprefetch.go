@@ -0,0 +1,195 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/miu200521358/win"
+)
+
+// measurePrefetch is a font being created and measured in the background. Its
+// results must only be read after done has been closed.
+type measurePrefetch struct {
+	done      chan struct{}
+	font      *Font
+	scale     float64 // fontScale when the prefetch was started
+	texts     []string
+	hFont     win.HFONT
+	baseUnits Size   // dialog base units
+	textSizes []Size // of texts
+}
+
+type prefetchedTextSizeKey struct {
+	font fontInfoAndDPI
+	text string
+}
+
+var (
+	measurePrefetchMutex sync.Mutex
+	measurePrefetches    = make(map[fontInfoAndDPI][]*measurePrefetch)
+	prefetchedTextSizes  = make(map[prefetchedTextSizeKey]Size)
+	measurePrefetchSem   = make(chan struct{}, runtime.NumCPU())
+)
+
+// PrefetchFont starts creating the handle of font for dpi in the background,
+// along with the measurements that ideal sizes of widgets using it are
+// computed from: its dialog base units and the sizes of texts. Windows using
+// the font that are created later then don't have to wait for them. A nil
+// font means the default font.
+//
+// Only the sizes of texts measured without a width limit are prefetched, e.g.
+// of a Label or PushButton. Results are dropped if Application.SetFontScale
+// is called in the meantime. Like NewFont, PrefetchFont must be called from
+// the UI thread.
+func PrefetchFont(font *Font, dpi int, texts ...string) {
+	if font == nil {
+		font = defaultFont
+	}
+	if font == nil || dpi <= 0 {
+		return
+	}
+
+	key := fontInfoAndDPI{fontInfo{font.family, font.pointSize, font.style}, dpi}
+
+	measurePrefetchMutex.Lock()
+	defer measurePrefetchMutex.Unlock()
+
+	var pending []string
+	for _, text := range texts {
+		if _, ok := prefetchedTextSizes[prefetchedTextSizeKey{key, text}]; !ok && text != "" {
+			pending = append(pending, text)
+		}
+	}
+
+	_, hasHandle := font.dpi2hFont[dpi]
+	_, hasBaseUnits := fontInfoAndDPI2DialogBaseUnits[key]
+	if len(pending) == 0 && (hasHandle && hasBaseUnits || len(measurePrefetches[key]) > 0) {
+		return
+	}
+
+	mp := &measurePrefetch{
+		done:  make(chan struct{}),
+		font:  font,
+		scale: fontScale,
+		texts: pending,
+	}
+	measurePrefetches[key] = append(measurePrefetches[key], mp)
+
+	go mp.run(dpi)
+}
+
+// run creates the font and measures with it on a memory DC of its own thread.
+func (mp *measurePrefetch) run(dpi int) {
+	measurePrefetchSem <- struct{}{}
+	defer func() {
+		<-measurePrefetchSem
+		close(mp.done)
+	}()
+
+	// A DC belongs to the thread that created it.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	hFont, err := mp.font.createForDPIAndScale(dpi, mp.scale)
+	if err != nil {
+		return
+	}
+
+	hdc := win.CreateCompatibleDC(0)
+	if hdc == 0 {
+		win.DeleteObject(win.HGDIOBJ(hFont))
+		return
+	}
+	defer win.DeleteDC(hdc)
+
+	hFontOld := win.SelectObject(hdc, win.HGDIOBJ(hFont))
+	defer win.SelectObject(hdc, hFontOld)
+
+	mp.baseUnits = dialogBaseUnitsOfDC(hdc, newErrorNoPanic)
+
+	mp.textSizes = make([]Size, len(mp.texts))
+	for i, text := range mp.texts {
+		mp.textSizes[i] = textExtentOfDC(hdc, text, newErrorNoPanic)
+	}
+
+	// Only handed over once measuring succeeded, see takeMeasurePrefetches.
+	mp.hFont = hFont
+}
+
+// takeMeasurePrefetches waits for the pending prefetches of the font and DPI
+// of key, if any, and moves their results into the caches of the UI thread.
+func takeMeasurePrefetches(key fontInfoAndDPI) {
+	measurePrefetchMutex.Lock()
+	mps := measurePrefetches[key]
+	delete(measurePrefetches, key)
+	measurePrefetchMutex.Unlock()
+
+	for _, mp := range mps {
+		<-mp.done
+
+		if mp.hFont == 0 {
+			continue
+		}
+
+		if mp.scale != fontScale {
+			win.DeleteObject(win.HGDIOBJ(mp.hFont))
+			continue
+		}
+
+		if mp.font.dpi2hFont == nil {
+			mp.font.dpi2hFont = make(map[int]win.HFONT)
+		}
+		if _, ok := mp.font.dpi2hFont[key.dpi]; ok {
+			win.DeleteObject(win.HGDIOBJ(mp.hFont))
+		} else {
+			mp.font.dpi2hFont[key.dpi] = mp.hFont
+		}
+
+		if _, ok := fontInfoAndDPI2DialogBaseUnits[key]; !ok {
+			fontInfoAndDPI2DialogBaseUnits[key] = mp.baseUnits
+		}
+
+		measurePrefetchMutex.Lock()
+		for i, text := range mp.texts {
+			prefetchedTextSizes[prefetchedTextSizeKey{key, text}] = mp.textSizes[i]
+		}
+		measurePrefetchMutex.Unlock()
+	}
+}
+
+// prefetchedTextSize returns the size of text drawn with font at dpi without
+// a width limit, if it was prefetched by PrefetchFont.
+func prefetchedTextSize(font fontInfo, dpi int, text string) (Size, bool) {
+	key := fontInfoAndDPI{font, dpi}
+
+	measurePrefetchMutex.Lock()
+	pending := len(measurePrefetches[key]) > 0
+	measurePrefetchMutex.Unlock()
+
+	if pending {
+		takeMeasurePrefetches(key)
+	}
+
+	measurePrefetchMutex.Lock()
+	defer measurePrefetchMutex.Unlock()
+
+	size, ok := prefetchedTextSizes[prefetchedTextSizeKey{key, text}]
+
+	return size, ok
+}
+
+// clearPrefetchedTextSizes forgets the prefetched text sizes, which are wrong
+// once the font scale changed.
+func clearPrefetchedTextSizes() {
+	measurePrefetchMutex.Lock()
+	defer measurePrefetchMutex.Unlock()
+
+	prefetchedTextSizes = make(map[prefetchedTextSizeKey]Size)
+}
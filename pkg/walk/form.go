@@ -101,6 +101,9 @@ type FormBase struct {
 	isInRestoreState            bool
 	started                     bool
 	layoutScheduled             bool
+	wheelRouting                WheelRouting
+	globalHotKeys               map[int]*GlobalHotKey
+	validationState             *ValidationState
 }
 
 func (fb *FormBase) init(form Form) error {
@@ -156,6 +159,12 @@ func (fb *FormBase) init(form Form) error {
 func (fb *FormBase) Dispose() {
 	if fb.hWnd != 0 {
 		fb.quitLayoutPerformer <- struct{}{}
+
+		fb.unregisterGlobalHotKeys()
+	}
+
+	if fb.validationState != nil {
+		fb.validationState.dispose()
 	}
 
 	fb.WindowBase.Dispose()
@@ -337,8 +346,19 @@ func (fb *FormBase) RightToLeftLayout() bool {
 
 // SetRightToLeftLayout sets whether coordinates on the x axis of the
 // FormBase increase from right to left.
+//
+// Widgets that already exist are mirrored as well, including the painting of
+// custom-drawn ones.
 func (fb *FormBase) SetRightToLeftLayout(rtl bool) error {
-	return fb.ensureExtendedStyleBits(win.WS_EX_LAYOUTRTL, rtl)
+	if err := fb.ensureExtendedStyleBits(win.WS_EX_LAYOUTRTL, rtl); err != nil {
+		return err
+	}
+
+	applyRightToLeftLayout(fb.window, rtl)
+
+	fb.Invalidate()
+
+	return nil
 }
 
 func (fb *FormBase) Run() int {
@@ -362,6 +382,8 @@ func (fb *FormBase) Run() int {
 	fb.started = true
 	fb.startingPublisher.Publish()
 
+	fb.startWatchdog()
+
 	fb.SetBoundsPixels(fb.BoundsPixels())
 
 	if fb.proposedSize == (Size{}) {
@@ -373,6 +395,16 @@ func (fb *FormBase) Run() int {
 
 	fb.SetSuspended(false)
 
+	// Calls of SynchronizeContext can't run anymore once the outermost loop
+	// of the thread exited.
+	group := fb.group
+	group.loops++
+	defer func() {
+		if group.loops--; group.loops == 0 {
+			group.failSyncCalls(nil)
+		}
+	}()
+
 	return fb.mainLoop()
 }
 
@@ -717,6 +749,8 @@ func (fb *FormBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 
 			fb.group.SetActiveForm(fb.window.(Form))
 
+			invalidateCommandsIfAny()
+
 			fb.activatingPublisher.Publish()
 
 		case win.WA_INACTIVE:
@@ -748,6 +782,13 @@ func (fb *FormBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 	case win.WM_COMMAND:
 		return fb.clientComposite.WndProc(hwnd, msg, wParam, lParam)
 
+	case win.WM_INITMENUPOPUP:
+		invalidateCommandsIfAny()
+
+	case win.WM_HOTKEY:
+		fb.handleHotKey(wParam)
+		return 0
+
 	case win.WM_GETMINMAXINFO:
 		if fb.Suspended() || fb.proposedSize == (Size{}) {
 			break
@@ -826,6 +867,11 @@ func (fb *FormBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 	case win.WM_SYSCOLORCHANGE:
 		fb.ApplySysColors()
 
+	case win.WM_SETTINGCHANGE:
+		if isImmersiveColorSetChange(lParam) && appearance.Theme == ThemeSystem {
+			updateAppTheme()
+		}
+
 	case win.WM_DPICHANGED:
 		wasSuspended := fb.Suspended()
 		fb.SetSuspended(true)
@@ -835,9 +881,11 @@ func (fb *FormBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 
 		seenInApplyFontToDescendantsDuringDPIChange = make(map[*WindowBase]bool)
 		seenInApplyDPIToDescendantsDuringDPIChange = make(map[*WindowBase]bool)
+		pendingDPIChanges = make([]dpiChange, 0)
 		defer func() {
 			seenInApplyFontToDescendantsDuringDPIChange = nil
 			seenInApplyDPIToDescendantsDuringDPIChange = nil
+			pendingDPIChanges = nil
 		}()
 
 		fb.clientComposite.ApplyDPI(dpi)
@@ -856,19 +904,16 @@ func (fb *FormBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 
 		fb.SetIcon(fb.icon)
 
-		time.AfterFunc(time.Second, func() {
-			if fb.hWnd == 0 {
-				return
+		publishPendingDPIChanges()
+
+		afterUI(fb.window, time.Second, func() {
+			for ni := range notifyIcons {
+				// We do this on all NotifyIcons, not just ones attached to this form or descendents, because
+				// the notify icon might be on a different screen, and since it can't get notifications itself
+				// we hope that one of the forms did for it. We also have to delay it by a second, because the
+				// tray usually gets resized sometime after us. This is a nasty hack!
+				ni.applyDPI()
 			}
-			fb.Synchronize(func() {
-				for ni := range notifyIcons {
-					// We do this on all NotifyIcons, not just ones attached to this form or descendents, because
-					// the notify icon might be on a different screen, and since it can't get notifications itself
-					// we hope that one of the forms did for it. We also have to delay it by a second, because the
-					// tray usually gets resized sometime after us. This is a nasty hack!
-					ni.applyDPI()
-				}
-			})
 		})
 
 	case win.WM_SYSCOMMAND:
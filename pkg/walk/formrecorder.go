@@ -0,0 +1,671 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"bufio"
+	"bytes"
+	"compress/lzw"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/miu200521358/win"
+)
+
+const recordingIndicatorWindowClass = `\o/ Walk_RecordingIndicator_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(recordingIndicatorWindowClass)
+	})
+}
+
+// FormRecorder captures the client area of a Form at a fixed frame rate and
+// writes the frames to an animated GIF or an MP4 video file.
+//
+// Frames are written while recording, with the time they were captured at,
+// so frames dropped because writing could not keep up lengthen the previous
+// one instead of speeding up the recording.
+//
+// While recording, a small indicator is shown in the top right corner of the
+// Form. The indicator itself is not part of the recording.
+type FormRecorder struct {
+	form                Form
+	fps                 int
+	indicatorVisible    bool
+	indicator           *recordingIndicator
+	recording           bool
+	frameCount          int
+	frames              chan recordedFrame
+	stop                chan struct{}
+	done                chan error
+	stopTime            time.Time
+	boundsChangedHandle int
+	disposingHandle     int
+	startedPublisher    EventPublisher
+	stoppedPublisher    EventPublisher
+	frameDroppedCount   int
+}
+
+// NewFormRecorder returns a new FormRecorder that captures form with fps
+// frames per second.
+//
+// Valid values for fps are 1 through 50, as GIF viewers don't show frames
+// shorter than 1/50 of a second.
+func NewFormRecorder(form Form, fps int) (*FormRecorder, error) {
+	if form == nil {
+		return nil, newError("form must not be nil")
+	}
+	if fps < 1 || fps > 50 {
+		return nil, newError("fps must >= 1 && <= 50")
+	}
+
+	return &FormRecorder{
+		form:             form,
+		fps:              fps,
+		indicatorVisible: true,
+	}, nil
+}
+
+// Form returns the Form that is captured by the FormRecorder.
+func (fr *FormRecorder) Form() Form {
+	return fr.form
+}
+
+// FPS returns the number of frames per second the FormRecorder captures.
+func (fr *FormRecorder) FPS() int {
+	return fr.fps
+}
+
+// IndicatorVisible returns whether a recording indicator is shown on top of
+// the Form while recording.
+func (fr *FormRecorder) IndicatorVisible() bool {
+	return fr.indicatorVisible
+}
+
+// SetIndicatorVisible sets whether a recording indicator is shown on top of
+// the Form while recording.
+func (fr *FormRecorder) SetIndicatorVisible(visible bool) error {
+	if visible == fr.indicatorVisible {
+		return nil
+	}
+
+	fr.indicatorVisible = visible
+
+	if !fr.recording {
+		return nil
+	}
+
+	if visible {
+		return fr.showIndicator()
+	}
+
+	fr.hideIndicator()
+
+	return nil
+}
+
+// Recording returns whether the FormRecorder is currently recording.
+func (fr *FormRecorder) Recording() bool {
+	return fr.recording
+}
+
+// FrameCount returns the number of frames captured by the current or most
+// recent recording.
+func (fr *FormRecorder) FrameCount() int {
+	return fr.frameCount
+}
+
+// DroppedFrameCount returns the number of frames of the current or most recent
+// recording that were skipped, because encoding could not keep up. The frame
+// before a dropped one is shown longer instead.
+func (fr *FormRecorder) DroppedFrameCount() int {
+	return fr.frameDroppedCount
+}
+
+// Started returns the event that is published when a recording was started.
+func (fr *FormRecorder) Started() *Event {
+	return fr.startedPublisher.Event()
+}
+
+// Stopped returns the event that is published when a recording was stopped.
+func (fr *FormRecorder) Stopped() *Event {
+	return fr.stoppedPublisher.Event()
+}
+
+// Start starts recording into the file at filePath, whose extension selects
+// the format: animated GIF (.gif) or H.264 video in MP4 (.mp4), which is
+// written with Media Foundation.
+//
+// The size of the recording is that of the client area of the Form at
+// Start. If the Form is resized while recording, later frames are clipped to
+// it.
+//
+// Start must be called from the goroutine that created the Form.
+func (fr *FormRecorder) Start(filePath string) error {
+	if fr.recording {
+		return newError("already recording")
+	}
+
+	size := fr.form.AsFormBase().clientComposite.ClientBoundsPixels().Size()
+	if size.Width <= 0 || size.Height <= 0 {
+		return newError("the client area of the form is empty")
+	}
+
+	var newSink func() (recordingSink, error)
+
+	switch ext := strings.ToLower(filepath.Ext(filePath)); ext {
+	case ".gif":
+		newSink = func() (recordingSink, error) {
+			return newGIFRecordingSink(filePath, image.Pt(size.Width, size.Height))
+		}
+
+	case ".mp4":
+		// H.264 requires even dimensions.
+		width, height := maxi(size.Width&^1, 2), maxi(size.Height&^1, 2)
+
+		newSink = func() (recordingSink, error) {
+			return newMP4RecordingSink(filePath, image.Pt(width, height), fr.fps)
+		}
+
+	default:
+		return newError("unsupported recording format: " + ext)
+	}
+
+	frames := make(chan recordedFrame, fr.fps)
+	ready := make(chan error, 1)
+	done := make(chan error, 1)
+
+	go fr.encode(newSink, frames, ready, done)
+
+	if err := <-ready; err != nil {
+		return err
+	}
+
+	fr.recording = true
+	fr.frameCount = 0
+	fr.frameDroppedCount = 0
+	fr.frames = frames
+	fr.stop = make(chan struct{})
+	fr.done = done
+
+	fr.captureFrame()
+
+	go fr.tick(fr.stop)
+
+	fr.disposingHandle = fr.form.Disposing().Attach(func() {
+		fr.Stop()
+	})
+
+	if fr.indicatorVisible {
+		if err := fr.showIndicator(); err != nil {
+			fr.Stop()
+			return err
+		}
+	}
+
+	fr.startedPublisher.Publish()
+
+	return nil
+}
+
+// Stop stops the current recording and waits until all captured frames have
+// been written.
+func (fr *FormRecorder) Stop() error {
+	if !fr.recording {
+		return nil
+	}
+
+	fr.recording = false
+
+	fr.form.Disposing().Detach(fr.disposingHandle)
+
+	fr.hideIndicator()
+
+	close(fr.stop)
+
+	// Read by encode for the duration of the last frame, after frames is
+	// closed.
+	fr.stopTime = time.Now()
+	close(fr.frames)
+
+	err := <-fr.done
+
+	fr.stoppedPublisher.Publish()
+
+	return err
+}
+
+func (fr *FormRecorder) tick(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Second / time.Duration(fr.fps))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			fr.form.Synchronize(fr.captureFrame)
+
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (fr *FormRecorder) captureFrame() {
+	if !fr.recording || fr.form.IsDisposed() {
+		return
+	}
+
+	captured := time.Now()
+
+	hBmp, err := hBitmapFromWindowClient(fr.form.AsFormBase().clientComposite)
+	if err != nil {
+		return
+	}
+
+	bmp, err := newBitmapFromHBITMAP(hBmp, fr.form.DPI())
+	if err != nil {
+		win.DeleteObject(win.HGDIOBJ(hBmp))
+		return
+	}
+	defer bmp.Dispose()
+
+	img, err := bmp.ToImage()
+	if err != nil {
+		return
+	}
+
+	select {
+	case fr.frames <- recordedFrame{img, captured}:
+		fr.frameCount++
+
+	default:
+		fr.frameDroppedCount++
+	}
+}
+
+// recordedFrame is a frame captured by a FormRecorder and when it was
+// captured.
+type recordedFrame struct {
+	img  *image.RGBA
+	time time.Time
+}
+
+// recordingSink writes the frames of a recording in a file format.
+type recordingSink interface {
+	// size returns the fixed size of the frames.
+	size() image.Point
+
+	// writeFrame writes a frame of size, to be shown for duration.
+	writeFrame(img *image.RGBA, duration time.Duration) error
+
+	// close finishes the file.
+	close() error
+}
+
+// encode creates a sink with newSink, reports the result to ready and then
+// writes the frames to it until frames is closed.
+//
+// It runs on its own locked thread, which Media Foundation requires to be
+// initialized for COM.
+func (fr *FormRecorder) encode(newSink func() (recordingSink, error), frames <-chan recordedFrame, ready, done chan<- error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	sink, err := newSink()
+	ready <- err
+	if err != nil {
+		return
+	}
+
+	interval := time.Second / time.Duration(fr.fps)
+
+	// Each frame is written once the next one arrived, which tells how long
+	// it is shown. Frames equal to the previous one only lengthen it.
+	var pending recordedFrame
+
+	for frame := range frames {
+		frame.img = fitRecordedFrame(frame.img, sink.size())
+
+		if pending.img != nil {
+			if bytes.Equal(frame.img.Pix, pending.img.Pix) {
+				continue
+			}
+
+			if err == nil {
+				err = sink.writeFrame(pending.img, frame.time.Sub(pending.time))
+			}
+		}
+
+		pending = frame
+	}
+
+	if pending.img != nil && err == nil {
+		duration := fr.stopTime.Sub(pending.time)
+		if duration < interval {
+			duration = interval
+		}
+
+		err = sink.writeFrame(pending.img, duration)
+	}
+
+	if e := sink.close(); err == nil {
+		err = e
+	}
+
+	if err != nil {
+		err = wrapErrorNoPanic(err)
+	}
+
+	done <- err
+}
+
+// fitRecordedFrame returns img clipped, or extended with black, to size.
+func fitRecordedFrame(img *image.RGBA, size image.Point) *image.RGBA {
+	if img.Bounds().Size() == size {
+		return img
+	}
+
+	fitted := image.NewRGBA(image.Rectangle{Max: size})
+	draw.Draw(fitted, fitted.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	draw.Draw(fitted, fitted.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	return fitted
+}
+
+// gifRecordingSink writes an animated GIF, one frame at a time.
+type gifRecordingSink struct {
+	file    *os.File
+	w       *bufio.Writer
+	sz      image.Point
+	elapsed time.Duration // up to the end of the frames written
+	delays  int           // sum of the delays written, in 1/100 s
+	pm      *image.Paletted
+}
+
+func newGIFRecordingSink(filePath string, size image.Point) (*gifRecordingSink, error) {
+	if size.X > 0xFFFF || size.Y > 0xFFFF {
+		return nil, newError("the form is too large for GIF")
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return nil, wrapErrorNoPanic(err)
+	}
+
+	gs := &gifRecordingSink{
+		file: file,
+		w:    bufio.NewWriter(file),
+		sz:   size,
+		pm:   image.NewPaletted(image.Rectangle{Max: size}, palette.Plan9),
+	}
+
+	// Header and logical screen descriptor without global color table.
+	gs.w.WriteString("GIF89a")
+	binary.Write(gs.w, binary.LittleEndian, [2]uint16{uint16(size.X), uint16(size.Y)})
+	gs.w.Write([]byte{0, 0, 0})
+
+	// Loop forever.
+	gs.w.Write([]byte{0x21, 0xFF, 0x0B})
+	gs.w.WriteString("NETSCAPE2.0")
+	gs.w.Write([]byte{0x03, 0x01, 0x00, 0x00, 0x00})
+
+	if err := gs.w.Flush(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return gs, nil
+}
+
+func (gs *gifRecordingSink) size() image.Point {
+	return gs.sz
+}
+
+func (gs *gifRecordingSink) writeFrame(img *image.RGBA, duration time.Duration) error {
+	// Delays are rounded such that their sum follows the elapsed time.
+	// Viewers show frames shorter than 2/100 s slower, so none are.
+	gs.elapsed += duration
+	delay := int((gs.elapsed+5*time.Millisecond)/(10*time.Millisecond)) - gs.delays
+	if delay < 2 {
+		delay = 2
+	} else if delay > 0xFFFF {
+		delay = 0xFFFF
+	}
+	gs.delays += delay
+
+	draw.FloydSteinberg.Draw(gs.pm, gs.pm.Bounds(), img, image.Point{})
+
+	// Graphic control extension with the delay.
+	gs.w.Write([]byte{0x21, 0xF9, 0x04, 0x00, byte(delay), byte(delay >> 8), 0x00, 0x00})
+
+	// Image descriptor with a local color table of 256 colors.
+	gs.w.WriteByte(0x2C)
+	binary.Write(gs.w, binary.LittleEndian, [4]uint16{0, 0, uint16(gs.sz.X), uint16(gs.sz.Y)})
+	gs.w.WriteByte(0x87)
+
+	for i := 0; i < 256; i++ {
+		var r, g, b uint32
+		if i < len(gs.pm.Palette) {
+			r, g, b, _ = gs.pm.Palette[i].RGBA()
+		}
+		gs.w.Write([]byte{byte(r >> 8), byte(g >> 8), byte(b >> 8)})
+	}
+
+	const litWidth = 8
+	gs.w.WriteByte(litWidth)
+
+	bw := &gifBlockWriter{w: gs.w}
+	lw := lzw.NewWriter(bw, lzw.LSB, litWidth)
+	if _, err := lw.Write(gs.pm.Pix); err != nil {
+		return err
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+	if err := bw.close(); err != nil {
+		return err
+	}
+
+	return gs.w.Flush()
+}
+
+func (gs *gifRecordingSink) close() error {
+	gs.w.WriteByte(0x3B)
+
+	err := gs.w.Flush()
+
+	if e := gs.file.Close(); err == nil {
+		err = e
+	}
+
+	return err
+}
+
+// gifBlockWriter splits the LZW data of a GIF frame into sub-blocks of at
+// most 255 bytes.
+type gifBlockWriter struct {
+	w   *bufio.Writer
+	buf [255]byte
+	n   int
+}
+
+func (bw *gifBlockWriter) Write(p []byte) (int, error) {
+	written := len(p)
+
+	for len(p) > 0 {
+		c := copy(bw.buf[bw.n:], p)
+		bw.n += c
+		p = p[c:]
+
+		if bw.n == len(bw.buf) {
+			if err := bw.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+func (bw *gifBlockWriter) flush() error {
+	if bw.n == 0 {
+		return nil
+	}
+
+	bw.w.WriteByte(byte(bw.n))
+	_, err := bw.w.Write(bw.buf[:bw.n])
+	bw.n = 0
+
+	return err
+}
+
+func (bw *gifBlockWriter) close() error {
+	if err := bw.flush(); err != nil {
+		return err
+	}
+
+	return bw.w.WriteByte(0)
+}
+
+func (fr *FormRecorder) showIndicator() error {
+	if fr.indicator == nil {
+		ri, err := newRecordingIndicator(fr.form)
+		if err != nil {
+			return err
+		}
+
+		fr.indicator = ri
+	}
+
+	fr.boundsChangedHandle = fr.form.BoundsChanged().Attach(fr.indicator.updatePosition)
+
+	fr.indicator.updatePosition()
+	win.ShowWindow(fr.indicator.hWnd, win.SW_SHOWNOACTIVATE)
+
+	return nil
+}
+
+func (fr *FormRecorder) hideIndicator() {
+	if fr.indicator == nil {
+		return
+	}
+
+	fr.form.BoundsChanged().Detach(fr.boundsChangedHandle)
+
+	fr.indicator.Dispose()
+	fr.indicator = nil
+}
+
+// recordingIndicator is a small popup window that marks a Form as being
+// recorded.
+type recordingIndicator struct {
+	WindowBase
+	form Form
+}
+
+func newRecordingIndicator(form Form) (*recordingIndicator, error) {
+	ri := &recordingIndicator{form: form}
+
+	if err := InitWindow(
+		ri,
+		form,
+		recordingIndicatorWindowClass,
+		win.WS_POPUP|win.WS_DISABLED,
+		win.WS_EX_TOOLWINDOW|win.WS_EX_TOPMOST|win.WS_EX_NOACTIVATE); err != nil {
+		return nil, err
+	}
+
+	return ri, nil
+}
+
+func (ri *recordingIndicator) updatePosition() {
+	size := Size{IntFrom96DPI(44, ri.form.DPI()), IntFrom96DPI(18, ri.form.DPI())}
+	margin := IntFrom96DPI(4, ri.form.DPI())
+
+	var rc win.RECT
+	if !win.GetClientRect(ri.form.Handle(), &rc) {
+		return
+	}
+
+	pt := win.POINT{X: rc.Right - int32(size.Width+margin), Y: rc.Top + int32(margin)}
+	win.ClientToScreen(ri.form.Handle(), &pt)
+
+	win.SetWindowPos(
+		ri.hWnd,
+		win.HWND_TOPMOST,
+		pt.X,
+		pt.Y,
+		int32(size.Width),
+		int32(size.Height),
+		win.SWP_NOACTIVATE)
+}
+
+func (ri *recordingIndicator) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_PAINT:
+		var ps win.PAINTSTRUCT
+
+		hdc := win.BeginPaint(hwnd, &ps)
+		if hdc == 0 {
+			break
+		}
+		defer win.EndPaint(hwnd, &ps)
+
+		canvas, err := newCanvasFromHDC(hdc)
+		if err != nil {
+			break
+		}
+		defer canvas.Dispose()
+
+		if err := ri.paint(canvas); err != nil {
+			break
+		}
+
+		return 0
+	}
+
+	return ri.WindowBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (ri *recordingIndicator) paint(canvas *Canvas) error {
+	bounds := ri.ClientBoundsPixels()
+
+	bg, err := NewSolidColorBrush(RGB(32, 32, 32))
+	if err != nil {
+		return err
+	}
+	defer bg.Dispose()
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	dot, err := NewSolidColorBrush(RGB(230, 32, 32))
+	if err != nil {
+		return err
+	}
+	defer dot.Dispose()
+
+	d := bounds.Height / 2
+	if err := canvas.FillEllipsePixels(dot, Rectangle{bounds.Height/4 + 1, bounds.Height / 4, d, d}); err != nil {
+		return err
+	}
+
+	textBounds := Rectangle{bounds.Height, 0, bounds.Width - bounds.Height, bounds.Height}
+
+	return canvas.DrawTextPixels("REC", ri.Font(), RGB(255, 255, 255), textBounds, TextVCenter|TextSingleLine)
+}
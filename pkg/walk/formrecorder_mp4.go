@@ -0,0 +1,300 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"image"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+var (
+	mfMTMajorType        = win.IID{Data1: 0x48EBA18E, Data2: 0xF8C9, Data3: 0x4687, Data4: [8]byte{0xBF, 0x11, 0x0A, 0x74, 0xC9, 0xF9, 0x6A, 0x8F}}
+	mfMTSubtype          = win.IID{Data1: 0xF7E34C9A, Data2: 0x42E8, Data3: 0x4714, Data4: [8]byte{0xB7, 0x4B, 0xCB, 0x29, 0xD7, 0x2C, 0x35, 0xE5}}
+	mfMTAvgBitrate       = win.IID{Data1: 0x20332624, Data2: 0xFB0D, Data3: 0x4D9E, Data4: [8]byte{0xBD, 0x0D, 0xCB, 0xF6, 0x78, 0x6C, 0x10, 0x2E}}
+	mfMTInterlaceMode    = win.IID{Data1: 0xE2724BB8, Data2: 0xE676, Data3: 0x4806, Data4: [8]byte{0xB4, 0xB2, 0xA8, 0xD6, 0xEF, 0xB4, 0x4C, 0xCD}}
+	mfMTFrameSize        = win.IID{Data1: 0x1652C33D, Data2: 0xD6B2, Data3: 0x4012, Data4: [8]byte{0xB8, 0x34, 0x72, 0x03, 0x08, 0x49, 0xA3, 0x7D}}
+	mfMTFrameRate        = win.IID{Data1: 0xC459A2E8, Data2: 0x3D2C, Data3: 0x4E44, Data4: [8]byte{0xB1, 0x32, 0xFE, 0xE5, 0x15, 0x6C, 0x7B, 0xB0}}
+	mfMTPixelAspectRatio = win.IID{Data1: 0xC6376A1E, Data2: 0x8D0A, Data3: 0x4027, Data4: [8]byte{0xBE, 0x45, 0x6D, 0x9A, 0x0A, 0xD3, 0x9B, 0xB6}}
+	mfMTDefaultStride    = win.IID{Data1: 0x644B4E48, Data2: 0x1E02, Data3: 0x4516, Data4: [8]byte{0xB0, 0xEB, 0xC0, 0x1C, 0xA9, 0xD4, 0x9A, 0xC6}}
+	mfMediaTypeVideo     = win.IID{Data1: 0x73646976, Data2: 0x0000, Data3: 0x0010, Data4: [8]byte{0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}}
+	mfVideoFormatH264    = win.IID{Data1: 0x34363248, Data2: 0x0000, Data3: 0x0010, Data4: [8]byte{0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}}
+	mfVideoFormatRGB32   = win.IID{Data1: 0x00000016, Data2: 0x0000, Data3: 0x0010, Data4: [8]byte{0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}}
+)
+
+const (
+	mfVersion                   = 0x00020070 // MF_SDK_VERSION << 16 | MF_API_VERSION
+	mfVideoInterlaceProgressive = 2
+)
+
+// Indexes into the vtables of the Media Foundation interfaces.
+const (
+	mfAttributesSetUINT32     = 21
+	mfAttributesSetUINT64     = 22
+	mfAttributesSetGUID       = 24
+	mfSampleSetSampleTime     = 36
+	mfSampleSetSampleDuration = 38
+	mfSampleAddBuffer         = 42
+	mfMediaBufferLock         = 3
+	mfMediaBufferUnlock       = 4
+	mfMediaBufferSetLength    = 6
+	mfSinkWriterAddStream     = 3
+	mfSinkWriterSetInputType  = 4
+	mfSinkWriterBeginWriting  = 5
+	mfSinkWriterWriteSample   = 6
+	mfSinkWriterFinalize      = 11
+)
+
+var (
+	libmfplat                 = syscall.NewLazyDLL("mfplat.dll")
+	mfStartup                 = libmfplat.NewProc("MFStartup")
+	mfShutdown                = libmfplat.NewProc("MFShutdown")
+	mfCreateMediaType         = libmfplat.NewProc("MFCreateMediaType")
+	mfCreateSample            = libmfplat.NewProc("MFCreateSample")
+	mfCreateMemoryBuffer      = libmfplat.NewProc("MFCreateMemoryBuffer")
+	mfCreateSinkWriterFromURL = syscall.NewLazyDLL("mfreadwrite.dll").NewProc("MFCreateSinkWriterFromURL")
+)
+
+// mp4RecordingSink writes H.264 video to an MP4 file with the sink writer of
+// Media Foundation. It must be used on a single locked thread.
+type mp4RecordingSink struct {
+	sz         image.Point
+	writer     uintptr // IMFSinkWriter
+	stream     uint32
+	sampleTime time.Duration
+	started    bool // MFStartup succeeded
+	comInit    bool // CoInitializeEx succeeded
+}
+
+func newMP4RecordingSink(filePath string, size image.Point, fps int) (sink *mp4RecordingSink, err error) {
+	ms := &mp4RecordingSink{sz: size}
+
+	defer func() {
+		if err != nil {
+			ms.release()
+		}
+	}()
+
+	if hr := win.CoInitializeEx(nil, win.COINIT_MULTITHREADED); win.FAILED(hr) {
+		return nil, mfError("CoInitializeEx", uintptr(hr))
+	}
+	ms.comInit = true
+
+	if hr, _, _ := mfStartup.Call(mfVersion, 0); failedHRESULT(hr) {
+		return nil, mfError("MFStartup", hr)
+	}
+	ms.started = true
+
+	if hr, _, _ := mfCreateSinkWriterFromURL.Call(
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(filePath))),
+		0,
+		0,
+		uintptr(unsafe.Pointer(&ms.writer))); failedHRESULT(hr) {
+		return nil, mfError("MFCreateSinkWriterFromURL", hr)
+	}
+
+	frameSize := uint64(size.X)<<32 | uint64(size.Y)
+	frameRate := uint64(fps)<<32 | 1
+
+	outputType, err := newMFMediaType(func(mt uintptr) uintptr {
+		return firstFailedHRESULT(
+			comCall(mt, mfAttributesSetGUID, uintptr(unsafe.Pointer(&mfMTMajorType)), uintptr(unsafe.Pointer(&mfMediaTypeVideo))),
+			comCall(mt, mfAttributesSetGUID, uintptr(unsafe.Pointer(&mfMTSubtype)), uintptr(unsafe.Pointer(&mfVideoFormatH264))),
+			comCall(mt, mfAttributesSetUINT32, uintptr(unsafe.Pointer(&mfMTAvgBitrate)), uintptr(mp4Bitrate(size, fps))),
+			comCall(mt, mfAttributesSetUINT32, uintptr(unsafe.Pointer(&mfMTInterlaceMode)), mfVideoInterlaceProgressive),
+			setMFAttributeUINT64(mt, &mfMTFrameSize, frameSize),
+			setMFAttributeUINT64(mt, &mfMTFrameRate, frameRate),
+			setMFAttributeUINT64(mt, &mfMTPixelAspectRatio, 1<<32|1))
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer comCall(outputType, unknownRelease)
+
+	if hr := comCall(ms.writer, mfSinkWriterAddStream, outputType, uintptr(unsafe.Pointer(&ms.stream))); failedHRESULT(hr) {
+		return nil, mfError("IMFSinkWriter.AddStream", hr)
+	}
+
+	// Top-down BGRX rows, like those of the captured frames.
+	inputType, err := newMFMediaType(func(mt uintptr) uintptr {
+		return firstFailedHRESULT(
+			comCall(mt, mfAttributesSetGUID, uintptr(unsafe.Pointer(&mfMTMajorType)), uintptr(unsafe.Pointer(&mfMediaTypeVideo))),
+			comCall(mt, mfAttributesSetGUID, uintptr(unsafe.Pointer(&mfMTSubtype)), uintptr(unsafe.Pointer(&mfVideoFormatRGB32))),
+			comCall(mt, mfAttributesSetUINT32, uintptr(unsafe.Pointer(&mfMTInterlaceMode)), mfVideoInterlaceProgressive),
+			comCall(mt, mfAttributesSetUINT32, uintptr(unsafe.Pointer(&mfMTDefaultStride)), uintptr(4*size.X)),
+			setMFAttributeUINT64(mt, &mfMTFrameSize, frameSize),
+			setMFAttributeUINT64(mt, &mfMTFrameRate, frameRate),
+			setMFAttributeUINT64(mt, &mfMTPixelAspectRatio, 1<<32|1))
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer comCall(inputType, unknownRelease)
+
+	if hr := comCall(ms.writer, mfSinkWriterSetInputType, uintptr(ms.stream), inputType, 0); failedHRESULT(hr) {
+		return nil, mfError("IMFSinkWriter.SetInputMediaType", hr)
+	}
+
+	if hr := comCall(ms.writer, mfSinkWriterBeginWriting); failedHRESULT(hr) {
+		return nil, mfError("IMFSinkWriter.BeginWriting", hr)
+	}
+
+	return ms, nil
+}
+
+func (ms *mp4RecordingSink) size() image.Point {
+	return ms.sz
+}
+
+func (ms *mp4RecordingSink) writeFrame(img *image.RGBA, duration time.Duration) error {
+	length := 4 * ms.sz.X * ms.sz.Y
+
+	var buffer uintptr // IMFMediaBuffer
+	if hr, _, _ := mfCreateMemoryBuffer.Call(uintptr(length), uintptr(unsafe.Pointer(&buffer))); failedHRESULT(hr) {
+		return mfError("MFCreateMemoryBuffer", hr)
+	}
+	defer comCall(buffer, unknownRelease)
+
+	var p unsafe.Pointer
+	if hr := comCall(buffer, mfMediaBufferLock, uintptr(unsafe.Pointer(&p)), 0, 0); failedHRESULT(hr) {
+		return mfError("IMFMediaBuffer.Lock", hr)
+	}
+
+	dst := unsafe.Slice((*byte)(p), length)
+	for y := 0; y < ms.sz.Y; y++ {
+		src := img.Pix[y*img.Stride : y*img.Stride+4*ms.sz.X]
+		row := dst[y*4*ms.sz.X:]
+		for i := 0; i < len(src); i += 4 {
+			row[i], row[i+1], row[i+2], row[i+3] = src[i+2], src[i+1], src[i], 0xFF
+		}
+	}
+
+	comCall(buffer, mfMediaBufferUnlock)
+
+	if hr := comCall(buffer, mfMediaBufferSetLength, uintptr(length)); failedHRESULT(hr) {
+		return mfError("IMFMediaBuffer.SetCurrentLength", hr)
+	}
+
+	var sample uintptr // IMFSample
+	if hr, _, _ := mfCreateSample.Call(uintptr(unsafe.Pointer(&sample))); failedHRESULT(hr) {
+		return mfError("MFCreateSample", hr)
+	}
+	defer comCall(sample, unknownRelease)
+
+	// Media Foundation times are in units of 100 ns.
+	if hr := firstFailedHRESULT(
+		comCall(sample, mfSampleAddBuffer, buffer),
+		comCall(sample, mfSampleSetSampleTime, comArgsUINT64(uint64(ms.sampleTime/100))...),
+		comCall(sample, mfSampleSetSampleDuration, comArgsUINT64(uint64(duration/100))...)); failedHRESULT(hr) {
+		return mfError("IMFSample", hr)
+	}
+
+	if hr := comCall(ms.writer, mfSinkWriterWriteSample, uintptr(ms.stream), sample); failedHRESULT(hr) {
+		return mfError("IMFSinkWriter.WriteSample", hr)
+	}
+
+	ms.sampleTime += duration
+
+	return nil
+}
+
+func (ms *mp4RecordingSink) close() error {
+	var err error
+	if hr := comCall(ms.writer, mfSinkWriterFinalize); failedHRESULT(hr) {
+		err = mfError("IMFSinkWriter.Finalize", hr)
+	}
+
+	ms.release()
+
+	return err
+}
+
+func (ms *mp4RecordingSink) release() {
+	if ms.writer != 0 {
+		comCall(ms.writer, unknownRelease)
+		ms.writer = 0
+	}
+
+	if ms.started {
+		mfShutdown.Call()
+		ms.started = false
+	}
+
+	if ms.comInit {
+		win.CoUninitialize()
+		ms.comInit = false
+	}
+}
+
+// mp4Bitrate returns a bit rate in bits per second that keeps text of a
+// recorded form sharp.
+func mp4Bitrate(size image.Point, fps int) uint32 {
+	bitrate := uint64(size.X) * uint64(size.Y) * uint64(fps) / 5
+
+	if bitrate < 500000 {
+		bitrate = 500000
+	} else if bitrate > 50000000 {
+		bitrate = 50000000
+	}
+
+	return uint32(bitrate)
+}
+
+// newMFMediaType returns a new IMFMediaType, whose attributes init sets.
+func newMFMediaType(init func(mt uintptr) uintptr) (uintptr, error) {
+	var mt uintptr
+	if hr, _, _ := mfCreateMediaType.Call(uintptr(unsafe.Pointer(&mt))); failedHRESULT(hr) {
+		return 0, mfError("MFCreateMediaType", hr)
+	}
+
+	if hr := init(mt); failedHRESULT(hr) {
+		comCall(mt, unknownRelease)
+		return 0, mfError("IMFMediaType", hr)
+	}
+
+	return mt, nil
+}
+
+func setMFAttributeUINT64(attributes uintptr, key *win.IID, value uint64) uintptr {
+	return comCall(attributes, mfAttributesSetUINT64, append([]uintptr{uintptr(unsafe.Pointer(key))}, comArgsUINT64(value)...)...)
+}
+
+// comArgsUINT64 returns value as arguments of a COM call, which are two on
+// 32-bit systems.
+func comArgsUINT64(value uint64) []uintptr {
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		return []uintptr{uintptr(value)}
+	}
+
+	return []uintptr{uintptr(uint32(value)), uintptr(uint32(value >> 32))}
+}
+
+func failedHRESULT(hr uintptr) bool {
+	return win.FAILED(win.HRESULT(int32(hr)))
+}
+
+// firstFailedHRESULT returns the first of hrs that failed, or S_OK.
+func firstFailedHRESULT(hrs ...uintptr) uintptr {
+	for _, hr := range hrs {
+		if failedHRESULT(hr) {
+			return hr
+		}
+	}
+
+	return win.S_OK
+}
+
+// mfError returns an error for a failed Media Foundation call, which doesn't
+// panic, as it happens while recording.
+func mfError(funcName string, hr uintptr) error {
+	return newErrorNoPanic(fmt.Sprintf("%s: Error 0x%08X", funcName, uint32(hr)))
+}
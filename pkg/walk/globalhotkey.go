@@ -0,0 +1,157 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+const (
+	modAlt      = 0x0001
+	modControl  = 0x0002
+	modShift    = 0x0004
+	modNoRepeat = 0x4000
+
+	errorHotKeyAlreadyRegistered = 1409
+
+	// Applications may use the hot key ids 0x0000 through 0xBFFF.
+	maxGlobalHotKeyID = 0xBFFF
+)
+
+var (
+	registerHotKey   = syscall.NewLazyDLL("user32.dll").NewProc("RegisterHotKey")
+	unregisterHotKey = syscall.NewLazyDLL("user32.dll").NewProc("UnregisterHotKey")
+)
+
+// ErrHotKeyInUse is returned by RegisterGlobalHotKey if the shortcut is
+// already registered as a global hot key, by this or another application.
+var ErrHotKeyInUse = errors.New("hot key already registered")
+
+var lastGlobalHotKeyID int
+
+// GlobalHotKey is a system-wide shortcut registered with
+// RegisterGlobalHotKey.
+type GlobalHotKey struct {
+	form     *FormBase
+	id       int
+	shortcut Shortcut
+	handler  func()
+}
+
+// RegisterGlobalHotKey registers shortcut as a system-wide hot key, so handler
+// is called whenever it is pressed, even if no window of the application has
+// the focus. Holding the shortcut down doesn't repeat the call.
+//
+// The hot key is posted to form, so handler runs on its UI thread, and it is
+// unregistered when form is disposed. If another application, or this one,
+// already registered shortcut, ErrHotKeyInUse is returned.
+func RegisterGlobalHotKey(form Form, shortcut Shortcut, handler func()) (*GlobalHotKey, error) {
+	if shortcut.Key == 0 {
+		return nil, newError("shortcut without key")
+	}
+	if handler == nil {
+		return nil, newError("handler must not be nil")
+	}
+
+	fb := form.AsFormBase()
+
+	id := nextGlobalHotKeyID(fb)
+	if id < 0 {
+		return nil, newError("too many global hot keys")
+	}
+
+	var mods uintptr = modNoRepeat
+	if shortcut.Modifiers&ModAlt != 0 {
+		mods |= modAlt
+	}
+	if shortcut.Modifiers&ModControl != 0 {
+		mods |= modControl
+	}
+	if shortcut.Modifiers&ModShift != 0 {
+		mods |= modShift
+	}
+
+	if r, _, e := registerHotKey.Call(uintptr(fb.hWnd), uintptr(id), mods, uintptr(shortcut.Key)); r == 0 {
+		if errno, ok := e.(syscall.Errno); ok && errno == errorHotKeyAlreadyRegistered {
+			return nil, ErrHotKeyInUse
+		}
+
+		return nil, newError(fmt.Sprintf("RegisterHotKey: %s: %v", shortcut, e))
+	}
+
+	hk := &GlobalHotKey{
+		form:     fb,
+		id:       id,
+		shortcut: shortcut,
+		handler:  handler,
+	}
+
+	if fb.globalHotKeys == nil {
+		fb.globalHotKeys = make(map[int]*GlobalHotKey)
+	}
+	fb.globalHotKeys[id] = hk
+
+	return hk, nil
+}
+
+// nextGlobalHotKeyID returns an id for a new hot key of fb, or -1 if all of
+// them are in use.
+func nextGlobalHotKeyID(fb *FormBase) int {
+	for i := 0; i <= maxGlobalHotKeyID; i++ {
+		lastGlobalHotKeyID = (lastGlobalHotKeyID + 1) % (maxGlobalHotKeyID + 1)
+
+		if _, ok := fb.globalHotKeys[lastGlobalHotKeyID]; !ok {
+			return lastGlobalHotKeyID
+		}
+	}
+
+	return -1
+}
+
+// Shortcut returns the shortcut of the GlobalHotKey.
+func (hk *GlobalHotKey) Shortcut() Shortcut {
+	return hk.shortcut
+}
+
+// Unregister unregisters the GlobalHotKey, so its handler is no longer called.
+func (hk *GlobalHotKey) Unregister() error {
+	if hk.form == nil {
+		return nil
+	}
+
+	fb := hk.form
+	hk.form = nil
+	delete(fb.globalHotKeys, hk.id)
+
+	if fb.hWnd == 0 {
+		return nil
+	}
+
+	if r, _, e := unregisterHotKey.Call(uintptr(fb.hWnd), uintptr(hk.id)); r == 0 {
+		return newError(fmt.Sprintf("UnregisterHotKey: %s: %v", hk.shortcut, e))
+	}
+
+	return nil
+}
+
+// unregisterGlobalHotKeys unregisters all hot keys of the form, before its
+// window is destroyed.
+func (fb *FormBase) unregisterGlobalHotKeys() {
+	for _, hk := range fb.globalHotKeys {
+		hk.Unregister()
+	}
+}
+
+// handleHotKey calls the handler of the hot key of a WM_HOTKEY message.
+func (fb *FormBase) handleHotKey(wParam uintptr) {
+	if hk, ok := fb.globalHotKeys[int(wParam)]; ok {
+		hk.handler()
+	}
+}
@@ -0,0 +1,501 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+
+	"github.com/miu200521358/win"
+)
+
+const gradientEditorWindowClass = `\o/ Walk_GradientEditor_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClassWithStyle(gradientEditorWindowClass, win.CS_DBLCLKS)
+	})
+}
+
+// minGradientStopCount is the number of stops a GradientEditor keeps at
+// least, as required by gradient brushes.
+const minGradientStopCount = 2
+
+// GradientEditor is a widget for editing GradientStops.
+//
+// The ramp is shown as a bar with a handle for each stop below it. Clicking
+// the bar adds a stop, dragging a handle moves its stop and dragging it down
+// and away from the bar removes the stop. Double-clicking a handle or
+// pressing Enter opens a color dialog to recolor the current stop. The arrow
+// keys move and the Delete key removes the current stop.
+type GradientEditor struct {
+	WidgetBase
+	stops                        *GradientStops
+	stopsChangedHandle           int
+	currentIndex                 int
+	dragging                     bool
+	removing                     bool
+	stopsChangedPublisher        EventPublisher
+	currentIndexChangedPublisher EventPublisher
+}
+
+// NewGradientEditor creates and initializes a new GradientEditor, editing a
+// black to white ramp.
+func NewGradientEditor(parent Container) (*GradientEditor, error) {
+	ge := &GradientEditor{currentIndex: -1}
+
+	if err := InitWidget(
+		ge,
+		parent,
+		gradientEditorWindowClass,
+		win.WS_TABSTOP|win.WS_VISIBLE,
+		0); err != nil {
+		return nil, err
+	}
+
+	ge.GraphicsEffects().Add(InteractionEffect)
+	ge.GraphicsEffects().Add(FocusEffect)
+
+	ge.SetStops(nil)
+
+	return ge, nil
+}
+
+func (ge *GradientEditor) Dispose() {
+	if ge.stops != nil {
+		ge.stops.Changed().Detach(ge.stopsChangedHandle)
+		ge.stops = nil
+	}
+
+	ge.WidgetBase.Dispose()
+}
+
+// Stops returns the GradientStops edited by the GradientEditor.
+func (ge *GradientEditor) Stops() *GradientStops {
+	return ge.stops
+}
+
+// SetStops sets the GradientStops edited by the GradientEditor.
+//
+// If stops is nil or has less than 2 stops, it is replaced or filled up with
+// black and white stops.
+func (ge *GradientEditor) SetStops(stops *GradientStops) {
+	if ge.stops != nil {
+		ge.stops.Changed().Detach(ge.stopsChangedHandle)
+	}
+
+	if stops == nil {
+		stops = NewGradientStops()
+	}
+
+	switch stops.Len() {
+	case 0:
+		stops.SetStops([]GradientStop{{0, RGB(0, 0, 0)}, {1, RGB(255, 255, 255)}})
+
+	case 1:
+		stop := stops.At(0)
+		if stop.Offset < 1 {
+			stops.Add(GradientStop{1, stop.Color})
+		} else {
+			stops.Add(GradientStop{0, stop.Color})
+		}
+	}
+
+	ge.stops = stops
+	ge.stopsChangedHandle = stops.Changed().Attach(ge.onStopsChanged)
+
+	ge.setCurrentIndex(0)
+	ge.onStopsChanged()
+}
+
+func (ge *GradientEditor) onStopsChanged() {
+	if ge.currentIndex >= ge.stops.Len() {
+		ge.setCurrentIndex(ge.stops.Len() - 1)
+	}
+
+	ge.Invalidate()
+
+	ge.stopsChangedPublisher.Publish()
+}
+
+// StopsChanged returns the event that is published when the stops changed.
+func (ge *GradientEditor) StopsChanged() *Event {
+	return ge.stopsChangedPublisher.Event()
+}
+
+// CurrentIndex returns the index of the current stop.
+func (ge *GradientEditor) CurrentIndex() int {
+	return ge.currentIndex
+}
+
+// SetCurrentIndex sets the index of the current stop.
+func (ge *GradientEditor) SetCurrentIndex(index int) error {
+	if index < 0 || index >= ge.stops.Len() {
+		return newError("index out of range")
+	}
+
+	ge.setCurrentIndex(index)
+
+	return nil
+}
+
+func (ge *GradientEditor) setCurrentIndex(index int) {
+	if index == ge.currentIndex {
+		return
+	}
+
+	ge.currentIndex = index
+
+	ge.Invalidate()
+
+	ge.currentIndexChangedPublisher.Publish()
+}
+
+// CurrentIndexChanged returns the event that is published when the current
+// stop changed.
+func (ge *GradientEditor) CurrentIndexChanged() *Event {
+	return ge.currentIndexChangedPublisher.Event()
+}
+
+// ChooseCurrentColor shows a color dialog to recolor the current stop.
+func (ge *GradientEditor) ChooseCurrentColor() error {
+	if ge.currentIndex < 0 {
+		return nil
+	}
+
+	dlg := ColorDialog{Color: ge.stops.At(ge.currentIndex).Color, FullOpen: true}
+
+	accepted, err := dlg.ShowChoose(ge.Form())
+	if err != nil || !accepted {
+		return err
+	}
+
+	return ge.stops.SetColor(ge.currentIndex, dlg.Color)
+}
+
+// barBounds returns the bounds of the ramp bar in native pixels.
+func (ge *GradientEditor) barBounds() Rectangle {
+	cb := ge.ClientBoundsPixels()
+	dpi := ge.DPI()
+
+	hw := IntFrom96DPI(6, dpi)
+	handleHeight := IntFrom96DPI(14, dpi)
+
+	return Rectangle{hw, IntFrom96DPI(2, dpi), cb.Width - 2*hw, cb.Height - handleHeight - IntFrom96DPI(4, dpi)}
+}
+
+// handleBounds returns the bounds of the handle of the stop at index in
+// native pixels.
+func (ge *GradientEditor) handleBounds(index int) Rectangle {
+	bar := ge.barBounds()
+	dpi := ge.DPI()
+
+	hw := IntFrom96DPI(5, dpi)
+	x := bar.X + int(math.Round(ge.stops.At(index).Offset*float64(bar.Width)))
+
+	return Rectangle{x - hw, bar.Y + bar.Height + IntFrom96DPI(2, dpi), 2 * hw, IntFrom96DPI(12, dpi)}
+}
+
+func (ge *GradientEditor) handleAt(x, y int) int {
+	// Search backwards, so the topmost handle wins.
+	for i := ge.stops.Len() - 1; i >= 0; i-- {
+		if i == ge.currentIndex {
+			continue
+		}
+
+		if b := ge.handleBounds(i); x >= b.X && x < b.X+b.Width && y >= b.Y && y < b.Y+b.Height {
+			return i
+		}
+	}
+
+	if i := ge.currentIndex; i > -1 {
+		if b := ge.handleBounds(i); x >= b.X && x < b.X+b.Width && y >= b.Y && y < b.Y+b.Height {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (ge *GradientEditor) offsetAt(x int) float64 {
+	bar := ge.barBounds()
+	if bar.Width <= 0 {
+		return 0
+	}
+
+	return clampGradientOffset(float64(x-bar.X) / float64(bar.Width))
+}
+
+func (ge *GradientEditor) moveCurrent(offset float64) {
+	if index, err := ge.stops.SetOffset(ge.currentIndex, offset); err == nil {
+		ge.setCurrentIndex(index)
+	}
+}
+
+func (ge *GradientEditor) removeCurrent() {
+	if ge.currentIndex < 0 || ge.stops.Len() <= minGradientStopCount {
+		return
+	}
+
+	index := ge.currentIndex
+	if index == ge.stops.Len()-1 {
+		ge.setCurrentIndex(index - 1)
+	}
+
+	ge.stops.RemoveAt(index)
+}
+
+func (ge *GradientEditor) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, ge.DPI(), ge.paint) {
+			return 0
+		}
+
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_LBUTTONDOWN:
+		if !ge.Enabled() {
+			break
+		}
+
+		ge.SetFocus()
+
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+		index := ge.handleAt(x, y)
+		if index == -1 {
+			offset := ge.offsetAt(x)
+			index = ge.stops.Add(GradientStop{offset, ge.stops.ColorAt(offset)})
+		}
+
+		ge.setCurrentIndex(index)
+
+		win.SetCapture(hwnd)
+		ge.dragging = true
+
+	case win.WM_MOUSEMOVE:
+		if !ge.dragging {
+			break
+		}
+
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+		cb := ge.ClientBoundsPixels()
+		removing := y > cb.Height+IntFrom96DPI(24, ge.DPI()) && ge.stops.Len() > minGradientStopCount
+		if removing != ge.removing {
+			ge.removing = removing
+			ge.Invalidate()
+		}
+
+		ge.moveCurrent(ge.offsetAt(x))
+
+	case win.WM_LBUTTONUP:
+		if !ge.dragging {
+			break
+		}
+
+		ge.dragging = false
+		win.ReleaseCapture()
+
+		if ge.removing {
+			ge.removing = false
+			ge.removeCurrent()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		ge.dragging = false
+		if ge.removing {
+			ge.removing = false
+			ge.Invalidate()
+		}
+
+	case win.WM_LBUTTONDBLCLK:
+		if !ge.Enabled() {
+			break
+		}
+
+		if index := ge.handleAt(int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))); index > -1 {
+			ge.setCurrentIndex(index)
+			ge.ChooseCurrentColor()
+		}
+
+	case win.WM_KEYDOWN:
+		if !ge.Enabled() || ge.currentIndex < 0 {
+			break
+		}
+
+		step := 0.01
+		if ControlDown() {
+			step = 0.1
+		}
+
+		switch Key(wParam) {
+		case KeyLeft:
+			ge.moveCurrent(ge.stops.At(ge.currentIndex).Offset - step)
+
+		case KeyRight:
+			ge.moveCurrent(ge.stops.At(ge.currentIndex).Offset + step)
+
+		case KeyUp:
+			if ge.currentIndex > 0 {
+				ge.setCurrentIndex(ge.currentIndex - 1)
+			}
+
+		case KeyDown:
+			if ge.currentIndex < ge.stops.Len()-1 {
+				ge.setCurrentIndex(ge.currentIndex + 1)
+			}
+
+		case KeyDelete:
+			ge.removeCurrent()
+
+		case KeyReturn:
+			ge.ChooseCurrentColor()
+		}
+	}
+
+	return ge.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (ge *GradientEditor) paint(canvas *Canvas, bounds Rectangle) error {
+	bg, _ := ge.backgroundEffective()
+	if bg == nil {
+		bg = sysColorBtnFaceBrush
+	}
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	bar := ge.barBounds()
+	if bar.Width <= 0 || bar.Height <= 0 {
+		return nil
+	}
+
+	stops := ge.stops.Stops()
+
+	first, last := stops[0], stops[len(stops)-1]
+
+	x0 := bar.X + int(math.Round(first.Offset*float64(bar.Width)))
+	if err := ge.fillSolid(canvas, first.Color, Rectangle{bar.X, bar.Y, x0 - bar.X, bar.Height}); err != nil {
+		return err
+	}
+
+	for i := 1; i < len(stops); i++ {
+		x1 := bar.X + int(math.Round(stops[i].Offset*float64(bar.Width)))
+
+		if x1 > x0 {
+			if err := canvas.GradientFillRectanglePixels(stops[i-1].Color, stops[i].Color, Horizontal, Rectangle{x0, bar.Y, x1 - x0, bar.Height}); err != nil {
+				return err
+			}
+		}
+
+		x0 = x1
+	}
+
+	if err := ge.fillSolid(canvas, last.Color, Rectangle{x0, bar.Y, bar.X + bar.Width - x0, bar.Height}); err != nil {
+		return err
+	}
+
+	borderPen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer borderPen.Dispose()
+
+	if err := canvas.DrawRectanglePixels(borderPen, bar); err != nil {
+		return err
+	}
+
+	for i := range stops {
+		if i == ge.currentIndex {
+			continue
+		}
+
+		if err := ge.paintHandle(canvas, i, borderPen); err != nil {
+			return err
+		}
+	}
+
+	if ge.currentIndex > -1 && !ge.removing {
+		highlightBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_HIGHLIGHT)))
+		if err != nil {
+			return err
+		}
+		defer highlightBrush.Dispose()
+
+		currentPen, err := NewGeometricPen(PenSolid|PenJoinMiter, IntFrom96DPI(2, ge.DPI()), highlightBrush)
+		if err != nil {
+			return err
+		}
+		defer currentPen.Dispose()
+
+		return ge.paintHandle(canvas, ge.currentIndex, currentPen)
+	}
+
+	return nil
+}
+
+func (ge *GradientEditor) fillSolid(canvas *Canvas, color Color, bounds Rectangle) error {
+	if bounds.Width <= 0 {
+		return nil
+	}
+
+	brush, err := NewSolidColorBrush(color)
+	if err != nil {
+		return err
+	}
+	defer brush.Dispose()
+
+	return canvas.FillRectanglePixels(brush, bounds)
+}
+
+func (ge *GradientEditor) paintHandle(canvas *Canvas, index int, pen Pen) error {
+	b := ge.handleBounds(index)
+	bar := ge.barBounds()
+
+	cx := b.X + b.Width/2
+	if err := canvas.DrawLinePixels(pen, Point{cx, bar.Y + bar.Height}, Point{cx, b.Y}); err != nil {
+		return err
+	}
+
+	if err := ge.fillSolid(canvas, ge.stops.At(index).Color, b); err != nil {
+		return err
+	}
+
+	return canvas.DrawRectanglePixels(pen, b)
+}
+
+func (ge *GradientEditor) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &gradientEditorLayoutItem{
+		idealSize: SizeFrom96DPI(Size{200, 40}, ctx.dpi),
+		minSize:   SizeFrom96DPI(Size{40, 32}, ctx.dpi),
+	}
+}
+
+type gradientEditorLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+	minSize   Size // in native pixels
+}
+
+func (*gradientEditorLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | GrowableHorz | GreedyHorz
+}
+
+func (li *gradientEditorLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *gradientEditorLayoutItem) MinSize() Size {
+	return li.minSize
+}
@@ -0,0 +1,219 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"sort"
+)
+
+// GradientStops is an editable list of GradientStop values, ordered by
+// offset, that describes a color ramp.
+//
+// Offsets are kept in the range [0, 1]. GradientStops is the model of a
+// GradientEditor and can be turned into a GradientBrush.
+type GradientStops struct {
+	stops            []GradientStop
+	changedPublisher EventPublisher
+}
+
+// NewGradientStops returns a new GradientStops, initialized with stops.
+func NewGradientStops(stops ...GradientStop) *GradientStops {
+	gs := new(GradientStops)
+
+	gs.setStops(stops)
+
+	return gs
+}
+
+// Len returns the number of stops.
+func (gs *GradientStops) Len() int {
+	return len(gs.stops)
+}
+
+// At returns the stop at index.
+func (gs *GradientStops) At(index int) GradientStop {
+	return gs.stops[index]
+}
+
+// Stops returns a copy of the stops, ordered by offset.
+func (gs *GradientStops) Stops() []GradientStop {
+	return append([]GradientStop(nil), gs.stops...)
+}
+
+// SetStops replaces all stops.
+func (gs *GradientStops) SetStops(stops []GradientStop) {
+	gs.setStops(stops)
+
+	gs.changedPublisher.Publish()
+}
+
+func (gs *GradientStops) setStops(stops []GradientStop) {
+	gs.stops = make([]GradientStop, len(stops))
+
+	for i, stop := range stops {
+		stop.Offset = clampGradientOffset(stop.Offset)
+		gs.stops[i] = stop
+	}
+
+	sort.SliceStable(gs.stops, func(i, j int) bool {
+		return gs.stops[i].Offset < gs.stops[j].Offset
+	})
+}
+
+// Add inserts stop and returns its index.
+func (gs *GradientStops) Add(stop GradientStop) int {
+	stop.Offset = clampGradientOffset(stop.Offset)
+
+	index := sort.Search(len(gs.stops), func(i int) bool {
+		return gs.stops[i].Offset > stop.Offset
+	})
+
+	gs.stops = append(gs.stops, GradientStop{})
+	copy(gs.stops[index+1:], gs.stops[index:])
+	gs.stops[index] = stop
+
+	gs.changedPublisher.Publish()
+
+	return index
+}
+
+// RemoveAt removes the stop at index.
+func (gs *GradientStops) RemoveAt(index int) error {
+	if index < 0 || index >= len(gs.stops) {
+		return newError("index out of range")
+	}
+
+	gs.stops = append(gs.stops[:index], gs.stops[index+1:]...)
+
+	gs.changedPublisher.Publish()
+
+	return nil
+}
+
+// SetOffset moves the stop at index to offset and returns its new index.
+func (gs *GradientStops) SetOffset(index int, offset float64) (int, error) {
+	if index < 0 || index >= len(gs.stops) {
+		return -1, newError("index out of range")
+	}
+
+	stop := gs.stops[index]
+	stop.Offset = clampGradientOffset(offset)
+
+	if stop == gs.stops[index] {
+		return index, nil
+	}
+
+	gs.stops[index] = stop
+
+	for index > 0 && gs.stops[index-1].Offset > stop.Offset {
+		gs.stops[index-1], gs.stops[index] = gs.stops[index], gs.stops[index-1]
+		index--
+	}
+	for index < len(gs.stops)-1 && gs.stops[index+1].Offset < stop.Offset {
+		gs.stops[index+1], gs.stops[index] = gs.stops[index], gs.stops[index+1]
+		index++
+	}
+
+	gs.changedPublisher.Publish()
+
+	return index, nil
+}
+
+// SetColor sets the color of the stop at index.
+func (gs *GradientStops) SetColor(index int, color Color) error {
+	if index < 0 || index >= len(gs.stops) {
+		return newError("index out of range")
+	}
+
+	if color == gs.stops[index].Color {
+		return nil
+	}
+
+	gs.stops[index].Color = color
+
+	gs.changedPublisher.Publish()
+
+	return nil
+}
+
+// ColorAt returns the color of the ramp at offset, interpolated linearly
+// between the neighboring stops.
+func (gs *GradientStops) ColorAt(offset float64) Color {
+	switch len(gs.stops) {
+	case 0:
+		return 0
+
+	case 1:
+		return gs.stops[0].Color
+	}
+
+	if offset <= gs.stops[0].Offset {
+		return gs.stops[0].Color
+	}
+
+	for i := 1; i < len(gs.stops); i++ {
+		s0, s1 := gs.stops[i-1], gs.stops[i]
+
+		if offset <= s1.Offset {
+			if s1.Offset == s0.Offset {
+				return s1.Color
+			}
+
+			return s0.Color.Blend(s1.Color, (offset-s0.Offset)/(s1.Offset-s0.Offset))
+		}
+	}
+
+	return gs.stops[len(gs.stops)-1].Color
+}
+
+// Changed returns the event that is published when stops were added,
+// removed, moved or recolored.
+func (gs *GradientStops) Changed() *Event {
+	return gs.changedPublisher.Event()
+}
+
+// NewHorizontalGradientBrush returns a new horizontal GradientBrush that
+// draws the ramp.
+func (gs *GradientStops) NewHorizontalGradientBrush() (*GradientBrush, error) {
+	return NewHorizontalGradientBrush(gs.brushStops())
+}
+
+// NewVerticalGradientBrush returns a new vertical GradientBrush that draws
+// the ramp.
+func (gs *GradientStops) NewVerticalGradientBrush() (*GradientBrush, error) {
+	return NewVerticalGradientBrush(gs.brushStops())
+}
+
+// brushStops returns the stops, extended to cover the whole range [0, 1].
+func (gs *GradientStops) brushStops() []GradientStop {
+	stops := gs.Stops()
+	if len(stops) == 0 {
+		return nil
+	}
+
+	if first := stops[0]; first.Offset > 0 {
+		stops = append([]GradientStop{{0, first.Color}}, stops...)
+	}
+	if last := stops[len(stops)-1]; last.Offset < 1 {
+		stops = append(stops, GradientStop{1, last.Color})
+	}
+
+	return stops
+}
+
+func clampGradientOffset(offset float64) float64 {
+	if math.IsNaN(offset) || offset < 0 {
+		return 0
+	}
+	if offset > 1 {
+		return 1
+	}
+
+	return offset
+}
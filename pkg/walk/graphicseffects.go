@@ -186,6 +186,41 @@ func (dse *DropShadowEffect) Draw(widget Widget, canvas *Canvas) error {
 	return nil
 }
 
+// ErrorAdornerEffect draws a border around a widget, e.g. in red to mark
+// invalid input.
+type ErrorAdornerEffect struct {
+	color Color
+}
+
+func NewErrorAdornerEffect(color Color) (*ErrorAdornerEffect, error) {
+	return &ErrorAdornerEffect{color: color}, nil
+}
+
+func (eae *ErrorAdornerEffect) Draw(widget Widget, canvas *Canvas) error {
+	b := widget.BoundsPixels()
+
+	w := IntFrom96DPI(2, canvas.DPI())
+
+	brush, err := NewSolidColorBrush(eae.color)
+	if err != nil {
+		return err
+	}
+	defer brush.Dispose()
+
+	for _, r := range [...]Rectangle{
+		{b.X - w, b.Y - w, b.Width + 2*w, w},
+		{b.X - w, b.Y + b.Height, b.Width + 2*w, w},
+		{b.X - w, b.Y, w, b.Height},
+		{b.X + b.Width, b.Y, w, b.Height},
+	} {
+		if err := canvas.FillRectanglePixels(brush, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 type widgetGraphicsEffectListObserver interface {
 	onInsertedGraphicsEffect(index int, effect WidgetGraphicsEffect) error
 	onRemovedGraphicsEffect(index int, effect WidgetGraphicsEffect) error
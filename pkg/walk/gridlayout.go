@@ -34,6 +34,8 @@ type GridLayout struct {
 	LayoutBase
 	rowStretchFactors    []int
 	columnStretchFactors []int
+	row2MinHeight96dpi   map[int]int
+	column2MinWidth96dpi map[int]int
 	widgetBase2Info      map[*WidgetBase]*gridLayoutWidgetInfo
 	cells                [][]gridLayoutCell
 }
@@ -44,7 +46,9 @@ func NewGridLayout() *GridLayout {
 			margins96dpi: Margins{6, 6, 6, 6},
 			spacing96dpi: 3,
 		},
-		widgetBase2Info: make(map[*WidgetBase]*gridLayoutWidgetInfo),
+		row2MinHeight96dpi:   make(map[int]int),
+		column2MinWidth96dpi: make(map[int]int),
+		widgetBase2Info:      make(map[*WidgetBase]*gridLayoutWidgetInfo),
 	}
 	l.layout = l
 
@@ -174,6 +178,98 @@ func (l *GridLayout) SetColumnStretchFactor(column, factor int) error {
 	return nil
 }
 
+// MinRowHeight returns the minimum height of row in 1/96".
+func (l *GridLayout) MinRowHeight(row int) int {
+	return l.row2MinHeight96dpi[row]
+}
+
+// SetMinRowHeight sets the minimum height of row in 1/96", which applies even
+// if the row is empty, e.g. to keep rows of a form aligned.
+func (l *GridLayout) SetMinRowHeight(row, height int) error {
+	if row < 0 {
+		return newError("row must be >= 0")
+	}
+	if height < 0 {
+		return newError("height must be >= 0")
+	}
+
+	if height != l.MinRowHeight(row) {
+		l.ensureSufficientSize(row+1, len(l.columnStretchFactors))
+
+		if height == 0 {
+			delete(l.row2MinHeight96dpi, row)
+		} else {
+			l.row2MinHeight96dpi[row] = height
+		}
+
+		if l.container != nil {
+			l.container.RequestLayout()
+		}
+	}
+
+	return nil
+}
+
+// MinColumnWidth returns the minimum width of column in 1/96".
+func (l *GridLayout) MinColumnWidth(column int) int {
+	return l.column2MinWidth96dpi[column]
+}
+
+// SetMinColumnWidth sets the minimum width of column in 1/96", which applies
+// even if the column is empty, e.g. to align the labels of a form.
+func (l *GridLayout) SetMinColumnWidth(column, width int) error {
+	if column < 0 {
+		return newError("column must be >= 0")
+	}
+	if width < 0 {
+		return newError("width must be >= 0")
+	}
+
+	if width != l.MinColumnWidth(column) {
+		l.ensureSufficientSize(len(l.rowStretchFactors), column+1)
+
+		if width == 0 {
+			delete(l.column2MinWidth96dpi, column)
+		} else {
+			l.column2MinWidth96dpi[column] = width
+		}
+
+		if l.container != nil {
+			l.container.RequestLayout()
+		}
+	}
+
+	return nil
+}
+
+// WidgetAt returns the widget that occupies the cell at row and column, or nil
+// if the cell is free.
+func (l *GridLayout) WidgetAt(row, column int) Widget {
+	if row < 0 || row >= len(l.cells) || column < 0 || column >= len(l.cells[row]) {
+		return nil
+	}
+
+	wb := l.cells[row][column].widgetBase
+	if wb == nil {
+		return nil
+	}
+
+	return wb.window.(Widget)
+}
+
+// RangeFree returns whether none of the cells in r is occupied by a widget.
+func (l *GridLayout) RangeFree(r Rectangle) bool {
+	for row := r.Y; row < r.Y+r.Height; row++ {
+		for col := r.X; col < r.X+r.Width; col++ {
+			if l.WidgetAt(row, col) != nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 func rangeFromGridLayoutWidgetInfo(info *gridLayoutWidgetInfo) Rectangle {
 	return Rectangle{
 		X:      info.cell.column,
@@ -301,6 +397,20 @@ func (l *GridLayout) CreateLayoutItem(ctx *LayoutContext) ContainerLayoutItem {
 		}
 	}
 
+	rowMinSizes := make([]int, len(l.rowStretchFactors))
+	for row, height := range l.row2MinHeight96dpi {
+		if row < len(rowMinSizes) {
+			rowMinSizes[row] = IntFrom96DPI(height, ctx.dpi)
+		}
+	}
+
+	columnMinSizes := make([]int, len(l.columnStretchFactors))
+	for col, width := range l.column2MinWidth96dpi {
+		if col < len(columnMinSizes) {
+			columnMinSizes[col] = IntFrom96DPI(width, ctx.dpi)
+		}
+	}
+
 	return &gridLayoutItem{
 		ContainerLayoutItemBase: ContainerLayoutItemBase{
 			children: children,
@@ -308,6 +418,8 @@ func (l *GridLayout) CreateLayoutItem(ctx *LayoutContext) ContainerLayoutItem {
 		size2MinSize:         make(map[Size]Size),
 		rowStretchFactors:    append([]int(nil), l.rowStretchFactors...),
 		columnStretchFactors: append([]int(nil), l.columnStretchFactors...),
+		rowMinSizes:          rowMinSizes,
+		columnMinSizes:       columnMinSizes,
 		item2Info:            item2Info,
 		cells:                cells,
 	}
@@ -319,6 +431,8 @@ type gridLayoutItem struct {
 	size2MinSize         map[Size]Size // in native pixels
 	rowStretchFactors    []int
 	columnStretchFactors []int
+	rowMinSizes          []int // in native pixels
+	columnMinSizes       []int // in native pixels
 	item2Info            map[LayoutItem]*gridLayoutItemInfo
 	cells                [][]gridLayoutItemCell
 	minSize              Size // in native pixels
@@ -402,7 +516,7 @@ func (li *gridLayoutItem) MinSizeForSize(size Size) Size {
 		return min
 	}
 
-	ws := make([]int, len(li.cells[0]))
+	ws := append([]int(nil), li.columnMinSizes...)
 
 	for row := 0; row < len(li.cells); row++ {
 		for col := 0; col < len(ws); col++ {
@@ -467,7 +581,7 @@ func (li *gridLayoutItem) MinSizeForSize(size Size) Size {
 
 		wg.Wait()
 
-		heights[row] = maxHeight
+		heights[row] = maxi(maxHeight, li.rowMinSizes[row])
 	}
 
 	margins := MarginsFrom96DPI(li.margins96dpi, li.ctx.dpi)
@@ -607,7 +721,11 @@ func (li *gridLayoutItem) PerformLayout() []LayoutResultItem {
 		w := width
 		h := height
 
-		if lf := item.LayoutFlags(); lf&GrowableHorz == 0 || lf&GrowableVert == 0 {
+		// A child with an alignment of its own keeps its ideal size and is
+		// aligned within its cells, instead of filling them.
+		ownAlignment := item.Geometry().Alignment != AlignHVDefault
+
+		if lf := item.LayoutFlags(); ownAlignment || lf&GrowableHorz == 0 || lf&GrowableVert == 0 {
 			var s Size
 			if hfw, ok := item.(HeightForWidther); !ok || !hfw.HasHeightForWidth() {
 				if is, ok := item.(IdealSizer); ok {
@@ -619,7 +737,7 @@ func (li *gridLayoutItem) PerformLayout() []LayoutResultItem {
 			if max.Width > 0 && s.Width > max.Width {
 				s.Width = max.Width
 			}
-			if lf&GrowableHorz == 0 {
+			if lf&GrowableHorz == 0 || ownAlignment && s.Width > 0 {
 				w = s.Width
 			}
 			w = mini(w, width)
@@ -630,7 +748,7 @@ func (li *gridLayoutItem) PerformLayout() []LayoutResultItem {
 				if max.Height > 0 && s.Height > max.Height {
 					s.Height = max.Height
 				}
-				if lf&GrowableVert == 0 {
+				if lf&GrowableVert == 0 || ownAlignment && s.Height > 0 {
 					h = s.Height
 				}
 			}
@@ -670,11 +788,13 @@ func (li *gridLayoutItem) PerformLayout() []LayoutResultItem {
 
 // sectionSizesForSpace returns section sizes. Input and outpus is measured in native pixels.
 func (li *gridLayoutItem) sectionSizesForSpace(orientation Orientation, space int, widths []int) []int {
-	var stretchFactors []int
+	var stretchFactors, sectionMinSizes []int
 	if orientation == Horizontal {
 		stretchFactors = li.columnStretchFactors
+		sectionMinSizes = li.columnMinSizes
 	} else {
 		stretchFactors = li.rowStretchFactors
+		sectionMinSizes = li.rowMinSizes
 	}
 
 	var sectionCountWithGreedyNonSpacer int
@@ -771,6 +891,11 @@ func (li *gridLayoutItem) sectionSizesForSpace(orientation Orientation, space in
 			}
 		}
 
+		if min := sectionMinSizes[i]; min > 0 {
+			minSizes[i] = maxi(minSizes[i], min)
+			maxSizes[i] = maxi(maxSizes[i], min)
+		}
+
 		sortedSections[i].index = i
 		sortedSections[i].minSize = minSizes[i]
 		sortedSections[i].maxSize = maxSizes[i]
@@ -26,6 +26,7 @@ type GroupBox struct {
 	WidgetBase
 	hWndGroupBox          win.HWND
 	checkBox              *CheckBox
+	header                *Composite
 	composite             *Composite
 	headerHeight          int // in native pixels
 	titleChangedPublisher EventPublisher
@@ -52,7 +53,7 @@ func NewGroupBox(parent Container) (*GroupBox, error) {
 
 	gb.hWndGroupBox = win.CreateWindowEx(
 		0, syscall.StringToUTF16Ptr("BUTTON"), nil,
-		win.WS_CHILD|win.WS_VISIBLE|win.BS_GROUPBOX,
+		win.WS_CHILD|win.WS_VISIBLE|win.WS_CLIPSIBLINGS|win.BS_GROUPBOX,
 		0, 0, 80, 24, gb.hWnd, 0, 0, nil)
 	if gb.hWndGroupBox == 0 {
 		return nil, lastError("CreateWindowEx(BUTTON)")
@@ -79,6 +80,22 @@ func NewGroupBox(parent Container) (*GroupBox, error) {
 
 	setWindowVisible(gb.checkBox.hWnd, false)
 
+	// The header must be created before the composite, which would otherwise
+	// become its parent.
+	gb.header, err = NewComposite(gb)
+	if err != nil {
+		return nil, err
+	}
+	win.SetWindowLong(gb.header.hWnd, win.GWL_ID, 4)
+	gb.header.name = "header"
+
+	headerLayout := NewHBoxLayout()
+	headerLayout.SetMargins(Margins{})
+	headerLayout.SetSpacing(4)
+	if err := gb.header.SetLayout(headerLayout); err != nil {
+		return nil, err
+	}
+
 	gb.composite, err = NewComposite(gb)
 	if err != nil {
 		return nil, err
@@ -87,6 +104,7 @@ func NewGroupBox(parent Container) (*GroupBox, error) {
 	gb.composite.name = "composite"
 
 	win.SetWindowPos(gb.checkBox.hWnd, win.HWND_TOP, 0, 0, 0, 0, win.SWP_NOMOVE|win.SWP_NOSIZE)
+	win.SetWindowPos(gb.header.hWnd, win.HWND_TOP, 0, 0, 0, 0, win.SWP_NOMOVE|win.SWP_NOSIZE)
 
 	gb.SetBackground(NullBrush())
 
@@ -122,6 +140,38 @@ func (gb *GroupBox) AsContainerBase() *ContainerBase {
 	return gb.composite.AsContainerBase()
 }
 
+// HeaderContainer returns the Container for widgets shown in the header of
+// the GroupBox, right-aligned on the line of the title, e.g. a switch that
+// enables the section or a help button. It has a HBoxLayout.
+func (gb *GroupBox) HeaderContainer() Container {
+	return gb.header
+}
+
+// hasHeaderWidgets returns whether the header holds visible widgets.
+func (gb *GroupBox) hasHeaderWidgets() bool {
+	if gb.header == nil {
+		return false
+	}
+
+	for _, w := range gb.header.children.items {
+		if w.Visible() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Border returns the Border drawn inside the frame of the GroupBox.
+func (gb *GroupBox) Border() Border {
+	return gb.composite.Border()
+}
+
+// SetBorder sets the Border drawn inside the frame of the GroupBox.
+func (gb *GroupBox) SetBorder(border Border) error {
+	return gb.composite.SetBorder(border)
+}
+
 func (gb *GroupBox) ClientBoundsPixels() Rectangle {
 	cb := windowClientBounds(gb.hWndGroupBox)
 
@@ -171,6 +221,10 @@ func (gb *GroupBox) applyEnabled(enabled bool) {
 		gb.checkBox.applyEnabled(enabled)
 	}
 
+	if gb.header != nil {
+		gb.header.applyEnabled(enabled)
+	}
+
 	if gb.composite != nil {
 		gb.composite.applyEnabled(enabled)
 	}
@@ -193,6 +247,10 @@ func (gb *GroupBox) applyFont(font *Font) {
 		gb.checkBox.applyFont(font)
 	}
 
+	if gb.header != nil {
+		gb.header.applyFont(font)
+	}
+
 	if gb.hWndGroupBox != 0 {
 		SetWindowFont(gb.hWndGroupBox, font)
 	}
@@ -205,6 +263,7 @@ func (gb *GroupBox) applyFont(font *Font) {
 }
 
 func (gb *GroupBox) SetSuspended(suspend bool) {
+	gb.header.SetSuspended(suspend)
 	gb.composite.SetSuspended(suspend)
 	gb.WidgetBase.SetSuspended(suspend)
 	gb.Invalidate()
@@ -269,6 +328,9 @@ func (gb *GroupBox) ApplyDPI(dpi int) {
 	if gb.checkBox != nil {
 		gb.checkBox.ApplyDPI(dpi)
 	}
+	if gb.header != nil {
+		gb.header.ApplyDPI(dpi)
+	}
 	if gb.composite != nil {
 		gb.composite.ApplyDPI(dpi)
 	}
@@ -332,6 +394,7 @@ func (gb *GroupBox) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 
 		case win.WM_PAINT:
 			win.UpdateWindow(gb.checkBox.hWnd)
+			win.UpdateWindow(gb.header.hWnd)
 
 		case win.WM_WINDOWPOSCHANGED:
 			wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
@@ -386,12 +449,34 @@ func (gb *GroupBox) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 
 	li.children = append(li.children, gbli)
 
+	hli := CreateLayoutItemsForContainerWithContext(gb.header, ctx)
+	hli.AsLayoutItemBase().parent = li
+
+	li.children = append(li.children, hli)
+
+	if gb.hasHeaderWidgets() {
+		li.headerSize = hli.(IdealSizer).IdealSize()
+		li.headerInset = gb.headerHeight * 2 / 3
+
+		// The header is centered on the line of the title and may be taller.
+		li.headerY = (gb.headerHeight - li.headerSize.Height) / 2
+		if li.headerY < 0 {
+			li.headerY = 0
+		}
+		if bottom := li.headerY + li.headerSize.Height; bottom > li.compositePos.Y {
+			li.compositePos.Y = bottom
+		}
+	}
+
 	return li
 }
 
 type groupBoxLayoutItem struct {
 	ContainerLayoutItemBase
 	compositePos Point // in native pixels
+	headerSize   Size  // in native pixels, zero without header widgets
+	headerInset  int   // in native pixels
+	headerY      int   // in native pixels
 }
 
 func (li *groupBoxLayoutItem) LayoutFlags() LayoutFlags {
@@ -403,6 +488,10 @@ func (li *groupBoxLayoutItem) MinSize() Size {
 	min.Width += li.compositePos.X * 2
 	min.Height += li.compositePos.Y + 2
 
+	if w := li.headerSize.Width + li.headerInset*2; w > min.Width {
+		min.Width = w
+	}
+
 	return min
 }
 
@@ -425,10 +514,24 @@ func (li *groupBoxLayoutItem) IdealSize() Size {
 }
 
 func (li *groupBoxLayoutItem) PerformLayout() []LayoutResultItem {
+	var headerBounds Rectangle
+	if li.headerSize != (Size{}) {
+		headerBounds = Rectangle{
+			X:      li.geometry.Size.Width - li.headerInset - li.headerSize.Width,
+			Y:      li.headerY,
+			Width:  li.headerSize.Width,
+			Height: li.headerSize.Height,
+		}
+	}
+
 	return []LayoutResultItem{
 		{
 			Item:   li.children[0],
 			Bounds: Rectangle{X: li.compositePos.X, Y: li.compositePos.Y, Width: li.geometry.Size.Width - li.compositePos.X*2, Height: li.geometry.Size.Height - li.compositePos.Y - 4},
 		},
+		{
+			Item:   li.children[1],
+			Bounds: headerBounds,
+		},
 	}
 }
@@ -0,0 +1,203 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"bytes"
+	"log"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+var disableProcessWindowsGhosting = syscall.NewLazyDLL("user32.dll").NewProc("DisableProcessWindowsGhosting")
+
+// DisableWindowsGhosting keeps Windows from replacing the windows of the
+// process with frozen "Not Responding" ghost windows, when the message loop
+// doesn't pump for a while. This can't be undone while the process runs.
+func DisableWindowsGhosting() {
+	disableProcessWindowsGhosting.Call()
+}
+
+// HangDetector watches the message loop of a Form from a separate goroutine
+// and reports when it didn't process messages for longer than a threshold,
+// which usually means that an event handler made a blocking call.
+type HangDetector struct {
+	form                  Form
+	threshold             time.Duration
+	goroutineID           string
+	pingPending           int32
+	disposingHandle       int
+	stop                  chan struct{}
+	hangDetectedPublisher StringEventPublisher
+}
+
+// NewHangDetector starts watching the message loop of form, until the
+// HangDetector or form are disposed. It must be called on the goroutine that
+// runs the message loop.
+//
+// Whenever the message loop is blocked for threshold, HangDetected is
+// published once, until the loop catches up again.
+func NewHangDetector(form Form, threshold time.Duration) (*HangDetector, error) {
+	if threshold <= 0 {
+		return nil, newError("threshold must be > 0")
+	}
+
+	hd := &HangDetector{
+		form:        form,
+		threshold:   threshold,
+		goroutineID: currentGoroutineID(),
+		stop:        make(chan struct{}),
+	}
+
+	hd.disposingHandle = form.Disposing().Attach(hd.Dispose)
+
+	go hd.watch(hd.stop)
+
+	return hd, nil
+}
+
+// Dispose stops watching the message loop. Calling Dispose more than once has
+// no effect.
+func (hd *HangDetector) Dispose() {
+	if hd.stop == nil {
+		return
+	}
+
+	close(hd.stop)
+	hd.stop = nil
+
+	hd.form.Disposing().Detach(hd.disposingHandle)
+}
+
+// Threshold returns how long the message loop must be blocked, before a hang
+// is reported.
+func (hd *HangDetector) Threshold() time.Duration {
+	return hd.threshold
+}
+
+// HangDetected returns the event that is published with the stack of the
+// goroutine running the message loop, when it has been blocked for longer
+// than the threshold.
+//
+// As the message loop is blocked, the event is published on the goroutine of
+// the HangDetector. Handlers must not use windows or widgets and should be
+// attached right after NewHangDetector returned.
+func (hd *HangDetector) HangDetected() *StringEvent {
+	return hd.hangDetectedPublisher.Event()
+}
+
+func (hd *HangDetector) watch(stop <-chan struct{}) {
+	interval := hd.threshold / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var pingSent time.Time
+	var reported bool
+
+	for {
+		select {
+		case <-stop:
+			return
+
+		case now := <-ticker.C:
+			if atomic.LoadInt32(&hd.pingPending) == 0 {
+				// The message loop answered the last ping.
+				reported = false
+
+				atomic.StoreInt32(&hd.pingPending, 1)
+				pingSent = now
+
+				hd.form.Synchronize(func() {
+					atomic.StoreInt32(&hd.pingPending, 0)
+				})
+			} else if !reported && now.Sub(pingSent) >= hd.threshold {
+				reported = true
+
+				hd.hangDetectedPublisher.Publish(goroutineStack(hd.goroutineID))
+			}
+		}
+	}
+}
+
+// startWatchdog starts a HangDetector for the message loop of fb, if
+// configured with Application.SetWatchdogThreshold and none watches the
+// thread yet. Nested loops of the same thread, e.g. of modal dialogs, are
+// covered by the detector of the outermost loop.
+func (fb *FormBase) startWatchdog() {
+	threshold := App().WatchdogThreshold()
+	if threshold <= 0 || fb.group.hangDetector != nil {
+		return
+	}
+
+	hd, err := NewHangDetector(fb.window.(Form), threshold)
+	if err != nil {
+		return
+	}
+
+	hd.HangDetected().Attach(func(stack string) {
+		log.Printf("walk: message loop blocked for more than %v\n\nStack:\n%s", threshold, stack)
+
+		App().unresponsivePublisher.Publish(stack)
+	})
+
+	fb.group.hangDetector = hd
+
+	fb.Disposing().Attach(func() {
+		if fb.group.hangDetector == hd {
+			fb.group.hangDetector = nil
+		}
+	})
+}
+
+// currentGoroutineID returns the ID of the calling goroutine, as it appears
+// in stack traces.
+func currentGoroutineID() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	// The trace starts with "goroutine 1 [running]:".
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		if _, err := strconv.Atoi(string(buf[:i])); err == nil {
+			return string(buf[:i])
+		}
+	}
+
+	return ""
+}
+
+// goroutineStack returns the stack trace of the goroutine with id, or those
+// of all goroutines, if it can't be found.
+func goroutineStack(id string) string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	prefix := []byte("goroutine " + id + " ")
+
+	for _, trace := range bytes.Split(buf, []byte("\n\n")) {
+		if id != "" && bytes.HasPrefix(trace, prefix) {
+			return string(trace)
+		}
+	}
+
+	return string(buf)
+}
@@ -0,0 +1,537 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const headerBarWindowClass = `\o/ Walk_HeaderBar_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(headerBarWindowClass)
+	})
+}
+
+const (
+	hdsButtons  = 0x0002
+	hdsHotTrack = 0x0004
+	hdsDragDrop = 0x0040
+	hdsFullDrag = 0x0080
+)
+
+// nmHeader is the NMHEADER struct.
+type nmHeader struct {
+	hdr     win.NMHDR
+	iItem   int32
+	iButton int32
+	pitem   *win.HDITEM
+}
+
+// HeaderBar is a widget that shows column headers, which the user can resize,
+// reorder and click for sorting, for custom views that draw their columns
+// themselves.
+//
+// Call SetScrollOffset when the view scrolls horizontally, to keep the headers
+// aligned with the columns, and use ColumnBoundsPixels to lay out the columns.
+type HeaderBar struct {
+	WidgetBase
+	hWndHeader                  win.HWND
+	scrollOffset                int // in native pixels
+	sortable                    bool
+	sortColumn                  int
+	sortOrder                   SortOrder
+	columnClickedPublisher      IntEventPublisher
+	columnWidthChangedPublisher IntEventPublisher
+	columnsReorderedPublisher   EventPublisher
+	sortChangedPublisher        EventPublisher
+}
+
+// NewHeaderBar returns a new HeaderBar as child of parent.
+func NewHeaderBar(parent Container) (*HeaderBar, error) {
+	hb := &HeaderBar{sortable: true, sortColumn: -1}
+
+	if err := InitWidget(
+		hb,
+		parent,
+		headerBarWindowClass,
+		win.WS_VISIBLE,
+		0); err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			hb.Dispose()
+		}
+	}()
+
+	hb.hWndHeader = win.CreateWindowEx(
+		0, syscall.StringToUTF16Ptr("SysHeader32"), nil,
+		win.WS_CHILD|win.WS_VISIBLE|hdsButtons|hdsHotTrack|hdsDragDrop|hdsFullDrag,
+		0, 0, 0, 0, hb.hWnd, 0, 0, nil)
+	if hb.hWndHeader == 0 {
+		return nil, lastError("CreateWindowEx")
+	}
+
+	hb.applyFont(hb.Font())
+
+	succeeded = true
+
+	return hb, nil
+}
+
+func (hb *HeaderBar) applyEnabled(enabled bool) {
+	hb.WidgetBase.applyEnabled(enabled)
+
+	setWindowEnabled(hb.hWndHeader, enabled)
+}
+
+func (hb *HeaderBar) applyFont(font *Font) {
+	hb.WidgetBase.applyFont(font)
+
+	SetWindowFont(hb.hWndHeader, font)
+
+	hb.RequestLayout()
+}
+
+// ColumnCount returns the number of columns of the HeaderBar.
+func (hb *HeaderBar) ColumnCount() int {
+	return int(win.SendMessage(hb.hWndHeader, win.HDM_GETITEMCOUNT, 0, 0))
+}
+
+// AddColumn appends a column with title and width in 1/96" units.
+func (hb *HeaderBar) AddColumn(title string, width int) error {
+	return hb.InsertColumn(hb.ColumnCount(), title, width)
+}
+
+// InsertColumn inserts a column with title and width in 1/96" units at index.
+func (hb *HeaderBar) InsertColumn(index int, title string, width int) error {
+	if index < 0 || index > hb.ColumnCount() {
+		return newError("invalid index")
+	}
+
+	text := syscall.StringToUTF16(title)
+
+	item := win.HDITEM{
+		Mask:    win.HDI_TEXT | win.HDI_WIDTH | win.HDI_FORMAT,
+		Cxy:     int32(hb.IntFrom96DPI(width)),
+		PszText: &text[0],
+		Fmt:     win.HDF_STRING | win.HDF_LEFT,
+	}
+
+	if -1 == int(int32(win.SendMessage(hb.hWndHeader, win.HDM_INSERTITEM, uintptr(index), uintptr(unsafe.Pointer(&item))))) {
+		return newError("SendMessage(HDM_INSERTITEM) failed")
+	}
+
+	if hb.sortColumn >= index {
+		hb.sortColumn++
+	}
+
+	return nil
+}
+
+// RemoveColumn removes the column at index.
+func (hb *HeaderBar) RemoveColumn(index int) error {
+	if 0 == win.SendMessage(hb.hWndHeader, win.HDM_DELETEITEM, uintptr(index), 0) {
+		return newError("SendMessage(HDM_DELETEITEM) failed")
+	}
+
+	if index == hb.sortColumn {
+		hb.sortColumn = -1
+		hb.sortChangedPublisher.Publish()
+	} else if index < hb.sortColumn {
+		hb.sortColumn--
+	}
+
+	return nil
+}
+
+func (hb *HeaderBar) item(index int, mask uint32, buf []uint16) (win.HDITEM, error) {
+	item := win.HDITEM{Mask: mask}
+	if buf != nil {
+		item.PszText = &buf[0]
+		item.CchTextMax = int32(len(buf))
+	}
+
+	if 0 == win.SendMessage(hb.hWndHeader, win.HDM_GETITEM, uintptr(index), uintptr(unsafe.Pointer(&item))) {
+		return item, newError("SendMessage(HDM_GETITEM) failed")
+	}
+
+	return item, nil
+}
+
+func (hb *HeaderBar) setItem(index int, item *win.HDITEM) error {
+	if 0 == win.SendMessage(hb.hWndHeader, win.HDM_SETITEM, uintptr(index), uintptr(unsafe.Pointer(item))) {
+		return newError("SendMessage(HDM_SETITEM) failed")
+	}
+
+	return nil
+}
+
+// ColumnTitle returns the title of the column at index.
+func (hb *HeaderBar) ColumnTitle(index int) string {
+	buf := make([]uint16, 256)
+
+	if _, err := hb.item(index, win.HDI_TEXT, buf); err != nil {
+		return ""
+	}
+
+	return syscall.UTF16ToString(buf)
+}
+
+// SetColumnTitle sets the title of the column at index.
+func (hb *HeaderBar) SetColumnTitle(index int, title string) error {
+	text := syscall.StringToUTF16(title)
+
+	return hb.setItem(index, &win.HDITEM{Mask: win.HDI_TEXT, PszText: &text[0]})
+}
+
+// ColumnWidth returns the width of the column at index in 1/96" units.
+func (hb *HeaderBar) ColumnWidth(index int) int {
+	return hb.IntTo96DPI(hb.ColumnWidthPixels(index))
+}
+
+// ColumnWidthPixels returns the width of the column at index in native
+// pixels.
+func (hb *HeaderBar) ColumnWidthPixels(index int) int {
+	item, err := hb.item(index, win.HDI_WIDTH, nil)
+	if err != nil {
+		return 0
+	}
+
+	return int(item.Cxy)
+}
+
+// SetColumnWidth sets the width of the column at index in 1/96" units.
+func (hb *HeaderBar) SetColumnWidth(index, width int) error {
+	return hb.setItem(index, &win.HDITEM{Mask: win.HDI_WIDTH, Cxy: int32(hb.IntFrom96DPI(width))})
+}
+
+// ColumnAlignment returns the alignment of the title of the column at index.
+func (hb *HeaderBar) ColumnAlignment(index int) Alignment1D {
+	item, err := hb.item(index, win.HDI_FORMAT, nil)
+	if err != nil {
+		return AlignDefault
+	}
+
+	switch item.Fmt & win.HDF_JUSTIFYMASK {
+	case win.HDF_CENTER:
+		return AlignCenter
+
+	case win.HDF_RIGHT:
+		return AlignFar
+	}
+
+	return AlignNear
+}
+
+// SetColumnAlignment sets the alignment of the title of the column at index.
+func (hb *HeaderBar) SetColumnAlignment(index int, alignment Alignment1D) error {
+	item, err := hb.item(index, win.HDI_FORMAT, nil)
+	if err != nil {
+		return err
+	}
+
+	item.Fmt &^= win.HDF_JUSTIFYMASK
+	switch alignment {
+	case AlignCenter:
+		item.Fmt |= win.HDF_CENTER
+
+	case AlignFar:
+		item.Fmt |= win.HDF_RIGHT
+	}
+
+	return hb.setItem(index, &item)
+}
+
+// ColumnOrder returns the indexes of the columns in the order they are
+// displayed.
+func (hb *HeaderBar) ColumnOrder() []int {
+	count := hb.ColumnCount()
+	if count == 0 {
+		return nil
+	}
+
+	order32 := make([]int32, count)
+	if 0 == win.SendMessage(hb.hWndHeader, win.HDM_GETORDERARRAY, uintptr(count), uintptr(unsafe.Pointer(&order32[0]))) {
+		return nil
+	}
+
+	order := make([]int, count)
+	for i, index := range order32 {
+		order[i] = int(index)
+	}
+
+	return order
+}
+
+// SetColumnOrder sets the order in which the columns are displayed, by the
+// indexes of the columns.
+func (hb *HeaderBar) SetColumnOrder(order []int) error {
+	count := hb.ColumnCount()
+	if len(order) != count {
+		return newError("order must contain all columns")
+	}
+	if count == 0 {
+		return nil
+	}
+
+	order32 := make([]int32, count)
+	for i, index := range order {
+		order32[i] = int32(index)
+	}
+
+	if 0 == win.SendMessage(hb.hWndHeader, win.HDM_SETORDERARRAY, uintptr(count), uintptr(unsafe.Pointer(&order32[0]))) {
+		return newError("SendMessage(HDM_SETORDERARRAY) failed")
+	}
+
+	hb.columnsReorderedPublisher.Publish()
+
+	return nil
+}
+
+// ColumnBoundsPixels returns the bounds of the column at index in native
+// pixels, relative to the HeaderBar and taking the scroll offset into account.
+//
+// A view below the HeaderBar with the same x coordinate and scroll offset can
+// draw the column within the horizontal extent of these bounds.
+func (hb *HeaderBar) ColumnBoundsPixels(index int) Rectangle {
+	var rc win.RECT
+	if 0 == win.SendMessage(hb.hWndHeader, win.HDM_GETITEMRECT, uintptr(index), uintptr(unsafe.Pointer(&rc))) {
+		return Rectangle{}
+	}
+
+	r := rectangleFromRECT(rc)
+	r.X -= hb.scrollOffset
+
+	return r
+}
+
+// ContentWidthPixels returns the total width of all columns in native pixels,
+// which a view can use as the range for horizontal scrolling.
+func (hb *HeaderBar) ContentWidthPixels() int {
+	var width int
+
+	for i, n := 0, hb.ColumnCount(); i < n; i++ {
+		width += hb.ColumnWidthPixels(i)
+	}
+
+	return width
+}
+
+// ScrollOffset returns by how many native pixels the columns are scrolled to
+// the left.
+func (hb *HeaderBar) ScrollOffset() int {
+	return hb.scrollOffset
+}
+
+// SetScrollOffset sets by how many native pixels the columns are scrolled to
+// the left. Views call it whenever they scroll horizontally.
+func (hb *HeaderBar) SetScrollOffset(offset int) {
+	if offset < 0 {
+		offset = 0
+	}
+
+	if offset == hb.scrollOffset {
+		return
+	}
+
+	hb.scrollOffset = offset
+
+	hb.updateHeaderBounds()
+}
+
+// Sortable returns if clicking a column changes the sort indicator.
+func (hb *HeaderBar) Sortable() bool {
+	return hb.sortable
+}
+
+// SetSortable sets if clicking a column changes the sort indicator, sorting
+// ascending first and reversing the order on further clicks.
+func (hb *HeaderBar) SetSortable(sortable bool) {
+	hb.sortable = sortable
+}
+
+// SortColumn returns the index of the column with the sort indicator, or -1.
+func (hb *HeaderBar) SortColumn() int {
+	return hb.sortColumn
+}
+
+// SortOrder returns the order the sort indicator shows.
+func (hb *HeaderBar) SortOrder() SortOrder {
+	return hb.sortOrder
+}
+
+// SetSort shows the sort indicator for order on the column at index, or
+// removes it if index is -1.
+func (hb *HeaderBar) SetSort(index int, order SortOrder) error {
+	if index < -1 || index >= hb.ColumnCount() {
+		return newError("invalid index")
+	}
+
+	if index == hb.sortColumn && order == hb.sortOrder {
+		return nil
+	}
+
+	for i, n := 0, hb.ColumnCount(); i < n; i++ {
+		item, err := hb.item(i, win.HDI_FORMAT, nil)
+		if err != nil {
+			return err
+		}
+
+		item.Fmt &^= win.HDF_SORTDOWN | win.HDF_SORTUP
+		if i == index {
+			if order == SortAscending {
+				item.Fmt |= win.HDF_SORTUP
+			} else {
+				item.Fmt |= win.HDF_SORTDOWN
+			}
+		}
+
+		if err := hb.setItem(i, &item); err != nil {
+			return err
+		}
+	}
+
+	hb.sortColumn = index
+	hb.sortOrder = order
+
+	hb.sortChangedPublisher.Publish()
+
+	return nil
+}
+
+// ColumnClicked returns the event that is published with the index of a
+// column the user clicked.
+func (hb *HeaderBar) ColumnClicked() *IntEvent {
+	return hb.columnClickedPublisher.Event()
+}
+
+// ColumnWidthChanged returns the event that is published with the index of a
+// column whose width changed, also while the user drags its divider.
+func (hb *HeaderBar) ColumnWidthChanged() *IntEvent {
+	return hb.columnWidthChangedPublisher.Event()
+}
+
+// ColumnsReordered returns the event that is published after the user dragged
+// a column to another position, or SetColumnOrder was called.
+func (hb *HeaderBar) ColumnsReordered() *Event {
+	return hb.columnsReorderedPublisher.Event()
+}
+
+// SortChanged returns the event that is published when the sort indicator
+// changed.
+func (hb *HeaderBar) SortChanged() *Event {
+	return hb.sortChangedPublisher.Event()
+}
+
+// headerHeight returns the height the header control needs, in native
+// pixels.
+func (hb *HeaderBar) headerHeight() int {
+	rc := win.RECT{Right: 1000, Bottom: 1000}
+	var wp win.WINDOWPOS
+
+	layout := win.HDLAYOUT{Prc: &rc, Pwpos: &wp}
+	win.SendMessage(hb.hWndHeader, win.HDM_LAYOUT, 0, uintptr(unsafe.Pointer(&layout)))
+
+	return int(wp.Cy)
+}
+
+// updateHeaderBounds moves the header control to the left by the scroll
+// offset, so the visible part of the columns matches the view.
+func (hb *HeaderBar) updateHeaderBounds() {
+	cb := hb.ClientBoundsPixels()
+
+	win.SetWindowPos(
+		hb.hWndHeader,
+		0,
+		int32(-hb.scrollOffset),
+		0,
+		int32(cb.Width+hb.scrollOffset),
+		int32(cb.Height),
+		win.SWP_NOZORDER|win.SWP_NOACTIVATE)
+}
+
+func (hb *HeaderBar) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if hb.hWndHeader != 0 {
+		switch msg {
+		case win.WM_WINDOWPOSCHANGED:
+			wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
+
+			if wp.Flags&win.SWP_NOSIZE == 0 {
+				hb.updateHeaderBounds()
+			}
+
+		case win.WM_NOTIFY:
+			nmh := (*nmHeader)(unsafe.Pointer(lParam))
+			if nmh.hdr.HwndFrom != hb.hWndHeader {
+				break
+			}
+
+			switch nmh.hdr.Code {
+			case win.HDN_ITEMCLICK:
+				index := int(nmh.iItem)
+
+				hb.columnClickedPublisher.Publish(index)
+
+				if hb.sortable {
+					order := SortAscending
+					if index == hb.sortColumn && hb.sortOrder == SortAscending {
+						order = SortDescending
+					}
+
+					hb.SetSort(index, order)
+				}
+
+			case win.HDN_ITEMCHANGED:
+				if nmh.pitem != nil && nmh.pitem.Mask&win.HDI_WIDTH != 0 {
+					hb.columnWidthChangedPublisher.Publish(int(nmh.iItem))
+				}
+
+			case win.HDN_ENDDRAG:
+				// The header control updates the order after the notification.
+				hb.Synchronize(hb.columnsReorderedPublisher.Publish)
+			}
+		}
+	}
+
+	return hb.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (hb *HeaderBar) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	height := hb.headerHeight()
+
+	return &headerBarLayoutItem{
+		idealSize: Size{hb.dialogBaseUnitsToPixels(Size{50, 0}).Width, height},
+		minSize:   Size{0, height},
+	}
+}
+
+type headerBarLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+	minSize   Size // in native pixels
+}
+
+func (*headerBarLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | GrowableHorz | GreedyHorz
+}
+
+func (li *headerBarLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *headerBarLayoutItem) MinSize() Size {
+	return li.minSize
+}
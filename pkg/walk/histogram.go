@@ -0,0 +1,592 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"strconv"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const histogramWindowClass = `\o/ Walk_Histogram_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(histogramWindowClass)
+	})
+}
+
+// Histogram is a widget that displays counts or levels as a row of bars,
+// e.g. a weight distribution or audio levels.
+//
+// The bars are rendered into cached bitmaps, so that hovering and selecting
+// does not redraw thousands of bars. If there are more bins than pixels, each
+// pixel column shows the largest value of its bins.
+//
+// Hovering a bar shows a readout of its value range and value. Dragging over
+// the bars selects a range of bins, pressing Escape clears the selection.
+type Histogram struct {
+	WidgetBase
+	bins                       []float64
+	minValue                   float64
+	maxValue                   float64
+	logScale                   bool
+	hoverIndex                 int
+	trackingMouseEvent         bool
+	selFirst                   int
+	selLast                    int
+	selAnchor                  int
+	selecting                  bool
+	cache                      *Bitmap
+	selectedCache              *Bitmap
+	hoverIndexChangedPublisher EventPublisher
+	selectionChangedPublisher  EventPublisher
+}
+
+// NewHistogram creates and initializes a new Histogram.
+func NewHistogram(parent Container) (*Histogram, error) {
+	h := &Histogram{
+		maxValue:   1,
+		hoverIndex: -1,
+		selFirst:   -1,
+		selLast:    -1,
+	}
+
+	if err := InitWidget(
+		h,
+		parent,
+		histogramWindowClass,
+		win.WS_TABSTOP|win.WS_VISIBLE,
+		0); err != nil {
+		return nil, err
+	}
+
+	h.GraphicsEffects().Add(InteractionEffect)
+	h.GraphicsEffects().Add(FocusEffect)
+
+	return h, nil
+}
+
+func (h *Histogram) Dispose() {
+	h.invalidateCache()
+
+	h.WidgetBase.Dispose()
+}
+
+// Bins returns the values of the bins.
+func (h *Histogram) Bins() []float64 {
+	return append([]float64(nil), h.bins...)
+}
+
+// SetBins sets the values of the bins. Negative values are shown as 0.
+func (h *Histogram) SetBins(bins []float64) {
+	h.bins = append([]float64(nil), bins...)
+
+	h.binsChanged()
+}
+
+// SetSamples counts samples into binCount bins of equal width, spanning the
+// range from the smallest to the largest sample, which becomes the value range
+// of the Histogram.
+func (h *Histogram) SetSamples(samples []float64, binCount int) error {
+	if binCount < 1 {
+		return newError("binCount must >= 1")
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, s := range samples {
+		if math.IsNaN(s) || math.IsInf(s, 0) {
+			continue
+		}
+
+		min = math.Min(min, s)
+		max = math.Max(max, s)
+	}
+
+	if min > max {
+		min, max = 0, 1
+	} else if min == max {
+		max = min + 1
+	}
+
+	bins := make([]float64, binCount)
+	for _, s := range samples {
+		if math.IsNaN(s) || math.IsInf(s, 0) {
+			continue
+		}
+
+		i := int((s - min) / (max - min) * float64(binCount))
+		if i == binCount {
+			i--
+		}
+		bins[i]++
+	}
+
+	h.bins = bins
+	h.minValue, h.maxValue = min, max
+
+	h.binsChanged()
+
+	return nil
+}
+
+func (h *Histogram) binsChanged() {
+	if h.hoverIndex >= len(h.bins) {
+		h.setHoverIndex(-1)
+	}
+
+	if h.selLast >= len(h.bins) {
+		h.ClearSelection()
+	}
+
+	h.invalidateCache()
+	h.Invalidate()
+}
+
+// ValueRange returns the range of values spanned by the bins, which is used
+// for the readout.
+func (h *Histogram) ValueRange() (min, max float64) {
+	return h.minValue, h.maxValue
+}
+
+// SetValueRange sets the range of values spanned by the bins, which is used
+// for the readout.
+func (h *Histogram) SetValueRange(min, max float64) error {
+	if !(min < max) {
+		return newError("min must < max")
+	}
+
+	h.minValue, h.maxValue = min, max
+
+	h.Invalidate()
+
+	return nil
+}
+
+// BinRange returns the range of values of the bin at index.
+func (h *Histogram) BinRange(index int) (lower, upper float64) {
+	width := (h.maxValue - h.minValue) / float64(maxi(1, len(h.bins)))
+
+	return h.minValue + float64(index)*width, h.minValue + float64(index+1)*width
+}
+
+// LogScale returns whether the bar heights are scaled logarithmically.
+func (h *Histogram) LogScale() bool {
+	return h.logScale
+}
+
+// SetLogScale sets whether the bar heights are scaled logarithmically, which
+// helps when few bins dominate.
+func (h *Histogram) SetLogScale(logScale bool) {
+	if logScale == h.logScale {
+		return
+	}
+
+	h.logScale = logScale
+
+	h.invalidateCache()
+	h.Invalidate()
+}
+
+// HoverIndex returns the index of the bin under the mouse pointer or -1.
+func (h *Histogram) HoverIndex() int {
+	return h.hoverIndex
+}
+
+// HoverIndexChanged returns the event that is published when the bin under
+// the mouse pointer changed.
+func (h *Histogram) HoverIndexChanged() *Event {
+	return h.hoverIndexChangedPublisher.Event()
+}
+
+func (h *Histogram) setHoverIndex(index int) {
+	if index == h.hoverIndex {
+		return
+	}
+
+	h.hoverIndex = index
+
+	h.Invalidate()
+
+	h.hoverIndexChangedPublisher.Publish()
+}
+
+// Selection returns the first and last index of the selected range of bins,
+// or -1, -1 if nothing is selected.
+func (h *Histogram) Selection() (first, last int) {
+	return h.selFirst, h.selLast
+}
+
+// SetSelection selects the bins from first to last, inclusive.
+func (h *Histogram) SetSelection(first, last int) error {
+	if first > last {
+		first, last = last, first
+	}
+
+	if first < 0 || last >= len(h.bins) {
+		return newError("index out of range")
+	}
+
+	h.setSelection(first, last)
+
+	return nil
+}
+
+// ClearSelection clears the selection.
+func (h *Histogram) ClearSelection() {
+	h.setSelection(-1, -1)
+}
+
+func (h *Histogram) setSelection(first, last int) {
+	if first == h.selFirst && last == h.selLast {
+		return
+	}
+
+	h.selFirst, h.selLast = first, last
+
+	h.Invalidate()
+
+	h.selectionChangedPublisher.Publish()
+}
+
+// SelectionChanged returns the event that is published when the selected
+// range of bins changed.
+func (h *Histogram) SelectionChanged() *Event {
+	return h.selectionChangedPublisher.Event()
+}
+
+func (h *Histogram) invalidateCache() {
+	if h.cache != nil {
+		h.cache.Dispose()
+		h.cache = nil
+	}
+	if h.selectedCache != nil {
+		h.selectedCache.Dispose()
+		h.selectedCache = nil
+	}
+}
+
+// readoutHeight returns the height of the readout strip above the bars in
+// native pixels.
+func (h *Histogram) readoutHeight() int {
+	return IntFrom96DPI(16, h.DPI())
+}
+
+// barsBounds returns the bounds of the bar area in native pixels.
+func (h *Histogram) barsBounds() Rectangle {
+	cb := h.ClientBoundsPixels()
+	rh := h.readoutHeight()
+
+	return Rectangle{0, rh, cb.Width, cb.Height - rh}
+}
+
+func (h *Histogram) binAt(x int) int {
+	n := len(h.bins)
+	bounds := h.barsBounds()
+	if n == 0 || bounds.Width <= 0 {
+		return -1
+	}
+
+	i := (x - bounds.X) * n / bounds.Width
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+
+	return i
+}
+
+// binSpan returns the horizontal extent of the bins from first to last in
+// native pixels, relative to the bar area.
+func (h *Histogram) binSpan(first, last, width int) (x0, x1 int) {
+	n := len(h.bins)
+
+	x0 = first * width / n
+	x1 = (last + 1) * width / n
+	if x1 <= x0 {
+		x1 = x0 + 1
+	}
+
+	return
+}
+
+func (h *Histogram) scale(v float64) float64 {
+	if v <= 0 || math.IsNaN(v) {
+		return 0
+	}
+
+	if h.logScale {
+		return math.Log10(1 + v)
+	}
+
+	return v
+}
+
+func (h *Histogram) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, h.DPI(), h.paint) {
+			return 0
+		}
+
+	case win.WM_SIZE:
+		h.invalidateCache()
+		h.Invalidate()
+
+	case win.WM_LBUTTONDOWN:
+		if !h.Enabled() {
+			break
+		}
+
+		h.SetFocus()
+
+		if i := h.binAt(int(win.GET_X_LPARAM(lParam))); i > -1 {
+			win.SetCapture(hwnd)
+			h.selecting = true
+			h.selAnchor = i
+			h.setSelection(i, i)
+		}
+
+	case win.WM_MOUSEMOVE:
+		if !h.trackingMouseEvent {
+			var tme win.TRACKMOUSEEVENT
+			tme.CbSize = uint32(unsafe.Sizeof(tme))
+			tme.DwFlags = win.TME_LEAVE
+			tme.HwndTrack = hwnd
+
+			h.trackingMouseEvent = win.TrackMouseEvent(&tme)
+		}
+
+		i := h.binAt(int(win.GET_X_LPARAM(lParam)))
+		h.setHoverIndex(i)
+
+		if h.selecting && i > -1 {
+			h.setSelection(mini(h.selAnchor, i), maxi(h.selAnchor, i))
+		}
+
+	case win.WM_MOUSELEAVE:
+		h.trackingMouseEvent = false
+		h.setHoverIndex(-1)
+
+	case win.WM_LBUTTONUP:
+		if h.selecting {
+			h.selecting = false
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		h.selecting = false
+
+	case win.WM_KEYDOWN:
+		if Key(wParam) == KeyEscape {
+			h.ClearSelection()
+		}
+	}
+
+	return h.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+// renderBars draws the bars into a new bitmap of the size of bounds.
+func (h *Histogram) renderBars(bounds Rectangle, bg, bar Color) (*Bitmap, error) {
+	bmp, err := NewBitmapForDPI(bounds.Size(), h.DPI())
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			bmp.Dispose()
+		}
+	}()
+
+	canvas, err := NewCanvasFromImage(bmp)
+	if err != nil {
+		return nil, err
+	}
+	defer canvas.Dispose()
+
+	bgBrush, err := NewSolidColorBrush(bg)
+	if err != nil {
+		return nil, err
+	}
+	defer bgBrush.Dispose()
+
+	barBrush, err := NewSolidColorBrush(bar)
+	if err != nil {
+		return nil, err
+	}
+	defer barBrush.Dispose()
+
+	width, height := bounds.Width, bounds.Height
+
+	if err := canvas.FillRectanglePixels(bgBrush, Rectangle{0, 0, width, height}); err != nil {
+		return nil, err
+	}
+
+	n := len(h.bins)
+
+	var top float64
+	for _, v := range h.bins {
+		top = math.Max(top, h.scale(v))
+	}
+	if n == 0 || top == 0 {
+		succeeded = true
+		return bmp, nil
+	}
+
+	barHeight := func(v float64) int {
+		return int(math.Round(h.scale(v) / top * float64(height)))
+	}
+
+	if n*3 <= width {
+		// Wide bins get a bar each, separated by a gap.
+		for i, v := range h.bins {
+			x0, x1 := h.binSpan(i, i, width)
+
+			if bh := barHeight(v); bh > 0 {
+				if err := canvas.FillRectanglePixels(barBrush, Rectangle{x0, height - bh, x1 - x0 - 1, bh}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	} else {
+		// Narrow bins are aggregated per pixel column.
+		for x := 0; x < width; x++ {
+			first := x * n / width
+			last := maxi(first+1, (x+1)*n/width)
+
+			var v float64
+			for _, b := range h.bins[first:mini(last, n)] {
+				v = math.Max(v, b)
+			}
+
+			if bh := barHeight(v); bh > 0 {
+				if err := canvas.FillRectanglePixels(barBrush, Rectangle{x, height - bh, 1, bh}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	succeeded = true
+
+	return bmp, nil
+}
+
+func (h *Histogram) ensureCache(bounds Rectangle) error {
+	if h.cache != nil && h.cache.Size() == bounds.Size() {
+		return nil
+	}
+
+	h.invalidateCache()
+
+	window := Color(win.GetSysColor(win.COLOR_WINDOW))
+	highlight := Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
+
+	bar := Color(win.GetSysColor(win.COLOR_BTNSHADOW))
+
+	// A light tint of the highlight color for the selected background.
+	tint := window.Blend(highlight, 0.25)
+
+	cache, err := h.renderBars(bounds, window, bar)
+	if err != nil {
+		return err
+	}
+
+	selectedCache, err := h.renderBars(bounds, tint, highlight)
+	if err != nil {
+		cache.Dispose()
+		return err
+	}
+
+	h.cache, h.selectedCache = cache, selectedCache
+
+	return nil
+}
+
+func blitBitmapPart(hdc win.HDC, bmp *Bitmap, dst Point, src Rectangle) error {
+	return bmp.withSelectedIntoMemDC(func(hdcMem win.HDC) error {
+		if !win.BitBlt(hdc, int32(dst.X), int32(dst.Y), int32(src.Width), int32(src.Height), hdcMem, int32(src.X), int32(src.Y), win.SRCCOPY) {
+			return newError("BitBlt failed")
+		}
+
+		return nil
+	})
+}
+
+func (h *Histogram) paint(canvas *Canvas, bounds Rectangle) error {
+	windowBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_WINDOW)))
+	if err != nil {
+		return err
+	}
+	defer windowBrush.Dispose()
+
+	if err := canvas.FillRectanglePixels(windowBrush, bounds); err != nil {
+		return err
+	}
+
+	bars := h.barsBounds()
+	if bars.Width <= 0 || bars.Height <= 0 {
+		return nil
+	}
+
+	if err := h.ensureCache(bars); err != nil {
+		return err
+	}
+
+	if err := blitBitmapPart(canvas.hdc, h.cache, bars.Location(), Rectangle{0, 0, bars.Width, bars.Height}); err != nil {
+		return err
+	}
+
+	if h.selFirst > -1 {
+		x0, x1 := h.binSpan(h.selFirst, h.selLast, bars.Width)
+
+		if err := blitBitmapPart(canvas.hdc, h.selectedCache, Point{bars.X + x0, bars.Y}, Rectangle{x0, 0, x1 - x0, bars.Height}); err != nil {
+			return err
+		}
+	}
+
+	if h.hoverIndex < 0 {
+		return nil
+	}
+
+	x0, x1 := h.binSpan(h.hoverIndex, h.hoverIndex, bars.Width)
+
+	hoverPen, err := NewCosmeticPen(PenDot, Color(win.GetSysColor(win.COLOR_WINDOWTEXT)))
+	if err != nil {
+		return err
+	}
+	defer hoverPen.Dispose()
+
+	if err := canvas.DrawRectanglePixels(hoverPen, Rectangle{bars.X + x0, bars.Y, x1 - x0, bars.Height}); err != nil {
+		return err
+	}
+
+	lower, upper := h.BinRange(h.hoverIndex)
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', 4, 64)
+	}
+	text := "[" + format(lower) + ", " + format(upper) + "): " + format(h.bins[h.hoverIndex])
+
+	padding := IntFrom96DPI(4, h.DPI())
+	readout := Rectangle{padding, 0, bounds.Width - 2*padding, h.readoutHeight()}
+
+	return canvas.DrawTextPixels(text, h.Font(), Color(win.GetSysColor(win.COLOR_WINDOWTEXT)), readout, TextLeft|TextVCenter|TextSingleLine|TextEndEllipsis)
+}
+
+func (*Histogram) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return NewGreedyLayoutItem()
+}
@@ -7,6 +7,10 @@
 
 package walk
 
+import (
+	"github.com/miu200521358/win"
+)
+
 var iconCache *IconCache
 
 func init() {
@@ -18,6 +22,7 @@ func init() {
 type IconCache struct {
 	imageAndDPI2Bitmap map[imageAndDPI]*Bitmap
 	imageAndDPI2Icon   map[imageAndDPI]*Icon
+	disabledKey2Bitmap map[disabledImageKey]*Bitmap
 }
 
 type imageAndDPI struct {
@@ -25,10 +30,17 @@ type imageAndDPI struct {
 	dpi   int
 }
 
+type disabledImageKey struct {
+	image Image
+	size  Size
+	dark  bool
+}
+
 func NewIconCache() *IconCache {
 	return &IconCache{
 		imageAndDPI2Bitmap: make(map[imageAndDPI]*Bitmap),
 		imageAndDPI2Icon:   make(map[imageAndDPI]*Icon),
+		disabledKey2Bitmap: make(map[disabledImageKey]*Bitmap),
 	}
 }
 
@@ -41,6 +53,10 @@ func (ic *IconCache) Clear() {
 		ico.Dispose()
 		delete(ic.imageAndDPI2Icon, key)
 	}
+	for key, bmp := range ic.disabledKey2Bitmap {
+		bmp.Dispose()
+		delete(ic.disabledKey2Bitmap, key)
+	}
 }
 
 func (ic *IconCache) Dispose() {
@@ -89,3 +105,58 @@ func (ic *IconCache) Icon(image Image, dpi int) (*Icon, error) {
 
 	return ico, nil
 }
+
+// DisabledBitmap returns a faded grayscale variant of image for dpi, as menus,
+// tool bars and ImageButtons show it for disabled items. The variant is
+// cached per DPI and for dark and light themes.
+func (ic *IconCache) DisabledBitmap(image Image, dpi int) (*Bitmap, error) {
+	return ic.disabledBitmap(image, SizeFrom96DPI(image.Size(), dpi))
+}
+
+// disabledBitmap returns the faded grayscale variant of image in size, in
+// native pixels.
+func (ic *IconCache) disabledBitmap(image Image, size Size) (*Bitmap, error) {
+	dark := themeDark()
+	key := disabledImageKey{image, size, dark}
+
+	if bmp, ok := ic.disabledKey2Bitmap[key]; ok {
+		return bmp, nil
+	}
+
+	bmp, err := NewBitmapFromImageWithSize(image, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bmp.withPixels(func(bi *win.BITMAPINFO, hdc win.HDC, pixels *[maxPixels]bgraPixel, pixelsLen int) error {
+		for i := 0; i < pixelsLen; i++ {
+			p := &pixels[i]
+
+			// The pixels are premultiplied, so the gray must not exceed the
+			// alpha, and fading scales all channels alike.
+			gray := (int(p.R)*30 + int(p.G)*59 + int(p.B)*11) / 100
+			if dark {
+				// Lifts dark glyphs, which would vanish on dark backgrounds.
+				gray = int(p.A)/4 + gray*3/4
+			}
+
+			p.R = byte(gray / 2)
+			p.G = p.R
+			p.B = p.R
+			p.A /= 2
+		}
+
+		if 0 == win.SetDIBits(hdc, bmp.hBmp, 0, uint32(bi.BmiHeader.BiHeight), &pixels[0].B, bi, win.DIB_RGB_COLORS) {
+			return newError("SetDIBits")
+		}
+
+		return nil
+	}); err != nil {
+		bmp.Dispose()
+		return nil, err
+	}
+
+	ic.disabledKey2Bitmap[key] = bmp
+
+	return bmp, nil
+}
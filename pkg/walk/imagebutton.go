@@ -0,0 +1,512 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// ImageButtonState identifies the state of an ImageButton an image is shown
+// for.
+type ImageButtonState int
+
+const (
+	ImageButtonNormal ImageButtonState = iota
+	ImageButtonHot
+	ImageButtonPressed
+	ImageButtonDisabled
+	ImageButtonChecked
+)
+
+type imageButtonVariant struct {
+	dpi   int
+	image Image
+}
+
+// ImageButton is a button that only shows an image, with optional images for
+// its hot, pressed, disabled and checked states, e.g. for the dense tool heads
+// of editors.
+//
+// Without an image of its own, the hot, pressed and checked states are shown
+// by drawing a frame behind the normal image, and the disabled state by
+// drawing a faded grayscale version of it.
+//
+// Images can be provided for several DPIs, in which case the one that fits
+// the DPI of the ImageButton best is shown.
+type ImageButton struct {
+	*CustomWidget
+	state2Variants          map[ImageButtonState][]imageButtonVariant
+	imageSize               Size // in 1/96", zero for the size of the normal image
+	checkable               bool
+	checked                 bool
+	hot                     bool
+	pressed                 bool
+	trackingMouseEvent      bool
+	clickedPublisher        EventPublisher
+	checkedChangedPublisher EventPublisher
+	imageChangedPublisher   EventPublisher
+}
+
+// NewImageButton creates and initializes a new ImageButton.
+func NewImageButton(parent Container) (*ImageButton, error) {
+	ib := &ImageButton{state2Variants: make(map[ImageButtonState][]imageButtonVariant)}
+
+	cw, err := NewCustomWidgetPixels(parent, win.WS_TABSTOP, func(canvas *Canvas, updateBounds Rectangle) error {
+		return ib.paint(canvas)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			cw.Dispose()
+		}
+	}()
+
+	ib.CustomWidget = cw
+
+	if err := InitWrapperWindow(ib); err != nil {
+		return nil, err
+	}
+
+	ib.SetInvalidatesOnResize(true)
+
+	ib.GraphicsEffects().Add(FocusEffect)
+
+	ib.MustRegisterProperty("Image", NewProperty(
+		func() interface{} {
+			return ib.Image(ImageButtonNormal)
+		},
+		func(v interface{}) error {
+			img, err := ImageFrom(v)
+			if err != nil {
+				return err
+			}
+
+			return ib.SetImage(ImageButtonNormal, img)
+		},
+		ib.imageChangedPublisher.Event()))
+
+	ib.MustRegisterProperty("Checked", NewBoolProperty(
+		func() bool {
+			return ib.Checked()
+		},
+		func(v bool) error {
+			ib.SetChecked(v)
+			return nil
+		},
+		ib.checkedChangedPublisher.Event()))
+
+	succeeded = true
+
+	return ib, nil
+}
+
+// Image returns the image shown for state at 96dpi, or nil.
+func (ib *ImageButton) Image(state ImageButtonState) Image {
+	return ib.ImageForDPI(state, 96)
+}
+
+// SetImage sets the image shown for state. Use nil to remove it.
+//
+// The ImageButton doesn't take ownership of the image.
+func (ib *ImageButton) SetImage(state ImageButtonState, image Image) error {
+	return ib.SetImageForDPI(state, 96, image)
+}
+
+// ImageForDPI returns the image that is shown for state at dpi, or nil. This
+// is the variant for the smallest DPI >= dpi, or else the one for the largest
+// DPI.
+func (ib *ImageButton) ImageForDPI(state ImageButtonState, dpi int) Image {
+	var best *imageButtonVariant
+
+	for i, v := range ib.state2Variants[state] {
+		switch {
+		case best == nil:
+			best = &ib.state2Variants[state][i]
+
+		case v.dpi >= dpi && (best.dpi < dpi || v.dpi < best.dpi):
+			best = &ib.state2Variants[state][i]
+
+		case v.dpi < dpi && best.dpi < dpi && v.dpi > best.dpi:
+			best = &ib.state2Variants[state][i]
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	return best.image
+}
+
+// SetImageForDPI sets the image shown for state at dpi, e.g. a bitmap with
+// twice the pixels for 192dpi. Use nil to remove it.
+//
+// The ImageButton doesn't take ownership of the image.
+func (ib *ImageButton) SetImageForDPI(state ImageButtonState, dpi int, image Image) error {
+	if state < ImageButtonNormal || state > ImageButtonChecked {
+		return newError("invalid state")
+	}
+	if dpi <= 0 {
+		return newError("dpi must be > 0")
+	}
+
+	variants := ib.state2Variants[state]
+
+	index := -1
+	for i, v := range variants {
+		if v.dpi == dpi {
+			index = i
+			break
+		}
+	}
+
+	switch {
+	case image == nil && index > -1:
+		variants = append(variants[:index], variants[index+1:]...)
+
+	case image == nil:
+		return nil
+
+	case index > -1:
+		variants[index].image = image
+
+	default:
+		variants = append(variants, imageButtonVariant{dpi, image})
+	}
+
+	ib.state2Variants[state] = variants
+
+	ib.RequestLayout()
+	ib.Invalidate()
+
+	if state == ImageButtonNormal {
+		ib.imageChangedPublisher.Publish()
+	}
+
+	return nil
+}
+
+// ImageSize returns the size the images are drawn at in 1/96". A zero size
+// means the size of the normal image is used.
+func (ib *ImageButton) ImageSize() Size {
+	return ib.imageSize
+}
+
+// SetImageSize sets the size the images are drawn at in 1/96". A zero size
+// means the size of the normal image is used.
+func (ib *ImageButton) SetImageSize(size Size) error {
+	if size.Width < 0 || size.Height < 0 {
+		return newError("size must not be negative")
+	}
+
+	if size == ib.imageSize {
+		return nil
+	}
+
+	ib.imageSize = size
+
+	ib.RequestLayout()
+	ib.Invalidate()
+
+	return nil
+}
+
+// Checkable returns whether clicking the ImageButton toggles its checked
+// state.
+func (ib *ImageButton) Checkable() bool {
+	return ib.checkable
+}
+
+// SetCheckable sets whether clicking the ImageButton toggles its checked
+// state.
+func (ib *ImageButton) SetCheckable(checkable bool) {
+	ib.checkable = checkable
+
+	if !checkable {
+		ib.SetChecked(false)
+	}
+}
+
+// Checked returns whether the ImageButton is checked.
+func (ib *ImageButton) Checked() bool {
+	return ib.checked
+}
+
+// SetChecked sets whether the ImageButton is checked.
+func (ib *ImageButton) SetChecked(checked bool) {
+	if checked == ib.checked {
+		return
+	}
+
+	ib.checked = checked
+
+	ib.Invalidate()
+
+	ib.checkedChangedPublisher.Publish()
+}
+
+// CheckedChanged returns the event that is published when the checked state
+// of the ImageButton changed.
+func (ib *ImageButton) CheckedChanged() *Event {
+	return ib.checkedChangedPublisher.Event()
+}
+
+// Clicked returns the event that is published when the ImageButton was
+// clicked or activated with Space or Enter.
+func (ib *ImageButton) Clicked() *Event {
+	return ib.clickedPublisher.Event()
+}
+
+func (ib *ImageButton) click() {
+	if ib.checkable {
+		ib.SetChecked(!ib.checked)
+	}
+
+	ib.clickedPublisher.Publish()
+}
+
+func (ib *ImageButton) setHot(hot bool) {
+	if hot != ib.hot {
+		ib.hot = hot
+		ib.Invalidate()
+	}
+}
+
+func (ib *ImageButton) setPressed(pressed bool) {
+	if pressed != ib.pressed {
+		ib.pressed = pressed
+		ib.Invalidate()
+	}
+}
+
+func (ib *ImageButton) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_MOUSEMOVE:
+		if !ib.Enabled() {
+			break
+		}
+
+		if !ib.trackingMouseEvent {
+			var tme win.TRACKMOUSEEVENT
+			tme.CbSize = uint32(unsafe.Sizeof(tme))
+			tme.DwFlags = win.TME_LEAVE
+			tme.HwndTrack = hwnd
+
+			ib.trackingMouseEvent = win.TrackMouseEvent(&tme)
+		}
+
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+		cb := ib.ClientBoundsPixels()
+		inside := x >= 0 && y >= 0 && x < cb.Width && y < cb.Height
+
+		ib.setHot(inside)
+		if hasCapture(hwnd) {
+			ib.setPressed(inside)
+		}
+
+	case win.WM_MOUSELEAVE:
+		ib.trackingMouseEvent = false
+		ib.setHot(false)
+
+	case win.WM_LBUTTONDOWN, win.WM_LBUTTONDBLCLK:
+		if !ib.Enabled() {
+			break
+		}
+
+		ib.SetFocus()
+		ib.setPressed(true)
+		win.SetCapture(hwnd)
+
+	case win.WM_LBUTTONUP:
+		if !hasCapture(hwnd) {
+			break
+		}
+
+		pressed := ib.pressed
+		win.ReleaseCapture()
+
+		if pressed {
+			ib.click()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		ib.setPressed(false)
+
+	case win.WM_KEYDOWN:
+		if !ib.Enabled() {
+			break
+		}
+
+		switch Key(wParam) {
+		case KeySpace:
+			ib.setPressed(true)
+
+		case KeyReturn:
+			ib.click()
+		}
+
+	case win.WM_KEYUP:
+		if Key(wParam) == KeySpace && ib.pressed {
+			ib.setPressed(false)
+			ib.click()
+		}
+
+	case win.WM_KILLFOCUS:
+		if !hasCapture(hwnd) {
+			ib.setPressed(false)
+		}
+
+	case win.WM_ENABLE:
+		ib.setHot(false)
+		ib.setPressed(false)
+		ib.Invalidate()
+	}
+
+	return ib.CustomWidget.WndProc(hwnd, msg, wParam, lParam)
+}
+
+// imageSizePixels returns the size the images are drawn at in native pixels.
+func (ib *ImageButton) imageSizePixels(dpi int) Size {
+	size := ib.imageSize
+
+	if size.Width == 0 || size.Height == 0 {
+		if img := ib.ImageForDPI(ImageButtonNormal, dpi); img != nil {
+			size = img.Size()
+		} else {
+			size = Size{16, 16}
+		}
+	}
+
+	return SizeFrom96DPI(size, dpi)
+}
+
+func (ib *ImageButton) paint(canvas *Canvas) error {
+	bounds := ib.ClientBoundsPixels()
+	dpi := ib.DPI()
+
+	base := ib.ImageForDPI(ImageButtonNormal, dpi)
+	if ib.checked {
+		if img := ib.ImageForDPI(ImageButtonChecked, dpi); img != nil {
+			base = img
+		}
+	}
+
+	var image Image
+	var frame bool
+	var offset int
+
+	switch {
+	case !ib.Enabled():
+		if image = ib.ImageForDPI(ImageButtonDisabled, dpi); image == nil && base != nil {
+			size := ib.imageSizePixels(dpi)
+
+			bmp, err := iconCache.disabledBitmap(base, size)
+			if err != nil {
+				return err
+			}
+
+			return canvas.DrawBitmapWithOpacityPixels(bmp, ib.imageBounds(bounds, size, 0), 255)
+		}
+
+	case ib.pressed:
+		if image = ib.ImageForDPI(ImageButtonPressed, dpi); image == nil {
+			image, frame, offset = base, true, IntFrom96DPI(1, dpi)
+		}
+
+	case ib.hot:
+		if image = ib.ImageForDPI(ImageButtonHot, dpi); image == nil {
+			image, frame = base, true
+		}
+
+	default:
+		image = base
+		frame = ib.checked && ib.ImageForDPI(ImageButtonChecked, dpi) == nil
+	}
+
+	if frame {
+		if err := ib.paintFrame(canvas, bounds); err != nil {
+			return err
+		}
+	}
+
+	if image == nil {
+		return nil
+	}
+
+	size := ib.imageSizePixels(dpi)
+
+	return canvas.DrawImageStretchedPixels(image, ib.imageBounds(bounds, size, offset))
+}
+
+// imageBounds returns the bounds of an image of size centered in bounds and
+// moved by offset to the bottom right.
+func (ib *ImageButton) imageBounds(bounds Rectangle, size Size, offset int) Rectangle {
+	return Rectangle{
+		bounds.X + (bounds.Width-size.Width)/2 + offset,
+		bounds.Y + (bounds.Height-size.Height)/2 + offset,
+		size.Width,
+		size.Height,
+	}
+}
+
+func (ib *ImageButton) paintFrame(canvas *Canvas, bounds Rectangle) error {
+	fillColor := win.COLOR_3DLIGHT
+	if ib.pressed || ib.checked {
+		fillColor = win.COLOR_3DSHADOW
+	}
+
+	brush, err := NewSolidColorBrush(Color(win.GetSysColor(fillColor)))
+	if err != nil {
+		return err
+	}
+	defer brush.Dispose()
+
+	pen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer pen.Dispose()
+
+	r := Rectangle{bounds.X, bounds.Y, bounds.Width - 1, bounds.Height - 1}
+
+	if err := canvas.FillRectanglePixels(brush, r); err != nil {
+		return err
+	}
+
+	return canvas.DrawRectanglePixels(pen, r)
+}
+
+func (ib *ImageButton) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	size := ib.imageSizePixels(ctx.dpi)
+	padding := IntFrom96DPI(4, ctx.dpi)
+
+	return &imageButtonLayoutItem{idealSize: Size{size.Width + 2*padding, size.Height + 2*padding}}
+}
+
+type imageButtonLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+}
+
+func (*imageButtonLayoutItem) LayoutFlags() LayoutFlags {
+	return 0
+}
+
+func (li *imageButtonLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *imageButtonLayoutItem) MinSize() Size {
+	return li.idealSize
+}
@@ -0,0 +1,1015 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const inkCanvasWindowClass = `\o/ Walk_InkCanvas_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(inkCanvasWindowClass)
+	})
+}
+
+const (
+	wmPointerUpdate = 0x0245
+	wmPointerDown   = 0x0246
+	wmPointerUp     = 0x0247
+
+	ptPen = 3
+
+	penFlagInverted = 0x00000002
+	penFlagEraser   = 0x00000004
+
+	penMaskPressure = 0x00000001
+	penMaskTiltX    = 0x00000004
+	penMaskTiltY    = 0x00000008
+)
+
+type pointerInfo struct {
+	pointerType           uint32
+	pointerId             uint32
+	frameId               uint32
+	pointerFlags          uint32
+	sourceDevice          win.HANDLE
+	hwndTarget            win.HWND
+	ptPixelLocation       win.POINT
+	ptHimetricLocation    win.POINT
+	ptPixelLocationRaw    win.POINT
+	ptHimetricLocationRaw win.POINT
+	dwTime                uint32
+	historyCount          uint32
+	inputData             int32
+	dwKeyStates           uint32
+	performanceCount      uint64
+	buttonChangeType      int32
+}
+
+type pointerPenInfo struct {
+	pointerInfo pointerInfo
+	penFlags    uint32
+	penMask     uint32
+	pressure    uint32 // 0 to 1024
+	rotation    uint32
+	tiltX       int32
+	tiltY       int32
+}
+
+var (
+	getPointerType    = syscall.NewLazyDLL("user32.dll").NewProc("GetPointerType")
+	getPointerPenInfo = syscall.NewLazyDLL("user32.dll").NewProc("GetPointerPenInfo")
+)
+
+// penInfoFromWParam returns the pen info of the pointer of a WM_POINTER*
+// message, or false if the pointer is no pen or the system doesn't support
+// pointer input.
+func penInfoFromWParam(wParam uintptr) (info pointerPenInfo, ok bool) {
+	if getPointerType.Find() != nil || getPointerPenInfo.Find() != nil {
+		return info, false
+	}
+
+	id := uintptr(win.LOWORD(uint32(wParam)))
+
+	var pointerType uint32
+	if r, _, _ := getPointerType.Call(id, uintptr(unsafe.Pointer(&pointerType))); r == 0 || pointerType != ptPen {
+		return info, false
+	}
+
+	if r, _, _ := getPointerPenInfo.Call(id, uintptr(unsafe.Pointer(&info))); r == 0 {
+		return info, false
+	}
+
+	return info, true
+}
+
+// InkPoint is a sampled point of an InkStroke.
+type InkPoint struct {
+	// X and Y are relative to the upper left corner of the InkCanvas, in
+	// 1/96" units.
+	X, Y float64
+
+	// Pressure is in the range from 0 to 1. Input without pressure
+	// information, like from a mouse, has a pressure of 1.
+	Pressure float64
+
+	// TiltX and TiltY are the angles of the pen from the vertical, in degrees
+	// in the range from -90 to 90, or 0 if the pen doesn't report them.
+	TiltX, TiltY int
+}
+
+// InkStroke is a stroke drawn on an InkCanvas.
+type InkStroke struct {
+	Points []InkPoint
+	Color  Color
+
+	// Width is the width of the stroke at full pressure, in 1/96" units.
+	Width float64
+}
+
+// Bounds returns the bounds of the points of s, including its width.
+func (s *InkStroke) Bounds() SceneRect {
+	if len(s.Points) == 0 {
+		return SceneRect{}
+	}
+
+	minX, minY := s.Points[0].X, s.Points[0].Y
+	maxX, maxY := minX, minY
+
+	for _, p := range s.Points[1:] {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+
+	half := s.Width / 2
+
+	return SceneRect{minX - half, minY - half, maxX - minX + s.Width, maxY - minY + s.Width}
+}
+
+// Path returns the smoothed polyline the InkCanvas renders for s, in 1/96"
+// units, e.g. to export it as vector data.
+func (s *InkStroke) Path() []ScenePoint {
+	points := smoothInkPoints(s.Points)
+
+	path := make([]ScenePoint, len(points))
+	for i, p := range points {
+		path[i] = ScenePoint{p.X, p.Y}
+	}
+
+	return path
+}
+
+// inkSmoothingSteps is the number of segments each segment between two
+// sampled points is divided into.
+const inkSmoothingSteps = 4
+
+// smoothInkPoints interpolates points with a Catmull-Rom spline, so strokes
+// look smooth even if the input was sampled coarsely.
+func smoothInkPoints(points []InkPoint) []InkPoint {
+	if len(points) < 3 {
+		return points
+	}
+
+	smoothed := make([]InkPoint, 0, (len(points)-1)*inkSmoothingSteps+1)
+
+	at := func(i int) InkPoint {
+		if i < 0 {
+			return points[0]
+		}
+		if i >= len(points) {
+			return points[len(points)-1]
+		}
+		return points[i]
+	}
+
+	for i := 0; i < len(points)-1; i++ {
+		p0, p1, p2, p3 := at(i-1), at(i), at(i+1), at(i+2)
+
+		for step := 0; step < inkSmoothingSteps; step++ {
+			t := float64(step) / inkSmoothingSteps
+			t2, t3 := t*t, t*t*t
+
+			spline := func(v0, v1, v2, v3 float64) float64 {
+				return 0.5 * (2*v1 + (v2-v0)*t + (2*v0-5*v1+4*v2-v3)*t2 + (3*v1-v0-3*v2+v3)*t3)
+			}
+
+			smoothed = append(smoothed, InkPoint{
+				X:        spline(p0.X, p1.X, p2.X, p3.X),
+				Y:        spline(p0.Y, p1.Y, p2.Y, p3.Y),
+				Pressure: p1.Pressure + (p2.Pressure-p1.Pressure)*t,
+				TiltX:    p1.TiltX,
+				TiltY:    p1.TiltY,
+			})
+		}
+	}
+
+	return append(smoothed, points[len(points)-1])
+}
+
+// hitTest returns whether p lies within radius of the stroke.
+func (s *InkStroke) hitTest(p ScenePoint, radius float64) bool {
+	radius += s.Width / 2
+
+	if len(s.Points) == 1 {
+		return math.Hypot(p.X-s.Points[0].X, p.Y-s.Points[0].Y) <= radius
+	}
+
+	for i := 1; i < len(s.Points); i++ {
+		a, b := s.Points[i-1], s.Points[i]
+
+		dx, dy := b.X-a.X, b.Y-a.Y
+
+		var t float64
+		if lenSq := dx*dx + dy*dy; lenSq > 0 {
+			t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/lenSq))
+		}
+
+		if math.Hypot(p.X-(a.X+t*dx), p.Y-(a.Y+t*dy)) <= radius {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *InkStroke) translate(dx, dy float64) {
+	for i := range s.Points {
+		s.Points[i].X += dx
+		s.Points[i].Y += dy
+	}
+}
+
+// InkMode specifies what pen and mouse input does on an InkCanvas.
+type InkMode int
+
+const (
+	// InkModeDraw draws new strokes.
+	InkModeDraw InkMode = iota
+
+	// InkModeErase erases the strokes touched.
+	InkModeErase
+
+	// InkModeSelect selects strokes by clicking them and moves the selected
+	// strokes by dragging them.
+	InkModeSelect
+)
+
+const (
+	inkEraserRadius   = 6 // in 1/96" units
+	inkSelectRadius   = 3 // in 1/96" units
+	inkMinPointLength = 0.5
+)
+
+// InkCanvas is a widget that captures pen strokes, including pressure and
+// tilt, and renders them as smoothed lines whose width follows the pressure.
+// Mouse and touch input draws strokes at full pressure.
+//
+// With an Image set, the strokes annotate the image, which is stretched to
+// the bounds of the InkCanvas. The eraser end of a pen erases regardless of
+// the mode. In InkModeSelect, pressing Delete removes the selected strokes.
+type InkCanvas struct {
+	WidgetBase
+	strokes                   []*InkStroke
+	current                   *InkStroke
+	selected                  map[*InkStroke]bool
+	mode                      InkMode
+	inkColor                  Color
+	inkWidth                  float64
+	image                     Image
+	cache                     *Bitmap
+	tracking                  bool // mouse input
+	erasing                   bool
+	moving                    bool
+	moved                     bool
+	lastPoint                 ScenePoint
+	strokesChangedPublisher   EventPublisher
+	selectionChangedPublisher EventPublisher
+	modeChangedPublisher      EventPublisher
+	imageChangedPublisher     EventPublisher
+}
+
+// NewInkCanvas creates and initializes a new, empty InkCanvas that draws
+// black strokes of 3/96" width.
+func NewInkCanvas(parent Container) (*InkCanvas, error) {
+	ic := &InkCanvas{
+		selected: make(map[*InkStroke]bool),
+		inkWidth: 3,
+	}
+
+	if err := InitWidget(
+		ic,
+		parent,
+		inkCanvasWindowClass,
+		win.WS_VISIBLE,
+		0); err != nil {
+		return nil, err
+	}
+
+	ic.MustRegisterProperty("Image", NewProperty(
+		func() interface{} {
+			return ic.Image()
+		},
+		func(v interface{}) error {
+			img, err := ImageFrom(v)
+			if err != nil {
+				return err
+			}
+
+			ic.SetImage(img)
+
+			return nil
+		},
+		ic.imageChangedPublisher.Event()))
+
+	return ic, nil
+}
+
+func (ic *InkCanvas) Dispose() {
+	ic.invalidateCache()
+
+	ic.WidgetBase.Dispose()
+}
+
+// Mode returns what pen and mouse input does.
+func (ic *InkCanvas) Mode() InkMode {
+	return ic.mode
+}
+
+// SetMode sets what pen and mouse input does.
+func (ic *InkCanvas) SetMode(mode InkMode) {
+	if mode == ic.mode {
+		return
+	}
+
+	ic.cancelInput()
+
+	ic.mode = mode
+
+	if mode != InkModeSelect {
+		ic.ClearSelection()
+	}
+
+	ic.modeChangedPublisher.Publish()
+}
+
+// ModeChanged returns the event that is published when the mode changed.
+func (ic *InkCanvas) ModeChanged() *Event {
+	return ic.modeChangedPublisher.Event()
+}
+
+// InkColor returns the color of new strokes.
+func (ic *InkCanvas) InkColor() Color {
+	return ic.inkColor
+}
+
+// SetInkColor sets the color of new strokes.
+func (ic *InkCanvas) SetInkColor(color Color) {
+	ic.inkColor = color
+}
+
+// InkWidth returns the width of new strokes at full pressure, in 1/96" units.
+func (ic *InkCanvas) InkWidth() float64 {
+	return ic.inkWidth
+}
+
+// SetInkWidth sets the width of new strokes at full pressure, in 1/96" units.
+func (ic *InkCanvas) SetInkWidth(width float64) {
+	ic.inkWidth = math.Max(width, 0.5)
+}
+
+// Image returns the image the strokes are drawn over, or nil.
+func (ic *InkCanvas) Image() Image {
+	return ic.image
+}
+
+// SetImage sets the image the strokes are drawn over, which is stretched to
+// the bounds of the InkCanvas.
+func (ic *InkCanvas) SetImage(image Image) {
+	if image == ic.image {
+		return
+	}
+
+	ic.image = image
+
+	ic.invalidateCache()
+	ic.Invalidate()
+
+	ic.imageChangedPublisher.Publish()
+}
+
+// Strokes returns the strokes, in the order they were drawn.
+func (ic *InkCanvas) Strokes() []*InkStroke {
+	return append([]*InkStroke(nil), ic.strokes...)
+}
+
+// SetStrokes replaces the strokes, e.g. to restore saved annotations.
+func (ic *InkCanvas) SetStrokes(strokes []*InkStroke) {
+	ic.cancelInput()
+
+	ic.strokes = append([]*InkStroke(nil), strokes...)
+
+	ic.ClearSelection()
+	ic.strokesChanged()
+}
+
+// AddStroke adds stroke on top of the other strokes.
+func (ic *InkCanvas) AddStroke(stroke *InkStroke) {
+	ic.strokes = append(ic.strokes, stroke)
+
+	ic.strokesChanged()
+}
+
+// RemoveStroke removes stroke.
+func (ic *InkCanvas) RemoveStroke(stroke *InkStroke) {
+	if ic.removeStroke(stroke) {
+		ic.strokesChanged()
+	}
+}
+
+func (ic *InkCanvas) removeStroke(stroke *InkStroke) bool {
+	for i, s := range ic.strokes {
+		if s == stroke {
+			ic.strokes = append(ic.strokes[:i], ic.strokes[i+1:]...)
+
+			if ic.selected[stroke] {
+				delete(ic.selected, stroke)
+				ic.selectionChangedPublisher.Publish()
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Clear removes all strokes.
+func (ic *InkCanvas) Clear() {
+	if len(ic.strokes) == 0 {
+		return
+	}
+
+	ic.SetStrokes(nil)
+}
+
+// StrokesChanged returns the event that is published when strokes were added,
+// erased, moved or replaced.
+func (ic *InkCanvas) StrokesChanged() *Event {
+	return ic.strokesChangedPublisher.Event()
+}
+
+func (ic *InkCanvas) strokesChanged() {
+	ic.invalidateCache()
+	ic.Invalidate()
+
+	ic.strokesChangedPublisher.Publish()
+}
+
+// StrokeAt returns the topmost stroke at p, in 1/96" units, or nil.
+func (ic *InkCanvas) StrokeAt(p ScenePoint) *InkStroke {
+	for i := len(ic.strokes) - 1; i >= 0; i-- {
+		if s := ic.strokes[i]; s.hitTest(p, inkSelectRadius) {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// SelectedStrokes returns the selected strokes, in the order they were drawn.
+func (ic *InkCanvas) SelectedStrokes() []*InkStroke {
+	var strokes []*InkStroke
+
+	for _, s := range ic.strokes {
+		if ic.selected[s] {
+			strokes = append(strokes, s)
+		}
+	}
+
+	return strokes
+}
+
+// SetSelectedStrokes selects strokes and deselects the others.
+func (ic *InkCanvas) SetSelectedStrokes(strokes []*InkStroke) {
+	ic.selected = make(map[*InkStroke]bool)
+	for _, s := range strokes {
+		ic.selected[s] = true
+	}
+
+	ic.Invalidate()
+
+	ic.selectionChangedPublisher.Publish()
+}
+
+// ClearSelection deselects all strokes.
+func (ic *InkCanvas) ClearSelection() {
+	if len(ic.selected) == 0 {
+		return
+	}
+
+	ic.SetSelectedStrokes(nil)
+}
+
+// DeleteSelection removes the selected strokes.
+func (ic *InkCanvas) DeleteSelection() {
+	if len(ic.selected) == 0 {
+		return
+	}
+
+	var strokes []*InkStroke
+	for _, s := range ic.strokes {
+		if !ic.selected[s] {
+			strokes = append(strokes, s)
+		}
+	}
+	ic.strokes = strokes
+
+	ic.ClearSelection()
+	ic.strokesChanged()
+}
+
+// SelectionChanged returns the event that is published when the selection
+// changed.
+func (ic *InkCanvas) SelectionChanged() *Event {
+	return ic.selectionChangedPublisher.Event()
+}
+
+// ExportBitmap returns a new Bitmap for dpi of the image, or the background,
+// with the strokes painted over it, as large as the InkCanvas.
+func (ic *InkCanvas) ExportBitmap(dpi int) (*Bitmap, error) {
+	size := SizeFrom96DPI(ic.ClientBounds().Size(), dpi)
+	if size.Width <= 0 || size.Height <= 0 {
+		return nil, newError("InkCanvas has no area")
+	}
+
+	bmp, err := NewBitmapForDPI(size, dpi)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := func() error {
+		canvas, err := NewCanvasFromImage(bmp)
+		if err != nil {
+			return err
+		}
+		defer canvas.Dispose()
+
+		return ic.paintContent(canvas, Rectangle{0, 0, size.Width, size.Height})
+	}(); err != nil {
+		bmp.Dispose()
+		return nil, err
+	}
+
+	return bmp, nil
+}
+
+func (ic *InkCanvas) invalidateCache() {
+	if ic.cache != nil {
+		ic.cache.Dispose()
+		ic.cache = nil
+	}
+}
+
+func (ic *InkCanvas) pointFromPixels(pt win.POINT) ScenePoint {
+	scale := 96 / float64(ic.DPI())
+
+	return ScenePoint{float64(pt.X) * scale, float64(pt.Y) * scale}
+}
+
+// beginInput starts the action of the mode at p. Pressing the eraser end of a
+// pen erases in any mode.
+func (ic *InkCanvas) beginInput(p InkPoint, erase, toggleSelection bool) {
+	ic.cancelInput()
+
+	sp := ScenePoint{p.X, p.Y}
+	ic.lastPoint = sp
+
+	switch {
+	case erase || ic.mode == InkModeErase:
+		ic.erasing = true
+		ic.eraseAt(sp)
+
+	case ic.mode == InkModeSelect:
+		stroke := ic.StrokeAt(sp)
+
+		switch {
+		case stroke == nil:
+			if !toggleSelection {
+				ic.ClearSelection()
+			}
+
+		case toggleSelection:
+			if ic.selected[stroke] {
+				delete(ic.selected, stroke)
+			} else {
+				ic.selected[stroke] = true
+			}
+
+			ic.Invalidate()
+			ic.selectionChangedPublisher.Publish()
+
+		default:
+			if !ic.selected[stroke] {
+				ic.SetSelectedStrokes([]*InkStroke{stroke})
+			}
+			ic.moving = true
+		}
+
+	default:
+		ic.current = &InkStroke{
+			Points: []InkPoint{p},
+			Color:  ic.inkColor,
+			Width:  ic.inkWidth,
+		}
+		ic.Invalidate()
+	}
+}
+
+func (ic *InkCanvas) continueInput(p InkPoint) {
+	sp := ScenePoint{p.X, p.Y}
+
+	switch {
+	case ic.erasing:
+		ic.eraseAt(sp)
+
+	case ic.moving:
+		dx, dy := sp.X-ic.lastPoint.X, sp.Y-ic.lastPoint.Y
+		if dx == 0 && dy == 0 {
+			return
+		}
+
+		for _, s := range ic.SelectedStrokes() {
+			s.translate(dx, dy)
+		}
+		ic.moved = true
+
+		ic.invalidateCache()
+		ic.Invalidate()
+
+	case ic.current != nil:
+		last := ic.current.Points[len(ic.current.Points)-1]
+		if math.Hypot(p.X-last.X, p.Y-last.Y) < inkMinPointLength {
+			return
+		}
+
+		ic.current.Points = append(ic.current.Points, p)
+		ic.Invalidate()
+	}
+
+	ic.lastPoint = sp
+}
+
+func (ic *InkCanvas) endInput() {
+	switch {
+	case ic.current != nil:
+		stroke := ic.current
+		ic.current = nil
+		ic.AddStroke(stroke)
+
+	case ic.moved:
+		ic.moved = false
+		ic.strokesChangedPublisher.Publish()
+	}
+
+	ic.erasing = false
+	ic.moving = false
+}
+
+// cancelInput abandons a stroke being drawn. Erased or moved strokes stay
+// erased or moved.
+func (ic *InkCanvas) cancelInput() {
+	if ic.current != nil {
+		ic.current = nil
+		ic.Invalidate()
+	}
+
+	if ic.moved {
+		ic.moved = false
+		ic.strokesChangedPublisher.Publish()
+	}
+
+	ic.erasing = false
+	ic.moving = false
+}
+
+func (ic *InkCanvas) eraseAt(p ScenePoint) {
+	var erased bool
+
+	for i := len(ic.strokes) - 1; i >= 0; i-- {
+		if s := ic.strokes[i]; s.hitTest(p, inkEraserRadius) {
+			erased = ic.removeStroke(s) || erased
+		}
+	}
+
+	if erased {
+		ic.strokesChanged()
+	}
+}
+
+func (ic *InkCanvas) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, ic.DPI(), ic.paint) {
+			return 0
+		}
+
+	case win.WM_SIZE:
+		ic.invalidateCache()
+		ic.Invalidate()
+
+	case wmPointerDown, wmPointerUpdate, wmPointerUp:
+		info, ok := penInfoFromWParam(wParam)
+		if !ok {
+			// Mouse and touch input arrives as mouse messages.
+			break
+		}
+
+		if !ic.Enabled() {
+			return 0
+		}
+
+		pt := info.pointerInfo.ptPixelLocation
+		win.ScreenToClient(hwnd, &pt)
+
+		p := ic.pointFromPixels(pt)
+		ip := InkPoint{X: p.X, Y: p.Y, Pressure: 1}
+		if info.penMask&penMaskPressure != 0 {
+			ip.Pressure = float64(info.pressure) / 1024
+		}
+		if info.penMask&penMaskTiltX != 0 {
+			ip.TiltX = int(info.tiltX)
+		}
+		if info.penMask&penMaskTiltY != 0 {
+			ip.TiltY = int(info.tiltY)
+		}
+
+		switch msg {
+		case wmPointerDown:
+			ic.SetFocus()
+			ic.beginInput(ip, info.penFlags&(penFlagEraser|penFlagInverted) != 0, ControlDown())
+
+		case wmPointerUpdate:
+			ic.continueInput(ip)
+
+		case wmPointerUp:
+			ic.continueInput(ip)
+			ic.endInput()
+		}
+
+		return 0
+
+	case win.WM_LBUTTONDOWN:
+		if !ic.Enabled() {
+			break
+		}
+
+		ic.SetFocus()
+		win.SetCapture(hwnd)
+		ic.tracking = true
+
+		p := ic.pointFromPixels(win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)})
+		ic.beginInput(InkPoint{X: p.X, Y: p.Y, Pressure: 1}, false, wParam&win.MK_CONTROL != 0)
+
+	case win.WM_MOUSEMOVE:
+		if !ic.tracking {
+			break
+		}
+
+		p := ic.pointFromPixels(win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)})
+		ic.continueInput(InkPoint{X: p.X, Y: p.Y, Pressure: 1})
+
+	case win.WM_LBUTTONUP:
+		if ic.tracking {
+			ic.tracking = false
+			ic.endInput()
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		if ic.tracking {
+			ic.tracking = false
+			ic.endInput()
+		}
+
+	case win.WM_KEYDOWN:
+		switch Key(wParam) {
+		case KeyEscape:
+			if ic.current != nil || ic.moving || ic.erasing {
+				ic.cancelInput()
+				return 0
+			}
+
+		case KeyDelete:
+			if ic.mode == InkModeSelect && len(ic.selected) > 0 {
+				ic.DeleteSelection()
+				return 0
+			}
+		}
+	}
+
+	return ic.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (ic *InkCanvas) paint(canvas *Canvas, bounds Rectangle) error {
+	dpi := ic.DPI()
+
+	if ic.cache == nil || ic.cache.Size() != bounds.Size() {
+		ic.invalidateCache()
+
+		cache, err := NewBitmapForDPI(bounds.Size(), dpi)
+		if err != nil {
+			return err
+		}
+
+		err = func() error {
+			cacheCanvas, err := NewCanvasFromImage(cache)
+			if err != nil {
+				return err
+			}
+			defer cacheCanvas.Dispose()
+
+			return ic.paintContent(cacheCanvas, Rectangle{0, 0, bounds.Width, bounds.Height})
+		}()
+		if err != nil {
+			cache.Dispose()
+			return err
+		}
+
+		ic.cache = cache
+	}
+
+	if err := blitBitmapPart(canvas.hdc, ic.cache, Point{}, bounds); err != nil {
+		return err
+	}
+
+	if ic.current != nil {
+		pens := make(inkPens)
+		defer pens.Dispose()
+
+		if err := pens.paintStroke(canvas, ic.current); err != nil {
+			return err
+		}
+	}
+
+	if len(ic.selected) == 0 {
+		return nil
+	}
+
+	pen, err := NewCosmeticPen(PenDot, Color(win.GetSysColor(win.COLOR_HIGHLIGHT)))
+	if err != nil {
+		return err
+	}
+	defer pen.Dispose()
+
+	for _, s := range ic.SelectedStrokes() {
+		sb := s.Bounds()
+
+		r := RectangleFrom96DPI(Rectangle{
+			int(math.Floor(sb.X)) - 2,
+			int(math.Floor(sb.Y)) - 2,
+			int(math.Ceil(sb.Width)) + 4,
+			int(math.Ceil(sb.Height)) + 4,
+		}, dpi)
+
+		if err := canvas.DrawRectanglePixels(pen, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// paintContent paints the image, or the background, and the strokes.
+func (ic *InkCanvas) paintContent(canvas *Canvas, bounds Rectangle) error {
+	if ic.image != nil {
+		if err := canvas.DrawImageStretchedPixels(ic.image, bounds); err != nil {
+			return err
+		}
+	} else {
+		bg, _ := ic.backgroundEffective()
+		if bg == nil {
+			brush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_WINDOW)))
+			if err != nil {
+				return err
+			}
+			defer brush.Dispose()
+
+			bg = brush
+		}
+
+		if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+			return err
+		}
+	}
+
+	pens := make(inkPens)
+	defer pens.Dispose()
+
+	for _, s := range ic.strokes {
+		if err := pens.paintStroke(canvas, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inkPens caches the pens for painting strokes by color and width.
+type inkPens map[inkPenKey]*GeometricPen
+
+type inkPenKey struct {
+	color Color
+	width int
+}
+
+func (pens inkPens) Dispose() {
+	for key, pen := range pens {
+		pen.Dispose()
+		pen.Brush().Dispose()
+		delete(pens, key)
+	}
+}
+
+func (pens inkPens) pen(color Color, width int) (*GeometricPen, error) {
+	key := inkPenKey{color, width}
+	if pen, ok := pens[key]; ok {
+		return pen, nil
+	}
+
+	brush, err := NewSolidColorBrush(color)
+	if err != nil {
+		return nil, err
+	}
+
+	pen, err := NewGeometricPen(PenSolid|PenCapRound|PenJoinRound, width, brush)
+	if err != nil {
+		brush.Dispose()
+		return nil, err
+	}
+
+	pens[key] = pen
+
+	return pen, nil
+}
+
+// paintStroke paints the smoothed stroke s segment by segment, each with the
+// width for its pressure.
+func (pens inkPens) paintStroke(canvas *Canvas, s *InkStroke) error {
+	points := smoothInkPoints(s.Points)
+	if len(points) == 0 {
+		return nil
+	}
+
+	scale := float64(canvas.DPI()) / 96
+
+	pen := func(pressure float64) (*GeometricPen, error) {
+		return pens.pen(s.Color, int(math.Max(1, math.Round(s.Width*math.Max(pressure, 0.1)))))
+	}
+
+	toPixels := func(p InkPoint) Point {
+		return Point{int(math.Round(p.X * scale)), int(math.Round(p.Y * scale))}
+	}
+
+	if len(points) == 1 {
+		p, err := pen(points[0].Pressure)
+		if err != nil {
+			return err
+		}
+
+		pt := toPixels(points[0])
+
+		return canvas.DrawLinePixels(p, pt, pt)
+	}
+
+	for i := 1; i < len(points); i++ {
+		p, err := pen((points[i-1].Pressure + points[i].Pressure) / 2)
+		if err != nil {
+			return err
+		}
+
+		if err := canvas.DrawLinePixels(p, toPixels(points[i-1]), toPixels(points[i])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ic *InkCanvas) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &inkCanvasLayoutItem{
+		idealSize: SizeFrom96DPI(Size{200, 150}, ctx.dpi),
+		minSize:   SizeFrom96DPI(Size{20, 20}, ctx.dpi),
+	}
+}
+
+type inkCanvasLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+	minSize   Size // in native pixels
+}
+
+func (li *inkCanvasLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | ShrinkableVert | GrowableHorz | GrowableVert | GreedyHorz | GreedyVert
+}
+
+func (li *inkCanvasLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *inkCanvasLayoutItem) MinSize() Size {
+	return li.minSize
+}
@@ -15,11 +15,17 @@ type intRangeEventHandlerInfo struct {
 type IntRangeEventHandler func(from, to int)
 
 type IntRangeEvent struct {
-	handlers []intRangeEventHandlerInfo
+	handlers   []*intRangeEventHandlerInfo
+	publishing int // Number of Publish calls in progress
 }
 
 func (e *IntRangeEvent) Attach(handler IntRangeEventHandler) int {
-	handlerInfo := intRangeEventHandlerInfo{handler, false}
+	handlerInfo := &intRangeEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*intRangeEventHandlerInfo(nil), e.handlers...)
+	}
 
 	for i, h := range e.handlers {
 		if h.handler == nil {
@@ -42,6 +48,20 @@ func (e *IntRangeEvent) Once(handler IntRangeEventHandler) {
 	e.handlers[i].once = true
 }
 
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *IntRangeEvent) Connect(handler IntRangeEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *IntRangeEvent) AttachOwned(owner Window, handler IntRangeEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
 type IntRangeEventPublisher struct {
 	event IntRangeEvent
 }
@@ -51,13 +71,28 @@ func (p *IntRangeEventPublisher) Event() *IntRangeEvent {
 }
 
 func (p *IntRangeEventPublisher) Publish(from, to int) {
-	for i, h := range p.event.handlers {
-		if h.handler != nil {
-			h.handler(from, to)
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
 
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
 			if h.once {
-				p.event.Detach(i)
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
 			}
+
+			handler(from, to)
 		}
 	}
 }
+
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+func (p *IntRangeEventPublisher) PublishAsync(from, to int) {
+	publishAsync(func() {
+		p.Publish(from, to)
+	})
+}
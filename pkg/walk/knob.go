@@ -0,0 +1,451 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/miu200521358/win"
+)
+
+// The knob pointer sweeps 270 degrees, clockwise from the lower left of the
+// face to its lower right.
+const (
+	knobStartDegrees = 225
+	knobSweepDegrees = 270
+)
+
+// Knob is a rotary widget for entering a value in a range, e.g. a rotation
+// or a gain, where a Slider would be awkward.
+//
+// The value is changed by dragging the pointer up or down, turning the mouse
+// wheel or with the arrow, page, home and end keys. Holding down the Shift
+// key while dragging makes fine adjustments and disables snapping. If
+// snapping is enabled, the value snaps to multiples of the step, counted
+// from the minimum.
+type Knob struct {
+	*CustomWidget
+	value                 float64
+	minValue              float64
+	maxValue              float64
+	step                  float64
+	pageStep              float64
+	snap                  bool
+	dragging              bool
+	dragOriginY           int32
+	dragOriginValue       float64
+	persistent            bool
+	wheelValueMode        WheelValueMode
+	valueChangedPublisher EventPublisher
+}
+
+// NewKnob creates and initializes a new Knob with the range [0, 100] and a
+// step of 1.
+func NewKnob(parent Container) (*Knob, error) {
+	k := &Knob{maxValue: 100, step: 1, pageStep: 10, snap: true}
+
+	cw, err := NewCustomWidgetPixels(parent, win.WS_TABSTOP, func(canvas *Canvas, updateBounds Rectangle) error {
+		return k.paint(canvas)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			cw.Dispose()
+		}
+	}()
+
+	k.CustomWidget = cw
+
+	if err := InitWrapperWindow(k); err != nil {
+		return nil, err
+	}
+
+	k.SetInvalidatesOnResize(true)
+
+	k.GraphicsEffects().Add(InteractionEffect)
+	k.GraphicsEffects().Add(FocusEffect)
+
+	k.MustRegisterProperty("Value", NewProperty(
+		func() interface{} {
+			return k.Value()
+		},
+		func(v interface{}) error {
+			return k.SetValue(assertFloat64Or(v, 0))
+		},
+		k.valueChangedPublisher.Event()))
+
+	succeeded = true
+
+	return k, nil
+}
+
+// MinValue returns the minimum value of the Knob.
+func (k *Knob) MinValue() float64 {
+	return k.minValue
+}
+
+// MaxValue returns the maximum value of the Knob.
+func (k *Knob) MaxValue() float64 {
+	return k.maxValue
+}
+
+// SetRange sets the minimum and maximum value of the Knob. The current value
+// is clamped to the new range.
+func (k *Knob) SetRange(min, max float64) error {
+	if math.IsNaN(min) || math.IsNaN(max) || math.IsInf(min, 0) || math.IsInf(max, 0) {
+		return newError("invalid range")
+	}
+	if min >= max {
+		return newError("invalid range")
+	}
+
+	k.minValue = min
+	k.maxValue = max
+
+	k.Invalidate()
+
+	return k.setValue(k.value)
+}
+
+// Step returns the amount the arrow keys and the mouse wheel change the value
+// by, which is also what the value snaps to.
+func (k *Knob) Step() float64 {
+	return k.step
+}
+
+// SetStep sets the amount the arrow keys and the mouse wheel change the value
+// by, which is also what the value snaps to.
+func (k *Knob) SetStep(step float64) error {
+	if !(step > 0) {
+		return newError("step must > 0")
+	}
+
+	k.step = step
+
+	return nil
+}
+
+// PageStep returns the amount the page keys change the value by.
+func (k *Knob) PageStep() float64 {
+	return k.pageStep
+}
+
+// SetPageStep sets the amount the page keys change the value by.
+func (k *Knob) SetPageStep(step float64) error {
+	if !(step > 0) {
+		return newError("step must > 0")
+	}
+
+	k.pageStep = step
+
+	return nil
+}
+
+// Snap returns whether the value snaps to multiples of the step.
+func (k *Knob) Snap() bool {
+	return k.snap
+}
+
+// SetSnap sets whether the value snaps to multiples of the step.
+func (k *Knob) SetSnap(snap bool) {
+	k.snap = snap
+}
+
+// WheelValueMode returns whether the mouse wheel changes Value.
+func (k *Knob) WheelValueMode() WheelValueMode {
+	return k.wheelValueMode
+}
+
+// SetWheelValueMode sets whether the mouse wheel changes Value.
+// WheelValueDefault uses App().WheelValueMode().
+func (k *Knob) SetWheelValueMode(mode WheelValueMode) {
+	k.wheelValueMode = mode
+}
+
+// Value returns the value of the Knob.
+func (k *Knob) Value() float64 {
+	return k.value
+}
+
+// SetValue sets the value of the Knob, clamped to its range.
+func (k *Knob) SetValue(value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return newError("invalid value")
+	}
+
+	return k.setValue(value)
+}
+
+func (k *Knob) setValue(value float64) error {
+	value = math.Max(k.minValue, math.Min(k.maxValue, value))
+
+	if value == k.value {
+		return nil
+	}
+
+	k.value = value
+
+	k.Invalidate()
+
+	k.valueChangedPublisher.Publish()
+
+	return nil
+}
+
+// ValueChanged returns an Event that can be used to track changes to Value.
+func (k *Knob) ValueChanged() *Event {
+	return k.valueChangedPublisher.Event()
+}
+
+func (k *Knob) Persistent() bool {
+	return k.persistent
+}
+
+func (k *Knob) SetPersistent(value bool) {
+	k.persistent = value
+}
+
+func (k *Knob) SaveState() error {
+	return k.WriteState(strconv.FormatFloat(k.value, 'f', -1, 64))
+}
+
+func (k *Knob) RestoreState() error {
+	s, err := k.ReadState()
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+
+	return k.SetValue(value)
+}
+
+func (k *Knob) snapValue(value float64) float64 {
+	if !k.snap {
+		return value
+	}
+
+	return k.minValue + math.Round((value-k.minValue)/k.step)*k.step
+}
+
+func (k *Knob) stepBy(delta float64) {
+	k.setValue(k.snapValue(k.value + delta))
+}
+
+// dragRange returns the distance in native pixels the pointer has to be
+// dragged to sweep the whole range.
+func (k *Knob) dragRange() float64 {
+	return float64(IntFrom96DPI(200, k.DPI()))
+}
+
+func (k *Knob) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_LBUTTONDOWN:
+		if !k.Enabled() {
+			break
+		}
+
+		k.SetFocus()
+		win.SetCapture(hwnd)
+		k.dragging = true
+		k.dragOriginY = win.GET_Y_LPARAM(lParam)
+		k.dragOriginValue = k.value
+
+	case win.WM_MOUSEMOVE:
+		if !k.dragging {
+			break
+		}
+
+		fine := wParam&win.MK_SHIFT != 0
+
+		dy := float64(k.dragOriginY - win.GET_Y_LPARAM(lParam))
+		if fine {
+			dy /= 10
+		}
+
+		value := k.dragOriginValue + dy/k.dragRange()*(k.maxValue-k.minValue)
+		if !fine {
+			value = k.snapValue(value)
+		}
+
+		k.setValue(value)
+
+	case win.WM_LBUTTONUP:
+		if k.dragging {
+			k.dragging = false
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		k.dragging = false
+
+	case win.WM_MOUSEWHEEL:
+		if !k.Enabled() || !wheelChangesValue(k.wheelValueMode, k.Focused()) {
+			break
+		}
+
+		delta := float64(int16(win.HIWORD(uint32(wParam)))) / 120
+
+		// With WheelValueWithCtrl, Ctrl is needed for any change, so it can't
+		// also select the page step.
+		mode := k.wheelValueMode
+		if mode == WheelValueDefault {
+			mode = App().WheelValueMode()
+		}
+
+		step := k.step
+		if mode != WheelValueWithCtrl && win.LOWORD(uint32(wParam))&win.MK_CONTROL != 0 {
+			step = k.pageStep
+		}
+
+		k.stepBy(delta * step)
+
+		return 0
+
+	case win.WM_KEYDOWN:
+		if !k.Enabled() {
+			break
+		}
+
+		switch Key(wParam) {
+		case KeyRight, KeyUp:
+			k.stepBy(k.step)
+
+		case KeyLeft, KeyDown:
+			k.stepBy(-k.step)
+
+		case KeyPrior:
+			k.stepBy(k.pageStep)
+
+		case KeyNext:
+			k.stepBy(-k.pageStep)
+
+		case KeyHome:
+			k.setValue(k.minValue)
+
+		case KeyEnd:
+			k.setValue(k.maxValue)
+		}
+	}
+
+	return k.CustomWidget.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (k *Knob) paint(canvas *Canvas) error {
+	bounds := k.ClientBoundsPixels()
+
+	dpi := k.DPI()
+	margin := IntFrom96DPI(2, dpi)
+
+	diameter := mini(bounds.Width, bounds.Height) - 2*margin
+	if diameter <= 0 {
+		return nil
+	}
+
+	face := Rectangle{
+		(bounds.Width - diameter) / 2,
+		(bounds.Height - diameter) / 2,
+		diameter,
+		diameter,
+	}
+
+	faceColor := Color(win.GetSysColor(win.COLOR_BTNFACE))
+	lineColor := Color(win.GetSysColor(win.COLOR_BTNSHADOW))
+	pointerColor := Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
+	if !k.Enabled() {
+		pointerColor = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	cx := float64(face.X) + float64(diameter)/2
+	cy := float64(face.Y) + float64(diameter)/2
+	radius := float64(diameter) / 2
+
+	pointAt := func(degrees, r float64) Point {
+		rad := degrees * math.Pi / 180
+
+		return Point{
+			int(math.Round(cx + math.Cos(rad)*r)),
+			int(math.Round(cy - math.Sin(rad)*r)),
+		}
+	}
+
+	linePen, err := NewCosmeticPen(PenSolid, lineColor)
+	if err != nil {
+		return err
+	}
+	defer linePen.Dispose()
+
+	// Scale ticks along the sweep, outside the knob body.
+	tickLength := float64(IntFrom96DPI(3, dpi))
+	for i := 0; i <= 10; i++ {
+		deg := knobStartDegrees - float64(i)*knobSweepDegrees/10
+		if err := canvas.DrawLinePixels(linePen, pointAt(deg, radius-tickLength), pointAt(deg, radius)); err != nil {
+			return err
+		}
+	}
+
+	body := int(radius - tickLength - float64(margin))
+	if body <= 0 {
+		return nil
+	}
+
+	bodyBounds := Rectangle{int(math.Round(cx)) - body, int(math.Round(cy)) - body, 2 * body, 2 * body}
+
+	faceBrush, err := NewSolidColorBrush(faceColor)
+	if err != nil {
+		return err
+	}
+	defer faceBrush.Dispose()
+
+	if err := canvas.FillEllipsePixels(faceBrush, bodyBounds); err != nil {
+		return err
+	}
+	if err := canvas.DrawEllipsePixels(linePen, bodyBounds); err != nil {
+		return err
+	}
+
+	pointerBrush, err := NewSolidColorBrush(pointerColor)
+	if err != nil {
+		return err
+	}
+	defer pointerBrush.Dispose()
+
+	pointerPen, err := NewGeometricPen(PenSolid|PenCapRound, IntFrom96DPI(2, dpi), pointerBrush)
+	if err != nil {
+		return err
+	}
+	defer pointerPen.Dispose()
+
+	fraction := (k.value - k.minValue) / (k.maxValue - k.minValue)
+	deg := knobStartDegrees - fraction*knobSweepDegrees
+
+	r := float64(body)
+
+	return canvas.DrawLinePixels(pointerPen, pointAt(deg, r*0.35), pointAt(deg, r-float64(margin)))
+}
+
+func (k *Knob) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &dialLayoutItem{
+		idealSize: SizeFrom96DPI(Size{40, 40}, ctx.dpi),
+		minSize:   SizeFrom96DPI(Size{24, 24}, ctx.dpi),
+	}
+}
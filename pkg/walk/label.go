@@ -7,7 +7,11 @@
 
 package walk
 
-import "github.com/miu200521358/win"
+import (
+	"sync"
+
+	"github.com/miu200521358/win"
+)
 
 type EllipsisMode int
 
@@ -17,8 +21,15 @@ const (
 	EllipsisPath              = EllipsisMode(win.SS_PATHELLIPSIS)
 )
 
+// Label is a widget that shows text.
+//
+// The text is aligned horizontally and vertically within the bounds of the
+// Label. With WordWrap, it breaks into lines to fit the width the layout
+// assigns and the Label grows as tall as needed. Selectable text can be
+// selected and copied with the mouse and keyboard.
 type Label struct {
 	static
+	wordWrap             bool
 	textChangedPublisher EventPublisher
 }
 
@@ -83,6 +94,63 @@ func (l *Label) SetTextAlignment(alignment Alignment1D) error {
 	return l.setTextAlignment1D(alignment)
 }
 
+// TextAlignment2D returns the horizontal and vertical alignment of the text.
+func (l *Label) TextAlignment2D() Alignment2D {
+	return l.textAlignment
+}
+
+// SetTextAlignment2D sets the horizontal and vertical alignment of the text.
+//
+// Vertical alignment takes effect if the Label is taller than its text, e.g.
+// because of its MinSize or the row of a GridLayout it is in.
+func (l *Label) SetTextAlignment2D(alignment Alignment2D) error {
+	if alignment == AlignHVDefault {
+		alignment = AlignHNearVCenter
+	}
+
+	if err := l.setTextAlignment(alignment); err != nil {
+		return err
+	}
+
+	l.updateStaticBounds()
+
+	return nil
+}
+
+// WordWrap returns whether the text breaks into lines to fit the width of the
+// Label.
+func (l *Label) WordWrap() bool {
+	return l.wordWrap
+}
+
+// SetWordWrap sets whether the text breaks into lines to fit the width of the
+// Label. An EllipsisMode other than EllipsisNone keeps the text on one line.
+func (l *Label) SetWordWrap(wordWrap bool) error {
+	if wordWrap == l.wordWrap {
+		return nil
+	}
+
+	l.wordWrap = wordWrap
+
+	if err := l.recreateEdit(); err != nil {
+		return err
+	}
+
+	l.RequestLayout()
+
+	return nil
+}
+
+// Selectable returns whether the text can be selected and copied.
+func (l *Label) Selectable() bool {
+	return l.selectable()
+}
+
+// SetSelectable sets whether the text can be selected and copied.
+func (l *Label) SetSelectable(selectable bool) error {
+	return l.setSelectable(selectable)
+}
+
 func (l *Label) Text() string {
 	return l.text()
 }
@@ -98,3 +166,57 @@ func (l *Label) SetText(text string) error {
 
 	return nil
 }
+
+func (l *Label) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	if !l.wordWrap || l.EllipsisMode() != EllipsisNone {
+		return l.static.CreateLayoutItem(ctx)
+	}
+
+	return &wordWrapLabelLayoutItem{
+		width2Height: make(map[int]int),
+		text:         l.Text(),
+		font:         l.Font(),
+	}
+}
+
+type wordWrapLabelLayoutItem struct {
+	LayoutItemBase
+	mutex        sync.Mutex
+	width2Height map[int]int // in native pixels
+	text         string
+	font         *Font
+}
+
+func (*wordWrapLabelLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | GrowableHorz
+}
+
+func (li *wordWrapLabelLayoutItem) IdealSize() Size {
+	return calculateTextSize(li.text, li.font, li.ctx.dpi, 0, li.handle)
+}
+
+func (li *wordWrapLabelLayoutItem) MinSize() Size {
+	// Wrapping lets the text get narrow, but not arbitrarily.
+	width := mini(li.IdealSize().Width, IntFrom96DPI(100, li.ctx.dpi))
+
+	return Size{width, li.HeightForWidth(width)}
+}
+
+func (li *wordWrapLabelLayoutItem) HasHeightForWidth() bool {
+	return true
+}
+
+func (li *wordWrapLabelLayoutItem) HeightForWidth(width int) int {
+	li.mutex.Lock()
+	defer li.mutex.Unlock()
+
+	if height, ok := li.width2Height[width]; ok {
+		return height
+	}
+
+	height := calculateTextSize(li.text, li.font, li.ctx.dpi, width, li.handle).Height
+
+	li.width2Height[width] = height
+
+	return height
+}
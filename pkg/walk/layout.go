@@ -81,6 +81,14 @@ func CreateLayoutItemsForContainerWithContext(container Container, ctx *LayoutCo
 		clib.spacing96dpi = lb.spacing96dpi
 	}
 
+	if cb.border.Style != BorderNone {
+		insets := cb.border.insets96dpi()
+		clib.margins96dpi.HNear += insets.HNear
+		clib.margins96dpi.VNear += insets.VNear
+		clib.margins96dpi.HFar += insets.HFar
+		clib.margins96dpi.VFar += insets.VFar
+	}
+
 	if len(clib.children) == 0 {
 		children := container.Children()
 		count := children.Len()
@@ -306,10 +314,12 @@ func applyLayoutResults(results []LayoutResult, stopwatch *stopwatch) error {
 		}
 
 		var maybeInvalidate bool
+		var animator *layoutAnimator
 		if wnd := windowFromHandle(result.container.Handle()); wnd != nil {
 			if ctr, ok := wnd.(Container); ok {
 				if cb := ctr.AsContainerBase(); cb != nil {
 					maybeInvalidate = cb.hasComplexBackground()
+					animator = cb.layoutAnimatorForApply()
 				}
 			}
 		}
@@ -339,6 +349,10 @@ func applyLayoutResults(results []LayoutResult, stopwatch *stopwatch) error {
 
 				oldBounds := widget.BoundsPixels()
 
+				if animator != nil && animator.animate(widget, oldBounds, ri.Bounds) {
+					continue
+				}
+
 				if ri.Bounds == oldBounds {
 					continue
 				}
@@ -546,7 +560,14 @@ type MinSizeForSizer interface {
 	MinSizeForSize(size Size) Size
 }
 
+// HeightForWidther is implemented by layout items whose height depends on
+// their width, e.g. wrapping text or flowing children.
+//
+// Layouts assign such items their width first and then ask for the height to
+// give them, so they grow taller rather than being clipped when their
+// container narrows. Container layout items propagate this, so nesting works.
 type HeightForWidther interface {
+	// HasHeightForWidth returns whether HeightForWidth should be consulted.
 	HasHeightForWidth() bool
 
 	// HeightForWidth returns appropriate height if element has given width. width parameter and
@@ -566,7 +587,7 @@ func (ctx *LayoutContext) DPI() int {
 func newLayoutContext(handle win.HWND) *LayoutContext {
 	return &LayoutContext{
 		layoutItem2MinSizeEffective: make(map[LayoutItem]Size),
-		dpi:                         int(win.GetDpiForWindow(handle)),
+		dpi:                         dpiForWindow(handle),
 	}
 }
 
@@ -0,0 +1,237 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"time"
+
+	"github.com/miu200521358/win"
+)
+
+// layoutAnimationInterval is the time between the frames of a layout
+// animation, for about 60 frames per second.
+const layoutAnimationInterval = 16 * time.Millisecond
+
+// Easing maps the elapsed fraction of an animation, in the range [0, 1], to
+// the fraction of the distance covered, which is 0 at the start and 1 at the
+// end.
+type Easing func(t float64) float64
+
+// EasingLinear moves at constant speed.
+func EasingLinear(t float64) float64 {
+	return t
+}
+
+// EasingEaseIn starts slowly and accelerates.
+func EasingEaseIn(t float64) float64 {
+	return t * t * t
+}
+
+// EasingEaseOut starts fast and decelerates.
+func EasingEaseOut(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+// EasingEaseInOut accelerates and then decelerates.
+func EasingEaseInOut(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+// LayoutAnimation returns the duration and easing of the animation that
+// moves the children of the container to their new bounds when its layout
+// changes. A duration of 0 means there is no animation.
+func (cb *ContainerBase) LayoutAnimation() (time.Duration, Easing) {
+	return cb.layoutAnimationDuration, cb.layoutAnimationEasing
+}
+
+// SetLayoutAnimation sets the duration and easing of the animation that moves
+// the children of the container to their new bounds when its layout changes,
+// e.g. when a child is shown or hidden. A duration of 0 turns the animation
+// off, a nil easing means EasingEaseOut.
+//
+// Children that become visible, and layouts of a container that isn't
+// visible, are not animated.
+func (cb *ContainerBase) SetLayoutAnimation(duration time.Duration, easing Easing) error {
+	if duration < 0 {
+		return newError("duration must not be negative")
+	}
+
+	if easing == nil {
+		easing = EasingEaseOut
+	}
+
+	cb.layoutAnimationDuration = duration
+	cb.layoutAnimationEasing = easing
+
+	if duration == 0 && cb.layoutAnimator != nil {
+		cb.layoutAnimator.finish()
+	}
+
+	return nil
+}
+
+// layoutAnimatorForApply returns the layoutAnimator to move the children with
+// when applying a layout, or nil if they are moved immediately.
+func (cb *ContainerBase) layoutAnimatorForApply() *layoutAnimator {
+	if cb.layoutAnimationDuration == 0 || !cb.visible || !win.IsWindowVisible(cb.hWnd) {
+		if cb.layoutAnimator != nil {
+			cb.layoutAnimator.finish()
+		}
+
+		return nil
+	}
+
+	if cb.layoutAnimator == nil {
+		cb.layoutAnimator = &layoutAnimator{
+			container:  cb,
+			animations: make(map[Widget]*layoutAnimation),
+		}
+	}
+
+	return cb.layoutAnimator
+}
+
+type layoutAnimation struct {
+	from  Rectangle // in native pixels
+	to    Rectangle // in native pixels
+	start time.Time
+}
+
+// layoutAnimator moves the children of a container to their new bounds over
+// time, driven by a UITimer.
+type layoutAnimator struct {
+	container  *ContainerBase
+	animations map[Widget]*layoutAnimation
+	timer      *UITimer
+}
+
+// animate starts moving widget from its current bounds to bounds and reports
+// whether it took care of it. It doesn't for widgets that haven't been laid
+// out yet and for widgets that already are at bounds.
+func (la *layoutAnimator) animate(widget Widget, current, bounds Rectangle) bool {
+	if a := la.animations[widget]; a != nil {
+		if a.to == bounds {
+			return true
+		}
+
+		if current == bounds {
+			delete(la.animations, widget)
+			return false
+		}
+	}
+
+	if current == bounds || current.Width <= 0 || current.Height <= 0 || !widget.AsWidgetBase().visible {
+		return false
+	}
+
+	la.animations[widget] = &layoutAnimation{from: current, to: bounds, start: time.Now()}
+
+	if la.timer == nil {
+		timer, err := NewUITimer(layoutAnimationInterval, la.step)
+		if err != nil {
+			delete(la.animations, widget)
+			return false
+		}
+
+		timer.SetOwner(la.container.window)
+
+		la.timer = timer
+	}
+
+	return true
+}
+
+// step moves the animated widgets to their bounds for the current frame.
+func (la *layoutAnimator) step() {
+	now := time.Now()
+	duration := la.container.layoutAnimationDuration
+	easing := la.container.layoutAnimationEasing
+
+	hdwp := win.BeginDeferWindowPos(int32(len(la.animations)))
+
+	var moved []Widget
+
+	for widget, a := range la.animations {
+		if widget.IsDisposed() {
+			delete(la.animations, widget)
+			continue
+		}
+
+		b := a.to
+
+		if t := float64(now.Sub(a.start)) / float64(duration); t < 1 && duration > 0 {
+			f := easing(t)
+
+			lerp := func(from, to int) int {
+				return from + int(math.Round(float64(to-from)*f))
+			}
+
+			b = Rectangle{
+				lerp(a.from.X, a.to.X),
+				lerp(a.from.Y, a.to.Y),
+				lerp(a.from.Width, a.to.Width),
+				lerp(a.from.Height, a.to.Height),
+			}
+		} else {
+			delete(la.animations, widget)
+		}
+
+		if hdwp != 0 {
+			hdwp = win.DeferWindowPos(
+				hdwp,
+				widget.Handle(),
+				0,
+				int32(b.X),
+				int32(b.Y),
+				int32(b.Width),
+				int32(b.Height),
+				win.SWP_NOACTIVATE|win.SWP_NOOWNERZORDER|win.SWP_NOZORDER)
+		}
+
+		moved = append(moved, widget)
+	}
+
+	if hdwp != 0 {
+		win.EndDeferWindowPos(hdwp)
+	}
+
+	if la.container.hasComplexBackground() {
+		la.container.Invalidate()
+	}
+
+	for _, widget := range moved {
+		if widget.GraphicsEffects().Len() > 0 {
+			widget.AsWidgetBase().invalidateBorderInParent()
+		}
+	}
+
+	if len(la.animations) == 0 {
+		la.stop()
+	}
+}
+
+// finish moves all animated widgets to their final bounds at once.
+func (la *layoutAnimator) finish() {
+	for _, a := range la.animations {
+		a.start = time.Time{}
+	}
+
+	la.step()
+}
+
+func (la *layoutAnimator) stop() {
+	if la.timer != nil {
+		la.timer.Dispose()
+		la.timer = nil
+	}
+}
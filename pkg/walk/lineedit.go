@@ -29,12 +29,18 @@ const (
 
 type LineEdit struct {
 	WidgetBase
-	editingFinishedPublisher EventPublisher
-	readOnlyChangedPublisher EventPublisher
-	textChangedPublisher     EventPublisher
-	charWidthFont            *Font
-	charWidth                int // in native pixels
-	textColor                Color
+	editingFinishedPublisher     EventPublisher
+	readOnlyChangedPublisher     EventPublisher
+	textChangedPublisher         EventPublisher
+	textChangedDetailedPublisher ValueChangedEventPublisher
+	lastText                     string // published last
+	settingText                  bool
+	charWidthFont                *Font
+	charWidth                    int // in native pixels
+	textColor                    Color
+	completer                    *lineEditCompleter
+	completionMode               CompletionMode
+	suggestionActivatedPublisher StringEventPublisher
 }
 
 func newLineEdit(parent Window, exStyle uint32) (*LineEdit, error) {
@@ -49,6 +55,8 @@ func newLineEdit(parent Window, exStyle uint32) (*LineEdit, error) {
 		return nil, err
 	}
 
+	le.lastText = le.Text()
+
 	le.GraphicsEffects().Add(InteractionEffect)
 	le.GraphicsEffects().Add(FocusEffect)
 
@@ -185,7 +193,20 @@ func (le *LineEdit) Text() string {
 }
 
 func (le *LineEdit) SetText(value string) error {
-	return le.setText(value)
+	le.settingText = true
+	defer func() {
+		le.settingText = false
+	}()
+
+	if err := le.setText(value); err != nil {
+		return err
+	}
+
+	// In case EN_CHANGE was not sent, so the next change reports the right
+	// OldValue.
+	le.lastText = le.Text()
+
+	return nil
 }
 
 func (le *LineEdit) TextSelection() (start, end int) {
@@ -342,6 +363,129 @@ func (le *LineEdit) TextChanged() *Event {
 	return le.textChangedPublisher.Event()
 }
 
+// TextChangedDetailed returns an Event that is published along with
+// TextChanged if Text actually changed, with the old and new string and how it
+// was changed, e.g. to record changes for undo.
+func (le *LineEdit) TextChangedDetailed() *ValueChangedEvent {
+	return le.textChangedDetailedPublisher.Event()
+}
+
+func (le *LineEdit) publishTextChanged() {
+	le.textChangedPublisher.Publish()
+
+	if text := le.Text(); text != le.lastText {
+		oldText := le.lastText
+		le.lastText = text
+
+		source := ValueChangeSourceKeyboard
+		if le.settingText {
+			source = ValueChangeSourceProgrammatic
+		}
+
+		le.textChangedDetailedPublisher.Publish(ValueChange{OldValue: oldText, NewValue: text, Source: source})
+	}
+}
+
+// SuggestionModel returns the model the LineEdit offers suggestions from.
+func (le *LineEdit) SuggestionModel() interface{} {
+	if le.completer == nil {
+		return nil
+	}
+
+	return le.completer.providedModel
+}
+
+// SetSuggestionModel sets the model the LineEdit offers suggestions from in a
+// drop down list while the user types, which may be a ListModel or a slice,
+// e.g. a []string.
+//
+// A SuggestionProvider takes precedence over the model.
+func (le *LineEdit) SetSuggestionModel(mdl interface{}) error {
+	model, ok := mdl.(ListModel)
+	if !ok && mdl != nil {
+		var err error
+		if model, err = newReflectListModel(mdl); err != nil {
+			return err
+		}
+	}
+
+	if model == nil && le.completer == nil {
+		return nil
+	}
+
+	if err := le.ensureCompleter(); err != nil {
+		return err
+	}
+
+	le.completer.setModel(mdl, model)
+
+	return nil
+}
+
+// SuggestionProvider returns the function the LineEdit asks for suggestions.
+func (le *LineEdit) SuggestionProvider() SuggestionProvider {
+	if le.completer == nil {
+		return nil
+	}
+
+	return le.completer.provider
+}
+
+// SetSuggestionProvider sets a function the LineEdit asks for the
+// suggestions to offer for its text, instead of searching its
+// SuggestionModel. The suggestions it returns are offered as they are,
+// regardless of the CompletionMode.
+func (le *LineEdit) SetSuggestionProvider(provider SuggestionProvider) error {
+	if provider == nil && le.completer == nil {
+		return nil
+	}
+
+	if err := le.ensureCompleter(); err != nil {
+		return err
+	}
+
+	le.completer.setProvider(provider)
+
+	return nil
+}
+
+func (le *LineEdit) ensureCompleter() error {
+	if le.completer != nil {
+		return nil
+	}
+
+	c, err := newLineEditCompleter(le)
+	if err != nil {
+		return err
+	}
+
+	le.completer = c
+
+	return nil
+}
+
+// CompletionMode returns which suggestions of the SuggestionModel are offered
+// for the text.
+func (le *LineEdit) CompletionMode() CompletionMode {
+	return le.completionMode
+}
+
+// SetCompletionMode sets which suggestions of the SuggestionModel are offered
+// for the text.
+func (le *LineEdit) SetCompletionMode(mode CompletionMode) {
+	le.completionMode = mode
+
+	if le.completer != nil {
+		le.completer.resetSuggestions()
+	}
+}
+
+// SuggestionActivated returns the event that is published when the user
+// chose a suggestion from the drop down list.
+func (le *LineEdit) SuggestionActivated() *StringEvent {
+	return le.suggestionActivatedPublisher.Event()
+}
+
 func (le *LineEdit) TextColor() Color {
 	return le.textColor
 }
@@ -357,11 +501,15 @@ func (*LineEdit) NeedsWmSize() bool {
 }
 
 func (le *LineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if le.completer != nil {
+		le.completer.wndProc(msg, wParam, lParam)
+	}
+
 	switch msg {
 	case win.WM_COMMAND:
 		switch win.HIWORD(uint32(wParam)) {
 		case win.EN_CHANGE:
-			le.textChangedPublisher.Publish()
+			le.publishTextChanged()
 		}
 
 	case win.WM_GETDLGCODE:
@@ -0,0 +1,377 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// CompletionMode specifies which suggestions of a model a LineEdit offers
+// for its text.
+type CompletionMode int
+
+const (
+	// CompletionPrefix offers the suggestions that start with the text.
+	CompletionPrefix CompletionMode = iota
+
+	// CompletionSubstring offers the suggestions that contain the text.
+	CompletionSubstring
+)
+
+// SuggestionProvider returns the suggestions a LineEdit offers for text.
+type SuggestionProvider func(text string) []string
+
+var (
+	clsidAutoComplete        = win.CLSID{Data1: 0x00BB2763, Data2: 0x6A77, Data3: 0x11D0, Data4: [8]byte{0xA5, 0x35, 0x00, 0xC0, 0x4F, 0xD7, 0xD0, 0x62}}
+	iidIAutoComplete2        = win.IID{Data1: 0xEAC04BC0, Data2: 0x3791, Data3: 0x11D2, Data4: [8]byte{0xBB, 0x95, 0x00, 0x60, 0x97, 0x7B, 0x46, 0x4C}}
+	iidIAutoCompleteDropDown = win.IID{Data1: 0x3CD141F4, Data2: 0x3C6A, Data3: 0x11D2, Data4: [8]byte{0xBC, 0xAA, 0x00, 0xC0, 0x4F, 0xD9, 0x29, 0xDB}}
+	iidIEnumString           = win.IID{Data1: 0x00000101, Data2: 0x0000, Data3: 0x0000, Data4: [8]byte{0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+)
+
+// Indexes into the vtables of the autocomplete interfaces.
+const (
+	unknownQueryInterface         = 0
+	unknownRelease                = 2
+	autoCompleteInit              = 3
+	autoCompleteEnable            = 4
+	autoCompleteSetOptions        = 5
+	autoCompleteDropDownGetStatus = 3
+	autoCompleteDropDownReset     = 4
+)
+
+// Options of IAutoComplete2.
+const (
+	acoAutoSuggest         = 0x0001
+	acoUpDownKeyDropsList  = 0x0020
+	acoNoPrefixFiltering   = 0x0100
+	acddVisible            = 0x0001
+	lineEditSuggestionsMax = 1000
+)
+
+var (
+	libole32       = syscall.NewLazyDLL("ole32.dll")
+	coTaskMemAlloc = libole32.NewProc("CoTaskMemAlloc")
+	enumStringVtbl *lineEditEnumStringVtbl
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		enumStringVtbl = &lineEditEnumStringVtbl{
+			syscall.NewCallback(lineEditEnumString_QueryInterface),
+			syscall.NewCallback(lineEditEnumString_AddRef),
+			syscall.NewCallback(lineEditEnumString_Release),
+			syscall.NewCallback(lineEditEnumString_Next),
+			syscall.NewCallback(lineEditEnumString_Skip),
+			syscall.NewCallback(lineEditEnumString_Reset),
+			syscall.NewCallback(lineEditEnumString_Clone),
+		}
+	})
+}
+
+type lineEditEnumStringVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+	Next           uintptr
+	Skip           uintptr
+	Reset          uintptr
+	Clone          uintptr
+}
+
+// lineEditEnumString implements IEnumString for IAutoComplete, enumerating
+// the suggestions of a lineEditCompleter.
+type lineEditEnumString struct {
+	vtbl      *lineEditEnumStringVtbl
+	completer *lineEditCompleter
+	items     []string
+	pos       int
+}
+
+func lineEditEnumString_QueryInterface(es *lineEditEnumString, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &iidIEnumString) {
+		*ppvObject = unsafe.Pointer(es)
+		return win.S_OK
+	}
+
+	*ppvObject = nil
+	return win.E_NOINTERFACE
+}
+
+func lineEditEnumString_AddRef(es *lineEditEnumString) uintptr {
+	return 1
+}
+
+func lineEditEnumString_Release(es *lineEditEnumString) uintptr {
+	return 1
+}
+
+func lineEditEnumString_Next(es *lineEditEnumString, celt uint32, rgelt *uintptr, pceltFetched *uint32) uintptr {
+	elts := unsafe.Slice(rgelt, celt)
+
+	var n uint32
+	for n < celt && es.pos < len(es.items) {
+		s := utf16.Encode([]rune(es.items[es.pos]))
+
+		// The caller frees the strings with CoTaskMemFree.
+		p, _, _ := coTaskMemAlloc.Call(uintptr(len(s)+1) * 2)
+		if p == 0 {
+			break
+		}
+
+		str := unsafe.Slice((*uint16)(unsafe.Pointer(p)), len(s)+1)
+		copy(str, s)
+		str[len(s)] = 0
+
+		elts[n] = p
+		n++
+		es.pos++
+	}
+
+	if pceltFetched != nil {
+		*pceltFetched = n
+	}
+
+	if n < celt {
+		return win.S_FALSE
+	}
+
+	return win.S_OK
+}
+
+func lineEditEnumString_Skip(es *lineEditEnumString, celt uint32) uintptr {
+	es.pos += int(celt)
+
+	if es.pos > len(es.items) {
+		es.pos = len(es.items)
+		return win.S_FALSE
+	}
+
+	return win.S_OK
+}
+
+func lineEditEnumString_Reset(es *lineEditEnumString) uintptr {
+	es.items = es.completer.suggestions()
+	es.pos = 0
+
+	return win.S_OK
+}
+
+func lineEditEnumString_Clone(es *lineEditEnumString, ppenum *unsafe.Pointer) uintptr {
+	clone := &lineEditEnumString{
+		vtbl:      es.vtbl,
+		completer: es.completer,
+		items:     es.items,
+		pos:       es.pos,
+	}
+
+	// The clone doesn't count references, so it is kept alive along with the
+	// completer.
+	es.completer.clones = append(es.completer.clones, clone)
+
+	*ppenum = unsafe.Pointer(clone)
+
+	return win.S_OK
+}
+
+// lineEditCompleter offers suggestions for the text of a LineEdit in a drop
+// down list, using the IAutoComplete implementation of the shell.
+type lineEditCompleter struct {
+	le                 *LineEdit
+	autoComplete       uintptr // IAutoComplete2
+	dropDown           uintptr // IAutoCompleteDropDown
+	enum               *lineEditEnumString
+	clones             []*lineEditEnumString
+	providedModel      interface{}
+	model              ListModel
+	modelHandles       [4]int
+	provider           SuggestionProvider
+	dropDownWasVisible bool
+}
+
+func newLineEditCompleter(le *LineEdit) (*lineEditCompleter, error) {
+	c := &lineEditCompleter{le: le}
+	c.enum = &lineEditEnumString{vtbl: enumStringVtbl, completer: c}
+
+	if hr := win.CoCreateInstance(&clsidAutoComplete, nil, win.CLSCTX_INPROC_SERVER, &iidIAutoComplete2, (*unsafe.Pointer)(unsafe.Pointer(&c.autoComplete))); win.FAILED(hr) {
+		return nil, errorFromHRESULT("CoCreateInstance(CLSID_AutoComplete)", hr)
+	}
+
+	if hr := win.HRESULT(int32(comCall(c.autoComplete, autoCompleteInit, uintptr(le.hWnd), uintptr(unsafe.Pointer(c.enum)), 0, 0))); win.FAILED(hr) {
+		comCall(c.autoComplete, unknownRelease)
+		return nil, errorFromHRESULT("IAutoComplete.Init", hr)
+	}
+
+	comCall(c.autoComplete, autoCompleteSetOptions, acoAutoSuggest|acoUpDownKeyDropsList|acoNoPrefixFiltering)
+
+	comCall(c.autoComplete, unknownQueryInterface, uintptr(unsafe.Pointer(&iidIAutoCompleteDropDown)), uintptr(unsafe.Pointer(&c.dropDown)))
+
+	le.Disposing().Attach(c.dispose)
+
+	return c, nil
+}
+
+func (c *lineEditCompleter) dispose() {
+	c.setModel(nil, nil)
+
+	if c.dropDown != 0 {
+		comCall(c.dropDown, unknownRelease)
+		c.dropDown = 0
+	}
+
+	if c.autoComplete != 0 {
+		comCall(c.autoComplete, unknownRelease)
+		c.autoComplete = 0
+	}
+}
+
+func (c *lineEditCompleter) setModel(providedModel interface{}, model ListModel) {
+	if c.model != nil {
+		c.model.ItemsReset().Detach(c.modelHandles[0])
+		c.model.ItemChanged().Detach(c.modelHandles[1])
+		c.model.ItemsInserted().Detach(c.modelHandles[2])
+		c.model.ItemsRemoved().Detach(c.modelHandles[3])
+	}
+
+	c.providedModel = providedModel
+	c.model = model
+
+	if model != nil {
+		rangeChanged := func(from, to int) {
+			c.resetSuggestions()
+		}
+
+		c.modelHandles[0] = model.ItemsReset().Attach(c.resetSuggestions)
+		c.modelHandles[1] = model.ItemChanged().Attach(func(index int) {
+			c.resetSuggestions()
+		})
+		c.modelHandles[2] = model.ItemsInserted().Attach(rangeChanged)
+		c.modelHandles[3] = model.ItemsRemoved().Attach(rangeChanged)
+	}
+
+	c.update()
+}
+
+func (c *lineEditCompleter) setProvider(provider SuggestionProvider) {
+	c.provider = provider
+
+	c.update()
+}
+
+// update enables the completion if there is something to suggest from.
+func (c *lineEditCompleter) update() {
+	enable := c.model != nil || c.provider != nil
+
+	comCall(c.autoComplete, autoCompleteEnable, uintptr(win.BoolToBOOL(enable)))
+
+	c.resetSuggestions()
+}
+
+// resetSuggestions makes the drop down list enumerate the suggestions again
+// the next time it is shown.
+func (c *lineEditCompleter) resetSuggestions() {
+	if c.dropDown != 0 {
+		comCall(c.dropDown, autoCompleteDropDownReset)
+	}
+}
+
+// suggestions returns the suggestions for the current text of the LineEdit.
+func (c *lineEditCompleter) suggestions() []string {
+	text := c.le.Text()
+
+	if c.provider != nil {
+		return c.provider(text)
+	}
+
+	if c.model == nil || text == "" {
+		return nil
+	}
+
+	text = strings.ToLower(text)
+	substring := c.le.completionMode == CompletionSubstring
+
+	var suggestions []string
+	for i, n := 0, c.model.ItemCount(); i < n && len(suggestions) < lineEditSuggestionsMax; i++ {
+		var s string
+		switch v := c.model.Value(i).(type) {
+		case string:
+			s = v
+
+		default:
+			s = fmt.Sprint(v)
+		}
+
+		lower := strings.ToLower(s)
+		if substring && strings.Contains(lower, text) || !substring && strings.HasPrefix(lower, text) {
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	return suggestions
+}
+
+func (c *lineEditCompleter) dropDownVisible() bool {
+	if c.dropDown == 0 {
+		return false
+	}
+
+	var flags uint32
+	comCall(c.dropDown, autoCompleteDropDownGetStatus, uintptr(unsafe.Pointer(&flags)), 0)
+
+	return flags&acddVisible != 0
+}
+
+// checkActivation publishes SuggestionActivated if the drop down list closed
+// and left the LineEdit with one of the suggestions it showed, as happens
+// when the user clicks on a suggestion or presses Return.
+func (c *lineEditCompleter) checkActivation() {
+	visible := c.dropDownVisible()
+	wasVisible := c.dropDownWasVisible
+	c.dropDownWasVisible = visible
+
+	if visible || !wasVisible {
+		return
+	}
+
+	text := c.le.Text()
+	for _, s := range c.enum.items {
+		if s == text {
+			c.le.suggestionActivatedPublisher.Publish(text)
+			return
+		}
+	}
+}
+
+// wndProc tracks the drop down list for the messages of the LineEdit.
+func (c *lineEditCompleter) wndProc(msg uint32, wParam, lParam uintptr) {
+	switch msg {
+	case win.WM_COMMAND:
+		if win.HIWORD(uint32(wParam)) == win.EN_CHANGE {
+			c.resetSuggestions()
+
+			// The drop down list is updated after the change.
+			c.le.Synchronize(c.checkActivation)
+		}
+
+	case win.WM_KEYUP:
+		if Key(wParam) == KeyEscape {
+			// Escape closes the drop down list without choosing a suggestion.
+			c.dropDownWasVisible = false
+		} else {
+			c.checkActivation()
+		}
+
+	case win.WM_KILLFOCUS:
+		c.dropDownWasVisible = false
+	}
+}
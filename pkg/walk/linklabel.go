@@ -8,12 +8,19 @@
 package walk
 
 import (
+	"strings"
+	"sync"
 	"syscall"
 	"unsafe"
 
 	"github.com/miu200521358/win"
 )
 
+// LinkLabel is a label that may contain multiple inline links, written as
+// <a href="url" id="id">text</a> in its text.
+//
+// Activating a link with the mouse or keyboard publishes LinkActivated with
+// the id and URL of the link. The mouse cursor turns into a hand over links.
 type LinkLabel struct {
 	WidgetBase
 	textChangedPublisher   EventPublisher
@@ -68,6 +75,50 @@ func (ll *LinkLabel) LinkActivated() *LinkLabelLinkEvent {
 	return ll.linkActivatedPublisher.Event()
 }
 
+// Links returns the links of the LinkLabel in text order.
+func (ll *LinkLabel) Links() []*LinkLabelLink {
+	var links []*LinkLabelLink
+
+	for index := 0; ; index++ {
+		link, ok := ll.linkAt(index)
+		if !ok {
+			return links
+		}
+
+		links = append(links, link)
+	}
+}
+
+// Link returns the first link of the LinkLabel with the specified id, e.g.
+// "foo" for <a id="foo">, or nil if there is none.
+func (ll *LinkLabel) Link(id string) *LinkLabelLink {
+	for _, link := range ll.Links() {
+		if link.id == id {
+			return link
+		}
+	}
+
+	return nil
+}
+
+func (ll *LinkLabel) linkAt(index int) (*LinkLabelLink, bool) {
+	li := win.LITEM{
+		ILink: int32(index),
+		Mask:  win.LIF_ITEMINDEX | win.LIF_ITEMID | win.LIF_URL,
+	}
+
+	if win.TRUE != ll.SendMessage(win.LM_GETITEM, 0, uintptr(unsafe.Pointer(&li))) {
+		return nil, false
+	}
+
+	return &LinkLabelLink{
+		ll:    ll,
+		index: index,
+		id:    syscall.UTF16ToString(li.SzID[:]),
+		url:   syscall.UTF16ToString(li.SzUrl[:]),
+	}, true
+}
+
 func (ll *LinkLabel) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	switch msg {
 	case win.WM_NOTIFY:
@@ -104,44 +155,6 @@ func (ll *LinkLabel) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr)
 	return ll.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
 }
 
-type LinkLabelLinkEventHandler func(link *LinkLabelLink)
-
-type LinkLabelLinkEvent struct {
-	handlers []LinkLabelLinkEventHandler
-}
-
-func (e *LinkLabelLinkEvent) Attach(handler LinkLabelLinkEventHandler) int {
-	for i, h := range e.handlers {
-		if h == nil {
-			e.handlers[i] = handler
-			return i
-		}
-	}
-
-	e.handlers = append(e.handlers, handler)
-	return len(e.handlers) - 1
-}
-
-func (e *LinkLabelLinkEvent) Detach(handle int) {
-	e.handlers[handle] = nil
-}
-
-type LinkLabelLinkEventPublisher struct {
-	event LinkLabelLinkEvent
-}
-
-func (p *LinkLabelLinkEventPublisher) Event() *LinkLabelLinkEvent {
-	return &p.event
-}
-
-func (p *LinkLabelLinkEventPublisher) Publish(link *LinkLabelLink) {
-	for _, handler := range p.event.handlers {
-		if handler != nil {
-			handler(link)
-		}
-	}
-}
-
 type LinkLabelLink struct {
 	ll    *LinkLabel
 	index int
@@ -224,17 +237,53 @@ func (ll *LinkLabel) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 	ll.SendMessage(win.LM_GETIDEALSIZE, uintptr(ll.IntFrom96DPI(ll.maxSize96dpi.Width)), uintptr(unsafe.Pointer(&s)))
 
 	return &linkLabelLayoutItem{
-		idealSize: sizeFromSIZE(s),
+		idealSize:    sizeFromSIZE(s),
+		width2Height: make(map[int]int),
+		text:         linkLabelPlainText(ll.Text()),
+		font:         ll.Font(),
+	}
+}
+
+// linkLabelPlainText returns text without the markup of its links.
+func linkLabelPlainText(text string) string {
+	var sb strings.Builder
+
+	for {
+		start := strings.Index(text, "<")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(text[start:], ">")
+		if end < 0 {
+			break
+		}
+
+		if tag := strings.ToLower(text[start+1 : start+end]); tag == "a" || tag == "/a" || strings.HasPrefix(tag, "a ") {
+			sb.WriteString(text[:start])
+		} else {
+			sb.WriteString(text[:start+end+1])
+		}
+
+		text = text[start+end+1:]
 	}
+
+	sb.WriteString(text)
+
+	return sb.String()
 }
 
 type linkLabelLayoutItem struct {
 	LayoutItemBase
-	idealSize Size // in native pixels
+	mutex        sync.Mutex
+	idealSize    Size        // in native pixels
+	width2Height map[int]int // in native pixels
+	text         string
+	font         *Font
 }
 
 func (*linkLabelLayoutItem) LayoutFlags() LayoutFlags {
-	return 0
+	return ShrinkableHorz
 }
 
 func (li *linkLabelLayoutItem) IdealSize() Size {
@@ -242,5 +291,32 @@ func (li *linkLabelLayoutItem) IdealSize() Size {
 }
 
 func (li *linkLabelLayoutItem) MinSize() Size {
-	return li.idealSize
+	// The links wrap, but not into arbitrarily narrow columns.
+	width := mini(li.idealSize.Width, IntFrom96DPI(100, li.ctx.dpi))
+
+	return Size{width, li.HeightForWidth(width)}
+}
+
+func (li *linkLabelLayoutItem) HasHeightForWidth() bool {
+	return true
+}
+
+func (li *linkLabelLayoutItem) HeightForWidth(width int) int {
+	if width >= li.idealSize.Width {
+		return li.idealSize.Height
+	}
+
+	li.mutex.Lock()
+	defer li.mutex.Unlock()
+
+	if height, ok := li.width2Height[width]; ok {
+		return height
+	}
+
+	// Layout runs off the GUI thread, so the control itself can't be asked.
+	height := maxi(li.idealSize.Height, calculateTextSize(li.text, li.font, li.ctx.dpi, width, li.handle).Height)
+
+	li.width2Height[width] = height
+
+	return height
 }
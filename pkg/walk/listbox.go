@@ -168,11 +168,11 @@ func (lb *ListBox) ApplySysColors() {
 		lb.style.highContrastActive = hc.DwFlags&win.HCF_HIGHCONTRASTON != 0
 	}
 
-	lb.themeNormalBGColor = Color(win.GetSysColor(win.COLOR_WINDOW))
-	lb.themeNormalTextColor = Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
-	lb.themeSelectedBGColor = Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
-	lb.themeSelectedTextColor = Color(win.GetSysColor(win.COLOR_HIGHLIGHTTEXT))
-	lb.themeSelectedNotFocusedBGColor = Color(win.GetSysColor(win.COLOR_BTNFACE))
+	lb.themeNormalBGColor = themeSysColor(win.COLOR_WINDOW)
+	lb.themeNormalTextColor = themeSysColor(win.COLOR_WINDOWTEXT)
+	lb.themeSelectedBGColor = themeSysColor(win.COLOR_HIGHLIGHT)
+	lb.themeSelectedTextColor = themeSysColor(win.COLOR_HIGHLIGHTTEXT)
+	lb.themeSelectedNotFocusedBGColor = themeSysColor(win.COLOR_BTNFACE)
 }
 
 func (lb *ListBox) ApplyDPI(dpi int) {
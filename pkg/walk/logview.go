@@ -0,0 +1,780 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const logViewWindowClass = `\o/ Walk_LogView_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClassWithStyle(logViewWindowClass, win.CS_DBLCLKS)
+	})
+}
+
+// LogLevel is the severity of a line of a LogView, which selects its color.
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarning
+	LogLevelError
+)
+
+type logLine struct {
+	text  string
+	level LogLevel
+}
+
+// LogView is a read-only, terminal-style view of log lines, that stays fast
+// when lines are appended at a high rate.
+//
+// It keeps at most MaxLines lines in a ring buffer, dropping the oldest ones,
+// and only paints the lines that are visible. Appending is safe from any
+// goroutine; appended lines are batched and shown by the UI thread. While
+// following the tail, it keeps the newest line in view. Scrolling up stops
+// following, scrolling back to the bottom resumes it.
+//
+// Lines can be selected with the mouse and keyboard and copied using Ctrl+C.
+type LogView struct {
+	WidgetBase
+	lines                      []logLine // ring buffer
+	first                      int       // index of the oldest line in lines
+	maxLines                   int
+	levelColors                [LogLevelError + 1]Color
+	scrollPos                  int // index of the first visible line
+	updatingScrollBar          bool
+	followTail                 bool
+	selAnchor                  int
+	selCaret                   int
+	selecting                  bool
+	lineHeightFont             *Font
+	lineHeightDPI              int
+	lineHeight                 int // in native pixels
+	pendingMutex               sync.Mutex
+	pending                    []logLine
+	partial                    string
+	flushScheduled             bool
+	followTailChangedPublisher EventPublisher
+	selectionChangedPublisher  EventPublisher
+}
+
+// NewLogView creates and initializes a new LogView that keeps up to 10000
+// lines and follows the tail.
+func NewLogView(parent Container) (*LogView, error) {
+	lv := &LogView{
+		maxLines:   10000,
+		followTail: true,
+		selAnchor:  -1,
+		selCaret:   -1,
+		levelColors: [...]Color{
+			LogLevelDebug:   Color(win.GetSysColor(win.COLOR_GRAYTEXT)),
+			LogLevelInfo:    Color(win.GetSysColor(win.COLOR_WINDOWTEXT)),
+			LogLevelWarning: RGB(0xB0, 0x60, 0x00),
+			LogLevelError:   RGB(0xC8, 0x00, 0x00),
+		},
+	}
+
+	if err := InitWidget(
+		lv,
+		parent,
+		logViewWindowClass,
+		win.WS_TABSTOP|win.WS_VISIBLE|win.WS_VSCROLL,
+		win.WS_EX_CLIENTEDGE); err != nil {
+		return nil, err
+	}
+
+	if font, err := NewFont("Consolas", 9, 0); err == nil {
+		lv.SetFont(font)
+	}
+
+	lv.GraphicsEffects().Add(InteractionEffect)
+	lv.GraphicsEffects().Add(FocusEffect)
+
+	lv.MustRegisterProperty("FollowTail", NewBoolProperty(
+		func() bool {
+			return lv.FollowTail()
+		},
+		func(b bool) error {
+			lv.SetFollowTail(b)
+			return nil
+		},
+		lv.followTailChangedPublisher.Event()))
+
+	lv.updateScrollBar()
+
+	return lv, nil
+}
+
+// AppendLine appends a line of text with level. Line breaks in text start
+// new lines of the same level.
+//
+// AppendLine may be called from any goroutine.
+func (lv *LogView) AppendLine(level LogLevel, text string) {
+	lv.pendingMutex.Lock()
+	defer lv.pendingMutex.Unlock()
+
+	lv.appendPendingLocked(level, text)
+}
+
+// Write implements io.Writer, so a LogView can be the output of a
+// log.Logger. Complete lines are appended with LogLevelInfo, an incomplete
+// last line is held back until its line break arrives.
+//
+// Write may be called from any goroutine.
+func (lv *LogView) Write(p []byte) (n int, err error) {
+	lv.pendingMutex.Lock()
+	defer lv.pendingMutex.Unlock()
+
+	text := lv.partial + string(p)
+
+	i := strings.LastIndexByte(text, '\n')
+	if i < 0 {
+		lv.partial = text
+		return len(p), nil
+	}
+
+	lv.partial = text[i+1:]
+	lv.appendPendingLocked(LogLevelInfo, text[:i])
+
+	return len(p), nil
+}
+
+func (lv *LogView) appendPendingLocked(level LogLevel, text string) {
+	for _, s := range strings.Split(text, "\n") {
+		lv.pending = append(lv.pending, logLine{strings.TrimSuffix(s, "\r"), level})
+	}
+
+	// Lines that would be dropped right away need not pile up.
+	if n := len(lv.pending) - lv.maxLines; n > 0 {
+		lv.pending = append(lv.pending[:0], lv.pending[n:]...)
+	}
+
+	if !lv.flushScheduled {
+		lv.flushScheduled = true
+		lv.Synchronize(lv.flushPending)
+	}
+}
+
+// flushPending moves the pending lines into the ring buffer, in one batch.
+func (lv *LogView) flushPending() {
+	lv.pendingMutex.Lock()
+	pending := lv.pending
+	lv.pending = nil
+	lv.flushScheduled = false
+	lv.pendingMutex.Unlock()
+
+	if lv.hWnd == 0 || len(pending) == 0 {
+		return
+	}
+
+	var dropped int
+	for _, line := range pending {
+		if len(lv.lines) < lv.maxLines {
+			lv.lines = append(lv.lines, line)
+		} else {
+			lv.lines[lv.first] = line
+			lv.first = (lv.first + 1) % lv.maxLines
+			dropped++
+		}
+	}
+
+	lv.shiftLines(dropped)
+}
+
+// shiftLines adjusts positions after the oldest dropped lines were removed.
+func (lv *LogView) shiftLines(dropped int) {
+	if dropped > 0 {
+		lv.scrollPos = maxi(0, lv.scrollPos-dropped)
+
+		if lv.selCaret > -1 {
+			lv.selAnchor -= dropped
+			lv.selCaret -= dropped
+
+			if lv.selAnchor < 0 && lv.selCaret < 0 {
+				lv.setSelection(-1, -1)
+			} else {
+				lv.selAnchor, lv.selCaret = maxi(0, lv.selAnchor), maxi(0, lv.selCaret)
+			}
+		}
+	}
+
+	lv.updateScrollBar()
+
+	if lv.followTail {
+		lv.setScrollPos(lv.maxScrollPos(), false)
+	}
+
+	lv.Invalidate()
+}
+
+// Clear removes all lines, including pending ones.
+func (lv *LogView) Clear() {
+	lv.pendingMutex.Lock()
+	lv.pending = nil
+	lv.partial = ""
+	lv.pendingMutex.Unlock()
+
+	lv.lines = nil
+	lv.first = 0
+	lv.scrollPos = 0
+	lv.setSelection(-1, -1)
+
+	lv.updateScrollBar()
+	lv.Invalidate()
+}
+
+// LineCount returns the number of lines of the LogView.
+func (lv *LogView) LineCount() int {
+	return len(lv.lines)
+}
+
+// Line returns the text and level of the line at index, where 0 is the
+// oldest line.
+func (lv *LogView) Line(index int) (text string, level LogLevel) {
+	line := lv.line(index)
+
+	return line.text, line.level
+}
+
+func (lv *LogView) line(index int) logLine {
+	return lv.lines[(lv.first+index)%len(lv.lines)]
+}
+
+// MaxLines returns the number of lines the LogView keeps at most.
+func (lv *LogView) MaxLines() int {
+	return lv.maxLines
+}
+
+// SetMaxLines sets the number of lines the LogView keeps at most. If there
+// are more lines already, the oldest ones are dropped.
+func (lv *LogView) SetMaxLines(maxLines int) error {
+	if maxLines < 1 {
+		return newError("maxLines must > 0")
+	}
+
+	if maxLines == lv.maxLines {
+		return nil
+	}
+
+	dropped := maxi(0, len(lv.lines)-maxLines)
+
+	lines := make([]logLine, 0, mini(maxLines, len(lv.lines)))
+	for i := dropped; i < len(lv.lines); i++ {
+		lines = append(lines, lv.line(i))
+	}
+
+	lv.lines = lines
+	lv.first = 0
+
+	lv.pendingMutex.Lock()
+	lv.maxLines = maxLines
+	lv.pendingMutex.Unlock()
+
+	lv.shiftLines(dropped)
+
+	return nil
+}
+
+// LevelColor returns the text color of lines with level.
+func (lv *LogView) LevelColor(level LogLevel) Color {
+	if level < LogLevelDebug || level > LogLevelError {
+		level = LogLevelInfo
+	}
+
+	return lv.levelColors[level]
+}
+
+// SetLevelColor sets the text color of lines with level.
+func (lv *LogView) SetLevelColor(level LogLevel, color Color) error {
+	if level < LogLevelDebug || level > LogLevelError {
+		return newError("invalid level")
+	}
+
+	lv.levelColors[level] = color
+
+	lv.Invalidate()
+
+	return nil
+}
+
+// FollowTail returns whether the LogView keeps the newest line in view.
+func (lv *LogView) FollowTail() bool {
+	return lv.followTail
+}
+
+// SetFollowTail sets whether the LogView keeps the newest line in view.
+// Enabling it scrolls to the bottom.
+func (lv *LogView) SetFollowTail(follow bool) {
+	if follow {
+		lv.setScrollPos(lv.maxScrollPos(), false)
+	}
+
+	lv.setFollowTail(follow)
+}
+
+func (lv *LogView) setFollowTail(follow bool) {
+	if follow == lv.followTail {
+		return
+	}
+
+	lv.followTail = follow
+
+	lv.followTailChangedPublisher.Publish()
+}
+
+// FollowTailChanged returns the event that is published when the LogView
+// started or stopped following the tail.
+func (lv *LogView) FollowTailChanged() *Event {
+	return lv.followTailChangedPublisher.Event()
+}
+
+// Selection returns the indexes of the first and last selected line, or -1,
+// -1 if no line is selected.
+func (lv *LogView) Selection() (first, last int) {
+	if lv.selCaret < 0 {
+		return -1, -1
+	}
+
+	return mini(lv.selAnchor, lv.selCaret), maxi(lv.selAnchor, lv.selCaret)
+}
+
+// SetSelection selects the lines from first through last. Pass -1, -1 to
+// clear the selection.
+func (lv *LogView) SetSelection(first, last int) error {
+	if first == -1 && last == -1 {
+		lv.setSelection(-1, -1)
+		return nil
+	}
+
+	if first < 0 || last < first || last >= len(lv.lines) {
+		return newError("invalid selection")
+	}
+
+	lv.setSelection(first, last)
+
+	return nil
+}
+
+func (lv *LogView) setSelection(anchor, caret int) {
+	if anchor == lv.selAnchor && caret == lv.selCaret {
+		return
+	}
+
+	lv.selAnchor, lv.selCaret = anchor, caret
+
+	lv.Invalidate()
+
+	lv.selectionChangedPublisher.Publish()
+}
+
+// SelectionChanged returns the event that is published when the selected
+// lines changed.
+func (lv *LogView) SelectionChanged() *Event {
+	return lv.selectionChangedPublisher.Event()
+}
+
+// SelectedText returns the text of the selected lines, separated by line
+// breaks.
+func (lv *LogView) SelectedText() string {
+	first, last := lv.Selection()
+	if first < 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i := first; i <= last; i++ {
+		if i > first {
+			sb.WriteString("\r\n")
+		}
+		sb.WriteString(lv.line(i).text)
+	}
+
+	return sb.String()
+}
+
+// Copy copies the selected lines to the clipboard.
+func (lv *LogView) Copy() error {
+	text := lv.SelectedText()
+	if text == "" {
+		return nil
+	}
+
+	return Clipboard().SetText(text)
+}
+
+// Find searches for the next line containing text, starting after the
+// caret line and wrapping around, or backwards if backward is true. A line
+// that is found becomes selected and is scrolled into view, which stops
+// following the tail.
+func (lv *LogView) Find(text string, matchCase, backward bool) bool {
+	n := len(lv.lines)
+	if text == "" || n == 0 {
+		return false
+	}
+
+	if !matchCase {
+		text = strings.ToLower(text)
+	}
+
+	step := 1
+	if backward {
+		step = -1
+	}
+
+	start := lv.selCaret
+	if start < 0 && backward {
+		start = n
+	}
+
+	for k := 1; k <= n; k++ {
+		i := ((start+k*step)%n + n) % n
+
+		s := lv.line(i).text
+		if !matchCase {
+			s = strings.ToLower(s)
+		}
+
+		if strings.Contains(s, text) {
+			lv.setSelection(i, i)
+			lv.EnsureVisible(i)
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnsureVisible scrolls the line at index into view.
+func (lv *LogView) EnsureVisible(index int) {
+	if index < lv.scrollPos {
+		lv.setScrollPos(index, true)
+	} else if rows := lv.visibleRows(); index >= lv.scrollPos+rows {
+		lv.setScrollPos(index-rows+1, true)
+	}
+}
+
+func (lv *LogView) applyFont(font *Font) {
+	lv.WidgetBase.applyFont(font)
+
+	lv.updateScrollBar()
+	lv.Invalidate()
+}
+
+func (lv *LogView) lineHeightPixels() int {
+	font, dpi := lv.Font(), lv.DPI()
+
+	if font != lv.lineHeightFont || dpi != lv.lineHeightDPI {
+		lv.lineHeight = IntFrom96DPI(14, dpi)
+
+		if canvas, err := lv.CreateCanvas(); err == nil {
+			if bounds, _, err := canvas.MeasureTextPixels("Xg", font, Rectangle{Width: 1000, Height: 1000}, TextSingleLine); err == nil {
+				lv.lineHeight = maxi(1, bounds.Height)
+			}
+			canvas.Dispose()
+		}
+
+		lv.lineHeightFont, lv.lineHeightDPI = font, dpi
+	}
+
+	return lv.lineHeight
+}
+
+// visibleRows returns the number of lines that fit the client area
+// completely.
+func (lv *LogView) visibleRows() int {
+	return maxi(1, lv.ClientBoundsPixels().Height/lv.lineHeightPixels())
+}
+
+func (lv *LogView) maxScrollPos() int {
+	return maxi(0, len(lv.lines)-lv.visibleRows())
+}
+
+func (lv *LogView) updateScrollBar() {
+	if lv.updatingScrollBar {
+		return
+	}
+	lv.updatingScrollBar = true
+	defer func() {
+		lv.updatingScrollBar = false
+	}()
+
+	var si win.SCROLLINFO
+	si.CbSize = uint32(unsafe.Sizeof(si))
+	si.FMask = win.SIF_PAGE | win.SIF_RANGE | win.SIF_DISABLENOSCROLL
+	si.NMax = int32(len(lv.lines) - 1)
+	si.NPage = uint32(lv.visibleRows())
+
+	win.SetScrollInfo(lv.hWnd, win.SB_VERT, &si, true)
+
+	lv.setScrollPos(lv.scrollPos, false)
+}
+
+// setScrollPos scrolls so the line at pos is the first visible one. If byUser
+// is true, following the tail stops or resumes depending on whether the
+// bottom was reached.
+func (lv *LogView) setScrollPos(pos int, byUser bool) {
+	if max := lv.maxScrollPos(); pos > max {
+		pos = max
+	}
+	if pos < 0 {
+		pos = 0
+	}
+
+	var si win.SCROLLINFO
+	si.CbSize = uint32(unsafe.Sizeof(si))
+	si.FMask = win.SIF_POS
+	si.NPos = int32(pos)
+
+	win.SetScrollInfo(lv.hWnd, win.SB_VERT, &si, true)
+
+	if pos != lv.scrollPos {
+		lv.scrollPos = pos
+		lv.Invalidate()
+	}
+
+	if byUser {
+		lv.setFollowTail(pos == lv.maxScrollPos())
+	}
+}
+
+func (lv *LogView) scroll(cmd uint16) {
+	page := lv.visibleRows()
+
+	switch cmd {
+	case win.SB_LINEUP:
+		lv.setScrollPos(lv.scrollPos-1, true)
+
+	case win.SB_LINEDOWN:
+		lv.setScrollPos(lv.scrollPos+1, true)
+
+	case win.SB_PAGEUP:
+		lv.setScrollPos(lv.scrollPos-page, true)
+
+	case win.SB_PAGEDOWN:
+		lv.setScrollPos(lv.scrollPos+page, true)
+
+	case win.SB_TOP:
+		lv.setScrollPos(0, true)
+
+	case win.SB_BOTTOM:
+		lv.setScrollPos(lv.maxScrollPos(), true)
+
+	case win.SB_THUMBTRACK:
+		var si win.SCROLLINFO
+		si.CbSize = uint32(unsafe.Sizeof(si))
+		si.FMask = win.SIF_TRACKPOS
+
+		win.GetScrollInfo(lv.hWnd, win.SB_VERT, &si)
+
+		lv.setScrollPos(int(si.NTrackPos), true)
+	}
+}
+
+// lineAt returns the index of the line at y, clamped to the existing lines.
+func (lv *LogView) lineAt(y int) int {
+	index := lv.scrollPos + y/lv.lineHeightPixels()
+	if y < 0 {
+		index = lv.scrollPos - 1
+	}
+
+	return maxi(0, mini(index, len(lv.lines)-1))
+}
+
+// moveCaret moves the caret line to index, extending the selection if
+// extend is true.
+func (lv *LogView) moveCaret(index int, extend bool) {
+	if len(lv.lines) == 0 {
+		return
+	}
+
+	index = maxi(0, mini(index, len(lv.lines)-1))
+
+	anchor := index
+	if extend && lv.selAnchor > -1 {
+		anchor = lv.selAnchor
+	}
+
+	lv.setSelection(anchor, index)
+	lv.EnsureVisible(index)
+}
+
+func (lv *LogView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, lv.DPI(), lv.paint) {
+			return 0
+		}
+
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_SIZE:
+		lv.updateScrollBar()
+
+		if lv.followTail {
+			lv.setScrollPos(lv.maxScrollPos(), false)
+		}
+
+		lv.Invalidate()
+
+	case win.WM_VSCROLL:
+		lv.scroll(win.LOWORD(uint32(wParam)))
+		return 0
+
+	case win.WM_MOUSEWHEEL:
+		delta := int(int16(win.HIWORD(uint32(wParam))))
+
+		lv.setScrollPos(lv.scrollPos-delta*3/120, true)
+		return 0
+
+	case win.WM_LBUTTONDOWN:
+		lv.SetFocus()
+
+		lv.moveCaret(lv.lineAt(int(win.GET_Y_LPARAM(lParam))), ShiftDown())
+
+		lv.selecting = true
+		win.SetCapture(hwnd)
+
+	case win.WM_MOUSEMOVE:
+		if lv.selecting {
+			lv.moveCaret(lv.lineAt(int(win.GET_Y_LPARAM(lParam))), true)
+		}
+
+	case win.WM_LBUTTONUP:
+		if lv.selecting {
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		lv.selecting = false
+
+	case win.WM_KEYDOWN:
+		page := lv.visibleRows()
+		shift := ShiftDown()
+
+		switch Key(wParam) {
+		case KeyUp:
+			lv.moveCaret(lv.selCaret-1, shift)
+
+		case KeyDown:
+			lv.moveCaret(lv.selCaret+1, shift)
+
+		case KeyPrior:
+			lv.moveCaret(lv.selCaret-page, shift)
+
+		case KeyNext:
+			lv.moveCaret(lv.selCaret+page, shift)
+
+		case KeyHome:
+			lv.moveCaret(0, shift)
+
+		case KeyEnd:
+			lv.moveCaret(len(lv.lines)-1, shift)
+
+		case KeyA:
+			if ControlDown() && len(lv.lines) > 0 {
+				lv.setSelection(0, len(lv.lines)-1)
+			}
+
+		case KeyC, KeyInsert:
+			if ControlDown() {
+				lv.Copy()
+			}
+		}
+	}
+
+	return lv.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (lv *LogView) paint(canvas *Canvas, bounds Rectangle) error {
+	bg, _ := lv.backgroundEffective()
+	if bg == nil {
+		windowBrush, err := NewSystemColorBrush(SysColorWindow)
+		if err != nil {
+			return err
+		}
+		defer windowBrush.Dispose()
+
+		bg = windowBrush
+	}
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	selBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_HIGHLIGHT)))
+	if err != nil {
+		return err
+	}
+	defer selBrush.Dispose()
+
+	selTextColor := Color(win.GetSysColor(win.COLOR_HIGHLIGHTTEXT))
+
+	font := lv.Font()
+	lh := lv.lineHeightPixels()
+	pad := IntFrom96DPI(3, lv.DPI())
+	first, last := lv.Selection()
+
+	const format = TextSingleLine | TextNoPrefix | TextExpandTabs | TextVCenter
+
+	// Only the lines intersecting the client area are painted.
+	for i, y := lv.scrollPos, 0; i < len(lv.lines) && y < bounds.Height; i, y = i+1, y+lh {
+		line := lv.line(i)
+		color := lv.LevelColor(line.level)
+
+		if i >= first && i <= last {
+			if err := canvas.FillRectanglePixels(selBrush, Rectangle{0, y, bounds.Width, lh}); err != nil {
+				return err
+			}
+
+			color = selTextColor
+		}
+
+		if err := canvas.DrawTextPixels(line.text, font, color, Rectangle{pad, y, bounds.Width - pad, lh}, format); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (lv *LogView) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &logViewLayoutItem{
+		idealSize: SizeFrom96DPI(Size{300, 150}, ctx.dpi),
+		minSize:   SizeFrom96DPI(Size{50, 30}, ctx.dpi),
+	}
+}
+
+type logViewLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+	minSize   Size // in native pixels
+}
+
+func (*logViewLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | GrowableHorz | GreedyHorz | ShrinkableVert | GrowableVert | GreedyVert
+}
+
+func (li *logViewLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *logViewLayoutItem) MinSize() Size {
+	return li.minSize
+}
@@ -17,6 +17,8 @@ import (
 //
 // extern void shimRunSynchronized(uintptr_t fb);
 // extern unsigned char shimHandleKeyDown(uintptr_t fb, uintptr_t m);
+// extern unsigned char shimIsModelessDialogMessage(uintptr_t fb, uintptr_t m);
+// extern void shimRouteMouseWheel(uintptr_t fb, uintptr_t m);
 //
 // static int mainloop(uintptr_t handle_ptr, uintptr_t fb_ptr)
 // {
@@ -32,7 +34,9 @@ import (
 //             return -1;
 //         if (m.message == WM_KEYDOWN && shimHandleKeyDown(fb_ptr, (uintptr_t)&m))
 //             continue;
-//         if (!IsDialogMessage(*hwnd, &m)) {
+//         if (m.message == WM_MOUSEWHEEL || m.message == WM_MOUSEHWHEEL)
+//             shimRouteMouseWheel(fb_ptr, (uintptr_t)&m);
+//         if (!shimIsModelessDialogMessage(fb_ptr, (uintptr_t)&m) && !IsDialogMessage(*hwnd, &m)) {
 //             TranslateMessage(&m);
 //             DispatchMessage(&m);
 //         }
@@ -47,6 +51,16 @@ func shimHandleKeyDown(fb uintptr, msg uintptr) bool {
 	return (*FormBase)(unsafe.Pointer(fb)).handleKeyDown((*win.MSG)(unsafe.Pointer(msg)))
 }
 
+//export shimIsModelessDialogMessage
+func shimIsModelessDialogMessage(fb uintptr, msg uintptr) bool {
+	return (*FormBase)(unsafe.Pointer(fb)).group.isModelessDialogMessage((*win.MSG)(unsafe.Pointer(msg)))
+}
+
+//export shimRouteMouseWheel
+func shimRouteMouseWheel(fb uintptr, msg uintptr) {
+	(*FormBase)(unsafe.Pointer(fb)).routeMouseWheel((*win.MSG)(unsafe.Pointer(msg)))
+}
+
 //export shimRunSynchronized
 func shimRunSynchronized(fb uintptr) {
 	(*FormBase)(unsafe.Pointer(fb)).group.RunSynchronized()
@@ -31,9 +31,12 @@ func (fb *FormBase) mainLoop() int {
 			if fb.handleKeyDown(msg) {
 				continue
 			}
+
+		case win.WM_MOUSEWHEEL, wmMouseHWheel:
+			fb.routeMouseWheel(msg)
 		}
 
-		if !win.IsDialogMessage(fb.hWnd, msg) {
+		if !fb.group.isModelessDialogMessage(msg) && !win.IsDialogMessage(fb.hWnd, msg) {
 			win.TranslateMessage(msg)
 			win.DispatchMessage(msg)
 		}
@@ -52,6 +52,10 @@ func (m *mapTableModel) Value(row, col int) interface{} {
 	return m.items[row][m.dataMembers[col]]
 }
 
+func (m *mapTableModel) rowItem(row int) interface{} {
+	return m.items[row]
+}
+
 func (m *mapTableModel) Sort(col int, order SortOrder) error {
 	m.col, m.order = col, order
 
@@ -91,7 +91,7 @@ func (m *Menu) updateItemsWithImageForWindow(window Window) {
 	}
 
 	for _, action := range m.actions.actions {
-		if action.image != nil {
+		if action.displayImage() != nil {
 			m.onActionChanged(action)
 		}
 		if action.menu != nil {
@@ -103,7 +103,7 @@ func (m *Menu) updateItemsWithImageForWindow(window Window) {
 func (m *Menu) initMenuItemInfoFromAction(mii *win.MENUITEMINFO, action *Action) {
 	mii.CbSize = uint32(unsafe.Sizeof(*mii))
 	mii.FMask = win.MIIM_FTYPE | win.MIIM_ID | win.MIIM_STATE | win.MIIM_STRING
-	if action.image != nil {
+	if image := action.displayImage(); image != nil {
 		mii.FMask |= win.MIIM_BITMAP
 		dpi := 96
 		if m.getDPI != nil {
@@ -113,7 +113,12 @@ func (m *Menu) initMenuItemInfoFromAction(mii *win.MENUITEMINFO, action *Action)
 		} else {
 			dpi = screenDPI()
 		}
-		if bmp, err := iconCache.Bitmap(action.image, dpi); err == nil {
+		bitmap := iconCache.Bitmap
+		if !action.enabled {
+			// Menus draw the bitmaps of disabled items in full color.
+			bitmap = iconCache.DisabledBitmap
+		}
+		if bmp, err := bitmap(image, dpi); err == nil {
 			mii.HbmpItem = bmp.hBmp
 		}
 	}
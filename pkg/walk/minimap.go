@@ -0,0 +1,344 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+const minimapWindowClass = `\o/ Walk_Minimap_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(minimapWindowClass)
+	})
+}
+
+// MinimapSource is implemented by large scrollable widgets, like Timeline,
+// that a Minimap can show an overview of.
+//
+// Extent and viewport are measured along the orientation of the Minimap, in
+// units chosen by the source, e.g. frames or lines.
+type MinimapSource interface {
+	// MinimapExtent returns the length of the whole content.
+	MinimapExtent() float64
+
+	// MinimapViewport returns the start and length of the visible part of
+	// the content.
+	MinimapViewport() (start, length float64)
+
+	// SetMinimapViewportStart scrolls the source, so that the visible part
+	// of the content starts at start. The source clamps start as needed.
+	SetMinimapViewportStart(start float64)
+
+	// PaintMinimap paints a miniature of the whole content into bounds.
+	PaintMinimap(canvas *Canvas, bounds Rectangle) error
+
+	// MinimapContentChanged returns the event that is published when the
+	// miniature needs to be repainted.
+	MinimapContentChanged() *Event
+
+	// MinimapViewportChanged returns the event that is published when the
+	// visible part of the content changed.
+	MinimapViewportChanged() *Event
+}
+
+// Minimap is an overview strip that shows a miniature of a MinimapSource
+// together with a rectangle marking the visible part of it.
+//
+// The miniature is cached in a bitmap and only repainted when the content of
+// the source changed. Dragging the rectangle, or clicking beside it, scrolls
+// the source.
+type Minimap struct {
+	WidgetBase
+	source                 MinimapSource
+	contentChangedHandle   int
+	viewportChangedHandle  int
+	orientation            Orientation
+	cache                  *Bitmap
+	dragging               bool
+	dragOffset             float64
+	sourceChangedPublisher EventPublisher
+}
+
+// NewMinimap creates and initializes a new horizontal Minimap.
+func NewMinimap(parent Container) (*Minimap, error) {
+	return NewMinimapWithOrientation(parent, Horizontal)
+}
+
+// NewMinimapWithOrientation creates and initializes a new Minimap with the
+// specified orientation.
+func NewMinimapWithOrientation(parent Container, orientation Orientation) (*Minimap, error) {
+	mm := &Minimap{orientation: orientation}
+
+	if err := InitWidget(
+		mm,
+		parent,
+		minimapWindowClass,
+		win.WS_VISIBLE,
+		0); err != nil {
+		return nil, err
+	}
+
+	return mm, nil
+}
+
+func (mm *Minimap) Dispose() {
+	mm.detachSource()
+	mm.invalidateCache()
+
+	mm.WidgetBase.Dispose()
+}
+
+// Orientation returns the orientation of the Minimap.
+func (mm *Minimap) Orientation() Orientation {
+	return mm.orientation
+}
+
+// Source returns the MinimapSource shown by the Minimap.
+func (mm *Minimap) Source() MinimapSource {
+	return mm.source
+}
+
+// SetSource sets the MinimapSource shown by the Minimap.
+func (mm *Minimap) SetSource(source MinimapSource) {
+	if source == mm.source {
+		return
+	}
+
+	mm.detachSource()
+
+	mm.source = source
+
+	if source != nil {
+		mm.contentChangedHandle = source.MinimapContentChanged().Attach(func() {
+			mm.invalidateCache()
+			mm.Invalidate()
+		})
+		mm.viewportChangedHandle = source.MinimapViewportChanged().Attach(func() {
+			mm.Invalidate()
+		})
+	}
+
+	mm.invalidateCache()
+	mm.Invalidate()
+
+	mm.sourceChangedPublisher.Publish()
+}
+
+// SourceChanged returns the event that is published when the source changed.
+func (mm *Minimap) SourceChanged() *Event {
+	return mm.sourceChangedPublisher.Event()
+}
+
+func (mm *Minimap) detachSource() {
+	if mm.source == nil {
+		return
+	}
+
+	mm.source.MinimapContentChanged().Detach(mm.contentChangedHandle)
+	mm.source.MinimapViewportChanged().Detach(mm.viewportChangedHandle)
+	mm.source = nil
+}
+
+func (mm *Minimap) invalidateCache() {
+	if mm.cache != nil {
+		mm.cache.Dispose()
+		mm.cache = nil
+	}
+}
+
+// length returns the length of the Minimap along its orientation in native
+// pixels.
+func (mm *Minimap) length() int {
+	cb := mm.ClientBoundsPixels()
+
+	if mm.orientation == Vertical {
+		return cb.Height
+	}
+
+	return cb.Width
+}
+
+func (mm *Minimap) valueAt(lParam uintptr) float64 {
+	pos := win.GET_X_LPARAM(lParam)
+	if mm.orientation == Vertical {
+		pos = win.GET_Y_LPARAM(lParam)
+	}
+
+	length := mm.length()
+	if length <= 0 {
+		return 0
+	}
+
+	return float64(pos) * mm.source.MinimapExtent() / float64(length)
+}
+
+// viewportBounds returns the bounds of the viewport rectangle in native
+// pixels.
+func (mm *Minimap) viewportBounds() Rectangle {
+	cb := mm.ClientBoundsPixels()
+
+	extent := mm.source.MinimapExtent()
+	if extent <= 0 {
+		return cb
+	}
+
+	start, length := mm.source.MinimapViewport()
+
+	scale := float64(mm.length()) / extent
+	p0, p1 := int(start*scale), int((start+length)*scale)
+	if p1-p0 < 3 {
+		p1 = p0 + 3
+	}
+
+	if mm.orientation == Vertical {
+		return Rectangle{0, p0, cb.Width, p1 - p0}
+	}
+
+	return Rectangle{p0, 0, p1 - p0, cb.Height}
+}
+
+func (mm *Minimap) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, mm.DPI(), mm.paint) {
+			return 0
+		}
+
+	case win.WM_SIZE:
+		mm.invalidateCache()
+		mm.Invalidate()
+
+	case win.WM_LBUTTONDOWN:
+		if !mm.Enabled() || mm.source == nil {
+			break
+		}
+
+		value := mm.valueAt(lParam)
+		start, length := mm.source.MinimapViewport()
+
+		if value >= start && value < start+length {
+			mm.dragOffset = value - start
+		} else {
+			mm.dragOffset = length / 2
+			mm.source.SetMinimapViewportStart(value - mm.dragOffset)
+		}
+
+		win.SetCapture(hwnd)
+		mm.dragging = true
+
+	case win.WM_MOUSEMOVE:
+		if mm.dragging && mm.source != nil {
+			mm.source.SetMinimapViewportStart(mm.valueAt(lParam) - mm.dragOffset)
+		}
+
+	case win.WM_LBUTTONUP:
+		if mm.dragging {
+			mm.dragging = false
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		mm.dragging = false
+	}
+
+	return mm.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (mm *Minimap) paint(canvas *Canvas, bounds Rectangle) error {
+	if mm.source == nil {
+		bg, _ := mm.backgroundEffective()
+		if bg == nil {
+			bg = sysColorBtnFaceBrush
+		}
+
+		return canvas.FillRectanglePixels(bg, bounds)
+	}
+
+	if mm.cache == nil || mm.cache.Size() != bounds.Size() {
+		mm.invalidateCache()
+
+		cache, err := NewBitmapForDPI(bounds.Size(), mm.DPI())
+		if err != nil {
+			return err
+		}
+
+		err = func() error {
+			cacheCanvas, err := NewCanvasFromImage(cache)
+			if err != nil {
+				return err
+			}
+			defer cacheCanvas.Dispose()
+
+			return mm.source.PaintMinimap(cacheCanvas, Rectangle{0, 0, bounds.Width, bounds.Height})
+		}()
+		if err != nil {
+			cache.Dispose()
+			return err
+		}
+
+		mm.cache = cache
+	}
+
+	if err := blitBitmapPart(canvas.hdc, mm.cache, Point{}, bounds); err != nil {
+		return err
+	}
+
+	pen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_HIGHLIGHT)))
+	if err != nil {
+		return err
+	}
+	defer pen.Dispose()
+
+	vb := mm.viewportBounds()
+
+	if err := canvas.DrawRectanglePixels(pen, vb); err != nil {
+		return err
+	}
+
+	return canvas.DrawRectanglePixels(pen, Rectangle{vb.X + 1, vb.Y + 1, vb.Width - 2, vb.Height - 2})
+}
+
+func (mm *Minimap) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	li := &minimapLayoutItem{
+		layoutFlags: ShrinkableHorz | GrowableHorz | GreedyHorz,
+		idealSize:   SizeFrom96DPI(Size{100, 24}, ctx.dpi),
+		minSize:     SizeFrom96DPI(Size{20, 8}, ctx.dpi),
+	}
+
+	if mm.orientation == Vertical {
+		li.layoutFlags = ShrinkableVert | GrowableVert | GreedyVert
+		li.idealSize = Size{li.idealSize.Height, li.idealSize.Width}
+		li.minSize = Size{li.minSize.Height, li.minSize.Width}
+	}
+
+	return li
+}
+
+type minimapLayoutItem struct {
+	LayoutItemBase
+	layoutFlags LayoutFlags
+	idealSize   Size // in native pixels
+	minSize     Size // in native pixels
+}
+
+func (li *minimapLayoutItem) LayoutFlags() LayoutFlags {
+	return li.layoutFlags
+}
+
+func (li *minimapLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *minimapLayoutItem) MinSize() Size {
+	return li.minSize
+}
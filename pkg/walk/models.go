@@ -317,8 +317,11 @@ type CellStyler interface {
 // CellStyle carries information about the display style of a cell in a tabular widget
 // like TableView.
 type CellStyle struct {
+	tv              *TableView
 	row             int
 	col             int
+	value           interface{}
+	valueLoaded     bool
 	bounds          Rectangle // in native pixels
 	hdc             win.HDC
 	dpi             int
@@ -344,6 +347,68 @@ func (cs *CellStyle) Col() int {
 	return cs.col
 }
 
+// Value returns the value of the model for the cell, as displayed by the
+// TableView before formatting, or nil for a whole row or a header.
+func (cs *CellStyle) Value() interface{} {
+	if !cs.valueLoaded {
+		cs.value = nil
+		if cs.row >= 0 && cs.col >= 0 && cs.tv != nil && cs.tv.model != nil {
+			cs.value = cs.tv.model.Value(cs.row, cs.col)
+		}
+		cs.valueLoaded = true
+	}
+
+	return cs.value
+}
+
+// Item returns the item of the row of the cell, i.e. an element of the slice
+// of a ReflectTableModel or a map of a []map[string]interface{} model, or nil
+// for other models and for headers.
+func (cs *CellStyle) Item() interface{} {
+	if cs.row < 0 || cs.tv == nil {
+		return nil
+	}
+
+	if ri, ok := cs.tv.model.(rowItemer); ok {
+		return ri.rowItem(cs.row)
+	}
+
+	return nil
+}
+
+// Column returns the column of the cell, or nil for a whole row.
+func (cs *CellStyle) Column() *TableViewColumn {
+	if cs.col < 0 || cs.tv == nil || cs.col >= len(cs.tv.columns.items) {
+		return nil
+	}
+
+	return cs.tv.columns.items[cs.col]
+}
+
+// ColumnKey returns the effective data member of the column of the cell, which
+// identifies it independent of its position, or "" for a whole row.
+func (cs *CellStyle) ColumnKey() string {
+	if col := cs.Column(); col != nil {
+		return col.DataMemberEffective()
+	}
+
+	return ""
+}
+
+// styleWith lets styler style the cell.
+func (cs *CellStyle) styleWith(styler CellStyler) {
+	cs.value = nil
+	cs.valueLoaded = false
+
+	styler.StyleCell(cs)
+}
+
+// rowItemer is implemented by the models TableView creates for data sources
+// that have items, to pass them on to CellStylers.
+type rowItemer interface {
+	rowItem(row int) interface{}
+}
+
 func (cs *CellStyle) Bounds() Rectangle {
 	return RectangleTo96DPI(cs.bounds, cs.dpi)
 }
@@ -409,6 +474,7 @@ type ListItemStyle struct {
 	dpi                int
 	canvas             *Canvas
 	highContrastActive bool
+	selectionField     bool
 }
 
 func (lis *ListItemStyle) Index() int {
@@ -499,6 +565,29 @@ func (lis *ListItemStyle) DrawText(text string, bounds Rectangle, format DrawTex
 	return nil
 }
 
+// DrawImage draws image stretched to bounds specified in native pixels, e.g.
+// an icon next to the text of the item.
+func (lis *ListItemStyle) DrawImage(image Image, bounds Rectangle) error {
+	canvas := lis.Canvas()
+	if canvas == nil {
+		return nil
+	}
+
+	return canvas.DrawImageStretchedPixels(image, bounds)
+}
+
+// SecondaryTextColor returns a dimmed TextColor for less important text of
+// the item, like a description below its name.
+func (lis *ListItemStyle) SecondaryTextColor() Color {
+	return lis.TextColor.Blend(lis.BackgroundColor, 0.4)
+}
+
+// InSelectionField returns if the item is drawn in the selection field of a
+// ComboBox, rather than in its drop down list.
+func (lis *ListItemStyle) InSelectionField() bool {
+	return lis.selectionField
+}
+
 func (lis *ListItemStyle) stateID() int32 {
 	if lis.state&win.ODS_CHECKED != 0 {
 		if win.GetFocus() == lis.hwnd {
@@ -661,16 +750,37 @@ type TreeModel interface {
 	ItemRemoved() *TreeItemEvent
 }
 
+// BatchTreeModel is a TreeModel that can publish the insertion and removal of
+// a contiguous range of siblings as a single event.
+//
+// TreeView handles these events in a single pass, which is much faster than
+// handling the insertion or removal of many items one at a time.
+type BatchTreeModel interface {
+	TreeModel
+
+	// ItemsInserted returns the event that the model should publish when the
+	// children of the specified item, or the roots if no item is specified,
+	// at the indexes from to to were inserted.
+	ItemsInserted() *TreeItemRangeEvent
+
+	// ItemsRemoved returns the event that the model should publish when the
+	// children of the specified item, or the roots if no item is specified,
+	// that were at the indexes from to to were removed.
+	ItemsRemoved() *TreeItemRangeEvent
+}
+
 // TreeModelBase partially implements the TreeModel interface.
 //
 // You still need to provide your own implementation of at least the
 // RootCount and RootAt methods. If your model needs lazy population,
 // you will also have to implement LazyPopulation.
 type TreeModelBase struct {
-	itemsResetPublisher   TreeItemEventPublisher
-	itemChangedPublisher  TreeItemEventPublisher
-	itemInsertedPublisher TreeItemEventPublisher
-	itemRemovedPublisher  TreeItemEventPublisher
+	itemsResetPublisher    TreeItemEventPublisher
+	itemChangedPublisher   TreeItemEventPublisher
+	itemInsertedPublisher  TreeItemEventPublisher
+	itemRemovedPublisher   TreeItemEventPublisher
+	itemsInsertedPublisher TreeItemRangeEventPublisher
+	itemsRemovedPublisher  TreeItemRangeEventPublisher
 }
 
 func (tmb *TreeModelBase) LazyPopulation() bool {
@@ -693,6 +803,14 @@ func (tmb *TreeModelBase) ItemRemoved() *TreeItemEvent {
 	return tmb.itemRemovedPublisher.Event()
 }
 
+func (tmb *TreeModelBase) ItemsInserted() *TreeItemRangeEvent {
+	return tmb.itemsInsertedPublisher.Event()
+}
+
+func (tmb *TreeModelBase) ItemsRemoved() *TreeItemRangeEvent {
+	return tmb.itemsRemovedPublisher.Event()
+}
+
 func (tmb *TreeModelBase) PublishItemsReset(parent TreeItem) {
 	tmb.itemsResetPublisher.Publish(parent)
 }
@@ -708,3 +826,82 @@ func (tmb *TreeModelBase) PublishItemInserted(item TreeItem) {
 func (tmb *TreeModelBase) PublishItemRemoved(item TreeItem) {
 	tmb.itemRemovedPublisher.Publish(item)
 }
+
+// PublishItemsInserted notifies that the children of parent, or the roots if
+// parent is nil, at the indexes from to to were inserted.
+func (tmb *TreeModelBase) PublishItemsInserted(parent TreeItem, from, to int) {
+	tmb.itemsInsertedPublisher.Publish(parent, from, to)
+}
+
+// PublishItemsRemoved notifies that the children of parent, or the roots if
+// parent is nil, that were at the indexes from to to were removed.
+func (tmb *TreeModelBase) PublishItemsRemoved(parent TreeItem, from, to int) {
+	tmb.itemsRemovedPublisher.Publish(parent, from, to)
+}
+
+// ChartSeriesKind specifies how a series of a ChartModel is drawn.
+type ChartSeriesKind int
+
+const (
+	ChartLine ChartSeriesKind = iota
+	ChartBar
+	ChartScatter
+)
+
+// ChartPoint is a data point of a ChartModel series.
+type ChartPoint struct {
+	X, Y float64
+}
+
+// ChartModel is the interface that a model must implement to support the
+// Chart widget.
+type ChartModel interface {
+	// SeriesCount returns the number of series in the model.
+	SeriesCount() int
+
+	// SeriesName returns the name of the series, as displayed in the legend.
+	SeriesName(series int) string
+
+	// SeriesColor returns the color the series is drawn with.
+	SeriesColor(series int) Color
+
+	// SeriesKind returns how the series is drawn.
+	SeriesKind(series int) ChartSeriesKind
+
+	// PointCount returns the number of points of the series.
+	PointCount(series int) int
+
+	// Point returns the point at index of the series.
+	Point(series, index int) ChartPoint
+
+	// SeriesReset returns the event that the model should publish when the
+	// number of its series changes.
+	SeriesReset() *Event
+
+	// SeriesChanged returns the event that the model should publish when the
+	// points or attributes of a series were changed.
+	SeriesChanged() *IntEvent
+}
+
+// ChartModelBase implements the SeriesReset and SeriesChanged methods of the
+// ChartModel interface.
+type ChartModelBase struct {
+	seriesResetPublisher   EventPublisher
+	seriesChangedPublisher IntEventPublisher
+}
+
+func (cmb *ChartModelBase) SeriesReset() *Event {
+	return cmb.seriesResetPublisher.Event()
+}
+
+func (cmb *ChartModelBase) SeriesChanged() *IntEvent {
+	return cmb.seriesChangedPublisher.Event()
+}
+
+func (cmb *ChartModelBase) PublishSeriesReset() {
+	cmb.seriesResetPublisher.Publish()
+}
+
+func (cmb *ChartModelBase) PublishSeriesChanged(series int) {
+	cmb.seriesChangedPublisher.Publish(series)
+}
@@ -28,11 +28,17 @@ type mouseEventHandlerInfo struct {
 type MouseEventHandler func(x, y int, button MouseButton)
 
 type MouseEvent struct {
-	handlers []mouseEventHandlerInfo
+	handlers   []*mouseEventHandlerInfo
+	publishing int // Number of Publish calls in progress
 }
 
 func (e *MouseEvent) Attach(handler MouseEventHandler) int {
-	handlerInfo := mouseEventHandlerInfo{handler, false}
+	handlerInfo := &mouseEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*mouseEventHandlerInfo(nil), e.handlers...)
+	}
 
 	for i, h := range e.handlers {
 		if h.handler == nil {
@@ -55,6 +61,20 @@ func (e *MouseEvent) Once(handler MouseEventHandler) {
 	e.handlers[i].once = true
 }
 
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *MouseEvent) Connect(handler MouseEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *MouseEvent) AttachOwned(owner Window, handler MouseEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
 type MouseEventPublisher struct {
 	event MouseEvent
 }
@@ -65,17 +85,32 @@ func (p *MouseEventPublisher) Event() *MouseEvent {
 
 // Publish publishes mouse event. x and y are measured in native pixels.
 func (p *MouseEventPublisher) Publish(x, y int, button MouseButton) {
-	for i, h := range p.event.handlers {
-		if h.handler != nil {
-			h.handler(x, y, button)
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
 
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
 			if h.once {
-				p.event.Detach(i)
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
 			}
+
+			handler(x, y, button)
 		}
 	}
 }
 
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+func (p *MouseEventPublisher) PublishAsync(x, y int, button MouseButton) {
+	publishAsync(func() {
+		p.Publish(x, y, button)
+	})
+}
+
 func MouseWheelEventDelta(button MouseButton) int {
 	return int(int32(button) >> 16)
 }
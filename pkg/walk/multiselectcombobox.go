@@ -0,0 +1,617 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// Constants of DrawFrameControl.
+const (
+	dfcButton       = 4
+	dfcsButtonCheck = 0x0000
+	dfcsInactive    = 0x0100
+	dfcsChecked     = 0x0400
+)
+
+// selectionFieldIndex is the item index of CB_SETITEMHEIGHT and
+// CB_GETITEMHEIGHT that refers to the selection field.
+var selectionFieldIndex int32 = -1
+
+var (
+	drawFrameControl = syscall.NewLazyDLL("user32.dll").NewProc("DrawFrameControl")
+
+	multiSelectComboBoxListWndProcPtr uintptr
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		multiSelectComboBoxListWndProcPtr = syscall.NewCallback(multiSelectComboBoxListWndProc)
+	})
+}
+
+func multiSelectComboBoxListWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	cb := (*MultiSelectComboBox)(unsafe.Pointer(win.GetWindowLongPtr(hwnd, win.GWLP_USERDATA)))
+
+	switch msg {
+	case win.WM_LBUTTONDOWN, win.WM_LBUTTONDBLCLK, win.WM_LBUTTONUP:
+		result := uint32(win.SendMessage(hwnd, win.LB_ITEMFROMPOINT, 0, lParam))
+		if win.HIWORD(result) != 0 {
+			// Outside of the items, which closes the drop down list.
+			break
+		}
+
+		// Toggle the item instead of closing the drop down list.
+		if msg != win.WM_LBUTTONUP {
+			index := int(win.LOWORD(result))
+			win.SendMessage(hwnd, win.LB_SETCURSEL, uintptr(index), 0)
+			cb.toggle(index)
+		}
+		return 0
+	}
+
+	return win.CallWindowProc(cb.listOrigWndProcPtr, hwnd, msg, wParam, lParam)
+}
+
+// MultiSelectComboBox is a drop down list with a check box per item, that
+// shows the checked items as a summary while it is closed.
+type MultiSelectComboBox struct {
+	WidgetBase
+	model                      ListModel
+	providedModel              interface{}
+	checked                    []bool
+	separator                  string
+	emptyText                  string
+	itemsResetHandlerHandle    int
+	itemChangedHandlerHandle   int
+	itemsInsertedHandlerHandle int
+	itemsRemovedHandlerHandle  int
+	maxItemTextWidth           int // in native pixels
+	checkedChangedPublisher    EventPublisher
+	listHWnd                   win.HWND
+	listOrigWndProcPtr         uintptr
+	persistent                 bool
+}
+
+// NewMultiSelectComboBox creates a new MultiSelectComboBox as child of parent.
+func NewMultiSelectComboBox(parent Container) (*MultiSelectComboBox, error) {
+	cb := &MultiSelectComboBox{separator: ", "}
+
+	if err := InitWidget(
+		cb,
+		parent,
+		"COMBOBOX",
+		win.WS_TABSTOP|win.WS_VISIBLE|win.WS_VSCROLL|win.CBS_DROPDOWNLIST|win.CBS_OWNERDRAWFIXED|win.CBS_HASSTRINGS,
+		0); err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			cb.Dispose()
+		}
+	}()
+
+	cbi := comboBoxInfo{cbSize: uint32(unsafe.Sizeof(comboBoxInfo{}))}
+	if win.FALSE == cb.SendMessage(win.CB_GETCOMBOBOXINFO, 0, uintptr(unsafe.Pointer(&cbi))) {
+		return nil, newError("SendMessage(CB_GETCOMBOBOXINFO)")
+	}
+
+	cb.listHWnd = cbi.hwndList
+	win.SetWindowLongPtr(cb.listHWnd, win.GWLP_USERDATA, uintptr(unsafe.Pointer(cb)))
+	cb.listOrigWndProcPtr = win.SetWindowLongPtr(cb.listHWnd, win.GWLP_WNDPROC, multiSelectComboBoxListWndProcPtr)
+
+	cb.updateItemHeights()
+
+	cb.GraphicsEffects().Add(InteractionEffect)
+	cb.GraphicsEffects().Add(FocusEffect)
+
+	cb.MustRegisterProperty("CheckedIndexes", NewProperty(
+		func() interface{} {
+			return cb.CheckedIndexes()
+		},
+		func(v interface{}) error {
+			indexes, _ := v.([]int)
+			return cb.SetCheckedIndexes(indexes)
+		},
+		cb.CheckedChanged()))
+
+	cb.MustRegisterProperty("HasCheckedItems", NewReadOnlyBoolProperty(
+		func() bool {
+			return len(cb.CheckedIndexes()) > 0
+		},
+		cb.CheckedChanged()))
+
+	succeeded = true
+
+	return cb, nil
+}
+
+func (cb *MultiSelectComboBox) applyFont(font *Font) {
+	cb.WidgetBase.applyFont(font)
+
+	cb.updateItemHeights()
+
+	if cb.model != nil {
+		cb.maxItemTextWidth = cb.calculateMaxItemTextWidth()
+		cb.RequestLayout()
+	}
+}
+
+// updateItemHeights makes room for the check boxes in the items, which are
+// owner drawn.
+func (cb *MultiSelectComboBox) updateItemHeights() {
+	height := maxi(cb.calculateTextSizeImpl("gM").Height, cb.checkBoxSize()) + IntFrom96DPI(4, cb.DPI())
+
+	cb.SendMessage(win.CB_SETITEMHEIGHT, uintptr(selectionFieldIndex), uintptr(height))
+	cb.SendMessage(win.CB_SETITEMHEIGHT, 0, uintptr(height))
+}
+
+func (cb *MultiSelectComboBox) checkBoxSize() int {
+	return IntFrom96DPI(13, cb.DPI())
+}
+
+// Model returns the model of the MultiSelectComboBox.
+func (cb *MultiSelectComboBox) Model() interface{} {
+	return cb.providedModel
+}
+
+// SetModel sets the model of the MultiSelectComboBox, which may be a ListModel
+// or a slice, e.g. a []string. All items are unchecked afterwards.
+func (cb *MultiSelectComboBox) SetModel(mdl interface{}) error {
+	model, ok := mdl.(ListModel)
+	if !ok && mdl != nil {
+		var err error
+		if model, err = newReflectListModel(mdl); err != nil {
+			return err
+		}
+	}
+
+	if cb.model != nil {
+		cb.detachModel()
+	}
+
+	cb.providedModel = mdl
+	cb.model = model
+
+	if model != nil {
+		cb.attachModel()
+	}
+
+	return cb.resetItems()
+}
+
+func (cb *MultiSelectComboBox) attachModel() {
+	cb.itemsResetHandlerHandle = cb.model.ItemsReset().Attach(func() {
+		cb.resetItems()
+	})
+
+	cb.itemChangedHandlerHandle = cb.model.ItemChanged().Attach(func(index int) {
+		cb.SendMessage(win.CB_DELETESTRING, uintptr(index), 0)
+		cb.insertItemAt(index)
+		cb.invalidateSummary()
+	})
+
+	cb.itemsInsertedHandlerHandle = cb.model.ItemsInserted().Attach(func(from, to int) {
+		inserted := make([]bool, to-from+1)
+		cb.checked = append(cb.checked[:from], append(inserted, cb.checked[from:]...)...)
+
+		for i := from; i <= to; i++ {
+			cb.insertItemAt(i)
+		}
+	})
+
+	cb.itemsRemovedHandlerHandle = cb.model.ItemsRemoved().Attach(func(from, to int) {
+		var checkedRemoved bool
+		for i := from; i <= to; i++ {
+			checkedRemoved = checkedRemoved || cb.checked[i]
+		}
+
+		cb.checked = append(cb.checked[:from], cb.checked[to+1:]...)
+
+		for i := to; i >= from; i-- {
+			cb.SendMessage(win.CB_DELETESTRING, uintptr(i), 0)
+		}
+
+		if checkedRemoved {
+			cb.invalidateSummary()
+			cb.checkedChangedPublisher.Publish()
+		}
+	})
+}
+
+func (cb *MultiSelectComboBox) detachModel() {
+	cb.model.ItemsReset().Detach(cb.itemsResetHandlerHandle)
+	cb.model.ItemChanged().Detach(cb.itemChangedHandlerHandle)
+	cb.model.ItemsInserted().Detach(cb.itemsInsertedHandlerHandle)
+	cb.model.ItemsRemoved().Detach(cb.itemsRemovedHandlerHandle)
+}
+
+func (cb *MultiSelectComboBox) itemString(index int) string {
+	switch val := cb.model.Value(index).(type) {
+	case string:
+		return val
+
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func (cb *MultiSelectComboBox) insertItemAt(index int) error {
+	lp := uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(cb.itemString(index))))
+
+	if win.CB_ERR == cb.SendMessage(win.CB_INSERTSTRING, uintptr(index), lp) {
+		return newError("SendMessage(CB_INSERTSTRING)")
+	}
+
+	return nil
+}
+
+func (cb *MultiSelectComboBox) resetItems() error {
+	defer suspendUpdates(cb)()
+
+	hadChecked := len(cb.CheckedIndexes()) > 0
+
+	if win.FALSE == cb.SendMessage(win.CB_RESETCONTENT, 0, 0) {
+		return newError("SendMessage(CB_RESETCONTENT)")
+	}
+
+	cb.checked = nil
+	cb.maxItemTextWidth = 0
+
+	if cb.model != nil {
+		count := cb.model.ItemCount()
+		cb.checked = make([]bool, count)
+
+		for i := 0; i < count; i++ {
+			if err := cb.insertItemAt(i); err != nil {
+				return err
+			}
+		}
+	}
+
+	cb.RequestLayout()
+
+	if hadChecked {
+		cb.checkedChangedPublisher.Publish()
+	}
+
+	return nil
+}
+
+func (cb *MultiSelectComboBox) calculateMaxItemTextWidth() int {
+	var maxWidth int
+
+	if cb.model == nil {
+		return maxWidth
+	}
+
+	for i, n := 0, cb.model.ItemCount(); i < n; i++ {
+		maxWidth = maxi(maxWidth, cb.calculateTextSizeImpl(cb.itemString(i)).Width)
+	}
+
+	return maxWidth
+}
+
+// Checked returns if the item at index is checked.
+func (cb *MultiSelectComboBox) Checked(index int) bool {
+	return index >= 0 && index < len(cb.checked) && cb.checked[index]
+}
+
+// SetChecked checks or unchecks the item at index.
+func (cb *MultiSelectComboBox) SetChecked(index int, checked bool) error {
+	if index < 0 || index >= len(cb.checked) {
+		return newError("invalid index")
+	}
+
+	if checked == cb.checked[index] {
+		return nil
+	}
+
+	cb.checked[index] = checked
+
+	cb.invalidateItem(index)
+	cb.invalidateSummary()
+
+	cb.checkedChangedPublisher.Publish()
+
+	return nil
+}
+
+// CheckedIndexes returns the indexes of the checked items, in ascending
+// order.
+func (cb *MultiSelectComboBox) CheckedIndexes() []int {
+	var indexes []int
+
+	for i, checked := range cb.checked {
+		if checked {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// SetCheckedIndexes checks the items at indexes and unchecks all others.
+func (cb *MultiSelectComboBox) SetCheckedIndexes(indexes []int) error {
+	checked := make([]bool, len(cb.checked))
+
+	for _, index := range indexes {
+		if index < 0 || index >= len(checked) {
+			return newError("invalid index")
+		}
+
+		checked[index] = true
+	}
+
+	var changed bool
+	for i := range checked {
+		if checked[i] != cb.checked[i] {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	cb.checked = checked
+
+	if cb.listHWnd != 0 {
+		win.InvalidateRect(cb.listHWnd, nil, true)
+	}
+	cb.invalidateSummary()
+
+	cb.checkedChangedPublisher.Publish()
+
+	return nil
+}
+
+// CheckedChanged returns the event that is published when items were checked
+// or unchecked.
+func (cb *MultiSelectComboBox) CheckedChanged() *Event {
+	return cb.checkedChangedPublisher.Event()
+}
+
+// Separator returns the text between the checked items in the summary.
+func (cb *MultiSelectComboBox) Separator() string {
+	return cb.separator
+}
+
+// SetSeparator sets the text between the checked items in the summary.
+func (cb *MultiSelectComboBox) SetSeparator(separator string) {
+	cb.separator = separator
+
+	cb.invalidateSummary()
+}
+
+// EmptyText returns the summary that is shown while no item is checked.
+func (cb *MultiSelectComboBox) EmptyText() string {
+	return cb.emptyText
+}
+
+// SetEmptyText sets the summary that is shown while no item is checked.
+func (cb *MultiSelectComboBox) SetEmptyText(text string) {
+	cb.emptyText = text
+
+	cb.invalidateSummary()
+}
+
+// Summary returns the text shown while the drop down list is closed.
+func (cb *MultiSelectComboBox) Summary() string {
+	var texts []string
+
+	for i, checked := range cb.checked {
+		if checked {
+			texts = append(texts, cb.itemString(i))
+		}
+	}
+
+	if len(texts) == 0 {
+		return cb.emptyText
+	}
+
+	return strings.Join(texts, cb.separator)
+}
+
+func (cb *MultiSelectComboBox) Persistent() bool {
+	return cb.persistent
+}
+
+func (cb *MultiSelectComboBox) SetPersistent(value bool) {
+	cb.persistent = value
+}
+
+func (cb *MultiSelectComboBox) SaveState() error {
+	var indexes []string
+	for _, index := range cb.CheckedIndexes() {
+		indexes = append(indexes, strconv.Itoa(index))
+	}
+
+	return cb.WriteState(strings.Join(indexes, " "))
+}
+
+func (cb *MultiSelectComboBox) RestoreState() error {
+	state, err := cb.ReadState()
+	if err != nil {
+		return err
+	}
+
+	var indexes []int
+	for _, field := range strings.Fields(state) {
+		if i, err := strconv.Atoi(field); err == nil && i < len(cb.checked) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return cb.SetCheckedIndexes(indexes)
+}
+
+func (cb *MultiSelectComboBox) toggle(index int) {
+	cb.SetChecked(index, !cb.Checked(index))
+}
+
+func (cb *MultiSelectComboBox) invalidateItem(index int) {
+	if cb.listHWnd == 0 {
+		return
+	}
+
+	var rc win.RECT
+	if win.LB_ERR != int(int32(win.SendMessage(cb.listHWnd, win.LB_GETITEMRECT, uintptr(index), uintptr(unsafe.Pointer(&rc))))) {
+		win.InvalidateRect(cb.listHWnd, &rc, true)
+	}
+}
+
+func (cb *MultiSelectComboBox) invalidateSummary() {
+	cb.Invalidate()
+}
+
+func (cb *MultiSelectComboBox) dropped() bool {
+	return 0 != cb.SendMessage(win.CB_GETDROPPEDSTATE, 0, 0)
+}
+
+func (cb *MultiSelectComboBox) drawItem(dis *win.DRAWITEMSTRUCT) {
+	canvas, err := newCanvasFromHDC(dis.HDC)
+	if err != nil {
+		return
+	}
+	defer canvas.Dispose()
+
+	bounds := rectangleFromRECT(dis.RcItem)
+	summary := dis.ItemState&win.ODS_COMBOBOXEDIT != 0 || int32(dis.ItemID) < 0
+
+	bgColor := Color(win.GetSysColor(win.COLOR_WINDOW))
+	textColor := Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+	if dis.ItemState&win.ODS_SELECTED != 0 {
+		bgColor = Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
+		textColor = Color(win.GetSysColor(win.COLOR_HIGHLIGHTTEXT))
+	}
+	if dis.ItemState&win.ODS_DISABLED != 0 {
+		textColor = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	if brush, err := NewSolidColorBrush(bgColor); err == nil {
+		canvas.FillRectanglePixels(brush, bounds)
+		brush.Dispose()
+	}
+
+	padding := IntFrom96DPI(2, cb.DPI())
+	textBounds := Rectangle{bounds.X + padding, bounds.Y, bounds.Width - 2*padding, bounds.Height}
+
+	var text string
+	if summary {
+		text = cb.Summary()
+	} else {
+		index := int(dis.ItemID)
+		text = cb.itemString(index)
+
+		size := cb.checkBoxSize()
+		rc := win.RECT{
+			Left: int32(textBounds.X),
+			Top:  int32(bounds.Y + (bounds.Height-size)/2),
+		}
+		rc.Right = rc.Left + int32(size)
+		rc.Bottom = rc.Top + int32(size)
+
+		cb.drawCheckBox(dis.HDC, &rc, cb.Checked(index), dis.ItemState&win.ODS_DISABLED != 0)
+
+		textBounds.X += size + 2*padding
+		textBounds.Width -= size + 2*padding
+	}
+
+	canvas.DrawTextPixels(text, cb.Font(), textColor, textBounds, TextLeft|TextVCenter|TextSingleLine|TextNoPrefix|TextEndEllipsis)
+
+	if summary && dis.ItemState&win.ODS_FOCUS != 0 {
+		win.DrawFocusRect(dis.HDC, &dis.RcItem)
+	}
+}
+
+func (cb *MultiSelectComboBox) drawCheckBox(hdc win.HDC, rc *win.RECT, checked, disabled bool) {
+	if hTheme := win.OpenThemeData(cb.hWnd, syscall.StringToUTF16Ptr("BUTTON")); hTheme != 0 {
+		defer win.CloseThemeData(hTheme)
+
+		stateID := int32(win.CBS_UNCHECKEDNORMAL)
+		if checked {
+			stateID = win.CBS_CHECKEDNORMAL
+		}
+		if disabled {
+			// The disabled states follow the normal, hot and pressed ones.
+			stateID += 3
+		}
+
+		if !win.FAILED(win.DrawThemeBackground(hTheme, hdc, win.BP_CHECKBOX, stateID, rc, nil)) {
+			return
+		}
+	}
+
+	state := uintptr(dfcsButtonCheck)
+	if checked {
+		state |= dfcsChecked
+	}
+	if disabled {
+		state |= dfcsInactive
+	}
+
+	drawFrameControl.Call(uintptr(hdc), uintptr(unsafe.Pointer(rc)), dfcButton, state)
+}
+
+func (cb *MultiSelectComboBox) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_DRAWITEM:
+		cb.drawItem((*win.DRAWITEMSTRUCT)(unsafe.Pointer(lParam)))
+		return win.TRUE
+
+	case win.WM_KEYDOWN:
+		if Key(wParam) == KeySpace && cb.dropped() {
+			if index := int(int32(cb.SendMessage(win.CB_GETCURSEL, 0, 0))); index > -1 {
+				cb.toggle(index)
+			}
+			return 0
+		}
+
+	case win.WM_CHAR:
+		if wParam == ' ' {
+			// Don't search for items starting with a space.
+			return 0
+		}
+
+	case win.WM_MOUSEWHEEL:
+		if !cb.Enabled() {
+			return 0
+		}
+	}
+
+	return cb.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (*MultiSelectComboBox) NeedsWmSize() bool {
+	return true
+}
+
+func (cb *MultiSelectComboBox) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	defaultSize := cb.dialogBaseUnitsToPixels(Size{30, 12})
+
+	if cb.model != nil && cb.maxItemTextWidth <= 0 {
+		cb.maxItemTextWidth = cb.calculateMaxItemTextWidth()
+	}
+
+	w := maxi(defaultSize.Width, cb.maxItemTextWidth+cb.checkBoxSize()+int(win.GetSystemMetricsForDpi(win.SM_CXVSCROLL, uint32(ctx.dpi)))+16)
+	h := maxi(defaultSize.Height+1, int(cb.SendMessage(win.CB_GETITEMHEIGHT, uintptr(selectionFieldIndex), 0))+6)
+
+	return &comboBoxLayoutItem{
+		layoutFlags: GrowableHorz,
+		idealSize:   Size{w, h},
+	}
+}
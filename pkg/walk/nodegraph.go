@@ -0,0 +1,904 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math"
+
+	"github.com/miu200521358/win"
+)
+
+// Node geometry in scene units.
+const (
+	nodeGraphNodeWidth     = 140
+	nodeGraphTitleHeight   = 24
+	nodeGraphPortRowHeight = 20
+	nodeGraphPortRadius    = 5
+	nodeGraphPortHitRadius = 8
+	nodeGraphCurveSegments = 24
+)
+
+var nodeGraphPortColors = []Color{
+	RGB(0x4e, 0x79, 0xa7),
+	RGB(0xf2, 0x8e, 0x2b),
+	RGB(0x59, 0xa1, 0x4f),
+	RGB(0xe1, 0x57, 0x59),
+	RGB(0x76, 0xb7, 0xb2),
+	RGB(0xed, 0xc9, 0x48),
+	RGB(0xb0, 0x7a, 0xa1),
+	RGB(0x9c, 0x75, 0x5f),
+}
+
+// NodeGraphConnectionValidator decides whether output may be connected to
+// input, in addition to the built-in rules of NodeGraph.CanConnect.
+type NodeGraphConnectionValidator func(output, input *NodeGraphPort) bool
+
+// NodeGraph is a SceneView for editing graphs of nodes with typed input and
+// output ports, e.g. for shader or constraint editors.
+//
+// Dragging from a port and dropping on a compatible port of another node
+// connects them; dragging from a connected input detaches its connection, so
+// it can be dropped elsewhere or discarded. The Delete key removes selected
+// connections. Selection, dragging nodes, panning and zooming work like in
+// SceneView.
+//
+// Nodes must be added with AddNode rather than AddItem.
+type NodeGraph struct {
+	SceneView
+	nodes                      []*NodeGraphNode
+	connections                []*NodeGraphConnection
+	validator                  NodeGraphConnectionValidator
+	pending                    *NodeGraphConnection
+	pendingFrom                *NodeGraphPort
+	targetPort                 *NodeGraphPort
+	persistent                 bool
+	connectionAddedPublisher   NodeGraphConnectionEventPublisher
+	connectionRemovedPublisher NodeGraphConnectionEventPublisher
+}
+
+// NewNodeGraph creates and initializes a new, empty NodeGraph.
+func NewNodeGraph(parent Container) (*NodeGraph, error) {
+	ng := new(NodeGraph)
+
+	if err := ng.SceneView.init(ng, parent); err != nil {
+		return nil, err
+	}
+
+	return ng, nil
+}
+
+// Nodes returns the nodes of the NodeGraph.
+func (ng *NodeGraph) Nodes() []*NodeGraphNode {
+	return append([]*NodeGraphNode(nil), ng.nodes...)
+}
+
+// Node returns the node with the specified id, or nil.
+func (ng *NodeGraph) Node(id string) *NodeGraphNode {
+	for _, node := range ng.nodes {
+		if node.id == id {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// AddNode adds node to the NodeGraph. The id of node must be unique within
+// the NodeGraph.
+func (ng *NodeGraph) AddNode(node *NodeGraphNode) error {
+	if node.id == "" {
+		return newError("node id must not be empty")
+	}
+	if node.graph != nil {
+		return newError("node already belongs to a NodeGraph")
+	}
+	if ng.Node(node.id) != nil {
+		return newError("duplicate node id: " + node.id)
+	}
+
+	if err := ng.AddItem(node); err != nil {
+		return err
+	}
+
+	node.graph = ng
+	ng.nodes = append(ng.nodes, node)
+
+	return nil
+}
+
+// RemoveNode removes node and all its connections from the NodeGraph.
+func (ng *NodeGraph) RemoveNode(node *NodeGraphNode) error {
+	index := -1
+	for i, n := range ng.nodes {
+		if n == node {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return newError("node not found")
+	}
+
+	for _, conn := range ng.Connections() {
+		if conn.output.node == node || conn.input.node == node {
+			ng.Disconnect(conn)
+		}
+	}
+
+	ng.nodes = append(ng.nodes[:index:index], ng.nodes[index+1:]...)
+	node.graph = nil
+
+	return ng.RemoveItem(node)
+}
+
+// Connections returns the connections of the NodeGraph.
+func (ng *NodeGraph) Connections() []*NodeGraphConnection {
+	return append([]*NodeGraphConnection(nil), ng.connections...)
+}
+
+// ConnectionsOf returns the connections attached to port.
+func (ng *NodeGraph) ConnectionsOf(port *NodeGraphPort) []*NodeGraphConnection {
+	var conns []*NodeGraphConnection
+	for _, conn := range ng.connections {
+		if conn.output == port || conn.input == port {
+			conns = append(conns, conn)
+		}
+	}
+
+	return conns
+}
+
+// ConnectionValidator returns the function that restricts which ports may be
+// connected, if any.
+func (ng *NodeGraph) ConnectionValidator() NodeGraphConnectionValidator {
+	return ng.validator
+}
+
+// SetConnectionValidator sets a function that restricts which ports may be
+// connected. It is consulted for connections made by the user and by
+// Connect.
+func (ng *NodeGraph) SetConnectionValidator(validator NodeGraphConnectionValidator) {
+	ng.validator = validator
+}
+
+// CanConnect returns whether output may be connected to input.
+//
+// Ports have to be an output and an input of different nodes of the
+// NodeGraph with equal types, where an empty type matches any type, that are
+// not connected yet. Finally the connection validator, if any, has to agree.
+func (ng *NodeGraph) CanConnect(output, input *NodeGraphPort) bool {
+	if output == nil || input == nil || !output.output || input.output {
+		return false
+	}
+	if output.node.graph != ng || input.node.graph != ng || output.node == input.node {
+		return false
+	}
+	if output.typ != "" && input.typ != "" && output.typ != input.typ {
+		return false
+	}
+
+	for _, conn := range ng.connections {
+		if conn.output == output && conn.input == input {
+			return false
+		}
+	}
+
+	return ng.validator == nil || ng.validator(output, input)
+}
+
+// Connect connects output to input. An input accepts a single connection, so
+// a connection already attached to input is removed.
+func (ng *NodeGraph) Connect(output, input *NodeGraphPort) (*NodeGraphConnection, error) {
+	if !ng.CanConnect(output, input) {
+		return nil, newError("ports cannot be connected")
+	}
+
+	for _, conn := range ng.ConnectionsOf(input) {
+		ng.Disconnect(conn)
+	}
+
+	conn := &NodeGraphConnection{output: output, input: input}
+	conn.immovable = true
+
+	if err := ng.AddItem(conn); err != nil {
+		return nil, err
+	}
+	ng.SendToBack(conn)
+
+	ng.connections = append(ng.connections, conn)
+
+	ng.connectionAddedPublisher.Publish(conn)
+
+	return conn, nil
+}
+
+// Disconnect removes conn from the NodeGraph.
+func (ng *NodeGraph) Disconnect(conn *NodeGraphConnection) error {
+	index := -1
+	for i, c := range ng.connections {
+		if c == conn {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return newError("connection not found")
+	}
+
+	ng.connections = append(ng.connections[:index:index], ng.connections[index+1:]...)
+
+	if err := ng.RemoveItem(conn); err != nil {
+		return err
+	}
+
+	ng.connectionRemovedPublisher.Publish(conn)
+
+	return nil
+}
+
+// ConnectionAdded returns the event that is published when a connection was
+// made.
+func (ng *NodeGraph) ConnectionAdded() *NodeGraphConnectionEvent {
+	return ng.connectionAddedPublisher.Event()
+}
+
+// ConnectionRemoved returns the event that is published when a connection was
+// removed.
+func (ng *NodeGraph) ConnectionRemoved() *NodeGraphConnectionEvent {
+	return ng.connectionRemovedPublisher.Event()
+}
+
+// portAt returns the port whose connector is hit by p, or nil.
+func (ng *NodeGraph) portAt(p ScenePoint) *NodeGraphPort {
+	for i := len(ng.nodes) - 1; i >= 0; i-- {
+		node := ng.nodes[i]
+		if !node.Visible() {
+			continue
+		}
+
+		for _, ports := range [][]*NodeGraphPort{node.inputs, node.outputs} {
+			for _, port := range ports {
+				c := port.Center()
+				if math.Hypot(p.X-c.X, p.Y-c.Y) <= nodeGraphPortHitRadius {
+					return port
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// connectTarget returns the port at p that the connection being dragged
+// could be dropped on, or nil.
+func (ng *NodeGraph) connectTarget(p ScenePoint) *NodeGraphPort {
+	port := ng.portAt(p)
+	if port == nil || port == ng.pendingFrom {
+		return nil
+	}
+
+	output, input := ng.pendingFrom, port
+	if !output.output {
+		output, input = input, output
+	}
+
+	if !ng.CanConnect(output, input) {
+		return nil
+	}
+
+	return port
+}
+
+func (ng *NodeGraph) beginConnect(port *NodeGraphPort, p ScenePoint) {
+	from := port
+
+	if !port.output {
+		if conns := ng.ConnectionsOf(port); len(conns) > 0 {
+			from = conns[0].output
+			ng.Disconnect(conns[0])
+		}
+	}
+
+	ng.pendingFrom = from
+	ng.targetPort = nil
+
+	ng.pending = &NodeGraphConnection{loose: p}
+	ng.pending.immovable = true
+	ng.pending.unselectable = true
+
+	if from.output {
+		ng.pending.output = from
+	} else {
+		ng.pending.input = from
+	}
+
+	ng.AddItem(ng.pending)
+}
+
+// endConnect finishes dragging a connection, connecting it if it was dropped
+// on a compatible port.
+func (ng *NodeGraph) endConnect(commit bool) {
+	if ng.pending == nil {
+		return
+	}
+
+	ng.RemoveItem(ng.pending)
+
+	from, target := ng.pendingFrom, ng.targetPort
+
+	ng.pending = nil
+	ng.pendingFrom = nil
+	ng.targetPort = nil
+
+	if !commit || target == nil {
+		return
+	}
+
+	if from.output {
+		ng.Connect(from, target)
+	} else {
+		ng.Connect(target, from)
+	}
+}
+
+type nodeGraphLayoutNode struct {
+	ID string  `json:"id"`
+	X  float64 `json:"x"`
+	Y  float64 `json:"y"`
+}
+
+type nodeGraphLayoutConnection struct {
+	Output     string `json:"output"`
+	OutputPort string `json:"outputPort"`
+	Input      string `json:"input"`
+	InputPort  string `json:"inputPort"`
+}
+
+type nodeGraphLayout struct {
+	Zoom        float64                     `json:"zoom"`
+	OriginX     float64                     `json:"originX"`
+	OriginY     float64                     `json:"originY"`
+	Nodes       []nodeGraphLayoutNode       `json:"nodes"`
+	Connections []nodeGraphLayoutConnection `json:"connections"`
+}
+
+// SaveLayout returns the node positions, connections and view of the
+// NodeGraph as JSON.
+func (ng *NodeGraph) SaveLayout() (string, error) {
+	layout := nodeGraphLayout{
+		Zoom:    ng.zoom,
+		OriginX: ng.origin.X,
+		OriginY: ng.origin.Y,
+	}
+
+	for _, node := range ng.nodes {
+		p := node.Position()
+		layout.Nodes = append(layout.Nodes, nodeGraphLayoutNode{node.id, p.X, p.Y})
+	}
+
+	for _, conn := range ng.connections {
+		layout.Connections = append(layout.Connections, nodeGraphLayoutConnection{
+			Output:     conn.output.node.id,
+			OutputPort: conn.output.name,
+			Input:      conn.input.node.id,
+			InputPort:  conn.input.name,
+		})
+	}
+
+	data, err := json.Marshal(layout)
+	if err != nil {
+		return "", wrapError(err)
+	}
+
+	return string(data), nil
+}
+
+// RestoreLayout applies a layout returned by SaveLayout to the nodes of the
+// NodeGraph, which are matched by id.
+//
+// The connections of the NodeGraph are replaced by those of the layout.
+// Nodes, ports and connections that don't exist or aren't allowed anymore are
+// skipped.
+func (ng *NodeGraph) RestoreLayout(s string) error {
+	var layout nodeGraphLayout
+	if err := json.Unmarshal([]byte(s), &layout); err != nil {
+		return wrapError(err)
+	}
+
+	for _, ln := range layout.Nodes {
+		if node := ng.Node(ln.ID); node != nil {
+			node.SetPosition(ScenePoint{ln.X, ln.Y})
+		}
+	}
+
+	for _, conn := range ng.Connections() {
+		ng.Disconnect(conn)
+	}
+
+	for _, lc := range layout.Connections {
+		output, input := ng.Node(lc.Output), ng.Node(lc.Input)
+		if output == nil || input == nil {
+			continue
+		}
+
+		if op, ip := output.Output(lc.OutputPort), input.Input(lc.InputPort); ng.CanConnect(op, ip) {
+			ng.Connect(op, ip)
+		}
+	}
+
+	if layout.Zoom > 0 {
+		if err := ng.ZoomAt(layout.Zoom, Point{}); err != nil {
+			return err
+		}
+	}
+
+	ng.SetViewOrigin(ScenePoint{layout.OriginX, layout.OriginY})
+
+	return nil
+}
+
+func (ng *NodeGraph) Persistent() bool {
+	return ng.persistent
+}
+
+func (ng *NodeGraph) SetPersistent(value bool) {
+	ng.persistent = value
+}
+
+func (ng *NodeGraph) SaveState() error {
+	layout, err := ng.SaveLayout()
+	if err != nil {
+		return err
+	}
+
+	return ng.WriteState(layout)
+}
+
+// RestoreState restores the layout saved by SaveState. If nodes are added
+// after the NodeGraph was created, call it again once they are in place.
+func (ng *NodeGraph) RestoreState() error {
+	s, err := ng.ReadState()
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+
+	return ng.RestoreLayout(s)
+}
+
+func (ng *NodeGraph) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_LBUTTONDOWN:
+		if !ng.Enabled() {
+			break
+		}
+
+		p := ng.MapToScene(Point{int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))})
+
+		if port := ng.portAt(p); port != nil {
+			ng.SetFocus()
+			win.SetCapture(hwnd)
+
+			ng.beginConnect(port, p)
+			return 0
+		}
+
+	case win.WM_MOUSEMOVE:
+		if ng.pending == nil {
+			break
+		}
+
+		p := ng.MapToScene(Point{int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))})
+
+		ng.targetPort = ng.connectTarget(p)
+		if ng.targetPort != nil {
+			p = ng.targetPort.Center()
+		}
+
+		ng.pending.loose = p
+		ng.Invalidate()
+
+	case win.WM_LBUTTONUP:
+		if ng.pending != nil {
+			ng.endConnect(true)
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		ng.endConnect(false)
+
+	case win.WM_KEYDOWN:
+		switch Key(wParam) {
+		case KeyEscape:
+			if ng.pending != nil {
+				ng.endConnect(false)
+				win.ReleaseCapture()
+				return 0
+			}
+
+		case KeyDelete:
+			for _, item := range ng.SelectedItems() {
+				if conn, ok := item.(*NodeGraphConnection); ok {
+					ng.Disconnect(conn)
+				}
+			}
+		}
+	}
+
+	return ng.SceneView.WndProc(hwnd, msg, wParam, lParam)
+}
+
+// nodeGraphPortColor returns the color ports of type typ are drawn with.
+func nodeGraphPortColor(typ string) Color {
+	if typ == "" {
+		return Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(typ))
+
+	return nodeGraphPortColors[h.Sum32()%uint32(len(nodeGraphPortColors))]
+}
+
+// NodeGraphPort is an input or output of a NodeGraphNode.
+type NodeGraphPort struct {
+	node   *NodeGraphNode
+	name   string
+	typ    string
+	output bool
+	index  int
+}
+
+// Node returns the node the port belongs to.
+func (p *NodeGraphPort) Node() *NodeGraphNode {
+	return p.node
+}
+
+// Name returns the name of the port, which is unique among the inputs resp.
+// outputs of its node.
+func (p *NodeGraphPort) Name() string {
+	return p.name
+}
+
+// Type returns the type of the port. Only ports of equal type can be
+// connected, an empty type matches any type.
+func (p *NodeGraphPort) Type() string {
+	return p.typ
+}
+
+// IsOutput returns whether the port is an output, rather than an input.
+func (p *NodeGraphPort) IsOutput() bool {
+	return p.output
+}
+
+// Center returns the center of the connector of the port in scene
+// coordinates.
+func (p *NodeGraphPort) Center() ScenePoint {
+	b := p.node.bounds
+
+	x := b.X
+	if p.output {
+		x += b.Width
+	}
+
+	return ScenePoint{x, b.Y + nodeGraphTitleHeight + (float64(p.index)+0.5)*nodeGraphPortRowHeight}
+}
+
+// NodeGraphNode is a node of a NodeGraph, with a title and rows of input
+// ports on the left and output ports on the right.
+type NodeGraphNode struct {
+	SceneItemBase
+	graph   *NodeGraph
+	id      string
+	title   string
+	inputs  []*NodeGraphPort
+	outputs []*NodeGraphPort
+}
+
+// NewNodeGraphNode returns a new NodeGraphNode without ports.
+func NewNodeGraphNode(id, title string) *NodeGraphNode {
+	node := &NodeGraphNode{id: id, title: title}
+	node.updateSize()
+
+	return node
+}
+
+// Graph returns the NodeGraph the node was added to, if any.
+func (n *NodeGraphNode) Graph() *NodeGraph {
+	return n.graph
+}
+
+// ID returns the id of the node.
+func (n *NodeGraphNode) ID() string {
+	return n.id
+}
+
+// Title returns the title of the node.
+func (n *NodeGraphNode) Title() string {
+	return n.title
+}
+
+// SetTitle sets the title of the node.
+func (n *NodeGraphNode) SetTitle(title string) {
+	n.title = title
+	n.Update()
+}
+
+// AddInput adds an input port of type typ to the node and returns it.
+func (n *NodeGraphNode) AddInput(name, typ string) (*NodeGraphPort, error) {
+	return n.addPort(&n.inputs, name, typ, false)
+}
+
+// AddOutput adds an output port of type typ to the node and returns it.
+func (n *NodeGraphNode) AddOutput(name, typ string) (*NodeGraphPort, error) {
+	return n.addPort(&n.outputs, name, typ, true)
+}
+
+func (n *NodeGraphNode) addPort(ports *[]*NodeGraphPort, name, typ string, output bool) (*NodeGraphPort, error) {
+	if n.port(*ports, name) != nil {
+		return nil, newError("duplicate port name: " + name)
+	}
+
+	port := &NodeGraphPort{node: n, name: name, typ: typ, output: output, index: len(*ports)}
+	*ports = append(*ports, port)
+
+	n.updateSize()
+
+	return port, nil
+}
+
+// Inputs returns the input ports of the node.
+func (n *NodeGraphNode) Inputs() []*NodeGraphPort {
+	return append([]*NodeGraphPort(nil), n.inputs...)
+}
+
+// Outputs returns the output ports of the node.
+func (n *NodeGraphNode) Outputs() []*NodeGraphPort {
+	return append([]*NodeGraphPort(nil), n.outputs...)
+}
+
+// Input returns the input port with the specified name, or nil.
+func (n *NodeGraphNode) Input(name string) *NodeGraphPort {
+	return n.port(n.inputs, name)
+}
+
+// Output returns the output port with the specified name, or nil.
+func (n *NodeGraphNode) Output(name string) *NodeGraphPort {
+	return n.port(n.outputs, name)
+}
+
+func (n *NodeGraphNode) port(ports []*NodeGraphPort, name string) *NodeGraphPort {
+	for _, port := range ports {
+		if port.name == name {
+			return port
+		}
+	}
+
+	return nil
+}
+
+func (n *NodeGraphNode) updateSize() {
+	rows := maxi(len(n.inputs), len(n.outputs))
+
+	n.SetBounds(SceneRect{
+		n.bounds.X,
+		n.bounds.Y,
+		nodeGraphNodeWidth,
+		nodeGraphTitleHeight + float64(rows)*nodeGraphPortRowHeight + 6,
+	})
+}
+
+func (n *NodeGraphNode) Paint(canvas *Canvas, view *SceneView) error {
+	bounds := view.MapRectFromScene(n.bounds)
+	corner := view.MapSizeFromScene(8)
+	ellipse := Size{corner, corner}
+
+	bodyBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_WINDOW)))
+	if err != nil {
+		return err
+	}
+	defer bodyBrush.Dispose()
+
+	if err := canvas.FillRoundedRectanglePixels(bodyBrush, bounds, ellipse); err != nil {
+		return err
+	}
+
+	titleBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_BTNFACE)))
+	if err != nil {
+		return err
+	}
+	defer titleBrush.Dispose()
+
+	titleBounds := bounds
+	titleBounds.Height = view.MapSizeFromScene(nodeGraphTitleHeight)
+
+	if err := canvas.FillRoundedRectanglePixels(titleBrush, titleBounds, ellipse); err != nil {
+		return err
+	}
+	// Square off the lower corners of the title bar.
+	if err := canvas.FillRectanglePixels(titleBrush, Rectangle{titleBounds.X, titleBounds.Y + titleBounds.Height/2, titleBounds.Width, titleBounds.Height - titleBounds.Height/2}); err != nil {
+		return err
+	}
+
+	outlineColor := Color(win.GetSysColor(win.COLOR_BTNSHADOW))
+	outlineWidth := 1.0
+	if n.selected {
+		outlineColor = Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
+		outlineWidth = 2
+	}
+
+	outlineBrush, err := NewSolidColorBrush(outlineColor)
+	if err != nil {
+		return err
+	}
+	defer outlineBrush.Dispose()
+
+	outlinePen, err := NewGeometricPen(PenSolid|PenInsideFrame, maxi(1, view.MapSizeFromScene(outlineWidth)), outlineBrush)
+	if err != nil {
+		return err
+	}
+	defer outlinePen.Dispose()
+
+	if err := canvas.DrawRoundedRectanglePixels(outlinePen, bounds, ellipse); err != nil {
+		return err
+	}
+
+	textColor := Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+	font := view.Font()
+	inset := view.MapSizeFromScene(nodeGraphPortRadius + 5)
+
+	if err := view.drawScaledText(canvas, n.title, font, textColor, Rectangle{titleBounds.X + inset, titleBounds.Y, titleBounds.Width - 2*inset, titleBounds.Height}, TextLeft|TextVCenter|TextSingleLine|TextEndEllipsis); err != nil {
+		return err
+	}
+
+	rowHeight := view.MapSizeFromScene(nodeGraphPortRowHeight)
+	radius := view.MapSizeFromScene(nodeGraphPortRadius)
+
+	for _, ports := range [][]*NodeGraphPort{n.inputs, n.outputs} {
+		for _, port := range ports {
+			c := view.MapFromScene(port.Center())
+
+			brush, err := NewSolidColorBrush(nodeGraphPortColor(port.typ))
+			if err != nil {
+				return err
+			}
+
+			err = canvas.FillEllipsePixels(brush, Rectangle{c.X - radius, c.Y - radius, 2 * radius, 2 * radius})
+			brush.Dispose()
+			if err != nil {
+				return err
+			}
+
+			textBounds := Rectangle{bounds.X + inset, c.Y - rowHeight/2, bounds.Width/2 - inset, rowHeight}
+			format := TextLeft
+			if port.output {
+				textBounds.X = bounds.X + bounds.Width/2
+				format = TextRight
+			}
+
+			if err := view.drawScaledText(canvas, port.name, font, textColor, textBounds, format|TextVCenter|TextSingleLine|TextEndEllipsis); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// NodeGraphConnection connects an output port to an input port of a
+// NodeGraph. It is drawn as bezier curve below the nodes.
+type NodeGraphConnection struct {
+	SceneItemBase
+	output *NodeGraphPort
+	input  *NodeGraphPort
+	loose  ScenePoint // the free end while being dragged
+}
+
+// Output returns the output port the connection starts at.
+func (c *NodeGraphConnection) Output() *NodeGraphPort {
+	return c.output
+}
+
+// Input returns the input port the connection ends at.
+func (c *NodeGraphConnection) Input() *NodeGraphPort {
+	return c.input
+}
+
+// curve returns points along the bezier curve of the connection.
+func (c *NodeGraphConnection) curve() []ScenePoint {
+	from, to := c.loose, c.loose
+	if c.output != nil {
+		from = c.output.Center()
+	}
+	if c.input != nil {
+		to = c.input.Center()
+	}
+
+	dx := math.Max(40, math.Abs(to.X-from.X)/2)
+	c1 := ScenePoint{from.X + dx, from.Y}
+	c2 := ScenePoint{to.X - dx, to.Y}
+
+	points := make([]ScenePoint, nodeGraphCurveSegments+1)
+	for i := range points {
+		t := float64(i) / nodeGraphCurveSegments
+		u := 1 - t
+
+		a, b, cc, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+
+		points[i] = ScenePoint{
+			a*from.X + b*c1.X + cc*c2.X + d*to.X,
+			a*from.Y + b*c1.Y + cc*c2.Y + d*to.Y,
+		}
+	}
+
+	return points
+}
+
+// HitTest returns whether p lies close to the curve of the connection.
+func (c *NodeGraphConnection) HitTest(p ScenePoint) bool {
+	const tolerance = 4
+
+	points := c.curve()
+	for i := 1; i < len(points); i++ {
+		if distanceToSegment(p, points[i-1], points[i]) <= tolerance {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *NodeGraphConnection) Paint(canvas *Canvas, view *SceneView) error {
+	color := Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	switch {
+	case c.selected:
+		color = Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
+
+	case c.output != nil:
+		color = nodeGraphPortColor(c.output.typ)
+
+	case c.input != nil:
+		color = nodeGraphPortColor(c.input.typ)
+	}
+
+	brush, err := NewSolidColorBrush(color)
+	if err != nil {
+		return err
+	}
+	defer brush.Dispose()
+
+	pen, err := NewGeometricPen(PenSolid|PenCapRound, maxi(1, view.MapSizeFromScene(2)), brush)
+	if err != nil {
+		return err
+	}
+	defer pen.Dispose()
+
+	curve := c.curve()
+	points := make([]Point, len(curve))
+	for i, p := range curve {
+		points[i] = view.MapFromScene(p)
+	}
+
+	return canvas.DrawPolylinePixels(pen, points)
+}
+
+// distanceToSegment returns the distance of p from the line segment a-b.
+func distanceToSegment(p, a, b ScenePoint) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l))
+	}
+
+	return math.Hypot(p.X-(a.X+t*dx), p.Y-(a.Y+t*dy))
+}
@@ -9,6 +9,7 @@ package walk
 
 import (
 	"syscall"
+	"time"
 	"unsafe"
 
 	"github.com/miu200521358/win"
@@ -16,6 +17,27 @@ import (
 
 var notifyIcons = make(map[*NotifyIcon]bool)
 
+var (
+	getDoubleClickTime     = syscall.NewLazyDLL("user32.dll").NewProc("GetDoubleClickTime")
+	shellNotifyIconGetRect = syscall.NewLazyDLL("shell32.dll").NewProc("Shell_NotifyIconGetRect")
+)
+
+const niifIconMask = 0x0000000F
+
+type notifyIconIdentifier struct {
+	cbSize   uint32
+	hWnd     win.HWND
+	uID      uint32
+	guidItem syscall.GUID
+}
+
+// doubleClickTime returns the maximum time between the clicks of a double
+// click.
+func doubleClickTime() time.Duration {
+	ms, _, _ := getDoubleClickTime.Call()
+	return time.Duration(ms) * time.Millisecond
+}
+
 func notifyIconWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) (result uintptr) {
 	// Retrieve our *NotifyIcon from the message window.
 	ptr := win.GetWindowLongPtr(hwnd, win.GWLP_USERDATA)
@@ -28,44 +50,41 @@ func notifyIconWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) (resul
 	case win.WM_LBUTTONUP:
 		ni.publishMouseEvent(&ni.mouseUpPublisher, LeftButton)
 
+		ni.onLeftButtonUp()
+
+	case win.WM_LBUTTONDBLCLK:
+		ni.onLeftButtonDoubleClick()
+
+	case win.NIN_KEYSELECT:
+		ni.onKeySelect()
+
 	case win.WM_RBUTTONDOWN:
 		ni.publishMouseEvent(&ni.mouseDownPublisher, RightButton)
 
 	case win.WM_RBUTTONUP:
 		ni.publishMouseEvent(&ni.mouseUpPublisher, RightButton)
 
-		win.SendMessage(hwnd, msg, wParam, win.WM_CONTEXTMENU)
-
-	case win.WM_CONTEXTMENU:
-		if ni.contextMenu.Actions().Len() == 0 {
-			break
-		}
-
-		win.SetForegroundWindow(hwnd)
-
 		var p win.POINT
 		if !win.GetCursorPos(&p) {
 			lastError("GetCursorPos")
 		}
 
-		ni.applyDPI()
-
-		actionId := uint16(win.TrackPopupMenuEx(
-			ni.contextMenu.hMenu,
-			win.TPM_NOANIMATION|win.TPM_RETURNCMD,
-			p.X,
-			p.Y,
-			hwnd,
-			nil))
-		if actionId != 0 {
-			if action, ok := actionsById[actionId]; ok {
-				action.raiseTriggered()
-			}
-		}
+		ni.showContextMenu(p)
+
+	case win.WM_CONTEXTMENU:
+		// The shell sends this when the menu is requested by the keyboard,
+		// e.g. with Shift+F10, in which case the cursor may be anywhere.
+		ni.showContextMenu(ni.anchorPoint())
 
 		return 0
+
 	case win.NIN_BALLOONUSERCLICK:
 		ni.messageClickedPublisher.Publish()
+
+		ni.finishBalloon(true)
+
+	case win.NIN_BALLOONTIMEOUT, win.NIN_BALLOONHIDE:
+		ni.finishBalloon(false)
 	}
 
 	return win.DefWindowProc(hwnd, msg, wParam, lParam)
@@ -83,6 +102,16 @@ type NotifyIcon struct {
 	mouseDownPublisher      MouseEventPublisher
 	mouseUpPublisher        MouseEventPublisher
 	messageClickedPublisher EventPublisher
+	clickedPublisher        EventPublisher
+	doubleClickedPublisher  EventPublisher
+	clickTimer              *UITimer
+	ignoreNextButtonUp      bool
+	lastKeySelect           time.Time
+	showingContextMenu      bool
+	balloon                 *BalloonOptions // shown last, until closed
+	toastAppID              string
+	toastActivatedHandler   *systemToastActivatedHandler
+	toastActivatedPublisher StringEventPublisher
 }
 
 // NewNotifyIcon creates and returns a new NotifyIcon.
@@ -218,6 +247,8 @@ func (ni *NotifyIcon) Dispose() error {
 	}
 	delete(notifyIcons, ni)
 
+	ni.cancelClick()
+
 	nid := ni.notifyIconData()
 
 	if !win.Shell_NotifyIcon(win.NIM_DELETE, nid) {
@@ -230,11 +261,15 @@ func (ni *NotifyIcon) Dispose() error {
 }
 
 func (ni *NotifyIcon) showMessage(title, info string, iconType uint32, icon Image) error {
+	return ni.showBalloon(title, info, iconType, icon, nil)
+}
+
+func (ni *NotifyIcon) showBalloon(title, info string, iconType uint32, icon Image, balloon *BalloonOptions) error {
 	nid := ni.notifyIconData()
 	nid.UFlags = win.NIF_INFO
 	nid.DwInfoFlags = iconType
 	var oldIcon Image
-	if iconType == win.NIIF_USER && icon != nil {
+	if iconType&niifIconMask == win.NIIF_USER && icon != nil {
 		oldIcon = ni.icon
 		if err := ni.setNIDIcon(nid, icon); err != nil {
 			return err
@@ -255,6 +290,10 @@ func (ni *NotifyIcon) showMessage(title, info string, iconType uint32, icon Imag
 		ni.SetIcon(oldIcon)
 	}
 
+	// The new balloon replaces the one shown before, if still there.
+	ni.finishBalloon(false)
+	ni.balloon = balloon
+
 	return nil
 }
 
@@ -294,6 +333,97 @@ func (ni *NotifyIcon) ShowCustom(title, info string, icon Image) error {
 	return ni.showMessage(title, info, win.NIIF_USER, icon)
 }
 
+// BalloonKind specifies the standard icon of a balloon.
+type BalloonKind int
+
+const (
+	BalloonNone BalloonKind = iota
+	BalloonInfo
+	BalloonWarning
+	BalloonError
+)
+
+// BalloonOptions specifies a balloon notification shown by ShowBalloon.
+type BalloonOptions struct {
+	Title string
+	Text  string
+
+	// Kind selects a standard icon, unless Icon is not nil.
+	Kind BalloonKind
+
+	// Icon, if not nil, is shown instead of a standard icon.
+	Icon Image
+
+	// LargeIcon shows Icon at the size of large icons.
+	LargeIcon bool
+
+	// Silent keeps the balloon from playing a sound.
+	Silent bool
+
+	// RespectQuietTime keeps the balloon from being shown during the first
+	// hour after a new user logs on or while in full screen mode.
+	RespectQuietTime bool
+
+	// OnClicked, if not nil, is called when the user clicks the balloon.
+	OnClicked func()
+
+	// OnClosed, if not nil, is called when the balloon goes away without
+	// being clicked, because it timed out, was closed by the user or was
+	// replaced by another balloon.
+	OnClosed func()
+}
+
+// ShowBalloon displays a balloon notification above the NotifyIcon, which
+// calls back when it is clicked or closed. MessageClicked is published too.
+//
+// The NotifyIcon must be visible before calling this method.
+func (ni *NotifyIcon) ShowBalloon(options BalloonOptions) error {
+	var flags uint32
+	switch {
+	case options.Icon != nil:
+		flags = win.NIIF_USER
+		if options.LargeIcon {
+			flags |= win.NIIF_LARGE_ICON
+		}
+
+	case options.Kind == BalloonInfo:
+		flags = win.NIIF_INFO
+
+	case options.Kind == BalloonWarning:
+		flags = win.NIIF_WARNING
+
+	case options.Kind == BalloonError:
+		flags = win.NIIF_ERROR
+	}
+
+	if options.Silent {
+		flags |= win.NIIF_NOSOUND
+	}
+	if options.RespectQuietTime {
+		flags |= win.NIIF_RESPECT_QUIET_TIME
+	}
+
+	return ni.showBalloon(options.Title, options.Text, flags, options.Icon, &options)
+}
+
+// finishBalloon calls back the options of the balloon shown last, if it wasn't
+// finished yet.
+func (ni *NotifyIcon) finishBalloon(clicked bool) {
+	balloon := ni.balloon
+	if balloon == nil {
+		return
+	}
+	ni.balloon = nil
+
+	if clicked {
+		if balloon.OnClicked != nil {
+			balloon.OnClicked()
+		}
+	} else if balloon.OnClosed != nil {
+		balloon.OnClosed()
+	}
+}
+
 // ContextMenu returns the context menu of the NotifyIcon.
 func (ni *NotifyIcon) ContextMenu() *Menu {
 	return ni.contextMenu
@@ -417,3 +547,119 @@ func (ni *NotifyIcon) MouseUp() *MouseEvent {
 func (ni *NotifyIcon) MessageClicked() *Event {
 	return ni.messageClickedPublisher.Event()
 }
+
+// Clicked returns the event that is published when the NotifyIcon is clicked
+// with the left mouse button, or selected with the keyboard.
+//
+// To tell clicks from double clicks, a click is only published once the
+// double click time has passed without a second click.
+func (ni *NotifyIcon) Clicked() *Event {
+	return ni.clickedPublisher.Event()
+}
+
+// DoubleClicked returns the event that is published when the NotifyIcon is
+// double clicked with the left mouse button. Clicked is not published for
+// either click.
+func (ni *NotifyIcon) DoubleClicked() *Event {
+	return ni.doubleClickedPublisher.Event()
+}
+
+func (ni *NotifyIcon) onLeftButtonUp() {
+	if ni.ignoreNextButtonUp {
+		// This ends the double click.
+		ni.ignoreNextButtonUp = false
+		return
+	}
+
+	ni.cancelClick()
+
+	var t *UITimer
+	t, err := NewUITimer(doubleClickTime(), func() {
+		t.Dispose()
+		ni.clickTimer = nil
+
+		ni.clickedPublisher.Publish()
+	})
+	if err != nil {
+		// Better early than never.
+		ni.clickedPublisher.Publish()
+		return
+	}
+
+	ni.clickTimer = t
+}
+
+func (ni *NotifyIcon) onLeftButtonDoubleClick() {
+	ni.cancelClick()
+	ni.ignoreNextButtonUp = true
+
+	ni.doubleClickedPublisher.Publish()
+}
+
+func (ni *NotifyIcon) onKeySelect() {
+	// The shell sends NIN_KEYSELECT twice for the Enter key.
+	now := time.Now()
+	if now.Sub(ni.lastKeySelect) < doubleClickTime() {
+		return
+	}
+	ni.lastKeySelect = now
+
+	ni.clickedPublisher.Publish()
+}
+
+// cancelClick drops a click that is waiting for the double click time to pass.
+func (ni *NotifyIcon) cancelClick() {
+	if ni.clickTimer != nil {
+		ni.clickTimer.Dispose()
+		ni.clickTimer = nil
+	}
+}
+
+// anchorPoint returns the center of the NotifyIcon on the screen, or the
+// cursor position if the shell doesn't tell.
+func (ni *NotifyIcon) anchorPoint() win.POINT {
+	nii := notifyIconIdentifier{hWnd: ni.hWnd, uID: ni.id}
+	nii.cbSize = uint32(unsafe.Sizeof(nii))
+
+	var rc win.RECT
+	if shellNotifyIconGetRect.Find() == nil {
+		if hr, _, _ := shellNotifyIconGetRect.Call(uintptr(unsafe.Pointer(&nii)), uintptr(unsafe.Pointer(&rc))); win.SUCCEEDED(win.HRESULT(hr)) {
+			return win.POINT{X: (rc.Left + rc.Right) / 2, Y: (rc.Top + rc.Bottom) / 2}
+		}
+	}
+
+	var p win.POINT
+	if !win.GetCursorPos(&p) {
+		lastError("GetCursorPos")
+	}
+
+	return p
+}
+
+func (ni *NotifyIcon) showContextMenu(p win.POINT) {
+	if ni.showingContextMenu || ni.contextMenu.Actions().Len() == 0 {
+		return
+	}
+
+	ni.showingContextMenu = true
+	defer func() {
+		ni.showingContextMenu = false
+	}()
+
+	win.SetForegroundWindow(ni.hWnd)
+
+	ni.applyDPI()
+
+	actionId := uint16(win.TrackPopupMenuEx(
+		ni.contextMenu.hMenu,
+		win.TPM_NOANIMATION|win.TPM_RETURNCMD,
+		p.X,
+		p.Y,
+		ni.hWnd,
+		nil))
+	if actionId != 0 {
+		if action, ok := actionsById[actionId]; ok {
+			action.raiseTriggered()
+		}
+	}
+}
@@ -0,0 +1,436 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows/registry"
+
+	"github.com/miu200521358/win"
+)
+
+// SystemToast is a toast notification of Windows, shown by
+// NotifyIcon.ShowSystemToast. Unlike a Toast, it is shown by the shell, next
+// to the taskbar, and stays in the notification center.
+type SystemToast struct {
+	Title string
+	Text  string
+
+	// Arguments are published by ToastActivated when the body of the toast is
+	// clicked.
+	Arguments string
+
+	// Actions are offered as buttons at the bottom of the toast.
+	Actions []SystemToastAction
+
+	// Silent keeps the toast from playing a sound.
+	Silent bool
+}
+
+// SystemToastAction is a button of a SystemToast.
+type SystemToastAction struct {
+	Text string
+
+	// Arguments are published by ToastActivated when the button is clicked.
+	// They default to Text.
+	Arguments string
+}
+
+var (
+	iidIToastNotificationManagerStatics = win.IID{Data1: 0x50AC103F, Data2: 0xD235, Data3: 0x4598, Data4: [8]byte{0xBB, 0xEF, 0x98, 0xFE, 0x4D, 0x1A, 0x3A, 0xD4}}
+	iidIToastNotificationFactory        = win.IID{Data1: 0x04124B20, Data2: 0x82C6, Data3: 0x4229, Data4: [8]byte{0xB1, 0x09, 0xFD, 0x9E, 0xD4, 0x66, 0x2B, 0x53}}
+	iidIToastActivatedEventArgs         = win.IID{Data1: 0xE3BF92F3, Data2: 0xC197, Data3: 0x436F, Data4: [8]byte{0x82, 0x65, 0x06, 0x25, 0x82, 0x4F, 0x8D, 0xAC}}
+	iidIXmlDocument                     = win.IID{Data1: 0xF7F3A506, Data2: 0x1E87, Data3: 0x42D6, Data4: [8]byte{0xBC, 0xFB, 0xB8, 0xC8, 0x09, 0xFA, 0x54, 0x94}}
+	iidIXmlDocumentIO                   = win.IID{Data1: 0x6CD0E74E, Data2: 0xEE65, Data3: 0x4489, Data4: [8]byte{0x9E, 0xBF, 0xCA, 0x43, 0xE8, 0x7B, 0xA6, 0x37}}
+	iidIAgileObject                     = win.IID{Data1: 0x94EA2B94, Data2: 0xE9CC, Data3: 0x49E0, Data4: [8]byte{0xC0, 0xFF, 0xEE, 0x64, 0xCA, 0x8F, 0x5B, 0x90}}
+
+	// ITypedEventHandler<ToastNotification*, IInspectable*>
+	iidToastActivatedHandler = win.IID{Data1: 0xAB54DE2D, Data2: 0x97D9, Data3: 0x5528, Data4: [8]byte{0xB6, 0xAD, 0x10, 0x5A, 0xFE, 0x15, 0x65, 0x30}}
+)
+
+// Indexes into the vtables of the WinRT interfaces, after the 6 methods of
+// IInspectable.
+const (
+	toastNotificationManagerCreateToastNotifierWithId = 7
+	toastNotificationFactoryCreateToastNotification   = 6
+	toastNotificationAddActivated                     = 11
+	toastNotifierShow                                 = 6
+	toastActivatedEventArgsGetArguments               = 6
+	xmlDocumentIOLoadXml                              = 6
+)
+
+var (
+	libcombase                = syscall.NewLazyDLL("combase.dll")
+	roInitialize              = libcombase.NewProc("RoInitialize")
+	roActivateInstance        = libcombase.NewProc("RoActivateInstance")
+	roGetActivationFactory    = libcombase.NewProc("RoGetActivationFactory")
+	windowsCreateString       = libcombase.NewProc("WindowsCreateString")
+	windowsDeleteString       = libcombase.NewProc("WindowsDeleteString")
+	windowsGetStringRawBuffer = libcombase.NewProc("WindowsGetStringRawBuffer")
+	toastActivatedHandlerVtbl *systemToastActivatedHandlerVtbl
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		toastActivatedHandlerVtbl = &systemToastActivatedHandlerVtbl{
+			syscall.NewCallback(systemToastActivatedHandler_QueryInterface),
+			syscall.NewCallback(systemToastActivatedHandler_AddRef),
+			syscall.NewCallback(systemToastActivatedHandler_Release),
+			syscall.NewCallback(systemToastActivatedHandler_Invoke),
+		}
+	})
+}
+
+type systemToastActivatedHandlerVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+	Invoke         uintptr
+}
+
+// systemToastActivatedHandler implements the delegate for the Activated event
+// of the toasts of a NotifyIcon. The NotifyIcon keeps it alive.
+type systemToastActivatedHandler struct {
+	vtbl   *systemToastActivatedHandlerVtbl
+	refs   int32
+	ni     *NotifyIcon
+	window Window // of the NotifyIcon, to synchronize with
+}
+
+func systemToastActivatedHandler_QueryInterface(h *systemToastActivatedHandler, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &iidIAgileObject) || win.EqualREFIID(riid, &iidToastActivatedHandler) {
+		atomic.AddInt32(&h.refs, 1)
+		*ppvObject = unsafe.Pointer(h)
+		return win.S_OK
+	}
+
+	*ppvObject = nil
+	return win.E_NOINTERFACE
+}
+
+func systemToastActivatedHandler_AddRef(h *systemToastActivatedHandler) uintptr {
+	return uintptr(atomic.AddInt32(&h.refs, 1))
+}
+
+func systemToastActivatedHandler_Release(h *systemToastActivatedHandler) uintptr {
+	return uintptr(atomic.AddInt32(&h.refs, -1))
+}
+
+// systemToastActivatedHandler_Invoke is called on a thread of the thread pool,
+// so the event is published on the thread of the NotifyIcon.
+func systemToastActivatedHandler_Invoke(h *systemToastActivatedHandler, sender, args uintptr) uintptr {
+	var arguments string
+
+	var activatedArgs uintptr
+	if args != 0 && win.SUCCEEDED(win.HRESULT(int32(comCall(args, unknownQueryInterface, uintptr(unsafe.Pointer(&iidIToastActivatedEventArgs)), uintptr(unsafe.Pointer(&activatedArgs)))))) {
+		var hs uintptr
+		if win.SUCCEEDED(win.HRESULT(int32(comCall(activatedArgs, toastActivatedEventArgsGetArguments, uintptr(unsafe.Pointer(&hs)))))) {
+			arguments = hStringToString(hs)
+			windowsDeleteString.Call(hs)
+		}
+
+		comCall(activatedArgs, unknownRelease)
+	}
+
+	ni := h.ni
+	h.window.Synchronize(func() {
+		if ni.hWnd != 0 {
+			ni.toastActivatedPublisher.Publish(arguments)
+		}
+	})
+
+	return win.S_OK
+}
+
+// winrtError returns an error for a failed WinRT call, which doesn't panic, as
+// ShowSystemToast falls back to a balloon then.
+func winrtError(funcName string, hr win.HRESULT) error {
+	return newErrorNoPanic(fmt.Sprintf("%s: Error %d", funcName, hr))
+}
+
+func newHString(s string) (uintptr, error) {
+	s16, err := syscall.UTF16FromString(s)
+	if err != nil {
+		return 0, wrapErrorNoPanic(err)
+	}
+
+	var hs uintptr
+	if hr, _, _ := windowsCreateString.Call(uintptr(unsafe.Pointer(&s16[0])), uintptr(len(s16)-1), uintptr(unsafe.Pointer(&hs))); win.FAILED(win.HRESULT(int32(hr))) {
+		return 0, winrtError("WindowsCreateString", win.HRESULT(int32(hr)))
+	}
+
+	return hs, nil
+}
+
+func hStringToString(hs uintptr) string {
+	var length uint32
+	p, _, _ := windowsGetStringRawBuffer.Call(hs, uintptr(unsafe.Pointer(&length)))
+	if p == 0 || length == 0 {
+		return ""
+	}
+
+	return syscall.UTF16ToString(unsafe.Slice((*uint16)(unsafe.Pointer(p)), length))
+}
+
+// roActivationFactory returns the activation factory of the WinRT class
+// className for the interface iid.
+func roActivationFactory(className string, iid *win.IID) (uintptr, error) {
+	hs, err := newHString(className)
+	if err != nil {
+		return 0, err
+	}
+	defer windowsDeleteString.Call(hs)
+
+	var factory uintptr
+	if hr, _, _ := roGetActivationFactory.Call(hs, uintptr(unsafe.Pointer(iid)), uintptr(unsafe.Pointer(&factory))); win.FAILED(win.HRESULT(int32(hr))) {
+		return 0, winrtError("RoGetActivationFactory", win.HRESULT(int32(hr)))
+	}
+
+	return factory, nil
+}
+
+// ToastAppID returns the application user model ID the NotifyIcon shows
+// SystemToasts for.
+func (ni *NotifyIcon) ToastAppID() string {
+	if ni.toastAppID != "" {
+		return ni.toastAppID
+	}
+
+	app := App()
+
+	name := app.ProductName()
+	if name == "" {
+		exe, _ := os.Executable()
+		name = strings.TrimSuffix(filepath.Base(exe), filepath.Ext(exe))
+	}
+
+	if org := app.OrganizationName(); org != "" {
+		return org + "." + name
+	}
+
+	return name
+}
+
+// SetToastAppID sets the application user model ID the NotifyIcon shows
+// SystemToasts for. It defaults to the organization and product name of the
+// application.
+func (ni *NotifyIcon) SetToastAppID(id string) {
+	ni.toastAppID = id
+}
+
+// ToastActivated returns the event that is published with the arguments of a
+// SystemToast or one of its actions, when the user clicks it while the
+// application is running.
+func (ni *NotifyIcon) ToastActivated() *StringEvent {
+	return ni.toastActivatedPublisher.Event()
+}
+
+// ShowSystemToast shows toast as a toast notification of Windows.
+//
+// For an application that isn't packaged, the ToastAppID is registered for
+// the current user, with the product name of the application as the name
+// shown for the toasts. If Windows can't show toasts, toast is shown as a
+// balloon instead, and clicking it publishes ToastActivated with the
+// Arguments of toast.
+func (ni *NotifyIcon) ShowSystemToast(toast SystemToast) error {
+	if err := ni.showSystemToast(toast); err != nil {
+		return ni.ShowBalloon(BalloonOptions{
+			Title:  toast.Title,
+			Text:   toast.Text,
+			Kind:   BalloonInfo,
+			Silent: toast.Silent,
+			OnClicked: func() {
+				ni.toastActivatedPublisher.Publish(toast.Arguments)
+			},
+		})
+	}
+
+	return nil
+}
+
+func (ni *NotifyIcon) showSystemToast(toast SystemToast) error {
+	if err := roInitialize.Find(); err != nil {
+		return wrapErrorNoPanic(err)
+	}
+
+	// This fails harmlessly if COM was initialized for the thread already.
+	roInitialize.Call(0) // RO_INIT_SINGLETHREADED
+
+	appID := ni.ToastAppID()
+	registerToastAppID(appID)
+
+	doc, err := newToastXmlDocument(toast)
+	if err != nil {
+		return err
+	}
+	defer comCall(doc, unknownRelease)
+
+	factory, err := roActivationFactory("Windows.UI.Notifications.ToastNotification", &iidIToastNotificationFactory)
+	if err != nil {
+		return err
+	}
+	defer comCall(factory, unknownRelease)
+
+	var notification uintptr
+	if hr := win.HRESULT(int32(comCall(factory, toastNotificationFactoryCreateToastNotification, doc, uintptr(unsafe.Pointer(&notification))))); win.FAILED(hr) {
+		return winrtError("IToastNotificationFactory.CreateToastNotification", hr)
+	}
+	defer comCall(notification, unknownRelease)
+
+	if ni.toastActivatedHandler == nil {
+		window := windowFromHandle(ni.hWnd)
+		if window == nil {
+			return newErrorNoPanic("NotifyIcon has no window")
+		}
+
+		ni.toastActivatedHandler = &systemToastActivatedHandler{vtbl: toastActivatedHandlerVtbl, ni: ni, window: window}
+	}
+
+	var token int64
+	comCall(notification, toastNotificationAddActivated, uintptr(unsafe.Pointer(ni.toastActivatedHandler)), uintptr(unsafe.Pointer(&token)))
+
+	manager, err := roActivationFactory("Windows.UI.Notifications.ToastNotificationManager", &iidIToastNotificationManagerStatics)
+	if err != nil {
+		return err
+	}
+	defer comCall(manager, unknownRelease)
+
+	hsAppID, err := newHString(appID)
+	if err != nil {
+		return err
+	}
+	defer windowsDeleteString.Call(hsAppID)
+
+	var notifier uintptr
+	if hr := win.HRESULT(int32(comCall(manager, toastNotificationManagerCreateToastNotifierWithId, hsAppID, uintptr(unsafe.Pointer(&notifier))))); win.FAILED(hr) {
+		return winrtError("IToastNotificationManagerStatics.CreateToastNotifierWithId", hr)
+	}
+	defer comCall(notifier, unknownRelease)
+
+	if hr := win.HRESULT(int32(comCall(notifier, toastNotifierShow, notification))); win.FAILED(hr) {
+		return winrtError("IToastNotifier.Show", hr)
+	}
+
+	return nil
+}
+
+// newToastXmlDocument returns an IXmlDocument with the toast XML of toast.
+func newToastXmlDocument(toast SystemToast) (uintptr, error) {
+	hsClass, err := newHString("Windows.Data.Xml.Dom.XmlDocument")
+	if err != nil {
+		return 0, err
+	}
+	defer windowsDeleteString.Call(hsClass)
+
+	var inspectable uintptr
+	if hr, _, _ := roActivateInstance.Call(hsClass, uintptr(unsafe.Pointer(&inspectable))); win.FAILED(win.HRESULT(int32(hr))) {
+		return 0, winrtError("RoActivateInstance", win.HRESULT(int32(hr)))
+	}
+	defer comCall(inspectable, unknownRelease)
+
+	var docIO uintptr
+	if hr := win.HRESULT(int32(comCall(inspectable, unknownQueryInterface, uintptr(unsafe.Pointer(&iidIXmlDocumentIO)), uintptr(unsafe.Pointer(&docIO))))); win.FAILED(hr) {
+		return 0, winrtError("QueryInterface", hr)
+	}
+	defer comCall(docIO, unknownRelease)
+
+	hsXml, err := newHString(toastXml(toast))
+	if err != nil {
+		return 0, err
+	}
+	defer windowsDeleteString.Call(hsXml)
+
+	if hr := win.HRESULT(int32(comCall(docIO, xmlDocumentIOLoadXml, hsXml))); win.FAILED(hr) {
+		return 0, winrtError("IXmlDocumentIO.LoadXml", hr)
+	}
+
+	var doc uintptr
+	if hr := win.HRESULT(int32(comCall(inspectable, unknownQueryInterface, uintptr(unsafe.Pointer(&iidIXmlDocument)), uintptr(unsafe.Pointer(&doc))))); win.FAILED(hr) {
+		return 0, winrtError("QueryInterface", hr)
+	}
+
+	return doc, nil
+}
+
+// toastXml returns the toast XML schema representation of toast.
+func toastXml(toast SystemToast) string {
+	var b strings.Builder
+
+	attr := func(name, value string) {
+		b.WriteString(" " + name + `="`)
+		xml.EscapeText(&b, []byte(value))
+		b.WriteString(`"`)
+	}
+
+	text := func(value string) {
+		b.WriteString("<text>")
+		xml.EscapeText(&b, []byte(value))
+		b.WriteString("</text>")
+	}
+
+	b.WriteString("<toast")
+	attr("launch", toast.Arguments)
+	b.WriteString(`><visual><binding template="ToastGeneric">`)
+	text(toast.Title)
+	text(toast.Text)
+	b.WriteString("</binding></visual>")
+
+	if toast.Silent {
+		b.WriteString(`<audio silent="true"/>`)
+	}
+
+	if len(toast.Actions) > 0 {
+		b.WriteString("<actions>")
+		for _, action := range toast.Actions {
+			arguments := action.Arguments
+			if arguments == "" {
+				arguments = action.Text
+			}
+
+			b.WriteString("<action")
+			attr("content", action.Text)
+			attr("arguments", arguments)
+			attr("activationType", "foreground")
+			b.WriteString("/>")
+		}
+		b.WriteString("</actions>")
+	}
+
+	b.WriteString("</toast>")
+
+	return b.String()
+}
+
+// registerToastAppID registers appID for the current user, unless it is
+// registered already, so Windows shows toasts for it.
+func registerToastAppID(appID string) {
+	key, existing, err := registry.CreateKey(registry.CURRENT_USER, `Software\Classes\AppUserModelId\`+appID, registry.SET_VALUE)
+	if err != nil {
+		return
+	}
+	defer key.Close()
+
+	if existing {
+		return
+	}
+
+	name := App().ProductName()
+	if name == "" {
+		name = appID
+	}
+
+	key.SetStringValue("DisplayName", name)
+}
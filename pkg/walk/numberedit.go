@@ -278,11 +278,11 @@ func (ne *NumberEdit) SetRange(min, max float64) error {
 	ne.edit.maxValue = max
 	if min != max {
 		if ne.edit.value < min {
-			if err := ne.edit.setValue(min, true); err != nil {
+			if err := ne.edit.setValue(min, true, ValueChangeSourceProgrammatic); err != nil {
 				return err
 			}
 		} else if ne.edit.value > max {
-			if err := ne.edit.setValue(max, true); err != nil {
+			if err := ne.edit.setValue(max, true, ValueChangeSourceProgrammatic); err != nil {
 				return err
 			}
 		}
@@ -298,6 +298,20 @@ func (ne *NumberEdit) SetRange(min, max float64) error {
 	return nil
 }
 
+// Wrapped returns if the value of the NumberEdit wraps around, instead of
+// stopping at MinValue and MaxValue.
+func (ne *NumberEdit) Wrapped() bool {
+	return ne.edit.wrapped
+}
+
+// SetWrapped sets if the value of the NumberEdit wraps around, instead of
+// stopping at MinValue and MaxValue, e.g. for angles from 0 to 360.
+//
+// Values past the range, whether set or typed, are wrapped into it.
+func (ne *NumberEdit) SetWrapped(wrapped bool) {
+	ne.edit.wrapped = wrapped
+}
+
 // Value returns the value of the NumberEdit.
 func (ne *NumberEdit) Value() float64 {
 	return ne.edit.value
@@ -305,13 +319,17 @@ func (ne *NumberEdit) Value() float64 {
 
 // SetValue sets the value of the NumberEdit.
 func (ne *NumberEdit) SetValue(value float64) error {
+	if ne.edit.wrapped {
+		value = wrapValue(value, ne.edit.minValue, ne.edit.maxValue)
+	}
+
 	if ne.edit.minValue != ne.edit.maxValue &&
 		(value < ne.edit.minValue || value > ne.edit.maxValue) {
 
 		return newError("value out of range")
 	}
 
-	return ne.edit.setValue(value, true)
+	return ne.edit.setValue(value, true, ValueChangeSourceProgrammatic)
 }
 
 // ValueChanged returns an Event that can be used to track changes to Value.
@@ -319,6 +337,31 @@ func (ne *NumberEdit) ValueChanged() *Event {
 	return ne.edit.valueChangedPublisher.Event()
 }
 
+// ValueChangedDetailed returns an Event that is published along with
+// ValueChanged, with the old and new float64 value and how it was changed,
+// e.g. to record changes for undo.
+func (ne *NumberEdit) ValueChangedDetailed() *ValueChangedEvent {
+	return ne.edit.valueChangedDetailedPublisher.Event()
+}
+
+// ValueChangeSource returns how Value was changed last, e.g. to tell
+// accidental changes by the mouse wheel from others while ValueChanged is
+// published.
+func (ne *NumberEdit) ValueChangeSource() ValueChangeSource {
+	return ne.edit.valueChangeSource
+}
+
+// WheelValueMode returns whether the mouse wheel changes Value.
+func (ne *NumberEdit) WheelValueMode() WheelValueMode {
+	return ne.edit.wheelValueMode
+}
+
+// SetWheelValueMode sets whether the mouse wheel changes Value.
+// WheelValueDefault uses App().WheelValueMode().
+func (ne *NumberEdit) SetWheelValueMode(mode WheelValueMode) {
+	ne.edit.wheelValueMode = mode
+}
+
 // SetFocus sets the keyboard input focus to the NumberEdit.
 func (ne *NumberEdit) SetFocus() error {
 	if win.SetFocus(ne.edit.hWnd) == 0 {
@@ -361,12 +404,18 @@ func (ne *NumberEdit) SpinButtonsVisible() bool {
 
 // SetSpinButtonsVisible sets whether the NumberEdit appears with spin buttons.
 func (ne *NumberEdit) SetSpinButtonsVisible(visible bool) error {
-	if visible == ne.SpinButtonsVisible() {
+	return setUpDownVisible(&ne.hWndUpDown, ne.hWnd, ne.edit.hWnd, visible)
+}
+
+// setUpDownVisible creates or destroys the spin buttons in *hWndUpDown, that
+// are aligned to the right of hWndParent and have hWndBuddy as their buddy.
+func setUpDownVisible(hWndUpDown *win.HWND, hWndParent, hWndBuddy win.HWND, visible bool) error {
+	if visible == (*hWndUpDown != 0) {
 		return nil
 	}
 
 	if visible {
-		ne.hWndUpDown = win.CreateWindowEx(
+		*hWndUpDown = win.CreateWindowEx(
 			0,
 			syscall.StringToUTF16Ptr("msctls_updown32"),
 			nil,
@@ -375,21 +424,21 @@ func (ne *NumberEdit) SetSpinButtonsVisible(visible bool) error {
 			0,
 			16,
 			20,
-			ne.hWnd,
+			hWndParent,
 			0,
 			0,
 			nil)
-		if ne.hWndUpDown == 0 {
+		if *hWndUpDown == 0 {
 			return lastError("CreateWindowEx")
 		}
 
-		win.SendMessage(ne.hWndUpDown, win.UDM_SETBUDDY, uintptr(ne.edit.hWnd), 0)
+		win.SendMessage(*hWndUpDown, win.UDM_SETBUDDY, uintptr(hWndBuddy), 0)
 	} else {
-		if !win.DestroyWindow(ne.hWndUpDown) {
+		if !win.DestroyWindow(*hWndUpDown) {
 			return lastError("DestroyWindow")
 		}
 
-		ne.hWndUpDown = 0
+		*hWndUpDown = 0
 	}
 
 	return nil
@@ -431,7 +480,7 @@ func (ne *NumberEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr)
 		switch ((*win.NMHDR)(unsafe.Pointer(lParam))).Code {
 		case win.UDN_DELTAPOS:
 			nmud := (*win.NMUPDOWN)(unsafe.Pointer(lParam))
-			ne.edit.incrementValue(-float64(nmud.IDelta) * ne.edit.increment)
+			ne.edit.incrementValue(-float64(nmud.IDelta)*ne.edit.increment, ValueChangeSourceSpin)
 		}
 
 	case win.WM_CTLCOLOREDIT, win.WM_CTLCOLORSTATIC:
@@ -490,16 +539,20 @@ func (li *numberEditLayoutItem) MinSize() Size {
 
 type numberLineEdit struct {
 	*LineEdit
-	buf                   *bytes.Buffer
-	prefix                []uint16
-	suffix                []uint16
-	value                 float64
-	minValue              float64
-	maxValue              float64
-	increment             float64
-	decimals              int
-	valueChangedPublisher EventPublisher
-	inEditMode            bool
+	buf                           *bytes.Buffer
+	prefix                        []uint16
+	suffix                        []uint16
+	value                         float64
+	minValue                      float64
+	maxValue                      float64
+	increment                     float64
+	decimals                      int
+	valueChangedPublisher         EventPublisher
+	valueChangedDetailedPublisher ValueChangedEventPublisher
+	valueChangeSource             ValueChangeSource
+	wheelValueMode                WheelValueMode
+	inEditMode                    bool
+	wrapped                       bool
 }
 
 func newNumberLineEdit(parent Widget) (*numberLineEdit, error) {
@@ -524,6 +577,8 @@ func newNumberLineEdit(parent Widget) (*numberLineEdit, error) {
 		return nil, err
 	}
 
+	nle.applyRightToLeftLayout(nle.hasExtendedStyleBits(win.WS_EX_LAYOUTRTL))
+
 	if err := InitWrapperWindow(nle); err != nil {
 		return nil, err
 	}
@@ -533,6 +588,13 @@ func newNumberLineEdit(parent Widget) (*numberLineEdit, error) {
 	return nle, nil
 }
 
+// applyRightToLeftLayout gives the edit a right-to-left reading order in
+// mirrored layouts, so the prefix is shown right and the suffix left of the
+// number, which itself still reads left to right.
+func (nle *numberLineEdit) applyRightToLeftLayout(rtl bool) {
+	nle.ensureExtendedStyleBits(win.WS_EX_RTLREADING, rtl)
+}
+
 func (nle *numberLineEdit) TextColor() Color {
 	return nle.LineEdit.TextColor()
 }
@@ -541,7 +603,7 @@ func (nle *numberLineEdit) SetTextColor(c Color) {
 	nle.LineEdit.SetTextColor(c)
 }
 
-func (nle *numberLineEdit) setValue(value float64, setText bool) error {
+func (nle *numberLineEdit) setValue(value float64, setText bool, source ValueChangeSource) error {
 	if setText {
 		if err := nle.setTextFromValue(value); err != nil {
 			return err
@@ -552,9 +614,12 @@ func (nle *numberLineEdit) setValue(value float64, setText bool) error {
 		return nil
 	}
 
+	oldValue := nle.value
 	nle.value = value
+	nle.valueChangeSource = source
 
 	nle.valueChangedPublisher.Publish()
+	nle.valueChangedDetailedPublisher.Publish(ValueChange{OldValue: oldValue, NewValue: value, Source: source})
 
 	return nil
 }
@@ -660,8 +725,12 @@ func (nle *numberLineEdit) tryUpdateValue(setText bool) bool {
 	}
 
 	if value, err := strconv.ParseFloat(text, 64); err == nil {
+		if nle.wrapped {
+			value = wrapValue(value, nle.minValue, nle.maxValue)
+		}
+
 		if nle.minValue == nle.maxValue || value >= nle.minValue && value <= nle.maxValue {
-			return nle.setValue(value, setText) == nil
+			return nle.setValue(value, setText, ValueChangeSourceKeyboard) == nil
 		}
 	}
 
@@ -680,10 +749,12 @@ func (nle *numberLineEdit) textUTF16() []uint16 {
 	return buf[:len(buf)-1]
 }
 
-func (nle *numberLineEdit) incrementValue(delta float64) {
+func (nle *numberLineEdit) incrementValue(delta float64, source ValueChangeSource) {
 	value := nle.value + delta
 
-	if nle.minValue != nle.maxValue {
+	if nle.wrapped {
+		value = wrapValue(value, nle.minValue, nle.maxValue)
+	} else if nle.minValue != nle.maxValue {
 		if value < nle.minValue {
 			value = nle.minValue
 		} else if value > nle.maxValue {
@@ -691,7 +762,7 @@ func (nle *numberLineEdit) incrementValue(delta float64) {
 		}
 	}
 
-	nle.setValue(value, true)
+	nle.setValue(value, true, source)
 	nle.selectNumber()
 }
 
@@ -801,7 +872,7 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 				return 0
 			}
 
-			nle.incrementValue(-nle.increment)
+			nle.incrementValue(-nle.increment, ValueChangeSourceKeyboard)
 			return 0
 
 		case KeyEnd:
@@ -866,7 +937,7 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 				return 0
 			}
 
-			nle.incrementValue(nle.increment)
+			nle.incrementValue(nle.increment, ValueChangeSourceKeyboard)
 			return 0
 		}
 
@@ -920,12 +991,12 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 		}
 
 	case win.WM_MOUSEWHEEL:
-		if nle.ReadOnly() || nle.increment <= 0 {
+		if nle.ReadOnly() || nle.increment <= 0 || !wheelChangesValue(nle.wheelValueMode, nle.Focused()) {
 			break
 		}
 
 		delta := float64(int16(win.HIWORD(uint32(wParam))))
-		nle.incrementValue(delta / 120 * nle.increment)
+		nle.incrementValue(delta/120*nle.increment, ValueChangeSourceWheel)
 		return 0
 
 	case win.WM_PASTE:
@@ -967,7 +1038,13 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 }
 
 func (nle *numberLineEdit) onFocusChanged() {
-	if ne := windowFromHandle(win.GetParent(nle.hWnd)); ne != nil {
+	invalidateWrapperBorderInParent(nle.hWnd)
+}
+
+// invalidateWrapperBorderInParent invalidates the border of the widget that
+// wraps the edit control hwnd, like a NumberEdit does.
+func invalidateWrapperBorderInParent(hwnd win.HWND) {
+	if ne := windowFromHandle(win.GetParent(hwnd)); ne != nil {
 		if wnd := windowFromHandle(win.GetParent(ne.Handle())); wnd != nil {
 			if _, ok := wnd.(Container); ok {
 				ne.(Widget).AsWidgetBase().invalidateBorderInParent()
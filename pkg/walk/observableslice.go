@@ -0,0 +1,235 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// ObservableSlice is a slice of items that publishes each change made through
+// its methods as the narrowest event, so a ListBox, ComboBox or TableView
+// showing it only updates the affected rows and keeps its selection and
+// scroll position.
+//
+// Use ListModel or TableModel as the model of the widget. Items modified in
+// place, e.g. through a pointer, must be reported with PublishItemChanged.
+//
+// While a TableView sorts the TableModel, it reorders the items of the
+// ObservableSlice, so indices always refer to the rows as shown.
+type ObservableSlice[T any] struct {
+	items                  []T
+	itemsResetPublisher    EventPublisher
+	itemsChangedPublisher  IntRangeEventPublisher
+	itemsInsertedPublisher IntRangeEventPublisher
+	itemsRemovedPublisher  IntRangeEventPublisher
+	listModel              *observableSliceListModel[T]
+	tableModel             *observableSliceTableModel[T]
+}
+
+// NewObservableSlice returns a new ObservableSlice that takes ownership of
+// items.
+func NewObservableSlice[T any](items []T) *ObservableSlice[T] {
+	return &ObservableSlice[T]{items: items}
+}
+
+// Len returns the number of items.
+func (s *ObservableSlice[T]) Len() int {
+	return len(s.items)
+}
+
+// At returns the item at index.
+func (s *ObservableSlice[T]) At(index int) T {
+	return s.items[index]
+}
+
+// Items returns the items. The slice must not be modified.
+func (s *ObservableSlice[T]) Items() []T {
+	return s.items
+}
+
+// Reset replaces all items, publishing ItemsReset.
+func (s *ObservableSlice[T]) Reset(items []T) {
+	s.items = items
+
+	s.itemsResetPublisher.Publish()
+}
+
+// Append adds items at the end.
+func (s *ObservableSlice[T]) Append(items ...T) {
+	s.Insert(len(s.items), items...)
+}
+
+// Insert inserts items before index. Index may be Len to append them.
+func (s *ObservableSlice[T]) Insert(index int, items ...T) error {
+	if index < 0 || index > len(s.items) {
+		return newError("index out of range")
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	n := len(items)
+	s.items = append(s.items, items...)
+	copy(s.items[index+n:], s.items[index:len(s.items)-n])
+	copy(s.items[index:], items)
+
+	s.itemsInsertedPublisher.Publish(index, index+n-1)
+
+	return nil
+}
+
+// Remove removes the item at index.
+func (s *ObservableSlice[T]) Remove(index int) error {
+	return s.RemoveRange(index, index)
+}
+
+// RemoveRange removes the items from index from to index to, inclusive.
+func (s *ObservableSlice[T]) RemoveRange(from, to int) error {
+	if from < 0 || to >= len(s.items) || from > to {
+		return newError("index out of range")
+	}
+
+	var zero T
+	n := copy(s.items[from:], s.items[to+1:])
+	for i := from + n; i < len(s.items); i++ {
+		// Releases the removed items to the garbage collector.
+		s.items[i] = zero
+	}
+	s.items = s.items[:from+n]
+
+	s.itemsRemovedPublisher.Publish(from, to)
+
+	return nil
+}
+
+// Move moves the item at index from to index to, shifting the items in
+// between.
+func (s *ObservableSlice[T]) Move(from, to int) error {
+	if from < 0 || from >= len(s.items) || to < 0 || to >= len(s.items) {
+		return newError("index out of range")
+	}
+	if from == to {
+		return nil
+	}
+
+	item := s.items[from]
+
+	if from < to {
+		copy(s.items[from:to], s.items[from+1:to+1])
+	} else {
+		copy(s.items[to+1:from+1], s.items[to:from])
+	}
+	s.items[to] = item
+
+	// There are no move events, but removing and inserting the item keeps the
+	// selection of the other items.
+	s.itemsRemovedPublisher.Publish(from, from)
+	s.itemsInsertedPublisher.Publish(to, to)
+
+	return nil
+}
+
+// Replace replaces the item at index.
+func (s *ObservableSlice[T]) Replace(index int, item T) error {
+	if index < 0 || index >= len(s.items) {
+		return newError("index out of range")
+	}
+
+	s.items[index] = item
+
+	s.itemsChangedPublisher.Publish(index, index)
+
+	return nil
+}
+
+// PublishItemChanged publishes that the item at index was modified in place.
+func (s *ObservableSlice[T]) PublishItemChanged(index int) {
+	s.itemsChangedPublisher.Publish(index, index)
+}
+
+// ItemsReset returns the event that is published when all items were
+// replaced.
+func (s *ObservableSlice[T]) ItemsReset() *Event {
+	return s.itemsResetPublisher.Event()
+}
+
+// ItemsChanged returns the event that is published when a range of items was
+// replaced or modified in place.
+func (s *ObservableSlice[T]) ItemsChanged() *IntRangeEvent {
+	return s.itemsChangedPublisher.Event()
+}
+
+// ItemsInserted returns the event that is published when a range of items was
+// inserted.
+func (s *ObservableSlice[T]) ItemsInserted() *IntRangeEvent {
+	return s.itemsInsertedPublisher.Event()
+}
+
+// ItemsRemoved returns the event that is published when a range of items was
+// removed.
+func (s *ObservableSlice[T]) ItemsRemoved() *IntRangeEvent {
+	return s.itemsRemovedPublisher.Event()
+}
+
+// ListModel returns a model of the items for widgets like ListBox and
+// ComboBox, which supports BindingMember and DisplayMember.
+func (s *ObservableSlice[T]) ListModel() ReflectListModel {
+	if s.listModel == nil {
+		m := &observableSliceListModel[T]{s: s}
+
+		s.ItemsReset().Attach(m.PublishItemsReset)
+		s.ItemsChanged().Attach(func(from, to int) {
+			for i := from; i <= to; i++ {
+				m.PublishItemChanged(i)
+			}
+		})
+		s.ItemsInserted().Attach(m.PublishItemsInserted)
+		s.ItemsRemoved().Attach(m.PublishItemsRemoved)
+
+		s.listModel = m
+	}
+
+	return s.listModel
+}
+
+// TableModel returns a sortable model of the items for a TableView, whose
+// columns refer to the fields or methods of the items by DataMember.
+func (s *ObservableSlice[T]) TableModel() ReflectTableModel {
+	if s.tableModel == nil {
+		m := &observableSliceTableModel[T]{s: s}
+
+		s.ItemsReset().Attach(m.PublishRowsReset)
+		s.ItemsChanged().Attach(func(from, to int) {
+			if from == to {
+				m.PublishRowChanged(from)
+			} else {
+				m.PublishRowsChanged(from, to)
+			}
+		})
+		s.ItemsInserted().Attach(m.PublishRowsInserted)
+		s.ItemsRemoved().Attach(m.PublishRowsRemoved)
+
+		s.tableModel = m
+	}
+
+	return s.tableModel
+}
+
+type observableSliceListModel[T any] struct {
+	ReflectListModelBase
+	s *ObservableSlice[T]
+}
+
+func (m *observableSliceListModel[T]) Items() interface{} {
+	return m.s.items
+}
+
+type observableSliceTableModel[T any] struct {
+	SortedReflectTableModelBase
+	s *ObservableSlice[T]
+}
+
+func (m *observableSliceTableModel[T]) Items() interface{} {
+	return m.s.items
+}
@@ -0,0 +1,543 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// PaletteFileDialogFilter is a FileDialog filter for the palette file formats
+// supported by NewPaletteFromFile and Palette.SaveToFile.
+const PaletteFileDialogFilter = "GIMP Palette (*.gpl)|*.gpl|Adobe Color Swatches (*.aco)|*.aco|JSON Palette (*.json)|*.json"
+
+// PaletteColor is a named color of a Palette.
+type PaletteColor struct {
+	Name  string
+	Color Color
+}
+
+// Palette is an editable, ordered list of named colors.
+//
+// Palette is the model of a PaletteView and can be read from and written to
+// GIMP (.gpl), Adobe Color Swatch (.aco) and JSON files.
+type Palette struct {
+	name             string
+	colors           []PaletteColor
+	changedPublisher EventPublisher
+}
+
+// NewPalette returns a new Palette, initialized with name and colors.
+func NewPalette(name string, colors ...PaletteColor) *Palette {
+	return &Palette{name: name, colors: append([]PaletteColor(nil), colors...)}
+}
+
+// NewPaletteFromFile reads a Palette from the file at filePath. The format is
+// determined by the file name extension.
+func NewPaletteFromFile(filePath string) (*Palette, error) {
+	format, err := PaletteFormatForPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer file.Close()
+
+	p, err := ReadPalette(file, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.name == "" {
+		p.name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+
+	return p, nil
+}
+
+// Name returns the name of the Palette.
+func (p *Palette) Name() string {
+	return p.name
+}
+
+// SetName sets the name of the Palette.
+func (p *Palette) SetName(name string) {
+	p.name = name
+}
+
+// Len returns the number of colors.
+func (p *Palette) Len() int {
+	return len(p.colors)
+}
+
+// At returns the color at index.
+func (p *Palette) At(index int) PaletteColor {
+	return p.colors[index]
+}
+
+// Colors returns a copy of the colors.
+func (p *Palette) Colors() []PaletteColor {
+	return append([]PaletteColor(nil), p.colors...)
+}
+
+// SetColors replaces all colors.
+func (p *Palette) SetColors(colors []PaletteColor) {
+	p.colors = append([]PaletteColor(nil), colors...)
+
+	p.changedPublisher.Publish()
+}
+
+// IndexOf returns the index of the first entry with color, or -1.
+func (p *Palette) IndexOf(color Color) int {
+	for i, c := range p.colors {
+		if c.Color == color {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Add appends color and returns its index.
+func (p *Palette) Add(color PaletteColor) int {
+	p.colors = append(p.colors, color)
+
+	p.changedPublisher.Publish()
+
+	return len(p.colors) - 1
+}
+
+// Insert inserts color at index.
+func (p *Palette) Insert(index int, color PaletteColor) error {
+	if index < 0 || index > len(p.colors) {
+		return newError("index out of range")
+	}
+
+	p.colors = append(p.colors, PaletteColor{})
+	copy(p.colors[index+1:], p.colors[index:])
+	p.colors[index] = color
+
+	p.changedPublisher.Publish()
+
+	return nil
+}
+
+// Set replaces the color at index.
+func (p *Palette) Set(index int, color PaletteColor) error {
+	if index < 0 || index >= len(p.colors) {
+		return newError("index out of range")
+	}
+
+	if color == p.colors[index] {
+		return nil
+	}
+
+	p.colors[index] = color
+
+	p.changedPublisher.Publish()
+
+	return nil
+}
+
+// RemoveAt removes the color at index.
+func (p *Palette) RemoveAt(index int) error {
+	if index < 0 || index >= len(p.colors) {
+		return newError("index out of range")
+	}
+
+	p.colors = append(p.colors[:index], p.colors[index+1:]...)
+
+	p.changedPublisher.Publish()
+
+	return nil
+}
+
+// Move moves the color at index from to index to.
+func (p *Palette) Move(from, to int) error {
+	if from < 0 || from >= len(p.colors) || to < 0 || to >= len(p.colors) {
+		return newError("index out of range")
+	}
+
+	if from == to {
+		return nil
+	}
+
+	color := p.colors[from]
+
+	if from < to {
+		copy(p.colors[from:to], p.colors[from+1:to+1])
+	} else {
+		copy(p.colors[to+1:from+1], p.colors[to:from])
+	}
+	p.colors[to] = color
+
+	p.changedPublisher.Publish()
+
+	return nil
+}
+
+// Changed returns the event that is published when colors were added,
+// removed, moved or replaced.
+func (p *Palette) Changed() *Event {
+	return p.changedPublisher.Event()
+}
+
+// SaveToFile writes the Palette to the file at filePath. The format is
+// determined by the file name extension.
+func (p *Palette) SaveToFile(filePath string) error {
+	format, err := PaletteFormatForPath(filePath)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := p.Write(&buf, format); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+// PaletteFormat is a file format for palettes.
+type PaletteFormat int
+
+const (
+	// PaletteGPL is the text format of GIMP palettes (.gpl).
+	PaletteGPL PaletteFormat = iota
+
+	// PaletteACO is the binary Adobe Color Swatch format (.aco).
+	PaletteACO
+
+	// PaletteJSON is a JSON object with a name and an array of colors,
+	// written as "#rrggbb" (.json).
+	PaletteJSON
+)
+
+// PaletteFormatForPath returns the PaletteFormat matching the file name
+// extension of filePath.
+func PaletteFormatForPath(filePath string) (PaletteFormat, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".gpl":
+		return PaletteGPL, nil
+
+	case ".aco":
+		return PaletteACO, nil
+
+	case ".json":
+		return PaletteJSON, nil
+	}
+
+	return 0, newError("unsupported palette file extension: " + filepath.Ext(filePath))
+}
+
+// ReadPalette reads a Palette in format from r.
+func ReadPalette(r io.Reader, format PaletteFormat) (*Palette, error) {
+	switch format {
+	case PaletteGPL:
+		return readGPLPalette(r)
+
+	case PaletteACO:
+		return readACOPalette(r)
+
+	case PaletteJSON:
+		return readJSONPalette(r)
+	}
+
+	return nil, newError("unsupported palette format")
+}
+
+// Write writes the Palette in format to w.
+func (p *Palette) Write(w io.Writer, format PaletteFormat) error {
+	switch format {
+	case PaletteGPL:
+		return p.writeGPL(w)
+
+	case PaletteACO:
+		return p.writeACO(w)
+
+	case PaletteJSON:
+		return p.writeJSON(w)
+	}
+
+	return newError("unsupported palette format")
+}
+
+func readGPLPalette(r io.Reader) (*Palette, error) {
+	scanner := bufio.NewScanner(r)
+
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "GIMP Palette" {
+		if err := scanner.Err(); err != nil {
+			return nil, wrapError(err)
+		}
+
+		return nil, newError("not a GIMP palette")
+	}
+
+	p := new(Palette)
+
+	for line := 2; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case text == "" || strings.HasPrefix(text, "#"):
+			continue
+
+		case strings.HasPrefix(text, "Name:"):
+			p.name = strings.TrimSpace(strings.TrimPrefix(text, "Name:"))
+			continue
+
+		case strings.HasPrefix(text, "Columns:"):
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 3 {
+			return nil, newError(fmt.Sprintf("line %d: invalid color", line))
+		}
+
+		var rgb [3]byte
+		for i := range rgb {
+			v, err := strconv.ParseUint(fields[i], 10, 8)
+			if err != nil {
+				return nil, newError(fmt.Sprintf("line %d: invalid color component: %s", line, fields[i]))
+			}
+			rgb[i] = byte(v)
+		}
+
+		p.colors = append(p.colors, PaletteColor{
+			Name:  strings.Join(fields[3:], " "),
+			Color: RGB(rgb[0], rgb[1], rgb[2]),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return p, nil
+}
+
+func (p *Palette) writeGPL(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "GIMP Palette")
+	fmt.Fprintf(bw, "Name: %s\n", strings.ReplaceAll(p.name, "\n", " "))
+	fmt.Fprintln(bw, "#")
+
+	for _, c := range p.colors {
+		fmt.Fprintf(bw, "%3d %3d %3d\t%s\n", c.Color.R(), c.Color.G(), c.Color.B(), strings.ReplaceAll(c.Name, "\n", " "))
+	}
+
+	if err := bw.Flush(); err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+// ACO color spaces
+const (
+	acoSpaceRGB       = 0
+	acoSpaceHSB       = 1
+	acoSpaceGrayscale = 8
+)
+
+// Limits to what is read from ACO files, so a corrupt one can't make us
+// allocate arbitrary amounts of memory up front.
+const (
+	acoMaxNameLength    = 1024 // in UTF-16 code units
+	acoMaxPreallocCount = 1024 // colors allocated before they have been read
+)
+
+func readACOPalette(r io.Reader) (*Palette, error) {
+	read := func(data interface{}) error {
+		return binary.Read(r, binary.BigEndian, data)
+	}
+
+	p := new(Palette)
+
+	for section := 0; ; section++ {
+		var header struct {
+			Version uint16
+			Count   uint16
+		}
+		if err := read(&header); err != nil {
+			if section > 0 && err == io.EOF {
+				break
+			}
+
+			return nil, wrapError(err)
+		}
+
+		if header.Version != 1 && header.Version != 2 {
+			return nil, newError(fmt.Sprintf("unsupported ACO version: %d", header.Version))
+		}
+
+		// The count is only trusted as far as colors can actually be read.
+		colors := make([]PaletteColor, 0, mini(int(header.Count), acoMaxPreallocCount))
+
+		for i := 0; i < int(header.Count); i++ {
+			colors = append(colors, PaletteColor{})
+
+			var spec struct {
+				Space uint16
+				W     uint16
+				X     uint16
+				Y     uint16
+				Z     uint16
+			}
+			if err := read(&spec); err != nil {
+				return nil, wrapError(err)
+			}
+
+			switch spec.Space {
+			case acoSpaceRGB:
+				colors[i].Color = RGB(byte(spec.W>>8), byte(spec.X>>8), byte(spec.Y>>8))
+
+			case acoSpaceHSB:
+				colors[i].Color = ColorFromHSV(float64(spec.W)/65535*360, float64(spec.X)/65535, float64(spec.Y)/65535)
+
+			case acoSpaceGrayscale:
+				v := byte(math.Round(255 - float64(mini(int(spec.W), 10000))/10000*255))
+				colors[i].Color = RGB(v, v, v)
+
+			default:
+				return nil, newError(fmt.Sprintf("unsupported ACO color space: %d", spec.Space))
+			}
+
+			if header.Version == 2 {
+				var length uint32
+				if err := read(&length); err != nil {
+					return nil, wrapError(err)
+				}
+
+				if length > acoMaxNameLength {
+					return nil, newError(fmt.Sprintf("ACO color name too long: %d", length))
+				}
+
+				name := make([]uint16, length)
+				if err := read(name); err != nil {
+					return nil, wrapError(err)
+				}
+
+				if n := len(name); n > 0 && name[n-1] == 0 {
+					name = name[:n-1]
+				}
+
+				colors[i].Name = string(utf16.Decode(name))
+			}
+		}
+
+		// A version 2 section, which adds names, supersedes version 1.
+		p.colors = colors
+
+		if header.Version == 2 {
+			break
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Palette) writeACO(w io.Writer) error {
+	var buf bytes.Buffer
+
+	write := func(data interface{}) {
+		binary.Write(&buf, binary.BigEndian, data)
+	}
+
+	for _, version := range []uint16{1, 2} {
+		write([]uint16{version, uint16(len(p.colors))})
+
+		for _, c := range p.colors {
+			r, g, b := uint16(c.Color.R()), uint16(c.Color.G()), uint16(c.Color.B())
+			write([]uint16{acoSpaceRGB, r<<8 | r, g<<8 | g, b<<8 | b, 0})
+
+			if version == 2 {
+				name := append(utf16.Encode([]rune(c.Name)), 0)
+				write(uint32(len(name)))
+				write(name)
+			}
+		}
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+type jsonPalette struct {
+	Name   string             `json:"name,omitempty"`
+	Colors []jsonPaletteColor `json:"colors"`
+}
+
+type jsonPaletteColor struct {
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color"`
+}
+
+func readJSONPalette(r io.Reader) (*Palette, error) {
+	var jp jsonPalette
+	if err := json.NewDecoder(r).Decode(&jp); err != nil {
+		return nil, wrapError(err)
+	}
+
+	p := &Palette{name: jp.Name, colors: make([]PaletteColor, len(jp.Colors))}
+
+	for i, jc := range jp.Colors {
+		hex := strings.TrimPrefix(jc.Color, "#")
+		if len(hex) != 6 {
+			return nil, newError("invalid color: " + jc.Color)
+		}
+
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return nil, newError("invalid color: " + jc.Color)
+		}
+
+		p.colors[i] = PaletteColor{jc.Name, RGB(byte(v>>16), byte(v>>8), byte(v))}
+	}
+
+	return p, nil
+}
+
+func (p *Palette) writeJSON(w io.Writer) error {
+	jp := jsonPalette{Name: p.name, Colors: make([]jsonPaletteColor, len(p.colors))}
+
+	for i, c := range p.colors {
+		jp.Colors[i] = jsonPaletteColor{c.Name, fmt.Sprintf("#%02x%02x%02x", c.Color.R(), c.Color.G(), c.Color.B())}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+
+	if err := enc.Encode(&jp); err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
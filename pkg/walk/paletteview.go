@@ -0,0 +1,665 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const paletteViewWindowClass = `\o/ Walk_PaletteView_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClassWithStyle(paletteViewWindowClass, win.CS_DBLCLKS)
+	})
+}
+
+// PaletteView is a widget that shows the colors of a Palette as a grid of
+// swatches, e.g. as the content of a tool window of a DockingManager.
+//
+// Clicking a swatch makes it current. Double-clicking it or pressing Enter
+// publishes the ItemActivated event. A swatch can be dragged out: dropping
+// it on the same PaletteView moves the color, dropping it on another
+// PaletteView inserts a copy there. In any case the ColorDropped event is
+// published with the window under the mouse pointer.
+type PaletteView struct {
+	WidgetBase
+	palette                   *Palette
+	paletteChangedHandle      int
+	currentIndex              int
+	swatchSize                int // in 1/96"
+	scrollPos                 int // in native pixels
+	updatingScrollBar         bool
+	dragIndex                 int
+	dragOrigin                win.POINT
+	dragging                  bool
+	selectionChangedPublisher EventPublisher
+	itemActivatedPublisher    EventPublisher
+	colorDroppedPublisher     PaletteColorDroppedEventPublisher
+}
+
+// NewPaletteView creates and initializes a new PaletteView, showing an empty
+// Palette.
+func NewPaletteView(parent Container) (*PaletteView, error) {
+	pv := &PaletteView{currentIndex: -1, swatchSize: 20, dragIndex: -1}
+
+	if err := InitWidget(
+		pv,
+		parent,
+		paletteViewWindowClass,
+		win.WS_TABSTOP|win.WS_VISIBLE|win.WS_VSCROLL,
+		0); err != nil {
+		return nil, err
+	}
+
+	pv.GraphicsEffects().Add(InteractionEffect)
+	pv.GraphicsEffects().Add(FocusEffect)
+
+	pv.SetPalette(nil)
+
+	return pv, nil
+}
+
+func (pv *PaletteView) Dispose() {
+	if pv.palette != nil {
+		pv.palette.Changed().Detach(pv.paletteChangedHandle)
+		pv.palette = nil
+	}
+
+	pv.WidgetBase.Dispose()
+}
+
+// Palette returns the Palette shown by the PaletteView.
+func (pv *PaletteView) Palette() *Palette {
+	return pv.palette
+}
+
+// SetPalette sets the Palette shown by the PaletteView. If palette is nil,
+// an empty Palette is used.
+func (pv *PaletteView) SetPalette(palette *Palette) {
+	if pv.palette != nil {
+		pv.palette.Changed().Detach(pv.paletteChangedHandle)
+	}
+
+	if palette == nil {
+		palette = NewPalette("")
+	}
+
+	pv.palette = palette
+	pv.paletteChangedHandle = palette.Changed().Attach(pv.onPaletteChanged)
+
+	pv.scrollPos = 0
+	pv.setCurrentIndex(-1)
+	pv.onPaletteChanged()
+}
+
+func (pv *PaletteView) onPaletteChanged() {
+	if pv.currentIndex >= pv.palette.Len() {
+		pv.setCurrentIndex(pv.palette.Len() - 1)
+	}
+
+	pv.updateScrollBar()
+	pv.Invalidate()
+}
+
+// ImportFromFile replaces the colors of the Palette with the ones read from
+// the file at filePath.
+func (pv *PaletteView) ImportFromFile(filePath string) error {
+	p, err := NewPaletteFromFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	pv.palette.SetName(p.Name())
+	pv.palette.SetColors(p.Colors())
+
+	return nil
+}
+
+// ExportToFile writes the Palette to the file at filePath.
+func (pv *PaletteView) ExportToFile(filePath string) error {
+	return pv.palette.SaveToFile(filePath)
+}
+
+// SwatchSize returns the edge length of a swatch in 1/96".
+func (pv *PaletteView) SwatchSize() int {
+	return pv.swatchSize
+}
+
+// SetSwatchSize sets the edge length of a swatch in 1/96".
+func (pv *PaletteView) SetSwatchSize(size int) error {
+	if size < 4 {
+		return newError("size must >= 4")
+	}
+
+	pv.swatchSize = size
+
+	pv.updateScrollBar()
+	pv.Invalidate()
+
+	return nil
+}
+
+// CurrentIndex returns the index of the current color or -1.
+func (pv *PaletteView) CurrentIndex() int {
+	return pv.currentIndex
+}
+
+// SetCurrentIndex sets the index of the current color. Use -1 to clear the
+// selection.
+func (pv *PaletteView) SetCurrentIndex(index int) error {
+	if index < -1 || index >= pv.palette.Len() {
+		return newError("index out of range")
+	}
+
+	pv.setCurrentIndex(index)
+
+	if index > -1 {
+		pv.EnsureVisible(index)
+	}
+
+	return nil
+}
+
+func (pv *PaletteView) setCurrentIndex(index int) {
+	if index == pv.currentIndex {
+		return
+	}
+
+	pv.currentIndex = index
+
+	pv.Invalidate()
+
+	pv.selectionChangedPublisher.Publish()
+}
+
+// CurrentColor returns the current color and whether there is one.
+func (pv *PaletteView) CurrentColor() (PaletteColor, bool) {
+	if pv.currentIndex < 0 {
+		return PaletteColor{}, false
+	}
+
+	return pv.palette.At(pv.currentIndex), true
+}
+
+// SelectionChanged returns the event that is published when the current
+// color changed.
+func (pv *PaletteView) SelectionChanged() *Event {
+	return pv.selectionChangedPublisher.Event()
+}
+
+// ItemActivated returns the event that is published when the user
+// double-clicked a swatch or pressed Enter.
+func (pv *PaletteView) ItemActivated() *Event {
+	return pv.itemActivatedPublisher.Event()
+}
+
+// ColorDropped returns the event that is published when the user dropped a
+// swatch dragged out of the PaletteView. The target is the window under the
+// mouse pointer, which is nil if it is not a walk window.
+func (pv *PaletteView) ColorDropped() *PaletteColorDroppedEvent {
+	return pv.colorDroppedPublisher.Event()
+}
+
+// ChooseCurrentColor shows a color dialog to replace the current color.
+func (pv *PaletteView) ChooseCurrentColor() error {
+	if pv.currentIndex < 0 {
+		return nil
+	}
+
+	color := pv.palette.At(pv.currentIndex)
+
+	dlg := ColorDialog{Color: color.Color, FullOpen: true}
+
+	accepted, err := dlg.ShowChoose(pv.Form())
+	if err != nil || !accepted {
+		return err
+	}
+
+	color.Color = dlg.Color
+
+	return pv.palette.Set(pv.currentIndex, color)
+}
+
+// EnsureVisible scrolls the PaletteView so that the swatch at index is
+// visible.
+func (pv *PaletteView) EnsureVisible(index int) {
+	b := pv.swatchBounds(index)
+	height := pv.ClientBoundsPixels().Height
+	gap := pv.gap()
+
+	if b.Y < gap {
+		pv.setScrollPos(pv.scrollPos + b.Y - gap)
+	} else if b.Y+b.Height+gap > height {
+		pv.setScrollPos(pv.scrollPos + b.Y + b.Height + gap - height)
+	}
+}
+
+func (pv *PaletteView) gap() int {
+	return IntFrom96DPI(3, pv.DPI())
+}
+
+func (pv *PaletteView) swatchSizePixels() int {
+	return IntFrom96DPI(pv.swatchSize, pv.DPI())
+}
+
+func (pv *PaletteView) columnCount() int {
+	gap := pv.gap()
+
+	return maxi(1, (pv.ClientBoundsPixels().Width-gap)/(pv.swatchSizePixels()+gap))
+}
+
+func (pv *PaletteView) contentHeight() int {
+	gap := pv.gap()
+	cols := pv.columnCount()
+	rows := (pv.palette.Len() + cols - 1) / cols
+
+	return gap + rows*(pv.swatchSizePixels()+gap)
+}
+
+// swatchBounds returns the bounds of the swatch at index in native pixels.
+func (pv *PaletteView) swatchBounds(index int) Rectangle {
+	gap := pv.gap()
+	size := pv.swatchSizePixels()
+	cols := pv.columnCount()
+
+	return Rectangle{
+		gap + (index%cols)*(size+gap),
+		gap + (index/cols)*(size+gap) - pv.scrollPos,
+		size,
+		size,
+	}
+}
+
+func (pv *PaletteView) indexAt(x, y int) int {
+	gap := pv.gap()
+	size := pv.swatchSizePixels()
+
+	x -= gap
+	y += pv.scrollPos - gap
+	if x < 0 || y < 0 || x%(size+gap) >= size || y%(size+gap) >= size {
+		return -1
+	}
+
+	col := x / (size + gap)
+	if col >= pv.columnCount() {
+		return -1
+	}
+
+	index := (y/(size+gap))*pv.columnCount() + col
+	if index >= pv.palette.Len() {
+		return -1
+	}
+
+	return index
+}
+
+// insertionIndexAt returns the index where a color dropped at x, y is
+// inserted.
+func (pv *PaletteView) insertionIndexAt(x, y int) int {
+	if index := pv.indexAt(x, y); index > -1 {
+		return index
+	}
+
+	return pv.palette.Len()
+}
+
+func (pv *PaletteView) updateScrollBar() {
+	if pv.updatingScrollBar {
+		return
+	}
+	pv.updatingScrollBar = true
+	defer func() {
+		pv.updatingScrollBar = false
+	}()
+
+	var si win.SCROLLINFO
+	si.CbSize = uint32(unsafe.Sizeof(si))
+	si.FMask = win.SIF_PAGE | win.SIF_RANGE
+	si.NMax = int32(pv.contentHeight() - 1)
+	si.NPage = uint32(pv.ClientBoundsPixels().Height)
+
+	win.SetScrollInfo(pv.hWnd, win.SB_VERT, &si, true)
+
+	pv.setScrollPos(pv.scrollPos)
+}
+
+func (pv *PaletteView) setScrollPos(pos int) {
+	if max := pv.contentHeight() - pv.ClientBoundsPixels().Height; pos > max {
+		pos = max
+	}
+	if pos < 0 {
+		pos = 0
+	}
+
+	var si win.SCROLLINFO
+	si.CbSize = uint32(unsafe.Sizeof(si))
+	si.FMask = win.SIF_POS
+	si.NPos = int32(pos)
+
+	win.SetScrollInfo(pv.hWnd, win.SB_VERT, &si, true)
+
+	if pos != pv.scrollPos {
+		pv.scrollPos = pos
+		pv.Invalidate()
+	}
+}
+
+func (pv *PaletteView) scroll(cmd uint16) {
+	line := pv.swatchSizePixels() + pv.gap()
+	page := maxi(line, pv.ClientBoundsPixels().Height-line)
+
+	switch cmd {
+	case win.SB_LINEUP:
+		pv.setScrollPos(pv.scrollPos - line)
+
+	case win.SB_LINEDOWN:
+		pv.setScrollPos(pv.scrollPos + line)
+
+	case win.SB_PAGEUP:
+		pv.setScrollPos(pv.scrollPos - page)
+
+	case win.SB_PAGEDOWN:
+		pv.setScrollPos(pv.scrollPos + page)
+
+	case win.SB_TOP:
+		pv.setScrollPos(0)
+
+	case win.SB_BOTTOM:
+		pv.setScrollPos(pv.contentHeight())
+
+	case win.SB_THUMBTRACK:
+		var si win.SCROLLINFO
+		si.CbSize = uint32(unsafe.Sizeof(si))
+		si.FMask = win.SIF_TRACKPOS
+
+		win.GetScrollInfo(pv.hWnd, win.SB_VERT, &si)
+
+		pv.setScrollPos(int(si.NTrackPos))
+	}
+}
+
+// dropTargetAt returns the walk window at pt in screen coordinates.
+func dropTargetAt(pt win.POINT) Window {
+	for hwnd := win.WindowFromPoint(pt); hwnd != 0; hwnd = win.GetParent(hwnd) {
+		if w := windowFromHandle(hwnd); w != nil {
+			return w
+		}
+	}
+
+	return nil
+}
+
+func (pv *PaletteView) drop(dragIndex int, screenPt win.POINT) {
+	color := pv.palette.At(dragIndex)
+
+	target := dropTargetAt(screenPt)
+
+	if tpv, ok := target.(*PaletteView); ok {
+		pt := screenPt
+		win.ScreenToClient(tpv.hWnd, &pt)
+
+		index := tpv.insertionIndexAt(int(pt.X), int(pt.Y))
+
+		if tpv == pv {
+			if index >= pv.palette.Len() {
+				index = pv.palette.Len() - 1
+			}
+
+			if pv.palette.Move(dragIndex, index) == nil {
+				pv.setCurrentIndex(index)
+			}
+		} else if tpv.palette.Insert(index, color) == nil {
+			tpv.setCurrentIndex(index)
+		}
+	}
+
+	pv.colorDroppedPublisher.Publish(color, target)
+}
+
+func (pv *PaletteView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, pv.DPI(), pv.paint) {
+			return 0
+		}
+
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_SIZE:
+		pv.updateScrollBar()
+		pv.Invalidate()
+
+	case win.WM_VSCROLL:
+		pv.scroll(win.LOWORD(uint32(wParam)))
+		return 0
+
+	case win.WM_MOUSEWHEEL:
+		delta := int(int16(win.HIWORD(uint32(wParam))))
+		line := pv.swatchSizePixels() + pv.gap()
+
+		pv.setScrollPos(pv.scrollPos - delta*line/120)
+		return 0
+
+	case win.WM_LBUTTONDOWN:
+		if !pv.Enabled() {
+			break
+		}
+
+		pv.SetFocus()
+
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+		index := pv.indexAt(x, y)
+		pv.setCurrentIndex(index)
+
+		if index > -1 {
+			pv.dragIndex = index
+			pv.dragOrigin = win.POINT{X: int32(x), Y: int32(y)}
+			win.SetCapture(hwnd)
+		}
+
+	case win.WM_MOUSEMOVE:
+		if pv.dragIndex < 0 {
+			break
+		}
+
+		x, y := win.GET_X_LPARAM(lParam), win.GET_Y_LPARAM(lParam)
+
+		if !pv.dragging {
+			dx, dy := x-pv.dragOrigin.X, y-pv.dragOrigin.Y
+			if dx < 0 {
+				dx = -dx
+			}
+			if dy < 0 {
+				dy = -dy
+			}
+
+			if dx <= win.GetSystemMetrics(win.SM_CXDRAG) && dy <= win.GetSystemMetrics(win.SM_CYDRAG) {
+				break
+			}
+
+			pv.dragging = true
+		}
+
+		pt := win.POINT{X: x, Y: y}
+		win.ClientToScreen(hwnd, &pt)
+
+		if dropTargetAt(pt) != nil {
+			win.SetCursor(CursorHand().handle())
+		} else {
+			win.SetCursor(CursorNo().handle())
+		}
+
+	case win.WM_LBUTTONUP:
+		if pv.dragIndex < 0 {
+			break
+		}
+
+		index, dragging := pv.dragIndex, pv.dragging
+		win.ReleaseCapture()
+
+		if dragging {
+			pt := win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}
+			win.ClientToScreen(hwnd, &pt)
+
+			pv.drop(index, pt)
+		}
+
+	case win.WM_CAPTURECHANGED:
+		pv.dragIndex = -1
+		pv.dragging = false
+
+	case win.WM_LBUTTONDBLCLK:
+		if !pv.Enabled() {
+			break
+		}
+
+		if index := pv.indexAt(int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))); index > -1 {
+			pv.setCurrentIndex(index)
+			pv.itemActivatedPublisher.Publish()
+		}
+
+	case win.WM_KEYDOWN:
+		if !pv.Enabled() || pv.palette.Len() == 0 {
+			break
+		}
+
+		index := pv.currentIndex
+		cols := pv.columnCount()
+
+		switch Key(wParam) {
+		case KeyLeft:
+			index--
+
+		case KeyRight:
+			index++
+
+		case KeyUp:
+			index -= cols
+
+		case KeyDown:
+			index += cols
+
+		case KeyHome:
+			index = 0
+
+		case KeyEnd:
+			index = pv.palette.Len() - 1
+
+		case KeyReturn:
+			if index > -1 {
+				pv.itemActivatedPublisher.Publish()
+			}
+
+		default:
+			return pv.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+		}
+
+		if index != pv.currentIndex && index >= 0 && index < pv.palette.Len() {
+			pv.setCurrentIndex(index)
+			pv.EnsureVisible(index)
+		}
+	}
+
+	return pv.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (pv *PaletteView) paint(canvas *Canvas, bounds Rectangle) error {
+	bg, _ := pv.backgroundEffective()
+	if bg == nil {
+		bg = sysColorBtnFaceBrush
+	}
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	borderPen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer borderPen.Dispose()
+
+	highlightBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_HIGHLIGHT)))
+	if err != nil {
+		return err
+	}
+	defer highlightBrush.Dispose()
+
+	inset := IntFrom96DPI(2, pv.DPI())
+
+	for i, n := 0, pv.palette.Len(); i < n; i++ {
+		b := pv.swatchBounds(i)
+		if b.Y+b.Height < 0 {
+			continue
+		}
+		if b.Y > bounds.Height {
+			break
+		}
+
+		if i == pv.currentIndex {
+			hb := Rectangle{b.X - inset, b.Y - inset, b.Width + 2*inset, b.Height + 2*inset}
+			if err := canvas.FillRectanglePixels(highlightBrush, hb); err != nil {
+				return err
+			}
+		}
+
+		brush, err := NewSolidColorBrush(pv.palette.At(i).Color)
+		if err != nil {
+			return err
+		}
+
+		err = canvas.FillRectanglePixels(brush, b)
+		brush.Dispose()
+		if err != nil {
+			return err
+		}
+
+		if err := canvas.DrawRectanglePixels(borderPen, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pv *PaletteView) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	gap := IntFrom96DPI(3, ctx.dpi)
+	size := IntFrom96DPI(pv.swatchSize, ctx.dpi)
+	vsbw := int(win.GetSystemMetricsForDpi(win.SM_CXVSCROLL, uint32(ctx.dpi)))
+
+	return &paletteViewLayoutItem{
+		idealSize: Size{gap + 8*(size+gap) + vsbw, gap + 4*(size+gap)},
+		minSize:   Size{2*gap + size + vsbw, 2*gap + size},
+	}
+}
+
+type paletteViewLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+	minSize   Size // in native pixels
+}
+
+func (*paletteViewLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | GrowableHorz | ShrinkableVert | GrowableVert | GreedyVert
+}
+
+func (li *paletteViewLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *paletteViewLayoutItem) MinSize() Size {
+	return li.minSize
+}
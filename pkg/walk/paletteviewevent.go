@@ -0,0 +1,98 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+type paletteColorDroppedEventHandlerInfo struct {
+	handler PaletteColorDroppedEventHandler
+	once    bool
+}
+
+type PaletteColorDroppedEventHandler func(color PaletteColor, target Window)
+
+type PaletteColorDroppedEvent struct {
+	handlers   []*paletteColorDroppedEventHandlerInfo
+	publishing int // Number of Publish calls in progress
+}
+
+func (e *PaletteColorDroppedEvent) Attach(handler PaletteColorDroppedEventHandler) int {
+	handlerInfo := &paletteColorDroppedEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*paletteColorDroppedEventHandlerInfo(nil), e.handlers...)
+	}
+
+	for i, h := range e.handlers {
+		if h.handler == nil {
+			e.handlers[i] = handlerInfo
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handlerInfo)
+
+	return len(e.handlers) - 1
+}
+
+func (e *PaletteColorDroppedEvent) Detach(handle int) {
+	e.handlers[handle].handler = nil
+}
+
+func (e *PaletteColorDroppedEvent) Once(handler PaletteColorDroppedEventHandler) {
+	i := e.Attach(handler)
+	e.handlers[i].once = true
+}
+
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *PaletteColorDroppedEvent) Connect(handler PaletteColorDroppedEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *PaletteColorDroppedEvent) AttachOwned(owner Window, handler PaletteColorDroppedEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
+type PaletteColorDroppedEventPublisher struct {
+	event PaletteColorDroppedEvent
+}
+
+func (p *PaletteColorDroppedEventPublisher) Event() *PaletteColorDroppedEvent {
+	return &p.event
+}
+
+func (p *PaletteColorDroppedEventPublisher) Publish(color PaletteColor, target Window) {
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
+
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
+			if h.once {
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
+			}
+
+			handler(color, target)
+		}
+	}
+}
+
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+func (p *PaletteColorDroppedEventPublisher) PublishAsync(color PaletteColor, target Window) {
+	publishAsync(func() {
+		p.Publish(color, target)
+	})
+}
@@ -0,0 +1,491 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const passwordEditWindowClass = `\o/ Walk_PasswordEdit_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(passwordEditWindowClass)
+	})
+}
+
+// passwordEditChar is the character shown for each character of a masked
+// password, BLACK CIRCLE like in the password fields of Windows.
+const passwordEditChar = 0x25CF
+
+// passwordEditDefaultClipboardClearDelay is how long a password copied from a
+// revealed PasswordEdit stays on the clipboard by default.
+const passwordEditDefaultClipboardClearDelay = 30 * time.Second
+
+// PasswordEdit is a widget to enter a password or an access token.
+//
+// The text is masked, unless the user reveals it with the toggle button at the
+// end of the field. Masked text can't be copied or cut at all, revealed text
+// is placed on the clipboard with ClipboardService.SetSecretText, so it stays
+// out of the clipboard history and is cleared again after
+// ClipboardClearDelay.
+type PasswordEdit struct {
+	WidgetBase
+	edit                     *passwordLineEdit
+	hWndReveal               win.HWND
+	revealed                 bool
+	clipboardClearDelay      time.Duration
+	revealedChangedPublisher EventPublisher
+}
+
+// NewPasswordEdit returns a new, masked PasswordEdit as child of parent.
+func NewPasswordEdit(parent Container) (*PasswordEdit, error) {
+	pe := &PasswordEdit{
+		clipboardClearDelay: passwordEditDefaultClipboardClearDelay,
+	}
+
+	if err := InitWidget(
+		pe,
+		parent,
+		passwordEditWindowClass,
+		win.WS_VISIBLE,
+		win.WS_EX_CONTROLPARENT); err != nil {
+		return nil, err
+	}
+
+	var succeeded bool
+	defer func() {
+		if !succeeded {
+			pe.Dispose()
+		}
+	}()
+
+	var err error
+	if pe.edit, err = newPasswordLineEdit(pe); err != nil {
+		return nil, err
+	}
+
+	if err := pe.SetRevealButtonVisible(true); err != nil {
+		return nil, err
+	}
+
+	pe.applyFont(pe.Font())
+
+	pe.GraphicsEffects().Add(InteractionEffect)
+	pe.GraphicsEffects().Add(FocusEffect)
+
+	pe.MustRegisterProperty("ReadOnly", NewProperty(
+		func() interface{} {
+			return pe.ReadOnly()
+		},
+		func(v interface{}) error {
+			return pe.SetReadOnly(v.(bool))
+		},
+		pe.edit.readOnlyChangedPublisher.Event()))
+
+	pe.MustRegisterProperty("Revealed", NewBoolProperty(
+		func() bool {
+			return pe.Revealed()
+		},
+		func(b bool) error {
+			pe.SetRevealed(b)
+			return nil
+		},
+		pe.revealedChangedPublisher.Event()))
+
+	pe.MustRegisterProperty("Text", NewProperty(
+		func() interface{} {
+			return pe.Text()
+		},
+		func(v interface{}) error {
+			return pe.SetText(assertStringOr(v, ""))
+		},
+		pe.edit.textChangedPublisher.Event()))
+
+	succeeded = true
+
+	return pe, nil
+}
+
+func (pe *PasswordEdit) applyEnabled(enabled bool) {
+	pe.WidgetBase.applyEnabled(enabled)
+
+	if pe.edit == nil {
+		return
+	}
+
+	pe.edit.applyEnabled(enabled)
+
+	if pe.hWndReveal != 0 {
+		win.EnableWindow(pe.hWndReveal, enabled)
+	}
+}
+
+func (pe *PasswordEdit) applyFont(font *Font) {
+	pe.WidgetBase.applyFont(font)
+
+	if pe.edit == nil {
+		return
+	}
+
+	pe.edit.applyFont(font)
+
+	if pe.hWndReveal != 0 {
+		setWindowFont(pe.hWndReveal, font.handleForDPI(pe.DPI()))
+	}
+}
+
+// Text returns the password.
+func (pe *PasswordEdit) Text() string {
+	return pe.edit.Text()
+}
+
+// SetText sets the password.
+func (pe *PasswordEdit) SetText(text string) error {
+	return pe.edit.SetText(text)
+}
+
+// TextChanged returns the event that is published when the password changed.
+func (pe *PasswordEdit) TextChanged() *Event {
+	return pe.edit.TextChanged()
+}
+
+// EditingFinished returns the event that is published when the user pressed
+// the return key or the PasswordEdit lost the focus.
+func (pe *PasswordEdit) EditingFinished() *Event {
+	return pe.edit.EditingFinished()
+}
+
+// CueBanner returns the hint shown while the PasswordEdit is empty.
+func (pe *PasswordEdit) CueBanner() string {
+	return pe.edit.CueBanner()
+}
+
+// SetCueBanner sets the hint shown while the PasswordEdit is empty.
+func (pe *PasswordEdit) SetCueBanner(value string) error {
+	return pe.edit.SetCueBanner(value)
+}
+
+// MaxLength returns the maximum number of characters of the password.
+func (pe *PasswordEdit) MaxLength() int {
+	return pe.edit.MaxLength()
+}
+
+// SetMaxLength sets the maximum number of characters of the password.
+func (pe *PasswordEdit) SetMaxLength(value int) {
+	pe.edit.SetMaxLength(value)
+}
+
+// ReadOnly returns whether the PasswordEdit is in read-only mode.
+func (pe *PasswordEdit) ReadOnly() bool {
+	return pe.edit.ReadOnly()
+}
+
+// SetReadOnly sets whether the PasswordEdit is in read-only mode.
+func (pe *PasswordEdit) SetReadOnly(readOnly bool) error {
+	if readOnly != pe.ReadOnly() {
+		pe.invalidateBorderInParent()
+	}
+
+	return pe.edit.SetReadOnly(readOnly)
+}
+
+// Revealed returns whether the password is shown in plain text.
+func (pe *PasswordEdit) Revealed() bool {
+	return pe.revealed
+}
+
+// SetRevealed sets whether the password is shown in plain text.
+func (pe *PasswordEdit) SetRevealed(revealed bool) {
+	if revealed == pe.revealed {
+		return
+	}
+
+	pe.revealed = revealed
+
+	var c uintptr
+	if !revealed {
+		c = passwordEditChar
+	}
+	pe.edit.SendMessage(win.EM_SETPASSWORDCHAR, c, 0)
+	pe.edit.Invalidate()
+
+	pe.updateRevealButton()
+
+	pe.revealedChangedPublisher.Publish()
+}
+
+// RevealedChanged returns the event that is published when the password was
+// revealed or masked.
+func (pe *PasswordEdit) RevealedChanged() *Event {
+	return pe.revealedChangedPublisher.Event()
+}
+
+// RevealButtonVisible returns whether the toggle button to reveal the
+// password is shown.
+func (pe *PasswordEdit) RevealButtonVisible() bool {
+	return pe.hWndReveal != 0
+}
+
+// SetRevealButtonVisible sets whether the toggle button to reveal the
+// password is shown, e.g. to hide it for secrets that must never be shown.
+func (pe *PasswordEdit) SetRevealButtonVisible(visible bool) error {
+	if visible == (pe.hWndReveal != 0) {
+		return nil
+	}
+
+	if visible {
+		pe.hWndReveal = win.CreateWindowEx(
+			0,
+			syscall.StringToUTF16Ptr("BUTTON"),
+			nil,
+			win.WS_CHILD|win.WS_VISIBLE|win.BS_AUTOCHECKBOX|win.BS_PUSHLIKE,
+			0,
+			0,
+			0,
+			0,
+			pe.hWnd,
+			0,
+			0,
+			nil)
+		if pe.hWndReveal == 0 {
+			return lastError("CreateWindowEx")
+		}
+
+		setWindowFont(pe.hWndReveal, pe.Font().handleForDPI(pe.DPI()))
+		win.EnableWindow(pe.hWndReveal, pe.Enabled())
+
+		pe.updateRevealButton()
+	} else {
+		if !win.DestroyWindow(pe.hWndReveal) {
+			return lastError("DestroyWindow")
+		}
+
+		pe.hWndReveal = 0
+	}
+
+	pe.layoutChildren()
+
+	return nil
+}
+
+func (pe *PasswordEdit) updateRevealButton() {
+	if pe.hWndReveal == 0 {
+		return
+	}
+
+	text, check := tr("Show", "walk"), uintptr(win.BST_UNCHECKED)
+	if pe.revealed {
+		text, check = tr("Hide", "walk"), win.BST_CHECKED
+	}
+
+	win.SendMessage(pe.hWndReveal, win.WM_SETTEXT, 0, uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(text))))
+	win.SendMessage(pe.hWndReveal, win.BM_SETCHECK, check, 0)
+}
+
+// ClipboardClearDelay returns how long revealed text that was copied from the
+// PasswordEdit stays on the clipboard.
+func (pe *PasswordEdit) ClipboardClearDelay() time.Duration {
+	return pe.clipboardClearDelay
+}
+
+// SetClipboardClearDelay sets how long revealed text that was copied from the
+// PasswordEdit stays on the clipboard. 0 leaves it there.
+func (pe *PasswordEdit) SetClipboardClearDelay(delay time.Duration) {
+	pe.clipboardClearDelay = delay
+}
+
+// SetFocus sets the keyboard input focus to the PasswordEdit.
+func (pe *PasswordEdit) SetFocus() error {
+	if win.SetFocus(pe.edit.hWnd) == 0 {
+		return lastError("SetFocus")
+	}
+
+	return nil
+}
+
+// TextSelection returns the range of the current text selection of the
+// PasswordEdit.
+func (pe *PasswordEdit) TextSelection() (start, end int) {
+	return pe.edit.TextSelection()
+}
+
+// SetTextSelection sets the range of the current text selection of the
+// PasswordEdit.
+func (pe *PasswordEdit) SetTextSelection(start, end int) {
+	pe.edit.SetTextSelection(start, end)
+}
+
+// Background returns the background Brush of the PasswordEdit.
+//
+// By default this is nil.
+func (pe *PasswordEdit) Background() Brush {
+	return pe.edit.Background()
+}
+
+// SetBackground sets the background Brush of the PasswordEdit.
+func (pe *PasswordEdit) SetBackground(bg Brush) {
+	pe.edit.SetBackground(bg)
+}
+
+// TextColor returns the Color used to draw the text of the PasswordEdit.
+func (pe *PasswordEdit) TextColor() Color {
+	return pe.edit.TextColor()
+}
+
+// SetTextColor sets the Color used to draw the text of the PasswordEdit.
+func (pe *PasswordEdit) SetTextColor(c Color) {
+	pe.edit.SetTextColor(c)
+}
+
+func (*PasswordEdit) NeedsWmSize() bool {
+	return true
+}
+
+// layoutChildren places the edit in the client area and the reveal button at
+// its end.
+func (pe *PasswordEdit) layoutChildren() {
+	if pe.edit == nil {
+		return
+	}
+
+	cb := pe.ClientBoundsPixels()
+
+	if pe.hWndReveal != 0 {
+		w := mini(pe.dialogBaseUnitsToPixels(Size{24, 12}).Width, cb.Width/2)
+		cb.Width -= w
+
+		win.MoveWindow(pe.hWndReveal, int32(cb.Width), int32(cb.Y), int32(w), int32(cb.Height), true)
+	}
+
+	pe.edit.SetBoundsPixels(cb)
+}
+
+// WndProc is the window procedure of the PasswordEdit.
+//
+// When implementing your own WndProc to add or modify behavior, call the
+// WndProc of the embedded PasswordEdit for messages you don't handle yourself.
+func (pe *PasswordEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_COMMAND:
+		if lParam != 0 && win.HWND(lParam) == pe.hWndReveal && win.HIWORD(uint32(wParam)) == win.BN_CLICKED {
+			pe.SetRevealed(win.SendMessage(pe.hWndReveal, win.BM_GETCHECK, 0, 0) == win.BST_CHECKED)
+			pe.SetFocus()
+			return 0
+		}
+
+	case win.WM_CTLCOLOREDIT, win.WM_CTLCOLORSTATIC:
+		if hBrush := pe.handleWMCTLCOLOR(wParam, lParam); hBrush != 0 {
+			return hBrush
+		}
+
+	case win.WM_WINDOWPOSCHANGED:
+		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
+
+		if wp.Flags&win.SWP_NOSIZE != 0 {
+			break
+		}
+
+		pe.layoutChildren()
+	}
+
+	return pe.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (pe *PasswordEdit) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &passwordEditLayoutItem{
+		idealSize: pe.dialogBaseUnitsToPixels(Size{80, 12}),
+		minSize:   pe.dialogBaseUnitsToPixels(Size{40, 12}),
+	}
+}
+
+type passwordEditLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+	minSize   Size // in native pixels
+}
+
+func (*passwordEditLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | GrowableHorz
+}
+
+func (li *passwordEditLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *passwordEditLayoutItem) MinSize() Size {
+	return li.minSize
+}
+
+type passwordLineEdit struct {
+	*LineEdit
+	owner *PasswordEdit
+}
+
+func newPasswordLineEdit(owner *PasswordEdit) (*passwordLineEdit, error) {
+	ple := &passwordLineEdit{owner: owner}
+
+	var err error
+	if ple.LineEdit, err = newLineEdit(owner, win.WS_EX_CLIENTEDGE); err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			ple.Dispose()
+		}
+	}()
+
+	ple.SendMessage(win.EM_SETPASSWORDCHAR, passwordEditChar, 0)
+
+	if err := InitWrapperWindow(ple); err != nil {
+		return nil, err
+	}
+
+	succeeded = true
+
+	return ple, nil
+}
+
+func (ple *passwordLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_COPY, win.WM_CUT:
+		if !ple.owner.revealed {
+			return 0
+		}
+
+		start, end := ple.TextSelection()
+		if start == end {
+			return 0
+		}
+
+		// The selection is in UTF-16 code units.
+		text := syscall.StringToUTF16(ple.Text())
+		defer zeroUTF16(text)
+		if end > len(text)-1 {
+			end = len(text) - 1
+		}
+
+		if err := Clipboard().SetSecretText(syscall.UTF16ToString(text[start:end]), ple.owner.clipboardClearDelay); err != nil {
+			return 0
+		}
+
+		if msg == win.WM_CUT && !ple.ReadOnly() {
+			ple.SendMessage(win.EM_REPLACESEL, 1, uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(""))))
+		}
+
+		return 0
+	}
+
+	return ple.LineEdit.WndProc(hwnd, msg, wParam, lParam)
+}
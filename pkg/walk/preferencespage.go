@@ -0,0 +1,255 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"math"
+)
+
+// preferencesFontScales are the font scales offered by a PreferencesPage.
+var preferencesFontScales = []float64{0.9, 1, 1.1, 1.25, 1.5, 1.75, 2}
+
+// PreferencesPage lets the user choose the Appearance of the application,
+// i.e. the theme, the family and scale of the UI font and the language.
+//
+// Every choice takes effect immediately by way of SetAppearance, which also
+// stores it in App().Settings(). The language row is only shown if languages
+// were added with RegisterLanguage.
+type PreferencesPage struct {
+	*Composite
+	themes             []AppearanceChoice
+	languages          []AppearanceChoice
+	fontFamilies       []string
+	themeComboBox      *ComboBox
+	fontFamilyComboBox *ComboBox
+	fontScaleComboBox  *ComboBox
+	languageLabel      *Label
+	languageComboBox   *ComboBox
+	updating           bool
+}
+
+// NewPreferencesPage returns a new PreferencesPage as child of parent.
+func NewPreferencesPage(parent Container) (*PreferencesPage, error) {
+	composite, err := NewComposite(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	pp := &PreferencesPage{Composite: composite}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			pp.Dispose()
+		}
+	}()
+
+	if err := InitWrapperWindow(pp); err != nil {
+		return nil, err
+	}
+
+	layout := NewGridLayout()
+	if err := pp.SetLayout(layout); err != nil {
+		return nil, err
+	}
+
+	row := 0
+	addRow := func(text string) (*Label, *ComboBox, error) {
+		label, err := NewLabel(pp)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := label.SetText(text); err != nil {
+			return nil, nil, err
+		}
+
+		comboBox, err := NewDropDownBox(pp)
+		if err != nil {
+			return nil, nil, err
+		}
+		comboBox.CurrentIndexChanged().Attach(pp.onCurrentIndexChanged)
+
+		if err := layout.SetRange(label, Rectangle{X: 0, Y: row, Width: 1, Height: 1}); err != nil {
+			return nil, nil, err
+		}
+		if err := layout.SetRange(comboBox, Rectangle{X: 1, Y: row, Width: 1, Height: 1}); err != nil {
+			return nil, nil, err
+		}
+
+		row++
+
+		return label, comboBox, nil
+	}
+
+	if _, pp.themeComboBox, err = addRow(tr("Theme:")); err != nil {
+		return nil, err
+	}
+	if _, pp.fontFamilyComboBox, err = addRow(tr("Font:")); err != nil {
+		return nil, err
+	}
+	if _, pp.fontScaleComboBox, err = addRow(tr("Font size:")); err != nil {
+		return nil, err
+	}
+	if pp.languageLabel, pp.languageComboBox, err = addRow(tr("Language:")); err != nil {
+		return nil, err
+	}
+
+	spacer, err := NewVSpacer(pp)
+	if err != nil {
+		return nil, err
+	}
+	if err := layout.SetRange(spacer, Rectangle{X: 0, Y: row, Width: 2, Height: 1}); err != nil {
+		return nil, err
+	}
+
+	if err := pp.resetChoices(); err != nil {
+		return nil, err
+	}
+
+	AppearanceChanged().AttachOwned(pp, pp.updateCurrentChoices)
+
+	succeeded = true
+
+	return pp, nil
+}
+
+// Appearance returns the Appearance chosen with the PreferencesPage.
+func (pp *PreferencesPage) Appearance() Appearance {
+	a := CurrentAppearance()
+
+	if i := pp.themeComboBox.CurrentIndex(); i >= 0 && i < len(pp.themes) {
+		a.Theme = pp.themes[i].ID
+	}
+
+	// The first font family item stands for the default font.
+	if i := pp.fontFamilyComboBox.CurrentIndex(); i == 0 {
+		a.FontFamily = ""
+	} else if i > 0 && i <= len(pp.fontFamilies) {
+		a.FontFamily = pp.fontFamilies[i-1]
+	}
+
+	if i := pp.fontScaleComboBox.CurrentIndex(); i >= 0 && i < len(preferencesFontScales) {
+		a.FontScale = preferencesFontScales[i]
+	}
+
+	// The first language item stands for the language the strings are
+	// written in.
+	if i := pp.languageComboBox.CurrentIndex(); i == 0 {
+		a.Language = ""
+	} else if i > 0 && i <= len(pp.languages) {
+		a.Language = pp.languages[i-1].ID
+	}
+
+	return a
+}
+
+// resetChoices fills the combo boxes with the themes, font families and
+// languages available now and selects those of the current Appearance.
+func (pp *PreferencesPage) resetChoices() error {
+	pp.updating = true
+	defer func() {
+		pp.updating = false
+	}()
+
+	pp.themes = Themes()
+	pp.languages = Languages()
+	pp.fontFamilies = FontFamilies()
+
+	themeNames := make([]string, len(pp.themes))
+	for i, theme := range pp.themes {
+		themeNames[i] = tr(theme.Name)
+	}
+	if err := pp.themeComboBox.SetModel(themeNames); err != nil {
+		return err
+	}
+
+	if err := pp.fontFamilyComboBox.SetModel(append([]string{tr("Default")}, pp.fontFamilies...)); err != nil {
+		return err
+	}
+
+	scaleNames := make([]string, len(preferencesFontScales))
+	for i, scale := range preferencesFontScales {
+		scaleNames[i] = fmt.Sprintf("%d%%", int(math.Round(scale*100)))
+	}
+	if err := pp.fontScaleComboBox.SetModel(scaleNames); err != nil {
+		return err
+	}
+
+	languageNames := []string{tr("Default")}
+	for _, language := range pp.languages {
+		languageNames = append(languageNames, language.Name)
+	}
+	if err := pp.languageComboBox.SetModel(languageNames); err != nil {
+		return err
+	}
+
+	pp.languageLabel.SetVisible(len(pp.languages) > 0)
+	pp.languageComboBox.SetVisible(len(pp.languages) > 0)
+
+	pp.updateCurrentChoices()
+
+	return nil
+}
+
+// updateCurrentChoices selects the items of the current Appearance.
+func (pp *PreferencesPage) updateCurrentChoices() {
+	wasUpdating := pp.updating
+	pp.updating = true
+	defer func() {
+		pp.updating = wasUpdating
+	}()
+
+	a := CurrentAppearance()
+
+	themeIndex := -1
+	for i, theme := range pp.themes {
+		if theme.ID == a.Theme {
+			themeIndex = i
+			break
+		}
+	}
+	pp.themeComboBox.SetCurrentIndex(themeIndex)
+
+	fontFamilyIndex := 0
+	for i, family := range pp.fontFamilies {
+		if family == a.FontFamily {
+			fontFamilyIndex = i + 1
+			break
+		}
+	}
+	pp.fontFamilyComboBox.SetCurrentIndex(fontFamilyIndex)
+
+	// Scales stored by other means may not be offered, so pick the closest.
+	fontScaleIndex := 0
+	for i, scale := range preferencesFontScales {
+		if math.Abs(scale-a.FontScale) < math.Abs(preferencesFontScales[fontScaleIndex]-a.FontScale) {
+			fontScaleIndex = i
+		}
+	}
+	pp.fontScaleComboBox.SetCurrentIndex(fontScaleIndex)
+
+	languageIndex := 0
+	for i, language := range pp.languages {
+		if language.ID == a.Language {
+			languageIndex = i + 1
+			break
+		}
+	}
+	pp.languageComboBox.SetCurrentIndex(languageIndex)
+}
+
+func (pp *PreferencesPage) onCurrentIndexChanged() {
+	if pp.updating {
+		return
+	}
+
+	if err := SetAppearance(pp.Appearance()); err != nil {
+		wrapErrorNoPanic(err)
+	}
+}
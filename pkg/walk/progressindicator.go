@@ -89,7 +89,7 @@ func (pi *ProgressIndicator) Completed() uint32 {
 func (pi *ProgressIndicator) SetOverlayIcon(icon *Icon, description string) error {
 	handle := win.HICON(0)
 	if icon != nil {
-		handle = icon.handleForDPI(int(win.GetDpiForWindow(pi.hwnd)))
+		handle = icon.handleForDPI(dpiForWindow(pi.hwnd))
 	}
 	description16, err := syscall.UTF16PtrFromString(description)
 	if err != nil {
@@ -29,6 +29,25 @@ type Property interface {
 	SetValidator(validator Validator) error
 }
 
+// BindingConverter converts values between a data source field and the
+// property of a widget bound to it.
+type BindingConverter interface {
+	// ToProperty converts the value of the field to a value of the property.
+	ToProperty(value interface{}) (interface{}, error)
+
+	// FromProperty converts the value of the property to a value of the
+	// field. An error counts as a validation error of the binding.
+	FromProperty(value interface{}) (interface{}, error)
+}
+
+// ConvertibleProperty is a Property whose values are converted by a
+// BindingConverter when they are exchanged with its data source field.
+type ConvertibleProperty interface {
+	Property
+	Converter() BindingConverter
+	SetConverter(converter BindingConverter) error
+}
+
 type property struct {
 	get                 func() interface{}
 	set                 func(v interface{}) error
@@ -36,6 +55,7 @@ type property struct {
 	source              interface{}
 	sourceChangedHandle int
 	validator           Validator
+	converter           BindingConverter
 }
 
 func NewProperty(get func() interface{}, set func(v interface{}) error, changed *Event) Property {
@@ -136,6 +156,20 @@ func (p *property) SetValidator(validator Validator) error {
 	return nil
 }
 
+func (p *property) Converter() BindingConverter {
+	return p.converter
+}
+
+func (p *property) SetConverter(converter BindingConverter) error {
+	if p.ReadOnly() {
+		return ErrPropertyReadOnly
+	}
+
+	p.converter = converter
+
+	return nil
+}
+
 type readOnlyProperty struct {
 	get     func() interface{}
 	changed *Event
@@ -191,6 +225,7 @@ type boolProperty struct {
 	changed             *Event
 	source              interface{}
 	sourceChangedHandle int
+	converter           BindingConverter
 }
 
 func NewBoolProperty(get func() bool, set func(b bool) error, changed *Event) Property {
@@ -296,6 +331,20 @@ func (*boolProperty) SetValidator(validator Validator) error {
 	return ErrPropertyNotValidatable
 }
 
+func (bp *boolProperty) Converter() BindingConverter {
+	return bp.converter
+}
+
+func (bp *boolProperty) SetConverter(converter BindingConverter) error {
+	if bp.ReadOnly() {
+		return ErrPropertyReadOnly
+	}
+
+	bp.converter = converter
+
+	return nil
+}
+
 func (bp *boolProperty) Satisfied() bool {
 	return bp.get()
 }
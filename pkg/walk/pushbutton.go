@@ -13,6 +13,7 @@ import (
 
 type PushButton struct {
 	Button
+	requiresElevation bool
 }
 
 func NewPushButton(parent Container) (*PushButton, error) {
@@ -0,0 +1,535 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"errors"
+)
+
+// ErrQRCodeTextTooLong is returned if a text doesn't fit into a QR code of
+// the largest version at the requested error correction level.
+var ErrQRCodeTextTooLong = errors.New("text too long for a QR code")
+
+// QRErrorCorrectionLevel specifies the share of a QR code that may be damaged
+// or covered while it still can be read.
+type QRErrorCorrectionLevel int
+
+const (
+	// QRErrorCorrectionMedium restores about 15% of the code. It is the
+	// default.
+	QRErrorCorrectionMedium QRErrorCorrectionLevel = iota
+
+	// QRErrorCorrectionLow restores about 7% of the code.
+	QRErrorCorrectionLow
+
+	// QRErrorCorrectionQuartile restores about 25% of the code.
+	QRErrorCorrectionQuartile
+
+	// QRErrorCorrectionHigh restores about 30% of the code.
+	QRErrorCorrectionHigh
+)
+
+const (
+	qrMinVersion = 1
+	qrMaxVersion = 40
+)
+
+// qrFormatBits are the bits of the error correction levels in the format
+// information.
+var qrFormatBits = [4]int{0, 1, 3, 2}
+
+// qrECCCodewordsPerBlock and qrErrorCorrectionBlocks are indexed by error
+// correction level and version. Index 0 of each version is unused.
+var qrECCCodewordsPerBlock = [4][41]int{
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var qrErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// qrCode is an encoded QR code. modules[y][x] is true for dark modules.
+type qrCode struct {
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// encodeQRCode encodes data in byte mode into a QR code of the smallest
+// version that fits, choosing the mask with the lowest penalty.
+func encodeQRCode(data []byte, level QRErrorCorrectionLevel) (*qrCode, error) {
+	version := qrMinVersion
+	for ; ; version++ {
+		if version > qrMaxVersion {
+			return nil, ErrQRCodeTextTooLong
+		}
+
+		if qrDataBits(version, len(data)) <= qrNumDataCodewords(version, level)*8 {
+			break
+		}
+	}
+
+	var bb qrBitBuffer
+	bb.append(0x4, 4) // byte mode
+	bb.append(len(data), qrCharCountBits(version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+
+	capacity := qrNumDataCodewords(version, level) * 8
+
+	terminator := capacity - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	size := version*4 + 17
+	qr := &qrCode{
+		size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for i := 0; i < size; i++ {
+		qr.modules[i] = make([]bool, size)
+		qr.isFunction[i] = make([]bool, size)
+	}
+
+	qr.drawFunctionPatterns(version, level)
+	qr.drawCodewords(qrAddECCAndInterleave(codewords, version, level))
+
+	bestMask, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(level, mask)
+
+		if penalty := qr.penalty(); minPenalty == -1 || penalty < minPenalty {
+			bestMask, minPenalty = mask, penalty
+		}
+
+		qr.applyMask(mask) // XOR undoes it.
+	}
+
+	qr.applyMask(bestMask)
+	qr.drawFormatBits(level, bestMask)
+
+	return qr, nil
+}
+
+type qrBitBuffer []bool
+
+func (bb *qrBitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*bb = append(*bb, (value>>uint(i))&1 != 0)
+	}
+}
+
+func qrCharCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+
+	return 16
+}
+
+func qrDataBits(version, byteCount int) int {
+	if byteCount >= 1<<uint(qrCharCountBits(version)) {
+		return 1 << 30
+	}
+
+	return 4 + qrCharCountBits(version) + byteCount*8
+}
+
+// qrNumRawDataModules returns the number of modules of a version that are
+// no function patterns, including remainder bits.
+func qrNumRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+
+		if version >= 7 {
+			result -= 36
+		}
+	}
+
+	return result
+}
+
+func qrNumDataCodewords(version int, level QRErrorCorrectionLevel) int {
+	return qrNumRawDataModules(version)/8 - qrECCCodewordsPerBlock[level][version]*qrErrorCorrectionBlocks[level][version]
+}
+
+// qrAddECCAndInterleave splits data into blocks, appends the Reed-Solomon
+// error correction codewords to each and interleaves the blocks.
+func qrAddECCAndInterleave(data []byte, version int, level QRErrorCorrectionLevel) []byte {
+	numBlocks := qrErrorCorrectionBlocks[level][version]
+	blockECCLen := qrECCCodewordsPerBlock[level][version]
+	rawCodewords := qrNumRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := qrReedSolomonDivisor(blockECCLen)
+
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortBlockLen - blockECCLen
+		if i >= numShortBlocks {
+			n++
+		}
+
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+
+		ecc := qrReedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			// Pad short blocks, so all blocks can be interleaved alike.
+			block = append(block, 0)
+		}
+
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockECCLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+
+	return result
+}
+
+func qrReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = qrReedSolomonMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+
+		root = qrReedSolomonMultiply(root, 0x02)
+	}
+
+	return result
+}
+
+func qrReedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+
+	for _, b := range data {
+		factor := b ^ result[0]
+
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+
+		for i, d := range divisor {
+			result[i] ^= qrReedSolomonMultiply(d, factor)
+		}
+	}
+
+	return result
+}
+
+// qrReedSolomonMultiply multiplies x and y in GF(2^8/0x11D).
+func qrReedSolomonMultiply(x, y byte) byte {
+	var z int
+
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+
+	return byte(z)
+}
+
+func (qr *qrCode) setFunctionModule(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.isFunction[y][x] = true
+}
+
+func (qr *qrCode) drawFunctionPatterns(version int, level QRErrorCorrectionLevel) {
+	for i := 0; i < qr.size; i++ {
+		qr.setFunctionModule(6, i, i%2 == 0)
+		qr.setFunctionModule(i, 6, i%2 == 0)
+	}
+
+	qr.drawFinderPattern(3, 3)
+	qr.drawFinderPattern(qr.size-4, 3)
+	qr.drawFinderPattern(3, qr.size-4)
+
+	positions := qrAlignmentPatternPositions(version, qr.size)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue // Overlaps a finder pattern.
+			}
+
+			qr.drawAlignmentPattern(x, y)
+		}
+	}
+
+	// Reserves the format and version areas.
+	qr.drawFormatBits(level, 0)
+	qr.drawVersion(version)
+}
+
+func (qr *qrCode) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= qr.size || yy < 0 || yy >= qr.size {
+				continue
+			}
+
+			dist := maxi(absi(dx), absi(dy))
+			qr.setFunctionModule(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (qr *qrCode) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			qr.setFunctionModule(x+dx, y+dy, maxi(absi(dx), absi(dy)) != 1)
+		}
+	}
+}
+
+func qrAlignmentPatternPositions(version, size int) []int {
+	if version == 1 {
+		return nil
+	}
+
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, size-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+
+	return result
+}
+
+func (qr *qrCode) drawFormatBits(level QRErrorCorrectionLevel, mask int) {
+	data := qrFormatBits[level]<<3 | mask
+
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	bit := func(i int) bool {
+		return (bits>>uint(i))&1 != 0
+	}
+
+	for i := 0; i <= 5; i++ {
+		qr.setFunctionModule(8, i, bit(i))
+	}
+	qr.setFunctionModule(8, 7, bit(6))
+	qr.setFunctionModule(8, 8, bit(7))
+	qr.setFunctionModule(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.setFunctionModule(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		qr.setFunctionModule(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunctionModule(8, qr.size-15+i, bit(i))
+	}
+	qr.setFunctionModule(8, qr.size-8, true)
+}
+
+func (qr *qrCode) drawVersion(version int) {
+	if version < 7 {
+		return
+	}
+
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := (bits>>uint(i))&1 != 0
+		a, b := qr.size-11+i%3, i/3
+
+		qr.setFunctionModule(a, b, dark)
+		qr.setFunctionModule(b, a, dark)
+	}
+}
+
+// drawCodewords places data in the zigzag order of the standard, skipping
+// function modules.
+func (qr *qrCode) drawCodewords(data []byte) {
+	i := 0
+
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skips the vertical timing pattern.
+		}
+
+		for vert := 0; vert < qr.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+
+				if !qr.isFunction[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = (data[i>>3]>>uint(7-(i&7)))&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (qr *qrCode) applyMask(mask int) {
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+
+			if invert && !qr.isFunction[y][x] {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code is to read, following the rules of the
+// standard for choosing a mask.
+func (qr *qrCode) penalty() int {
+	var result int
+
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+
+	line := make([]bool, qr.size)
+
+	for pass := 0; pass < 2; pass++ {
+		for i := 0; i < qr.size; i++ {
+			for j := 0; j < qr.size; j++ {
+				if pass == 0 {
+					line[j] = qr.modules[i][j]
+				} else {
+					line[j] = qr.modules[j][i]
+				}
+			}
+
+			// Runs of five or more modules of the same color.
+			run := 1
+			for j := 1; j <= qr.size; j++ {
+				if j < qr.size && line[j] == line[j-1] {
+					run++
+					continue
+				}
+
+				if run >= 5 {
+					result += 3 + run - 5
+				}
+				run = 1
+			}
+
+			// Patterns that look like finder patterns.
+			for j := 0; j+len(finderLike[0]) <= qr.size; j++ {
+				for _, pattern := range finderLike {
+					match := true
+					for k, dark := range pattern {
+						if line[j+k] != dark {
+							match = false
+							break
+						}
+					}
+
+					if match {
+						result += 40
+					}
+				}
+			}
+		}
+	}
+
+	// 2x2 blocks of the same color.
+	for y := 0; y < qr.size-1; y++ {
+		for x := 0; x < qr.size-1; x++ {
+			c := qr.modules[y][x]
+			if c == qr.modules[y][x+1] && c == qr.modules[y+1][x] && c == qr.modules[y+1][x+1] {
+				result += 3
+			}
+		}
+	}
+
+	// Imbalance of dark and light modules.
+	var dark int
+	for _, row := range qr.modules {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	total := qr.size * qr.size
+	result += ((absi(dark*20-total*10)+total-1)/total - 1) * 10
+
+	return result
+}
@@ -0,0 +1,303 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+const qrCodeViewWindowClass = `\o/ Walk_QRCodeView_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(qrCodeViewWindowClass)
+	})
+}
+
+// qrCodeDefaultQuietZone is the width of the light margin around the code
+// the standard requires, in modules.
+const qrCodeDefaultQuietZone = 4
+
+// QRCodeView is a widget that shows its text encoded as a QR code, e.g. a
+// link or a pairing code to scan with a phone.
+//
+// The code is drawn with a whole number of native pixels per module, so it
+// stays crisp at any DPI, and centered in the bounds of the widget.
+type QRCodeView struct {
+	WidgetBase
+	text                                 string
+	level                                QRErrorCorrectionLevel
+	quietZone                            int
+	code                                 *qrCode
+	textChangedPublisher                 EventPublisher
+	errorCorrectionLevelChangedPublisher EventPublisher
+}
+
+// NewQRCodeView creates and initializes a new, empty QRCodeView with medium
+// error correction.
+func NewQRCodeView(parent Container) (*QRCodeView, error) {
+	qv := &QRCodeView{
+		level:     QRErrorCorrectionMedium,
+		quietZone: qrCodeDefaultQuietZone,
+	}
+
+	if err := InitWidget(
+		qv,
+		parent,
+		qrCodeViewWindowClass,
+		win.WS_VISIBLE,
+		0); err != nil {
+		return nil, err
+	}
+
+	qv.MustRegisterProperty("Text", NewProperty(
+		func() interface{} {
+			return qv.Text()
+		},
+		func(v interface{}) error {
+			return qv.SetText(assertStringOr(v, ""))
+		},
+		qv.textChangedPublisher.Event()))
+
+	return qv, nil
+}
+
+// Text returns the text encoded in the QR code.
+func (qv *QRCodeView) Text() string {
+	return qv.text
+}
+
+// SetText sets the text encoded in the QR code, as UTF-8. If text is too long
+// for a QR code at the error correction level, ErrQRCodeTextTooLong is
+// returned and the text is left unchanged.
+func (qv *QRCodeView) SetText(text string) error {
+	if text == qv.text {
+		return nil
+	}
+
+	code, err := qv.encode(text, qv.level)
+	if err != nil {
+		return err
+	}
+
+	qv.text = text
+
+	qv.setCode(code)
+
+	qv.textChangedPublisher.Publish()
+
+	return nil
+}
+
+// TextChanged returns the event that is published when the text changed.
+func (qv *QRCodeView) TextChanged() *Event {
+	return qv.textChangedPublisher.Event()
+}
+
+// ErrorCorrectionLevel returns the share of the code that may be damaged or
+// covered while it still can be read.
+func (qv *QRCodeView) ErrorCorrectionLevel() QRErrorCorrectionLevel {
+	return qv.level
+}
+
+// SetErrorCorrectionLevel sets the share of the code that may be damaged or
+// covered while it still can be read. Higher levels need more modules.
+func (qv *QRCodeView) SetErrorCorrectionLevel(level QRErrorCorrectionLevel) error {
+	if level < QRErrorCorrectionMedium || level > QRErrorCorrectionHigh {
+		return newError("invalid error correction level")
+	}
+	if level == qv.level {
+		return nil
+	}
+
+	code, err := qv.encode(qv.text, level)
+	if err != nil {
+		return err
+	}
+
+	qv.level = level
+
+	qv.setCode(code)
+
+	qv.errorCorrectionLevelChangedPublisher.Publish()
+
+	return nil
+}
+
+// ErrorCorrectionLevelChanged returns the event that is published when the
+// error correction level changed.
+func (qv *QRCodeView) ErrorCorrectionLevelChanged() *Event {
+	return qv.errorCorrectionLevelChangedPublisher.Event()
+}
+
+// QuietZone returns the width of the light margin around the code, in
+// modules.
+func (qv *QRCodeView) QuietZone() int {
+	return qv.quietZone
+}
+
+// SetQuietZone sets the width of the light margin around the code, in
+// modules. Readers may fail with less than the 4 modules of the standard,
+// unless the background around the widget is light.
+func (qv *QRCodeView) SetQuietZone(modules int) {
+	if modules < 0 {
+		modules = 0
+	}
+	if modules == qv.quietZone {
+		return
+	}
+
+	qv.quietZone = modules
+
+	qv.Invalidate()
+	qv.RequestLayout()
+}
+
+func (qv *QRCodeView) encode(text string, level QRErrorCorrectionLevel) (*qrCode, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	return encodeQRCode([]byte(text), level)
+}
+
+func (qv *QRCodeView) setCode(code *qrCode) {
+	qv.code = code
+
+	qv.Invalidate()
+	qv.RequestLayout()
+}
+
+// modules returns the width of the code including the quiet zone, in modules,
+// or 0 if there is no text.
+func (qv *QRCodeView) modules() int {
+	if qv.code == nil {
+		return 0
+	}
+
+	return qv.code.size + 2*qv.quietZone
+}
+
+func (qv *QRCodeView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBufferedUnmirrored(hwnd, qv.DPI(), qv.paint) {
+			return 0
+		}
+
+	case win.WM_SIZE:
+		qv.Invalidate()
+	}
+
+	return qv.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+// paint paints the code, centered in bounds.
+func (qv *QRCodeView) paint(canvas *Canvas, bounds Rectangle) error {
+	bg, _ := qv.backgroundEffective()
+	if bg == nil {
+		bg = sysColorBtnFaceBrush
+	}
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	modules := qv.modules()
+	if modules == 0 {
+		return nil
+	}
+
+	moduleSize := mini(bounds.Width, bounds.Height) / modules
+	if moduleSize < 1 {
+		return nil
+	}
+
+	side := moduleSize * modules
+	x0 := bounds.X + (bounds.Width-side)/2
+	y0 := bounds.Y + (bounds.Height-side)/2
+
+	light, err := NewSolidColorBrush(RGB(255, 255, 255))
+	if err != nil {
+		return err
+	}
+	defer light.Dispose()
+
+	dark, err := NewSolidColorBrush(RGB(0, 0, 0))
+	if err != nil {
+		return err
+	}
+	defer dark.Dispose()
+
+	if err := canvas.FillRectanglePixels(light, Rectangle{x0, y0, side, side}); err != nil {
+		return err
+	}
+
+	x0 += qv.quietZone * moduleSize
+	y0 += qv.quietZone * moduleSize
+
+	// Fills horizontal runs of dark modules at a time.
+	for y, row := range qv.code.modules {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+
+			if err := canvas.FillRectanglePixels(dark, Rectangle{
+				x0 + start*moduleSize,
+				y0 + y*moduleSize,
+				(x - start) * moduleSize,
+				moduleSize,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (qv *QRCodeView) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	modules := qv.modules()
+	if modules == 0 {
+		modules = 21 + 2*qv.quietZone
+	}
+
+	// Ideally 3, at least 1 native pixel per module at 96 DPI.
+	return &qrCodeViewLayoutItem{
+		idealSize: SizeFrom96DPI(Size{modules * 3, modules * 3}, ctx.dpi),
+		minSize:   Size{modules, modules},
+	}
+}
+
+type qrCodeViewLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+	minSize   Size // in native pixels
+}
+
+func (li *qrCodeViewLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | ShrinkableVert | GrowableHorz | GrowableVert
+}
+
+func (li *qrCodeViewLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *qrCodeViewLayoutItem) MinSize() Size {
+	return li.minSize
+}
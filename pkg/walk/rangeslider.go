@@ -0,0 +1,669 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+
+	"github.com/miu200521358/win"
+)
+
+type rangeSliderThumb int
+
+const (
+	rangeSliderNoThumb rangeSliderThumb = iota
+	rangeSliderLowerThumb
+	rangeSliderUpperThumb
+	rangeSliderBothThumbs
+)
+
+// RangeSlider is a slider with a lower and an upper thumb for selecting a
+// range of values, e.g. a range of frames.
+//
+// Dragging a thumb moves it, dragging the bar between the thumbs moves both.
+// Clicking the track outside the thumbs moves the nearest thumb by a page.
+// The arrow, page, home and end keys move the thumb that was clicked last,
+// with the Shift key held down they move the whole range. The mouse wheel
+// moves it by a line, depending on WheelValueMode.
+//
+// Like with Slider, a vertical RangeSlider has its minimum at the top.
+type RangeSlider struct {
+	*CustomWidget
+	orientation           Orientation
+	minValue              int
+	maxValue              int
+	lower                 int
+	upper                 int
+	lineSize              int
+	pageSize              int
+	tickFrequency         int
+	activeThumb           rangeSliderThumb
+	dragThumb             rangeSliderThumb
+	dragOrigin            int
+	dragLower             int
+	dragUpper             int
+	persistent            bool
+	wheelValueMode        WheelValueMode
+	wheelDelta            int
+	lowerChangedPublisher EventPublisher
+	upperChangedPublisher EventPublisher
+	rangeChangedPublisher IntRangeEventPublisher
+}
+
+// NewRangeSlider creates and initializes a new horizontal RangeSlider.
+func NewRangeSlider(parent Container) (*RangeSlider, error) {
+	return NewRangeSliderWithOrientation(parent, Horizontal)
+}
+
+// NewRangeSliderWithOrientation creates and initializes a new RangeSlider
+// with the specified orientation.
+func NewRangeSliderWithOrientation(parent Container, orientation Orientation) (*RangeSlider, error) {
+	rs := &RangeSlider{
+		orientation: orientation,
+		maxValue:    100,
+		upper:       100,
+		lineSize:    1,
+		pageSize:    10,
+		activeThumb: rangeSliderUpperThumb,
+	}
+
+	cw, err := NewCustomWidgetPixels(parent, win.WS_TABSTOP, func(canvas *Canvas, updateBounds Rectangle) error {
+		return rs.paint(canvas)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			cw.Dispose()
+		}
+	}()
+
+	rs.CustomWidget = cw
+
+	if err := InitWrapperWindow(rs); err != nil {
+		return nil, err
+	}
+
+	rs.SetInvalidatesOnResize(true)
+
+	rs.GraphicsEffects().Add(InteractionEffect)
+	rs.GraphicsEffects().Add(FocusEffect)
+
+	rs.MustRegisterProperty("Lower", NewProperty(
+		func() interface{} {
+			return rs.Lower()
+		},
+		func(v interface{}) error {
+			return rs.SetLower(assertIntOr(v, 0))
+		},
+		rs.lowerChangedPublisher.Event()))
+
+	rs.MustRegisterProperty("Upper", NewProperty(
+		func() interface{} {
+			return rs.Upper()
+		},
+		func(v interface{}) error {
+			return rs.SetUpper(assertIntOr(v, 0))
+		},
+		rs.upperChangedPublisher.Event()))
+
+	succeeded = true
+
+	return rs, nil
+}
+
+// Orientation returns the orientation of the RangeSlider.
+func (rs *RangeSlider) Orientation() Orientation {
+	return rs.orientation
+}
+
+func (rs *RangeSlider) MinValue() int {
+	return rs.minValue
+}
+
+func (rs *RangeSlider) MaxValue() int {
+	return rs.maxValue
+}
+
+// SetRange sets the minimum and maximum value. The selected range is clamped
+// to the new bounds.
+func (rs *RangeSlider) SetRange(min, max int) error {
+	if min > max {
+		return newError("min must <= max")
+	}
+
+	rs.minValue, rs.maxValue = min, max
+
+	rs.Invalidate()
+
+	return rs.SetValues(rs.lower, rs.upper)
+}
+
+// Lower returns the lower end of the selected range.
+func (rs *RangeSlider) Lower() int {
+	return rs.lower
+}
+
+// SetLower sets the lower end of the selected range. If it exceeds the upper
+// end, the upper end is moved along.
+func (rs *RangeSlider) SetLower(value int) error {
+	return rs.setValues(value, maxi(value, rs.upper))
+}
+
+// Upper returns the upper end of the selected range.
+func (rs *RangeSlider) Upper() int {
+	return rs.upper
+}
+
+// SetUpper sets the upper end of the selected range. If it falls below the
+// lower end, the lower end is moved along.
+func (rs *RangeSlider) SetUpper(value int) error {
+	return rs.setValues(mini(value, rs.lower), value)
+}
+
+// SetValues sets both ends of the selected range.
+func (rs *RangeSlider) SetValues(lower, upper int) error {
+	if lower > upper {
+		return newError("lower must <= upper")
+	}
+
+	return rs.setValues(lower, upper)
+}
+
+func (rs *RangeSlider) clamp(value int) int {
+	return maxi(rs.minValue, mini(rs.maxValue, value))
+}
+
+func (rs *RangeSlider) setValues(lower, upper int) error {
+	lower, upper = rs.clamp(lower), rs.clamp(upper)
+
+	lowerChanged, upperChanged := lower != rs.lower, upper != rs.upper
+	if !lowerChanged && !upperChanged {
+		return nil
+	}
+
+	rs.lower, rs.upper = lower, upper
+
+	rs.Invalidate()
+
+	if lowerChanged {
+		rs.lowerChangedPublisher.Publish()
+	}
+	if upperChanged {
+		rs.upperChangedPublisher.Publish()
+	}
+
+	rs.rangeChangedPublisher.Publish(lower, upper)
+
+	return nil
+}
+
+// RangeChanged returns the event that is published with the new lower and
+// upper end when the selected range changed.
+func (rs *RangeSlider) RangeChanged() *IntRangeEvent {
+	return rs.rangeChangedPublisher.Event()
+}
+
+// LowerChanged returns an Event that can be used to track changes to Lower.
+func (rs *RangeSlider) LowerChanged() *Event {
+	return rs.lowerChangedPublisher.Event()
+}
+
+// UpperChanged returns an Event that can be used to track changes to Upper.
+func (rs *RangeSlider) UpperChanged() *Event {
+	return rs.upperChangedPublisher.Event()
+}
+
+func (rs *RangeSlider) LineSize() int {
+	return rs.lineSize
+}
+
+func (rs *RangeSlider) SetLineSize(lineSize int) {
+	rs.lineSize = maxi(1, lineSize)
+}
+
+func (rs *RangeSlider) PageSize() int {
+	return rs.pageSize
+}
+
+func (rs *RangeSlider) SetPageSize(pageSize int) {
+	rs.pageSize = maxi(1, pageSize)
+}
+
+// TickFrequency returns the distance in values between two tick marks, 0
+// meaning no tick marks.
+func (rs *RangeSlider) TickFrequency() int {
+	return rs.tickFrequency
+}
+
+// SetTickFrequency sets the distance in values between two tick marks, 0
+// meaning no tick marks.
+func (rs *RangeSlider) SetTickFrequency(frequency int) {
+	rs.tickFrequency = maxi(0, frequency)
+
+	rs.Invalidate()
+}
+
+// WheelValueMode returns whether the mouse wheel changes Lower and Upper.
+func (rs *RangeSlider) WheelValueMode() WheelValueMode {
+	return rs.wheelValueMode
+}
+
+// SetWheelValueMode sets whether the mouse wheel changes Lower and Upper.
+// WheelValueDefault uses App().WheelValueMode().
+func (rs *RangeSlider) SetWheelValueMode(mode WheelValueMode) {
+	rs.wheelValueMode = mode
+	rs.wheelDelta = 0
+}
+
+func (rs *RangeSlider) Persistent() bool {
+	return rs.persistent
+}
+
+func (rs *RangeSlider) SetPersistent(value bool) {
+	rs.persistent = value
+}
+
+func (rs *RangeSlider) SaveState() error {
+	return rs.WriteState(fmt.Sprintf("%d %d", rs.lower, rs.upper))
+}
+
+func (rs *RangeSlider) RestoreState() error {
+	s, err := rs.ReadState()
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+
+	var lower, upper int
+	if _, err := fmt.Sscanf(s, "%d %d", &lower, &upper); err != nil {
+		return err
+	}
+
+	return rs.SetValues(lower, upper)
+}
+
+// thumbSize returns the size of a thumb in native pixels, along and across
+// the track.
+func (rs *RangeSlider) thumbSize() (along, across int) {
+	dpi := rs.DPI()
+
+	return IntFrom96DPI(10, dpi), IntFrom96DPI(18, dpi)
+}
+
+// trackExtent returns the start and end of the track along the orientation in
+// native pixels.
+func (rs *RangeSlider) trackExtent() (start, end int) {
+	along, _ := rs.thumbSize()
+	bounds := rs.ClientBoundsPixels()
+
+	length := bounds.Width
+	if rs.orientation == Vertical {
+		length = bounds.Height
+	}
+
+	return along / 2, maxi(along/2, length-along/2)
+}
+
+func (rs *RangeSlider) posForValue(value int) int {
+	start, end := rs.trackExtent()
+	if rs.maxValue == rs.minValue {
+		return start
+	}
+
+	return start + int(int64(value-rs.minValue)*int64(end-start)/int64(rs.maxValue-rs.minValue))
+}
+
+func (rs *RangeSlider) valueForPos(pos int) int {
+	start, end := rs.trackExtent()
+	if end == start {
+		return rs.minValue
+	}
+
+	span := int64(rs.maxValue - rs.minValue)
+	offset := int64(pos - start)
+
+	// Round to the nearest value.
+	return rs.clamp(rs.minValue + int((offset*span*2+int64(end-start))/(int64(end-start)*2)))
+}
+
+func (rs *RangeSlider) posFromLParam(lParam uintptr) int {
+	if rs.orientation == Vertical {
+		return int(win.GET_Y_LPARAM(lParam))
+	}
+
+	return int(win.GET_X_LPARAM(lParam))
+}
+
+func (rs *RangeSlider) thumbAt(pos int) rangeSliderThumb {
+	along, _ := rs.thumbSize()
+	half := along / 2
+
+	lowerPos, upperPos := rs.posForValue(rs.lower), rs.posForValue(rs.upper)
+
+	onLower := pos >= lowerPos-half && pos <= lowerPos+half
+	onUpper := pos >= upperPos-half && pos <= upperPos+half
+
+	switch {
+	case onLower && onUpper:
+		// Overlapping thumbs: prefer the one that can move towards pos.
+		if pos < lowerPos || (pos == lowerPos && rs.upper == rs.maxValue) {
+			return rangeSliderLowerThumb
+		}
+		return rangeSliderUpperThumb
+
+	case onLower:
+		return rangeSliderLowerThumb
+
+	case onUpper:
+		return rangeSliderUpperThumb
+
+	case pos > lowerPos && pos < upperPos:
+		return rangeSliderBothThumbs
+	}
+
+	return rangeSliderNoThumb
+}
+
+func (rs *RangeSlider) setActiveThumb(thumb rangeSliderThumb) {
+	if thumb != rs.activeThumb {
+		rs.activeThumb = thumb
+		rs.Invalidate()
+	}
+}
+
+func (rs *RangeSlider) moveBy(delta int) {
+	if ShiftDown() {
+		rs.moveRangeBy(delta)
+		return
+	}
+
+	if rs.activeThumb == rangeSliderLowerThumb {
+		rs.setValues(rs.lower+delta, maxi(rs.lower+delta, rs.upper))
+	} else {
+		rs.setValues(mini(rs.upper+delta, rs.lower), rs.upper+delta)
+	}
+}
+
+// moveRangeBy moves the selected range by delta, keeping its width.
+func (rs *RangeSlider) moveRangeBy(delta int) {
+	if rs.lower+delta < rs.minValue {
+		delta = rs.minValue - rs.lower
+	}
+	if rs.upper+delta > rs.maxValue {
+		delta = rs.maxValue - rs.upper
+	}
+
+	rs.setValues(rs.lower+delta, rs.upper+delta)
+}
+
+func (rs *RangeSlider) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_SETFOCUS, win.WM_KILLFOCUS:
+		rs.Invalidate()
+
+	case win.WM_LBUTTONDOWN:
+		if !rs.Enabled() {
+			break
+		}
+
+		rs.SetFocus()
+
+		pos := rs.posFromLParam(lParam)
+
+		thumb := rs.thumbAt(pos)
+		if thumb == rangeSliderNoThumb {
+			if pos < rs.posForValue(rs.lower) {
+				rs.setActiveThumb(rangeSliderLowerThumb)
+				rs.setValues(rs.lower-rs.pageSize, rs.upper)
+			} else {
+				rs.setActiveThumb(rangeSliderUpperThumb)
+				rs.setValues(rs.lower, rs.upper+rs.pageSize)
+			}
+			break
+		}
+
+		if thumb != rangeSliderBothThumbs {
+			rs.setActiveThumb(thumb)
+		}
+
+		rs.dragThumb = thumb
+		rs.dragOrigin = pos
+		rs.dragLower, rs.dragUpper = rs.lower, rs.upper
+		win.SetCapture(hwnd)
+
+	case win.WM_MOUSEMOVE:
+		if rs.dragThumb == rangeSliderNoThumb {
+			break
+		}
+
+		pos := rs.posFromLParam(lParam)
+		value := rs.valueForPos(pos)
+
+		switch rs.dragThumb {
+		case rangeSliderLowerThumb:
+			rs.setValues(mini(value, rs.upper), rs.upper)
+
+		case rangeSliderUpperThumb:
+			rs.setValues(rs.lower, maxi(value, rs.lower))
+
+		case rangeSliderBothThumbs:
+			delta := value - rs.valueForPos(rs.dragOrigin)
+			delta = maxi(delta, rs.minValue-rs.dragLower)
+			delta = mini(delta, rs.maxValue-rs.dragUpper)
+
+			rs.setValues(rs.dragLower+delta, rs.dragUpper+delta)
+		}
+
+	case win.WM_LBUTTONUP:
+		if rs.dragThumb != rangeSliderNoThumb {
+			rs.dragThumb = rangeSliderNoThumb
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		rs.dragThumb = rangeSliderNoThumb
+
+	case win.WM_MOUSEWHEEL:
+		if !rs.Enabled() || !wheelChangesValue(rs.wheelValueMode, rs.Focused()) {
+			break
+		}
+
+		// Like Slider, move towards MinValue when rotated forward.
+		if notches := wheelNotches(&rs.wheelDelta, wParam); notches != 0 {
+			rs.moveBy(-notches * rs.lineSize)
+		}
+		return 0
+
+	case win.WM_KEYDOWN:
+		if !rs.Enabled() {
+			break
+		}
+
+		switch Key(wParam) {
+		case KeyRight, KeyDown:
+			rs.moveBy(rs.lineSize)
+
+		case KeyLeft, KeyUp:
+			rs.moveBy(-rs.lineSize)
+
+		case KeyNext:
+			rs.moveBy(rs.pageSize)
+
+		case KeyPrior:
+			rs.moveBy(-rs.pageSize)
+
+		case KeyHome:
+			rs.moveBy(rs.minValue - rs.maxValue)
+
+		case KeyEnd:
+			rs.moveBy(rs.maxValue - rs.minValue)
+		}
+	}
+
+	return rs.CustomWidget.WndProc(hwnd, msg, wParam, lParam)
+}
+
+// rect returns a Rectangle from coordinates along and across the track.
+func (rs *RangeSlider) rect(along, across, alongLength, acrossLength int) Rectangle {
+	if rs.orientation == Vertical {
+		return Rectangle{across, along, acrossLength, alongLength}
+	}
+
+	return Rectangle{along, across, alongLength, acrossLength}
+}
+
+func (rs *RangeSlider) paint(canvas *Canvas) error {
+	bounds := rs.ClientBoundsPixels()
+	dpi := rs.DPI()
+
+	thickness := bounds.Height
+	if rs.orientation == Vertical {
+		thickness = bounds.Width
+	}
+
+	thumbAlong, thumbAcross := rs.thumbSize()
+	thumbAcross = mini(thumbAcross, thickness)
+
+	tickLength := 0
+	if rs.tickFrequency > 0 {
+		tickLength = IntFrom96DPI(4, dpi)
+	}
+
+	across := maxi(0, (thickness-thumbAcross-tickLength)/2)
+	trackThickness := IntFrom96DPI(4, dpi)
+	trackAcross := across + (thumbAcross-trackThickness)/2
+
+	start, end := rs.trackExtent()
+
+	shadowColor := Color(win.GetSysColor(win.COLOR_BTNSHADOW))
+	rangeColor := Color(win.GetSysColor(win.COLOR_HIGHLIGHT))
+	thumbColor := Color(win.GetSysColor(win.COLOR_BTNFACE))
+	if !rs.Enabled() {
+		rangeColor = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	shadowPen, err := NewCosmeticPen(PenSolid, shadowColor)
+	if err != nil {
+		return err
+	}
+	defer shadowPen.Dispose()
+
+	trackBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_3DLIGHT)))
+	if err != nil {
+		return err
+	}
+	defer trackBrush.Dispose()
+
+	rangeBrush, err := NewSolidColorBrush(rangeColor)
+	if err != nil {
+		return err
+	}
+	defer rangeBrush.Dispose()
+
+	thumbBrush, err := NewSolidColorBrush(thumbColor)
+	if err != nil {
+		return err
+	}
+	defer thumbBrush.Dispose()
+
+	track := rs.rect(start, trackAcross, end-start, trackThickness)
+	if err := canvas.FillRectanglePixels(trackBrush, track); err != nil {
+		return err
+	}
+	if err := canvas.DrawRectanglePixels(shadowPen, track); err != nil {
+		return err
+	}
+
+	lowerPos, upperPos := rs.posForValue(rs.lower), rs.posForValue(rs.upper)
+
+	if err := canvas.FillRectanglePixels(rangeBrush, rs.rect(lowerPos, trackAcross, upperPos-lowerPos, trackThickness)); err != nil {
+		return err
+	}
+
+	if rs.tickFrequency > 0 {
+		tickAcross := across + thumbAcross + IntFrom96DPI(1, dpi)
+
+		for v := rs.minValue; ; v += rs.tickFrequency {
+			if v > rs.maxValue {
+				v = rs.maxValue
+			}
+
+			p := rs.posForValue(v)
+
+			var from, to Point
+			if rs.orientation == Vertical {
+				from, to = Point{tickAcross, p}, Point{tickAcross + tickLength, p}
+			} else {
+				from, to = Point{p, tickAcross}, Point{p, tickAcross + tickLength}
+			}
+
+			if err := canvas.DrawLinePixels(shadowPen, from, to); err != nil {
+				return err
+			}
+
+			if v == rs.maxValue {
+				break
+			}
+		}
+	}
+
+	activePen, err := NewCosmeticPen(PenSolid, rangeColor)
+	if err != nil {
+		return err
+	}
+	defer activePen.Dispose()
+
+	corner := SizeFrom96DPI(Size{3, 3}, dpi)
+
+	for _, thumb := range []rangeSliderThumb{rangeSliderLowerThumb, rangeSliderUpperThumb} {
+		pos := lowerPos
+		if thumb == rangeSliderUpperThumb {
+			pos = upperPos
+		}
+
+		b := rs.rect(pos-thumbAlong/2, across, thumbAlong, thumbAcross)
+
+		if err := canvas.FillRoundedRectanglePixels(thumbBrush, b, corner); err != nil {
+			return err
+		}
+
+		pen := shadowPen
+		if thumb == rs.activeThumb && rs.Focused() {
+			pen = activePen
+		}
+
+		if err := canvas.DrawRoundedRectanglePixels(pen, b, corner); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rs *RangeSlider) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	flags := ShrinkableHorz | GrowableHorz
+	size := SizeFrom96DPI(Size{60, 26}, ctx.dpi)
+	if rs.orientation == Vertical {
+		flags = ShrinkableVert | GrowableVert
+		size = Size{size.Height, size.Width}
+	}
+
+	return &sliderLayoutItem{
+		layoutFlags: flags,
+		idealSize:   size,
+	}
+}
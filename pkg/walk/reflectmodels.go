@@ -210,6 +210,10 @@ func (m *reflectTableModel) Value(row, col int) interface{} {
 	return valueFromSlice(m.dataSource, m.value, m.dataMembers[col], row)
 }
 
+func (m *reflectTableModel) rowItem(row int) interface{} {
+	return m.value.Index(row).Interface()
+}
+
 func (m *reflectTableModel) Checked(row int) bool {
 	if m.value.Index(row).IsNil() {
 		return false
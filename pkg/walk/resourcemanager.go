@@ -11,13 +11,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
+	"strings"
 )
 
 func init() {
 	Resources.rootDirPath, _ = os.Getwd()
 	Resources.bitmaps = make(map[string]*Bitmap)
 	Resources.icons = make(map[string]*Icon)
+	Resources.imagePrefetches = make(map[string]*imagePrefetch)
 }
 
 // Resources is the singleton instance of ResourceManager.
@@ -27,9 +30,18 @@ var Resources ResourceManager
 // The resources can be either embedded in the running executable
 // file or located below a specified root directory in the file system.
 type ResourceManager struct {
-	rootDirPath string
-	bitmaps     map[string]*Bitmap
-	icons       map[string]*Icon
+	rootDirPath     string
+	bitmaps         map[string]*Bitmap
+	icons           map[string]*Icon
+	imagePrefetches map[string]*imagePrefetch
+}
+
+// imagePrefetch is an image file being loaded in the background. Its
+// fields must only be read after done has been closed.
+type imagePrefetch struct {
+	done   chan struct{}
+	bitmap *Bitmap
+	icon   *Icon
 }
 
 // RootDirPath returns the root directory path where resources are to be loaded from.
@@ -60,6 +72,8 @@ func (rm *ResourceManager) Bitmap(name string) (*Bitmap, error) {
 // BitmapForDPI loads a bitmap from file or resource identified by name, or an error if it could
 // not be found. When bitmap is loaded, given DPI is assumed.
 func (rm *ResourceManager) BitmapForDPI(name string, dpi int) (*Bitmap, error) {
+	rm.takeImagePrefetch(name)
+
 	if bm := rm.bitmaps[name]; bm != nil {
 		return bm, nil
 	}
@@ -86,6 +100,8 @@ func (rm *ResourceManager) BitmapForDPI(name string, dpi int) (*Bitmap, error) {
 
 // Icon returns the Icon identified by name, or an error if it could not be found.
 func (rm *ResourceManager) Icon(name string) (*Icon, error) {
+	rm.takeImagePrefetch(name)
+
 	if icon := rm.icons[name]; icon != nil {
 		return icon, nil
 	}
@@ -123,6 +139,68 @@ func (rm *ResourceManager) Image(name string) (Image, error) {
 	return nil, rm.notFoundErr("image", name)
 }
 
+// PrefetchImages starts loading the image files identified by names in the
+// background, so Bitmap, Icon and Image don't have to wait for them to be
+// decoded later. Files ending in .ico are loaded as icons, others as bitmaps
+// at 96dpi. See PrefetchFont for fonts.
+//
+// Names that are loaded already or that don't identify a file below the root
+// directory are ignored. Like the other methods, PrefetchImages must be
+// called from the UI thread.
+func (rm *ResourceManager) PrefetchImages(names ...string) {
+	// GDI objects aren't bound to the thread that created them, so only the
+	// bookkeeping has to stay on the UI thread.
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	for _, name := range names {
+		if rm.bitmaps[name] != nil || rm.icons[name] != nil || rm.imagePrefetches[name] != nil {
+			continue
+		}
+
+		path := filepath.Join(rm.rootDirPath, name)
+		if fi, err := os.Stat(path); err != nil || fi.IsDir() {
+			continue
+		}
+
+		rp := &imagePrefetch{done: make(chan struct{})}
+		rm.imagePrefetches[name] = rp
+
+		go func() {
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+				close(rp.done)
+			}()
+
+			if strings.HasSuffix(strings.ToLower(path), ".ico") {
+				rp.icon, _ = NewIconFromFile(path)
+			} else {
+				rp.bitmap, _ = NewBitmapFromFileForDPI(path, 96)
+			}
+		}()
+	}
+}
+
+// takeImagePrefetch waits for a pending prefetch of name, if any, and moves its
+// result into the cache.
+func (rm *ResourceManager) takeImagePrefetch(name string) {
+	rp := rm.imagePrefetches[name]
+	if rp == nil {
+		return
+	}
+
+	<-rp.done
+
+	delete(rm.imagePrefetches, name)
+
+	if rp.bitmap != nil {
+		rm.bitmaps[name] = rp.bitmap
+	}
+	if rp.icon != nil {
+		rm.icons[name] = rp.icon
+	}
+}
+
 func (rm *ResourceManager) notFoundErr(typ, name string) error {
 	path := filepath.Clean(filepath.Join(rm.rootDirPath, name))
 
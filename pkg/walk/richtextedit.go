@@ -0,0 +1,840 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+var richEditStreamCallbackPtr uintptr
+
+func init() {
+	AppendToWalkInit(func() {
+		richEditStreamCallbackPtr = syscall.NewCallback(richEditStreamCallback)
+	})
+}
+
+// richEditStream is the Go side of an EM_STREAMIN or EM_STREAMOUT in progress.
+type richEditStream struct {
+	r   io.Reader
+	w   io.Writer
+	err error
+}
+
+var (
+	richEditStreams      = make(map[uintptr]*richEditStream)
+	richEditStreamCookie uintptr
+)
+
+func richEditStreamCallback(cookie uintptr, buf *byte, cb int32, pcb *int32) uintptr {
+	s := richEditStreams[cookie]
+	if s == nil || cb <= 0 {
+		*pcb = 0
+		return 0
+	}
+
+	b := unsafe.Slice(buf, cb)
+
+	var n int
+	if s.r != nil {
+		n, s.err = io.ReadFull(s.r, b)
+		if s.err == io.EOF || s.err == io.ErrUnexpectedEOF {
+			s.err = nil
+		}
+	} else {
+		n, s.err = s.w.Write(b)
+	}
+
+	*pcb = int32(n)
+
+	if s.err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+// TextRange is a range of the text of a RichTextEdit.
+//
+// Positions count UTF-16 code units, with paragraph breaks counted as a
+// single unit. An End of -1 extends the range to the end of the text.
+type TextRange struct {
+	Start int
+	End   int
+}
+
+// Len returns the number of code units in the TextRange, if it doesn't extend
+// to the end of the text.
+func (r TextRange) Len() int {
+	return r.End - r.Start
+}
+
+func (r TextRange) charRange() win.CHARRANGE {
+	return win.CHARRANGE{CpMin: int32(r.Start), CpMax: int32(r.End)}
+}
+
+// TextCharFormatMask selects attributes of a TextCharFormat.
+type TextCharFormatMask uint32
+
+const (
+	TextCharFormatBold TextCharFormatMask = 1 << iota
+	TextCharFormatItalic
+	TextCharFormatUnderline
+	TextCharFormatStrikeout
+	TextCharFormatTextColor
+	TextCharFormatBackColor
+	TextCharFormatFontFamily
+	TextCharFormatPointSize
+)
+
+// TextCharFormat holds character formatting attributes of a range of text.
+//
+// Only the attributes selected by Mask are applied. When retrieved, Mask
+// selects the attributes that are the same for the whole range.
+type TextCharFormat struct {
+	Mask       TextCharFormatMask
+	Bold       bool
+	Italic     bool
+	Underline  bool
+	Strikeout  bool
+	TextColor  Color
+	BackColor  Color
+	FontFamily string
+	PointSize  int
+}
+
+var textCharFormatMasks = [...]struct {
+	mask   TextCharFormatMask
+	cfm    uint32
+	effect uint32
+}{
+	{TextCharFormatBold, win.CFM_BOLD, win.CFE_BOLD},
+	{TextCharFormatItalic, win.CFM_ITALIC, win.CFE_ITALIC},
+	{TextCharFormatUnderline, win.CFM_UNDERLINE, win.CFE_UNDERLINE},
+	{TextCharFormatStrikeout, win.CFM_STRIKEOUT, win.CFE_STRIKEOUT},
+	{TextCharFormatTextColor, win.CFM_COLOR, 0},
+	{TextCharFormatBackColor, win.CFM_BACKCOLOR, 0},
+	{TextCharFormatFontFamily, win.CFM_FACE, 0},
+	{TextCharFormatPointSize, win.CFM_SIZE, 0},
+}
+
+func (f *TextCharFormat) toCHARFORMAT2() *win.CHARFORMAT2 {
+	cf := new(win.CHARFORMAT2)
+	cf.CbSize = uint32(unsafe.Sizeof(*cf))
+
+	flags := [...]bool{f.Bold, f.Italic, f.Underline, f.Strikeout}
+
+	for i, m := range textCharFormatMasks {
+		if f.Mask&m.mask == 0 {
+			continue
+		}
+
+		cf.DwMask |= m.cfm
+
+		if i < len(flags) && flags[i] {
+			cf.DwEffects |= m.effect
+		}
+	}
+
+	cf.CrTextColor = win.COLORREF(f.TextColor)
+	cf.CrBackColor = win.COLORREF(f.BackColor)
+	cf.YHeight = int32(f.PointSize * 20)
+
+	if name, err := syscall.UTF16FromString(f.FontFamily); err == nil {
+		copy(cf.SzFaceName[:win.LF_FACESIZE-1], name)
+	}
+
+	return cf
+}
+
+func textCharFormatFromCHARFORMAT2(cf *win.CHARFORMAT2) TextCharFormat {
+	var f TextCharFormat
+
+	flags := [...]*bool{&f.Bold, &f.Italic, &f.Underline, &f.Strikeout}
+
+	for i, m := range textCharFormatMasks {
+		if cf.DwMask&m.cfm == 0 {
+			continue
+		}
+
+		f.Mask |= m.mask
+
+		if i < len(flags) {
+			*flags[i] = cf.DwEffects&m.effect != 0
+		}
+	}
+
+	if cf.DwEffects&win.CFE_AUTOCOLOR != 0 {
+		f.TextColor = Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+	} else {
+		f.TextColor = Color(cf.CrTextColor)
+	}
+	if cf.DwEffects&win.CFE_AUTOBACKCOLOR != 0 {
+		f.BackColor = Color(win.GetSysColor(win.COLOR_WINDOW))
+	} else {
+		f.BackColor = Color(cf.CrBackColor)
+	}
+	f.PointSize = int(cf.YHeight) / 20
+	f.FontFamily = syscall.UTF16ToString(cf.SzFaceName[:])
+
+	return f
+}
+
+// ParagraphAlignment specifies the horizontal alignment of a paragraph.
+type ParagraphAlignment int
+
+const (
+	ParagraphAlignLeft ParagraphAlignment = iota
+	ParagraphAlignCenter
+	ParagraphAlignRight
+	ParagraphAlignJustify
+)
+
+// TextParagraphFormatMask selects attributes of a TextParagraphFormat.
+type TextParagraphFormatMask uint32
+
+const (
+	TextParagraphFormatAlignment TextParagraphFormatMask = 1 << iota
+	TextParagraphFormatBullet
+	TextParagraphFormatIndents
+	TextParagraphFormatSpacing
+)
+
+// TextParagraphFormat holds paragraph formatting attributes of the
+// paragraphs a range of text touches.
+//
+// Only the attributes selected by Mask are applied. When retrieved, Mask
+// selects the attributes that are the same for all paragraphs. Indents and
+// spacing are in points.
+type TextParagraphFormat struct {
+	Mask            TextParagraphFormatMask
+	Alignment       ParagraphAlignment
+	Bullet          bool
+	StartIndent     int
+	RightIndent     int
+	FirstLineOffset int // relative to StartIndent
+	SpaceBefore     int
+	SpaceAfter      int
+}
+
+var paragraphAlignments = [...]uint16{win.PFA_LEFT, win.PFA_CENTER, win.PFA_RIGHT, win.PFA_JUSTIFY}
+
+func (f *TextParagraphFormat) toPARAFORMAT2() *win.PARAFORMAT2 {
+	pf := new(win.PARAFORMAT2)
+	pf.CbSize = uint32(unsafe.Sizeof(*pf))
+
+	if f.Mask&TextParagraphFormatAlignment != 0 {
+		pf.DwMask |= win.PFM_ALIGNMENT
+		if f.Alignment >= 0 && int(f.Alignment) < len(paragraphAlignments) {
+			pf.WAlignment = paragraphAlignments[f.Alignment]
+		}
+	}
+
+	if f.Mask&TextParagraphFormatBullet != 0 {
+		pf.DwMask |= win.PFM_NUMBERING
+		if f.Bullet {
+			pf.WNumbering = win.PFN_BULLET
+		}
+	}
+
+	if f.Mask&TextParagraphFormatIndents != 0 {
+		// RichEdit indents the first line by DxStartIndent and the others by
+		// DxStartIndent+DxOffset.
+		pf.DwMask |= win.PFM_STARTINDENT | win.PFM_RIGHTINDENT | win.PFM_OFFSET
+		pf.DxStartIndent = int32((f.StartIndent + f.FirstLineOffset) * 20)
+		pf.DxOffset = int32(-f.FirstLineOffset * 20)
+		pf.DxRightIndent = int32(f.RightIndent * 20)
+	}
+
+	if f.Mask&TextParagraphFormatSpacing != 0 {
+		pf.DwMask |= win.PFM_SPACEBEFORE | win.PFM_SPACEAFTER
+		pf.DySpaceBefore = int32(f.SpaceBefore * 20)
+		pf.DySpaceAfter = int32(f.SpaceAfter * 20)
+	}
+
+	return pf
+}
+
+func textParagraphFormatFromPARAFORMAT2(pf *win.PARAFORMAT2) TextParagraphFormat {
+	var f TextParagraphFormat
+
+	if pf.DwMask&win.PFM_ALIGNMENT != 0 {
+		f.Mask |= TextParagraphFormatAlignment
+		for i, a := range paragraphAlignments {
+			if a == pf.WAlignment {
+				f.Alignment = ParagraphAlignment(i)
+			}
+		}
+	}
+
+	if pf.DwMask&win.PFM_NUMBERING != 0 {
+		f.Mask |= TextParagraphFormatBullet
+		f.Bullet = pf.WNumbering == win.PFN_BULLET
+	}
+
+	const indents = win.PFM_STARTINDENT | win.PFM_RIGHTINDENT | win.PFM_OFFSET
+	if pf.DwMask&indents == indents {
+		f.Mask |= TextParagraphFormatIndents
+		f.FirstLineOffset = int(-pf.DxOffset) / 20
+		f.StartIndent = int(pf.DxStartIndent)/20 - f.FirstLineOffset
+		f.RightIndent = int(pf.DxRightIndent) / 20
+	}
+
+	const spacing = win.PFM_SPACEBEFORE | win.PFM_SPACEAFTER
+	if pf.DwMask&spacing == spacing {
+		f.Mask |= TextParagraphFormatSpacing
+		f.SpaceBefore = int(pf.DySpaceBefore) / 20
+		f.SpaceAfter = int(pf.DySpaceAfter) / 20
+	}
+
+	return f
+}
+
+// RichTextEdit is a multi line text edit backed by the RichEdit control,
+// whose text can be formatted per range of characters and paragraphs and be
+// loaded and saved as RTF.
+type RichTextEdit struct {
+	WidgetBase
+	readOnlyChangedPublisher  EventPublisher
+	textChangedPublisher      EventPublisher
+	selectionChangedPublisher EventPublisher
+	finder                    *textFinder
+}
+
+// NewRichTextEdit creates and initializes a new RichTextEdit with word wrap.
+func NewRichTextEdit(parent Container) (*RichTextEdit, error) {
+	return NewRichTextEditWithStyle(parent, win.WS_VSCROLL)
+}
+
+// NewRichTextEditWithStyle creates and initializes a new RichTextEdit with
+// additional style bits, e.g. win.WS_VSCROLL or win.WS_HSCROLL. Without
+// win.WS_HSCROLL, lines are wrapped.
+func NewRichTextEditWithStyle(parent Container, style uint32) (*RichTextEdit, error) {
+	if err := loadMsftedit(); err != nil {
+		return nil, err
+	}
+
+	if style&win.WS_HSCROLL != 0 {
+		style |= win.ES_AUTOHSCROLL
+	}
+
+	rte := new(RichTextEdit)
+	rte.finder = newTextFinder(rte)
+
+	if err := InitWidget(
+		rte,
+		parent,
+		win.MSFTEDIT_CLASS,
+		win.WS_TABSTOP|win.WS_VISIBLE|win.ES_MULTILINE|win.ES_WANTRETURN|win.ES_AUTOVSCROLL|style,
+		win.WS_EX_CLIENTEDGE); err != nil {
+		return nil, err
+	}
+
+	if style&win.WS_HSCROLL != 0 {
+		// Disables word wrap.
+		rte.SendMessage(win.EM_SETTARGETDEVICE, 0, 1)
+	}
+
+	rte.SendMessage(win.EM_EXLIMITTEXT, 0, 0x7FFFFFFE)
+	rte.SendMessage(win.EM_SETEVENTMASK, 0, win.ENM_CHANGE|win.ENM_SELCHANGE)
+
+	rte.GraphicsEffects().Add(InteractionEffect)
+	rte.GraphicsEffects().Add(FocusEffect)
+
+	rte.MustRegisterProperty("ReadOnly", NewProperty(
+		func() interface{} {
+			return rte.ReadOnly()
+		},
+		func(v interface{}) error {
+			return rte.SetReadOnly(v.(bool))
+		},
+		rte.readOnlyChangedPublisher.Event()))
+
+	rte.MustRegisterProperty("Text", NewProperty(
+		func() interface{} {
+			return rte.Text()
+		},
+		func(v interface{}) error {
+			return rte.SetText(assertStringOr(v, ""))
+		},
+		rte.textChangedPublisher.Event()))
+
+	return rte, nil
+}
+
+// Text returns the plain text of the RichTextEdit.
+func (rte *RichTextEdit) Text() string {
+	return rte.text()
+}
+
+// SetText sets the plain text of the RichTextEdit, in the default format.
+func (rte *RichTextEdit) SetText(text string) error {
+	if text == rte.Text() {
+		return nil
+	}
+
+	return rte.setText(text)
+}
+
+// TextLength returns the number of UTF-16 code units of the text of the
+// RichTextEdit, with paragraph breaks counted as a single unit.
+func (rte *RichTextEdit) TextLength() int {
+	gtl := win.GETTEXTLENGTHEX{Flags: win.GTL_NUMCHARS | win.GTL_PRECISE, Codepage: 1200}
+	return int(rte.SendMessage(win.EM_GETTEXTLENGTHEX, uintptr(unsafe.Pointer(&gtl)), 0))
+}
+
+// TextInRange returns the plain text in r.
+func (rte *RichTextEdit) TextInRange(r TextRange) string {
+	return string(utf16.Decode(rte.utf16InRange(r)))
+}
+
+func (rte *RichTextEdit) utf16InRange(r TextRange) []uint16 {
+	if r.End < 0 {
+		r.End = rte.TextLength()
+	}
+	if r.End <= r.Start {
+		return nil
+	}
+
+	buf := make([]uint16, r.Len()+1)
+	tr := win.TEXTRANGE{Chrg: r.charRange(), LpstrText: &buf[0]}
+	n := int(rte.SendMessage(win.EM_GETTEXTRANGE, 0, uintptr(unsafe.Pointer(&tr))))
+
+	return buf[:n]
+}
+
+// TextChanged returns the event that is published when the text of the
+// RichTextEdit changed.
+func (rte *RichTextEdit) TextChanged() *Event {
+	return rte.textChangedPublisher.Event()
+}
+
+// ReadOnly returns whether the RichTextEdit is in read-only mode.
+func (rte *RichTextEdit) ReadOnly() bool {
+	return rte.hasStyleBits(win.ES_READONLY)
+}
+
+// SetReadOnly sets whether the RichTextEdit is in read-only mode.
+func (rte *RichTextEdit) SetReadOnly(readOnly bool) error {
+	if readOnly == rte.ReadOnly() {
+		return nil
+	}
+
+	if 0 == rte.SendMessage(win.EM_SETREADONLY, uintptr(win.BoolToBOOL(readOnly)), 0) {
+		return newError("SendMessage(EM_SETREADONLY)")
+	}
+
+	rte.readOnlyChangedPublisher.Publish()
+
+	return nil
+}
+
+// Selection returns the range of the selected text.
+func (rte *RichTextEdit) Selection() TextRange {
+	var cr win.CHARRANGE
+	rte.SendMessage(win.EM_EXGETSEL, 0, uintptr(unsafe.Pointer(&cr)))
+
+	return TextRange{int(cr.CpMin), int(cr.CpMax)}
+}
+
+// SetSelection selects the text in r.
+func (rte *RichTextEdit) SetSelection(r TextRange) {
+	cr := r.charRange()
+	rte.SendMessage(win.EM_EXSETSEL, 0, uintptr(unsafe.Pointer(&cr)))
+}
+
+// SelectionChanged returns the event that is published when the selection or
+// caret of the RichTextEdit moved.
+func (rte *RichTextEdit) SelectionChanged() *Event {
+	return rte.selectionChangedPublisher.Event()
+}
+
+// ReplaceSelectedText replaces the selected text with text, in the format of
+// the selection.
+func (rte *RichTextEdit) ReplaceSelectedText(text string, canUndo bool) {
+	rte.SendMessage(win.EM_REPLACESEL,
+		uintptr(win.BoolToBOOL(canUndo)),
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(text))))
+}
+
+// ReplaceRange replaces the text in r with text, in the format of the start
+// of r.
+func (rte *RichTextEdit) ReplaceRange(r TextRange, text string, canUndo bool) {
+	rte.withRange(r, func() {
+		rte.ReplaceSelectedText(text, canUndo)
+	})
+}
+
+// ScrollToCaret scrolls the caret into view.
+func (rte *RichTextEdit) ScrollToCaret() {
+	rte.SendMessage(win.EM_SCROLLCARET, 0, 0)
+}
+
+// CanUndo returns whether there is an edit to undo.
+func (rte *RichTextEdit) CanUndo() bool {
+	return rte.SendMessage(win.EM_CANUNDO, 0, 0) != 0
+}
+
+// Undo undoes the last edit.
+func (rte *RichTextEdit) Undo() {
+	rte.SendMessage(win.EM_UNDO, 0, 0)
+}
+
+// CanRedo returns whether there is an undone edit to redo.
+func (rte *RichTextEdit) CanRedo() bool {
+	return rte.SendMessage(win.EM_CANREDO, 0, 0) != 0
+}
+
+// Redo redoes the last undone edit.
+func (rte *RichTextEdit) Redo() {
+	rte.SendMessage(win.EM_REDO, 0, 0)
+}
+
+// CharFormat returns the character format of the text in r.
+func (rte *RichTextEdit) CharFormat(r TextRange) TextCharFormat {
+	cf := win.CHARFORMAT2{}
+	cf.CbSize = uint32(unsafe.Sizeof(cf))
+
+	rte.withRange(r, func() {
+		rte.SendMessage(win.EM_GETCHARFORMAT, win.SCF_SELECTION, uintptr(unsafe.Pointer(&cf)))
+	})
+
+	return textCharFormatFromCHARFORMAT2(&cf)
+}
+
+// SetCharFormat applies the attributes selected by the mask of format to the
+// text in r.
+func (rte *RichTextEdit) SetCharFormat(r TextRange, format TextCharFormat) error {
+	cf := format.toCHARFORMAT2()
+
+	var ret uintptr
+	rte.withRange(r, func() {
+		ret = rte.SendMessage(win.EM_SETCHARFORMAT, win.SCF_SELECTION, uintptr(unsafe.Pointer(cf)))
+	})
+
+	if ret == 0 {
+		return newError("SendMessage(EM_SETCHARFORMAT)")
+	}
+
+	return nil
+}
+
+// SetRangeBold sets whether the text in r is bold.
+func (rte *RichTextEdit) SetRangeBold(r TextRange, bold bool) error {
+	return rte.SetCharFormat(r, TextCharFormat{Mask: TextCharFormatBold, Bold: bold})
+}
+
+// SetRangeItalic sets whether the text in r is italic.
+func (rte *RichTextEdit) SetRangeItalic(r TextRange, italic bool) error {
+	return rte.SetCharFormat(r, TextCharFormat{Mask: TextCharFormatItalic, Italic: italic})
+}
+
+// SetRangeTextColor sets the Color of the text in r.
+func (rte *RichTextEdit) SetRangeTextColor(r TextRange, c Color) error {
+	return rte.SetCharFormat(r, TextCharFormat{Mask: TextCharFormatTextColor, TextColor: c})
+}
+
+// SetRangePointSize sets the font size of the text in r.
+func (rte *RichTextEdit) SetRangePointSize(r TextRange, pointSize int) error {
+	return rte.SetCharFormat(r, TextCharFormat{Mask: TextCharFormatPointSize, PointSize: pointSize})
+}
+
+// ParagraphFormat returns the paragraph format of the paragraphs r touches.
+func (rte *RichTextEdit) ParagraphFormat(r TextRange) TextParagraphFormat {
+	pf := win.PARAFORMAT2{}
+	pf.CbSize = uint32(unsafe.Sizeof(pf))
+
+	rte.withRange(r, func() {
+		rte.SendMessage(win.EM_GETPARAFORMAT, 0, uintptr(unsafe.Pointer(&pf)))
+	})
+
+	return textParagraphFormatFromPARAFORMAT2(&pf)
+}
+
+// SetParagraphFormat applies the attributes selected by the mask of format to
+// the paragraphs r touches.
+func (rte *RichTextEdit) SetParagraphFormat(r TextRange, format TextParagraphFormat) error {
+	pf := format.toPARAFORMAT2()
+
+	var ret uintptr
+	rte.withRange(r, func() {
+		ret = rte.SendMessage(win.EM_SETPARAFORMAT, 0, uintptr(unsafe.Pointer(pf)))
+	})
+
+	if ret == 0 {
+		return newError("SendMessage(EM_SETPARAFORMAT)")
+	}
+
+	return nil
+}
+
+// SetRangeAlignment sets the alignment of the paragraphs r touches.
+func (rte *RichTextEdit) SetRangeAlignment(r TextRange, alignment ParagraphAlignment) error {
+	return rte.SetParagraphFormat(r, TextParagraphFormat{Mask: TextParagraphFormatAlignment, Alignment: alignment})
+}
+
+// withRange calls f with r selected, without the change of the selection
+// being visible or notified.
+func (rte *RichTextEdit) withRange(r TextRange, f func()) {
+	sel := rte.Selection()
+	if sel == r {
+		f()
+		return
+	}
+
+	var scrollPos win.POINT
+	rte.SendMessage(win.EM_GETSCROLLPOS, 0, uintptr(unsafe.Pointer(&scrollPos)))
+
+	eventMask := rte.SendMessage(win.EM_SETEVENTMASK, 0, 0)
+	rte.SendMessage(win.EM_HIDESELECTION, 1, 0)
+
+	rte.SetSelection(r)
+	f()
+	rte.SetSelection(sel)
+
+	rte.SendMessage(win.EM_SETSCROLLPOS, 0, uintptr(unsafe.Pointer(&scrollPos)))
+	rte.SendMessage(win.EM_HIDESELECTION, 0, 0)
+	rte.SendMessage(win.EM_SETEVENTMASK, 0, eventMask)
+}
+
+// Find returns the ranges of all matches of text in the text of the
+// RichTextEdit.
+func (rte *RichTextEdit) Find(text string, opts FindOptions) []TextRange {
+	return rte.finder.find(text, opts)
+}
+
+// Search returns the text and options FindNext, FindPrevious and the
+// replace methods search for.
+func (rte *RichTextEdit) Search() (text string, opts FindOptions) {
+	return rte.finder.what, rte.finder.opts
+}
+
+// SetSearch sets the text and options FindNext, FindPrevious and the replace
+// methods search for.
+func (rte *RichTextEdit) SetSearch(text string, opts FindOptions) {
+	rte.finder.setSearch(text, opts)
+}
+
+// FindNext selects the next match of the search after the selection, wrapping
+// around at the end of the text, and returns whether there is one.
+//
+// F3 calls FindNext while the RichTextEdit has the focus.
+func (rte *RichTextEdit) FindNext() bool {
+	if !rte.finder.findNext(false) {
+		return false
+	}
+
+	rte.ScrollToCaret()
+
+	return true
+}
+
+// FindPrevious selects the previous match of the search before the selection,
+// wrapping around at the start of the text, and returns whether there is one.
+//
+// Shift+F3 calls FindPrevious while the RichTextEdit has the focus.
+func (rte *RichTextEdit) FindPrevious() bool {
+	if !rte.finder.findNext(true) {
+		return false
+	}
+
+	rte.ScrollToCaret()
+
+	return true
+}
+
+// ReplaceNext replaces the selection with replacement if it is a match of the
+// search, then selects the next match and returns whether there is one.
+func (rte *RichTextEdit) ReplaceNext(replacement string) bool {
+	if !rte.finder.replaceNext(replacement) {
+		return false
+	}
+
+	rte.ScrollToCaret()
+
+	return true
+}
+
+// ReplaceAll replaces all matches of the search with replacement and returns
+// their number.
+func (rte *RichTextEdit) ReplaceAll(replacement string) int {
+	return rte.finder.replaceAll(replacement)
+}
+
+// HighlightAllMatches returns whether all matches of the search are
+// highlighted.
+func (rte *RichTextEdit) HighlightAllMatches() bool {
+	return rte.finder.highlightAll
+}
+
+// SetHighlightAllMatches sets whether all matches of the search are
+// highlighted, by drawing a frame around them.
+func (rte *RichTextEdit) SetHighlightAllMatches(highlight bool) {
+	rte.finder.setHighlightAll(highlight)
+}
+
+// MatchHighlightColor returns the color of the frames around highlighted
+// matches.
+func (rte *RichTextEdit) MatchHighlightColor() Color {
+	return rte.finder.highlightColor
+}
+
+// SetMatchHighlightColor sets the color of the frames around highlighted
+// matches.
+func (rte *RichTextEdit) SetMatchHighlightColor(c Color) {
+	rte.finder.setHighlightColor(c)
+}
+
+// FindReplaceEnabled returns whether Ctrl+F and Ctrl+H show the find and
+// replace dialogs.
+func (rte *RichTextEdit) FindReplaceEnabled() bool {
+	return rte.finder.dialogEnabled
+}
+
+// SetFindReplaceEnabled sets whether Ctrl+F and Ctrl+H show the find and
+// replace dialogs. Ctrl+H is ignored while the RichTextEdit is read-only.
+func (rte *RichTextEdit) SetFindReplaceEnabled(enabled bool) {
+	rte.finder.dialogEnabled = enabled
+}
+
+// ShowFindDialog shows the modeless stock find dialog of Windows for the
+// RichTextEdit.
+func (rte *RichTextEdit) ShowFindDialog() error {
+	return rte.finder.showDialog(false)
+}
+
+// ShowReplaceDialog shows the modeless stock replace dialog of Windows for
+// the RichTextEdit.
+func (rte *RichTextEdit) ShowReplaceDialog() error {
+	return rte.finder.showDialog(true)
+}
+func (rte *RichTextEdit) findText() []uint16 {
+	return rte.utf16InRange(TextRange{0, -1})
+}
+
+func (rte *RichTextEdit) findSelection() TextRange {
+	return rte.Selection()
+}
+
+func (rte *RichTextEdit) setFindSelection(r TextRange) {
+	rte.SetSelection(r)
+}
+
+func (rte *RichTextEdit) replaceFindRange(r TextRange, text string) {
+	rte.SetSelection(r)
+	rte.ReplaceSelectedText(text, true)
+}
+
+func (rte *RichTextEdit) charPosition(index int) (Point, bool) {
+	var pt win.POINT
+	rte.SendMessage(win.EM_POSFROMCHAR, uintptr(unsafe.Pointer(&pt)), uintptr(index))
+
+	return Point{int(pt.X), int(pt.Y)}, true
+}
+
+// ReadRTF replaces the content of the RichTextEdit with the RTF document read
+// from r.
+func (rte *RichTextEdit) ReadRTF(r io.Reader) error {
+	return rte.stream(win.EM_STREAMIN, &richEditStream{r: r})
+}
+
+// WriteRTF writes the content of the RichTextEdit to w as RTF document.
+func (rte *RichTextEdit) WriteRTF(w io.Writer) error {
+	return rte.stream(win.EM_STREAMOUT, &richEditStream{w: w})
+}
+
+// RTF returns the content of the RichTextEdit as RTF document.
+func (rte *RichTextEdit) RTF() (string, error) {
+	var buf bytes.Buffer
+	if err := rte.WriteRTF(&buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// SetRTF replaces the content of the RichTextEdit with the RTF document rtf.
+func (rte *RichTextEdit) SetRTF(rtf string) error {
+	return rte.ReadRTF(strings.NewReader(rtf))
+}
+
+func (rte *RichTextEdit) stream(msg uint32, s *richEditStream) error {
+	richEditStreamCookie++
+	cookie := richEditStreamCookie
+
+	richEditStreams[cookie] = s
+	defer delete(richEditStreams, cookie)
+
+	es := win.EDITSTREAM{DwCookie: cookie, PfnCallback: richEditStreamCallbackPtr}
+	rte.SendMessage(msg, win.SF_RTF, uintptr(unsafe.Pointer(&es)))
+
+	if s.err != nil {
+		return wrapError(s.err)
+	}
+	if es.DwError != 0 {
+		return newError("RTF streaming failed")
+	}
+
+	if msg == win.EM_STREAMIN {
+		rte.textChangedPublisher.Publish()
+	}
+
+	return nil
+}
+
+func (*RichTextEdit) NeedsWmSize() bool {
+	return true
+}
+
+func (rte *RichTextEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if rte.finder.wndProc(msg, wParam, lParam) {
+		return 0
+	}
+
+	switch msg {
+	case win.WM_PAINT:
+		result := rte.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+		rte.finder.paintHighlights()
+		return result
+
+	case win.WM_COMMAND:
+		switch win.HIWORD(uint32(wParam)) {
+		case win.EN_CHANGE:
+			rte.textChangedPublisher.Publish()
+		}
+
+	case win.WM_NOTIFY:
+		switch ((*win.NMHDR)(unsafe.Pointer(lParam))).Code {
+		case win.EN_SELCHANGE:
+			rte.selectionChangedPublisher.Publish()
+		}
+
+	case win.WM_GETDLGCODE:
+		if wParam == win.VK_RETURN {
+			return win.DLGC_WANTALLKEYS
+		}
+
+		return win.DLGC_HASSETSEL | win.DLGC_WANTARROWS | win.DLGC_WANTCHARS
+	}
+
+	return rte.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (rte *RichTextEdit) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &logViewLayoutItem{
+		idealSize: SizeFrom96DPI(Size{100, 100}, ctx.dpi),
+		minSize:   rte.dialogBaseUnitsToPixels(Size{20, 12}),
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+
+	"github.com/miu200521358/win"
+)
+
+const layoutBitmapOrientationPreserved = 0x00000008
+
+var getLayout = syscall.NewLazyDLL("gdi32.dll").NewProc("GetLayout")
+
+func dcLayout(hdc win.HDC) uint32 {
+	r, _, _ := getLayout.Call(uintptr(hdc))
+	if int32(r) == -1 {
+		return 0
+	}
+
+	return uint32(r)
+}
+
+// RightToLeftLayout returns whether the x axis of the Canvas increases from
+// right to left, as it does when painting a window of a Form with
+// RightToLeftLayout.
+func (c *Canvas) RightToLeftLayout() bool {
+	return dcLayout(c.hdc)&layoutRTL != 0
+}
+
+// SetRightToLeftLayout sets whether the x axis of the Canvas increases from
+// right to left. Text stays readable and images keep their orientation.
+//
+// A Canvas for an off-screen Bitmap that is copied to a mirrored Canvas must
+// be mirrored as well, or the copy ends up with mirrored text.
+func (c *Canvas) SetRightToLeftLayout(rtl bool) error {
+	var layout uintptr
+	if rtl {
+		layout = layoutRTL | layoutBitmapOrientationPreserved
+	}
+
+	if r, _, _ := setLayout.Call(uintptr(c.hdc), layout); int32(r) == -1 {
+		return newError("SetLayout failed")
+	}
+
+	return nil
+}
+
+// adoptLayout mirrors the Canvas, which buffers painting, if hdc is mirrored,
+// so copying the buffer to hdc doesn't mirror it once more.
+func (c *Canvas) adoptLayout(hdc win.HDC) {
+	if dcLayout(hdc)&layoutRTL != 0 {
+		c.SetRightToLeftLayout(true)
+	}
+}
+
+// preserveBitmapOrientation keeps images drawn to a mirrored Canvas from being
+// mirrored.
+func (c *Canvas) preserveBitmapOrientation() {
+	layout := dcLayout(c.hdc)
+	if layout&layoutRTL == 0 || layout&layoutBitmapOrientationPreserved != 0 {
+		return
+	}
+
+	if r, _, _ := setLayout.Call(uintptr(c.hdc), uintptr(layout|layoutBitmapOrientationPreserved)); int32(r) != -1 {
+		c.restoreLayout = true
+		c.layout = layout
+	}
+}
+
+// MirrorRectangle returns bounds mirrored within a width wide area, e.g. to
+// convert between the coordinates of a window with RightToLeftLayout and an
+// unmirrored buffer or screen coordinates.
+func MirrorRectangle(bounds Rectangle, width int) Rectangle {
+	bounds.X = width - bounds.X - bounds.Width
+
+	return bounds
+}
+
+// applyRightToLeftLayout mirrors or unmirrors the descendants of window, which
+// don't inherit the layout of their parents when it changes after their
+// creation.
+func applyRightToLeftLayout(window Window, rtl bool) {
+	walkDescendants(window, func(w Window) bool {
+		wb := w.AsWindowBase()
+		if wb.window == window {
+			return true
+		}
+
+		if wb.hasExtendedStyleBits(win.WS_EX_NOINHERITLAYOUT) {
+			wb.ensureExtendedStyleBits(win.WS_EX_LAYOUTRTL, rtl)
+			return false
+		}
+
+		wb.ensureExtendedStyleBits(win.WS_EX_LAYOUTRTL, rtl)
+
+		if nle, ok := w.(*numberLineEdit); ok {
+			nle.applyRightToLeftLayout(rtl)
+		}
+
+		return true
+	})
+}
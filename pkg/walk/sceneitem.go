@@ -0,0 +1,503 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"syscall"
+
+	"github.com/miu200521358/win"
+)
+
+// ScenePoint is a point in the virtual coordinate space of a SceneView.
+//
+// At a zoom factor of 1, one scene unit is 1/96".
+type ScenePoint struct {
+	X, Y float64
+}
+
+// Add returns the sum of p and q.
+func (p ScenePoint) Add(q ScenePoint) ScenePoint {
+	return ScenePoint{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns the difference of p and q.
+func (p ScenePoint) Sub(q ScenePoint) ScenePoint {
+	return ScenePoint{p.X - q.X, p.Y - q.Y}
+}
+
+// SceneRect is a rectangle in the virtual coordinate space of a SceneView.
+type SceneRect struct {
+	X, Y, Width, Height float64
+}
+
+// Location returns the upper left corner of r.
+func (r SceneRect) Location() ScenePoint {
+	return ScenePoint{r.X, r.Y}
+}
+
+// Center returns the center of r.
+func (r SceneRect) Center() ScenePoint {
+	return ScenePoint{r.X + r.Width/2, r.Y + r.Height/2}
+}
+
+// IsEmpty returns whether r has no area.
+func (r SceneRect) IsEmpty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Contains returns whether p lies within r.
+func (r SceneRect) Contains(p ScenePoint) bool {
+	return p.X >= r.X && p.X < r.X+r.Width && p.Y >= r.Y && p.Y < r.Y+r.Height
+}
+
+// Intersects returns whether r and s overlap.
+func (r SceneRect) Intersects(s SceneRect) bool {
+	return r.X < s.X+s.Width && s.X < r.X+r.Width && r.Y < s.Y+s.Height && s.Y < r.Y+r.Height
+}
+
+// Union returns the smallest rectangle that contains both r and s. Empty
+// rectangles are ignored.
+func (r SceneRect) Union(s SceneRect) SceneRect {
+	if r.IsEmpty() {
+		return s
+	}
+	if s.IsEmpty() {
+		return r
+	}
+
+	x0, y0 := math.Min(r.X, s.X), math.Min(r.Y, s.Y)
+	x1, y1 := math.Max(r.X+r.Width, s.X+s.Width), math.Max(r.Y+r.Height, s.Y+s.Height)
+
+	return SceneRect{x0, y0, x1 - x0, y1 - y0}
+}
+
+// sceneRectFromPoints returns the rectangle spanned by a and b.
+func sceneRectFromPoints(a, b ScenePoint) SceneRect {
+	return SceneRect{
+		math.Min(a.X, b.X),
+		math.Min(a.Y, b.Y),
+		math.Abs(a.X - b.X),
+		math.Abs(a.Y - b.Y),
+	}
+}
+
+// SceneItem is a lightweight drawable hosted by a SceneView.
+//
+// Custom items embed SceneItemBase and implement Paint, and HitTest if their
+// shape is not rectangular.
+type SceneItem interface {
+	AsSceneItemBase() *SceneItemBase
+
+	// Paint paints the item. Bounds are mapped to native pixels of canvas
+	// using the Map* methods of view.
+	Paint(canvas *Canvas, view *SceneView) error
+
+	// HitTest returns whether p, in scene coordinates, hits the item.
+	HitTest(p ScenePoint) bool
+}
+
+// SceneItemBase implements the common parts of SceneItem.
+//
+// The zero value is a visible, movable and selectable item with empty
+// bounds.
+type SceneItemBase struct {
+	scene        *SceneView
+	bounds       SceneRect
+	hidden       bool
+	immovable    bool
+	unselectable bool
+	selected     bool
+}
+
+func (sib *SceneItemBase) AsSceneItemBase() *SceneItemBase {
+	return sib
+}
+
+// Scene returns the SceneView the item was added to, if any.
+func (sib *SceneItemBase) Scene() *SceneView {
+	return sib.scene
+}
+
+// Update requests the item to be repainted.
+func (sib *SceneItemBase) Update() {
+	if sib.scene != nil {
+		sib.scene.Invalidate()
+	}
+}
+
+// Bounds returns the bounds of the item in scene coordinates.
+func (sib *SceneItemBase) Bounds() SceneRect {
+	return sib.bounds
+}
+
+// SetBounds sets the bounds of the item in scene coordinates.
+func (sib *SceneItemBase) SetBounds(bounds SceneRect) {
+	if bounds == sib.bounds {
+		return
+	}
+
+	sib.bounds = bounds
+
+	sib.Update()
+}
+
+// Position returns the upper left corner of the item in scene coordinates.
+func (sib *SceneItemBase) Position() ScenePoint {
+	return sib.bounds.Location()
+}
+
+// SetPosition moves the item, so its upper left corner is at p.
+func (sib *SceneItemBase) SetPosition(p ScenePoint) {
+	sib.SetBounds(SceneRect{p.X, p.Y, sib.bounds.Width, sib.bounds.Height})
+}
+
+// Visible returns whether the item is painted and can be hit.
+func (sib *SceneItemBase) Visible() bool {
+	return !sib.hidden
+}
+
+// SetVisible sets whether the item is painted and can be hit.
+func (sib *SceneItemBase) SetVisible(visible bool) {
+	if visible != sib.hidden {
+		return
+	}
+
+	sib.hidden = !visible
+
+	sib.Update()
+}
+
+// Movable returns whether the item can be dragged by the user.
+func (sib *SceneItemBase) Movable() bool {
+	return !sib.immovable
+}
+
+// SetMovable sets whether the item can be dragged by the user.
+func (sib *SceneItemBase) SetMovable(movable bool) {
+	sib.immovable = !movable
+}
+
+// Selectable returns whether the item can be selected by the user.
+func (sib *SceneItemBase) Selectable() bool {
+	return !sib.unselectable
+}
+
+// SetSelectable sets whether the item can be selected by the user.
+func (sib *SceneItemBase) SetSelectable(selectable bool) {
+	sib.unselectable = !selectable
+}
+
+// Selected returns whether the item is selected in its SceneView.
+func (sib *SceneItemBase) Selected() bool {
+	return sib.selected
+}
+
+// HitTest returns whether p lies within the bounds of the item.
+func (sib *SceneItemBase) HitTest(p ScenePoint) bool {
+	return sib.bounds.Contains(p)
+}
+
+// SceneShape specifies the outline of a SceneShapeItem.
+type SceneShape int
+
+const (
+	SceneRectangle SceneShape = iota
+	SceneRoundedRectangle
+	SceneEllipse
+)
+
+// SceneShapeItem is a SceneItem that draws a rectangle, rounded rectangle or
+// ellipse.
+type SceneShapeItem struct {
+	SceneItemBase
+	shape        SceneShape
+	fillColor    Color
+	filled       bool
+	outlineColor Color
+	outlineWidth float64
+}
+
+// NewSceneShapeItem returns a new SceneShapeItem with the specified shape and
+// bounds, filled and outlined with system colors.
+func NewSceneShapeItem(shape SceneShape, bounds SceneRect) *SceneShapeItem {
+	si := &SceneShapeItem{
+		shape:        shape,
+		fillColor:    Color(win.GetSysColor(win.COLOR_BTNFACE)),
+		filled:       true,
+		outlineColor: Color(win.GetSysColor(win.COLOR_BTNSHADOW)),
+		outlineWidth: 1,
+	}
+	si.bounds = bounds
+
+	return si
+}
+
+// Shape returns the shape of the item.
+func (si *SceneShapeItem) Shape() SceneShape {
+	return si.shape
+}
+
+// SetShape sets the shape of the item.
+func (si *SceneShapeItem) SetShape(shape SceneShape) {
+	si.shape = shape
+	si.Update()
+}
+
+// FillColor returns the color the item is filled with.
+func (si *SceneShapeItem) FillColor() Color {
+	return si.fillColor
+}
+
+// SetFillColor sets the color the item is filled with and makes it filled.
+func (si *SceneShapeItem) SetFillColor(c Color) {
+	si.fillColor = c
+	si.filled = true
+	si.Update()
+}
+
+// Filled returns whether the item is filled.
+func (si *SceneShapeItem) Filled() bool {
+	return si.filled
+}
+
+// SetFilled sets whether the item is filled.
+func (si *SceneShapeItem) SetFilled(filled bool) {
+	si.filled = filled
+	si.Update()
+}
+
+// OutlineColor returns the color of the outline of the item.
+func (si *SceneShapeItem) OutlineColor() Color {
+	return si.outlineColor
+}
+
+// SetOutlineColor sets the color of the outline of the item.
+func (si *SceneShapeItem) SetOutlineColor(c Color) {
+	si.outlineColor = c
+	si.Update()
+}
+
+// OutlineWidth returns the width of the outline in scene units.
+func (si *SceneShapeItem) OutlineWidth() float64 {
+	return si.outlineWidth
+}
+
+// SetOutlineWidth sets the width of the outline in scene units. A width of 0
+// disables the outline.
+func (si *SceneShapeItem) SetOutlineWidth(width float64) {
+	si.outlineWidth = math.Max(0, width)
+	si.Update()
+}
+
+func (si *SceneShapeItem) HitTest(p ScenePoint) bool {
+	if si.shape != SceneEllipse {
+		return si.SceneItemBase.HitTest(p)
+	}
+
+	c := si.bounds.Center()
+	rx, ry := si.bounds.Width/2, si.bounds.Height/2
+	if rx <= 0 || ry <= 0 {
+		return false
+	}
+
+	dx, dy := (p.X-c.X)/rx, (p.Y-c.Y)/ry
+
+	return dx*dx+dy*dy <= 1
+}
+
+func (si *SceneShapeItem) Paint(canvas *Canvas, view *SceneView) error {
+	bounds := view.MapRectFromScene(si.bounds)
+	corner := view.MapSizeFromScene(8)
+	ellipse := Size{corner, corner}
+
+	if si.filled {
+		brush, err := NewSolidColorBrush(si.fillColor)
+		if err != nil {
+			return err
+		}
+		defer brush.Dispose()
+
+		var err2 error
+		switch si.shape {
+		case SceneEllipse:
+			err2 = canvas.FillEllipsePixels(brush, bounds)
+
+		case SceneRoundedRectangle:
+			err2 = canvas.FillRoundedRectanglePixels(brush, bounds, ellipse)
+
+		default:
+			err2 = canvas.FillRectanglePixels(brush, bounds)
+		}
+		if err2 != nil {
+			return err2
+		}
+	}
+
+	if si.outlineWidth <= 0 {
+		return nil
+	}
+
+	brush, err := NewSolidColorBrush(si.outlineColor)
+	if err != nil {
+		return err
+	}
+	defer brush.Dispose()
+
+	pen, err := NewGeometricPen(PenSolid|PenInsideFrame, maxi(1, view.MapSizeFromScene(si.outlineWidth)), brush)
+	if err != nil {
+		return err
+	}
+	defer pen.Dispose()
+
+	switch si.shape {
+	case SceneEllipse:
+		return canvas.DrawEllipsePixels(pen, bounds)
+
+	case SceneRoundedRectangle:
+		return canvas.DrawRoundedRectanglePixels(pen, bounds, ellipse)
+	}
+
+	return canvas.DrawRectanglePixels(pen, bounds)
+}
+
+// SceneImageItem is a SceneItem that draws an Image stretched to its bounds.
+type SceneImageItem struct {
+	SceneItemBase
+	image Image
+}
+
+// NewSceneImageItem returns a new SceneImageItem that draws image into
+// bounds.
+func NewSceneImageItem(image Image, bounds SceneRect) *SceneImageItem {
+	ii := &SceneImageItem{image: image}
+	ii.bounds = bounds
+
+	return ii
+}
+
+// Image returns the image drawn by the item.
+func (ii *SceneImageItem) Image() Image {
+	return ii.image
+}
+
+// SetImage sets the image drawn by the item.
+func (ii *SceneImageItem) SetImage(image Image) {
+	ii.image = image
+	ii.Update()
+}
+
+func (ii *SceneImageItem) Paint(canvas *Canvas, view *SceneView) error {
+	if ii.image == nil {
+		return nil
+	}
+
+	return canvas.DrawImageStretchedPixels(ii.image, view.MapRectFromScene(ii.bounds))
+}
+
+// SceneTextItem is a SceneItem that draws text into its bounds, scaling the
+// font with the zoom factor of the SceneView.
+type SceneTextItem struct {
+	SceneItemBase
+	text   string
+	font   *Font
+	color  Color
+	format DrawTextFormat
+}
+
+// NewSceneTextItem returns a new SceneTextItem that draws text into bounds.
+//
+// If font is nil, the font of the SceneView is used.
+func NewSceneTextItem(text string, font *Font, bounds SceneRect) *SceneTextItem {
+	ti := &SceneTextItem{
+		text:   text,
+		font:   font,
+		color:  Color(win.GetSysColor(win.COLOR_WINDOWTEXT)),
+		format: TextLeft | TextTop | TextWordbreak | TextEndEllipsis,
+	}
+	ti.bounds = bounds
+
+	return ti
+}
+
+// Text returns the text drawn by the item.
+func (ti *SceneTextItem) Text() string {
+	return ti.text
+}
+
+// SetText sets the text drawn by the item.
+func (ti *SceneTextItem) SetText(text string) {
+	ti.text = text
+	ti.Update()
+}
+
+// Font returns the font of the item, nil means that of the SceneView.
+func (ti *SceneTextItem) Font() *Font {
+	return ti.font
+}
+
+// SetFont sets the font of the item, nil means that of the SceneView.
+func (ti *SceneTextItem) SetFont(font *Font) {
+	ti.font = font
+	ti.Update()
+}
+
+// TextColor returns the color of the text.
+func (ti *SceneTextItem) TextColor() Color {
+	return ti.color
+}
+
+// SetTextColor sets the color of the text.
+func (ti *SceneTextItem) SetTextColor(c Color) {
+	ti.color = c
+	ti.Update()
+}
+
+// Format returns the format the text is drawn with.
+func (ti *SceneTextItem) Format() DrawTextFormat {
+	return ti.format
+}
+
+// SetFormat sets the format the text is drawn with.
+func (ti *SceneTextItem) SetFormat(format DrawTextFormat) {
+	ti.format = format
+	ti.Update()
+}
+
+func (ti *SceneTextItem) Paint(canvas *Canvas, view *SceneView) error {
+	font := ti.font
+	if font == nil {
+		font = view.Font()
+	}
+
+	return view.drawScaledText(canvas, ti.text, font, ti.color, view.MapRectFromScene(ti.bounds), ti.format)
+}
+
+// drawScaledText draws text into bounds with font scaled by the zoom factor
+// of the SceneView.
+func (sv *SceneView) drawScaledText(canvas *Canvas, text string, font *Font, color Color, bounds Rectangle, format DrawTextFormat) error {
+	dpi := int(math.Round(float64(canvas.DPI()) * sv.zoom))
+	if dpi < sceneMinTextDPI {
+		return nil
+	}
+
+	return canvas.withGdiObj(win.HGDIOBJ(font.handleForDPI(dpi)), func() error {
+		oldColor := win.SetTextColor(canvas.hdc, win.COLORREF(color))
+		if oldColor == win.CLR_INVALID {
+			return newError("SetTextColor failed")
+		}
+		defer win.SetTextColor(canvas.hdc, oldColor)
+
+		rect := bounds.toRECT()
+		if win.DrawTextEx(canvas.hdc, syscall.StringToUTF16Ptr(text), -1, &rect, uint32(format)|win.DT_EDITCONTROL, nil) == 0 {
+			return newError("DrawTextEx failed")
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,902 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+
+	"github.com/miu200521358/win"
+)
+
+const sceneViewWindowClass = `\o/ Walk_SceneView_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(sceneViewWindowClass)
+	})
+}
+
+const (
+	sceneMinZoom    = 0.05
+	sceneMaxZoom    = 20
+	sceneZoomStep   = 1.2 // per mouse wheel notch
+	sceneMinTextDPI = 8   // below this, text is too small to be worth drawing
+)
+
+type sceneViewMode int
+
+const (
+	sceneViewModeNone sceneViewMode = iota
+	sceneViewModePress
+	sceneViewModeDrag
+	sceneViewModeRubberBand
+	sceneViewModePan
+)
+
+// SceneView is a widget that hosts lightweight SceneItems, like shapes,
+// images and text, in a virtual coordinate space that can be zoomed and
+// panned.
+//
+// Items are painted in the order they were added, so later items are on top.
+// Clicking an item selects it, holding down the Control key toggles it, and
+// dragging on the background selects the items within the rubber band.
+// Dragging selected movable items moves them; pressing Escape while dragging
+// puts them back.
+//
+// Turning the mouse wheel scrolls vertically, or horizontally while Shift is
+// held down, and zooms around the cursor with Control. Dragging with the
+// middle mouse button pans.
+//
+// With a Snapper set, dragged items snap to its grid and guides and to the
+// edges and centers of the other items, unless Alt is held down.
+type SceneView struct {
+	WidgetBase
+	items                     []SceneItem
+	zoom                      float64
+	origin                    ScenePoint // scene coordinates of the upper left corner
+	mode                      sceneViewMode
+	pressPoint                Point // in native pixels
+	pressScene                ScenePoint
+	lastScene                 ScenePoint
+	toggleSelection           bool
+	dragItems                 []SceneItem
+	dragOrigins               []ScenePoint
+	dragBounds                SceneRect
+	dragOffset                ScenePoint // applied to the dragged items
+	snapper                   *Snapper
+	snapLines                 []SnapLine
+	zoomChangedPublisher      EventPublisher
+	viewChangedPublisher      EventPublisher
+	selectionChangedPublisher EventPublisher
+	itemsDraggedPublisher     SceneItemsDragEventPublisher
+	itemsMovedPublisher       SceneItemsDragEventPublisher
+}
+
+// NewSceneView creates and initializes a new, empty SceneView.
+func NewSceneView(parent Container) (*SceneView, error) {
+	sv := new(SceneView)
+
+	if err := sv.init(sv, parent); err != nil {
+		return nil, err
+	}
+
+	return sv, nil
+}
+
+// init initializes sv as part of widget, which is either sv itself or a
+// widget embedding it.
+func (sv *SceneView) init(widget Widget, parent Container) error {
+	sv.zoom = 1
+
+	if err := InitWidget(
+		widget,
+		parent,
+		sceneViewWindowClass,
+		win.WS_TABSTOP|win.WS_VISIBLE,
+		0); err != nil {
+		return err
+	}
+
+	sv.GraphicsEffects().Add(InteractionEffect)
+	sv.GraphicsEffects().Add(FocusEffect)
+
+	sv.MustRegisterProperty("Zoom", NewProperty(
+		func() interface{} {
+			return sv.Zoom()
+		},
+		func(v interface{}) error {
+			return sv.SetZoom(assertFloat64Or(v, 1))
+		},
+		sv.zoomChangedPublisher.Event()))
+
+	return nil
+}
+
+// Items returns the items of the SceneView, from bottom to top.
+func (sv *SceneView) Items() []SceneItem {
+	return append([]SceneItem(nil), sv.items...)
+}
+
+// AddItem adds item on top of the items of the SceneView.
+func (sv *SceneView) AddItem(item SceneItem) error {
+	sib := item.AsSceneItemBase()
+	if sib.scene != nil {
+		return newError("item already belongs to a SceneView")
+	}
+
+	sib.scene = sv
+	sv.items = append(sv.items, item)
+
+	sv.Invalidate()
+
+	return nil
+}
+
+// RemoveItem removes item from the SceneView.
+func (sv *SceneView) RemoveItem(item SceneItem) error {
+	index := sv.indexOfItem(item)
+	if index == -1 {
+		return newError("item not found")
+	}
+
+	sib := item.AsSceneItemBase()
+	sib.scene = nil
+
+	sv.items = append(sv.items[:index:index], sv.items[index+1:]...)
+
+	if sib.selected {
+		sib.selected = false
+		sv.selectionChangedPublisher.Publish()
+	}
+
+	sv.Invalidate()
+
+	return nil
+}
+
+// ClearItems removes all items from the SceneView.
+func (sv *SceneView) ClearItems() {
+	var hadSelection bool
+	for _, item := range sv.items {
+		sib := item.AsSceneItemBase()
+
+		hadSelection = hadSelection || sib.selected
+
+		sib.scene = nil
+		sib.selected = false
+	}
+
+	sv.items = nil
+	sv.mode = sceneViewModeNone
+
+	sv.Invalidate()
+
+	if hadSelection {
+		sv.selectionChangedPublisher.Publish()
+	}
+}
+
+// BringToFront moves item on top of all other items.
+func (sv *SceneView) BringToFront(item SceneItem) error {
+	index := sv.indexOfItem(item)
+	if index == -1 {
+		return newError("item not found")
+	}
+
+	copy(sv.items[index:], sv.items[index+1:])
+	sv.items[len(sv.items)-1] = item
+
+	sv.Invalidate()
+
+	return nil
+}
+
+// SendToBack moves item below all other items.
+func (sv *SceneView) SendToBack(item SceneItem) error {
+	index := sv.indexOfItem(item)
+	if index == -1 {
+		return newError("item not found")
+	}
+
+	copy(sv.items[1:index+1], sv.items[:index])
+	sv.items[0] = item
+
+	sv.Invalidate()
+
+	return nil
+}
+
+func (sv *SceneView) indexOfItem(item SceneItem) int {
+	for i, it := range sv.items {
+		if it == item {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ItemAt returns the topmost visible item that is hit by p, or nil.
+func (sv *SceneView) ItemAt(p ScenePoint) SceneItem {
+	for i := len(sv.items) - 1; i >= 0; i-- {
+		item := sv.items[i]
+
+		if item.AsSceneItemBase().Visible() && item.HitTest(p) {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// ItemsIn returns the visible items whose bounds intersect r, from bottom to
+// top.
+func (sv *SceneView) ItemsIn(r SceneRect) []SceneItem {
+	var items []SceneItem
+	for _, item := range sv.items {
+		if sib := item.AsSceneItemBase(); sib.Visible() && sib.bounds.Intersects(r) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// ItemsBounds returns the union of the bounds of all visible items.
+func (sv *SceneView) ItemsBounds() SceneRect {
+	var bounds SceneRect
+	for _, item := range sv.items {
+		if sib := item.AsSceneItemBase(); sib.Visible() {
+			bounds = bounds.Union(sib.bounds)
+		}
+	}
+
+	return bounds
+}
+
+// SelectedItems returns the selected items, from bottom to top.
+func (sv *SceneView) SelectedItems() []SceneItem {
+	var items []SceneItem
+	for _, item := range sv.items {
+		if item.AsSceneItemBase().selected {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// SetSelectedItems selects exactly items. Items that don't belong to the
+// SceneView are ignored.
+func (sv *SceneView) SetSelectedItems(items []SceneItem) {
+	selected := make(map[SceneItem]bool, len(items))
+	for _, item := range items {
+		selected[item] = true
+	}
+
+	var changed bool
+	for _, item := range sv.items {
+		sib := item.AsSceneItemBase()
+
+		if sel := selected[item]; sel != sib.selected {
+			sib.selected = sel
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	sv.Invalidate()
+
+	sv.selectionChangedPublisher.Publish()
+}
+
+// ClearSelection deselects all items.
+func (sv *SceneView) ClearSelection() {
+	sv.SetSelectedItems(nil)
+}
+
+// SelectionChanged returns the event that is published when the selection
+// changed.
+func (sv *SceneView) SelectionChanged() *Event {
+	return sv.selectionChangedPublisher.Event()
+}
+
+// ItemsDragged returns the event that is published repeatedly while the user
+// drags items, with the offset they were moved by since it was last
+// published.
+func (sv *SceneView) ItemsDragged() *SceneItemsDragEvent {
+	return sv.itemsDraggedPublisher.Event()
+}
+
+// ItemsMoved returns the event that is published when the user dropped
+// dragged items, with the total offset they were moved by.
+func (sv *SceneView) ItemsMoved() *SceneItemsDragEvent {
+	return sv.itemsMovedPublisher.Event()
+}
+
+// Snapper returns the Snapper that dragged items snap with, or nil.
+func (sv *SceneView) Snapper() *Snapper {
+	return sv.snapper
+}
+
+// SetSnapper sets the Snapper that dragged items snap with. Its grid size,
+// guides and tolerance are in scene units. Its targets are replaced with the
+// bounds of the items that are not dragged, whenever a drag starts.
+func (sv *SceneView) SetSnapper(snapper *Snapper) {
+	sv.snapper = snapper
+
+	sv.Invalidate()
+}
+
+// Zoom returns the zoom factor of the SceneView.
+func (sv *SceneView) Zoom() float64 {
+	return sv.zoom
+}
+
+// SetZoom sets the zoom factor of the SceneView, keeping the center of the
+// view in place.
+func (sv *SceneView) SetZoom(zoom float64) error {
+	cb := sv.ClientBoundsPixels()
+
+	return sv.ZoomAt(zoom, Point{cb.Width / 2, cb.Height / 2})
+}
+
+// ZoomAt sets the zoom factor of the SceneView, keeping the scene point below
+// pt, in native pixels, in place.
+func (sv *SceneView) ZoomAt(zoom float64, pt Point) error {
+	if math.IsNaN(zoom) || zoom <= 0 {
+		return newError("invalid zoom")
+	}
+
+	zoom = math.Max(sceneMinZoom, math.Min(sceneMaxZoom, zoom))
+	if zoom == sv.zoom {
+		return nil
+	}
+
+	anchor := sv.MapToScene(pt)
+
+	sv.zoom = zoom
+
+	scale := sv.scale()
+	sv.origin = ScenePoint{anchor.X - float64(pt.X)/scale, anchor.Y - float64(pt.Y)/scale}
+
+	sv.Invalidate()
+
+	sv.zoomChangedPublisher.Publish()
+	sv.viewChangedPublisher.Publish()
+
+	return nil
+}
+
+// ZoomChanged returns the event that is published when the zoom factor
+// changed.
+func (sv *SceneView) ZoomChanged() *Event {
+	return sv.zoomChangedPublisher.Event()
+}
+
+// ViewOrigin returns the scene coordinates shown at the upper left corner of
+// the SceneView.
+func (sv *SceneView) ViewOrigin() ScenePoint {
+	return sv.origin
+}
+
+// SetViewOrigin pans the SceneView, so that p is shown at its upper left
+// corner.
+func (sv *SceneView) SetViewOrigin(p ScenePoint) {
+	if p == sv.origin {
+		return
+	}
+
+	sv.origin = p
+
+	sv.Invalidate()
+
+	sv.viewChangedPublisher.Publish()
+}
+
+// ViewChanged returns the event that is published when the SceneView was
+// panned or zoomed.
+func (sv *SceneView) ViewChanged() *Event {
+	return sv.viewChangedPublisher.Event()
+}
+
+// VisibleRect returns the part of the scene that is currently visible.
+func (sv *SceneView) VisibleRect() SceneRect {
+	cb := sv.ClientBoundsPixels()
+	scale := sv.scale()
+
+	return SceneRect{sv.origin.X, sv.origin.Y, float64(cb.Width) / scale, float64(cb.Height) / scale}
+}
+
+// CenterOn pans the SceneView, so that p is shown at its center.
+func (sv *SceneView) CenterOn(p ScenePoint) {
+	vr := sv.VisibleRect()
+
+	sv.SetViewOrigin(ScenePoint{p.X - vr.Width/2, p.Y - vr.Height/2})
+}
+
+// FitRect zooms and pans the SceneView, so that r is fully visible.
+func (sv *SceneView) FitRect(r SceneRect) error {
+	if r.IsEmpty() {
+		return newError("empty rect")
+	}
+
+	cb := sv.ClientBoundsPixels()
+	if cb.Width <= 0 || cb.Height <= 0 {
+		return nil
+	}
+
+	dpiScale := float64(sv.DPI()) / 96
+	zoom := math.Min(float64(cb.Width)/r.Width, float64(cb.Height)/r.Height) / dpiScale
+
+	if err := sv.SetZoom(zoom); err != nil {
+		return err
+	}
+
+	sv.CenterOn(r.Center())
+
+	return nil
+}
+
+// FitItems zooms and pans the SceneView, so that all visible items are
+// visible.
+func (sv *SceneView) FitItems() error {
+	bounds := sv.ItemsBounds()
+	if bounds.IsEmpty() {
+		return nil
+	}
+
+	return sv.FitRect(bounds)
+}
+
+// scale returns the number of native pixels per scene unit.
+func (sv *SceneView) scale() float64 {
+	return sv.zoom * float64(sv.DPI()) / 96
+}
+
+// MapToScene maps pt, in native pixels of the SceneView, to scene
+// coordinates.
+func (sv *SceneView) MapToScene(pt Point) ScenePoint {
+	scale := sv.scale()
+
+	return ScenePoint{sv.origin.X + float64(pt.X)/scale, sv.origin.Y + float64(pt.Y)/scale}
+}
+
+// MapFromScene maps p, in scene coordinates, to native pixels of the
+// SceneView.
+func (sv *SceneView) MapFromScene(p ScenePoint) Point {
+	scale := sv.scale()
+
+	return Point{
+		int(math.Round((p.X - sv.origin.X) * scale)),
+		int(math.Round((p.Y - sv.origin.Y) * scale)),
+	}
+}
+
+// MapRectFromScene maps r, in scene coordinates, to native pixels of the
+// SceneView.
+func (sv *SceneView) MapRectFromScene(r SceneRect) Rectangle {
+	p0 := sv.MapFromScene(r.Location())
+	p1 := sv.MapFromScene(ScenePoint{r.X + r.Width, r.Y + r.Height})
+
+	return Rectangle{p0.X, p0.Y, p1.X - p0.X, p1.Y - p0.Y}
+}
+
+// MapSizeFromScene maps the length d, in scene units, to native pixels.
+func (sv *SceneView) MapSizeFromScene(d float64) int {
+	return int(math.Round(d * sv.scale()))
+}
+
+func (sv *SceneView) beginDrag() {
+	sv.dragItems = sv.dragItems[:0]
+	sv.dragOrigins = sv.dragOrigins[:0]
+
+	for _, item := range sv.SelectedItems() {
+		if sib := item.AsSceneItemBase(); sib.Movable() {
+			sv.dragItems = append(sv.dragItems, item)
+			sv.dragOrigins = append(sv.dragOrigins, sib.Position())
+		}
+	}
+
+	sv.dragBounds = SceneRect{}
+	sv.dragOffset = ScenePoint{}
+	sv.snapLines = nil
+
+	if sv.snapper == nil {
+		return
+	}
+
+	var targets []SceneRect
+
+	for _, item := range sv.items {
+		sib := item.AsSceneItemBase()
+
+		if sib.selected && sib.Movable() {
+			sv.dragBounds = sv.dragBounds.Union(sib.bounds)
+		} else if sib.Visible() && !sib.bounds.IsEmpty() {
+			targets = append(targets, sib.bounds)
+		}
+	}
+
+	sv.snapper.SetTargets(targets)
+}
+
+// snapDragOffset returns offset adjusted so the dragged items snap, along
+// with the lines they snap to.
+func (sv *SceneView) snapDragOffset(offset ScenePoint) (ScenePoint, []SnapLine) {
+	if sv.snapper == nil || sv.dragBounds.IsEmpty() || AltDown() {
+		return offset, nil
+	}
+
+	r := sv.dragBounds
+	r.X += offset.X
+	r.Y += offset.Y
+
+	snapped, lines := sv.snapper.SnapRect(r)
+
+	return offset.Add(snapped.Location().Sub(r.Location())), lines
+}
+
+// dragThreshold returns the square of the distance in native pixels the mouse
+// has to move before a press turns into a drag.
+func (sv *SceneView) dragThreshold(metric int32) int {
+	d := int(win.GetSystemMetrics(metric))
+
+	return d * d
+}
+
+func (sv *SceneView) moveDragItems(delta ScenePoint) {
+	for _, item := range sv.dragItems {
+		sib := item.AsSceneItemBase()
+		sib.SetPosition(sib.Position().Add(delta))
+	}
+}
+
+func (sv *SceneView) cancelDrag() {
+	if sv.mode == sceneViewModeDrag {
+		for i, item := range sv.dragItems {
+			item.AsSceneItemBase().SetPosition(sv.dragOrigins[i])
+		}
+	}
+
+	sv.mode = sceneViewModeNone
+	sv.snapLines = nil
+
+	sv.Invalidate()
+}
+
+// rubberBand returns the rectangle spanned by the rubber band in scene
+// coordinates.
+func (sv *SceneView) rubberBand() SceneRect {
+	return sceneRectFromPoints(sv.pressScene, sv.lastScene)
+}
+
+func (sv *SceneView) selectItem(item SceneItem, toggle bool) {
+	sib := item.AsSceneItemBase()
+
+	if toggle {
+		sib.selected = !sib.selected
+
+		sv.Invalidate()
+
+		sv.selectionChangedPublisher.Publish()
+		return
+	}
+
+	if !sib.selected {
+		sv.SetSelectedItems([]SceneItem{item})
+	}
+}
+
+func (sv *SceneView) finishRubberBand() {
+	items := sv.ItemsIn(sv.rubberBand())
+
+	if sv.toggleSelection {
+		items = append(items, sv.SelectedItems()...)
+	}
+
+	selectable := items[:0]
+	for _, item := range items {
+		if item.AsSceneItemBase().Selectable() {
+			selectable = append(selectable, item)
+		}
+	}
+
+	sv.SetSelectedItems(selectable)
+
+	sv.Invalidate()
+}
+
+func (sv *SceneView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, sv.DPI(), sv.paint) {
+			return 0
+		}
+
+	case win.WM_LBUTTONDOWN:
+		if !sv.Enabled() {
+			break
+		}
+
+		sv.SetFocus()
+
+		sv.pressPoint = Point{int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))}
+		sv.pressScene = sv.MapToScene(sv.pressPoint)
+		sv.lastScene = sv.pressScene
+		sv.toggleSelection = wParam&win.MK_CONTROL != 0
+
+		if item := sv.ItemAt(sv.pressScene); item != nil && item.AsSceneItemBase().Selectable() {
+			sv.selectItem(item, sv.toggleSelection)
+
+			if sv.toggleSelection {
+				sv.mode = sceneViewModeNone
+			} else {
+				sv.mode = sceneViewModePress
+			}
+		} else {
+			if !sv.toggleSelection {
+				sv.ClearSelection()
+			}
+
+			sv.mode = sceneViewModeRubberBand
+		}
+
+	case win.WM_MBUTTONDOWN:
+		if !sv.Enabled() {
+			break
+		}
+
+		sv.SetFocus()
+		win.SetCapture(hwnd)
+
+		sv.pressPoint = Point{int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))}
+		sv.pressScene = sv.origin
+		sv.mode = sceneViewModePan
+
+	case win.WM_MOUSEMOVE:
+		pt := Point{int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))}
+
+		switch sv.mode {
+		case sceneViewModePress:
+			dx, dy := pt.X-sv.pressPoint.X, pt.Y-sv.pressPoint.Y
+			if dx*dx < sv.dragThreshold(win.SM_CXDRAG) && dy*dy < sv.dragThreshold(win.SM_CYDRAG) {
+				break
+			}
+
+			sv.beginDrag()
+			if len(sv.dragItems) == 0 {
+				sv.mode = sceneViewModeNone
+				break
+			}
+
+			sv.mode = sceneViewModeDrag
+			fallthrough
+
+		case sceneViewModeDrag:
+			sv.lastScene = sv.MapToScene(pt)
+
+			offset, lines := sv.snapDragOffset(sv.lastScene.Sub(sv.pressScene))
+			delta := offset.Sub(sv.dragOffset)
+			sv.dragOffset = offset
+
+			if len(lines) > 0 || len(sv.snapLines) > 0 {
+				sv.snapLines = lines
+				sv.Invalidate()
+			}
+
+			if delta == (ScenePoint{}) {
+				break
+			}
+
+			sv.moveDragItems(delta)
+
+			sv.itemsDraggedPublisher.Publish(sv.dragItems, delta)
+
+		case sceneViewModeRubberBand:
+			sv.lastScene = sv.MapToScene(pt)
+			sv.Invalidate()
+
+		case sceneViewModePan:
+			scale := sv.scale()
+
+			sv.SetViewOrigin(ScenePoint{
+				sv.pressScene.X - float64(pt.X-sv.pressPoint.X)/scale,
+				sv.pressScene.Y - float64(pt.Y-sv.pressPoint.Y)/scale,
+			})
+		}
+
+	case win.WM_LBUTTONUP:
+		switch sv.mode {
+		case sceneViewModeDrag:
+			sv.mode = sceneViewModeNone
+
+			if sv.snapLines != nil {
+				sv.snapLines = nil
+				sv.Invalidate()
+			}
+
+			if sv.dragOffset != (ScenePoint{}) {
+				sv.itemsMovedPublisher.Publish(sv.dragItems, sv.dragOffset)
+			}
+
+		case sceneViewModeRubberBand:
+			sv.mode = sceneViewModeNone
+
+			sv.finishRubberBand()
+
+		case sceneViewModePress:
+			sv.mode = sceneViewModeNone
+		}
+
+	case win.WM_MBUTTONUP:
+		if sv.mode == sceneViewModePan {
+			sv.mode = sceneViewModeNone
+			win.ReleaseCapture()
+		}
+
+	case win.WM_CAPTURECHANGED:
+		if sv.mode != sceneViewModeNone {
+			sv.cancelDrag()
+		}
+
+	case win.WM_KEYDOWN:
+		if Key(wParam) == KeyEscape && sv.mode != sceneViewModeNone {
+			sv.cancelDrag()
+			return 0
+		}
+
+	case win.WM_MOUSEWHEEL:
+		if !sv.Enabled() {
+			break
+		}
+
+		notches := float64(int16(win.HIWORD(uint32(wParam)))) / 120
+		keys := win.LOWORD(uint32(wParam))
+
+		switch {
+		case keys&win.MK_CONTROL != 0:
+			pt := win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}
+			win.ScreenToClient(hwnd, &pt)
+
+			sv.ZoomAt(sv.zoom*math.Pow(sceneZoomStep, notches), Point{int(pt.X), int(pt.Y)})
+
+		case keys&win.MK_SHIFT != 0:
+			sv.SetViewOrigin(ScenePoint{sv.origin.X - notches*48/sv.zoom, sv.origin.Y})
+
+		default:
+			sv.SetViewOrigin(ScenePoint{sv.origin.X, sv.origin.Y - notches*48/sv.zoom})
+		}
+
+		return 0
+	}
+
+	return sv.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (sv *SceneView) paint(canvas *Canvas, bounds Rectangle) error {
+	bg, _ := sv.backgroundEffective()
+	if bg == nil {
+		brush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_WINDOW)))
+		if err != nil {
+			return err
+		}
+		defer brush.Dispose()
+
+		bg = brush
+	}
+
+	if err := canvas.FillRectanglePixels(bg, bounds); err != nil {
+		return err
+	}
+
+	if sv.snapper != nil {
+		if err := sv.paintSnapLines(canvas, bounds, sv.snapper.guides); err != nil {
+			return err
+		}
+	}
+
+	visible := sv.VisibleRect()
+
+	var selectionPen *CosmeticPen
+	defer func() {
+		if selectionPen != nil {
+			selectionPen.Dispose()
+		}
+	}()
+
+	margin := IntFrom96DPI(2, sv.DPI())
+
+	for _, item := range sv.items {
+		sib := item.AsSceneItemBase()
+		if !sib.Visible() {
+			continue
+		}
+
+		// Items with empty bounds, like connectors, are always painted.
+		if !sib.bounds.IsEmpty() && !sib.bounds.Intersects(visible) {
+			continue
+		}
+
+		if err := item.Paint(canvas, sv); err != nil {
+			return err
+		}
+
+		// Items with empty bounds indicate selection themselves.
+		if !sib.selected || sib.bounds.IsEmpty() {
+			continue
+		}
+
+		if selectionPen == nil {
+			pen, err := NewCosmeticPen(PenDot, Color(win.GetSysColor(win.COLOR_HIGHLIGHT)))
+			if err != nil {
+				return err
+			}
+			selectionPen = pen
+		}
+
+		r := sv.MapRectFromScene(sib.bounds)
+		r = Rectangle{r.X - margin, r.Y - margin, r.Width + 2*margin, r.Height + 2*margin}
+
+		if err := canvas.DrawRectanglePixels(selectionPen, r); err != nil {
+			return err
+		}
+	}
+
+	if err := sv.paintSnapLines(canvas, bounds, sv.snapLines); err != nil {
+		return err
+	}
+
+	if sv.mode != sceneViewModeRubberBand {
+		return nil
+	}
+
+	pen, err := NewCosmeticPen(PenDot, Color(win.GetSysColor(win.COLOR_WINDOWTEXT)))
+	if err != nil {
+		return err
+	}
+	defer pen.Dispose()
+
+	return canvas.DrawRectanglePixels(pen, sv.MapRectFromScene(sv.rubberBand()))
+}
+
+func (sv *SceneView) paintSnapLines(canvas *Canvas, bounds Rectangle, lines []SnapLine) error {
+	for _, line := range lines {
+		pt := sv.MapFromScene(ScenePoint{line.Position, line.Position})
+
+		position := pt.Y
+		if line.Orientation == Vertical {
+			position = pt.X
+		}
+
+		if err := DrawSnapLinePixels(canvas, bounds, line, position); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sv *SceneView) Dispose() {
+	for _, item := range sv.items {
+		item.AsSceneItemBase().scene = nil
+	}
+	sv.items = nil
+
+	sv.WidgetBase.Dispose()
+}
+
+func (*SceneView) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return NewGreedyLayoutItem()
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+type sceneItemsDragEventHandlerInfo struct {
+	handler SceneItemsDragEventHandler
+	once    bool
+}
+
+type SceneItemsDragEventHandler func(items []SceneItem, delta ScenePoint)
+
+type SceneItemsDragEvent struct {
+	handlers   []*sceneItemsDragEventHandlerInfo
+	publishing int // Number of Publish calls in progress
+}
+
+func (e *SceneItemsDragEvent) Attach(handler SceneItemsDragEventHandler) int {
+	handlerInfo := &sceneItemsDragEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*sceneItemsDragEventHandlerInfo(nil), e.handlers...)
+	}
+
+	for i, h := range e.handlers {
+		if h.handler == nil {
+			e.handlers[i] = handlerInfo
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handlerInfo)
+
+	return len(e.handlers) - 1
+}
+
+func (e *SceneItemsDragEvent) Detach(handle int) {
+	e.handlers[handle].handler = nil
+}
+
+func (e *SceneItemsDragEvent) Once(handler SceneItemsDragEventHandler) {
+	i := e.Attach(handler)
+	e.handlers[i].once = true
+}
+
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *SceneItemsDragEvent) Connect(handler SceneItemsDragEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *SceneItemsDragEvent) AttachOwned(owner Window, handler SceneItemsDragEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
+type SceneItemsDragEventPublisher struct {
+	event SceneItemsDragEvent
+}
+
+func (p *SceneItemsDragEventPublisher) Event() *SceneItemsDragEvent {
+	return &p.event
+}
+
+func (p *SceneItemsDragEventPublisher) Publish(items []SceneItem, delta ScenePoint) {
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
+
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
+			if h.once {
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
+			}
+
+			handler(items, delta)
+		}
+	}
+}
+
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+func (p *SceneItemsDragEventPublisher) PublishAsync(items []SceneItem, delta ScenePoint) {
+	publishAsync(func() {
+		p.Publish(items, delta)
+	})
+}
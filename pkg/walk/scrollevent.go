@@ -0,0 +1,98 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+type scrollEventHandlerInfo struct {
+	handler ScrollEventHandler
+	once    bool
+}
+
+type ScrollEventHandler func(x, y int)
+
+type ScrollEvent struct {
+	handlers   []*scrollEventHandlerInfo
+	publishing int // Number of Publish calls in progress
+}
+
+func (e *ScrollEvent) Attach(handler ScrollEventHandler) int {
+	handlerInfo := &scrollEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*scrollEventHandlerInfo(nil), e.handlers...)
+	}
+
+	for i, h := range e.handlers {
+		if h.handler == nil {
+			e.handlers[i] = handlerInfo
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handlerInfo)
+
+	return len(e.handlers) - 1
+}
+
+func (e *ScrollEvent) Detach(handle int) {
+	e.handlers[handle].handler = nil
+}
+
+func (e *ScrollEvent) Once(handler ScrollEventHandler) {
+	i := e.Attach(handler)
+	e.handlers[i].once = true
+}
+
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *ScrollEvent) Connect(handler ScrollEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *ScrollEvent) AttachOwned(owner Window, handler ScrollEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
+type ScrollEventPublisher struct {
+	event ScrollEvent
+}
+
+func (p *ScrollEventPublisher) Event() *ScrollEvent {
+	return &p.event
+}
+
+func (p *ScrollEventPublisher) Publish(x, y int) {
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
+
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
+			if h.once {
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
+			}
+
+			handler(x, y)
+		}
+	}
+}
+
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+func (p *ScrollEventPublisher) PublishAsync(x, y int) {
+	publishAsync(func() {
+		p.Publish(x, y)
+	})
+}
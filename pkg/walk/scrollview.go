@@ -15,17 +15,51 @@ import (
 
 const scrollViewWindowClass = `\o/ Walk_ScrollView_Class \o/`
 
+const (
+	scrollViewSmoothScrollTimerId  = 1
+	scrollViewSmoothScrollInterval = 10 // in milliseconds
+)
+
+const (
+	wmMouseHWheel          = 0x020E
+	spiGetWheelScrollLines = 0x0068
+	spiGetWheelScrollChars = 0x006C
+	wheelPageScroll        = 0xFFFFFFFF
+)
+
 func init() {
 	AppendToWalkInit(func() {
 		MustRegisterWindowClass(scrollViewWindowClass)
 	})
 }
 
+// ScrollBarPolicy specifies when a ScrollView shows a scroll bar.
+type ScrollBarPolicy int
+
+const (
+	// ScrollBarAuto shows the scroll bar when the content doesn't fit.
+	ScrollBarAuto ScrollBarPolicy = iota
+
+	// ScrollBarAlways shows the scroll bar, disabled while the content fits.
+	ScrollBarAlways
+
+	// ScrollBarNever doesn't scroll, so the content is sized to fit instead.
+	ScrollBarNever
+)
+
 type ScrollView struct {
 	WidgetBase
-	composite  *Composite
-	horizontal bool
-	vertical   bool
+	composite          *Composite
+	horizontal         bool
+	vertical           bool
+	horizontalAlways   bool
+	verticalAlways     bool
+	fitWidth           bool
+	smoothScrolling    bool
+	smoothScrollActive bool
+	scrollTarget       Point // in native pixels
+	scrolledPos        Point // in native pixels
+	scrolledPublisher  ScrollEventPublisher
 }
 
 func NewScrollView(parent Container) (*ScrollView, error) {
@@ -77,7 +111,7 @@ func (sv *ScrollView) ApplyDPI(dpi int) {
 }
 
 func (sv *ScrollView) Scrollbars() (horizontal, vertical bool) {
-	horizontal = sv.horizontal
+	horizontal = sv.horizontal && !sv.fitWidth
 	vertical = sv.vertical
 
 	return
@@ -87,10 +121,340 @@ func (sv *ScrollView) SetScrollbars(horizontal, vertical bool) {
 	sv.horizontal = horizontal
 	sv.vertical = vertical
 
-	sv.ensureStyleBits(win.WS_HSCROLL, horizontal)
+	sv.ensureStyleBits(win.WS_HSCROLL, horizontal && !sv.fitWidth)
 	sv.ensureStyleBits(win.WS_VSCROLL, vertical)
 }
 
+// ScrollBarPolicies returns when the horizontal and vertical scroll bars are
+// shown.
+func (sv *ScrollView) ScrollBarPolicies() (horizontal, vertical ScrollBarPolicy) {
+	policy := func(scrollable, always bool) ScrollBarPolicy {
+		switch {
+		case !scrollable:
+			return ScrollBarNever
+
+		case always:
+			return ScrollBarAlways
+		}
+
+		return ScrollBarAuto
+	}
+
+	return policy(sv.horizontal, sv.horizontalAlways), policy(sv.vertical, sv.verticalAlways)
+}
+
+// SetScrollBarPolicies sets when the horizontal and vertical scroll bars are
+// shown. ScrollBarNever is the same as disabling the scroll bar with
+// SetScrollbars.
+func (sv *ScrollView) SetScrollBarPolicies(horizontal, vertical ScrollBarPolicy) {
+	sv.horizontalAlways = horizontal == ScrollBarAlways
+	sv.verticalAlways = vertical == ScrollBarAlways
+
+	sv.SetScrollbars(horizontal != ScrollBarNever, vertical != ScrollBarNever)
+
+	sv.updateScrollBars()
+	sv.RequestLayout()
+}
+
+// FitWidth returns if the content is laid out at the width of the
+// ScrollView.
+func (sv *ScrollView) FitWidth() bool {
+	return sv.fitWidth
+}
+
+// SetFitWidth sets if the content is laid out at the width of the
+// ScrollView, which then doesn't scroll horizontally, e.g. for content that
+// wraps like a flow of widgets or text. Unlike without a horizontal scroll
+// bar, the ScrollView can become narrower than the minimum width of its
+// content.
+func (sv *ScrollView) SetFitWidth(fit bool) {
+	sv.fitWidth = fit
+
+	sv.ensureStyleBits(win.WS_HSCROLL, sv.horizontal && !fit)
+
+	sv.RequestLayout()
+}
+
+// SmoothScrolling returns if mouse wheel input scrolls gradually.
+func (sv *ScrollView) SmoothScrolling() bool {
+	return sv.smoothScrolling
+}
+
+// SetSmoothScrolling sets if mouse wheel input scrolls gradually, by the
+// number of pixels the system settings for wheel scrolling amount to, instead
+// of jumping there at once. It is off by default.
+func (sv *ScrollView) SetSmoothScrolling(smooth bool) {
+	sv.smoothScrolling = smooth
+
+	if !smooth {
+		sv.stopSmoothScroll()
+	}
+}
+
+// Scrolled returns the event that is published with the new scroll position
+// in native pixels, whenever the content of the ScrollView moved.
+func (sv *ScrollView) Scrolled() *ScrollEvent {
+	return sv.scrolledPublisher.Event()
+}
+
+// ScrollPositionPixels returns how far the content is scrolled, in native
+// pixels.
+func (sv *ScrollView) ScrollPositionPixels() Point {
+	return Point{sv.scrollPos(win.SB_HORZ), sv.scrollPos(win.SB_VERT)}
+}
+
+// SetScrollPositionPixels scrolls the content to pos in native pixels,
+// limited to the scrollable range.
+func (sv *ScrollView) SetScrollPositionPixels(pos Point) {
+	sv.stopSmoothScroll()
+
+	sv.scrollToPixels(pos.X, pos.Y)
+}
+
+// ScrollTo scrolls the content, so widget, which must be a descendant of the
+// ScrollView, is in view.
+//
+// With AlignHVDefault, the ScrollView scrolls as little as needed, otherwise
+// widget is aligned with the near or far edge, or the center of the view.
+func (sv *ScrollView) ScrollTo(widget Widget, align Alignment2D) error {
+	if widget == nil || !win.IsChild(sv.composite.hWnd, widget.Handle()) {
+		return newError("widget must be a descendant of the ScrollView")
+	}
+
+	var rc win.RECT
+	if !win.GetWindowRect(widget.Handle(), &rc) {
+		return lastError("GetWindowRect")
+	}
+
+	// The client coordinates of the composite are those of the content.
+	p1 := win.POINT{X: rc.Left, Y: rc.Top}
+	p2 := win.POINT{X: rc.Right, Y: rc.Bottom}
+	win.ScreenToClient(sv.composite.hWnd, &p1)
+	win.ScreenToClient(sv.composite.hWnd, &p2)
+
+	view := sv.ClientBoundsPixels().Size()
+	pos := sv.ScrollPositionPixels()
+
+	var h, v Alignment1D
+	switch align {
+	case AlignHNearVNear, AlignHNearVCenter, AlignHNearVFar:
+		h = AlignNear
+
+	case AlignHCenterVNear, AlignHCenterVCenter, AlignHCenterVFar:
+		h = AlignCenter
+
+	case AlignHFarVNear, AlignHFarVCenter, AlignHFarVFar:
+		h = AlignFar
+	}
+	switch align {
+	case AlignHNearVNear, AlignHCenterVNear, AlignHFarVNear:
+		v = AlignNear
+
+	case AlignHNearVCenter, AlignHCenterVCenter, AlignHFarVCenter:
+		v = AlignCenter
+
+	case AlignHNearVFar, AlignHCenterVFar, AlignHFarVFar:
+		v = AlignFar
+	}
+
+	sv.stopSmoothScroll()
+
+	sv.scrollToPixels(
+		scrollPosToShow(pos.X, int(minInt32(p1.X, p2.X)), int(maxInt32(p1.X, p2.X)), view.Width, h),
+		scrollPosToShow(pos.Y, int(p1.Y), int(p2.Y), view.Height, v))
+
+	return nil
+}
+
+// scrollPosToShow returns the scroll position along one axis, that shows the
+// range from start to end in a view of size, aligned as requested.
+func scrollPosToShow(pos, start, end, size int, align Alignment1D) int {
+	switch align {
+	case AlignNear:
+		return start
+
+	case AlignCenter:
+		return (start + end - size) / 2
+
+	case AlignFar:
+		return end - size
+	}
+
+	if start < pos || end-start > size {
+		return start
+	}
+	if end > pos+size {
+		return end - size
+	}
+
+	return pos
+}
+
+// scrollPos returns the scroll position for scroll bar sb in native pixels.
+func (sv *ScrollView) scrollPos(sb int32) int {
+	var si win.SCROLLINFO
+	si.CbSize = uint32(unsafe.Sizeof(si))
+	si.FMask = win.SIF_POS
+
+	win.GetScrollInfo(sv.hWnd, sb, &si)
+
+	return int(si.NPos)
+}
+
+// setScrollPos sets the scroll position for scroll bar sb, limited to its
+// range, and returns the new position in native pixels.
+func (sv *ScrollView) setScrollPos(sb int32, pos int) int {
+	pos = sv.clampScrollPos(sb, pos)
+
+	var si win.SCROLLINFO
+	si.CbSize = uint32(unsafe.Sizeof(si))
+	si.FMask = win.SIF_POS
+	si.NPos = int32(pos)
+	win.SetScrollInfo(sv.hWnd, sb, &si, true)
+
+	return pos
+}
+
+// scrollToPixels scrolls the content to x, y in native pixels.
+func (sv *ScrollView) scrollToPixels(x, y int) {
+	x = sv.setScrollPos(win.SB_HORZ, x)
+	y = sv.setScrollPos(win.SB_VERT, y)
+
+	b := sv.composite.BoundsPixels()
+	if b.X == -x && b.Y == -y {
+		return
+	}
+
+	b.X, b.Y = -x, -y
+	sv.composite.SetBoundsPixels(b)
+
+	if sv.hasComplexBackground() {
+		sv.composite.Invalidate()
+	}
+
+	sv.publishScrolled()
+}
+
+// publishScrolled publishes the Scrolled event, if the content moved since
+// the last time.
+func (sv *ScrollView) publishScrolled() {
+	b := sv.composite.BoundsPixels()
+
+	if pos := (Point{-b.X, -b.Y}); pos != sv.scrolledPos {
+		sv.scrolledPos = pos
+		sv.scrolledPublisher.Publish(pos.X, pos.Y)
+	}
+}
+
+// wheelScrollPixels returns by how many native pixels the wheel delta scrolls
+// along scroll bar sb.
+func (sv *ScrollView) wheelScrollPixels(sb int32, delta int) int {
+	action := uint32(spiGetWheelScrollLines)
+	if sb == win.SB_HORZ {
+		action = spiGetWheelScrollChars
+	}
+
+	lines := uint32(3)
+	win.SystemParametersInfo(action, 0, unsafe.Pointer(&lines), 0)
+
+	var lineSize int
+	if lines == wheelPageScroll {
+		var si win.SCROLLINFO
+		si.CbSize = uint32(unsafe.Sizeof(si))
+		si.FMask = win.SIF_PAGE
+		win.GetScrollInfo(sv.hWnd, sb, &si)
+
+		lines, lineSize = 1, int(si.NPage)
+	} else {
+		lineSize = sv.IntFrom96DPI(20)
+	}
+
+	return delta * int(lines) * lineSize / 120
+}
+
+// scrollByWheel scrolls by dx, dy native pixels in response to wheel input,
+// gradually if smooth scrolling is enabled.
+func (sv *ScrollView) scrollByWheel(dx, dy int) {
+	if !sv.smoothScrolling {
+		pos := sv.ScrollPositionPixels()
+		sv.scrollToPixels(pos.X+dx, pos.Y+dy)
+		return
+	}
+
+	if !sv.smoothScrollActive {
+		sv.scrollTarget = sv.ScrollPositionPixels()
+	}
+
+	sv.scrollTarget.X = sv.clampScrollPos(win.SB_HORZ, sv.scrollTarget.X+dx)
+	sv.scrollTarget.Y = sv.clampScrollPos(win.SB_VERT, sv.scrollTarget.Y+dy)
+
+	if !sv.smoothScrollActive {
+		if 0 == win.SetTimer(sv.hWnd, scrollViewSmoothScrollTimerId, scrollViewSmoothScrollInterval, 0) {
+			lastError("SetTimer")
+			sv.scrollToPixels(sv.scrollTarget.X, sv.scrollTarget.Y)
+			return
+		}
+
+		sv.smoothScrollActive = true
+	}
+
+	sv.smoothScrollStep()
+}
+
+// clampScrollPos returns pos limited to the range of scroll bar sb.
+func (sv *ScrollView) clampScrollPos(sb int32, pos int) int {
+	var si win.SCROLLINFO
+	si.CbSize = uint32(unsafe.Sizeof(si))
+	si.FMask = win.SIF_PAGE | win.SIF_RANGE
+
+	win.GetScrollInfo(sv.hWnd, sb, &si)
+
+	if max := int(si.NMax + 1 - int32(si.NPage)); pos > max {
+		pos = max
+	}
+	if pos < 0 {
+		pos = 0
+	}
+
+	return pos
+}
+
+// smoothScrollStep moves a third of the remaining distance towards the
+// scroll target, and stops once it is reached.
+func (sv *ScrollView) smoothScrollStep() {
+	step := func(cur, target int) int {
+		d := (target - cur) / 3
+		if d == 0 && target != cur {
+			if target > cur {
+				d = 1
+			} else {
+				d = -1
+			}
+		}
+
+		return cur + d
+	}
+
+	pos := sv.ScrollPositionPixels()
+	x, y := step(pos.X, sv.scrollTarget.X), step(pos.Y, sv.scrollTarget.Y)
+
+	sv.scrollToPixels(x, y)
+
+	// The range may have shrunk meanwhile, so also stop when stuck.
+	if next := sv.ScrollPositionPixels(); next == sv.scrollTarget || next == pos {
+		sv.stopSmoothScroll()
+	}
+}
+
+func (sv *ScrollView) stopSmoothScroll() {
+	if !sv.smoothScrollActive {
+		return
+	}
+
+	sv.smoothScrollActive = false
+	win.KillTimer(sv.hWnd, scrollViewSmoothScrollTimerId)
+}
+
 func (sv *ScrollView) SetSuspended(suspend bool) {
 	sv.composite.SetSuspended(suspend)
 	sv.WidgetBase.SetSuspended(suspend)
@@ -176,34 +540,54 @@ func (sv *ScrollView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr)
 
 		switch msg {
 		case win.WM_HSCROLL:
+			sv.stopSmoothScroll()
 			sv.composite.SetXPixels(sv.scroll(win.SB_HORZ, win.LOWORD(uint32(wParam))))
 			if wParam == win.SB_ENDSCROLL {
 				avoidBGArtifacts()
 			}
+			sv.publishScrolled()
 
 		case win.WM_VSCROLL:
+			sv.stopSmoothScroll()
 			sv.composite.SetYPixels(sv.scroll(win.SB_VERT, win.LOWORD(uint32(wParam))))
 			if wParam == win.SB_ENDSCROLL {
 				avoidBGArtifacts()
 			}
+			sv.publishScrolled()
 
 		case win.WM_MOUSEWHEEL:
 			if win.GetWindowLong(sv.hWnd, win.GWL_STYLE)&win.WS_VSCROLL == 0 {
 				break
 			}
 
-			var cmd uint16
-			if delta := int16(win.HIWORD(uint32(wParam))); delta < 0 {
-				cmd = win.SB_LINEDOWN
+			delta := int(int16(win.HIWORD(uint32(wParam))))
+
+			// Shift turns the wheel into a horizontal one, like elsewhere.
+			if win.LOWORD(uint32(wParam))&win.MK_SHIFT != 0 && win.GetWindowLong(sv.hWnd, win.GWL_STYLE)&win.WS_HSCROLL != 0 {
+				sv.scrollByWheel(-sv.wheelScrollPixels(win.SB_HORZ, delta), 0)
 			} else {
-				cmd = win.SB_LINEUP
+				sv.scrollByWheel(0, -sv.wheelScrollPixels(win.SB_VERT, delta))
+			}
+
+			return 0
+
+		case wmMouseHWheel:
+			if win.GetWindowLong(sv.hWnd, win.GWL_STYLE)&win.WS_HSCROLL == 0 {
+				break
 			}
 
-			sv.composite.SetYPixels(sv.scroll(win.SB_VERT, cmd))
-			avoidBGArtifacts()
+			delta := int(int16(win.HIWORD(uint32(wParam))))
+
+			sv.scrollByWheel(sv.wheelScrollPixels(win.SB_HORZ, delta), 0)
 
 			return 0
 
+		case win.WM_TIMER:
+			if wParam == scrollViewSmoothScrollTimerId {
+				sv.smoothScrollStep()
+				return 0
+			}
+
 		case win.WM_COMMAND, win.WM_NOTIFY:
 			sv.composite.WndProc(hwnd, msg, wParam, lParam)
 
@@ -235,29 +619,46 @@ func (sv *ScrollView) updateScrollBars() {
 
 	newCompositeBounds := Rectangle{Width: compositeSize.Width, Height: compositeSize.Height}
 
-	if size != compositeSize {
+	if size != compositeSize || sv.horizontalAlways || sv.verticalAlways {
 		dpi := uint32(sv.DPI())
 
 		vsbw := int(win.GetSystemMetricsForDpi(win.SM_CXVSCROLL, dpi))
 		hsbh := int(win.GetSystemMetricsForDpi(win.SM_CYHSCROLL, dpi))
 
-		if size.Width < compositeSize.Width && size.Height < compositeSize.Height {
+		bothNeeded := size.Width < compositeSize.Width && size.Height < compositeSize.Height
+
+		if bothNeeded || sv.verticalAlways {
 			size.Width -= vsbw
+		}
+		if bothNeeded || sv.horizontalAlways {
 			size.Height -= hsbh
 		}
 	}
 
+	// Scroll bars shown always are disabled instead of hidden.
+	disableNoScroll := func(always bool) uint32 {
+		if always {
+			return win.SIF_DISABLENOSCROLL
+		}
+
+		return 0
+	}
+
+	si.FMask = win.SIF_PAGE | win.SIF_RANGE | disableNoScroll(sv.horizontalAlways)
 	si.NMax = int32(compositeSize.Width - 1)
 	si.NPage = uint32(size.Width)
 	win.SetScrollInfo(sv.hWnd, win.SB_HORZ, &si, false)
 	newCompositeBounds.X = sv.scroll(win.SB_HORZ, win.SB_THUMBPOSITION)
 
+	si.FMask = win.SIF_PAGE | win.SIF_RANGE | disableNoScroll(sv.verticalAlways)
 	si.NMax = int32(compositeSize.Height - 1)
 	si.NPage = uint32(size.Height)
 	win.SetScrollInfo(sv.hWnd, win.SB_VERT, &si, false)
 	newCompositeBounds.Y = sv.scroll(win.SB_VERT, win.SB_THUMBPOSITION)
 
 	sv.composite.SetBoundsPixels(newCompositeBounds)
+
+	sv.publishScrolled()
 }
 
 // scroll scrolls and returns new position in native pixels.
@@ -288,18 +689,7 @@ func (sv *ScrollView) scroll(sb int32, cmd uint16) int {
 		pos = si.NTrackPos
 	}
 
-	if pos < 0 {
-		pos = 0
-	}
-	if pos > si.NMax+1-int32(si.NPage) {
-		pos = si.NMax + 1 - int32(si.NPage)
-	}
-
-	si.FMask = win.SIF_POS
-	si.NPos = pos
-	win.SetScrollInfo(sv.hWnd, sb, &si, true)
-
-	return -int(pos)
+	return -sv.setScrollPos(sb, int(pos))
 }
 
 func (sv *ScrollView) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
@@ -356,6 +746,22 @@ func (sv *ScrollView) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 		}
 	}
 
+	if sv.fitWidth {
+		svli.layoutFlags |= ShrinkableHorz | GrowableHorz
+		svli.minSize.Width = svli.sbSize.Width
+		svli.fitWidth = true
+	}
+
+	dpi := uint32(ctx.dpi)
+	if sv.verticalAlways && v && svli.sbSize.Width == 0 {
+		svli.sbSize.Width = int(win.GetSystemMetricsForDpi(win.SM_CXVSCROLL, dpi))
+		svli.idealSize.Width += svli.sbSize.Width
+	}
+	if sv.horizontalAlways && h && svli.sbSize.Height == 0 {
+		svli.sbSize.Height = int(win.GetSystemMetricsForDpi(win.SM_CYHSCROLL, dpi))
+		svli.idealSize.Height += svli.sbSize.Height
+	}
+
 	var si win.SCROLLINFO
 	si.CbSize = uint32(unsafe.Sizeof(si))
 	si.FMask = win.SIF_POS | win.SIF_RANGE
@@ -377,6 +783,7 @@ type scrollViewLayoutItem struct {
 	layoutFlags LayoutFlags
 	scrollX     float64
 	scrollY     float64
+	fitWidth    bool
 }
 
 func (li *scrollViewLayoutItem) LayoutFlags() LayoutFlags {
@@ -411,7 +818,7 @@ func (li *scrollViewLayoutItem) PerformLayout() []LayoutResultItem {
 	clientSize.Height -= li.sbSize.Height
 
 	minSize := composite.(MinSizeForSizer).MinSizeForSize(clientSize)
-	if hfw, ok := composite.(HeightForWidther); ok && hfw.HasHeightForWidth() {
+	if hfw, ok := composite.(HeightForWidther); ok && hfw.HasHeightForWidth() && !li.fitWidth {
 		if minSize.Height > clientSize.Height {
 			if minSize.Width > clientSize.Width {
 				clientSize.Width = minSize.Width
@@ -428,9 +835,12 @@ func (li *scrollViewLayoutItem) PerformLayout() []LayoutResultItem {
 	}
 
 	s := maxSize(minSize, clientSize)
+	if li.fitWidth {
+		s.Width = clientSize.Width
+	}
 
 	var x, y int
-	if clientSize.Width < minSize.Width {
+	if clientSize.Width < minSize.Width && !li.fitWidth {
 		x = -int(float64(minSize.Width) * li.scrollX)
 	}
 	if clientSize.Height < minSize.Height {
@@ -0,0 +1,345 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShortcutConflictError is returned by ShortcutManager methods if a shortcut
+// is already bound to another command.
+type ShortcutConflictError struct {
+	Shortcut Shortcut
+
+	// Command is the command the shortcut was to be bound to.
+	Command string
+
+	// ConflictingCommand is the command the shortcut is bound to.
+	ConflictingCommand string
+}
+
+func (err *ShortcutConflictError) Error() string {
+	return fmt.Sprintf("shortcut %s of command %q is already bound to command %q", err.Shortcut, err.Command, err.ConflictingCommand)
+}
+
+type shortcutCommand struct {
+	name            string
+	action          *Action
+	defaultShortcut Shortcut
+}
+
+// ShortcutManager maps named commands to the shortcuts of their Actions, which
+// it adds to the ShortcutActions of its window, and lets users rebind them at
+// runtime. Menu items of the Actions display the current bindings.
+//
+// With a settings key, SaveState and RestoreState persist the bindings that
+// differ from the defaults in App().Settings().
+type ShortcutManager struct {
+	window                   Window
+	settingsKey              string
+	commands                 []*shortcutCommand
+	name2Command             map[string]*shortcutCommand
+	shortcutChangedPublisher StringEventPublisher
+}
+
+// NewShortcutManager returns a new ShortcutManager for the shortcuts
+// triggered while window or one of its descendants has the keyboard focus.
+//
+// settingsKey is the key below which the bindings are persisted, or "" if
+// they are not.
+func NewShortcutManager(window Window, settingsKey string) *ShortcutManager {
+	return &ShortcutManager{
+		window:       window,
+		settingsKey:  settingsKey,
+		name2Command: make(map[string]*shortcutCommand),
+	}
+}
+
+// Register registers action as the command name. Its current shortcut becomes
+// the default binding of the command.
+func (sm *ShortcutManager) Register(name string, action *Action) error {
+	if name == "" || strings.ContainsAny(name, "/=") {
+		return newError("invalid command name")
+	}
+	if action == nil {
+		return newError("action must not be nil")
+	}
+	if _, ok := sm.name2Command[name]; ok {
+		return newError(fmt.Sprintf("command %q already registered", name))
+	}
+
+	shortcut := action.Shortcut()
+	if other := sm.commandFor(shortcut); other != nil {
+		return &ShortcutConflictError{shortcut, name, other.name}
+	}
+
+	actions := sm.window.AsWindowBase().ShortcutActions()
+	if !actions.Contains(action) {
+		if err := actions.Add(action); err != nil {
+			return err
+		}
+	}
+
+	cmd := &shortcutCommand{name: name, action: action, defaultShortcut: shortcut}
+	sm.commands = append(sm.commands, cmd)
+	sm.name2Command[name] = cmd
+
+	return nil
+}
+
+// Unregister unregisters the command name and removes its Action from the
+// ShortcutActions of the window.
+func (sm *ShortcutManager) Unregister(name string) error {
+	cmd, ok := sm.name2Command[name]
+	if !ok {
+		return nil
+	}
+
+	delete(sm.name2Command, name)
+	for i, c := range sm.commands {
+		if c == cmd {
+			sm.commands = append(sm.commands[:i], sm.commands[i+1:]...)
+			break
+		}
+	}
+
+	return sm.window.AsWindowBase().ShortcutActions().Remove(cmd.action)
+}
+
+// Commands returns the names of the registered commands, in the order of
+// registration.
+func (sm *ShortcutManager) Commands() []string {
+	names := make([]string, len(sm.commands))
+	for i, cmd := range sm.commands {
+		names[i] = cmd.name
+	}
+
+	return names
+}
+
+// Action returns the Action of the command name, or nil if there is none.
+func (sm *ShortcutManager) Action(name string) *Action {
+	if cmd, ok := sm.name2Command[name]; ok {
+		return cmd.action
+	}
+
+	return nil
+}
+
+// Shortcut returns the shortcut currently bound to the command name.
+func (sm *ShortcutManager) Shortcut(name string) Shortcut {
+	if cmd, ok := sm.name2Command[name]; ok {
+		return cmd.action.Shortcut()
+	}
+
+	return Shortcut{}
+}
+
+// DefaultShortcut returns the shortcut the command name was registered with.
+func (sm *ShortcutManager) DefaultShortcut(name string) Shortcut {
+	if cmd, ok := sm.name2Command[name]; ok {
+		return cmd.defaultShortcut
+	}
+
+	return Shortcut{}
+}
+
+// CommandFor returns the name of the command shortcut is bound to, or "" if
+// there is none, e.g. to warn users about a conflict before rebinding.
+func (sm *ShortcutManager) CommandFor(shortcut Shortcut) string {
+	if cmd := sm.commandFor(shortcut); cmd != nil {
+		return cmd.name
+	}
+
+	return ""
+}
+
+func (sm *ShortcutManager) commandFor(shortcut Shortcut) *shortcutCommand {
+	if shortcut.Key == 0 {
+		return nil
+	}
+
+	for _, cmd := range sm.commands {
+		if cmd.action.Shortcut() == shortcut {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+// Bind binds shortcut to the command name. A zero Shortcut leaves the command
+// without a binding. If shortcut is bound to another command, a
+// *ShortcutConflictError is returned, unless replace is true, in which case
+// the other command loses its binding.
+func (sm *ShortcutManager) Bind(name string, shortcut Shortcut, replace bool) error {
+	cmd, ok := sm.name2Command[name]
+	if !ok {
+		return newError(fmt.Sprintf("unknown command %q", name))
+	}
+
+	if cmd.action.Shortcut() == shortcut {
+		return nil
+	}
+
+	if other := sm.commandFor(shortcut); other != nil {
+		if !replace {
+			return &ShortcutConflictError{shortcut, name, other.name}
+		}
+
+		if err := sm.setShortcut(other, Shortcut{}); err != nil {
+			return err
+		}
+	}
+
+	return sm.setShortcut(cmd, shortcut)
+}
+
+// Reset binds the default shortcut to the command name, taking it from any
+// other command it has been bound to since.
+func (sm *ShortcutManager) Reset(name string) error {
+	cmd, ok := sm.name2Command[name]
+	if !ok {
+		return newError(fmt.Sprintf("unknown command %q", name))
+	}
+
+	return sm.Bind(name, cmd.defaultShortcut, true)
+}
+
+// ResetAll binds the default shortcuts to all commands.
+func (sm *ShortcutManager) ResetAll() error {
+	for _, cmd := range sm.commands {
+		if err := sm.setShortcut(cmd, Shortcut{}); err != nil {
+			return err
+		}
+	}
+
+	for _, cmd := range sm.commands {
+		if err := sm.setShortcut(cmd, cmd.defaultShortcut); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sm *ShortcutManager) setShortcut(cmd *shortcutCommand, shortcut Shortcut) error {
+	if cmd.action.Shortcut() == shortcut {
+		return nil
+	}
+
+	if err := cmd.action.SetShortcut(shortcut); err != nil {
+		return err
+	}
+
+	sm.shortcutChangedPublisher.Publish(cmd.name)
+
+	return nil
+}
+
+// ShortcutChanged returns the event that is published with the name of a
+// command after its binding changed.
+func (sm *ShortcutManager) ShortcutChanged() *StringEvent {
+	return sm.shortcutChangedPublisher.Event()
+}
+
+func (sm *ShortcutManager) settingKey(name string) string {
+	return sm.settingsKey + "/" + name
+}
+
+// SaveState writes the bindings that differ from the defaults to
+// App().Settings(), and removes those of the other commands.
+func (sm *ShortcutManager) SaveState() error {
+	if sm.settingsKey == "" {
+		return nil
+	}
+
+	settings := App().Settings()
+	if settings == nil {
+		return newError("App().Settings() must not be nil")
+	}
+
+	for _, cmd := range sm.commands {
+		key := sm.settingKey(cmd.name)
+
+		shortcut := cmd.action.Shortcut()
+		if shortcut == cmd.defaultShortcut {
+			if _, ok := settings.Get(key); ok {
+				if err := settings.Remove(key); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := settings.Put(key, fmt.Sprint(int(shortcut.Modifiers), int(shortcut.Key))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RestoreState binds the shortcuts saved by SaveState to their commands.
+// Saved bindings that conflict with others are skipped, leaving their
+// commands with the default binding, if that is still free.
+func (sm *ShortcutManager) RestoreState() error {
+	if sm.settingsKey == "" {
+		return nil
+	}
+
+	settings := App().Settings()
+	if settings == nil {
+		return newError("App().Settings() must not be nil")
+	}
+
+	saved := make(map[*shortcutCommand]Shortcut)
+	for _, cmd := range sm.commands {
+		state, ok := settings.Get(sm.settingKey(cmd.name))
+		if !ok || state == "" {
+			continue
+		}
+
+		var mods, key int
+		if _, err := fmt.Sscan(state, &mods, &key); err != nil {
+			continue
+		}
+
+		saved[cmd] = Shortcut{Modifiers(mods), Key(key)}
+	}
+
+	// Unbind first, so saved bindings can swap shortcuts between commands.
+	for _, cmd := range sm.commands {
+		if _, ok := saved[cmd]; ok {
+			if err := sm.setShortcut(cmd, Shortcut{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, cmd := range sm.commands {
+		shortcut, ok := saved[cmd]
+		if !ok {
+			continue
+		}
+
+		if sm.commandFor(shortcut) != nil {
+			shortcut = cmd.defaultShortcut
+			if sm.commandFor(shortcut) != nil {
+				continue
+			}
+		}
+
+		if err := sm.setShortcut(cmd, shortcut); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -9,16 +9,32 @@ package walk
 
 import (
 	"strconv"
+	"unsafe"
 
 	"github.com/miu200521358/win"
 )
 
 type Slider struct {
 	WidgetBase
-	valueChangedPublisher EventPublisher
-	layoutFlags           LayoutFlags
-	tracking              bool
-	persistent            bool
+	valueChangedPublisher         EventPublisher
+	valueChangedDetailedPublisher ValueChangedEventPublisher
+	lastValue                     int // published last
+	layoutFlags                   LayoutFlags
+	tracking                      bool
+	persistent                    bool
+	wrapped                       bool
+	wheelDelta                    int
+	wheelValueMode                WheelValueMode
+	inputSource                   ValueChangeSource
+	valueChangeSource             ValueChangeSource
+	rangeChangedPublisher         EventPublisher
+	buddyLabelsVisible            bool
+	minLabelText                  string
+	maxLabelText                  string
+	rangeEditable                 bool
+	hwndRangeEdit                 win.HWND
+	rangeEditOrigWndProcPtr       uintptr
+	editingMax                    bool
 }
 
 type SliderCfg struct {
@@ -57,6 +73,8 @@ func NewSliderWithCfg(parent Container, cfg *SliderCfg) (*Slider, error) {
 		return nil, err
 	}
 
+	sl.lastValue = sl.Value()
+
 	sl.SetBackground(nullBrushSingleton)
 
 	sl.GraphicsEffects().Add(InteractionEffect)
@@ -72,9 +90,35 @@ func NewSliderWithCfg(parent Container, cfg *SliderCfg) (*Slider, error) {
 		},
 		sl.valueChangedPublisher.Event()))
 
+	sl.MustRegisterProperty("MinValue", NewReadOnlyProperty(
+		func() interface{} {
+			return sl.MinValue()
+		},
+		sl.rangeChangedPublisher.Event()))
+
+	sl.MustRegisterProperty("MaxValue", NewReadOnlyProperty(
+		func() interface{} {
+			return sl.MaxValue()
+		},
+		sl.rangeChangedPublisher.Event()))
+
 	return sl, nil
 }
 
+func (sl *Slider) Dispose() {
+	sl.endRangeEdit(false)
+
+	sl.WidgetBase.Dispose()
+}
+
+func (sl *Slider) applyFont(font *Font) {
+	sl.WidgetBase.applyFont(font)
+
+	if sl.buddyLabelsVisible {
+		sl.updateLabels()
+	}
+}
+
 func (sl *Slider) MinValue() int {
 	return int(sl.SendMessage(win.TBM_GETRANGEMIN, 0, 0))
 }
@@ -84,8 +128,23 @@ func (sl *Slider) MaxValue() int {
 }
 
 func (sl *Slider) SetRange(min, max int) {
+	if min == sl.MinValue() && max == sl.MaxValue() {
+		return
+	}
+
 	sl.SendMessage(win.TBM_SETRANGEMIN, 0, uintptr(min))
 	sl.SendMessage(win.TBM_SETRANGEMAX, 1, uintptr(max))
+
+	if sl.buddyLabelsVisible {
+		sl.updateLabels()
+	}
+
+	sl.rangeChangedPublisher.Publish()
+
+	// The trackbar moves the thumb into the new range without notifying.
+	if sl.Value() != sl.lastValue {
+		sl.publishValueChanged(ValueChangeSourceProgrammatic)
+	}
 }
 
 func (sl *Slider) Value() int {
@@ -93,8 +152,28 @@ func (sl *Slider) Value() int {
 }
 
 func (sl *Slider) SetValue(value int) {
+	sl.setValue(value, ValueChangeSourceProgrammatic)
+}
+
+func (sl *Slider) setValue(value int, source ValueChangeSource) {
+	if sl.wrapped {
+		value = int(wrapValue(float64(value), float64(sl.MinValue()), float64(sl.MaxValue())))
+	}
+
 	sl.SendMessage(win.TBM_SETPOS, 1, uintptr(value))
+	sl.publishValueChanged(source)
+}
+
+func (sl *Slider) publishValueChanged(source ValueChangeSource) {
+	sl.valueChangeSource = source
 	sl.valueChangedPublisher.Publish()
+
+	if value := sl.Value(); value != sl.lastValue {
+		oldValue := sl.lastValue
+		sl.lastValue = value
+
+		sl.valueChangedDetailedPublisher.Publish(ValueChange{OldValue: oldValue, NewValue: value, Source: source})
+	}
 }
 
 // ValueChanged returns an Event that can be used to track changes to Value.
@@ -102,6 +181,32 @@ func (sl *Slider) ValueChanged() *Event {
 	return sl.valueChangedPublisher.Event()
 }
 
+// ValueChangedDetailed returns an Event that is published along with
+// ValueChanged if Value actually changed, with the old and new int value and
+// how it was changed, e.g. to record changes for undo.
+func (sl *Slider) ValueChangedDetailed() *ValueChangedEvent {
+	return sl.valueChangedDetailedPublisher.Event()
+}
+
+// ValueChangeSource returns how Value was changed last, e.g. to tell
+// accidental changes by the mouse wheel from others while ValueChanged is
+// published.
+func (sl *Slider) ValueChangeSource() ValueChangeSource {
+	return sl.valueChangeSource
+}
+
+// WheelValueMode returns whether the mouse wheel changes Value.
+func (sl *Slider) WheelValueMode() WheelValueMode {
+	return sl.wheelValueMode
+}
+
+// SetWheelValueMode sets whether the mouse wheel changes Value.
+// WheelValueDefault uses App().WheelValueMode().
+func (sl *Slider) SetWheelValueMode(mode WheelValueMode) {
+	sl.wheelValueMode = mode
+	sl.wheelDelta = 0
+}
+
 func (sl *Slider) Persistent() bool {
 	return sl.persistent
 }
@@ -154,19 +259,121 @@ func (sl *Slider) SetTracking(tracking bool) {
 	sl.tracking = tracking
 }
 
+// Wrapped returns if the value of the Slider wraps around, instead of stopping
+// at MinValue and MaxValue, when changed by keyboard or mouse wheel.
+func (sl *Slider) Wrapped() bool {
+	return sl.wrapped
+}
+
+// SetWrapped sets if the value of the Slider wraps around, instead of stopping
+// at MinValue and MaxValue, when changed by keyboard or mouse wheel, e.g. for
+// angles from 0 to 360.
+func (sl *Slider) SetWrapped(wrapped bool) {
+	sl.wrapped = wrapped
+	sl.wheelDelta = 0
+}
+
+// keyDelta returns by how much the trackbar would move for key.
+func (sl *Slider) keyDelta(key Key) (int, bool) {
+	switch key {
+	case KeyLeft, KeyUp:
+		return -sl.LineSize(), true
+
+	case KeyRight, KeyDown:
+		return sl.LineSize(), true
+
+	case KeyPrior:
+		return -sl.PageSize(), true
+
+	case KeyNext:
+		return sl.PageSize(), true
+	}
+
+	return 0, false
+}
+
 func (sl *Slider) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	switch msg {
 	case win.WM_HSCROLL, win.WM_VSCROLL:
 		switch win.LOWORD(uint32(wParam)) {
 		case win.TB_THUMBPOSITION, win.TB_ENDTRACK:
-			sl.valueChangedPublisher.Publish()
+			sl.publishValueChanged(sl.inputSource)
 
 		case win.TB_THUMBTRACK:
-			if sl.tracking {
-				sl.valueChangedPublisher.Publish()
+			// With AutoSubmit, bound data follows the thumb as it is dragged.
+			if sl.tracking || sl.autoSubmit {
+				sl.publishValueChanged(ValueChangeSourceMouse)
 			}
 		}
 		return 0
+
+	case win.WM_KEYDOWN:
+		// The trackbar reports the change by WM_HSCROLL or WM_VSCROLL.
+		sl.inputSource = ValueChangeSourceKeyboard
+
+		// The trackbar stops at the ends of the range by itself.
+		if sl.wrapped {
+			if delta, ok := sl.keyDelta(Key(wParam)); ok {
+				sl.setValue(sl.Value()+delta, ValueChangeSourceKeyboard)
+				return 0
+			}
+		}
+
+	case win.WM_LBUTTONDOWN:
+		sl.inputSource = ValueChangeSourceMouse
+
+	case win.WM_NCCALCSIZE:
+		if sl.buddyLabelsVisible {
+			// With wParam TRUE, the NCCALCSIZE_PARAMS start with the RECT.
+			result := sl.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+			sl.onNCCalcSize((*win.RECT)(unsafe.Pointer(lParam)))
+			return result
+		}
+
+	case win.WM_NCPAINT:
+		if sl.buddyLabelsVisible {
+			result := sl.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+			sl.paintLabels()
+			return result
+		}
+
+	case win.WM_NCHITTEST:
+		if _, ok := sl.labelAt(win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}); ok {
+			return win.HTBORDER
+		}
+
+	case win.WM_NCLBUTTONDOWN:
+		if sl.rangeEditable && wParam == win.HTBORDER {
+			if max, ok := sl.labelAt(win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}); ok {
+				sl.beginRangeEdit(max)
+				return 0
+			}
+		}
+
+	case win.WM_ENABLE:
+		if sl.buddyLabelsVisible {
+			win.RedrawWindow(hwnd, nil, 0, win.RDW_FRAME|win.RDW_INVALIDATE)
+		}
+
+	case win.WM_MOUSEWHEEL:
+		if !wheelChangesValue(sl.wheelValueMode, sl.Focused()) {
+			// Bypass the trackbar, so the wheel goes to the parent.
+			return win.DefWindowProc(hwnd, msg, wParam, lParam)
+		}
+
+		sl.inputSource = ValueChangeSourceWheel
+
+		if sl.wrapped {
+			sl.wheelDelta += int(int16(win.HIWORD(uint32(wParam))))
+			notches := sl.wheelDelta / 120
+			sl.wheelDelta -= notches * 120
+
+			// Like the trackbar, move towards MinValue when rotated forward.
+			if notches != 0 {
+				sl.setValue(sl.Value()-notches*sl.LineSize(), ValueChangeSourceWheel)
+			}
+			return 0
+		}
 	}
 	return sl.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
 }
@@ -176,9 +383,19 @@ func (*Slider) NeedsWmSize() bool {
 }
 
 func (sl *Slider) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	idealSize := sl.dialogBaseUnitsToPixels(Size{15, 15})
+
+	// The buddy labels are in the non-client area.
+	minSize, maxSize := sl.labelSizes()
+	if sl.vertical() {
+		idealSize.Height += minSize + maxSize
+	} else {
+		idealSize.Width += minSize + maxSize
+	}
+
 	return &sliderLayoutItem{
 		layoutFlags: sl.layoutFlags,
-		idealSize:   sl.dialogBaseUnitsToPixels(Size{15, 15}),
+		idealSize:   idealSize,
 	}
 }
 
@@ -0,0 +1,425 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// sliderLabelPadding is the space around the texts of the buddy labels of a
+// Slider, in 1/96".
+const sliderLabelPadding = 4
+
+var (
+	getWindowDC = syscall.NewLazyDLL("user32.dll").NewProc("GetWindowDC")
+
+	sliderRangeEditWndProcPtr uintptr
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		sliderRangeEditWndProcPtr = syscall.NewCallback(sliderRangeEditWndProc)
+	})
+}
+
+// BuddyLabelsVisible returns if labels at the ends of the Slider show the
+// minimum and maximum values.
+func (sl *Slider) BuddyLabelsVisible() bool {
+	return sl.buddyLabelsVisible
+}
+
+// SetBuddyLabelsVisible sets if labels at the ends of the Slider show the
+// minimum and maximum values, or the texts set with SetMinLabelText and
+// SetMaxLabelText.
+func (sl *Slider) SetBuddyLabelsVisible(visible bool) {
+	if visible == sl.buddyLabelsVisible {
+		return
+	}
+
+	sl.buddyLabelsVisible = visible
+
+	if !visible {
+		sl.endRangeEdit(false)
+	}
+
+	sl.updateLabels()
+}
+
+// MinLabelText returns the text of the label at the minimum end of the Slider.
+// An empty text means the label shows the minimum value.
+func (sl *Slider) MinLabelText() string {
+	return sl.minLabelText
+}
+
+// SetMinLabelText sets the text of the label at the minimum end of the
+// Slider. An empty text makes the label show the minimum value.
+func (sl *Slider) SetMinLabelText(text string) {
+	sl.minLabelText = text
+
+	sl.updateLabels()
+}
+
+// MaxLabelText returns the text of the label at the maximum end of the Slider.
+// An empty text means the label shows the maximum value.
+func (sl *Slider) MaxLabelText() string {
+	return sl.maxLabelText
+}
+
+// SetMaxLabelText sets the text of the label at the maximum end of the
+// Slider. An empty text makes the label show the maximum value.
+func (sl *Slider) SetMaxLabelText(text string) {
+	sl.maxLabelText = text
+
+	sl.updateLabels()
+}
+
+// RangeEditable returns if the user can change the minimum and maximum values
+// by clicking the buddy labels.
+func (sl *Slider) RangeEditable() bool {
+	return sl.rangeEditable
+}
+
+// SetRangeEditable sets if the user can change the minimum and maximum values
+// by clicking the buddy labels, which show an edit box for the value then.
+// Enter or leaving the edit box applies the value, Esc discards it.
+func (sl *Slider) SetRangeEditable(editable bool) {
+	sl.rangeEditable = editable
+
+	if !editable {
+		sl.endRangeEdit(false)
+	}
+}
+
+// RangeChanged returns an Event that can be used to track changes to MinValue
+// and MaxValue.
+func (sl *Slider) RangeChanged() *Event {
+	return sl.rangeChangedPublisher.Event()
+}
+
+func (sl *Slider) vertical() bool {
+	return sl.hasStyleBits(win.TBS_VERT)
+}
+
+func (sl *Slider) labelText(max bool) string {
+	if max {
+		if sl.maxLabelText != "" {
+			return sl.maxLabelText
+		}
+
+		return strconv.Itoa(sl.MaxValue())
+	}
+
+	if sl.minLabelText != "" {
+		return sl.minLabelText
+	}
+
+	return strconv.Itoa(sl.MinValue())
+}
+
+// labelSizes returns the widths of the buddy labels of a horizontal Slider, or
+// the heights of those of a vertical one, in native pixels.
+func (sl *Slider) labelSizes() (min, max int) {
+	if !sl.buddyLabelsVisible {
+		return 0, 0
+	}
+
+	hdc := win.GetDC(sl.hWnd)
+	if hdc == 0 {
+		return 0, 0
+	}
+	defer win.ReleaseDC(sl.hWnd, hdc)
+
+	defer win.SelectObject(hdc, win.SelectObject(hdc, win.HGDIOBJ(sl.Font().handleForDPI(sl.DPI()))))
+
+	measure := func(text string) int {
+		s := syscall.StringToUTF16(text)
+
+		var size win.SIZE
+		win.GetTextExtentPoint32(hdc, &s[0], int32(len(s)-1), &size)
+
+		if sl.vertical() {
+			return int(size.CY)
+		}
+
+		return int(size.CX)
+	}
+
+	padding := 2 * sl.IntFrom96DPI(sliderLabelPadding)
+
+	return measure(sl.labelText(false)) + padding, measure(sl.labelText(true)) + padding
+}
+
+// labelRect returns the bounds of a buddy label in client coordinates of the
+// trackbar, which are outside of its client area.
+func (sl *Slider) labelRect(max bool) win.RECT {
+	var cr win.RECT
+	win.GetClientRect(sl.hWnd, &cr)
+
+	minSize, maxSize := sl.labelSizes()
+
+	if sl.vertical() {
+		if max {
+			return win.RECT{Top: cr.Bottom, Right: cr.Right, Bottom: cr.Bottom + int32(maxSize)}
+		}
+
+		return win.RECT{Top: -int32(minSize), Right: cr.Right}
+	}
+
+	if max {
+		return win.RECT{Left: cr.Right, Right: cr.Right + int32(maxSize), Bottom: cr.Bottom}
+	}
+
+	return win.RECT{Left: -int32(minSize), Bottom: cr.Bottom}
+}
+
+// updateLabels makes the trackbar measure its non-client area, where the
+// buddy labels are, again.
+func (sl *Slider) updateLabels() {
+	win.SetWindowPos(sl.hWnd, 0, 0, 0, 0, 0, win.SWP_FRAMECHANGED|win.SWP_NOMOVE|win.SWP_NOSIZE|win.SWP_NOZORDER|win.SWP_NOACTIVATE)
+	win.RedrawWindow(sl.hWnd, nil, 0, win.RDW_FRAME|win.RDW_INVALIDATE)
+
+	sl.RequestLayout()
+}
+
+func (sl *Slider) onNCCalcSize(rc *win.RECT) {
+	minSize, maxSize := sl.labelSizes()
+
+	if sl.vertical() {
+		rc.Top += int32(minSize)
+		rc.Bottom -= int32(maxSize)
+	} else if sl.hasExtendedStyleBits(win.WS_EX_LAYOUTRTL) {
+		// The rectangle is in screen coordinates, which are not mirrored.
+		rc.Left += int32(maxSize)
+		rc.Right -= int32(minSize)
+	} else {
+		rc.Left += int32(minSize)
+		rc.Right -= int32(maxSize)
+	}
+
+	if rc.Right < rc.Left {
+		rc.Right = rc.Left
+	}
+	if rc.Bottom < rc.Top {
+		rc.Bottom = rc.Top
+	}
+}
+
+func (sl *Slider) paintLabels() {
+	if !sl.buddyLabelsVisible {
+		return
+	}
+
+	h, _, _ := getWindowDC.Call(uintptr(sl.hWnd))
+	hdc := win.HDC(h)
+	if hdc == 0 {
+		return
+	}
+	defer win.ReleaseDC(sl.hWnd, hdc)
+
+	// Window coordinates start at the minimum label.
+	minSize, _ := sl.labelSizes()
+	offset := win.POINT{X: int32(minSize)}
+	if sl.vertical() {
+		offset = win.POINT{Y: int32(minSize)}
+	}
+
+	bg, wnd := sl.backgroundEffective()
+	if bg == nil {
+		bg, wnd = sysColorBtnFaceBrush, sl
+	}
+	sl.prepareDCForBackground(hdc, sl.hWnd, wnd)
+
+	defer win.SelectObject(hdc, win.SelectObject(hdc, win.HGDIOBJ(sl.Font().handleForDPI(sl.DPI()))))
+
+	textColor := win.COLOR_BTNTEXT
+	if !sl.Enabled() {
+		textColor = win.COLOR_GRAYTEXT
+	}
+	win.SetTextColor(hdc, win.COLORREF(win.GetSysColor(textColor)))
+	win.SetBkMode(hdc, win.TRANSPARENT)
+
+	for _, max := range []bool{false, true} {
+		rc := sl.labelRect(max)
+		rc.Left += offset.X
+		rc.Right += offset.X
+		rc.Top += offset.Y
+		rc.Bottom += offset.Y
+
+		hRgn := win.CreateRectRgn(rc.Left, rc.Top, rc.Right, rc.Bottom)
+		win.FillRgn(hdc, hRgn, bg.handle())
+		win.DeleteObject(win.HGDIOBJ(hRgn))
+
+		if sl.editingMax == max && sl.hwndRangeEdit != 0 {
+			continue
+		}
+
+		text := syscall.StringToUTF16(sl.labelText(max))
+		win.DrawTextEx(hdc, &text[0], int32(len(text)-1), &rc, win.DT_CENTER|win.DT_VCENTER|win.DT_SINGLELINE|win.DT_NOPREFIX, nil)
+	}
+}
+
+// labelAt returns which buddy label contains pt, which is expected in screen
+// coordinates.
+func (sl *Slider) labelAt(pt win.POINT) (max, ok bool) {
+	if !sl.buddyLabelsVisible {
+		return false, false
+	}
+
+	win.ScreenToClient(sl.hWnd, &pt)
+
+	if rectContainsPOINT(sl.labelRect(false), pt) {
+		return false, true
+	}
+	if rectContainsPOINT(sl.labelRect(true), pt) {
+		return true, true
+	}
+
+	return false, false
+}
+
+// beginRangeEdit shows an edit box over a buddy label, for changing the
+// minimum or maximum value.
+func (sl *Slider) beginRangeEdit(max bool) {
+	sl.endRangeEdit(true)
+
+	hwndParent := win.GetParent(sl.hWnd)
+
+	// The edit box is a plain sibling of the trackbar, so it doesn't take part
+	// in layout.
+	rc := sl.labelRect(max)
+	pts := []win.POINT{{X: rc.Left, Y: rc.Top}, {X: rc.Right, Y: rc.Bottom}}
+	for i := range pts {
+		win.ClientToScreen(sl.hWnd, &pts[i])
+		win.ScreenToClient(hwndParent, &pts[i])
+	}
+	left, right := minInt32(pts[0].X, pts[1].X), maxInt32(pts[0].X, pts[1].X)
+
+	// The label may be too small for editing.
+	minWidth := int32(sl.IntFrom96DPI(40))
+	if right-left < minWidth {
+		if max {
+			right = left + minWidth
+		} else {
+			left = right - minWidth
+		}
+	}
+
+	value := sl.MinValue()
+	if max {
+		value = sl.MaxValue()
+	}
+
+	sl.hwndRangeEdit = win.CreateWindowEx(
+		win.WS_EX_CLIENTEDGE,
+		syscall.StringToUTF16Ptr("EDIT"),
+		syscall.StringToUTF16Ptr(strconv.Itoa(value)),
+		win.WS_CHILD|win.WS_VISIBLE|win.ES_AUTOHSCROLL|win.ES_CENTER,
+		left,
+		pts[0].Y,
+		right-left,
+		pts[1].Y-pts[0].Y,
+		hwndParent,
+		0,
+		0,
+		nil)
+	if sl.hwndRangeEdit == 0 {
+		return
+	}
+
+	sl.editingMax = max
+
+	win.SendMessage(sl.hwndRangeEdit, win.WM_SETFONT, uintptr(sl.Font().handleForDPI(sl.DPI())), 0)
+
+	win.SetWindowLongPtr(sl.hwndRangeEdit, win.GWLP_USERDATA, uintptr(unsafe.Pointer(sl)))
+	sl.rangeEditOrigWndProcPtr = win.SetWindowLongPtr(sl.hwndRangeEdit, win.GWLP_WNDPROC, sliderRangeEditWndProcPtr)
+
+	win.SetWindowPos(sl.hwndRangeEdit, win.HWND_TOP, 0, 0, 0, 0, win.SWP_NOMOVE|win.SWP_NOSIZE)
+	win.SetFocus(sl.hwndRangeEdit)
+	win.SendMessage(sl.hwndRangeEdit, win.EM_SETSEL, 0, ^uintptr(0))
+
+	win.RedrawWindow(sl.hWnd, nil, 0, win.RDW_FRAME|win.RDW_INVALIDATE)
+}
+
+// endRangeEdit removes the edit box of a buddy label, applying the value if
+// apply is true and it is valid.
+func (sl *Slider) endRangeEdit(apply bool) {
+	hwnd := sl.hwndRangeEdit
+	if hwnd == 0 {
+		return
+	}
+
+	sl.hwndRangeEdit = 0
+
+	if apply {
+		buf := make([]uint16, win.SendMessage(hwnd, win.WM_GETTEXTLENGTH, 0, 0)+1)
+		win.SendMessage(hwnd, win.WM_GETTEXT, uintptr(len(buf)), uintptr(unsafe.Pointer(&buf[0])))
+
+		if value, err := strconv.Atoi(strings.TrimSpace(syscall.UTF16ToString(buf))); err == nil {
+			min, max := sl.MinValue(), sl.MaxValue()
+			if sl.editingMax {
+				max = value
+			} else {
+				min = value
+			}
+
+			if min < max {
+				sl.SetRange(min, max)
+			}
+		}
+	}
+
+	if win.GetFocus() == hwnd {
+		win.SetFocus(sl.hWnd)
+	}
+
+	// The edit box may be processing a message, so it must be destroyed later.
+	sl.Synchronize(func() {
+		win.DestroyWindow(hwnd)
+	})
+
+	win.RedrawWindow(sl.hWnd, nil, 0, win.RDW_FRAME|win.RDW_INVALIDATE)
+}
+
+func sliderRangeEditWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	sl := (*Slider)(unsafe.Pointer(win.GetWindowLongPtr(hwnd, win.GWLP_USERDATA)))
+	origWndProcPtr := sl.rangeEditOrigWndProcPtr
+
+	if hwnd == sl.hwndRangeEdit {
+		switch msg {
+		case win.WM_GETDLGCODE:
+			return win.DLGC_WANTALLKEYS
+
+		case win.WM_KEYDOWN:
+			switch Key(wParam) {
+			case KeyReturn:
+				sl.endRangeEdit(true)
+				return 0
+
+			case KeyEscape:
+				sl.endRangeEdit(false)
+				return 0
+			}
+
+		case win.WM_CHAR:
+			// Avoid the beeps for Enter and Esc.
+			if wParam == '\r' || wParam == 27 {
+				return 0
+			}
+
+		case win.WM_KILLFOCUS:
+			sl.endRangeEdit(true)
+		}
+	}
+
+	return win.CallWindowProc(origWndProcPtr, hwnd, msg, wParam, lParam)
+}
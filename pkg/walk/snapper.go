@@ -0,0 +1,279 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+
+	"github.com/miu200521358/win"
+)
+
+// SnapKind specifies what a SnapLine stems from.
+type SnapKind int
+
+const (
+	SnapGrid SnapKind = iota
+	SnapGuide
+	SnapEdge
+)
+
+// SnapLine is a line something snapped to. A Vertical line is located at x
+// = Position, a Horizontal one at y = Position.
+type SnapLine struct {
+	Orientation Orientation
+	Position    float64
+	Kind        SnapKind
+}
+
+// Snapper makes dragged positions snap to a grid, to guide lines and to the
+// edges and centers of neighboring objects, once they come within its
+// tolerance.
+//
+// A Snapper works in whatever coordinates its caller uses, e.g. scene units
+// for a SceneView or native pixels for a Splitter. The same Snapper can be
+// shared by several widgets, so drags feel the same across them.
+type Snapper struct {
+	disabled  bool
+	gridSize  float64
+	tolerance float64
+	guides    []SnapLine
+	targets   []SceneRect
+}
+
+// NewSnapper returns a new Snapper with a tolerance of 8 and neither grid
+// nor guides.
+func NewSnapper() *Snapper {
+	return &Snapper{tolerance: 8}
+}
+
+// Enabled returns whether the Snapper snaps.
+func (s *Snapper) Enabled() bool {
+	return !s.disabled
+}
+
+// SetEnabled sets whether the Snapper snaps.
+func (s *Snapper) SetEnabled(enabled bool) {
+	s.disabled = !enabled
+}
+
+// GridSize returns the spacing of the grid, 0 means there is no grid.
+func (s *Snapper) GridSize() float64 {
+	return s.gridSize
+}
+
+// SetGridSize sets the spacing of the grid, 0 means there is no grid.
+func (s *Snapper) SetGridSize(size float64) error {
+	if size < 0 || math.IsNaN(size) || math.IsInf(size, 0) {
+		return newError("invalid grid size")
+	}
+
+	s.gridSize = size
+
+	return nil
+}
+
+// Tolerance returns the distance within which positions snap.
+func (s *Snapper) Tolerance() float64 {
+	return s.tolerance
+}
+
+// SetTolerance sets the distance within which positions snap.
+func (s *Snapper) SetTolerance(tolerance float64) error {
+	if tolerance < 0 || math.IsNaN(tolerance) {
+		return newError("invalid tolerance")
+	}
+
+	s.tolerance = tolerance
+
+	return nil
+}
+
+// Guides returns the guide lines of the Snapper.
+func (s *Snapper) Guides() []SnapLine {
+	return append([]SnapLine(nil), s.guides...)
+}
+
+// SetGuides sets the guide lines of the Snapper. The Kind of the lines is
+// ignored.
+func (s *Snapper) SetGuides(guides []SnapLine) {
+	s.guides = s.guides[:0]
+
+	for _, g := range guides {
+		s.AddGuide(g.Orientation, g.Position)
+	}
+}
+
+// AddGuide adds a guide line with the specified orientation at position.
+func (s *Snapper) AddGuide(orientation Orientation, position float64) {
+	s.guides = append(s.guides, SnapLine{orientation, position, SnapGuide})
+}
+
+// ClearGuides removes all guide lines.
+func (s *Snapper) ClearGuides() {
+	s.guides = nil
+}
+
+// SetTargets sets the bounds of the neighboring objects whose edges and
+// centers are snapped to. Callers typically set them when a drag starts,
+// excluding what is being dragged.
+func (s *Snapper) SetTargets(targets []SceneRect) {
+	s.targets = append(s.targets[:0], targets...)
+}
+
+// snapper1D finds the nearest snap position along a single axis.
+type snapper1D struct {
+	s           *Snapper
+	orientation Orientation // of the lines snapped to
+	best        float64
+	delta       float64
+	line        SnapLine
+	found       bool
+}
+
+func (s *Snapper) newSnapper1D(orientation Orientation) *snapper1D {
+	return &snapper1D{s: s, orientation: orientation, best: s.tolerance}
+}
+
+func (sd *snapper1D) consider(value, position float64, kind SnapKind) {
+	if d := math.Abs(position - value); d < sd.best || !sd.found && d == sd.best {
+		sd.best = d
+		sd.delta = position - value
+		sd.line = SnapLine{sd.orientation, position, kind}
+		sd.found = true
+	}
+}
+
+// guidesAndEdges considers the guides and target edges for value.
+func (sd *snapper1D) guidesAndEdges(value float64) {
+	for _, g := range sd.s.guides {
+		if g.Orientation == sd.orientation {
+			sd.consider(value, g.Position, SnapGuide)
+		}
+	}
+
+	for _, t := range sd.s.targets {
+		start, length := t.X, t.Width
+		if sd.orientation == Horizontal {
+			start, length = t.Y, t.Height
+		}
+
+		sd.consider(value, start, SnapEdge)
+		sd.consider(value, start+length/2, SnapEdge)
+		sd.consider(value, start+length, SnapEdge)
+	}
+}
+
+func (sd *snapper1D) grid(value float64) {
+	if g := sd.s.gridSize; g > 0 {
+		sd.consider(value, math.Round(value/g)*g, SnapGrid)
+	}
+}
+
+func (s *Snapper) snap(orientation Orientation, value float64) (float64, []SnapLine) {
+	if s.disabled {
+		return value, nil
+	}
+
+	sd := s.newSnapper1D(orientation)
+	sd.guidesAndEdges(value)
+	sd.grid(value)
+
+	if !sd.found {
+		return value, nil
+	}
+
+	return value + sd.delta, []SnapLine{sd.line}
+}
+
+// SnapX returns x snapped to vertical lines.
+func (s *Snapper) SnapX(x float64) float64 {
+	x, _ = s.snap(Vertical, x)
+	return x
+}
+
+// SnapY returns y snapped to horizontal lines.
+func (s *Snapper) SnapY(y float64) float64 {
+	y, _ = s.snap(Horizontal, y)
+	return y
+}
+
+// SnapPoint returns p snapped in both directions, together with the lines it
+// snapped to.
+func (s *Snapper) SnapPoint(p ScenePoint) (ScenePoint, []SnapLine) {
+	x, xLines := s.snap(Vertical, p.X)
+	y, yLines := s.snap(Horizontal, p.Y)
+
+	return ScenePoint{x, y}, append(xLines, yLines...)
+}
+
+// SnapRect returns r moved so that one of its edges or its center snaps in
+// each direction, together with the lines it snapped to.
+//
+// Only the upper left corner of r snaps to the grid.
+func (s *Snapper) SnapRect(r SceneRect) (SceneRect, []SnapLine) {
+	if s.disabled {
+		return r, nil
+	}
+
+	var lines []SnapLine
+
+	for _, orientation := range []Orientation{Vertical, Horizontal} {
+		start, length := r.X, r.Width
+		if orientation == Horizontal {
+			start, length = r.Y, r.Height
+		}
+
+		sd := s.newSnapper1D(orientation)
+		sd.guidesAndEdges(start)
+		sd.guidesAndEdges(start + length/2)
+		sd.guidesAndEdges(start + length)
+		sd.grid(start)
+
+		if !sd.found {
+			continue
+		}
+
+		if orientation == Vertical {
+			r.X += sd.delta
+		} else {
+			r.Y += sd.delta
+		}
+
+		lines = append(lines, sd.line)
+	}
+
+	return r, lines
+}
+
+// DrawSnapLinePixels draws line across bounds at position, in native pixels
+// along the axis the line is perpendicular to.
+//
+// Guides are drawn solid, edges dotted in the highlight color and grid lines
+// dotted in the gray text color.
+func DrawSnapLinePixels(canvas *Canvas, bounds Rectangle, line SnapLine, position int) error {
+	style, sysColor := PenDot, win.COLOR_HIGHLIGHT
+	switch line.Kind {
+	case SnapGuide:
+		style = PenSolid
+
+	case SnapGrid:
+		sysColor = win.COLOR_GRAYTEXT
+	}
+
+	pen, err := NewCosmeticPen(style, Color(win.GetSysColor(sysColor)))
+	if err != nil {
+		return err
+	}
+	defer pen.Dispose()
+
+	if line.Orientation == Vertical {
+		return canvas.DrawLinePixels(pen, Point{position, bounds.Y}, Point{position, bounds.Y + bounds.Height})
+	}
+
+	return canvas.DrawLinePixels(pen, Point{bounds.X, position}, Point{bounds.X + bounds.Width, position})
+}
@@ -0,0 +1,255 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// SnapshotTheme specifies how the windows of a form are themed while
+// RenderSnapshots renders it.
+type SnapshotTheme int
+
+const (
+	// SnapshotThemeDefault renders with the visual styles that are in effect.
+	SnapshotThemeDefault SnapshotTheme = iota
+
+	// SnapshotThemeClassic renders with visual styles turned off, as with the
+	// classic or high contrast themes.
+	SnapshotThemeClassic
+)
+
+func (t SnapshotTheme) String() string {
+	switch t {
+	case SnapshotThemeDefault:
+		return "default"
+
+	case SnapshotThemeClassic:
+		return "classic"
+	}
+
+	return fmt.Sprintf("theme%d", int(t))
+}
+
+// SnapshotOptions specify the variants RenderSnapshots renders.
+type SnapshotOptions struct {
+	// DPIs to render at. Empty means the current DPI of the form.
+	DPIs []int
+
+	// Themes to render with. Empty means SnapshotThemeDefault.
+	Themes []SnapshotTheme
+}
+
+// Snapshot is the client area of a form, rendered at a DPI with a theme.
+type Snapshot struct {
+	DPI    int
+	Theme  SnapshotTheme
+	Bitmap *Bitmap
+}
+
+// Name returns a name for the variant of the Snapshot, e.g. "144dpi-classic",
+// for use in the file names of golden images.
+func (s Snapshot) Name() string {
+	return fmt.Sprintf("%ddpi-%s", s.DPI, s.Theme)
+}
+
+var (
+	// snapshotRoot and snapshotDPI override the DPI of the windows of the form
+	// RenderSnapshots is rendering.
+	snapshotRoot win.HWND
+	snapshotDPI  int
+
+	// windowThemeAppNames holds the application names passed to
+	// SetWindowTheme, so themes can be restored after rendering without
+	// visual styles.
+	windowThemeAppNames = make(map[win.HWND]string)
+)
+
+// dpiForWindow returns the DPI of the window with handle hwnd.
+func dpiForWindow(hwnd win.HWND) int {
+	if snapshotDPI != 0 && (hwnd == snapshotRoot || win.GetAncestor(hwnd, win.GA_ROOT) == snapshotRoot) {
+		return snapshotDPI
+	}
+
+	return int(win.GetDpiForWindow(hwnd))
+}
+
+func setWindowTheme(hwnd win.HWND, appName string) win.HRESULT {
+	if themeDark() {
+		// The dark variant is used until the theme switches back.
+		windowThemeAppNames[hwnd] = appName
+		applyWindowThemeAppName(hwnd)
+
+		return win.S_OK
+	}
+
+	hr := win.SetWindowTheme(hwnd, syscall.StringToUTF16Ptr(appName), nil)
+	if !win.FAILED(hr) {
+		windowThemeAppNames[hwnd] = appName
+	}
+
+	return hr
+}
+
+// RenderSnapshots renders the client area of form offscreen at each
+// combination of the DPIs and themes of opts, and returns the bitmaps, e.g.
+// for comparison against golden images in tests. The caller must dispose the
+// bitmaps.
+//
+// The form is laid out anew for each variant without being activated, and is
+// restored afterwards. Controls that don't support WM_PRINT may be missing
+// from the bitmaps.
+func RenderSnapshots(form Form, opts SnapshotOptions) (snapshots []Snapshot, err error) {
+	fb := form.AsFormBase()
+
+	if snapshotDPI != 0 {
+		return nil, newError("RenderSnapshots is not reentrant")
+	}
+
+	dpis := opts.DPIs
+	if len(dpis) == 0 {
+		dpis = []int{fb.DPI()}
+	}
+	themes := opts.Themes
+	if len(themes) == 0 {
+		themes = []SnapshotTheme{SnapshotThemeDefault}
+	}
+
+	originalDPI := fb.DPI()
+	originalBounds := fb.BoundsPixels()
+	wasVisible := fb.Visible()
+
+	defer func() {
+		if err != nil {
+			for _, s := range snapshots {
+				s.Bitmap.Dispose()
+			}
+			snapshots = nil
+		}
+	}()
+
+	// Windows are only laid out and painted while visible.
+	if !wasVisible {
+		win.SetWindowPos(fb.hWnd, 0, -32000, -32000, 0, 0, win.SWP_NOACTIVATE|win.SWP_NOSIZE|win.SWP_NOZORDER|win.SWP_SHOWWINDOW)
+	}
+
+	snapshotRoot = fb.hWnd
+	defer func() {
+		fb.applySnapshotTheme(SnapshotThemeDefault)
+
+		dpi := fb.DPI()
+		snapshotDPI = 0
+		snapshotRoot = 0
+
+		fb.applySnapshotDPI(dpi, originalDPI)
+
+		if !wasVisible {
+			win.ShowWindow(fb.hWnd, win.SW_HIDE)
+		}
+		fb.SetBoundsPixels(originalBounds)
+	}()
+
+	dpi := originalDPI
+	for _, newDPI := range dpis {
+		snapshotDPI = newDPI
+		fb.applySnapshotDPI(dpi, newDPI)
+		dpi = newDPI
+
+		for _, theme := range themes {
+			fb.applySnapshotTheme(theme)
+			fb.layoutNow()
+
+			hBmp, err := hBitmapFromWindowClient(fb.window)
+			if err != nil {
+				return snapshots, err
+			}
+
+			bmp, err := newBitmapFromHBITMAP(hBmp, dpi)
+			if err != nil {
+				win.DeleteObject(win.HGDIOBJ(hBmp))
+				return snapshots, err
+			}
+
+			snapshots = append(snapshots, Snapshot{DPI: dpi, Theme: theme, Bitmap: bmp})
+		}
+	}
+
+	return snapshots, nil
+}
+
+// applySnapshotDPI makes the form apply newDPI instead of dpi, as if it was
+// moved to a monitor with that DPI.
+func (fb *FormBase) applySnapshotDPI(dpi, newDPI int) {
+	if newDPI == dpi {
+		return
+	}
+
+	bounds := fb.BoundsPixels()
+	scale := float64(newDPI) / float64(dpi)
+
+	rc := win.RECT{
+		Left:   int32(bounds.X),
+		Top:    int32(bounds.Y),
+		Right:  int32(bounds.X + scaleInt(bounds.Width, scale)),
+		Bottom: int32(bounds.Y + scaleInt(bounds.Height, scale)),
+	}
+
+	fb.SendMessage(win.WM_DPICHANGED, uintptr(win.MAKELONG(uint16(newDPI), uint16(newDPI))), uintptr(unsafe.Pointer(&rc)))
+}
+
+// applySnapshotTheme turns visual styles off or on again for the windows of
+// the form.
+func (fb *FormBase) applySnapshotTheme(theme SnapshotTheme) {
+	empty := syscall.StringToUTF16Ptr("")
+
+	apply := func(hwnd win.HWND) {
+		if theme == SnapshotThemeClassic {
+			win.SetWindowTheme(hwnd, empty, empty)
+		} else if appName, ok := windowThemeAppNames[hwnd]; ok {
+			win.SetWindowTheme(hwnd, syscall.StringToUTF16Ptr(appName), nil)
+		} else {
+			win.SetWindowTheme(hwnd, nil, nil)
+		}
+	}
+
+	apply(fb.hWnd)
+	fb.forEachDescendantRaw(0, func(hwnd win.HWND, lParam uintptr) bool {
+		apply(hwnd)
+		return true
+	})
+}
+
+// layoutNow lays out the form synchronously, instead of in the background.
+func (fb *FormBase) layoutNow() {
+	fb.proposedSize = fb.SizePixels()
+
+	cs := fb.clientSizeFromSizePixels(fb.proposedSize)
+	min := CreateLayoutItemsForContainer(fb.clientComposite).MinSizeForSize(fb.proposedSize)
+
+	if cs.Width < min.Width || cs.Height < min.Height {
+		cs = maxSize(cs, min)
+		fb.SetSizePixels(fb.sizeFromClientSizePixels(cs))
+	}
+
+	cbp := fb.window.ClientBoundsPixels()
+	fb.clientComposite.SetBoundsPixels(Rectangle{Y: cbp.Y, Width: cs.Width, Height: cs.Height})
+
+	cli := CreateLayoutItemsForContainer(fb)
+	cli.Geometry().ClientSize = cs
+
+	done := make(chan []LayoutResult, 1)
+	layoutTree(cli, cs, make(chan struct{}), done, nil)
+
+	applyLayoutResults(<-done, nil)
+
+	win.RedrawWindow(fb.hWnd, nil, 0, win.RDW_INVALIDATE|win.RDW_ERASE|win.RDW_ALLCHILDREN|win.RDW_UPDATENOW)
+}
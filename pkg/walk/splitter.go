@@ -10,6 +10,7 @@ package walk
 import (
 	"bytes"
 	"log"
+	"math"
 	"strconv"
 	"strings"
 	"unsafe"
@@ -35,8 +36,12 @@ type Splitter struct {
 	handleWidth   int
 	mouseDownPos  Point // in native pixels
 	draggedHandle *splitterHandle
+	snapper       *Snapper
 	persistent    bool
 	removing      bool
+	paneFractions map[string]float64 // remembered shares of named panes, see InsertWidgetAt
+
+	fractionsChangedPublisher EventPublisher
 }
 
 func newSplitter(parent Container, orientation Orientation) (*Splitter, error) {
@@ -91,6 +96,32 @@ func (s *Splitter) SetLayout(value Layout) error {
 	return newError("not supported")
 }
 
+// Snapper returns the Snapper that dragged handles snap with, or nil.
+func (s *Splitter) Snapper() *Snapper {
+	return s.snapper
+}
+
+// SetSnapper sets the Snapper that dragged handles snap with, unless Alt is
+// held down. It snaps the leading edge of a handle, in native pixels relative
+// to the Splitter.
+func (s *Splitter) SetSnapper(snapper *Snapper) {
+	s.snapper = snapper
+}
+
+// snapHandlePixels returns the position of a dragged handle snapped along
+// the orientation of the Splitter.
+func (s *Splitter) snapHandlePixels(pos int) int {
+	if s.snapper == nil || AltDown() {
+		return pos
+	}
+
+	if s.Orientation() == Horizontal {
+		return int(math.Round(s.snapper.SnapX(float64(pos))))
+	}
+
+	return int(math.Round(s.snapper.SnapY(float64(pos))))
+}
+
 func (s *Splitter) HandleWidth() int {
 	return s.handleWidth
 }
@@ -194,17 +225,38 @@ func (s *Splitter) SaveState() error {
 	count := s.children.Len()
 	layout := s.Layout().(*splitterLayout)
 
+	var total float64
 	for i := 0; i < count; i += 2 {
-		if i > 0 {
-			buf.WriteString(" ")
-		}
+		total += layout.hwnd2Item[s.children.At(i).Handle()].fraction
+	}
+	if total == 0 {
+		total = 1
+	}
 
+	// Panes are stored as fractions of the total, negated for collapsed ones,
+	// so they keep their proportions when restored at another size.
+	fractions := make([]float64, 0, count/2+1)
+	for i := 0; i < count; i += 2 {
 		item := layout.hwnd2Item[s.children.At(i).Handle()]
-		size := item.oldExplicitSize
-		if size == 0 {
-			size = item.size
+		fraction := item.fraction / total
+		if item.collapsed {
+			fraction = -fraction
+		}
+		fractions = append(fractions, fraction)
+	}
+
+	// If all panes are named, they are stored by name, so they find their
+	// shares even if panes were added, removed or reordered in the meantime.
+	if state, ok := s.namedPaneState(fractions); ok {
+		buf.WriteString(state)
+	} else {
+		for i, fraction := range fractions {
+			if i > 0 {
+				buf.WriteString(" ")
+			}
+
+			buf.WriteString(strconv.FormatFloat(fraction, 'f', 6, 64))
 		}
-		buf.WriteString(strconv.FormatInt(int64(size), 10))
 	}
 
 	s.WriteState(buf.String())
@@ -235,9 +287,10 @@ func (s *Splitter) RestoreState() error {
 	}
 
 	sizeStrs := strings.Split(state, " ")
+	named := strings.Contains(state, "=")
 
 	// FIXME: Solve this in a better way.
-	if len(sizeStrs) != childCount {
+	if !named && len(sizeStrs) != childCount {
 		log.Print("*Splitter.RestoreState: failed due to unexpected child count (FIXME!)")
 		return nil
 	}
@@ -260,25 +313,40 @@ func (s *Splitter) RestoreState() error {
 	}
 	regularSpace := space - layout.spaceUnavailableToRegularWidgets()
 
+	if named {
+		if err := s.restoreNamedPaneState(sizeStrs, regularSpace); err != nil {
+			return err
+		}
+	}
+
 	for i, wb := range s.children.items {
 		widget := wb.window.(Widget)
 
-		if i%2 == 0 {
+		if i%2 == 0 && !named {
 			j := i/2 + i%2
 			s := sizeStrs[j]
 
+			item := layout.hwnd2Item[widget.Handle()]
+			item.fractionPending = false
+
 			size, err := strconv.Atoi(s)
-			if err != nil {
-				// OK, we probably got old style settings which were stored as fractions.
+			if err == nil {
+				// Old style settings stored sizes in native pixels, so let the
+				// layout derive the fractions from them.
+				item.fraction = 0
+				layout.resetNeeded = true
+			} else {
 				fraction, err := strconv.ParseFloat(s, 64)
 				if err != nil {
 					return err
 				}
 
-				size = int(float64(regularSpace) * fraction)
+				item.collapsed = math.Signbit(fraction)
+				item.fraction = math.Abs(fraction)
+
+				size = int(float64(regularSpace) * item.fraction)
 			}
 
-			item := layout.hwnd2Item[widget.Handle()]
 			item.size = size
 			item.oldExplicitSize = size
 		}
@@ -357,7 +425,7 @@ func (s *Splitter) onInsertedWidget(index int, widget Widget) (err error) {
 		}
 	} else {
 		layout := s.Layout().(*splitterLayout)
-		item := &splitterLayoutItem{stretchFactor: 1, wasVisible: true}
+		item := &splitterLayoutItem{stretchFactor: 1, wasVisible: true, fractionPending: true}
 		layout.hwnd2Item[widget.Handle()] = item
 
 		layout.resetNeeded = true
@@ -434,7 +502,7 @@ func (s *Splitter) onInsertedWidget(index int, widget Widget) (err error) {
 						if s.Orientation() == Horizontal {
 							xh := s.draggedHandle.XPixels()
 
-							xnew := xh + x - s.mouseDownPos.X
+							xnew := s.snapHandlePixels(xh + x - s.mouseDownPos.X)
 							if xnew < bp.X+msep.Width {
 								xnew = bp.X + msep.Width
 							} else if xnew >= bn.X+bn.Width-msen.Width-handleWidth {
@@ -447,7 +515,7 @@ func (s *Splitter) onInsertedWidget(index int, widget Widget) (err error) {
 						} else {
 							yh := s.draggedHandle.YPixels()
 
-							ynew := yh + y - s.mouseDownPos.Y
+							ynew := s.snapHandlePixels(yh + y - s.mouseDownPos.Y)
 							if ynew < bp.Y+msep.Height {
 								ynew = bp.Y + msep.Height
 							} else if ynew >= bn.Y+bn.Height-msen.Height-handleWidth {
@@ -537,6 +605,8 @@ func (s *Splitter) onInsertedWidget(index int, widget Widget) (err error) {
 						nextItem := layout.hwnd2Item[next.Handle()]
 						nextItem.size = sizeNext
 						nextItem.oldExplicitSize = sizeNext
+
+						s.updateFractionsAfterDrag(prevItem, nextItem, sizePrev, sizeNext)
 					})
 				}
 			}()
@@ -547,6 +617,10 @@ func (s *Splitter) onInsertedWidget(index int, widget Widget) (err error) {
 }
 
 func (s *Splitter) onRemovingWidget(index int, widget Widget) (err error) {
+	if _, isHandle := widget.(*splitterHandle); !isHandle {
+		s.rememberPaneFractions()
+	}
+
 	return s.ContainerBase.onRemovingWidget(index, widget)
 }
 
@@ -567,6 +641,7 @@ func (s *Splitter) onRemovedWidget(index int, widget Widget) (err error) {
 	if !isHandle {
 		sl := s.layout.(*splitterLayout)
 		widget.AsWidgetBase().Property("Visible").Changed().Detach(sl.hwnd2Item[widget.Handle()].visibleChangedHandle)
+		delete(sl.hwnd2Item, widget.Handle())
 	}
 
 	if !isHandle && s.children.Len() > 1 {
@@ -597,6 +672,9 @@ func (s *Splitter) onRemovedWidget(index int, widget Widget) (err error) {
 				s.RequestLayout()
 
 				handle.Dispose()
+
+				// The other panes keep their shares relative to each other.
+				s.fractionsChangedPublisher.Publish()
 			}
 
 			s.removing = false
@@ -617,5 +695,7 @@ func (s *Splitter) onClearedWidgets() (err error) {
 }
 
 func (s *Splitter) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	s.assignPendingFractions()
+
 	return s.layout.CreateLayoutItem(ctx)
 }
@@ -0,0 +1,219 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// FractionsChanged returns the event that is published when the share of the
+// space that the panes occupy changed, by dragging a handle, collapsing or
+// restoring a pane, or calling SetFractions.
+func (s *Splitter) FractionsChanged() *Event {
+	return s.fractionsChangedPublisher.Event()
+}
+
+// Fractions returns the share of the space available to the panes that each
+// visible pane occupies, in the order of the panes. Hidden and collapsed panes
+// have a share of 0.
+func (s *Splitter) Fractions() []float64 {
+	layout := s.layout.(*splitterLayout)
+
+	fractions := make([]float64, 0, s.children.Len()/2+1)
+	var total float64
+
+	for i, wb := range s.children.items {
+		if i%2 == 1 {
+			continue
+		}
+
+		var fraction float64
+		if item := layout.hwnd2Item[wb.window.Handle()]; item != nil && wb.visible && !item.collapsed {
+			fraction = item.fraction
+		}
+
+		fractions = append(fractions, fraction)
+		total += fraction
+	}
+
+	if total > 0 {
+		for i := range fractions {
+			fractions[i] /= total
+		}
+	}
+
+	return fractions
+}
+
+// SetFractions sets the share of the space available to the panes that each
+// pane occupies, in the order of the panes. The fractions are normalized, so
+// they don't need to add up to 1. A pane with a fraction of 0 is collapsed.
+//
+// The panes keep their shares when the Splitter is resized.
+func (s *Splitter) SetFractions(fractions []float64) error {
+	layout := s.layout.(*splitterLayout)
+
+	if len(fractions) != s.children.Len()/2+1 {
+		return newError("fractions must contain a value for each pane")
+	}
+
+	var total float64
+	for _, fraction := range fractions {
+		if fraction < 0 {
+			return newError("fractions must not be negative")
+		}
+
+		total += fraction
+	}
+	if total == 0 {
+		return newError("fractions must not all be 0")
+	}
+
+	for i, wb := range s.children.items {
+		if i%2 == 1 {
+			continue
+		}
+
+		item := layout.hwnd2Item[wb.window.Handle()]
+		fraction := fractions[i/2]
+
+		item.collapsed = fraction == 0
+		item.fractionPending = false
+		if !item.collapsed {
+			item.fraction = fraction / total
+		}
+		item.oldExplicitSize = 0
+	}
+
+	s.RequestLayout()
+
+	s.fractionsChangedPublisher.Publish()
+
+	return nil
+}
+
+// Collapsed returns if widget is a collapsed pane.
+func (s *Splitter) Collapsed(widget Widget) bool {
+	item := s.layout.(*splitterLayout).hwnd2Item[widget.Handle()]
+
+	return item != nil && item.collapsed
+}
+
+// SetCollapsed collapses the pane widget to zero size, or restores it to its
+// previous share of the space.
+//
+// The user can also collapse the smaller of the panes next to a handle by
+// double-clicking the handle, and restore it by double-clicking again.
+func (s *Splitter) SetCollapsed(widget Widget, collapsed bool) error {
+	layout := s.layout.(*splitterLayout)
+
+	item := layout.hwnd2Item[widget.Handle()]
+	if item == nil {
+		return newError("unknown widget")
+	}
+
+	if collapsed == item.collapsed {
+		return nil
+	}
+
+	item.collapsed = collapsed
+
+	if !collapsed && item.fraction == 0 {
+		// The pane was collapsed by SetFractions, so give it an equal share.
+		var total float64
+		var count int
+		for i, wb := range s.children.items {
+			if i%2 == 1 {
+				continue
+			}
+
+			if other := layout.hwnd2Item[wb.window.Handle()]; other != item && !other.collapsed && other.fraction > 0 {
+				total += other.fraction
+				count++
+			}
+		}
+
+		if count > 0 {
+			item.fraction = total / float64(count)
+		}
+	}
+
+	s.RequestLayout()
+
+	s.fractionsChangedPublisher.Publish()
+
+	return nil
+}
+
+// adjacentPanes returns the closest visible panes before and after handle.
+func (s *Splitter) adjacentPanes(handle *splitterHandle) (prev, next Widget) {
+	closestVisibleWidget := func(offset, direction int) Widget {
+		index := offset + direction
+
+		for index >= 0 && index < len(s.children.items) {
+			if wb := s.children.items[index]; wb.visible {
+				return wb.window.(Widget)
+			}
+
+			index += direction
+		}
+
+		return nil
+	}
+
+	handleIndex := s.children.Index(handle)
+
+	return closestVisibleWidget(handleIndex, -1), closestVisibleWidget(handleIndex, 1)
+}
+
+// onHandleDoubleClicked restores a collapsed pane next to handle, or
+// otherwise collapses the smaller one.
+func (s *Splitter) onHandleDoubleClicked(handle *splitterHandle) {
+	prev, next := s.adjacentPanes(handle)
+	if prev == nil || next == nil {
+		return
+	}
+
+	if s.Collapsed(prev) {
+		s.SetCollapsed(prev, false)
+		return
+	}
+	if s.Collapsed(next) {
+		s.SetCollapsed(next, false)
+		return
+	}
+
+	bp, bn := prev.BoundsPixels(), next.BoundsPixels()
+
+	smaller := prev
+	if s.Orientation() == Horizontal && bn.Width < bp.Width || s.Orientation() == Vertical && bn.Height < bp.Height {
+		smaller = next
+	}
+
+	s.SetCollapsed(smaller, true)
+}
+
+// updateFractionsAfterDrag divides the shares of the panes next to a dragged
+// handle according to their new sizes, keeping the shares of all other panes.
+func (s *Splitter) updateFractionsAfterDrag(prevItem, nextItem *splitterLayoutItem, sizePrev, sizeNext int) {
+	layout := s.layout.(*splitterLayout)
+	anyNonFixed := layout.anyNonFixed()
+
+	if sizePrev > 0 {
+		prevItem.collapsed = false
+	}
+	if sizeNext > 0 {
+		nextItem.collapsed = false
+	}
+
+	if !prevItem.proportional(anyNonFixed) || !nextItem.proportional(anyNonFixed) || sizePrev+sizeNext == 0 {
+		return
+	}
+
+	total := prevItem.fraction + nextItem.fraction
+	prevItem.fraction = total * float64(sizePrev) / float64(sizePrev+sizeNext)
+	nextItem.fraction = total - prevItem.fraction
+
+	s.fractionsChangedPublisher.Publish()
+}
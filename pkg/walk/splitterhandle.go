@@ -15,7 +15,7 @@ const splitterHandleWindowClass = `\o/ Walk_SplitterHandle_Class \o/`
 
 func init() {
 	AppendToWalkInit(func() {
-		MustRegisterWindowClass(splitterHandleWindowClass)
+		MustRegisterWindowClassWithStyle(splitterHandleWindowClass, win.CS_DBLCLKS)
 	})
 }
 
@@ -56,6 +56,13 @@ func (sh *splitterHandle) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uint
 			return 1
 		}
 
+	case win.WM_LBUTTONDBLCLK:
+		if splitter, ok := sh.Parent().(*Splitter); ok {
+			splitter.onHandleDoubleClicked(sh)
+		}
+
+		return 0
+
 	case win.WM_PAINT:
 		if sh.Background() == nullBrushSingleton {
 			var ps win.PAINTSTRUCT
@@ -101,7 +108,7 @@ func (li *splitterHandleLayoutItem) LayoutFlags() LayoutFlags {
 
 func (li *splitterHandleLayoutItem) IdealSize() Size {
 	var size Size
-	dpi := int(win.GetDpiForWindow(li.handle))
+	dpi := dpiForWindow(li.handle)
 
 	if li.orientation == Horizontal {
 		size.Width = IntFrom96DPI(li.handleWidth, dpi)
@@ -28,9 +28,18 @@ type splitterLayoutItem struct {
 	stretchFactor        int
 	growth               int
 	visibleChangedHandle int
+	fraction             float64 // share of the space available to panes
 	fixed                bool
 	keepSize             bool
 	wasVisible           bool
+	collapsed            bool
+	fractionPending      bool // inserted since the last layout, see Splitter.assignPendingFractions
+}
+
+// proportional returns if the item is sized by its fraction of the space
+// available to panes, rather than by a size of its own.
+func (sli *splitterLayoutItem) proportional(anyNonFixed bool) bool {
+	return !sli.collapsed && !sli.keepSize && !(anyNonFixed && sli.fixed)
 }
 
 func newSplitterLayout(orientation Orientation) *splitterLayout {
@@ -231,6 +240,10 @@ func (li *splitterContainerLayoutItem) MinSizeForSize(size Size) Size {
 			continue
 		}
 
+		if sli, ok := li.hwnd2Item[item.Handle()]; ok && sli.collapsed {
+			continue
+		}
+
 		var cur Size
 
 		if sli, ok := li.hwnd2Item[item.Handle()]; ok && li.anyNonFixed && sli.fixed {
@@ -292,6 +305,9 @@ func (li *splitterContainerLayoutItem) PerformLayout() []LayoutResultItem {
 	var wis []WidgetItem
 
 	anyNonFixed := li.anyNonFixed
+
+	li.applyFractions(space1)
+
 	var totalRegularSize int
 	for i, item := range li.children {
 		if !anyVisibleItemInHierarchy(item) {
@@ -301,6 +317,10 @@ func (li *splitterContainerLayoutItem) PerformLayout() []LayoutResultItem {
 		if i%2 == 0 {
 			slItem := li.hwnd2Item[item.Handle()]
 
+			if slItem.collapsed {
+				continue
+			}
+
 			var wi *WidgetItem
 
 			if !anyNonFixed || !slItem.fixed {
@@ -452,6 +472,47 @@ func (li *splitterContainerLayoutItem) PerformLayout() []LayoutResultItem {
 	return resultItems
 }
 
+// applyFractions sizes the proportional items by their fractions of space,
+// minus the space taken by the other items, so all panes keep their
+// proportions when the Splitter is resized.
+func (li *splitterContainerLayoutItem) applyFractions(space int) {
+	var total float64
+
+	for i, item := range li.children {
+		if i%2 == 1 || !anyVisibleItemInHierarchy(item) {
+			continue
+		}
+
+		sli := li.hwnd2Item[item.Handle()]
+
+		if sli.proportional(li.anyNonFixed) {
+			if sli.fraction == 0 {
+				// The fractions are derived on the next reset.
+				return
+			}
+
+			total += sli.fraction
+		} else if !sli.collapsed {
+			space -= sli.size
+		}
+	}
+
+	if total == 0 || space < 0 {
+		return
+	}
+
+	for i, item := range li.children {
+		if i%2 == 1 || !anyVisibleItemInHierarchy(item) {
+			continue
+		}
+
+		if sli := li.hwnd2Item[item.Handle()]; sli.proportional(li.anyNonFixed) {
+			sli.size = int(sli.fraction / total * float64(space))
+			sli.growth = 0
+		}
+	}
+}
+
 func (li *splitterContainerLayoutItem) reset() {
 	var anyVisible bool
 
@@ -529,6 +590,9 @@ func (li *splitterContainerLayoutItem) reset() {
 		stretchTotal += li.StretchFactor(item)
 	}
 
+	preferredSizes := make([]int, len(li.children))
+	var fractionsMissing bool
+
 	for i, item := range li.children {
 		if i%2 == 1 || !anyVisibleItemInHierarchy(item) {
 			continue
@@ -542,6 +606,7 @@ func (li *splitterContainerLayoutItem) reset() {
 		} else {
 			sli.size = int(float64(li.StretchFactor(item)) / float64(stretchTotal) * float64(regularSpace))
 		}
+		preferredSizes[i] = sli.size
 
 		min := minSizes[i]
 		if minSizesTotal <= regularSpace {
@@ -558,5 +623,23 @@ func (li *splitterContainerLayoutItem) reset() {
 				sli.keepSize = true
 			}
 		}
+
+		if sli.fraction == 0 && sli.proportional(li.anyNonFixed) {
+			fractionsMissing = true
+		}
+	}
+
+	// Derive the fractions from the sizes before applying minimum sizes,
+	// unless all panes already have one, e.g. from SetFractions.
+	if fractionsMissing && regularSpace > 0 {
+		for i, item := range li.children {
+			if i%2 == 1 || !anyVisibleItemInHierarchy(item) {
+				continue
+			}
+
+			if sli := li.hwnd2Item[item.Handle()]; sli.proportional(li.anyNonFixed) {
+				sli.fraction = float64(maxi(preferredSizes[i], 1)) / float64(regularSpace)
+			}
+		}
 	}
 }
@@ -0,0 +1,277 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PaneCount returns the number of panes of the Splitter, not counting the
+// handles between them.
+func (s *Splitter) PaneCount() int {
+	return (s.children.Len() + 1) / 2
+}
+
+// Pane returns the pane at index, in the order of the panes.
+func (s *Splitter) Pane(index int) Widget {
+	return s.children.At(2 * index)
+}
+
+// InsertWidgetAt inserts widget as the pane at index, in the order of the
+// panes, e.g. to add a panel while the Splitter is shown.
+//
+// The new pane takes an equal share of the space, which the other panes give
+// up in proportion to their shares. If the pane has a name and the Splitter
+// remembers a share for it, from RestoreState or from when a pane of that name
+// was removed, it gets that share back instead.
+//
+// If widget is a pane of the Splitter already, it is moved to index and keeps
+// its share.
+func (s *Splitter) InsertWidgetAt(index int, widget Widget) error {
+	if current := s.children.Index(widget); current > -1 {
+		if current%2 == 1 {
+			return newError("cannot move splitter handle")
+		}
+
+		return s.movePane(current/2, index)
+	}
+
+	if index < 0 || index > s.PaneCount() {
+		return newError("index out of range")
+	}
+
+	childIndex := 2 * index
+	if index == s.PaneCount() {
+		// Add the pane at the end, after a new handle.
+		childIndex = s.children.Len()
+	}
+
+	return s.children.Insert(childIndex, widget)
+}
+
+// movePane moves the pane at from to to. As handles don't belong to a
+// particular pane, only the panes change places.
+func (s *Splitter) movePane(from, to int) error {
+	count := s.PaneCount()
+	if to < 0 || to >= count {
+		return newError("index out of range")
+	}
+
+	if from == to {
+		return nil
+	}
+
+	items := s.children.items
+
+	pane := items[2*from]
+	if from < to {
+		for i := from; i < to; i++ {
+			items[2*i] = items[2*(i+1)]
+		}
+	} else {
+		for i := from; i > to; i-- {
+			items[2*i] = items[2*(i-1)]
+		}
+	}
+	items[2*to] = pane
+
+	s.RequestLayout()
+
+	s.fractionsChangedPublisher.Publish()
+
+	return nil
+}
+
+// rememberPaneFractions remembers the shares of all named panes, so they get
+// them back when they are inserted again after being removed.
+func (s *Splitter) rememberPaneFractions() {
+	fractions := s.Fractions()
+
+	paneFractions := make(map[string]float64, len(fractions))
+
+	for i, fraction := range fractions {
+		if name := s.Pane(i).Name(); name != "" && fraction > 0 {
+			paneFractions[name] = fraction
+		}
+	}
+
+	s.paneFractions = paneFractions
+}
+
+// assignPendingFractions gives the panes inserted since the last layout their
+// shares of the space, unless there are no shares yet, which are then derived
+// from the sizes of all panes by the layout.
+//
+// This waits for the layout, as widgets are usually named after they have been
+// inserted.
+func (s *Splitter) assignPendingFractions() {
+	layout := s.layout.(*splitterLayout)
+
+	var pending []Widget
+	var total, knownTotal, knownRemembered float64
+	var count int
+
+	for i := 0; i < s.PaneCount(); i++ {
+		pane := s.Pane(i)
+
+		item := layout.hwnd2Item[pane.Handle()]
+		if item == nil {
+			continue
+		}
+
+		if item.fractionPending {
+			pending = append(pending, pane)
+			continue
+		}
+
+		if item.collapsed || item.fraction == 0 {
+			continue
+		}
+
+		total += item.fraction
+		count++
+
+		if remembered, ok := s.paneFractions[pane.Name()]; ok {
+			knownTotal += item.fraction
+			knownRemembered += remembered
+		}
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	for _, pane := range pending {
+		item := layout.hwnd2Item[pane.Handle()]
+		item.fractionPending = false
+
+		if count == 0 {
+			continue
+		}
+
+		// An equal share means the average of the existing ones, as the total
+		// is normalized.
+		item.fraction = total / float64(count)
+
+		// Remembered shares are relative to those of the other remembered
+		// panes that are still there.
+		if remembered, ok := s.paneFractions[pane.Name()]; ok && knownRemembered > 0 {
+			item.fraction = remembered * knownTotal / knownRemembered
+		}
+
+		item.oldExplicitSize = 0
+	}
+
+	if count > 0 {
+		s.fractionsChangedPublisher.Publish()
+	}
+}
+
+// namedPaneState returns the state of the panes keyed by their names, or
+// false if not all panes have a unique name.
+func (s *Splitter) namedPaneState(fractions []float64) (string, bool) {
+	names := make(map[string]bool, len(fractions))
+	entries := make([]string, len(fractions))
+
+	for i, fraction := range fractions {
+		name := s.Pane(i).Name()
+		if name == "" || names[name] {
+			return "", false
+		}
+		names[name] = true
+
+		entries[i] = url.QueryEscape(name) + "=" + strconv.FormatFloat(fraction, 'f', 6, 64)
+	}
+
+	return strings.Join(entries, " "), true
+}
+
+// restoreNamedPaneState restores the panes from entries of a state saved with
+// namedPaneState. Panes without an entry get the average share of the restored
+// ones, entries without a pane are remembered in case it is inserted later.
+func (s *Splitter) restoreNamedPaneState(entries []string, regularSpace int) error {
+	layout := s.layout.(*splitterLayout)
+
+	paneFractions := make(map[string]float64, len(entries))
+	collapsed := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		i := strings.LastIndexByte(entry, '=')
+		if i < 0 {
+			return newError("invalid splitter state")
+		}
+
+		name, err := url.QueryUnescape(entry[:i])
+		if err != nil {
+			return err
+		}
+
+		fraction, err := strconv.ParseFloat(entry[i+1:], 64)
+		if err != nil {
+			return err
+		}
+
+		paneFractions[name] = math.Abs(fraction)
+		collapsed[name] = math.Signbit(fraction)
+	}
+
+	var total float64
+	var count int
+	var missing []*splitterLayoutItem
+
+	for i := 0; i < s.PaneCount(); i++ {
+		pane := s.Pane(i)
+		item := layout.hwnd2Item[pane.Handle()]
+
+		fraction, ok := paneFractions[pane.Name()]
+		if !ok {
+			missing = append(missing, item)
+			continue
+		}
+
+		item.collapsed = collapsed[pane.Name()]
+		item.fraction = fraction
+		item.fractionPending = false
+
+		if fraction > 0 {
+			total += fraction
+			count++
+		}
+	}
+
+	for _, item := range missing {
+		item.collapsed = false
+		item.fraction = 0
+		item.fractionPending = false
+
+		if count > 0 {
+			item.fraction = total / float64(count)
+		}
+	}
+
+	if count == 0 {
+		// Nothing matched, so let the layout derive the shares.
+		layout.resetNeeded = true
+	} else {
+		total += float64(len(missing)) * total / float64(count)
+
+		for i := 0; i < s.PaneCount(); i++ {
+			item := layout.hwnd2Item[s.Pane(i).Handle()]
+
+			item.size = int(float64(regularSpace) * item.fraction / total)
+			item.oldExplicitSize = item.size
+		}
+	}
+
+	s.paneFractions = paneFractions
+
+	return nil
+}
@@ -0,0 +1,452 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+var (
+	starRatingFillColor    = RGB(255, 180, 0)
+	starRatingPreviewColor = RGB(255, 214, 120)
+)
+
+// StarRating is a widget that shows and edits a rating as a row of stars.
+//
+// Hovering the stars previews the rating under the mouse pointer, clicking
+// sets it and clicking the current rating again clears it. The arrow keys
+// change the rating by one step, Home and End set it to 0 and the maximum.
+//
+// To show ratings in a TableView, call DrawStarRatingPixels from a
+// CellStyler.
+type StarRating struct {
+	*CustomWidget
+	rating                 float64
+	maxStars               int
+	halfStars              bool
+	readOnly               bool
+	hoverRating            float64 // -1 if not hovering
+	trackingMouseEvent     bool
+	persistent             bool
+	ratingChangedPublisher EventPublisher
+}
+
+// NewStarRating creates and initializes a new StarRating with 5 stars.
+func NewStarRating(parent Container) (*StarRating, error) {
+	sr := &StarRating{maxStars: 5, hoverRating: -1}
+
+	cw, err := NewCustomWidgetPixels(parent, win.WS_TABSTOP, func(canvas *Canvas, updateBounds Rectangle) error {
+		return sr.paint(canvas)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			cw.Dispose()
+		}
+	}()
+
+	sr.CustomWidget = cw
+
+	if err := InitWrapperWindow(sr); err != nil {
+		return nil, err
+	}
+
+	sr.SetInvalidatesOnResize(true)
+
+	sr.GraphicsEffects().Add(InteractionEffect)
+	sr.GraphicsEffects().Add(FocusEffect)
+
+	sr.MustRegisterProperty("Rating", NewProperty(
+		func() interface{} {
+			return sr.Rating()
+		},
+		func(v interface{}) error {
+			return sr.SetRating(assertFloat64Or(v, 0))
+		},
+		sr.ratingChangedPublisher.Event()))
+
+	succeeded = true
+
+	return sr, nil
+}
+
+// Rating returns the rating, in the range [0, MaxStars].
+func (sr *StarRating) Rating() float64 {
+	return sr.rating
+}
+
+// SetRating sets the rating. It is clamped to [0, MaxStars] and rounded to
+// whole or, if HalfStars is enabled, half stars.
+func (sr *StarRating) SetRating(rating float64) error {
+	if math.IsNaN(rating) {
+		return newError("invalid rating")
+	}
+
+	rating = sr.round(math.Max(0, math.Min(float64(sr.maxStars), rating)))
+
+	if rating == sr.rating {
+		return nil
+	}
+
+	sr.rating = rating
+
+	sr.Invalidate()
+
+	sr.ratingChangedPublisher.Publish()
+
+	return nil
+}
+
+// RatingChanged returns an Event that can be used to track changes to Rating.
+func (sr *StarRating) RatingChanged() *Event {
+	return sr.ratingChangedPublisher.Event()
+}
+
+// MaxStars returns the number of stars.
+func (sr *StarRating) MaxStars() int {
+	return sr.maxStars
+}
+
+// SetMaxStars sets the number of stars. The rating is clamped to it.
+func (sr *StarRating) SetMaxStars(maxStars int) error {
+	if maxStars < 1 {
+		return newError("maxStars must >= 1")
+	}
+
+	if maxStars == sr.maxStars {
+		return nil
+	}
+
+	sr.maxStars = maxStars
+
+	sr.RequestLayout()
+	sr.Invalidate()
+
+	return sr.SetRating(sr.rating)
+}
+
+// HalfStars returns whether ratings have half star precision.
+func (sr *StarRating) HalfStars() bool {
+	return sr.halfStars
+}
+
+// SetHalfStars sets whether ratings have half star precision.
+func (sr *StarRating) SetHalfStars(halfStars bool) error {
+	sr.halfStars = halfStars
+
+	return sr.SetRating(sr.rating)
+}
+
+// ReadOnly returns whether the user is prevented from changing the rating.
+func (sr *StarRating) ReadOnly() bool {
+	return sr.readOnly
+}
+
+// SetReadOnly sets whether the user is prevented from changing the rating.
+func (sr *StarRating) SetReadOnly(readOnly bool) error {
+	sr.readOnly = readOnly
+
+	sr.setHoverRating(-1)
+
+	return sr.ensureStyleBits(win.WS_TABSTOP, !readOnly)
+}
+
+func (sr *StarRating) Persistent() bool {
+	return sr.persistent
+}
+
+func (sr *StarRating) SetPersistent(value bool) {
+	sr.persistent = value
+}
+
+func (sr *StarRating) SaveState() error {
+	return sr.WriteState(strconv.FormatFloat(sr.rating, 'f', -1, 64))
+}
+
+func (sr *StarRating) RestoreState() error {
+	s, err := sr.ReadState()
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+
+	rating, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+
+	return sr.SetRating(rating)
+}
+
+func (sr *StarRating) step() float64 {
+	if sr.halfStars {
+		return 0.5
+	}
+
+	return 1
+}
+
+func (sr *StarRating) round(rating float64) float64 {
+	return math.Round(rating/sr.step()) * sr.step()
+}
+
+func (sr *StarRating) starSize() int {
+	return starRatingSize(sr.ClientBoundsPixels(), sr.maxStars)
+}
+
+func (sr *StarRating) ratingAt(x int) float64 {
+	size := sr.starSize()
+	if size <= 0 {
+		return 0
+	}
+
+	step := sr.step()
+
+	rating := math.Ceil(float64(x)/float64(size)/step) * step
+
+	return math.Max(step, math.Min(float64(sr.maxStars), rating))
+}
+
+func (sr *StarRating) setHoverRating(rating float64) {
+	if rating != sr.hoverRating {
+		sr.hoverRating = rating
+		sr.Invalidate()
+	}
+}
+
+func (sr *StarRating) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_MOUSEMOVE:
+		if sr.readOnly || !sr.Enabled() {
+			break
+		}
+
+		if !sr.trackingMouseEvent {
+			var tme win.TRACKMOUSEEVENT
+			tme.CbSize = uint32(unsafe.Sizeof(tme))
+			tme.DwFlags = win.TME_LEAVE
+			tme.HwndTrack = hwnd
+
+			sr.trackingMouseEvent = win.TrackMouseEvent(&tme)
+		}
+
+		sr.setHoverRating(sr.ratingAt(int(win.GET_X_LPARAM(lParam))))
+
+	case win.WM_MOUSELEAVE:
+		sr.trackingMouseEvent = false
+		sr.setHoverRating(-1)
+
+	case win.WM_LBUTTONDOWN:
+		if sr.readOnly || !sr.Enabled() {
+			break
+		}
+
+		sr.SetFocus()
+
+		rating := sr.ratingAt(int(win.GET_X_LPARAM(lParam)))
+		if rating == sr.rating {
+			rating = 0
+		}
+
+		sr.SetRating(rating)
+
+	case win.WM_KEYDOWN:
+		if sr.readOnly || !sr.Enabled() {
+			break
+		}
+
+		switch Key(wParam) {
+		case KeyRight, KeyUp:
+			sr.SetRating(sr.rating + sr.step())
+
+		case KeyLeft, KeyDown:
+			sr.SetRating(sr.rating - sr.step())
+
+		case KeyHome:
+			sr.SetRating(0)
+
+		case KeyEnd:
+			sr.SetRating(float64(sr.maxStars))
+		}
+	}
+
+	return sr.CustomWidget.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (sr *StarRating) paint(canvas *Canvas) error {
+	bounds := sr.ClientBoundsPixels()
+
+	fill := starRatingFillColor
+	if !sr.Enabled() {
+		fill = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	if sr.hoverRating >= 0 {
+		return drawStarRatingPixels(canvas, bounds, sr.hoverRating, sr.maxStars, starRatingPreviewColor)
+	}
+
+	return drawStarRatingPixels(canvas, bounds, sr.rating, sr.maxStars, fill)
+}
+
+func (sr *StarRating) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	size := SizeFrom96DPI(Size{18*sr.maxStars + 2, 18}, ctx.dpi)
+
+	return &starRatingLayoutItem{idealSize: size}
+}
+
+type starRatingLayoutItem struct {
+	LayoutItemBase
+	idealSize Size // in native pixels
+}
+
+func (*starRatingLayoutItem) LayoutFlags() LayoutFlags {
+	return 0
+}
+
+func (li *starRatingLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *starRatingLayoutItem) MinSize() Size {
+	return li.idealSize
+}
+
+// DrawStarRatingPixels draws rating as a row of maxStars stars into bounds,
+// which is represented in native pixels. Fractional ratings fill the last
+// star partially.
+//
+// It can be used from a CellStyler to render ratings in a TableView cell.
+func DrawStarRatingPixels(canvas *Canvas, bounds Rectangle, rating float64, maxStars int) error {
+	return drawStarRatingPixels(canvas, bounds, rating, maxStars, starRatingFillColor)
+}
+
+func starRatingSize(bounds Rectangle, maxStars int) int {
+	if maxStars < 1 {
+		return 0
+	}
+
+	return mini(bounds.Height, bounds.Width/maxStars)
+}
+
+func drawStarRatingPixels(canvas *Canvas, bounds Rectangle, rating float64, maxStars int, fill Color) error {
+	size := starRatingSize(bounds, maxStars)
+	if size < 3 {
+		return nil
+	}
+
+	fillBrush, err := NewSolidColorBrush(fill)
+	if err != nil {
+		return err
+	}
+	defer fillBrush.Dispose()
+
+	outlinePen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer outlinePen.Dispose()
+
+	y := bounds.Y + (bounds.Height-size)/2
+
+	for i := 0; i < maxStars; i++ {
+		cell := Rectangle{bounds.X + i*size, y, size, size}
+		points := starPoints(cell)
+
+		if fraction := math.Min(1, rating-float64(i)); fraction > 0 {
+			clipRight := cell.X + int(math.Round(fraction*float64(size)))
+
+			if err := fillPolygonPixels(canvas, fillBrush, points, clipRight); err != nil {
+				return err
+			}
+		}
+
+		if err := canvas.DrawPolylinePixels(outlinePen, append(points, points[0])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// starPoints returns the corners of a five-pointed star inscribed in bounds.
+func starPoints(bounds Rectangle) []Point {
+	cx := float64(bounds.X) + float64(bounds.Width)/2
+	cy := float64(bounds.Y) + float64(bounds.Height)/2
+	outer := float64(mini(bounds.Width, bounds.Height))/2 - 1
+	inner := outer * 0.4
+
+	points := make([]Point, 10)
+	for i := range points {
+		r := outer
+		if i%2 == 1 {
+			r = inner
+		}
+
+		rad := (float64(i)*36 - 90) * math.Pi / 180
+
+		points[i] = Point{int(math.Round(cx + r*math.Cos(rad))), int(math.Round(cy + r*math.Sin(rad)))}
+	}
+
+	return points
+}
+
+// fillPolygonPixels fills the polygon described by points, left of clipRight,
+// using horizontal spans.
+func fillPolygonPixels(canvas *Canvas, brush Brush, points []Point, clipRight int) error {
+	minY, maxY := points[0].Y, points[0].Y
+	for _, p := range points {
+		minY, maxY = mini(minY, p.Y), maxi(maxY, p.Y)
+	}
+
+	var xs []float64
+
+	for y := minY; y < maxY; y++ {
+		scanY := float64(y) + 0.5
+
+		xs = xs[:0]
+		for i, p0 := range points {
+			p1 := points[(i+1)%len(points)]
+
+			y0, y1 := float64(p0.Y), float64(p1.Y)
+			if (scanY < y0) == (scanY < y1) {
+				continue
+			}
+
+			xs = append(xs, float64(p0.X)+(scanY-y0)/(y1-y0)*float64(p1.X-p0.X))
+		}
+
+		sort.Float64s(xs)
+
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0 := int(math.Round(xs[i]))
+			x1 := mini(int(math.Round(xs[i+1])), clipRight)
+
+			if x1 > x0 {
+				if err := canvas.FillRectanglePixels(brush, Rectangle{x0, y, x1 - x0, 1}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
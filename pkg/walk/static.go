@@ -28,6 +28,7 @@ func init() {
 type static struct {
 	WidgetBase
 	hwndStatic           win.HWND
+	hwndEdit             win.HWND // replaces hwndStatic while text is selectable
 	origStaticWndProcPtr uintptr
 	textAlignment        Alignment2D
 	textColor            Color
@@ -79,6 +80,11 @@ func (s *static) init(widget Widget, parent Container, style uint32) error {
 }
 
 func (s *static) Dispose() {
+	if s.hwndEdit != 0 {
+		win.DestroyWindow(s.hwndEdit)
+		s.hwndEdit = 0
+	}
+
 	if s.hwndStatic != 0 {
 		win.DestroyWindow(s.hwndStatic)
 		s.hwndStatic = 0
@@ -95,12 +101,130 @@ func (s *static) applyEnabled(enabled bool) {
 	s.WidgetBase.applyEnabled(enabled)
 
 	setWindowEnabled(s.hwndStatic, enabled)
+
+	if s.hwndEdit != 0 {
+		setWindowEnabled(s.hwndEdit, enabled)
+	}
 }
 
 func (s *static) applyFont(font *Font) {
 	s.WidgetBase.applyFont(font)
 
 	SetWindowFont(s.hwndStatic, font)
+
+	if s.hwndEdit != 0 {
+		SetWindowFont(s.hwndEdit, font)
+	}
+}
+
+// textHWND returns the child window that currently shows the text.
+func (s *static) textHWND() win.HWND {
+	if s.hwndEdit != 0 {
+		return s.hwndEdit
+	}
+
+	return s.hwndStatic
+}
+
+// selectable returns whether the text can be selected with the mouse.
+func (s *static) selectable() bool {
+	return s.hwndEdit != 0
+}
+
+// setSelectable replaces the static control with a read-only edit control,
+// whose text can be selected and copied, or the other way round.
+func (s *static) setSelectable(selectable bool) error {
+	if selectable == s.selectable() {
+		return nil
+	}
+
+	if selectable {
+		if err := s.createEdit(); err != nil {
+			return err
+		}
+
+		win.ShowWindow(s.hwndStatic, win.SW_HIDE)
+	} else {
+		win.DestroyWindow(s.hwndEdit)
+		s.hwndEdit = 0
+
+		win.ShowWindow(s.hwndStatic, win.SW_SHOW)
+	}
+
+	s.updateStaticBounds()
+
+	return nil
+}
+
+// recreateEdit recreates the edit control of a selectable static, for style
+// changes the edit control doesn't support after creation.
+func (s *static) recreateEdit() error {
+	if !s.selectable() {
+		return nil
+	}
+
+	win.DestroyWindow(s.hwndEdit)
+	s.hwndEdit = 0
+
+	if err := s.createEdit(); err != nil {
+		win.ShowWindow(s.hwndStatic, win.SW_SHOW)
+		return err
+	}
+
+	s.updateStaticBounds()
+
+	return nil
+}
+
+func (s *static) createEdit() error {
+	style := uint32(win.WS_CHILD | win.WS_CLIPSIBLINGS | win.WS_VISIBLE | win.ES_READONLY)
+
+	switch s.textAlignment1D() {
+	case AlignCenter:
+		style |= win.ES_CENTER
+
+	case AlignFar:
+		style |= win.ES_RIGHT
+	}
+
+	if s.wordWraps() {
+		style |= win.ES_MULTILINE
+	} else {
+		style |= win.ES_AUTOHSCROLL
+	}
+
+	if s.hwndEdit = win.CreateWindowEx(
+		0,
+		syscall.StringToUTF16Ptr("EDIT"),
+		nil,
+		style,
+		win.CW_USEDEFAULT,
+		win.CW_USEDEFAULT,
+		win.CW_USEDEFAULT,
+		win.CW_USEDEFAULT,
+		s.hWnd,
+		0,
+		0,
+		nil,
+	); s.hwndEdit == 0 {
+		return newError("creating edit failed")
+	}
+
+	// EC_LEFTMARGIN | EC_RIGHTMARGIN, so the text lines up with static text.
+	win.SendMessage(s.hwndEdit, win.EM_SETMARGINS, 0x1|0x2, 0)
+
+	SetWindowFont(s.hwndEdit, s.Font())
+	setWindowEnabled(s.hwndEdit, s.Enabled())
+
+	return setWindowText(s.hwndEdit, s.text())
+}
+
+func (s *static) wordWraps() bool {
+	if ww, ok := s.window.(interface{ WordWrap() bool }); ok {
+		return ww.WordWrap()
+	}
+
+	return false
 }
 
 func (s *static) textAlignment1D() Alignment1D {
@@ -157,6 +281,10 @@ func (s *static) setTextAlignment(alignment Alignment2D) error {
 
 	s.textAlignment = alignment
 
+	if err := s.recreateEdit(); err != nil {
+		return err
+	}
+
 	s.Invalidate()
 
 	return nil
@@ -175,6 +303,12 @@ func (s *static) setText(text string) (changed bool, err error) {
 		return false, err
 	}
 
+	if s.hwndEdit != 0 {
+		if err := setWindowText(s.hwndEdit, text); err != nil {
+			return false, err
+		}
+	}
+
 	s.RequestLayout()
 
 	return true, nil
@@ -227,7 +361,7 @@ func (s *static) updateStaticBounds() {
 
 	if shrinkable := s.shrinkable(); shrinkable || format&TextVCenter != 0 || format&TextBottom != 0 {
 		var size Size
-		if _, ok := s.window.(HeightForWidther); ok {
+		if _, ok := s.window.(HeightForWidther); ok || s.wordWraps() {
 			size = s.calculateTextSizeForWidth(cb.Width)
 		} else {
 			size = s.calculateTextSize()
@@ -252,7 +386,7 @@ func (s *static) updateStaticBounds() {
 		}
 	}
 
-	win.MoveWindow(s.hwndStatic, int32(cb.X), int32(cb.Y), int32(cb.Width), int32(cb.Height), true)
+	win.MoveWindow(s.textHWND(), int32(cb.X), int32(cb.Y), int32(cb.Width), int32(cb.Height), true)
 
 	s.Invalidate()
 }
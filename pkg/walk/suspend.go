@@ -0,0 +1,85 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// SuspendUpdates calls f with window suspended for layout and repainting, so
+// the batch of modifications f makes results in a single update afterwards.
+//
+// Calls may be nested. Only the outermost one resumes window, and only if
+// window wasn't suspended already before, so helpers can use SuspendUpdates
+// without knowing whether their caller did.
+func SuspendUpdates(window Window, f func()) {
+	defer suspendUpdates(window)()
+
+	f()
+}
+
+// suspendUpdates is the form of SuspendUpdates for use with defer:
+//
+//	defer suspendUpdates(window)()
+func suspendUpdates(window Window) (resume func()) {
+	if window.Suspended() {
+		return func() {}
+	}
+
+	window.SetSuspended(true)
+
+	return func() {
+		if !window.IsDisposed() {
+			window.SetSuspended(false)
+		}
+	}
+}
+
+// updateBurstThreshold is the number of model notifications a view receives
+// within one dispatch cycle of the message loop, at which it stops repainting
+// until the cycle completes.
+const updateBurstThreshold = 16
+
+// updateBurstSuspender detects bursts of model notifications, as caused by
+// code that publishes changes one by one in a loop, and suspends repainting of
+// a view for the rest of the dispatch cycle.
+type updateBurstSuspender struct {
+	window    Window
+	suspend   func(suspend bool)
+	count     int
+	suspended bool
+}
+
+func newUpdateBurstSuspender(window Window, suspend func(suspend bool)) *updateBurstSuspender {
+	return &updateBurstSuspender{window: window, suspend: suspend}
+}
+
+// notify counts a model notification.
+func (s *updateBurstSuspender) notify() {
+	if s.count == 0 {
+		// Runs once the current message is processed.
+		s.window.Synchronize(s.reset)
+	}
+
+	s.count++
+
+	if s.count == updateBurstThreshold && !s.window.Suspended() {
+		s.suspend(true)
+		s.suspended = true
+	}
+}
+
+func (s *updateBurstSuspender) reset() {
+	s.count = 0
+
+	if !s.suspended {
+		return
+	}
+
+	s.suspended = false
+
+	if !s.window.IsDisposed() {
+		s.suspend(false)
+	}
+}
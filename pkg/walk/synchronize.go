@@ -0,0 +1,167 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/miu200521358/win"
+)
+
+// ErrWindowClosed is returned by SynchronizeContext and SynchronizeResult
+// when f can't be called, because the window was disposed or the message
+// loop of its thread exited.
+var ErrWindowClosed = errors.New("window closed")
+
+// States of a call queued by SynchronizeContext.
+const (
+	syncCallPending int32 = iota
+	syncCallStarted
+	syncCallCanceled
+)
+
+// syncCall is a call queued by SynchronizeContext, whose caller waits for it.
+type syncCall struct {
+	window *WindowBase
+	state  int32
+	done   chan error
+}
+
+// fail makes the caller stop waiting with err, unless the call was started
+// or canceled already.
+func (c *syncCall) fail(err error) {
+	if atomic.CompareAndSwapInt32(&c.state, syncCallPending, syncCallCanceled) {
+		c.done <- err
+	}
+}
+
+// SynchronizeContext calls f on the goroutine of the message loop of the
+// *WindowBase, like Synchronize, but waits for it to return and returns its
+// error.
+//
+// If ctx is done before f was called, f is not called anymore and ctx.Err()
+// is returned. If f is already running, SynchronizeContext still returns
+// ctx.Err() right away, while f runs to completion.
+//
+// If the window is disposed, or the message loop of its thread exits, before
+// f was called, f is not called anymore and ErrWindowClosed is returned, so
+// callers never wait forever, even with context.Background().
+//
+// When called on the goroutine of the message loop already, f is called
+// directly, so this never deadlocks.
+func (wb *WindowBase) SynchronizeContext(ctx context.Context, f func() error) error {
+	if wb.hWnd == 0 {
+		return ErrWindowClosed
+	}
+
+	if win.GetCurrentThreadId() == wb.group.ThreadID() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		return f()
+	}
+
+	call := &syncCall{window: wb, done: make(chan error, 1)}
+
+	if !wb.group.addSyncCall(call) {
+		return ErrWindowClosed
+	}
+	defer wb.group.removeSyncCall(call)
+
+	wb.Synchronize(func() {
+		if !atomic.CompareAndSwapInt32(&call.state, syncCallPending, syncCallStarted) {
+			return
+		}
+
+		call.done <- f()
+	})
+
+	// The window may have been disposed before the call was added.
+	if wb.hWnd == 0 {
+		call.fail(ErrWindowClosed)
+	}
+
+	select {
+	case err := <-call.done:
+		return err
+
+	case <-ctx.Done():
+		atomic.CompareAndSwapInt32(&call.state, syncCallPending, syncCallCanceled)
+
+		return ctx.Err()
+	}
+}
+
+// addSyncCall records a pending call of SynchronizeContext, so it can be
+// failed by failSyncCalls. It returns false if the group was disposed.
+func (g *WindowGroup) addSyncCall(call *syncCall) bool {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+
+	if g.syncClosed {
+		return false
+	}
+
+	if g.syncCalls == nil {
+		g.syncCalls = make(map[*syncCall]bool)
+	}
+	g.syncCalls[call] = true
+
+	return true
+}
+
+// removeSyncCall reverts addSyncCall.
+func (g *WindowGroup) removeSyncCall(call *syncCall) {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+
+	delete(g.syncCalls, call)
+}
+
+// failSyncCalls fails the pending calls of SynchronizeContext for wb, or all
+// of them if wb is nil, with ErrWindowClosed.
+func (g *WindowGroup) failSyncCalls(wb *WindowBase) {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+
+	for call := range g.syncCalls {
+		if wb == nil || call.window == wb {
+			call.fail(ErrWindowClosed)
+			delete(g.syncCalls, call)
+		}
+	}
+}
+
+// SynchronizeResult calls f on the goroutine of the message loop of window
+// and returns its results, once it returned. See WindowBase.SynchronizeContext
+// for the details, including ErrWindowClosed.
+func SynchronizeResult[T any](window Window, f func() (T, error)) (T, error) {
+	return SynchronizeResultContext(context.Background(), window, f)
+}
+
+// SynchronizeResultContext is like SynchronizeResult, but stops waiting for
+// f when ctx is done, as WindowBase.SynchronizeContext does.
+func SynchronizeResultContext[T any](ctx context.Context, window Window, f func() (T, error)) (T, error) {
+	var result T
+
+	err := window.AsWindowBase().SynchronizeContext(ctx, func() error {
+		var err error
+		result, err = f()
+		return err
+	})
+	if err != nil && ctx.Err() != nil {
+		// f may still be running and writing result.
+		var zero T
+		return zero, err
+	}
+
+	return result, err
+}
@@ -81,6 +81,7 @@ type TableView struct {
 	rowsChangedHandlerHandle           int
 	rowsInsertedHandlerHandle          int
 	rowsRemovedHandlerHandle           int
+	updateBurstSuspender               *updateBurstSuspender
 	sortChangedHandlerHandle           int
 	selectedIndexes                    []int
 	prevIndex                          int
@@ -92,6 +93,7 @@ type TableView struct {
 	itemActivatedPublisher             EventPublisher
 	columnClickedPublisher             IntEventPublisher
 	columnsOrderableChangedPublisher   EventPublisher
+	columnChooserEnabled               bool
 	columnsSizableChangedPublisher     EventPublisher
 	itemCountChangedPublisher          EventPublisher
 	publishNextSelClear                bool
@@ -156,6 +158,7 @@ func NewTableViewWithCfg(parent Container, cfg *TableViewCfg) (*TableView, error
 	}
 
 	tv.columns = newTableViewColumnList(tv)
+	tv.updateBurstSuspender = newUpdateBurstSuspender(tv, tv.setRedraw)
 
 	if err := InitWidget(
 		tv,
@@ -241,10 +244,10 @@ func NewTableViewWithCfg(parent Container, cfg *TableViewCfg) (*TableView, error
 	win.SendMessage(tv.hwndFrozenLV, win.LVM_SETEXTENDEDLISTVIEWSTYLE, 0, exStyle)
 	win.SendMessage(tv.hwndNormalLV, win.LVM_SETEXTENDEDLISTVIEWSTYLE, 0, exStyle)
 
-	if hr := win.SetWindowTheme(tv.hwndFrozenLV, syscall.StringToUTF16Ptr("Explorer"), nil); win.FAILED(hr) {
+	if hr := setWindowTheme(tv.hwndFrozenLV, "Explorer"); win.FAILED(hr) {
 		return nil, errorFromHRESULT("SetWindowTheme", hr)
 	}
-	if hr := win.SetWindowTheme(tv.hwndNormalLV, syscall.StringToUTF16Ptr("Explorer"), nil); win.FAILED(hr) {
+	if hr := setWindowTheme(tv.hwndNormalLV, "Explorer"); win.FAILED(hr) {
 		return nil, errorFromHRESULT("SetWindowTheme", hr)
 	}
 
@@ -256,6 +259,7 @@ func NewTableViewWithCfg(parent Container, cfg *TableViewCfg) (*TableView, error
 
 	tv.applyFont(parent.Font())
 
+	tv.style.tv = tv
 	tv.style.dpi = tv.DPI()
 	tv.ApplySysColors()
 
@@ -415,17 +419,41 @@ func (tv *TableView) ApplyDPI(dpi int) {
 }
 
 func (tv *TableView) ApplySysColors() {
+	darkChanged := tv.darkThemeApplied != themeDark()
+
 	tv.WidgetBase.ApplySysColors()
 
+	if darkChanged {
+		// The list views and their headers aren't walk windows of their own.
+		for _, hwnd := range []win.HWND{tv.hwndFrozenLV, tv.hwndNormalLV} {
+			setWindowTheme(hwnd, "Explorer")
+		}
+		for _, hwnd := range []win.HWND{tv.hwndFrozenHdr, tv.hwndNormalHdr} {
+			applyWindowThemeAppName(hwnd)
+		}
+	}
+
 	// As some combinations of property and state may be invalid for any theme,
 	// we set some defaults here.
-	tv.themeNormalBGColor = Color(win.GetSysColor(win.COLOR_WINDOW))
-	tv.themeNormalTextColor = Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+	tv.themeNormalBGColor = themeSysColor(win.COLOR_WINDOW)
+	tv.themeNormalTextColor = themeSysColor(win.COLOR_WINDOWTEXT)
 	tv.themeSelectedBGColor = tv.themeNormalBGColor
 	tv.themeSelectedTextColor = tv.themeNormalTextColor
 	tv.themeSelectedNotFocusedBGColor = tv.themeNormalBGColor
-	tv.alternatingRowBGColor = Color(win.GetSysColor(win.COLOR_BTNFACE))
-	tv.alternatingRowTextColor = Color(win.GetSysColor(win.COLOR_BTNTEXT))
+	tv.alternatingRowBGColor = themeSysColor(win.COLOR_BTNFACE)
+	tv.alternatingRowTextColor = themeSysColor(win.COLOR_BTNTEXT)
+
+	if appTheme != nil {
+		// The application theme takes precedence over the visual styles.
+		tv.themeSelectedBGColor = appTheme.Color(ThemeRoleHighlight)
+		tv.themeSelectedTextColor = appTheme.Color(ThemeRoleHighlightText)
+		tv.themeSelectedNotFocusedBGColor = appTheme.Color(ThemeRoleBorder)
+		tv.alternatingRowBGColor = appTheme.Color(ThemeRoleAlternateRow)
+		tv.alternatingRowTextColor = appTheme.Color(ThemeRoleControlText)
+
+		tv.setListViewColors()
+		return
+	}
 
 	type item struct {
 		stateID    int32
@@ -468,8 +496,15 @@ func (tv *TableView) ApplySysColors() {
 		})
 	}
 
-	win.SendMessage(tv.hwndNormalLV, win.LVM_SETBKCOLOR, 0, uintptr(tv.themeNormalBGColor))
-	win.SendMessage(tv.hwndFrozenLV, win.LVM_SETBKCOLOR, 0, uintptr(tv.themeNormalBGColor))
+	tv.setListViewColors()
+}
+
+func (tv *TableView) setListViewColors() {
+	for _, hwnd := range []win.HWND{tv.hwndNormalLV, tv.hwndFrozenLV} {
+		win.SendMessage(hwnd, win.LVM_SETBKCOLOR, 0, uintptr(tv.themeNormalBGColor))
+		win.SendMessage(hwnd, win.LVM_SETTEXTBKCOLOR, 0, uintptr(tv.themeNormalBGColor))
+		win.SendMessage(hwnd, win.LVM_SETTEXTCOLOR, 0, uintptr(tv.themeNormalTextColor))
+	}
 }
 
 // ColumnsOrderable returns if the user can reorder columns by dragging and
@@ -553,7 +588,7 @@ func (tv *TableView) ContextMenuLocation() Point {
 	} else {
 		pt.X = rc.Left
 	}
-	pt.X = rc.Bottom
+	pt.Y = rc.Bottom
 	windowTrimToClientBounds(tv.hwndNormalLV, &pt)
 	win.ClientToScreen(tv.hwndNormalLV, &pt)
 	return pointPixelsFromPOINT(pt)
@@ -669,6 +704,18 @@ func (tv *TableView) Invalidate() error {
 	return tv.WidgetBase.Invalidate()
 }
 
+// setRedraw sets whether the list views of the TableView repaint.
+func (tv *TableView) setRedraw(redraw bool) {
+	wParam := uintptr(win.BoolToBOOL(redraw))
+
+	win.SendMessage(tv.hwndFrozenLV, win.WM_SETREDRAW, wParam, 0)
+	win.SendMessage(tv.hwndNormalLV, win.WM_SETREDRAW, wParam, 0)
+
+	if redraw {
+		tv.Invalidate()
+	}
+}
+
 func (tv *TableView) redrawItems() {
 	first := win.SendMessage(tv.hwndNormalLV, win.LVM_GETTOPINDEX, 0, 0)
 	last := first + win.SendMessage(tv.hwndNormalLV, win.LVM_GETCOUNTPERPAGE, 0, 0) + 1
@@ -723,6 +770,8 @@ func (tv *TableView) attachModel() {
 	}
 
 	tv.rowsResetHandlerHandle = tv.model.RowsReset().Attach(func() {
+		tv.updateBurstSuspender.notify()
+
 		tv.setItemCount()
 
 		if ip, ok := tv.providedModel.(IDProvider); ok && tv.restoringCurrentItemOnReset {
@@ -737,10 +786,14 @@ func (tv *TableView) attachModel() {
 	})
 
 	tv.rowChangedHandlerHandle = tv.model.RowChanged().Attach(func(row int) {
+		tv.updateBurstSuspender.notify()
+
 		tv.UpdateItem(row)
 	})
 
 	tv.rowsChangedHandlerHandle = tv.model.RowsChanged().Attach(func(from, to int) {
+		tv.updateBurstSuspender.notify()
+
 		if s, ok := tv.model.(Sorter); ok {
 			s.Sort(s.SortedColumn(), s.SortOrder())
 		} else {
@@ -751,6 +804,8 @@ func (tv *TableView) attachModel() {
 	})
 
 	tv.rowsInsertedHandlerHandle = tv.model.RowsInserted().Attach(func(from, to int) {
+		tv.updateBurstSuspender.notify()
+
 		i := tv.currentIndex
 
 		tv.setItemCount()
@@ -765,6 +820,8 @@ func (tv *TableView) attachModel() {
 	})
 
 	tv.rowsRemovedHandlerHandle = tv.model.RowsRemoved().Attach(func(from, to int) {
+		tv.updateBurstSuspender.notify()
+
 		i := tv.currentIndex
 
 		tv.setItemCount()
@@ -927,6 +984,19 @@ func (tv *TableView) SetCellStyler(styler CellStyler) {
 	tv.styler = styler
 }
 
+// cellStyler returns the CellStyler for the cells of the column at index col,
+// which is the one of the column, if it has one, or else that of the
+// TableView.
+func (tv *TableView) cellStyler(col int) CellStyler {
+	if col >= 0 && col < len(tv.columns.items) {
+		if styler := tv.columns.items[col].styler; styler != nil {
+			return styler
+		}
+	}
+
+	return tv.styler
+}
+
 func (tv *TableView) setItemCount() error {
 	var count int
 
@@ -1748,7 +1818,7 @@ func (tv *TableView) RestoreState() error {
 					break
 				}
 			}
-			if err := tvc.SetVisible(tvc.visible && (visible || tvcs.Visible)); err != nil {
+			if err := tvc.SetVisible(visible || tvcs.Visible); err != nil {
 				return err
 			}
 			if err := tvc.SetTitleOverride(tvcs.Title); err != nil {
@@ -2125,21 +2195,21 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 				(*buf)[max-1] = 0
 			}
 
-			if (tv.imageProvider != nil || tv.styler != nil) && di.Item.Mask&win.LVIF_IMAGE > 0 {
+			if (tv.imageProvider != nil || tv.cellStyler(col) != nil) && di.Item.Mask&win.LVIF_IMAGE > 0 {
 				var image interface{}
 				if di.Item.ISubItem == 0 {
 					if ip := tv.imageProvider; ip != nil && image == nil {
 						image = ip.Image(row)
 					}
 				}
-				if styler := tv.styler; styler != nil && image == nil {
+				if styler := tv.cellStyler(col); styler != nil && image == nil {
 					tv.style.row = row
 					tv.style.col = col
 					tv.style.bounds = Rectangle{}
 					tv.style.dpi = tv.DPI()
 					tv.style.Image = nil
 
-					styler.StyleCell(&tv.style)
+					tv.style.styleWith(styler)
 
 					image = tv.style.Image
 				}
@@ -2181,7 +2251,7 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 				}
 
 				applyCellStyle := func() int {
-					if tv.styler != nil {
+					if styler := tv.cellStyler(col); styler != nil {
 						dpi := tv.DPI()
 
 						tv.style.row = row
@@ -2194,7 +2264,7 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 						tv.style.Font = nil
 						tv.style.Image = nil
 
-						tv.styler.StyleCell(&tv.style)
+						tv.style.styleWith(styler)
 
 						defer func() {
 							tv.style.bounds = Rectangle{}
@@ -2255,7 +2325,7 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 						tv.style.Font = nil
 						tv.style.Image = nil
 
-						tv.styler.StyleCell(&tv.style)
+						tv.style.styleWith(tv.styler)
 
 						tv.itemFont = tv.style.Font
 					}
@@ -2498,6 +2568,12 @@ func tableViewHdrWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
 	}
 
 	switch msg {
+	case win.WM_CONTEXTMENU:
+		if tv.columnChooserEnabled {
+			tv.showColumnChooser(hwnd, lp)
+			return 0
+		}
+
 	case win.WM_NOTIFY:
 		switch ((*win.NMHDR)(unsafe.Pointer(lp))).Code {
 		case win.NM_CUSTOMDRAW:
@@ -2516,7 +2592,7 @@ func tableViewHdrWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
 
 			case win.CDDS_ITEMPOSTPAINT:
 				col := tv.fromLVColIdx(hwnd == tv.hwndFrozenHdr, int32(nmcd.DwItemSpec))
-				if tv.styler != nil && col > -1 {
+				if styler := tv.cellStyler(col); styler != nil && col > -1 {
 					tv.style.row = -1
 					tv.style.col = col
 					tv.style.bounds = rectangleFromRECT(nmcd.Rc)
@@ -2525,7 +2601,7 @@ func tableViewHdrWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
 					tv.style.TextColor = tv.themeNormalTextColor
 					tv.style.Font = nil
 
-					tv.styler.StyleCell(&tv.style)
+					tv.style.styleWith(styler)
 
 					defer func() {
 						tv.style.bounds = Rectangle{}
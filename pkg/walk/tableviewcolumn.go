@@ -27,16 +27,25 @@ type TableViewColumn struct {
 	width         int
 	lessFunc      func(i, j int) bool
 	formatFunc    func(value interface{}) string
+	styler        CellStyler
 	visible       bool
 	frozen        bool
+
+	// The layout the column was added with, for TableView.ResetColumnLayout.
+	defaultWidth   int
+	defaultVisible bool
+
+	// The position of the column in the header when it was hidden, or -1.
+	displayIndexHint int
 }
 
 // NewTableViewColumn returns a new TableViewColumn.
 func NewTableViewColumn() *TableViewColumn {
 	return &TableViewColumn{
-		format:  "%v",
-		visible: true,
-		width:   50,
+		format:           "%v",
+		visible:          true,
+		width:            50,
+		displayIndexHint: -1,
 	}
 }
 
@@ -220,11 +229,18 @@ func (tvc *TableViewColumn) Visible() bool {
 }
 
 // SetVisible sets if the column is visible.
+//
+// A column that is shown again gets back the width and the position in the
+// header it had when it was hidden.
 func (tvc *TableViewColumn) SetVisible(visible bool) (err error) {
 	if visible == tvc.visible {
 		return nil
 	}
 
+	if !visible && tvc.tv != nil {
+		tvc.displayIndexHint = tvc.displayIndex()
+	}
+
 	old := tvc.visible
 	defer func() {
 		if err != nil {
@@ -299,6 +315,11 @@ func (tvc *TableViewColumn) Width() int {
 		return tvc.width
 	}
 
+	return tvc.listViewWidth()
+}
+
+// listViewWidth returns the width of the column in the list view in 1/96".
+func (tvc *TableViewColumn) listViewWidth() int {
 	// We call win.SendMessage instead of tvc.sendMessage here, because some
 	// call inside the latter interferes with scrolling via scroll bar button
 	// when *TableViewColumn.Width is called from *TableView.StretchLastColumn.
@@ -354,6 +375,24 @@ func (tvc *TableViewColumn) SetFormatFunc(formatFunc func(value interface{}) str
 	tvc.formatFunc = formatFunc
 }
 
+// CellStyler returns the CellStyler for the cells of the column, or nil if
+// the CellStyler of the TableView styles them.
+func (tvc *TableViewColumn) CellStyler() CellStyler {
+	return tvc.styler
+}
+
+// SetCellStyler sets the CellStyler for the cells of the column, including its
+// header. It is used instead of the CellStyler of the TableView, which is then
+// only called for the cells of the other columns and for whole rows, so
+// columns without special styling need no callbacks.
+func (tvc *TableViewColumn) SetCellStyler(styler CellStyler) {
+	tvc.styler = styler
+
+	if tvc.tv != nil {
+		tvc.tv.Invalidate()
+	}
+}
+
 func (tvc *TableViewColumn) indexInListView() int32 {
 	if tvc.tv == nil {
 		return -1
@@ -378,6 +417,40 @@ func (tvc *TableViewColumn) indexInListView() int32 {
 	return idx
 }
 
+// siblingCount returns the number of the other visible columns in the list
+// view of the column.
+func (tvc *TableViewColumn) siblingCount() int {
+	var count int
+
+	for _, c := range tvc.tv.columns.items {
+		if c != tvc && c.visible && c.frozen == tvc.frozen {
+			count++
+		}
+	}
+
+	return count
+}
+
+// displayIndex returns the position of the visible column in the header of
+// its list view, which differs from its index if the user reordered columns.
+func (tvc *TableViewColumn) displayIndex() int {
+	index := tvc.indexInListView()
+
+	count := tvc.siblingCount() + 1
+	order := make([]int32, count)
+	if 0 == tvc.sendMessage(win.LVM_GETCOLUMNORDERARRAY, uintptr(count), uintptr(unsafe.Pointer(&order[0]))) {
+		return int(index)
+	}
+
+	for i, idx := range order {
+		if idx == index {
+			return i
+		}
+	}
+
+	return int(index)
+}
+
 func (tvc *TableViewColumn) create() error {
 	var lvc win.LVCOLUMN
 
@@ -401,6 +474,16 @@ func (tvc *TableViewColumn) create() error {
 		lvc.Fmt = 1
 	}
 
+	if tvc.displayIndexHint >= 0 {
+		lvc.Mask |= win.LVCF_ORDER
+		lvc.IOrder = int32(tvc.displayIndexHint)
+		if count := tvc.siblingCount(); int32(count) < lvc.IOrder {
+			lvc.IOrder = int32(count)
+		}
+
+		tvc.displayIndexHint = -1
+	}
+
 	if -1 == int(tvc.sendMessage(win.LVM_INSERTCOLUMN, uintptr(index), uintptr(unsafe.Pointer(&lvc)))) {
 		return newError("LVM_INSERTCOLUMN")
 	}
@@ -411,7 +494,7 @@ func (tvc *TableViewColumn) create() error {
 }
 
 func (tvc *TableViewColumn) destroy() error {
-	width := tvc.Width()
+	width := tvc.listViewWidth()
 
 	if win.FALSE == tvc.sendMessage(win.LVM_DELETECOLUMN, uintptr(tvc.indexInListView()), 0) {
 		return newError("LVM_DELETECOLUMN")
@@ -0,0 +1,150 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// ColumnChooserEnabled returns if right-clicking the header shows a menu to
+// show and hide columns and to reset their layout.
+func (tv *TableView) ColumnChooserEnabled() bool {
+	return tv.columnChooserEnabled
+}
+
+// SetColumnChooserEnabled sets if right-clicking the header shows a menu to
+// show and hide columns and to reset their layout.
+func (tv *TableView) SetColumnChooserEnabled(enabled bool) {
+	tv.columnChooserEnabled = enabled
+}
+
+// ResetColumnLayout restores the visibility and width the columns were added
+// with, removes title overrides and puts them back in their original order.
+func (tv *TableView) ResetColumnLayout() error {
+	tv.SetSuspended(true)
+	defer tv.SetSuspended(false)
+
+	for _, tvc := range tv.columns.items {
+		tvc.displayIndexHint = -1
+
+		if err := tvc.SetVisible(tvc.defaultVisible); err != nil {
+			return err
+		}
+		if err := tvc.SetTitleOverride(""); err != nil {
+			return err
+		}
+		if err := tvc.SetWidth(tvc.defaultWidth); err != nil {
+			return err
+		}
+	}
+
+	frozenCount := tv.visibleFrozenColumnCount()
+	normalCount := tv.visibleColumnCount() - frozenCount
+
+	for _, lv := range []struct {
+		hwnd  win.HWND
+		count int
+	}{
+		{tv.hwndFrozenLV, frozenCount},
+		{tv.hwndNormalLV, normalCount},
+	} {
+		if lv.count == 0 {
+			continue
+		}
+
+		indices := make([]int32, lv.count)
+		for i := range indices {
+			indices[i] = int32(i)
+		}
+
+		if 0 == win.SendMessage(lv.hwnd, win.LVM_SETCOLUMNORDERARRAY, uintptr(lv.count), uintptr(unsafe.Pointer(&indices[0]))) {
+			return newError("LVM_SETCOLUMNORDERARRAY")
+		}
+	}
+
+	tv.Invalidate()
+
+	return nil
+}
+
+// showColumnChooser shows the column chooser menu for a WM_CONTEXTMENU of the
+// header hwndHdr.
+func (tv *TableView) showColumnChooser(hwndHdr win.HWND, lParam uintptr) {
+	hMenu := win.CreatePopupMenu()
+	if hMenu == 0 {
+		return
+	}
+	defer win.DestroyMenu(hMenu)
+
+	insertItem := func(pos int, mii *win.MENUITEMINFO) bool {
+		mii.CbSize = uint32(unsafe.Sizeof(*mii))
+		return win.InsertMenuItem(hMenu, uint32(pos), true, mii)
+	}
+
+	visibleCount := tv.visibleColumnCount()
+
+	for i, tvc := range tv.columns.items {
+		title := syscall.StringToUTF16(tvc.TitleEffective())
+
+		mii := win.MENUITEMINFO{
+			FMask:      win.MIIM_ID | win.MIIM_STATE | win.MIIM_STRING,
+			WID:        uint32(i + 1),
+			DwTypeData: &title[0],
+			Cch:        uint32(len(title) - 1),
+		}
+		if tvc.visible {
+			mii.FState = win.MFS_CHECKED
+
+			// The last visible column can't be hidden.
+			if visibleCount == 1 {
+				mii.FState |= win.MFS_DISABLED
+			}
+		}
+
+		if !insertItem(i, &mii) {
+			return
+		}
+	}
+
+	count := tv.columns.Len()
+
+	if !insertItem(count, &win.MENUITEMINFO{FMask: win.MIIM_FTYPE, FType: win.MFT_SEPARATOR}) {
+		return
+	}
+
+	reset := syscall.StringToUTF16(tr("Reset Column Layout"))
+	resetID := count + 1
+	if !insertItem(count+1, &win.MENUITEMINFO{
+		FMask:      win.MIIM_ID | win.MIIM_STRING,
+		WID:        uint32(resetID),
+		DwTypeData: &reset[0],
+		Cch:        uint32(len(reset) - 1),
+	}) {
+		return
+	}
+
+	x, y := win.GET_X_LPARAM(lParam), win.GET_Y_LPARAM(lParam)
+	if x == -1 && y == -1 {
+		var rc win.RECT
+		win.GetWindowRect(hwndHdr, &rc)
+		x, y = rc.Left, rc.Bottom
+	}
+
+	id := int(win.TrackPopupMenuEx(hMenu, win.TPM_NOANIMATION|win.TPM_RETURNCMD, x, y, tv.hWnd, nil))
+	switch {
+	case id == resetID:
+		tv.ResetColumnLayout()
+
+	case id > 0 && id <= count:
+		tvc := tv.columns.items[id-1]
+		tvc.SetVisible(!tvc.visible)
+	}
+}
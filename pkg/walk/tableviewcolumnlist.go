@@ -78,6 +78,8 @@ func (l *TableViewColumnList) Insert(index int, item *TableViewColumn) error {
 	}
 
 	item.tv = l.tv
+	item.defaultWidth = item.width
+	item.defaultVisible = item.visible
 
 	if item.visible {
 		if err := item.create(); err != nil {
@@ -112,8 +114,10 @@ func (l *TableViewColumnList) Remove(item *TableViewColumn) error {
 func (l *TableViewColumnList) RemoveAt(index int) error {
 	tvc := l.items[index]
 
-	if err := tvc.destroy(); err != nil {
-		return err
+	if tvc.visible {
+		if err := tvc.destroy(); err != nil {
+			return err
+		}
 	}
 
 	tvc.tv = nil
@@ -0,0 +1,90 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+type tabPageClosingEventHandlerInfo struct {
+	handler TabPageClosingEventHandler
+	once    bool
+}
+
+type TabPageClosingEventHandler func(page *TabPage, canceled *bool)
+
+type TabPageClosingEvent struct {
+	handlers   []*tabPageClosingEventHandlerInfo
+	publishing int // Number of Publish calls in progress
+}
+
+func (e *TabPageClosingEvent) Attach(handler TabPageClosingEventHandler) int {
+	handlerInfo := &tabPageClosingEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*tabPageClosingEventHandlerInfo(nil), e.handlers...)
+	}
+
+	for i, h := range e.handlers {
+		if h.handler == nil {
+			e.handlers[i] = handlerInfo
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handlerInfo)
+
+	return len(e.handlers) - 1
+}
+
+func (e *TabPageClosingEvent) Detach(handle int) {
+	e.handlers[handle].handler = nil
+}
+
+func (e *TabPageClosingEvent) Once(handler TabPageClosingEventHandler) {
+	i := e.Attach(handler)
+	e.handlers[i].once = true
+}
+
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *TabPageClosingEvent) Connect(handler TabPageClosingEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *TabPageClosingEvent) AttachOwned(owner Window, handler TabPageClosingEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
+type TabPageClosingEventPublisher struct {
+	event TabPageClosingEvent
+}
+
+func (p *TabPageClosingEventPublisher) Event() *TabPageClosingEvent {
+	return &p.event
+}
+
+func (p *TabPageClosingEventPublisher) Publish(page *TabPage, canceled *bool) {
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
+
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
+			if h.once {
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
+			}
+
+			handler(page, canceled)
+		}
+	}
+}
@@ -34,10 +34,30 @@ type TabWidget struct {
 	currentIndexChangedPublisher EventPublisher
 	nonClientSizePixels          Size
 	persistent                   bool
+	pageDragOutHandler           func(page *TabPage)
+	pageDragCandidate            *TabPage
+	pageDragOrigin               win.POINT
+	pageDragLastPos              int32
+	pageDragReordered            bool
+	tabsClosable                 bool
+	tabsReorderable              bool
+	newPageButtonVisible         bool
+	hotCloseIndex                int
+	pressedCloseIndex            int
+	hotNewPageButton             bool
+	pressedNewPageButton         bool
+	mouseTracked                 bool
+	hotOverflowButton            bool
+	pressedOverflowButton        bool
+	tabPosition                  TabPosition
+	wheelDelta                   int
+	pageClosingPublisher         TabPageClosingEventPublisher
+	pagesReorderedPublisher      EventPublisher
+	newPageRequestedPublisher    EventPublisher
 }
 
 func NewTabWidget(parent Container) (*TabWidget, error) {
-	tw := &TabWidget{currentIndex: -1}
+	tw := &TabWidget{currentIndex: -1, hotCloseIndex: -1, pressedCloseIndex: -1}
 	tw.pages = newTabPageList(tw)
 
 	if err := InitWidget(
@@ -69,7 +89,7 @@ func NewTabWidget(parent Container) (*TabWidget, error) {
 	win.SetWindowLongPtr(tw.hWndTab, win.GWLP_USERDATA, uintptr(unsafe.Pointer(tw)))
 	tw.tabOrigWndProcPtr = win.SetWindowLongPtr(tw.hWndTab, win.GWLP_WNDPROC, tabWidgetTabWndProcPtr)
 
-	dpi := int(win.GetDpiForWindow(tw.hWndTab))
+	dpi := dpiForWindow(tw.hWndTab)
 	win.SendMessage(tw.hWndTab, win.WM_SETFONT, uintptr(defaultFont.handleForDPI(dpi)), 1)
 
 	tw.applyFont(tw.Font())
@@ -145,8 +165,12 @@ func (tw *TabWidget) ApplyDPI(dpi int) {
 
 	tw.imageList = iml
 
-	for _, page := range tw.pages.items {
-		tw.onPageChanged(page)
+	if tw.tabsClosable {
+		tw.updateTabPadding()
+	} else {
+		for _, page := range tw.pages.items {
+			tw.onPageChanged(page)
+		}
 	}
 }
 
@@ -242,34 +266,38 @@ func (tw *TabWidget) resizePages() {
 // pageBounds returns page bounds in native pixels.
 func (tw *TabWidget) pageBounds() Rectangle {
 	var r win.RECT
-	if !win.GetWindowRect(tw.hWndTab, &r) {
-		lastError("GetWindowRect")
+	if !win.GetClientRect(tw.hWndTab, &r) {
+		lastError("GetClientRect")
 		return Rectangle{}
 	}
 
-	p := win.POINT{
-		r.Left,
-		r.Top,
-	}
-	if !win.ScreenToClient(tw.hWnd, &p) {
-		newError("ScreenToClient failed")
-		return Rectangle{}
+	size := Size{int(r.Right - r.Left), int(r.Bottom - r.Top)}
+	m := tw.pageMargins(size)
+
+	return Rectangle{
+		m.HNear,
+		m.VNear,
+		size.Width - m.HNear - m.HFar,
+		size.Height - m.VNear - m.VFar,
 	}
+}
 
-	r = win.RECT{
-		p.X,
-		p.Y,
-		r.Right - r.Left + p.X,
-		r.Bottom - r.Top + p.Y,
+// pageMargins returns the distances of the pages from the edges of the tab
+// control of size, in native pixels.
+func (tw *TabWidget) pageMargins(size Size) Margins {
+	if size.Width <= 0 || size.Height <= 0 {
+		size = Size{1000, 1000}
 	}
+
+	r := win.RECT{Right: int32(size.Width), Bottom: int32(size.Height)}
 	win.SendMessage(tw.hWndTab, win.TCM_ADJUSTRECT, 0, uintptr(unsafe.Pointer(&r)))
 
-	adjustment := 2 * int32(tw.IntFrom96DPI(1))
-	return Rectangle{
-		int(r.Left - adjustment),
-		int(r.Top),
-		int(r.Right - r.Left + adjustment),
-		int(r.Bottom - r.Top),
+	adjustment := 2 * tw.IntFrom96DPI(1)
+	return Margins{
+		HNear: int(r.Left) - adjustment,
+		VNear: int(r.Top),
+		HFar:  size.Width - int(r.Right),
+		VFar:  size.Height - int(r.Bottom),
 	}
 }
 
@@ -348,163 +376,230 @@ var tabWidgetTabWndProcPtr uintptr
 func tabWidgetTabWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	tw := (*TabWidget)(unsafe.Pointer(win.GetWindowLongPtr(hwnd, win.GWLP_USERDATA)))
 
+	if tw.tabButtonsWndProc(hwnd, msg, wParam, lParam) {
+		return 0
+	}
+
 	switch msg {
+	case win.WM_LBUTTONDOWN:
+		if tw.pageDragOutHandler != nil || tw.tabsReorderable {
+			tw.pageDragOrigin = win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}
+			tw.pageDragCandidate = tw.pageAt(tw.pageDragOrigin)
+		}
+
+	case win.WM_LBUTTONUP:
+		tw.pageDragCandidate = nil
+
 	case win.WM_MOUSEMOVE:
 		win.InvalidateRect(hwnd, nil, true)
 
+		if page := tw.pageDragCandidate; page != nil && wParam&win.MK_LBUTTON != 0 {
+			dx := win.GET_X_LPARAM(lParam) - tw.pageDragOrigin.X
+			dy := win.GET_Y_LPARAM(lParam) - tw.pageDragOrigin.Y
+			if dx < 0 {
+				dx = -dx
+			}
+			if dy < 0 {
+				dy = -dy
+			}
+
+			if tw.pageDragOutHandler != nil && (dx > win.GetSystemMetrics(win.SM_CXDRAG) || dy > win.GetSystemMetrics(win.SM_CYDRAG)) {
+				tw.pageDragCandidate = nil
+
+				if hasCapture(hwnd) {
+					win.ReleaseCapture()
+				}
+
+				// The handler may move the page to another TabWidget, so we
+				// must not run it from within the tab control's WndProc.
+				handler := tw.pageDragOutHandler
+				tw.Synchronize(func() {
+					handler(page)
+				})
+			}
+		}
+
 	case win.WM_ERASEBKGND:
 		return 1
 
 	case win.WM_PAINT:
-		var ps win.PAINTSTRUCT
+		if paintBuffered(hwnd, tw.DPI(), tw.paintTabs) {
+			return 0
+		}
+	}
 
-		hdc := win.BeginPaint(hwnd, &ps)
-		defer win.EndPaint(hwnd, &ps)
+	return win.CallWindowProc(tw.tabOrigWndProcPtr, hwnd, msg, wParam, lParam)
+}
 
-		cb := tw.ClientBoundsPixels()
+// paintTabs draws the tab control, cb being its client bounds in native
+// pixels, including the backgrounds of the free area and the current tab,
+// which the control itself doesn't.
+func (tw *TabWidget) paintTabs(canvas *Canvas, cb Rectangle) error {
+	dpi := tw.DPI()
 
-		dpi := tw.DPI()
-		bitmap, err := NewBitmapForDPI(cb.Size(), dpi)
-		if err != nil {
-			break
-		}
-		defer bitmap.Dispose()
+	themed := tw.tabsThemed()
 
-		canvas, err := NewCanvasFromImage(bitmap)
-		if err != nil {
-			break
+	if !themed {
+		if err := canvas.FillRectanglePixels(sysColorBtnFaceBrush, cb); err != nil {
+			return err
 		}
-		defer canvas.Dispose()
+	}
 
-		themed := win.IsAppThemed()
+	win.SendMessage(tw.hWndTab, win.WM_PRINTCLIENT, uintptr(canvas.hdc), uintptr(win.PRF_CLIENT|win.PRF_CHILDREN|win.PRF_ERASEBKGND))
 
-		if !themed {
-			if err := canvas.FillRectanglePixels(sysColorBtnFaceBrush, cb); err != nil {
-				break
-			}
-		}
+	parent := tw.Parent()
+	if parent == nil {
+		return newErr("parent is nil")
+	}
 
-		win.SendMessage(hwnd, win.WM_PRINTCLIENT, uintptr(canvas.hdc), uintptr(win.PRF_CLIENT|win.PRF_CHILDREN|win.PRF_ERASEBKGND))
+	// Draw background of free area not occupied by tab items.
+	if bg, wnd := parent.AsWindowBase().backgroundEffective(); bg != nil {
+		tw.prepareDCForBackground(canvas.hdc, tw.hWndTab, wnd)
 
-		parent := tw.Parent()
-		if parent == nil {
-			break
-		}
+		hRgn := win.CreateRectRgn(0, 0, 0, 0)
+		defer win.DeleteObject(win.HGDIOBJ(hRgn))
 
-		// Draw background of free area not occupied by tab items.
-		if bg, wnd := parent.AsWindowBase().backgroundEffective(); bg != nil {
-			tw.prepareDCForBackground(canvas.hdc, hwnd, wnd)
+		var rc, items win.RECT
 
-			hRgn := win.CreateRectRgn(0, 0, 0, 0)
-			defer win.DeleteObject(win.HGDIOBJ(hRgn))
+		adjustment := SizeFrom96DPI(Size{1, 1}, dpi).toSIZE()
+		count := tw.pages.Len()
+		for i := 0; i < count; i++ {
+			if 0 == win.SendMessage(tw.hWndTab, win.TCM_GETITEMRECT, uintptr(i), uintptr(unsafe.Pointer(&rc))) {
+				break
+			}
 
-			var rc win.RECT
+			if i == 0 {
+				items = rc
+			} else {
+				items = unionRECT(items, rc)
+			}
 
-			adjustment := SizeFrom96DPI(Size{1, 1}, dpi).toSIZE()
-			count := tw.pages.Len()
-			for i := 0; i < count; i++ {
-				if 0 == win.SendMessage(hwnd, win.TCM_GETITEMRECT, uintptr(i), uintptr(unsafe.Pointer(&rc))) {
-					break
-				}
+			if i == tw.currentIndex {
+				// The current tab item sticks out towards the edge.
+				switch tw.tabPosition {
+				case TabPositionTop:
+					rc.Left -= 2 * adjustment.CX
+					rc.Top -= 2 * adjustment.CY
+					rc.Right += 2 * adjustment.CX
+
+				case TabPositionBottom:
+					rc.Left -= 2 * adjustment.CX
+					rc.Bottom += 2 * adjustment.CY
+					rc.Right += 2 * adjustment.CX
 
-				if i == tw.currentIndex {
+				case TabPositionLeft:
 					rc.Left -= 2 * adjustment.CX
 					rc.Top -= 2 * adjustment.CY
+					rc.Bottom += 2 * adjustment.CY
+
+				case TabPositionRight:
 					rc.Right += 2 * adjustment.CX
-				} else {
-					if i == count-1 && themed {
-						rc.Right -= 2 * adjustment.CX
-					}
+					rc.Top -= 2 * adjustment.CY
+					rc.Bottom += 2 * adjustment.CY
+				}
+			} else {
+				if i == count-1 && themed && !tw.tabsVertical() {
+					rc.Right -= 2 * adjustment.CX
 				}
-
-				hRgnTab := win.CreateRectRgn(rc.Left, rc.Top, rc.Right, rc.Bottom)
-				win.CombineRgn(hRgn, hRgn, hRgnTab, win.RGN_OR)
-				win.DeleteObject(win.HGDIOBJ(hRgnTab))
 			}
 
-			hRgnRC := win.CreateRectRgn(0, 0, int32(cb.Width), rc.Bottom)
-			win.CombineRgn(hRgn, hRgnRC, hRgn, win.RGN_DIFF)
-			win.DeleteObject(win.HGDIOBJ(hRgnRC))
+			hRgnTab := win.CreateRectRgn(rc.Left, rc.Top, rc.Right, rc.Bottom)
+			win.CombineRgn(hRgn, hRgn, hRgnTab, win.RGN_OR)
+			win.DeleteObject(win.HGDIOBJ(hRgnTab))
+		}
 
-			if !win.FillRgn(canvas.hdc, hRgn, bg.handle()) {
-				break
-			}
+		strip := tw.tabStripRect(items, cb.Size())
+		hRgnRC := win.CreateRectRgn(strip.Left, strip.Top, strip.Right, strip.Bottom)
+		win.CombineRgn(hRgn, hRgnRC, hRgn, win.RGN_DIFF)
+		win.DeleteObject(win.HGDIOBJ(hRgnRC))
+
+		if !win.FillRgn(canvas.hdc, hRgn, bg.handle()) {
+			return newErr("FillRgn failed")
 		}
+	}
 
-		// Draw current tab item.
-		if tw.currentIndex != -1 {
-			page := tw.pages.At(tw.CurrentIndex())
+	// Draw current tab item.
+	if tw.currentIndex != -1 && tw.tabPosition == TabPositionTop {
+		page := tw.pages.At(tw.CurrentIndex())
 
-			if bg, wnd := page.AsWindowBase().backgroundEffective(); bg != nil &&
-				bg != tabPageBackgroundBrush &&
-				(page.layout == nil || !page.layout.Margins().isZero()) {
+		if bg, wnd := page.AsWindowBase().backgroundEffective(); bg != nil &&
+			bg != tabPageBackgroundBrush &&
+			(page.layout == nil || !page.layout.Margins().isZero()) {
 
-				tw.prepareDCForBackground(canvas.hdc, hwnd, wnd)
+			tw.prepareDCForBackground(canvas.hdc, tw.hWndTab, wnd)
 
-				var rc win.RECT
-				if 0 == win.SendMessage(hwnd, win.TCM_GETITEMRECT, uintptr(tw.currentIndex), uintptr(unsafe.Pointer(&rc))) {
-					break
-				}
+			var rc win.RECT
+			if 0 == win.SendMessage(tw.hWndTab, win.TCM_GETITEMRECT, uintptr(tw.currentIndex), uintptr(unsafe.Pointer(&rc))) {
+				return newErr("TCM_GETITEMRECT failed")
+			}
 
-				adjustment := SizeFrom96DPI(Size{6, 1}, dpi).toSIZE()
-				hRgn := win.CreateRectRgn(rc.Left, rc.Top, rc.Right, rc.Bottom+2*adjustment.CY)
-				defer win.DeleteObject(win.HGDIOBJ(hRgn))
-				if !win.FillRgn(canvas.hdc, hRgn, bg.handle()) {
-					break
-				}
+			adjustment := SizeFrom96DPI(Size{6, 1}, dpi).toSIZE()
+			hRgn := win.CreateRectRgn(rc.Left, rc.Top, rc.Right, rc.Bottom+2*adjustment.CY)
+			defer win.DeleteObject(win.HGDIOBJ(hRgn))
+			if !win.FillRgn(canvas.hdc, hRgn, bg.handle()) {
+				return newErr("FillRgn failed")
+			}
 
-				if page.image != nil {
-					x := rc.Left + adjustment.CX
-					y := rc.Top
-					s := int32(IntFrom96DPI(16, dpi))
-
-					bmp, err := iconCache.Bitmap(page.image, dpi)
-					if err == nil {
-						if imageCanvas, err := NewCanvasFromImage(bmp); err == nil {
-							defer imageCanvas.Dispose()
-
-							if !win.TransparentBlt(
-								canvas.hdc, x, y, s, s,
-								imageCanvas.hdc, 0, 0, int32(bmp.size.Width), int32(bmp.size.Height),
-								0) {
-								break
-							}
+			if page.image != nil {
+				x := rc.Left + adjustment.CX
+				y := rc.Top
+				s := int32(IntFrom96DPI(16, dpi))
+
+				bmp, err := iconCache.Bitmap(page.image, dpi)
+				if err == nil {
+					if imageCanvas, err := NewCanvasFromImage(bmp); err == nil {
+						defer imageCanvas.Dispose()
+
+						if !win.TransparentBlt(
+							canvas.hdc, x, y, s, s,
+							imageCanvas.hdc, 0, 0, int32(bmp.size.Width), int32(bmp.size.Height),
+							0) {
+							return newErr("TransparentBlt failed")
 						}
-
-						rc.Left += s + adjustment.CX
 					}
+
+					rc.Left += s + adjustment.CX
 				}
+			}
 
-				rc.Left += adjustment.CX
-				rc.Top += adjustment.CY
+			rc.Left += adjustment.CX
+			rc.Top += adjustment.CY
 
-				title := syscall.StringToUTF16(page.title)
+			title := syscall.StringToUTF16(page.title)
 
-				if themed {
-					hTheme := win.OpenThemeData(hwnd, syscall.StringToUTF16Ptr("tab"))
-					defer win.CloseThemeData(hTheme)
+			if themed {
+				hTheme := win.OpenThemeData(tw.hWndTab, syscall.StringToUTF16Ptr("tab"))
+				defer win.CloseThemeData(hTheme)
 
-					options := win.DTTOPTS{DwFlags: win.DTT_GLOWSIZE, IGlowSize: int32(IntFrom96DPI(3, dpi))}
-					options.DwSize = uint32(unsafe.Sizeof(options))
-					if hr := win.DrawThemeTextEx(hTheme, canvas.hdc, 0, win.TIS_SELECTED, &title[0], int32(len(title)), 0, &rc, &options); !win.SUCCEEDED(hr) {
-						break
-					}
-				} else {
-					if 0 == win.DrawTextEx(canvas.hdc, &title[0], int32(len(title)), &rc, 0, nil) {
-						break
-					}
+				options := win.DTTOPTS{DwFlags: win.DTT_GLOWSIZE, IGlowSize: int32(IntFrom96DPI(3, dpi))}
+				options.DwSize = uint32(unsafe.Sizeof(options))
+				if hr := win.DrawThemeTextEx(hTheme, canvas.hdc, 0, win.TIS_SELECTED, &title[0], int32(len(title)), 0, &rc, &options); !win.SUCCEEDED(hr) {
+					return newErr("DrawThemeTextEx failed")
+				}
+			} else {
+				if 0 == win.DrawTextEx(canvas.hdc, &title[0], int32(len(title)), &rc, 0, nil) {
+					return newErr("DrawTextEx failed")
 				}
 			}
 		}
+	}
 
-		if !win.BitBlt(hdc, 0, 0, int32(cb.Width), int32(cb.Height), canvas.hdc, 0, 0, win.SRCCOPY) {
-			break
-		}
+	tw.paintTabButtons(canvas)
 
-		return 0
+	return nil
+}
+
+// pageAt returns the page whose tab contains pt, which is expected in client
+// coordinates of the tab control, or nil.
+func (tw *TabWidget) pageAt(pt win.POINT) *TabPage {
+	hti := win.TCHITTESTINFO{Pt: pt}
+
+	index := int(int32(win.SendMessage(tw.hWndTab, win.TCM_HITTEST, 0, uintptr(unsafe.Pointer(&hti)))))
+	if index < 0 || index >= tw.pages.Len() {
+		return nil
 	}
 
-	return win.CallWindowProc(tw.tabOrigWndProcPtr, hwnd, msg, wParam, lParam)
+	return tw.pages.At(index)
 }
 
 func (tw *TabWidget) onPageChanged(page *TabPage) (err error) {
@@ -699,10 +794,8 @@ func (tw *TabWidget) updateNonClientSize() {
 func (tw *TabWidget) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 	pages := make([]LayoutItem, tw.pages.Len())
 
-	bounds := tw.pageBounds()
-
 	li := &tabWidgetLayoutItem{
-		pagePos:             bounds.Location(),
+		pageMargins:         tw.pageMargins(tw.ClientBoundsPixels().Size()),
 		currentIndex:        tw.CurrentIndex(),
 		nonClientSizePixels: tw.nonClientSizePixels,
 	}
@@ -729,7 +822,7 @@ func (tw *TabWidget) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 type tabWidgetLayoutItem struct {
 	ContainerLayoutItemBase
 	nonClientSizePixels Size
-	pagePos             Point // in native pixels
+	pageMargins         Margins // in native pixels
 	currentIndex        int
 }
 
@@ -814,16 +907,16 @@ func (li *tabWidgetLayoutItem) IdealSize() Size {
 func (li *tabWidgetLayoutItem) PerformLayout() []LayoutResultItem {
 	if li.currentIndex > -1 {
 		page := li.children[li.currentIndex]
+		m := li.pageMargins
 
-		adjustment := IntFrom96DPI(1, li.ctx.dpi)
 		return []LayoutResultItem{
 			{
 				Item: page,
 				Bounds: Rectangle{
-					X:      li.pagePos.X,
-					Y:      li.pagePos.Y,
-					Width:  li.geometry.Size.Width - li.pagePos.X*2 - adjustment,
-					Height: li.geometry.Size.Height - li.pagePos.Y - 2*adjustment,
+					X:      m.HNear,
+					Y:      m.VNear,
+					Width:  li.geometry.Size.Width - m.HNear - m.HFar,
+					Height: li.geometry.Size.Height - m.VNear - m.VFar,
 				},
 			},
 		}
@@ -0,0 +1,272 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// TabPosition specifies at which edge of a TabWidget the tabs are placed.
+//
+// In right-to-left layouts, left and right are mirrored like all coordinates.
+type TabPosition int
+
+const (
+	TabPositionTop TabPosition = iota
+	TabPositionBottom
+	TabPositionLeft
+	TabPositionRight
+)
+
+const layoutRTL = 0x00000001
+
+var setLayout = syscall.NewLazyDLL("gdi32.dll").NewProc("SetLayout")
+
+// TabPosition returns at which edge of the TabWidget the tabs are placed.
+func (tw *TabWidget) TabPosition() TabPosition {
+	return tw.tabPosition
+}
+
+// SetTabPosition sets at which edge of the TabWidget the tabs are placed.
+//
+// Tabs at the left or right edge wrap into several columns instead of
+// scrolling, and are drawn without visual styles, which don't support them.
+func (tw *TabWidget) SetTabPosition(position TabPosition) error {
+	if position == tw.tabPosition {
+		return nil
+	}
+
+	var set uint32
+	switch position {
+	case TabPositionTop:
+
+	case TabPositionBottom:
+		set = win.TCS_BOTTOM
+
+	case TabPositionLeft:
+		set = win.TCS_VERTICAL | win.TCS_MULTILINE
+
+	case TabPositionRight:
+		set = win.TCS_VERTICAL | win.TCS_RIGHT | win.TCS_MULTILINE
+
+	default:
+		return newError("invalid TabPosition")
+	}
+
+	if err := setAndClearWindowLongBits(tw.hWndTab, win.GWL_STYLE, set, win.TCS_BOTTOM|win.TCS_VERTICAL|win.TCS_MULTILINE); err != nil {
+		return err
+	}
+
+	wasVertical := tw.tabsVertical()
+	tw.tabPosition = position
+
+	if vertical := tw.tabsVertical(); vertical != wasVertical {
+		if vertical {
+			empty := syscall.StringToUTF16Ptr("")
+			win.SetWindowTheme(tw.hWndTab, empty, empty)
+		} else {
+			win.SetWindowTheme(tw.hWndTab, nil, nil)
+		}
+	}
+
+	tw.hotCloseIndex = -1
+	tw.pressedCloseIndex = -1
+	tw.hotOverflowButton = false
+	tw.pressedOverflowButton = false
+
+	tw.updateNonClientSize()
+	tw.resizePages()
+	tw.RequestLayout()
+
+	win.InvalidateRect(tw.hWndTab, nil, true)
+
+	return nil
+}
+
+func (tw *TabWidget) tabsVertical() bool {
+	return tw.tabPosition == TabPositionLeft || tw.tabPosition == TabPositionRight
+}
+
+// tabsThemed returns if the tab control draws with visual styles.
+func (tw *TabWidget) tabsThemed() bool {
+	return win.IsAppThemed() && !tw.tabsVertical()
+}
+
+// alongTabs returns the coordinate of pt in the direction the tabs are lined
+// up.
+func (tw *TabWidget) alongTabs(pt win.POINT) int32 {
+	if tw.tabsVertical() {
+		return pt.Y
+	}
+
+	return pt.X
+}
+
+// tabStripRect returns the area of the tab control holding the tab items,
+// which occupy items, for a tab control of size.
+func (tw *TabWidget) tabStripRect(items win.RECT, size Size) win.RECT {
+	width, height := int32(size.Width), int32(size.Height)
+
+	switch tw.tabPosition {
+	case TabPositionBottom:
+		return win.RECT{Top: items.Top, Right: width, Bottom: height}
+
+	case TabPositionLeft:
+		return win.RECT{Right: items.Right, Bottom: height}
+
+	case TabPositionRight:
+		return win.RECT{Left: items.Left, Right: width, Bottom: height}
+	}
+
+	return win.RECT{Right: width, Bottom: items.Bottom}
+}
+
+func unionRECT(a, b win.RECT) win.RECT {
+	return win.RECT{
+		Left:   minInt32(a.Left, b.Left),
+		Top:    minInt32(a.Top, b.Top),
+		Right:  maxInt32(a.Right, b.Right),
+		Bottom: maxInt32(a.Bottom, b.Bottom),
+	}
+}
+
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// tabUpDown returns the up-down control the tab control shows for scrolling,
+// when the tabs don't fit, or 0.
+func (tw *TabWidget) tabUpDown() win.HWND {
+	buf := make([]uint16, 32)
+
+	for hwnd := win.GetWindow(tw.hWndTab, win.GW_CHILD); hwnd != 0; hwnd = win.GetWindow(hwnd, win.GW_HWNDNEXT) {
+		if n, _ := win.GetClassName(hwnd, &buf[0], len(buf)); n > 0 &&
+			syscall.UTF16ToString(buf[:n]) == "msctls_updown32" && win.IsWindowVisible(hwnd) {
+
+			return hwnd
+		}
+	}
+
+	return 0
+}
+
+// overflowButtonRect returns the bounds of the button for choosing from all
+// pages next to the scroll buttons, in client coordinates of the tab control.
+func (tw *TabWidget) overflowButtonRect() (win.RECT, bool) {
+	if tw.tabsVertical() {
+		return win.RECT{}, false
+	}
+
+	hWndUpDown := tw.tabUpDown()
+	if hWndUpDown == 0 {
+		return win.RECT{}, false
+	}
+
+	var rc win.RECT
+	win.GetClientRect(hWndUpDown, &rc)
+
+	// The up-down control is mirrored along with the tab control, so the
+	// translated origin is its leading edge in either layout.
+	var pt win.POINT
+	win.ClientToScreen(hWndUpDown, &pt)
+	win.ScreenToClient(tw.hWndTab, &pt)
+
+	height := rc.Bottom - rc.Top
+
+	return win.RECT{Left: pt.X - height, Top: pt.Y, Right: pt.X, Bottom: pt.Y + height}, true
+}
+
+func (tw *TabWidget) overflowButtonAt(pt win.POINT) bool {
+	rc, ok := tw.overflowButtonRect()
+
+	return ok && rectContainsPOINT(rc, pt)
+}
+
+// showOverflowMenu shows a menu of all pages below the overflow button and
+// makes the chosen page the current one.
+func (tw *TabWidget) showOverflowMenu() {
+	rc, ok := tw.overflowButtonRect()
+	if !ok {
+		return
+	}
+
+	hMenu := win.CreatePopupMenu()
+	if hMenu == 0 {
+		return
+	}
+	defer win.DestroyMenu(hMenu)
+
+	for i, page := range tw.pages.items {
+		title := syscall.StringToUTF16(page.title)
+
+		mii := win.MENUITEMINFO{
+			FMask:      win.MIIM_ID | win.MIIM_STATE | win.MIIM_STRING,
+			WID:        uint32(i + 1),
+			DwTypeData: &title[0],
+			Cch:        uint32(len(title) - 1),
+		}
+		mii.CbSize = uint32(unsafe.Sizeof(mii))
+		if i == tw.currentIndex {
+			mii.FState = win.MFS_CHECKED
+		}
+
+		if !win.InsertMenuItem(hMenu, uint32(i), true, &mii) {
+			return
+		}
+	}
+
+	pt := win.POINT{X: rc.Left, Y: rc.Bottom}
+	if tw.tabPosition == TabPositionBottom {
+		pt.Y = rc.Top
+	}
+	win.ClientToScreen(tw.hWndTab, &pt)
+
+	flags := uint32(win.TPM_NOANIMATION | win.TPM_RETURNCMD)
+	if tw.tabPosition == TabPositionBottom {
+		flags |= win.TPM_BOTTOMALIGN
+	}
+
+	id := int(win.TrackPopupMenuEx(hMenu, flags, pt.X, pt.Y, tw.hWnd, nil))
+	if id > 0 && id <= tw.pages.Len() {
+		tw.SetCurrentIndex(id - 1)
+	}
+}
+
+// scrollTabs scrolls the tabs that don't fit by delta tabs.
+func (tw *TabWidget) scrollTabs(delta int) bool {
+	hWndUpDown := tw.tabUpDown()
+	if hWndUpDown == 0 {
+		return false
+	}
+
+	var min, max int32
+	win.SendMessage(hWndUpDown, win.UDM_GETRANGE32, uintptr(unsafe.Pointer(&min)), uintptr(unsafe.Pointer(&max)))
+
+	pos := int32(win.SendMessage(hWndUpDown, win.UDM_GETPOS32, 0, 0)) + int32(delta)
+	pos = maxInt32(minInt32(pos, max), min)
+
+	win.SendMessage(hWndUpDown, win.UDM_SETPOS32, 0, uintptr(pos))
+	win.SendMessage(tw.hWndTab, win.WM_HSCROLL, uintptr(win.MAKELONG(win.SB_THUMBPOSITION, uint16(pos))), uintptr(hWndUpDown))
+
+	return true
+}
@@ -0,0 +1,556 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// Sizes of the buttons on the tabs, in 1/96".
+const (
+	tabButtonSize   = 14
+	tabButtonMargin = 4
+	tabPaddingX     = 6
+	tabPaddingY     = 3
+)
+
+var getCapture = syscall.NewLazyDLL("user32.dll").NewProc("GetCapture")
+
+// hasCapture returns if hwnd captured the mouse.
+func hasCapture(hwnd win.HWND) bool {
+	h, _, _ := getCapture.Call()
+
+	return win.HWND(h) == hwnd
+}
+
+// TabsClosable returns if the tabs of the TabWidget have close buttons.
+func (tw *TabWidget) TabsClosable() bool {
+	return tw.tabsClosable
+}
+
+// SetTabsClosable sets if the tabs of the TabWidget have close buttons.
+//
+// Clicking the close button of a tab, or clicking the tab with the middle
+// mouse button, closes its page like ClosePage.
+func (tw *TabWidget) SetTabsClosable(closable bool) {
+	if closable == tw.tabsClosable {
+		return
+	}
+
+	tw.tabsClosable = closable
+	tw.hotCloseIndex = -1
+	tw.pressedCloseIndex = -1
+
+	tw.updateTabPadding()
+}
+
+// TabsReorderable returns if the user can reorder the tabs of the TabWidget by
+// dragging them.
+func (tw *TabWidget) TabsReorderable() bool {
+	return tw.tabsReorderable
+}
+
+// SetTabsReorderable sets if the user can reorder the tabs of the TabWidget by
+// dragging them. PagesReordered is published after a drag moved tabs.
+func (tw *TabWidget) SetTabsReorderable(reorderable bool) {
+	tw.tabsReorderable = reorderable
+}
+
+// NewPageButtonVisible returns if a button for adding a page follows the tabs
+// of the TabWidget.
+func (tw *TabWidget) NewPageButtonVisible() bool {
+	return tw.newPageButtonVisible
+}
+
+// SetNewPageButtonVisible sets if a button for adding a page follows the tabs
+// of the TabWidget. Clicking it publishes NewPageRequested.
+func (tw *TabWidget) SetNewPageButtonVisible(visible bool) {
+	if visible == tw.newPageButtonVisible {
+		return
+	}
+
+	tw.newPageButtonVisible = visible
+	tw.hotNewPageButton = false
+	tw.pressedNewPageButton = false
+
+	win.InvalidateRect(tw.hWndTab, nil, true)
+}
+
+// PageClosing returns the event that is published before a page is closed,
+// either by the user or by ClosePage. Handlers can prevent closing the page by
+// setting canceled to true.
+func (tw *TabWidget) PageClosing() *TabPageClosingEvent {
+	return tw.pageClosingPublisher.Event()
+}
+
+// PagesReordered returns the event that is published after the user reordered
+// the tabs by dragging, or MovePage moved a page.
+func (tw *TabWidget) PagesReordered() *Event {
+	return tw.pagesReorderedPublisher.Event()
+}
+
+// NewPageRequested returns the event that is published when the user clicks
+// the new page button. Handlers are expected to add a page.
+func (tw *TabWidget) NewPageRequested() *Event {
+	return tw.newPageRequestedPublisher.Event()
+}
+
+// ClosePage removes page from the TabWidget and disposes it, unless a handler
+// of PageClosing cancels closing it.
+//
+// If page was the current page, its right neighbor, or else its left
+// neighbor, becomes the current page.
+func (tw *TabWidget) ClosePage(page *TabPage) error {
+	index := tw.pages.Index(page)
+	if index == -1 {
+		return newError("page is not in TabWidget")
+	}
+
+	var canceled bool
+	tw.pageClosingPublisher.Publish(page, &canceled)
+	if canceled {
+		return nil
+	}
+
+	var current *TabPage
+	if tw.currentIndex > -1 && tw.currentIndex != index {
+		current = tw.pages.At(tw.currentIndex)
+	}
+
+	if err := tw.pages.Remove(page); err != nil {
+		return err
+	}
+
+	page.Dispose()
+
+	if n := tw.pages.Len(); n > 0 {
+		if current != nil {
+			index = tw.pages.Index(current)
+		} else if index >= n {
+			index = n - 1
+		}
+
+		return tw.SetCurrentIndex(index)
+	}
+
+	return nil
+}
+
+// MovePage moves page to index, keeping the current page.
+func (tw *TabWidget) MovePage(page *TabPage, index int) error {
+	from := tw.pages.Index(page)
+	if from == -1 {
+		return newError("page is not in TabWidget")
+	}
+	if index < 0 || index >= tw.pages.Len() {
+		return newError("invalid index")
+	}
+
+	if from == index {
+		return nil
+	}
+
+	if err := tw.movePage(from, index); err != nil {
+		return err
+	}
+
+	tw.pagesReorderedPublisher.Publish()
+
+	return nil
+}
+
+func (tw *TabWidget) movePage(from, to int) error {
+	var current *TabPage
+	if tw.currentIndex > -1 {
+		current = tw.pages.At(tw.currentIndex)
+	}
+
+	page := tw.pages.items[from]
+	tw.pages.items = append(tw.pages.items[:from], tw.pages.items[from+1:]...)
+	tw.pages.insertIntoSlice(to, page)
+
+	win.SendMessage(tw.hWndTab, win.TCM_DELETEITEM, uintptr(from), 0)
+
+	item := tw.tcitemFromPage(page)
+	if idx := int(win.SendMessage(tw.hWndTab, win.TCM_INSERTITEM, uintptr(to), uintptr(unsafe.Pointer(item)))); idx == -1 {
+		return newError("SendMessage(TCM_INSERTITEM) failed")
+	}
+
+	if current != nil {
+		tw.currentIndex = tw.pages.Index(current)
+		win.SendMessage(tw.hWndTab, win.TCM_SETCURSEL, uintptr(tw.currentIndex), 0)
+	}
+
+	tw.hotCloseIndex = -1
+	tw.pressedCloseIndex = -1
+
+	tw.RequestLayout()
+	tw.Invalidate()
+
+	return nil
+}
+
+// updateTabPadding makes room for the close buttons on the tabs.
+func (tw *TabWidget) updateTabPadding() {
+	dpi := tw.DPI()
+
+	cx := IntFrom96DPI(tabPaddingX, dpi)
+	if tw.tabsClosable {
+		cx += IntFrom96DPI(tabButtonSize+tabButtonMargin, dpi)
+	}
+	cy := IntFrom96DPI(tabPaddingY, dpi)
+
+	win.SendMessage(tw.hWndTab, win.TCM_SETPADDING, 0, uintptr(win.MAKELONG(uint16(cx), uint16(cy))))
+
+	// The tab control only measures the tabs again when they change.
+	for _, page := range tw.pages.items {
+		tw.onPageChanged(page)
+	}
+
+	tw.RequestLayout()
+	tw.Invalidate()
+}
+
+// closeButtonRect returns the bounds of the close button of the tab at index,
+// in client coordinates of the tab control.
+func (tw *TabWidget) closeButtonRect(index int) (win.RECT, bool) {
+	var rc win.RECT
+	if 0 == win.SendMessage(tw.hWndTab, win.TCM_GETITEMRECT, uintptr(index), uintptr(unsafe.Pointer(&rc))) {
+		return rc, false
+	}
+
+	size := int32(tw.IntFrom96DPI(tabButtonSize))
+	margin := int32(tw.IntFrom96DPI(tabButtonMargin))
+
+	// The button follows the text, which reads upwards on tabs at the left
+	// and downwards on tabs at the right.
+	switch tw.tabPosition {
+	case TabPositionLeft:
+		left := (rc.Left + rc.Right - size) / 2
+		return win.RECT{Left: left, Top: rc.Top + margin, Right: left + size, Bottom: rc.Top + margin + size}, true
+
+	case TabPositionRight:
+		left := (rc.Left + rc.Right - size) / 2
+		return win.RECT{Left: left, Top: rc.Bottom - margin - size, Right: left + size, Bottom: rc.Bottom - margin}, true
+	}
+
+	top := (rc.Top + rc.Bottom - size) / 2
+
+	return win.RECT{Left: rc.Right - margin - size, Top: top, Right: rc.Right - margin, Bottom: top + size}, true
+}
+
+// closeButtonAt returns the index of the tab whose close button contains pt,
+// or -1.
+func (tw *TabWidget) closeButtonAt(pt win.POINT) int {
+	if !tw.tabsClosable {
+		return -1
+	}
+
+	page := tw.pageAt(pt)
+	if page == nil {
+		return -1
+	}
+
+	index := tw.pages.Index(page)
+	if rc, ok := tw.closeButtonRect(index); ok && rectContainsPOINT(rc, pt) {
+		return index
+	}
+
+	return -1
+}
+
+// newPageButtonRect returns the bounds of the new page button, in client
+// coordinates of the tab control.
+func (tw *TabWidget) newPageButtonRect() win.RECT {
+	margin := int32(tw.IntFrom96DPI(tabButtonMargin))
+
+	var rc win.RECT
+	if n := tw.pages.Len(); n == 0 || 0 == win.SendMessage(tw.hWndTab, win.TCM_GETITEMRECT, uintptr(n-1), uintptr(unsafe.Pointer(&rc))) {
+		size := int32(tw.IntFrom96DPI(tabButtonSize + 2*tabButtonMargin))
+		return win.RECT{Left: margin, Top: margin, Right: margin + size, Bottom: margin + size}
+	}
+
+	if tw.tabsVertical() {
+		return win.RECT{Left: rc.Left, Top: rc.Bottom + margin, Right: rc.Right, Bottom: rc.Bottom + margin + rc.Right - rc.Left}
+	}
+
+	return win.RECT{Left: rc.Right + margin, Top: rc.Top, Right: rc.Right + margin + rc.Bottom - rc.Top, Bottom: rc.Bottom}
+}
+
+func (tw *TabWidget) newPageButtonAt(pt win.POINT) bool {
+	return tw.newPageButtonVisible && rectContainsPOINT(tw.newPageButtonRect(), pt)
+}
+
+// inTabRow returns if pt is near enough to the rows of tabs to reorder tabs,
+// instead of dragging the page out.
+func (tw *TabWidget) inTabRow(pt win.POINT) bool {
+	var rc win.RECT
+	if 0 == win.SendMessage(tw.hWndTab, win.TCM_GETITEMRECT, 0, uintptr(unsafe.Pointer(&rc))) {
+		return false
+	}
+
+	for i, n := 1, tw.pages.Len(); i < n; i++ {
+		var r win.RECT
+		if 0 == win.SendMessage(tw.hWndTab, win.TCM_GETITEMRECT, uintptr(i), uintptr(unsafe.Pointer(&r))) {
+			break
+		}
+
+		rc = unionRECT(rc, r)
+	}
+
+	if tw.tabsVertical() {
+		cx := win.GetSystemMetrics(win.SM_CXDRAG)
+
+		return pt.X >= rc.Left-cx && pt.X < rc.Right+cx
+	}
+
+	cy := win.GetSystemMetrics(win.SM_CYDRAG)
+
+	return pt.Y >= rc.Top-cy && pt.Y < rc.Bottom+cy
+}
+
+func rectContainsPOINT(rc win.RECT, pt win.POINT) bool {
+	return pt.X >= rc.Left && pt.X < rc.Right && pt.Y >= rc.Top && pt.Y < rc.Bottom
+}
+
+// tabButtonsWndProc handles the mouse for the buttons on the tabs and for
+// reordering the tabs. It returns true if the message must not be processed
+// further.
+func (tw *TabWidget) tabButtonsWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) bool {
+	pt := win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}
+
+	switch msg {
+	case win.WM_LBUTTONDOWN:
+		if tw.overflowButtonAt(pt) {
+			tw.pressedOverflowButton = true
+		} else if tw.newPageButtonAt(pt) {
+			tw.pressedNewPageButton = true
+		} else if index := tw.closeButtonAt(pt); index != -1 {
+			tw.pressedCloseIndex = index
+		} else {
+			if tw.tabsReorderable && tw.pageAt(pt) != nil {
+				win.SetCapture(hwnd)
+				tw.pageDragLastPos = tw.alongTabs(pt)
+			}
+			break
+		}
+
+		win.SetCapture(hwnd)
+		win.InvalidateRect(hwnd, nil, true)
+		return true
+
+	case win.WM_LBUTTONUP:
+		if hasCapture(hwnd) {
+			win.ReleaseCapture()
+		}
+
+		if tw.pageDragReordered {
+			tw.pageDragReordered = false
+			tw.pagesReorderedPublisher.Publish()
+		}
+
+		if tw.pressedOverflowButton {
+			tw.pressedOverflowButton = false
+			win.InvalidateRect(hwnd, nil, true)
+
+			if tw.overflowButtonAt(pt) {
+				tw.Synchronize(tw.showOverflowMenu)
+			}
+			return true
+		}
+
+		if tw.pressedNewPageButton {
+			tw.pressedNewPageButton = false
+			win.InvalidateRect(hwnd, nil, true)
+
+			if tw.newPageButtonAt(pt) {
+				tw.Synchronize(tw.newPageRequestedPublisher.Publish)
+			}
+			return true
+		}
+
+		if index := tw.pressedCloseIndex; index != -1 {
+			tw.pressedCloseIndex = -1
+			win.InvalidateRect(hwnd, nil, true)
+
+			if tw.closeButtonAt(pt) == index {
+				tw.closePageLater(tw.pages.At(index))
+			}
+			return true
+		}
+
+	case win.WM_MBUTTONUP:
+		if tw.tabsClosable {
+			if page := tw.pageAt(pt); page != nil {
+				tw.closePageLater(page)
+				return true
+			}
+		}
+
+	case win.WM_MOUSEMOVE:
+		hotOverflowButton := tw.overflowButtonAt(pt)
+		hotCloseIndex := tw.closeButtonAt(pt)
+		hotNewPageButton := tw.newPageButtonAt(pt)
+		if hotOverflowButton != tw.hotOverflowButton || hotCloseIndex != tw.hotCloseIndex || hotNewPageButton != tw.hotNewPageButton {
+			tw.hotOverflowButton = hotOverflowButton
+			tw.hotCloseIndex = hotCloseIndex
+			tw.hotNewPageButton = hotNewPageButton
+			win.InvalidateRect(hwnd, nil, true)
+		}
+
+		if !tw.mouseTracked {
+			tme := win.TRACKMOUSEEVENT{DwFlags: win.TME_LEAVE, HwndTrack: hwnd}
+			tme.CbSize = uint32(unsafe.Sizeof(tme))
+			tw.mouseTracked = win.TrackMouseEvent(&tme)
+		}
+
+		if tw.pressedOverflowButton || tw.pressedNewPageButton || tw.pressedCloseIndex != -1 {
+			return true
+		}
+
+		if page := tw.pageDragCandidate; page != nil && tw.tabsReorderable && wParam&win.MK_LBUTTON != 0 && tw.inTabRow(pt) {
+			from := tw.pages.Index(page)
+
+			// Only moving towards another tab moves the dragged tab, so tabs of
+			// different width don't swap back and forth.
+			if target := tw.pageAt(pt); target != nil && target != page {
+				to := tw.pages.Index(target)
+
+				pos := tw.alongTabs(pt)
+				if to > from && pos > tw.pageDragLastPos || to < from && pos < tw.pageDragLastPos {
+					if err := tw.movePage(from, to); err == nil {
+						tw.pageDragReordered = true
+					}
+				}
+			}
+
+			// Dragging the page out only starts once the mouse left the row.
+			tw.pageDragLastPos = tw.alongTabs(pt)
+			tw.pageDragOrigin = pt
+		}
+
+	case win.WM_MOUSEWHEEL:
+		tw.wheelDelta += int(int16(win.HIWORD(uint32(wParam))))
+		notches := tw.wheelDelta / 120
+		tw.wheelDelta -= notches * 120
+
+		if notches != 0 && tw.scrollTabs(-notches) {
+			win.InvalidateRect(hwnd, nil, true)
+			return true
+		}
+
+	case win.WM_MOUSELEAVE:
+		tw.mouseTracked = false
+
+		if tw.hotOverflowButton || tw.hotCloseIndex != -1 || tw.hotNewPageButton {
+			tw.hotOverflowButton = false
+			tw.hotCloseIndex = -1
+			tw.hotNewPageButton = false
+			win.InvalidateRect(hwnd, nil, true)
+		}
+
+	case win.WM_CAPTURECHANGED:
+		if win.HWND(lParam) != hwnd && (tw.pressedOverflowButton || tw.pressedNewPageButton || tw.pressedCloseIndex != -1) {
+			tw.pressedOverflowButton = false
+			tw.pressedNewPageButton = false
+			tw.pressedCloseIndex = -1
+			win.InvalidateRect(hwnd, nil, true)
+		}
+	}
+
+	return false
+}
+
+// closePageLater closes page after the tab control processed the current
+// message, because closing the page removes its tab.
+func (tw *TabWidget) closePageLater(page *TabPage) {
+	tw.Synchronize(func() {
+		if tw.pages.Contains(page) {
+			tw.ClosePage(page)
+		}
+	})
+}
+
+// paintTabButtons draws the close buttons on the tabs, the new page button and
+// the overflow button.
+func (tw *TabWidget) paintTabButtons(canvas *Canvas) {
+	overflowRect, overflow := tw.overflowButtonRect()
+
+	if !tw.tabsClosable && !tw.newPageButtonVisible && !overflow {
+		return
+	}
+
+	pen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNTEXT)))
+	if err != nil {
+		return
+	}
+	defer pen.Dispose()
+
+	framePen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return
+	}
+	defer framePen.Dispose()
+
+	drawButton := func(rc win.RECT, hot, pressed bool, glyph func(r Rectangle, inset int)) {
+		r := rectangleFromRECT(rc)
+
+		if pressed {
+			canvas.FillRectanglePixels(sysColorBtnFaceBrush, r)
+		}
+		if hot || pressed {
+			canvas.DrawRectanglePixels(framePen, r)
+		}
+
+		inset := r.Height / 4
+		if pressed {
+			inset++
+		}
+
+		glyph(r, inset)
+	}
+
+	if tw.tabsClosable {
+		for i, n := 0, tw.pages.Len(); i < n; i++ {
+			rc, ok := tw.closeButtonRect(i)
+			if !ok {
+				break
+			}
+
+			drawButton(rc, i == tw.hotCloseIndex, i == tw.pressedCloseIndex, func(r Rectangle, inset int) {
+				canvas.DrawLinePixels(pen, Point{r.X + inset, r.Y + inset}, Point{r.X + r.Width - inset, r.Y + r.Height - inset})
+				canvas.DrawLinePixels(pen, Point{r.X + r.Width - inset - 1, r.Y + inset}, Point{r.X + inset - 1, r.Y + r.Height - inset})
+			})
+		}
+	}
+
+	if overflow {
+		// The button covers the tabs scrolled below it.
+		canvas.FillRectanglePixels(sysColorBtnFaceBrush, rectangleFromRECT(overflowRect))
+
+		drawButton(overflowRect, tw.hotOverflowButton, tw.pressedOverflowButton, func(r Rectangle, inset int) {
+			cx, cy := r.X+r.Width/2, r.Y+r.Height/2
+
+			for i := 0; i <= r.Width/2-inset; i++ {
+				canvas.DrawLinePixels(pen, Point{cx - i, cy + r.Height/8 - i}, Point{cx + i + 1, cy + r.Height/8 - i})
+			}
+		})
+	}
+
+	if tw.newPageButtonVisible {
+		drawButton(tw.newPageButtonRect(), tw.hotNewPageButton, tw.pressedNewPageButton, func(r Rectangle, inset int) {
+			cx, cy := r.X+r.Width/2, r.Y+r.Height/2
+
+			canvas.DrawLinePixels(pen, Point{r.X + inset, cy}, Point{r.X + r.Width - inset, cy})
+			canvas.DrawLinePixels(pen, Point{cx, r.Y + inset}, Point{cx, r.Y + r.Height - inset})
+		})
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/miu200521358/win"
+)
+
+// taskProgressRange is the number of steps progress is mapped to for the
+// taskbar and the ProgressBar of the progress dialog.
+const taskProgressRange = 1000
+
+// Task is work running in the background, started by FormBase.RunTask.
+//
+// Apart from Cancel, all methods of a Task must be called from the UI
+// goroutine.
+type Task struct {
+	form                     *FormBase
+	cancel                   context.CancelFunc
+	mutex                    sync.Mutex
+	pendingProgress          float64
+	pendingMessage           string
+	updatePending            bool
+	progress                 float64
+	message                  string
+	done                     bool
+	err                      error
+	disposingHandle          int
+	progressChangedPublisher EventPublisher
+	finishedPublisher        ErrorEventPublisher
+	dialog                   *Dialog
+	dialogLabel              *Label
+	dialogProgressBar        *ProgressBar
+	dialogCancelButton       *PushButton
+	statusBarItem            *StatusBarItem
+	statusBarItemText        string
+}
+
+// RunTask calls work in a new goroutine and returns a Task to track it.
+//
+// work should return early with ctx.Err() once ctx is done, which happens
+// when Cancel is called or the form is disposed. It may call progress from
+// its goroutine as often as it likes, with p from 0 to 1, or a negative p if
+// the progress can't be determined, and msg describing the current step.
+// Reports are coalesced and delivered on the UI goroutine, where they update
+// the taskbar button of the form and whatever the Task was asked to show, see
+// ShowProgressDialog and ShowInStatusBar.
+//
+// When work returns, Finished is published on the UI goroutine with its
+// result.
+func (fb *FormBase) RunTask(work func(ctx context.Context, progress func(p float64, msg string)) error) *Task {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t := &Task{form: fb, cancel: cancel, progress: -1}
+
+	t.disposingHandle = fb.Disposing().Attach(t.Cancel)
+
+	t.updateTaskbar()
+
+	go func() {
+		err := work(ctx, t.report)
+
+		cancel()
+
+		fb.Synchronize(func() {
+			t.finish(err)
+		})
+	}()
+
+	return t
+}
+
+// Cancel requests the work of the Task to stop, by canceling its context.
+//
+// The Task is only done once the work returned. Cancel may be called from
+// any goroutine.
+func (t *Task) Cancel() {
+	t.cancel()
+}
+
+// Done returns whether the work of the Task returned.
+func (t *Task) Done() bool {
+	return t.done
+}
+
+// Err returns the error the work of the Task returned, once it is done.
+func (t *Task) Err() error {
+	return t.err
+}
+
+// Progress returns the progress last reported by the work of the Task, from
+// 0 to 1, or a negative value if it is indeterminate.
+func (t *Task) Progress() float64 {
+	return t.progress
+}
+
+// Message returns the message last reported by the work of the Task.
+func (t *Task) Message() string {
+	return t.message
+}
+
+// ProgressChanged returns the event that is published on the UI goroutine,
+// when the work of the Task reported progress.
+func (t *Task) ProgressChanged() *Event {
+	return t.progressChangedPublisher.Event()
+}
+
+// Finished returns the event that is published on the UI goroutine with the
+// result of the work, when it returned.
+func (t *Task) Finished() *ErrorEvent {
+	return t.finishedPublisher.Event()
+}
+
+// ShowProgressDialog shows a dialog with the title, the message and
+// progress of the Task and a button to cancel it. The form is disabled while
+// the dialog is shown, which is until the Task is done.
+func (t *Task) ShowProgressDialog(title string) error {
+	if t.done || t.dialog != nil {
+		return nil
+	}
+
+	owner, _ := t.form.window.(Form)
+
+	dlg, err := NewDialogWithFixedSize(owner)
+	if err != nil {
+		return err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			dlg.Dispose()
+		}
+	}()
+
+	if err := dlg.SetTitle(title); err != nil {
+		return err
+	}
+	if err := dlg.SetLayout(NewVBoxLayout()); err != nil {
+		return err
+	}
+	if err := dlg.SetMinMaxSize(Size{Width: 360}, Size{}); err != nil {
+		return err
+	}
+
+	if t.dialogLabel, err = NewLabel(dlg); err != nil {
+		return err
+	}
+
+	if t.dialogProgressBar, err = NewProgressBar(dlg); err != nil {
+		return err
+	}
+	t.dialogProgressBar.SetRange(0, taskProgressRange)
+
+	buttons, err := NewComposite(dlg)
+	if err != nil {
+		return err
+	}
+	buttonsLayout := NewHBoxLayout()
+	buttonsLayout.SetMargins(Margins{})
+	if err := buttons.SetLayout(buttonsLayout); err != nil {
+		return err
+	}
+
+	if _, err := NewHSpacer(buttons); err != nil {
+		return err
+	}
+
+	if t.dialogCancelButton, err = NewPushButton(buttons); err != nil {
+		return err
+	}
+	if err := t.dialogCancelButton.SetText(tr("Cancel", "walk")); err != nil {
+		return err
+	}
+	t.dialogCancelButton.Clicked().Attach(t.cancelFromDialog)
+
+	if err := dlg.SetCancelButton(t.dialogCancelButton); err != nil {
+		return err
+	}
+
+	dlg.Closing().Attach(func(canceled *bool, reason CloseReason) {
+		// The dialog stays until the work returned, closing it only asks for
+		// that to happen soon.
+		if !t.done {
+			*canceled = true
+			t.cancelFromDialog()
+		}
+	})
+
+	t.dialog = dlg
+	t.updateDialog()
+
+	if owner != nil {
+		win.EnableWindow(owner.Handle(), false)
+	}
+
+	dlg.Show()
+
+	succeeded = true
+
+	return nil
+}
+
+// ShowInStatusBar shows the message and progress of the Task as the text of
+// item, until the Task is done. Then the text item had before is restored.
+func (t *Task) ShowInStatusBar(item *StatusBarItem) {
+	if t.done || item == nil || item == t.statusBarItem {
+		return
+	}
+
+	t.restoreStatusBarItem()
+
+	t.statusBarItem = item
+	t.statusBarItemText = item.Text()
+
+	t.updateStatusBarItem()
+}
+
+// report stores the progress reported by the work and makes sure it is
+// delivered on the UI goroutine. Reports arriving before the previous one was
+// delivered replace it.
+func (t *Task) report(progress float64, message string) {
+	if progress > 1 {
+		progress = 1
+	}
+
+	t.mutex.Lock()
+	t.pendingProgress = progress
+	t.pendingMessage = message
+	post := !t.updatePending
+	t.updatePending = true
+	t.mutex.Unlock()
+
+	if post {
+		t.form.Synchronize(t.deliverProgress)
+	}
+}
+
+func (t *Task) deliverProgress() {
+	t.mutex.Lock()
+	t.progress = t.pendingProgress
+	t.message = t.pendingMessage
+	t.updatePending = false
+	t.mutex.Unlock()
+
+	if t.done || t.form.IsDisposed() {
+		return
+	}
+
+	t.updateTaskbar()
+	t.updateDialog()
+	t.updateStatusBarItem()
+
+	t.progressChangedPublisher.Publish()
+}
+
+func (t *Task) finish(err error) {
+	t.done = true
+	t.err = err
+
+	if !t.form.IsDisposed() {
+		t.form.Disposing().Detach(t.disposingHandle)
+
+		if pi := t.form.ProgressIndicator(); pi != nil {
+			pi.SetState(PINoProgress)
+		}
+
+		t.closeDialog()
+		t.restoreStatusBarItem()
+	}
+
+	t.finishedPublisher.Publish(err)
+}
+
+func (t *Task) cancelFromDialog() {
+	t.Cancel()
+
+	if t.dialogCancelButton != nil {
+		t.dialogCancelButton.SetEnabled(false)
+	}
+}
+
+func (t *Task) closeDialog() {
+	if t.dialog == nil {
+		return
+	}
+
+	if owner := t.dialog.Owner(); owner != nil {
+		win.EnableWindow(owner.Handle(), true)
+	}
+
+	t.dialog.Close(DlgCmdNone)
+	t.dialog = nil
+	t.dialogLabel = nil
+	t.dialogProgressBar = nil
+	t.dialogCancelButton = nil
+}
+
+func (t *Task) updateTaskbar() {
+	pi := t.form.ProgressIndicator()
+	if pi == nil {
+		return
+	}
+
+	if t.progress < 0 {
+		pi.SetState(PIIndeterminate)
+		return
+	}
+
+	pi.SetState(PINormal)
+	pi.SetTotal(taskProgressRange)
+	pi.SetCompleted(uint32(t.progress * taskProgressRange))
+}
+
+func (t *Task) updateDialog() {
+	if t.dialog == nil {
+		return
+	}
+
+	t.dialogLabel.SetText(t.message)
+
+	if t.progress < 0 {
+		t.dialogProgressBar.SetMarqueeMode(true)
+		return
+	}
+
+	t.dialogProgressBar.SetMarqueeMode(false)
+	t.dialogProgressBar.SetValue(int(t.progress * taskProgressRange))
+}
+
+func (t *Task) updateStatusBarItem() {
+	if t.statusBarItem == nil {
+		return
+	}
+
+	text := t.message
+	if t.progress >= 0 {
+		percent := fmt.Sprintf("%d%%", int(t.progress*100))
+		if text == "" {
+			text = percent
+		} else {
+			text = fmt.Sprintf("%s (%s)", text, percent)
+		}
+	}
+
+	t.statusBarItem.SetText(text)
+}
+
+func (t *Task) restoreStatusBarItem() {
+	if t.statusBarItem == nil {
+		return
+	}
+
+	t.statusBarItem.SetText(t.statusBarItemText)
+	t.statusBarItem = nil
+	t.statusBarItemText = ""
+}
@@ -10,6 +10,7 @@ package walk
 import (
 	"sync"
 	"syscall"
+	"unicode/utf16"
 	"unsafe"
 
 	"github.com/miu200521358/win"
@@ -24,6 +25,7 @@ type TextEdit struct {
 	margins                  Size // in native pixels
 	lastHeight               int
 	origWordbreakProcPtr     uintptr
+	finder                   *textFinder
 }
 
 func NewTextEdit(parent Container) (*TextEdit, error) {
@@ -32,6 +34,7 @@ func NewTextEdit(parent Container) (*TextEdit, error) {
 
 func NewTextEditWithStyle(parent Container, style uint32) (*TextEdit, error) {
 	te := new(TextEdit)
+	te.finder = newTextFinder(te)
 
 	if err := InitWidget(
 		te,
@@ -274,6 +277,144 @@ func (te *TextEdit) SetTextColor(c Color) {
 	te.Invalidate()
 }
 
+// Find returns the ranges of all matches of text in the text of the
+// TextEdit.
+func (te *TextEdit) Find(text string, opts FindOptions) []TextRange {
+	return te.finder.find(text, opts)
+}
+
+// Search returns the text and options FindNext, FindPrevious and the
+// replace methods search for.
+func (te *TextEdit) Search() (text string, opts FindOptions) {
+	return te.finder.what, te.finder.opts
+}
+
+// SetSearch sets the text and options FindNext, FindPrevious and the replace
+// methods search for.
+func (te *TextEdit) SetSearch(text string, opts FindOptions) {
+	te.finder.setSearch(text, opts)
+}
+
+// FindNext selects the next match of the search after the selection, wrapping
+// around at the end of the text, and returns whether there is one.
+//
+// F3 calls FindNext while the TextEdit has the focus.
+func (te *TextEdit) FindNext() bool {
+	if !te.finder.findNext(false) {
+		return false
+	}
+
+	te.ScrollToCaret()
+
+	return true
+}
+
+// FindPrevious selects the previous match of the search before the selection,
+// wrapping around at the start of the text, and returns whether there is one.
+//
+// Shift+F3 calls FindPrevious while the TextEdit has the focus.
+func (te *TextEdit) FindPrevious() bool {
+	if !te.finder.findNext(true) {
+		return false
+	}
+
+	te.ScrollToCaret()
+
+	return true
+}
+
+// ReplaceNext replaces the selection with replacement if it is a match of the
+// search, then selects the next match and returns whether there is one.
+func (te *TextEdit) ReplaceNext(replacement string) bool {
+	if !te.finder.replaceNext(replacement) {
+		return false
+	}
+
+	te.ScrollToCaret()
+
+	return true
+}
+
+// ReplaceAll replaces all matches of the search with replacement and returns
+// their number.
+func (te *TextEdit) ReplaceAll(replacement string) int {
+	return te.finder.replaceAll(replacement)
+}
+
+// HighlightAllMatches returns whether all matches of the search are
+// highlighted.
+func (te *TextEdit) HighlightAllMatches() bool {
+	return te.finder.highlightAll
+}
+
+// SetHighlightAllMatches sets whether all matches of the search are
+// highlighted, by drawing a frame around them.
+func (te *TextEdit) SetHighlightAllMatches(highlight bool) {
+	te.finder.setHighlightAll(highlight)
+}
+
+// MatchHighlightColor returns the color of the frames around highlighted
+// matches.
+func (te *TextEdit) MatchHighlightColor() Color {
+	return te.finder.highlightColor
+}
+
+// SetMatchHighlightColor sets the color of the frames around highlighted
+// matches.
+func (te *TextEdit) SetMatchHighlightColor(c Color) {
+	te.finder.setHighlightColor(c)
+}
+
+// FindReplaceEnabled returns whether Ctrl+F and Ctrl+H show the find and
+// replace dialogs.
+func (te *TextEdit) FindReplaceEnabled() bool {
+	return te.finder.dialogEnabled
+}
+
+// SetFindReplaceEnabled sets whether Ctrl+F and Ctrl+H show the find and
+// replace dialogs. Ctrl+H is ignored while the TextEdit is read-only.
+func (te *TextEdit) SetFindReplaceEnabled(enabled bool) {
+	te.finder.dialogEnabled = enabled
+}
+
+// ShowFindDialog shows the modeless stock find dialog of Windows for the
+// TextEdit.
+func (te *TextEdit) ShowFindDialog() error {
+	return te.finder.showDialog(false)
+}
+
+// ShowReplaceDialog shows the modeless stock replace dialog of Windows for
+// the TextEdit.
+func (te *TextEdit) ShowReplaceDialog() error {
+	return te.finder.showDialog(true)
+}
+func (te *TextEdit) findText() []uint16 {
+	return utf16.Encode([]rune(te.Text()))
+}
+
+func (te *TextEdit) findSelection() TextRange {
+	start, end := te.TextSelection()
+	return TextRange{start, end}
+}
+
+func (te *TextEdit) setFindSelection(r TextRange) {
+	te.SetTextSelection(r.Start, r.End)
+}
+
+func (te *TextEdit) replaceFindRange(r TextRange, text string) {
+	te.SetTextSelection(r.Start, r.End)
+	te.ReplaceSelectedText(text, true)
+}
+
+func (te *TextEdit) charPosition(index int) (Point, bool) {
+	res := uint32(te.SendMessage(win.EM_POSFROMCHAR, uintptr(index), 0))
+	if res == 0xFFFFFFFF {
+		return Point{}, false
+	}
+
+	return Point{int(int16(win.LOWORD(res))), int(int16(win.HIWORD(res)))}, true
+}
+
 // ContextMenuLocation returns carret position in screen coordinates in native pixels.
 func (te *TextEdit) ContextMenuLocation() Point {
 	idx := int(te.SendMessage(win.EM_GETCARETINDEX, 0, 0))
@@ -292,7 +433,16 @@ func (*TextEdit) NeedsWmSize() bool {
 }
 
 func (te *TextEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if te.finder.wndProc(msg, wParam, lParam) {
+		return 0
+	}
+
 	switch msg {
+	case win.WM_PAINT:
+		result := te.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+		te.finder.paintHighlights()
+		return result
+
 	case win.WM_COMMAND:
 		switch win.HIWORD(uint32(wParam)) {
 		case win.EN_CHANGE:
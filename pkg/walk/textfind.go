@@ -0,0 +1,437 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/miu200521358/win"
+)
+
+// FindOptions control how text is searched for.
+type FindOptions uint32
+
+const (
+	// FindMatchCase makes the search case sensitive.
+	FindMatchCase FindOptions = 1 << iota
+
+	// FindWholeWord only matches text that is not part of a longer word.
+	FindWholeWord
+)
+
+// findInText returns the ranges of the matches of what in text, in UTF-16
+// code units.
+func findInText(text []uint16, what string, opts FindOptions) []TextRange {
+	pattern := utf16.Encode([]rune(what))
+	if len(pattern) == 0 || len(pattern) > len(text) {
+		return nil
+	}
+
+	fold := func(u uint16) uint16 { return u }
+	if opts&FindMatchCase == 0 {
+		fold = foldUTF16
+	}
+
+	for i, u := range pattern {
+		pattern[i] = fold(u)
+	}
+
+	var matches []TextRange
+
+	for i := 0; i <= len(text)-len(pattern); i++ {
+		j := 0
+		for j < len(pattern) && fold(text[i+j]) == pattern[j] {
+			j++
+		}
+		if j < len(pattern) {
+			continue
+		}
+
+		end := i + len(pattern)
+
+		if opts&FindWholeWord != 0 {
+			if i > 0 && isWordUTF16(text[i-1]) && isWordUTF16(text[i]) ||
+				end < len(text) && isWordUTF16(text[end-1]) && isWordUTF16(text[end]) {
+				continue
+			}
+		}
+
+		matches = append(matches, TextRange{i, end})
+		i = end - 1
+	}
+
+	return matches
+}
+
+func foldUTF16(u uint16) uint16 {
+	if u < 0xD800 || u > 0xDFFF {
+		if r := unicode.ToLower(rune(u)); r <= 0xFFFF {
+			return uint16(r)
+		}
+	}
+
+	return u
+}
+
+func isWordUTF16(u uint16) bool {
+	r := rune(u)
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// findTarget is implemented by the text edits a textFinder searches.
+type findTarget interface {
+	Window
+
+	// findText returns the text, in the UTF-16 code units its character
+	// offsets refer to.
+	findText() []uint16
+
+	findSelection() TextRange
+	setFindSelection(r TextRange)
+	replaceFindRange(r TextRange, text string)
+
+	ReadOnly() bool
+	ScrollToCaret()
+	TextChanged() *Event
+
+	// charPosition returns the top left of the character at index in client
+	// coordinates, in native pixels.
+	charPosition(index int) (Point, bool)
+}
+
+// textFinder implements find and replace for a findTarget.
+type textFinder struct {
+	target         findTarget
+	what           string
+	opts           FindOptions
+	highlightAll   bool
+	highlights     []TextRange
+	highlightColor Color
+	dialogEnabled  bool
+	dialog         *findReplaceDialog
+	swallowChar    bool
+}
+
+func newTextFinder(target findTarget) *textFinder {
+	f := &textFinder{
+		target:         target,
+		highlightColor: RGB(0xFF, 0x8C, 0x00),
+	}
+
+	target.TextChanged().Attach(f.updateHighlights)
+	target.Disposing().Attach(func() {
+		if f.dialog != nil {
+			f.dialog.Dispose()
+		}
+	})
+
+	return f
+}
+
+func (f *textFinder) find(what string, opts FindOptions) []TextRange {
+	return findInText(f.target.findText(), what, opts)
+}
+
+func (f *textFinder) setSearch(what string, opts FindOptions) {
+	if what == f.what && opts == f.opts {
+		return
+	}
+
+	f.what = what
+	f.opts = opts
+
+	f.updateHighlights()
+}
+
+func (f *textFinder) setHighlightAll(highlightAll bool) {
+	if highlightAll == f.highlightAll {
+		return
+	}
+
+	f.highlightAll = highlightAll
+
+	f.updateHighlights()
+}
+
+func (f *textFinder) setHighlightColor(c Color) {
+	f.highlightColor = c
+
+	if len(f.highlights) > 0 {
+		f.target.Invalidate()
+	}
+}
+
+// updateHighlights searches the matches to highlight again, after the text or
+// search changed.
+func (f *textFinder) updateHighlights() {
+	hadHighlights := len(f.highlights) > 0
+
+	if f.highlightAll && f.what != "" {
+		f.highlights = f.find(f.what, f.opts)
+	} else {
+		f.highlights = nil
+	}
+
+	if hadHighlights || len(f.highlights) > 0 {
+		f.target.Invalidate()
+	}
+}
+
+// findNext selects the next match of the search after the selection, or the
+// previous one before it if backward is true, wrapping around at the end of
+// the text.
+func (f *textFinder) findNext(backward bool) bool {
+	if f.what == "" {
+		return false
+	}
+
+	matches := f.find(f.what, f.opts)
+	if len(matches) == 0 {
+		return false
+	}
+
+	sel := f.target.findSelection()
+
+	var match TextRange
+	if backward {
+		i := sort.Search(len(matches), func(i int) bool {
+			return matches[i].Start >= sel.Start
+		})
+		if i == 0 {
+			i = len(matches)
+		}
+		match = matches[i-1]
+	} else {
+		from := sel.End
+		if sel.Len() == 0 {
+			from = sel.Start
+		}
+		i := sort.Search(len(matches), func(i int) bool {
+			return matches[i].Start >= from
+		})
+		if i == len(matches) {
+			i = 0
+		}
+		match = matches[i]
+	}
+
+	f.target.setFindSelection(match)
+
+	return true
+}
+
+// replaceNext replaces the selection with replacement if it is a match of the
+// search and then selects the next match.
+func (f *textFinder) replaceNext(replacement string) bool {
+	if f.what == "" {
+		return false
+	}
+
+	sel := f.target.findSelection()
+	for _, m := range f.find(f.what, f.opts) {
+		if m == sel {
+			f.target.replaceFindRange(sel, replacement)
+			break
+		}
+	}
+
+	return f.findNext(false)
+}
+
+// replaceAll replaces all matches of the search with replacement and returns
+// their number.
+func (f *textFinder) replaceAll(replacement string) int {
+	if f.what == "" {
+		return 0
+	}
+
+	matches := f.find(f.what, f.opts)
+
+	SuspendUpdates(f.target, func() {
+		for i := len(matches) - 1; i >= 0; i-- {
+			f.target.replaceFindRange(matches[i], replacement)
+		}
+	})
+
+	return len(matches)
+}
+
+func (f *textFinder) showDialog(replace bool) error {
+	if f.dialog != nil && f.dialog.replace == replace {
+		f.dialog.Activate()
+		return nil
+	}
+
+	if f.dialog != nil {
+		f.dialog.Dispose()
+	}
+
+	what := f.what
+	if sel := f.target.findSelection(); sel.Len() > 0 {
+		text := f.target.findText()
+		if sel.End <= len(text) {
+			if s := string(utf16.Decode(text[sel.Start:sel.End])); !strings.ContainsAny(s, "\r\n") {
+				what = s
+			}
+		}
+	}
+
+	dlg, err := newFindReplaceDialog(f.target, replace, what, f.opts)
+	if err != nil {
+		return err
+	}
+
+	f.dialog = dlg
+
+	return nil
+}
+
+// handleDialogNotification carries out the command of a notification of the
+// find and replace dialog.
+func (f *textFinder) handleDialogNotification() {
+	dlg := f.dialog
+	if dlg == nil {
+		return
+	}
+
+	req := dlg.request()
+
+	if req.command == findReplaceCommandClose {
+		dlg.Dispose()
+		f.dialog = nil
+		return
+	}
+
+	f.setSearch(req.what, req.opts)
+
+	var found bool
+	switch req.command {
+	case findReplaceCommandFindNext:
+		found = f.findNext(req.backward)
+
+	case findReplaceCommandReplace:
+		found = f.replaceNext(req.replacement)
+
+	case findReplaceCommandReplaceAll:
+		found = f.replaceAll(req.replacement) > 0
+	}
+
+	if found {
+		f.target.ScrollToCaret()
+	} else {
+		win.MessageBeep(win.MB_OK)
+	}
+}
+
+// wndProc handles the messages of the target related to find and replace. It
+// returns true if msg was handled and must not be processed further.
+func (f *textFinder) wndProc(msg uint32, wParam, lParam uintptr) bool {
+	switch msg {
+	case findReplaceMsg:
+		f.handleDialogNotification()
+		return true
+
+	case win.WM_KEYDOWN:
+		switch key := Key(wParam); {
+		case key == KeyF3 && !ControlDown() && !AltDown():
+			if f.findNext(ShiftDown()) {
+				f.target.ScrollToCaret()
+			} else {
+				win.MessageBeep(win.MB_OK)
+			}
+			return true
+
+		case f.dialogEnabled && ControlDown() && !ShiftDown() && !AltDown() && (key == KeyF || key == KeyH):
+			if key == KeyH && f.target.ReadOnly() {
+				return false
+			}
+
+			// Ctrl+F and Ctrl+H also produce control characters.
+			f.swallowChar = true
+			f.showDialog(key == KeyH)
+			return true
+		}
+
+	case win.WM_CHAR:
+		if f.swallowChar {
+			f.swallowChar = false
+			return true
+		}
+	}
+
+	return false
+}
+
+// paintHighlights draws frames around the visible highlighted matches, after
+// the target painted itself.
+func (f *textFinder) paintHighlights() {
+	if len(f.highlights) == 0 {
+		return
+	}
+
+	wb := f.target.AsWindowBase()
+
+	canvas, err := newCanvasFromWindow(f.target)
+	if err != nil {
+		return
+	}
+	defer canvas.Dispose()
+
+	pen, err := NewCosmeticPen(PenSolid, f.highlightColor)
+	if err != nil {
+		return
+	}
+	defer pen.Dispose()
+
+	clientHeight := wb.ClientBoundsPixels().Height
+	lineHeight := wb.calculateTextSizeImpl("gM").Height
+	charWidth := wb.calculateTextSizeImpl("W").Width
+
+	firstLine := int(wb.SendMessage(win.EM_GETFIRSTVISIBLELINE, 0, 0))
+	firstChar := int(wb.SendMessage(win.EM_LINEINDEX, uintptr(firstLine), 0))
+
+	i := sort.Search(len(f.highlights), func(i int) bool {
+		return f.highlights[i].End > firstChar
+	})
+
+	for ; i < len(f.highlights); i++ {
+		r := f.highlights[i]
+
+		start, ok := f.target.charPosition(r.Start)
+		if !ok {
+			continue
+		}
+		if start.Y >= clientHeight {
+			break
+		}
+
+		// Matches may span multiple lines, so draw a frame per line.
+		x := start.X
+		y := start.Y
+		for j := r.Start + 1; j <= r.End; j++ {
+			pos, ok := f.target.charPosition(j)
+			if j < r.End && (!ok || pos.Y == y) {
+				continue
+			}
+
+			var right int
+			if ok && pos.Y == y {
+				right = pos.X
+			} else if prev, ok := f.target.charPosition(j - 1); ok {
+				right = prev.X + charWidth
+			}
+
+			canvas.DrawRectanglePixels(pen, Rectangle{x, y, maxi(right-x, 1), lineHeight})
+
+			x, y = pos.X, pos.Y
+		}
+	}
+}
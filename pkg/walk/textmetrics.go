@@ -0,0 +1,124 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+// TextMetrics describes the layout of a measured text. All values are in
+// 1/96" units.
+type TextMetrics struct {
+	// Size is the extent of the text. Its Width is the width of the longest
+	// line.
+	Size Size
+
+	// LineCount is the number of lines the text occupies, after wrapping.
+	// It is 0 for an empty text.
+	LineCount int
+
+	// LineHeight is the distance between the tops of consecutive lines.
+	LineHeight int
+
+	// Ascent is the distance from the top of a line to its baseline.
+	Ascent int
+
+	// Descent is the distance from the baseline of a line to its bottom.
+	Descent int
+}
+
+// MeasureText measures text, drawn with font at dpi. The text is broken into
+// lines at line breaks only. If dpi is 0, the DPI of the screen is used.
+//
+// Measuring at the DPI the text will be drawn at accounts for fonts that
+// don't scale linearly, so results may differ slightly across DPIs.
+func MeasureText(text string, font *Font, dpi int) (TextMetrics, error) {
+	return measureText(text, font, 0, dpi)
+}
+
+// MeasureTextWrapped measures text, drawn with font at dpi and wrapped at
+// word boundaries to fit width, in 1/96" units. If dpi is 0, the DPI of the
+// screen is used.
+//
+// The reported width may exceed width, if a single word doesn't fit.
+func MeasureTextWrapped(text string, font *Font, width, dpi int) (TextMetrics, error) {
+	if width <= 0 {
+		return TextMetrics{}, newError("width must be positive")
+	}
+
+	return measureText(text, font, width, dpi)
+}
+
+func measureText(text string, font *Font, width, dpi int) (TextMetrics, error) {
+	if font == nil {
+		return TextMetrics{}, newError("font must not be nil")
+	}
+
+	if dpi <= 0 {
+		dpi = screenDPI()
+	}
+
+	hdc := win.GetDC(0)
+	if hdc == 0 {
+		return TextMetrics{}, newError("GetDC failed")
+	}
+	defer win.ReleaseDC(0, hdc)
+
+	canvas, err := newCanvasFromHDC(hdc)
+	if err != nil {
+		return TextMetrics{}, err
+	}
+	defer canvas.Dispose()
+
+	var tm win.TEXTMETRIC
+
+	hFontOld := win.SelectObject(hdc, win.HGDIOBJ(font.handleForDPI(dpi)))
+	ok := win.GetTextMetrics(hdc, &tm)
+	win.SelectObject(hdc, hFontOld)
+
+	if !ok {
+		return TextMetrics{}, newError("GetTextMetrics failed")
+	}
+
+	metrics := TextMetrics{
+		LineHeight: intTo96DPICeil(int(tm.TmHeight), dpi),
+		Ascent:     intTo96DPICeil(int(tm.TmAscent), dpi),
+		Descent:    intTo96DPICeil(int(tm.TmDescent), dpi),
+	}
+
+	if text == "" {
+		return metrics, nil
+	}
+
+	bounds := Rectangle{Width: 0x3FFFFFFF, Height: 0x3FFFFFFF}
+	var format DrawTextFormat
+	if width > 0 {
+		bounds.Width = IntFrom96DPI(width, dpi)
+		format = TextWordbreak
+	}
+
+	measured, err := canvas.measureTextForDPI(text, font, bounds, format, dpi)
+	if err != nil {
+		return TextMetrics{}, err
+	}
+
+	if tm.TmHeight > 0 {
+		metrics.LineCount = (measured.Height + int(tm.TmHeight) - 1) / int(tm.TmHeight)
+	}
+
+	// Rounding up ensures the text fits, when the size is converted back to
+	// native pixels for layout.
+	metrics.Size = Size{intTo96DPICeil(measured.Width, dpi), intTo96DPICeil(measured.Height, dpi)}
+
+	return metrics, nil
+}
+
+// intTo96DPICeil converts from native pixels to 1/96" units, rounding up.
+func intTo96DPICeil(value, dpi int) int {
+	return (value*96 + dpi - 1) / dpi
+}
@@ -0,0 +1,426 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+// ThemeRole identifies what a color of a Theme is used for.
+type ThemeRole int
+
+const (
+	// ThemeRoleWindow is the background of forms, containers and buttons.
+	ThemeRoleWindow ThemeRole = iota
+
+	// ThemeRoleText is the text on ThemeRoleWindow.
+	ThemeRoleText
+
+	// ThemeRoleControl is the background of edits, lists, trees and tables.
+	ThemeRoleControl
+
+	// ThemeRoleControlText is the text on ThemeRoleControl.
+	ThemeRoleControlText
+
+	// ThemeRoleHighlight is the background of selected items.
+	ThemeRoleHighlight
+
+	// ThemeRoleHighlightText is the text of selected items.
+	ThemeRoleHighlightText
+
+	// ThemeRoleDisabledText is the text of disabled widgets.
+	ThemeRoleDisabledText
+
+	// ThemeRoleBorder is the color of frames and separators.
+	ThemeRoleBorder
+
+	// ThemeRoleAlternateRow is the background of every other row of a
+	// TableView with alternating row colors.
+	ThemeRoleAlternateRow
+
+	themeRoleCount
+)
+
+// Theme is a palette with a color for each ThemeRole, that the built-in
+// widgets are drawn with while it is the theme of the application, see
+// SetAppTheme.
+type Theme struct {
+	dark    bool
+	colors  [themeRoleCount]Color
+	brushes [themeRoleCount]*SolidColorBrush
+}
+
+// NewTheme returns a new Theme, starting with the colors of DarkTheme or
+// LightTheme. A dark Theme also gets dark title bars, scroll bars and
+// menus.
+func NewTheme(dark bool) *Theme {
+	t := &Theme{dark: dark}
+
+	if dark {
+		t.colors = darkTheme.colors
+	} else {
+		t.colors = LightTheme().colors
+	}
+
+	return t
+}
+
+// LightTheme returns a Theme with the current system colors.
+func LightTheme() *Theme {
+	t := &Theme{}
+
+	for index, role := range sysColorThemeRoles {
+		t.colors[role] = Color(win.GetSysColor(index))
+	}
+	t.colors[ThemeRoleAlternateRow] = t.colors[ThemeRoleWindow]
+
+	return t
+}
+
+// DarkTheme returns the Theme used for ThemeDark, and for ThemeSystem while
+// Windows is set to dark mode for apps.
+func DarkTheme() *Theme {
+	return darkTheme
+}
+
+var darkTheme = &Theme{
+	dark: true,
+	colors: [themeRoleCount]Color{
+		ThemeRoleWindow:        RGB(32, 32, 32),
+		ThemeRoleText:          RGB(255, 255, 255),
+		ThemeRoleControl:       RGB(43, 43, 43),
+		ThemeRoleControlText:   RGB(255, 255, 255),
+		ThemeRoleHighlight:     RGB(0, 120, 215),
+		ThemeRoleHighlightText: RGB(255, 255, 255),
+		ThemeRoleDisabledText:  RGB(128, 128, 128),
+		ThemeRoleBorder:        RGB(85, 85, 85),
+		ThemeRoleAlternateRow:  RGB(38, 38, 38),
+	},
+}
+
+// sysColorThemeRoles maps the system colors walk paints with to the roles
+// that replace them while an application theme is active.
+var sysColorThemeRoles = map[int]ThemeRole{
+	win.COLOR_BTNFACE:       ThemeRoleWindow,
+	win.COLOR_BTNTEXT:       ThemeRoleText,
+	win.COLOR_WINDOW:        ThemeRoleControl,
+	win.COLOR_WINDOWTEXT:    ThemeRoleControlText,
+	win.COLOR_HIGHLIGHT:     ThemeRoleHighlight,
+	win.COLOR_HIGHLIGHTTEXT: ThemeRoleHighlightText,
+	win.COLOR_GRAYTEXT:      ThemeRoleDisabledText,
+	win.COLOR_BTNSHADOW:     ThemeRoleBorder,
+}
+
+// Dark returns whether the Theme has light text on dark backgrounds.
+func (t *Theme) Dark() bool {
+	return t.dark
+}
+
+// Color returns the color of the Theme for role.
+func (t *Theme) Color(role ThemeRole) Color {
+	if role < 0 || role >= themeRoleCount {
+		return 0
+	}
+
+	return t.colors[role]
+}
+
+// SetColor sets the color of the Theme for role. If the Theme is in use,
+// call SetAppTheme with its ID again to repaint the forms.
+func (t *Theme) SetColor(role ThemeRole, color Color) {
+	if role < 0 || role >= themeRoleCount {
+		return
+	}
+
+	t.colors[role] = color
+
+	if brush := t.brushes[role]; brush != nil {
+		brush.Dispose()
+		t.brushes[role] = nil
+	}
+}
+
+// brush returns a brush of the color of the Theme for role.
+func (t *Theme) brush(role ThemeRole) *SolidColorBrush {
+	if t.brushes[role] == nil {
+		t.brushes[role], _ = NewSolidColorBrush(t.colors[role])
+	}
+
+	return t.brushes[role]
+}
+
+var (
+	// appTheme is the active Theme, or nil while the system colors are used.
+	appTheme              *Theme
+	themePalettes         = map[string]*Theme{ThemeDark: darkTheme}
+	themeChangedPublisher EventPublisher
+)
+
+// SetAppTheme switches the application to the theme with id, one of
+// ThemeSystem, ThemeLight, ThemeDark or one added with RegisterTheme, and
+// repaints all forms. Other than SetAppearance, it doesn't store the choice
+// in the settings.
+//
+// With ThemeSystem, the application follows the dark mode setting of
+// Windows. Calling it with the ID of the current theme repaints the forms,
+// e.g. after changing colors of the Theme.
+func SetAppTheme(id string) error {
+	var known bool
+	for _, theme := range appearanceThemes {
+		if theme.ID == id {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return newError("unknown theme: " + id)
+	}
+
+	if id == appearance.Theme {
+		forEachForm(func(form Form) {
+			form.(ApplySysColorser).ApplySysColors()
+		})
+
+		return nil
+	}
+
+	a := appearance
+	a.Theme = id
+
+	return applyAppearance(a)
+}
+
+// AppTheme returns the Theme the application is drawn with.
+func AppTheme() *Theme {
+	if appTheme == nil {
+		return LightTheme()
+	}
+
+	return appTheme
+}
+
+// ThemeChanged returns the event that is published when the Theme of the
+// application changed, by SetAppTheme, SetAppearance or, with
+// ThemeSystem, by switching the dark mode setting of Windows.
+func ThemeChanged() *Event {
+	return themeChangedPublisher.Event()
+}
+
+// SystemDarkMode returns whether Windows is set to dark mode for apps.
+func SystemDarkMode() bool {
+	value, err := RegistryKeyUint32(
+		CurrentUserKey(),
+		`Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`,
+		"AppsUseLightTheme")
+
+	return err == nil && value == 0
+}
+
+// updateAppTheme activates the Theme for the theme ID of the current
+// Appearance and repaints all forms of the current thread, if it changed.
+func updateAppTheme() {
+	var theme *Theme
+	switch id := appearance.Theme; id {
+	case ThemeSystem:
+		if SystemDarkMode() {
+			theme = darkTheme
+		}
+
+	case ThemeLight:
+
+	default:
+		theme = themePalettes[id]
+	}
+
+	if theme == appTheme {
+		return
+	}
+
+	appTheme = theme
+
+	setPreferredAppMode(appearance.Theme, theme != nil && theme.dark)
+
+	forEachForm(func(form Form) {
+		form.(ApplySysColorser).ApplySysColors()
+	})
+
+	themeChangedPublisher.Publish()
+}
+
+// themeDark returns whether a dark Theme is active.
+func themeDark() bool {
+	return appTheme != nil && appTheme.dark
+}
+
+// themeSysColor returns the color of the active Theme that replaces the
+// system color with index, or the system color itself.
+func themeSysColor(index int) Color {
+	if appTheme != nil {
+		if role, ok := sysColorThemeRoles[index]; ok {
+			return appTheme.colors[role]
+		}
+	}
+
+	return Color(win.GetSysColor(index))
+}
+
+// themeSysColorBrush returns a brush of the color of themeSysColor.
+func themeSysColorBrush(index int) win.HBRUSH {
+	if appTheme != nil {
+		if role, ok := sysColorThemeRoles[index]; ok {
+			if brush := appTheme.brush(role); brush != nil {
+				return brush.hBrush
+			}
+		}
+	}
+
+	return win.GetSysColorBrush(index)
+}
+
+// applyTheme switches the window between dark and light title bars or
+// visual styles, to match the active Theme.
+func (wb *WindowBase) applyTheme() {
+	dark := themeDark()
+	if dark == wb.darkThemeApplied {
+		return
+	}
+
+	wb.darkThemeApplied = dark
+
+	allowDarkModeForWindow(wb.hWnd, dark)
+
+	if _, ok := wb.window.(Form); ok {
+		setDarkTitleBar(wb.hWnd, dark)
+		return
+	}
+
+	applyWindowThemeAppName(wb.hWnd)
+}
+
+// applyWindowThemeAppName sets the visual styles of the window with hwnd, as
+// chosen with setWindowTheme, or their dark variant while a dark Theme is
+// active.
+func applyWindowThemeAppName(hwnd win.HWND) {
+	appName := windowThemeAppNames[hwnd]
+
+	if themeDark() {
+		appName = "DarkMode_Explorer"
+
+		buf := make([]uint16, 32)
+		if n, _ := win.GetClassName(hwnd, &buf[0], len(buf)); n > 0 {
+			switch syscall.UTF16ToString(buf[:n]) {
+			case "Edit", "ComboBox", "RICHEDIT50W":
+				appName = "DarkMode_CFD"
+
+			case "SysHeader32":
+				appName = "DarkMode_ItemsView"
+			}
+		}
+	}
+
+	var appNamePtr *uint16
+	if appName != "" {
+		appNamePtr = syscall.StringToUTF16Ptr(appName)
+	}
+
+	win.SetWindowTheme(hwnd, appNamePtr, nil)
+}
+
+// isImmersiveColorSetChange returns whether the WM_SETTINGCHANGE message
+// with lParam reports a change of the dark mode setting.
+func isImmersiveColorSetChange(lParam uintptr) bool {
+	return lParam != 0 && win.UTF16PtrToString((*uint16)(unsafe.Pointer(lParam))) == "ImmersiveColorSet"
+}
+
+const (
+	dwmwaUseImmersiveDarkModeBefore20H1 = 19
+	dwmwaUseImmersiveDarkMode           = 20
+)
+
+var (
+	dwmSetWindowAttribute = syscall.NewLazyDLL("dwmapi.dll").NewProc("DwmSetWindowAttribute")
+
+	libuxtheme     = syscall.NewLazyDLL("uxtheme.dll")
+	getProcAddress = syscall.NewLazyDLL("kernel32.dll").NewProc("GetProcAddress")
+)
+
+// setDarkTitleBar switches the title bar of the top-level window with hwnd
+// between dark and light.
+func setDarkTitleBar(hwnd win.HWND, dark bool) {
+	if dwmSetWindowAttribute.Find() != nil {
+		return
+	}
+
+	var value int32
+	if dark {
+		value = 1
+	}
+
+	for _, attr := range []uintptr{dwmwaUseImmersiveDarkMode, dwmwaUseImmersiveDarkModeBefore20H1} {
+		hr, _, _ := dwmSetWindowAttribute.Call(uintptr(hwnd), attr, uintptr(unsafe.Pointer(&value)), unsafe.Sizeof(value))
+		if !win.FAILED(win.HRESULT(hr)) {
+			return
+		}
+	}
+}
+
+// uxthemeOrdinal returns the address of the undocumented function of
+// uxtheme.dll with ordinal, or 0.
+func uxthemeOrdinal(ordinal uintptr) uintptr {
+	if libuxtheme.Load() != nil || getProcAddress.Find() != nil {
+		return 0
+	}
+
+	addr, _, _ := getProcAddress.Call(libuxtheme.Handle(), ordinal)
+
+	return addr
+}
+
+// Preferred app modes of SetPreferredAppMode.
+const (
+	appModeAllowDark  = 1
+	appModeForceDark  = 2
+	appModeForceLight = 3
+)
+
+// setPreferredAppMode makes the context menus and other parts of the
+// process that Windows draws itself follow the theme.
+func setPreferredAppMode(id string, dark bool) {
+	addr := uxthemeOrdinal(135)
+	if addr == 0 {
+		return
+	}
+
+	mode := appModeForceLight
+	switch {
+	case id == ThemeSystem:
+		mode = appModeAllowDark
+
+	case dark:
+		mode = appModeForceDark
+	}
+
+	syscall.SyscallN(addr, uintptr(mode))
+
+	if flushMenuThemes := uxthemeOrdinal(136); flushMenuThemes != 0 {
+		syscall.SyscallN(flushMenuThemes)
+	}
+}
+
+// allowDarkModeForWindow lets the window with hwnd use dark visual styles.
+func allowDarkModeForWindow(hwnd win.HWND, allow bool) {
+	addr := uxthemeOrdinal(133)
+	if addr == 0 {
+		return
+	}
+
+	syscall.SyscallN(addr, uintptr(hwnd), uintptr(win.BoolToBOOL(allow)))
+}
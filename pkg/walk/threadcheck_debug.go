@@ -0,0 +1,26 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows && walk_debug
+// +build windows,walk_debug
+
+package walk
+
+import (
+	"fmt"
+
+	"github.com/miu200521358/win"
+)
+
+// checkUIThread panics, if called from another thread than the one that
+// created the window of wb, since walk isn't safe for concurrent use.
+func checkUIThread(wb *WindowBase) {
+	if wb.group == nil {
+		return
+	}
+
+	if tid := win.GetCurrentThreadId(); tid != wb.group.ThreadID() {
+		panic(fmt.Sprintf("walk: %T used from thread %d, but it belongs to the UI thread %d; use Synchronize to call it from other goroutines", wb.window, tid, wb.group.ThreadID()))
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows && !walk_debug
+// +build windows,!walk_debug
+
+package walk
+
+// checkUIThread does nothing, unless built with the walk_debug tag.
+func checkUIThread(wb *WindowBase) {
+}
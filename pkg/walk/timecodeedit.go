@@ -0,0 +1,765 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const timecodeEditWindowClass = `\o/ Walk_TimecodeEdit_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(timecodeEditWindowClass)
+	})
+}
+
+const (
+	timecodeLen       = len("HH:MM:SS:FF")
+	timecodeMaxHours  = 99
+	timecodeSegLen    = 3 // digits of a segment plus separator
+	timecodeSegFrames = 3 // index of the frames segment
+)
+
+// FramesToDuration returns the time.Duration of frames at fps frames per
+// second.
+func FramesToDuration(frames, fps int) time.Duration {
+	if fps <= 0 {
+		return 0
+	}
+
+	return time.Duration(frames) * time.Second / time.Duration(fps)
+}
+
+// DurationToFrames returns the number of the frame at fps frames per second,
+// that is nearest to d.
+func DurationToFrames(d time.Duration, fps int) int {
+	if fps <= 0 {
+		return 0
+	}
+
+	return int((d*time.Duration(fps) + time.Second/2) / time.Second)
+}
+
+// FormatTimecode formats frames at fps frames per second as HH:MM:SS:FF.
+func FormatTimecode(frames, fps int) string {
+	if fps <= 0 {
+		fps = 1
+	}
+
+	sign := ""
+	if frames < 0 {
+		sign = "-"
+		frames = -frames
+	}
+
+	ff := frames % fps
+	totalSeconds := frames / fps
+
+	return fmt.Sprintf("%s%02d:%02d:%02d:%02d", sign, totalSeconds/3600, totalSeconds/60%60, totalSeconds%60, ff)
+}
+
+// ParseTimecode parses a timecode of the form HH:MM:SS:FF at fps frames per
+// second and returns the number of frames it represents.
+//
+// Leading segments may be omitted, so "SS:FF" and "MM:SS:FF" are accepted as
+// well.
+func ParseTimecode(s string, fps int) (int, error) {
+	if fps <= 0 {
+		return 0, newError("fps must > 0")
+	}
+
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) == 0 || len(parts) > 4 {
+		return 0, newError("invalid timecode: " + s)
+	}
+
+	limits := []int{0, 60, 60, fps}[4-len(parts):]
+	units := []int{3600 * fps, 60 * fps, fps, 1}[4-len(parts):]
+
+	var frames int
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil || v < 0 {
+			return 0, newError("invalid timecode: " + s)
+		}
+		if limits[i] > 0 && v >= limits[i] {
+			return 0, newError("timecode segment out of range: " + s)
+		}
+
+		frames += v * units[i]
+	}
+
+	return frames, nil
+}
+
+// TimecodeEdit is a widget that is suited to edit a frame position as
+// HH:MM:SS:FF timecode.
+//
+// Each segment can be typed over or incremented and decremented separately
+// using the KeyUp and KeyDown keys, the mouse wheel or the spin buttons.
+type TimecodeEdit struct {
+	WidgetBase
+	edit                      *timecodeLineEdit
+	hWndUpDown                win.HWND
+	fpsChangedPublisher       EventPublisher
+	maxFramesChangedPublisher EventPublisher
+}
+
+// NewTimecodeEdit returns a new TimecodeEdit widget as child of parent.
+//
+// The initial frame rate is 30 frames per second.
+func NewTimecodeEdit(parent Container) (*TimecodeEdit, error) {
+	te := new(TimecodeEdit)
+
+	if err := InitWidget(
+		te,
+		parent,
+		timecodeEditWindowClass,
+		win.WS_VISIBLE,
+		win.WS_EX_CONTROLPARENT); err != nil {
+		return nil, err
+	}
+
+	var succeeded bool
+	defer func() {
+		if !succeeded {
+			te.Dispose()
+		}
+	}()
+
+	var err error
+	if te.edit, err = newTimecodeLineEdit(te); err != nil {
+		return nil, err
+	}
+
+	te.edit.applyFont(te.Font())
+
+	if err = te.edit.setFrames(0, true); err != nil {
+		return nil, err
+	}
+
+	te.GraphicsEffects().Add(InteractionEffect)
+	te.GraphicsEffects().Add(FocusEffect)
+
+	te.MustRegisterProperty("Duration", NewProperty(
+		func() interface{} {
+			return te.Duration()
+		},
+		func(v interface{}) error {
+			d, _ := v.(time.Duration)
+			return te.SetDuration(d)
+		},
+		te.edit.framesChangedPublisher.Event()))
+
+	te.MustRegisterProperty("FPS", NewProperty(
+		func() interface{} {
+			return te.FPS()
+		},
+		func(v interface{}) error {
+			return te.SetFPS(assertIntOr(v, 30))
+		},
+		te.fpsChangedPublisher.Event()))
+
+	te.MustRegisterProperty("Frames", NewProperty(
+		func() interface{} {
+			return te.Frames()
+		},
+		func(v interface{}) error {
+			return te.SetFrames(assertIntOr(v, 0))
+		},
+		te.edit.framesChangedPublisher.Event()))
+
+	te.MustRegisterProperty("MaxFrames", NewProperty(
+		func() interface{} {
+			return te.MaxFrames()
+		},
+		func(v interface{}) error {
+			return te.SetMaxFrames(assertIntOr(v, 0))
+		},
+		te.maxFramesChangedPublisher.Event()))
+
+	te.MustRegisterProperty("ReadOnly", NewProperty(
+		func() interface{} {
+			return te.ReadOnly()
+		},
+		func(v interface{}) error {
+			return te.SetReadOnly(v.(bool))
+		},
+		te.edit.readOnlyChangedPublisher.Event()))
+
+	succeeded = true
+
+	return te, nil
+}
+
+func (te *TimecodeEdit) applyEnabled(enabled bool) {
+	te.WidgetBase.applyEnabled(enabled)
+
+	if te.edit == nil {
+		return
+	}
+
+	te.edit.applyEnabled(enabled)
+}
+
+func (te *TimecodeEdit) applyFont(font *Font) {
+	te.WidgetBase.applyFont(font)
+
+	if te.edit == nil {
+		return
+	}
+
+	te.edit.applyFont(font)
+}
+
+// FPS returns the number of frames per second of the TimecodeEdit.
+func (te *TimecodeEdit) FPS() int {
+	return te.edit.fps
+}
+
+// SetFPS sets the number of frames per second of the TimecodeEdit.
+//
+// The current position in time is retained as close as possible.
+func (te *TimecodeEdit) SetFPS(fps int) error {
+	if fps < 1 || fps > 100 {
+		return newError("fps must >= 1 && <= 100")
+	}
+
+	if fps == te.edit.fps {
+		return nil
+	}
+
+	d := te.Duration()
+	maxD := FramesToDuration(te.edit.maxFrames, te.edit.fps)
+
+	te.edit.fps = fps
+	te.edit.maxFrames = mini(DurationToFrames(maxD, fps), maxTimecodeFrames(fps))
+
+	if err := te.edit.setFrames(DurationToFrames(d, fps), true); err != nil {
+		return err
+	}
+
+	te.fpsChangedPublisher.Publish()
+
+	return nil
+}
+
+// FPSChanged returns the event that is published when the frame rate changed.
+func (te *TimecodeEdit) FPSChanged() *Event {
+	return te.fpsChangedPublisher.Event()
+}
+
+// MaxFrames returns the maximum number of frames the TimecodeEdit will
+// accept.
+//
+// A value of 0 means the limit is the largest HH:MM:SS:FF timecode.
+func (te *TimecodeEdit) MaxFrames() int {
+	return te.edit.maxFrames
+}
+
+// SetMaxFrames sets the maximum number of frames the TimecodeEdit will
+// accept.
+//
+// If the current value is greater than maxFrames, it will be adjusted.
+// maxFrames must not exceed the largest HH:MM:SS:FF timecode at FPS, as the
+// hours have two digits.
+func (te *TimecodeEdit) SetMaxFrames(maxFrames int) error {
+	if maxFrames < 0 {
+		return newError("maxFrames must >= 0")
+	}
+	if maxFrames > maxTimecodeFrames(te.edit.fps) {
+		return newError("maxFrames must not exceed 99:59:59:FF")
+	}
+
+	if maxFrames == te.edit.maxFrames {
+		return nil
+	}
+
+	te.edit.maxFrames = maxFrames
+
+	if f := te.edit.clamp(te.edit.frames); f != te.edit.frames {
+		if err := te.edit.setFrames(f, true); err != nil {
+			return err
+		}
+	}
+
+	te.maxFramesChangedPublisher.Publish()
+
+	return nil
+}
+
+// MaxFramesChanged returns the event that is published when the maximum
+// number of frames changed.
+func (te *TimecodeEdit) MaxFramesChanged() *Event {
+	return te.maxFramesChangedPublisher.Event()
+}
+
+// Frames returns the frame position of the TimecodeEdit.
+func (te *TimecodeEdit) Frames() int {
+	return te.edit.frames
+}
+
+// SetFrames sets the frame position of the TimecodeEdit.
+func (te *TimecodeEdit) SetFrames(frames int) error {
+	if frames < 0 || frames > te.edit.maxFramesEffective() {
+		return newError("frames out of range")
+	}
+
+	return te.edit.setFrames(frames, true)
+}
+
+// FramesChanged returns an Event that can be used to track changes to Frames.
+func (te *TimecodeEdit) FramesChanged() *Event {
+	return te.edit.framesChangedPublisher.Event()
+}
+
+// Duration returns the frame position of the TimecodeEdit as time.Duration.
+func (te *TimecodeEdit) Duration() time.Duration {
+	return FramesToDuration(te.edit.frames, te.edit.fps)
+}
+
+// SetDuration sets the frame position of the TimecodeEdit to the frame nearest
+// to d.
+func (te *TimecodeEdit) SetDuration(d time.Duration) error {
+	return te.SetFrames(DurationToFrames(d, te.edit.fps))
+}
+
+// Timecode returns the frame position of the TimecodeEdit formatted as
+// HH:MM:SS:FF.
+func (te *TimecodeEdit) Timecode() string {
+	return FormatTimecode(te.edit.frames, te.edit.fps)
+}
+
+// SetTimecode parses timecode and sets the frame position of the TimecodeEdit
+// accordingly.
+func (te *TimecodeEdit) SetTimecode(timecode string) error {
+	frames, err := ParseTimecode(timecode, te.edit.fps)
+	if err != nil {
+		return err
+	}
+
+	return te.SetFrames(frames)
+}
+
+// SetFocus sets the keyboard input focus to the TimecodeEdit.
+func (te *TimecodeEdit) SetFocus() error {
+	if win.SetFocus(te.edit.hWnd) == 0 {
+		return lastError("SetFocus")
+	}
+
+	return nil
+}
+
+// ReadOnly returns whether the TimecodeEdit is in read-only mode.
+func (te *TimecodeEdit) ReadOnly() bool {
+	return te.edit.ReadOnly()
+}
+
+// SetReadOnly sets whether the TimecodeEdit is in read-only mode.
+func (te *TimecodeEdit) SetReadOnly(readOnly bool) error {
+	if readOnly != te.ReadOnly() {
+		te.invalidateBorderInParent()
+	}
+
+	return te.edit.SetReadOnly(readOnly)
+}
+
+// WheelValueMode returns whether the mouse wheel changes Frames.
+func (te *TimecodeEdit) WheelValueMode() WheelValueMode {
+	return te.edit.wheelValueMode
+}
+
+// SetWheelValueMode sets whether the mouse wheel changes Frames.
+// WheelValueDefault uses App().WheelValueMode().
+func (te *TimecodeEdit) SetWheelValueMode(mode WheelValueMode) {
+	te.edit.wheelValueMode = mode
+	te.edit.wheelDelta = 0
+}
+
+// SpinButtonsVisible returns whether the TimecodeEdit appears with spin
+// buttons.
+func (te *TimecodeEdit) SpinButtonsVisible() bool {
+	return te.hWndUpDown != 0
+}
+
+// SetSpinButtonsVisible sets whether the TimecodeEdit appears with spin
+// buttons.
+//
+// The spin buttons increment or decrement the segment at the caret.
+func (te *TimecodeEdit) SetSpinButtonsVisible(visible bool) error {
+	return setUpDownVisible(&te.hWndUpDown, te.hWnd, te.edit.hWnd, visible)
+}
+
+// Background returns the background Brush of the TimecodeEdit.
+//
+// By default this is nil.
+func (te *TimecodeEdit) Background() Brush {
+	return te.edit.Background()
+}
+
+// SetBackground sets the background Brush of the TimecodeEdit.
+func (te *TimecodeEdit) SetBackground(bg Brush) {
+	te.edit.SetBackground(bg)
+}
+
+// TextColor returns the Color used to draw the text of the TimecodeEdit.
+func (te *TimecodeEdit) TextColor() Color {
+	return te.edit.TextColor()
+}
+
+// SetTextColor sets the Color used to draw the text of the TimecodeEdit.
+func (te *TimecodeEdit) SetTextColor(c Color) {
+	te.edit.SetTextColor(c)
+}
+
+func (te *TimecodeEdit) SetToolTipText(s string) error {
+	return te.edit.SetToolTipText(s)
+}
+
+func (*TimecodeEdit) NeedsWmSize() bool {
+	return true
+}
+
+// WndProc is the window procedure of the TimecodeEdit.
+//
+// When implementing your own WndProc to add or modify behavior, call the
+// WndProc of the embedded TimecodeEdit for messages you don't handle yourself.
+func (te *TimecodeEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_NOTIFY:
+		switch ((*win.NMHDR)(unsafe.Pointer(lParam))).Code {
+		case win.UDN_DELTAPOS:
+			nmud := (*win.NMUPDOWN)(unsafe.Pointer(lParam))
+			te.edit.incrementSegment(-int(nmud.IDelta))
+		}
+
+	case win.WM_CTLCOLOREDIT, win.WM_CTLCOLORSTATIC:
+		if hBrush := te.handleWMCTLCOLOR(wParam, lParam); hBrush != 0 {
+			return hBrush
+		}
+
+	case win.WM_WINDOWPOSCHANGED:
+		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
+
+		if wp.Flags&win.SWP_NOSIZE != 0 {
+			break
+		}
+
+		if te.edit == nil {
+			break
+		}
+
+		cb := te.ClientBoundsPixels()
+		if err := te.edit.SetBoundsPixels(cb); err != nil {
+			break
+		}
+
+		if te.hWndUpDown != 0 {
+			win.SendMessage(te.hWndUpDown, win.UDM_SETBUDDY, uintptr(te.edit.hWnd), 0)
+		}
+	}
+
+	return te.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (te *TimecodeEdit) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return &numberEditLayoutItem{
+		idealSize: te.dialogBaseUnitsToPixels(Size{56, 12}),
+		minSize:   te.dialogBaseUnitsToPixels(Size{48, 12}),
+	}
+}
+
+type timecodeLineEdit struct {
+	*LineEdit
+	frames                 int
+	fps                    int
+	maxFrames              int
+	wheelValueMode         WheelValueMode
+	wheelDelta             int
+	framesChangedPublisher EventPublisher
+}
+
+func newTimecodeLineEdit(parent Widget) (*timecodeLineEdit, error) {
+	tle := &timecodeLineEdit{
+		fps: 30,
+	}
+
+	var err error
+	if tle.LineEdit, err = newLineEdit(parent, win.WS_EX_CLIENTEDGE); err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			tle.Dispose()
+		}
+	}()
+
+	if err := tle.LineEdit.setAndClearStyleBits(win.ES_RIGHT, win.ES_LEFT|win.ES_CENTER); err != nil {
+		return nil, err
+	}
+
+	if err := InitWrapperWindow(tle); err != nil {
+		return nil, err
+	}
+
+	succeeded = true
+
+	return tle, nil
+}
+
+func (tle *timecodeLineEdit) TextColor() Color {
+	return tle.LineEdit.TextColor()
+}
+
+func (tle *timecodeLineEdit) SetTextColor(c Color) {
+	tle.LineEdit.SetTextColor(c)
+}
+
+func (tle *timecodeLineEdit) maxFramesEffective() int {
+	if tle.maxFrames > 0 {
+		return tle.maxFrames
+	}
+
+	return maxTimecodeFrames(tle.fps)
+}
+
+// maxTimecodeFrames returns the frames of the largest HH:MM:SS:FF timecode at
+// fps.
+func maxTimecodeFrames(fps int) int {
+	return (timecodeMaxHours+1)*3600*fps - 1
+}
+
+func (tle *timecodeLineEdit) clamp(frames int) int {
+	if frames < 0 {
+		return 0
+	}
+	if max := tle.maxFramesEffective(); frames > max {
+		return max
+	}
+
+	return frames
+}
+
+func (tle *timecodeLineEdit) setFrames(frames int, setText bool) error {
+	if setText {
+		start, end := tle.TextSelection()
+
+		if err := tle.SetText(FormatTimecode(frames, tle.fps)); err != nil {
+			return err
+		}
+
+		tle.SetTextSelection(start, end)
+	}
+
+	if frames == tle.frames {
+		return nil
+	}
+
+	tle.frames = frames
+
+	tle.framesChangedPublisher.Publish()
+
+	return nil
+}
+
+// segmentUnit returns the number of frames a single step of segment seg
+// represents.
+func (tle *timecodeLineEdit) segmentUnit(seg int) int {
+	switch seg {
+	case 0:
+		return 3600 * tle.fps
+
+	case 1:
+		return 60 * tle.fps
+
+	case 2:
+		return tle.fps
+	}
+
+	return 1
+}
+
+func (tle *timecodeLineEdit) caretSegment() int {
+	start, _ := tle.TextSelection()
+
+	seg := start / timecodeSegLen
+	if seg > timecodeSegFrames {
+		seg = timecodeSegFrames
+	}
+
+	return seg
+}
+
+func (tle *timecodeLineEdit) selectSegment(seg int) {
+	start := seg * timecodeSegLen
+	tle.SetTextSelection(start, start+timecodeSegLen-1)
+}
+
+func (tle *timecodeLineEdit) incrementSegment(steps int) {
+	if tle.ReadOnly() || steps == 0 {
+		return
+	}
+
+	seg := tle.caretSegment()
+
+	tle.setFrames(tle.clamp(tle.frames+steps*tle.segmentUnit(seg)), true)
+	tle.selectSegment(seg)
+}
+
+// framesFromText parses the digits in text, clamping each segment to its
+// valid range.
+func (tle *timecodeLineEdit) framesFromText(text []byte) int {
+	limits := [4]int{timecodeMaxHours, 59, 59, tle.fps - 1}
+
+	var frames int
+	for seg := 0; seg < 4; seg++ {
+		i := seg * timecodeSegLen
+		v := int(text[i]-'0')*10 + int(text[i+1]-'0')
+		if v > limits[seg] {
+			v = limits[seg]
+		}
+
+		frames += v * tle.segmentUnit(seg)
+	}
+
+	return tle.clamp(frames)
+}
+
+// overwriteDigit replaces the digit at pos with digit and moves the caret
+// behind it, skipping separators.
+func (tle *timecodeLineEdit) overwriteDigit(pos int, digit byte) {
+	if pos%timecodeSegLen == timecodeSegLen-1 {
+		pos++
+	}
+	if pos >= timecodeLen {
+		return
+	}
+
+	text := []byte(FormatTimecode(tle.frames, tle.fps))
+	text[pos] = digit
+
+	tle.setFrames(tle.framesFromText(text), true)
+
+	pos++
+	if pos%timecodeSegLen == timecodeSegLen-1 && pos < timecodeLen {
+		pos++
+	}
+	tle.SetTextSelection(pos, pos)
+}
+
+func (tle *timecodeLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_CHAR:
+		if tle.ReadOnly() {
+			break
+		}
+
+		if AltDown() || ControlDown() {
+			break
+		}
+
+		start, _ := tle.TextSelection()
+
+		switch char := byte(wParam); {
+		case char >= '0' && char <= '9':
+			tle.overwriteDigit(start, char)
+
+		case char == ':' || char == '.' || char == ';' || char == ' ':
+			if seg := start / timecodeSegLen; seg < timecodeSegFrames {
+				tle.selectSegment(seg + 1)
+			}
+
+		case Key(wParam) == KeyBack:
+			if start%timecodeSegLen == 0 && start > 0 {
+				start--
+			}
+			if start > 0 {
+				tle.overwriteDigit(start-1, '0')
+				tle.SetTextSelection(start-1, start-1)
+			}
+		}
+
+		return 0
+
+	case win.WM_KEYDOWN:
+		switch Key(wParam) {
+		case KeyDelete:
+			if tle.ReadOnly() {
+				break
+			}
+
+			start, _ := tle.TextSelection()
+			tle.overwriteDigit(start, '0')
+			return 0
+
+		case KeyDown:
+			tle.incrementSegment(-1)
+			return 0
+
+		case KeyUp:
+			tle.incrementSegment(1)
+			return 0
+
+		case KeyPrior:
+			tle.incrementSegment(10)
+			return 0
+
+		case KeyNext:
+			tle.incrementSegment(-10)
+			return 0
+		}
+
+	case win.WM_KILLFOCUS:
+		invalidateWrapperBorderInParent(tle.hWnd)
+
+	case win.WM_SETFOCUS:
+		invalidateWrapperBorderInParent(tle.hWnd)
+
+	case win.WM_LBUTTONDBLCLK:
+		tle.selectSegment(tle.caretSegment())
+		return 0
+
+	case win.WM_MOUSEWHEEL:
+		if tle.ReadOnly() || !wheelChangesValue(tle.wheelValueMode, tle.Focused()) {
+			break
+		}
+
+		if notches := wheelNotches(&tle.wheelDelta, wParam); notches != 0 {
+			tle.incrementSegment(notches)
+		}
+		return 0
+
+	case win.WM_PASTE:
+		if tle.ReadOnly() {
+			break
+		}
+
+		ret := tle.LineEdit.WndProc(hwnd, msg, wParam, lParam)
+		if frames, err := ParseTimecode(tle.Text(), tle.fps); err == nil {
+			tle.setFrames(tle.clamp(frames), true)
+		} else {
+			tle.setFrames(tle.frames, true)
+		}
+		tle.SetTextSelection(0, timecodeLen)
+		return ret
+
+	case win.WM_CUT, win.WM_CLEAR:
+		return 0
+	}
+
+	return tle.LineEdit.WndProc(hwnd, msg, wParam, lParam)
+}
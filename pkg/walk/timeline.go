@@ -0,0 +1,1050 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/miu200521358/win"
+)
+
+const timelineWindowClass = `\o/ Walk_Timeline_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClassWithStyle(timelineWindowClass, win.CS_DBLCLKS)
+	})
+}
+
+// TimelineKeyframe identifies a keyframe of a TimelineTrack.
+type TimelineKeyframe struct {
+	Track *TimelineTrack
+	Frame int
+}
+
+// TimelineTrack is a row of keyframes in a Timeline.
+type TimelineTrack struct {
+	timeline  *Timeline
+	name      string
+	keyframes []int // sorted, unique
+}
+
+// Timeline returns the Timeline the track belongs to.
+func (tt *TimelineTrack) Timeline() *Timeline {
+	return tt.timeline
+}
+
+// Name returns the name of the track, as displayed in the track header.
+func (tt *TimelineTrack) Name() string {
+	return tt.name
+}
+
+// SetName sets the name of the track, as displayed in the track header.
+func (tt *TimelineTrack) SetName(name string) {
+	tt.name = name
+
+	tt.timeline.Invalidate()
+}
+
+// Keyframes returns the frames of the keyframes of the track in ascending
+// order.
+func (tt *TimelineTrack) Keyframes() []int {
+	return append([]int(nil), tt.keyframes...)
+}
+
+// SetKeyframes replaces the keyframes of the track.
+func (tt *TimelineTrack) SetKeyframes(frames []int) {
+	tt.keyframes = tt.keyframes[:0]
+	for _, frame := range frames {
+		tt.insert(frame)
+	}
+
+	tt.timeline.pruneSelection()
+	tt.timeline.contentChanged()
+}
+
+// HasKeyframe returns whether the track has a keyframe at frame.
+func (tt *TimelineTrack) HasKeyframe(frame int) bool {
+	i := sort.SearchInts(tt.keyframes, frame)
+
+	return i < len(tt.keyframes) && tt.keyframes[i] == frame
+}
+
+// AddKeyframe adds a keyframe at frame.
+func (tt *TimelineTrack) AddKeyframe(frame int) {
+	if tt.insert(frame) {
+		tt.timeline.contentChanged()
+	}
+}
+
+// RemoveKeyframe removes the keyframe at frame.
+func (tt *TimelineTrack) RemoveKeyframe(frame int) {
+	if tt.remove(frame) {
+		tt.timeline.pruneSelection()
+		tt.timeline.contentChanged()
+	}
+}
+
+func (tt *TimelineTrack) insert(frame int) bool {
+	i := sort.SearchInts(tt.keyframes, frame)
+	if i < len(tt.keyframes) && tt.keyframes[i] == frame {
+		return false
+	}
+
+	tt.keyframes = append(tt.keyframes, 0)
+	copy(tt.keyframes[i+1:], tt.keyframes[i:])
+	tt.keyframes[i] = frame
+
+	return true
+}
+
+func (tt *TimelineTrack) remove(frame int) bool {
+	i := sort.SearchInts(tt.keyframes, frame)
+	if i == len(tt.keyframes) || tt.keyframes[i] != frame {
+		return false
+	}
+
+	tt.keyframes = append(tt.keyframes[:i], tt.keyframes[i+1:]...)
+
+	return true
+}
+
+type timelineDragMode int
+
+const (
+	timelineDragNone timelineDragMode = iota
+	timelineDragSeek
+	timelineDragKeyframes
+)
+
+// Timeline is a widget that shows keyframes on multiple tracks along a
+// frame ruler, together with a playhead.
+//
+// Clicking or dragging in the ruler or on an empty spot of a track seeks the
+// playhead. Clicking a keyframe selects it, with the Ctrl key held down the
+// selection is toggled instead. Dragging a selected keyframe moves all
+// selected keyframes. Turning the mouse wheel scrolls the tracks, with the
+// Shift key held down it scrolls the frames and with the Ctrl key held down
+// it zooms around the mouse pointer.
+type Timeline struct {
+	WidgetBase
+	tracks                     []*TimelineTrack
+	frameCount                 int
+	frame                      int
+	frameWidth                 float64 // in 1/96"
+	scrollFrame                float64
+	scrollRow                  int
+	selection                  map[TimelineKeyframe]bool
+	dragMode                   timelineDragMode
+	dragFrame                  int
+	dragDelta                  int
+	frameChangedPublisher      EventPublisher
+	selectionChangedPublisher  EventPublisher
+	keyframesMovedPublisher    TimelineKeyframesMovedEventPublisher
+	frameCountChangedPublisher EventPublisher
+	contentChangedPublisher    EventPublisher
+	viewportChangedPublisher   EventPublisher
+}
+
+// NewTimeline creates and initializes a new Timeline.
+func NewTimeline(parent Container) (*Timeline, error) {
+	tl := &Timeline{
+		frameCount: 100,
+		frameWidth: 8,
+		selection:  make(map[TimelineKeyframe]bool),
+	}
+
+	if err := InitWidget(
+		tl,
+		parent,
+		timelineWindowClass,
+		win.WS_TABSTOP|win.WS_VISIBLE,
+		0); err != nil {
+		return nil, err
+	}
+
+	tl.GraphicsEffects().Add(InteractionEffect)
+	tl.GraphicsEffects().Add(FocusEffect)
+
+	tl.MustRegisterProperty("Frame", NewProperty(
+		func() interface{} {
+			return tl.Frame()
+		},
+		func(v interface{}) error {
+			return tl.SetFrame(assertIntOr(v, 0))
+		},
+		tl.frameChangedPublisher.Event()))
+
+	tl.MustRegisterProperty("FrameCount", NewProperty(
+		func() interface{} {
+			return tl.FrameCount()
+		},
+		func(v interface{}) error {
+			return tl.SetFrameCount(assertIntOr(v, 1))
+		},
+		tl.frameCountChangedPublisher.Event()))
+
+	return tl, nil
+}
+
+// Tracks returns the tracks of the Timeline from top to bottom.
+func (tl *Timeline) Tracks() []*TimelineTrack {
+	return append([]*TimelineTrack(nil), tl.tracks...)
+}
+
+// AddTrack appends a new track to the Timeline.
+func (tl *Timeline) AddTrack(name string) *TimelineTrack {
+	track := &TimelineTrack{timeline: tl, name: name}
+
+	tl.tracks = append(tl.tracks, track)
+
+	tl.contentChanged()
+
+	return track
+}
+
+// RemoveTrack removes track from the Timeline.
+func (tl *Timeline) RemoveTrack(track *TimelineTrack) {
+	for i, t := range tl.tracks {
+		if t == track {
+			tl.tracks = append(tl.tracks[:i], tl.tracks[i+1:]...)
+
+			tl.pruneSelection()
+			tl.contentChanged()
+			return
+		}
+	}
+}
+
+// FrameCount returns the number of frames of the Timeline.
+func (tl *Timeline) FrameCount() int {
+	return tl.frameCount
+}
+
+// SetFrameCount sets the number of frames of the Timeline.
+func (tl *Timeline) SetFrameCount(count int) error {
+	if count < 1 {
+		return newError("count must >= 1")
+	}
+
+	if count == tl.frameCount {
+		return nil
+	}
+
+	tl.frameCount = count
+
+	if tl.frame >= count {
+		tl.SetFrame(count - 1)
+	}
+
+	tl.setScrollFrame(tl.scrollFrame)
+
+	tl.contentChanged()
+	tl.viewportChanged()
+
+	tl.frameCountChangedPublisher.Publish()
+
+	return nil
+}
+
+// FrameCountChanged returns the event that is published when the number of
+// frames changed.
+func (tl *Timeline) FrameCountChanged() *Event {
+	return tl.frameCountChangedPublisher.Event()
+}
+
+// Frame returns the frame at the playhead.
+func (tl *Timeline) Frame() int {
+	return tl.frame
+}
+
+// SetFrame moves the playhead to frame.
+func (tl *Timeline) SetFrame(frame int) error {
+	frame = tl.clampFrame(frame)
+
+	if frame == tl.frame {
+		return nil
+	}
+
+	tl.frame = frame
+
+	tl.contentChanged()
+
+	tl.frameChangedPublisher.Publish()
+
+	return nil
+}
+
+// FrameChanged returns the event that is published when the playhead moved,
+// e.g. because the user seeked.
+func (tl *Timeline) FrameChanged() *Event {
+	return tl.frameChangedPublisher.Event()
+}
+
+// FrameWidth returns the width of a frame in 1/96".
+func (tl *Timeline) FrameWidth() float64 {
+	return tl.frameWidth
+}
+
+// SetFrameWidth sets the width of a frame in 1/96", which must be in the range
+// [1, 64].
+func (tl *Timeline) SetFrameWidth(width float64) error {
+	if width < 1 || width > 64 {
+		return newError("width must >= 1 && <= 64")
+	}
+
+	tl.frameWidth = width
+
+	tl.setScrollFrame(tl.scrollFrame)
+
+	tl.viewportChanged()
+
+	return nil
+}
+
+// EnsureFrameVisible scrolls the Timeline so that frame is visible.
+func (tl *Timeline) EnsureFrameVisible(frame int) {
+	visible := tl.visibleFrameCount()
+
+	if f := float64(frame); f < tl.scrollFrame {
+		tl.setScrollFrame(f)
+	} else if f+1 > tl.scrollFrame+visible {
+		tl.setScrollFrame(f + 1 - visible)
+	}
+}
+
+// SelectedKeyframes returns the selected keyframes.
+func (tl *Timeline) SelectedKeyframes() []TimelineKeyframe {
+	keyframes := make([]TimelineKeyframe, 0, len(tl.selection))
+	for _, track := range tl.tracks {
+		for _, frame := range track.keyframes {
+			if kf := (TimelineKeyframe{track, frame}); tl.selection[kf] {
+				keyframes = append(keyframes, kf)
+			}
+		}
+	}
+
+	return keyframes
+}
+
+// SetSelectedKeyframes sets the selected keyframes.
+func (tl *Timeline) SetSelectedKeyframes(keyframes []TimelineKeyframe) {
+	tl.selection = make(map[TimelineKeyframe]bool, len(keyframes))
+	for _, kf := range keyframes {
+		if kf.Track != nil && kf.Track.timeline == tl && kf.Track.HasKeyframe(kf.Frame) {
+			tl.selection[kf] = true
+		}
+	}
+
+	tl.contentChanged()
+
+	tl.selectionChangedPublisher.Publish()
+}
+
+// SelectionChanged returns the event that is published when the selected
+// keyframes changed.
+func (tl *Timeline) SelectionChanged() *Event {
+	return tl.selectionChangedPublisher.Event()
+}
+
+// KeyframesMoved returns the event that is published after the user dragged
+// selected keyframes. The event reports the keyframes at their new frames.
+func (tl *Timeline) KeyframesMoved() *TimelineKeyframesMovedEvent {
+	return tl.keyframesMovedPublisher.Event()
+}
+
+func (tl *Timeline) contentChanged() {
+	tl.Invalidate()
+
+	tl.contentChangedPublisher.Publish()
+}
+
+func (tl *Timeline) viewportChanged() {
+	tl.Invalidate()
+
+	tl.viewportChangedPublisher.Publish()
+}
+
+// pruneSelection drops selected keyframes that no longer exist.
+func (tl *Timeline) pruneSelection() {
+	changed := false
+
+	for kf := range tl.selection {
+		if kf.Track.timeline != tl || !kf.Track.HasKeyframe(kf.Frame) || !tl.hasTrack(kf.Track) {
+			delete(tl.selection, kf)
+			changed = true
+		}
+	}
+
+	if changed {
+		tl.selectionChangedPublisher.Publish()
+	}
+}
+
+func (tl *Timeline) hasTrack(track *TimelineTrack) bool {
+	for _, t := range tl.tracks {
+		if t == track {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (tl *Timeline) clampFrame(frame int) int {
+	if frame < 0 {
+		return 0
+	}
+	if frame >= tl.frameCount {
+		return tl.frameCount - 1
+	}
+
+	return frame
+}
+
+func (tl *Timeline) headerWidth() int {
+	return IntFrom96DPI(100, tl.DPI())
+}
+
+func (tl *Timeline) rulerHeight() int {
+	return IntFrom96DPI(22, tl.DPI())
+}
+
+func (tl *Timeline) rowHeight() int {
+	return IntFrom96DPI(20, tl.DPI())
+}
+
+func (tl *Timeline) frameWidthPixels() float64 {
+	return tl.frameWidth * float64(tl.DPI()) / 96
+}
+
+func (tl *Timeline) visibleFrameCount() float64 {
+	return float64(tl.ClientBoundsPixels().Width-tl.headerWidth()) / tl.frameWidthPixels()
+}
+
+func (tl *Timeline) visibleRowCount() int {
+	return (tl.ClientBoundsPixels().Height - tl.rulerHeight()) / tl.rowHeight()
+}
+
+func (tl *Timeline) setScrollFrame(frame float64) {
+	if max := float64(tl.frameCount) - tl.visibleFrameCount(); frame > max {
+		frame = max
+	}
+	if frame < 0 {
+		frame = 0
+	}
+
+	if frame != tl.scrollFrame {
+		tl.scrollFrame = frame
+		tl.viewportChanged()
+	}
+}
+
+func (tl *Timeline) setScrollRow(row int) {
+	if max := len(tl.tracks) - tl.visibleRowCount(); row > max {
+		row = max
+	}
+	if row < 0 {
+		row = 0
+	}
+
+	if row != tl.scrollRow {
+		tl.scrollRow = row
+		tl.Invalidate()
+	}
+}
+
+// xForFrame returns the x coordinate of the center of frame in native pixels.
+func (tl *Timeline) xForFrame(frame float64) int {
+	fw := tl.frameWidthPixels()
+
+	return tl.headerWidth() + int(math.Round((frame-tl.scrollFrame)*fw+fw/2))
+}
+
+func (tl *Timeline) frameAt(x int) int {
+	return int(math.Floor(float64(x-tl.headerWidth())/tl.frameWidthPixels() + tl.scrollFrame))
+}
+
+func (tl *Timeline) trackAt(y int) *TimelineTrack {
+	if y < tl.rulerHeight() {
+		return nil
+	}
+
+	row := (y-tl.rulerHeight())/tl.rowHeight() + tl.scrollRow
+	if row < 0 || row >= len(tl.tracks) {
+		return nil
+	}
+
+	return tl.tracks[row]
+}
+
+func (tl *Timeline) keyframeAt(x, y int) (TimelineKeyframe, bool) {
+	track := tl.trackAt(y)
+	if track == nil || x < tl.headerWidth() {
+		return TimelineKeyframe{}, false
+	}
+
+	radius := tl.markerRadius()
+
+	best, bestDist := -1, radius+1
+	for _, frame := range track.keyframes {
+		if d := x - tl.xForFrame(float64(frame)); d >= -radius && d <= radius {
+			if d < 0 {
+				d = -d
+			}
+			if d < bestDist {
+				best, bestDist = frame, d
+			}
+		}
+	}
+
+	if best == -1 {
+		return TimelineKeyframe{}, false
+	}
+
+	return TimelineKeyframe{track, best}, true
+}
+
+func (tl *Timeline) markerRadius() int {
+	return IntFrom96DPI(5, tl.DPI())
+}
+
+// clampDragDelta limits delta so that no selected keyframe leaves the range of
+// frames.
+func (tl *Timeline) clampDragDelta(delta int) int {
+	for kf := range tl.selection {
+		if f := kf.Frame + delta; f < 0 {
+			delta = -kf.Frame
+		} else if f >= tl.frameCount {
+			delta = tl.frameCount - 1 - kf.Frame
+		}
+	}
+
+	return delta
+}
+
+func (tl *Timeline) moveSelection(delta int) {
+	if delta == 0 || len(tl.selection) == 0 {
+		return
+	}
+
+	moved := tl.SelectedKeyframes()
+
+	for _, kf := range moved {
+		kf.Track.remove(kf.Frame)
+	}
+
+	tl.selection = make(map[TimelineKeyframe]bool, len(moved))
+	for i, kf := range moved {
+		kf.Frame += delta
+		kf.Track.insert(kf.Frame)
+
+		moved[i] = kf
+		tl.selection[kf] = true
+	}
+
+	tl.contentChanged()
+
+	tl.keyframesMovedPublisher.Publish(moved, delta)
+}
+
+func (tl *Timeline) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, tl.DPI(), tl.paint) {
+			return 0
+		}
+
+	case win.WM_GETDLGCODE:
+		return win.DLGC_WANTARROWS
+
+	case win.WM_LBUTTONDOWN, win.WM_LBUTTONDBLCLK:
+		if !tl.Enabled() {
+			break
+		}
+
+		tl.SetFocus()
+
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+		if x < tl.headerWidth() {
+			break
+		}
+
+		if kf, ok := tl.keyframeAt(x, y); ok {
+			if wParam&win.MK_CONTROL != 0 {
+				if tl.selection[kf] {
+					delete(tl.selection, kf)
+				} else {
+					tl.selection[kf] = true
+				}
+				tl.contentChanged()
+				tl.selectionChangedPublisher.Publish()
+				break
+			}
+
+			if !tl.selection[kf] {
+				tl.SetSelectedKeyframes([]TimelineKeyframe{kf})
+			}
+
+			tl.dragMode = timelineDragKeyframes
+			tl.dragFrame = tl.frameAt(x)
+			tl.dragDelta = 0
+			win.SetCapture(hwnd)
+			break
+		}
+
+		if y >= tl.rulerHeight() && wParam&win.MK_CONTROL == 0 && len(tl.selection) > 0 {
+			tl.SetSelectedKeyframes(nil)
+		}
+
+		tl.dragMode = timelineDragSeek
+		win.SetCapture(hwnd)
+		tl.SetFrame(tl.frameAt(x))
+
+	case win.WM_MOUSEMOVE:
+		x := int(win.GET_X_LPARAM(lParam))
+
+		switch tl.dragMode {
+		case timelineDragSeek:
+			tl.SetFrame(tl.frameAt(x))
+			tl.EnsureFrameVisible(tl.frame)
+
+		case timelineDragKeyframes:
+			if delta := tl.clampDragDelta(tl.frameAt(x) - tl.dragFrame); delta != tl.dragDelta {
+				tl.dragDelta = delta
+				tl.Invalidate()
+			}
+		}
+
+	case win.WM_LBUTTONUP:
+		if tl.dragMode == timelineDragNone {
+			break
+		}
+
+		mode, delta := tl.dragMode, tl.dragDelta
+		tl.dragMode = timelineDragNone
+		tl.dragDelta = 0
+		win.ReleaseCapture()
+
+		if mode == timelineDragKeyframes {
+			tl.moveSelection(delta)
+		}
+
+	case win.WM_CAPTURECHANGED:
+		if tl.dragMode != timelineDragNone {
+			tl.dragMode = timelineDragNone
+			tl.dragDelta = 0
+			tl.Invalidate()
+		}
+
+	case win.WM_MOUSEWHEEL:
+		delta := int(int16(win.HIWORD(uint32(wParam)))) / 120
+		keys := win.LOWORD(uint32(wParam))
+
+		switch {
+		case keys&win.MK_CONTROL != 0:
+			pt := win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}
+			win.ScreenToClient(hwnd, &pt)
+
+			anchor := float64(int(pt.X)-tl.headerWidth())/tl.frameWidthPixels() + tl.scrollFrame
+
+			width := math.Max(1, math.Min(64, tl.frameWidth*math.Pow(1.25, float64(delta))))
+			tl.SetFrameWidth(width)
+
+			tl.setScrollFrame(anchor - float64(int(pt.X)-tl.headerWidth())/tl.frameWidthPixels())
+
+		case keys&win.MK_SHIFT != 0 || len(tl.tracks) <= tl.visibleRowCount():
+			tl.setScrollFrame(tl.scrollFrame - float64(delta)*math.Max(1, tl.visibleFrameCount()/10))
+
+		default:
+			tl.setScrollRow(tl.scrollRow - delta)
+		}
+
+		return 0
+
+	case win.WM_KEYDOWN:
+		if !tl.Enabled() {
+			break
+		}
+
+		switch Key(wParam) {
+		case KeyLeft:
+			tl.SetFrame(tl.frame - 1)
+
+		case KeyRight:
+			tl.SetFrame(tl.frame + 1)
+
+		case KeyHome:
+			tl.SetFrame(0)
+
+		case KeyEnd:
+			tl.SetFrame(tl.frameCount - 1)
+
+		default:
+			return tl.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+		}
+
+		tl.EnsureFrameVisible(tl.frame)
+
+	case win.WM_SIZE:
+		tl.setScrollFrame(tl.scrollFrame)
+		tl.setScrollRow(tl.scrollRow)
+		tl.viewportChanged()
+	}
+
+	return tl.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+// rulerStep returns the number of frames between two labeled ticks of the
+// ruler.
+func (tl *Timeline) rulerStep() int {
+	minSpacing := float64(IntFrom96DPI(60, tl.DPI()))
+	fw := tl.frameWidthPixels()
+
+	for _, step := range []int{1, 2, 5, 10, 20, 30, 50, 100, 200, 300, 500, 1000} {
+		if float64(step)*fw >= minSpacing {
+			return step
+		}
+	}
+
+	return 1000 * int(math.Ceil(minSpacing/(1000*fw)))
+}
+
+func (tl *Timeline) paint(canvas *Canvas, bounds Rectangle) error {
+	dpi := tl.DPI()
+	font := tl.Font()
+
+	headerWidth := tl.headerWidth()
+	rulerHeight := tl.rulerHeight()
+	rowHeight := tl.rowHeight()
+	fw := tl.frameWidthPixels()
+
+	windowColor := Color(win.GetSysColor(win.COLOR_WINDOW))
+	faceColor := Color(win.GetSysColor(win.COLOR_BTNFACE))
+	textColor := Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+	if !tl.Enabled() {
+		textColor = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	windowBrush, err := NewSolidColorBrush(windowColor)
+	if err != nil {
+		return err
+	}
+	defer windowBrush.Dispose()
+
+	faceBrush, err := NewSolidColorBrush(faceColor)
+	if err != nil {
+		return err
+	}
+	defer faceBrush.Dispose()
+
+	linePen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_3DLIGHT)))
+	if err != nil {
+		return err
+	}
+	defer linePen.Dispose()
+
+	shadowPen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer shadowPen.Dispose()
+
+	if err := canvas.FillRectanglePixels(windowBrush, bounds); err != nil {
+		return err
+	}
+
+	// Ruler
+	if err := canvas.FillRectanglePixels(faceBrush, Rectangle{0, 0, bounds.Width, rulerHeight}); err != nil {
+		return err
+	}
+
+	firstFrame := int(math.Floor(tl.scrollFrame))
+	lastFrame := mini(tl.frameCount-1, int(math.Ceil(tl.scrollFrame+tl.visibleFrameCount())))
+	step := tl.rulerStep()
+
+	minorStep := 1
+	for float64(minorStep)*fw < float64(IntFrom96DPI(4, dpi)) {
+		minorStep *= 5
+	}
+
+	saved := win.SaveDC(canvas.hdc)
+	win.IntersectClipRect(canvas.hdc, int32(headerWidth), 0, int32(bounds.Width), int32(bounds.Height))
+
+	err = func() error {
+		for f := firstFrame - firstFrame%minorStep; f <= lastFrame; f += minorStep {
+			if f < 0 {
+				continue
+			}
+
+			x := tl.xForFrame(float64(f))
+
+			tick := IntFrom96DPI(3, dpi)
+			if f%step == 0 {
+				tick = rulerHeight / 2
+
+				labelBounds := Rectangle{x + IntFrom96DPI(2, dpi), 0, IntFrom96DPI(60, dpi), rulerHeight - tick}
+				if err := canvas.DrawTextPixels(strconv.Itoa(f), font, textColor, labelBounds, TextLeft|TextVCenter|TextSingleLine); err != nil {
+					return err
+				}
+			}
+
+			if err := canvas.DrawLinePixels(shadowPen, Point{x, rulerHeight - tick}, Point{x, rulerHeight}); err != nil {
+				return err
+			}
+		}
+
+		// Tracks
+		for row := tl.scrollRow; row < len(tl.tracks); row++ {
+			y := rulerHeight + (row-tl.scrollRow)*rowHeight
+			if y > bounds.Height {
+				break
+			}
+
+			if (row-tl.scrollRow)%2 == 1 {
+				if err := canvas.FillRectanglePixels(faceBrush, Rectangle{headerWidth, y, bounds.Width - headerWidth, rowHeight}); err != nil {
+					return err
+				}
+			}
+
+			if err := canvas.DrawLinePixels(linePen, Point{headerWidth, y + rowHeight - 1}, Point{bounds.Width, y + rowHeight - 1}); err != nil {
+				return err
+			}
+		}
+
+		for f := firstFrame - firstFrame%step; f <= lastFrame; f += step {
+			if f < 0 {
+				continue
+			}
+
+			x := tl.xForFrame(float64(f))
+			if err := canvas.DrawLinePixels(linePen, Point{x, rulerHeight}, Point{x, bounds.Height}); err != nil {
+				return err
+			}
+		}
+
+		if err := tl.paintKeyframes(canvas, bounds); err != nil {
+			return err
+		}
+
+		return tl.paintPlayhead(canvas, bounds)
+	}()
+
+	win.RestoreDC(canvas.hdc, saved)
+
+	if err != nil {
+		return err
+	}
+
+	// Track headers
+	if err := canvas.FillRectanglePixels(faceBrush, Rectangle{0, rulerHeight, headerWidth, bounds.Height - rulerHeight}); err != nil {
+		return err
+	}
+
+	padding := IntFrom96DPI(4, dpi)
+	for row := tl.scrollRow; row < len(tl.tracks); row++ {
+		y := rulerHeight + (row-tl.scrollRow)*rowHeight
+		if y > bounds.Height {
+			break
+		}
+
+		nameBounds := Rectangle{padding, y, headerWidth - 2*padding, rowHeight}
+		if err := canvas.DrawTextPixels(tl.tracks[row].name, font, textColor, nameBounds, TextLeft|TextVCenter|TextSingleLine|TextEndEllipsis); err != nil {
+			return err
+		}
+
+		if err := canvas.DrawLinePixels(linePen, Point{0, y + rowHeight - 1}, Point{headerWidth, y + rowHeight - 1}); err != nil {
+			return err
+		}
+	}
+
+	if err := canvas.DrawLinePixels(shadowPen, Point{headerWidth - 1, 0}, Point{headerWidth - 1, bounds.Height}); err != nil {
+		return err
+	}
+
+	return canvas.DrawLinePixels(shadowPen, Point{0, rulerHeight - 1}, Point{bounds.Width, rulerHeight - 1})
+}
+
+func (tl *Timeline) paintKeyframes(canvas *Canvas, bounds Rectangle) error {
+	rulerHeight := tl.rulerHeight()
+	rowHeight := tl.rowHeight()
+	radius := tl.markerRadius()
+
+	keyBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer keyBrush.Dispose()
+
+	selectedBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_HIGHLIGHT)))
+	if err != nil {
+		return err
+	}
+	defer selectedBrush.Dispose()
+
+	minFrame := tl.scrollFrame - 1
+	maxFrame := tl.scrollFrame + tl.visibleFrameCount() + 1
+
+	for row := tl.scrollRow; row < len(tl.tracks); row++ {
+		y := rulerHeight + (row-tl.scrollRow)*rowHeight + rowHeight/2
+		if y-radius > bounds.Height {
+			break
+		}
+
+		track := tl.tracks[row]
+
+		for _, frame := range track.keyframes {
+			brush := keyBrush
+
+			if tl.selection[TimelineKeyframe{track, frame}] {
+				brush = selectedBrush
+				frame += tl.dragDelta
+			}
+
+			if f := float64(frame); f < minFrame || f > maxFrame {
+				continue
+			}
+
+			x := tl.xForFrame(float64(frame))
+
+			if err := canvas.FillEllipsePixels(brush, Rectangle{x - radius, y - radius, 2 * radius, 2 * radius}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (tl *Timeline) paintPlayhead(canvas *Canvas, bounds Rectangle) error {
+	dpi := tl.DPI()
+
+	brush, err := NewSolidColorBrush(RGB(220, 40, 40))
+	if err != nil {
+		return err
+	}
+	defer brush.Dispose()
+
+	pen, err := NewGeometricPen(PenSolid|PenCapFlat, IntFrom96DPI(1, dpi), brush)
+	if err != nil {
+		return err
+	}
+	defer pen.Dispose()
+
+	x := tl.xForFrame(float64(tl.frame))
+
+	if err := canvas.DrawLinePixels(pen, Point{x, 0}, Point{x, bounds.Height}); err != nil {
+		return err
+	}
+
+	hw := IntFrom96DPI(4, dpi)
+
+	return canvas.FillRectanglePixels(brush, Rectangle{x - hw, tl.rulerHeight() - 2*hw, 2*hw + 1, 2 * hw})
+}
+
+func (*Timeline) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	return NewGreedyLayoutItem()
+}
+
+// MinimapExtent returns the number of frames, so that a Timeline can be the
+// source of a Minimap.
+func (tl *Timeline) MinimapExtent() float64 {
+	return float64(tl.frameCount)
+}
+
+// MinimapViewport returns the range of visible frames.
+func (tl *Timeline) MinimapViewport() (start, length float64) {
+	return tl.scrollFrame, math.Min(tl.visibleFrameCount(), float64(tl.frameCount))
+}
+
+// SetMinimapViewportStart scrolls the Timeline so that the visible frames
+// start at start.
+func (tl *Timeline) SetMinimapViewportStart(start float64) {
+	tl.setScrollFrame(start)
+}
+
+// PaintMinimap paints a miniature of all tracks into bounds, with a line for
+// each keyframe and the playhead.
+func (tl *Timeline) PaintMinimap(canvas *Canvas, bounds Rectangle) error {
+	windowBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_WINDOW)))
+	if err != nil {
+		return err
+	}
+	defer windowBrush.Dispose()
+
+	if err := canvas.FillRectanglePixels(windowBrush, bounds); err != nil {
+		return err
+	}
+
+	if len(tl.tracks) == 0 || bounds.Width <= 0 {
+		return nil
+	}
+
+	keyPen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer keyPen.Dispose()
+
+	selectedPen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_HIGHLIGHT)))
+	if err != nil {
+		return err
+	}
+	defer selectedPen.Dispose()
+
+	xForFrame := func(frame int) int {
+		return bounds.X + int(int64(frame)*int64(bounds.Width)/int64(tl.frameCount))
+	}
+
+	for row, track := range tl.tracks {
+		y0 := bounds.Y + row*bounds.Height/len(tl.tracks)
+		y1 := bounds.Y + (row+1)*bounds.Height/len(tl.tracks)
+		if y1 <= y0 {
+			continue
+		}
+
+		for _, frame := range track.keyframes {
+			pen := keyPen
+			if tl.selection[TimelineKeyframe{track, frame}] {
+				pen = selectedPen
+			}
+
+			x := xForFrame(frame)
+			if err := canvas.DrawLinePixels(pen, Point{x, y0}, Point{x, y1}); err != nil {
+				return err
+			}
+		}
+	}
+
+	playheadPen, err := NewCosmeticPen(PenSolid, RGB(220, 40, 40))
+	if err != nil {
+		return err
+	}
+	defer playheadPen.Dispose()
+
+	x := xForFrame(tl.frame)
+
+	return canvas.DrawLinePixels(playheadPen, Point{x, bounds.Y}, Point{x, bounds.Y + bounds.Height})
+}
+
+// MinimapContentChanged returns the event that is published when tracks,
+// keyframes, the selection or the playhead changed.
+func (tl *Timeline) MinimapContentChanged() *Event {
+	return tl.contentChangedPublisher.Event()
+}
+
+// MinimapViewportChanged returns the event that is published when the range
+// of visible frames changed.
+func (tl *Timeline) MinimapViewportChanged() *Event {
+	return tl.viewportChangedPublisher.Event()
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+type timelineKeyframesMovedEventHandlerInfo struct {
+	handler TimelineKeyframesMovedEventHandler
+	once    bool
+}
+
+type TimelineKeyframesMovedEventHandler func(keyframes []TimelineKeyframe, delta int)
+
+type TimelineKeyframesMovedEvent struct {
+	handlers   []*timelineKeyframesMovedEventHandlerInfo
+	publishing int // Number of Publish calls in progress
+}
+
+func (e *TimelineKeyframesMovedEvent) Attach(handler TimelineKeyframesMovedEventHandler) int {
+	handlerInfo := &timelineKeyframesMovedEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*timelineKeyframesMovedEventHandlerInfo(nil), e.handlers...)
+	}
+
+	for i, h := range e.handlers {
+		if h.handler == nil {
+			e.handlers[i] = handlerInfo
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handlerInfo)
+
+	return len(e.handlers) - 1
+}
+
+func (e *TimelineKeyframesMovedEvent) Detach(handle int) {
+	e.handlers[handle].handler = nil
+}
+
+func (e *TimelineKeyframesMovedEvent) Once(handler TimelineKeyframesMovedEventHandler) {
+	i := e.Attach(handler)
+	e.handlers[i].once = true
+}
+
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *TimelineKeyframesMovedEvent) Connect(handler TimelineKeyframesMovedEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *TimelineKeyframesMovedEvent) AttachOwned(owner Window, handler TimelineKeyframesMovedEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
+type TimelineKeyframesMovedEventPublisher struct {
+	event TimelineKeyframesMovedEvent
+}
+
+func (p *TimelineKeyframesMovedEventPublisher) Event() *TimelineKeyframesMovedEvent {
+	return &p.event
+}
+
+func (p *TimelineKeyframesMovedEventPublisher) Publish(keyframes []TimelineKeyframe, delta int) {
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
+
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
+			if h.once {
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
+			}
+
+			handler(keyframes, delta)
+		}
+	}
+}
+
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+func (p *TimelineKeyframesMovedEventPublisher) PublishAsync(keyframes []TimelineKeyframe, delta int) {
+	publishAsync(func() {
+		p.Publish(keyframes, delta)
+	})
+}
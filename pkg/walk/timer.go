@@ -0,0 +1,153 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"time"
+)
+
+// Timer calls a handler on the UI thread of a Form, every interval or once in
+// single-shot mode, while it is started. It is disposed with the Form.
+//
+// Use it instead of a goroutine with time.Ticker and Synchronize for periodic
+// UI updates. It is a UITimer that can be stopped and started again.
+type Timer struct {
+	owner                Form
+	interval             time.Duration
+	handler              func()
+	singleShot           bool
+	timer                *UITimer // while started
+	ownerDisposingHandle int
+	disposed             bool
+}
+
+// NewTimer returns a new Timer that calls handler every interval, once it is
+// started with Start. It is disposed with owner.
+//
+// It must be called on the UI thread of owner.
+func NewTimer(owner Form, interval time.Duration, handler func()) (*Timer, error) {
+	if owner == nil {
+		return nil, newError("owner required")
+	}
+	if interval <= 0 {
+		return nil, newError("interval must be positive")
+	}
+
+	t := &Timer{
+		owner:    owner,
+		interval: interval,
+		handler:  handler,
+	}
+
+	t.ownerDisposingHandle = owner.Disposing().Attach(t.Dispose)
+
+	return t, nil
+}
+
+// Start starts the Timer, so handler is called after the interval. If the
+// Timer is started already, its current period starts over.
+func (t *Timer) Start() error {
+	if t.disposed {
+		return newError("timer is disposed")
+	}
+
+	if t.timer != nil {
+		return t.timer.Restart()
+	}
+
+	timer, err := NewUITimer(t.interval, t.tick)
+	if err != nil {
+		return err
+	}
+
+	t.timer = timer
+
+	return nil
+}
+
+// Stop stops the Timer, so handler isn't called before Start is called again.
+func (t *Timer) Stop() {
+	if t.timer == nil {
+		return
+	}
+
+	t.timer.Dispose()
+	t.timer = nil
+}
+
+// Active returns whether the Timer is started.
+func (t *Timer) Active() bool {
+	return t.timer != nil
+}
+
+func (t *Timer) tick() {
+	// Stop first, so handler may start the Timer again.
+	if t.singleShot {
+		t.Stop()
+	}
+
+	if t.handler != nil {
+		t.handler()
+	}
+}
+
+// Interval returns the time from starting the Timer, or from the last call of
+// handler, until handler is called.
+func (t *Timer) Interval() time.Duration {
+	return t.interval
+}
+
+// SetInterval sets the time from starting the Timer, or from the last call of
+// handler, until handler is called. If the Timer is started, its current
+// period starts over with the new interval.
+func (t *Timer) SetInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return newError("interval must be positive")
+	}
+
+	t.interval = interval
+
+	if t.timer == nil {
+		return nil
+	}
+
+	return t.timer.SetInterval(interval)
+}
+
+// SingleShot returns whether the Timer stops after calling handler once.
+func (t *Timer) SingleShot() bool {
+	return t.singleShot
+}
+
+// SetSingleShot sets whether the Timer stops after calling handler once.
+func (t *Timer) SetSingleShot(singleShot bool) {
+	t.singleShot = singleShot
+}
+
+// Owner returns the Form the Timer is disposed with.
+func (t *Timer) Owner() Form {
+	return t.owner
+}
+
+// Dispose stops the Timer for good. It is called when the owner is disposed.
+func (t *Timer) Dispose() {
+	if t.disposed {
+		return
+	}
+
+	t.disposed = true
+
+	t.Stop()
+
+	t.owner.Disposing().Detach(t.ownerDisposingHandle)
+}
+
+// IsDisposed returns if Dispose was called.
+func (t *Timer) IsDisposed() bool {
+	return t.disposed
+}
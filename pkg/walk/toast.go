@@ -0,0 +1,427 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const toastWindowClass = `\o/ Walk_Toast_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(toastWindowClass)
+	})
+}
+
+const (
+	toastSlideTimerId   = 1
+	toastDismissTimerId = 2
+	toastSlideInterval  = 15 // milliseconds per step
+	toastSlideSteps     = 10
+)
+
+// DefaultToastDuration is how long a toast is shown, if ToastOptions.Duration
+// is 0.
+const DefaultToastDuration = 4 * time.Second
+
+// ToastCorner specifies the corner of a Form a toast is shown in.
+type ToastCorner int
+
+const (
+	ToastBottomRight ToastCorner = iota
+	ToastBottomLeft
+	ToastTopRight
+	ToastTopLeft
+)
+
+// ToastOptions specifies what a toast shows and when it goes away.
+type ToastOptions struct {
+	// Text is the message of the toast.
+	Text string
+
+	// Icon, if not nil, is drawn in front of Text.
+	Icon Image
+
+	// Duration is how long the toast is shown. It is DefaultToastDuration, if
+	// 0, and the toast is shown until it is clicked or closed, if negative.
+	// While the mouse is over the toast, it is not dismissed.
+	Duration time.Duration
+
+	// Action, if not nil, is offered as a link next to Text. Clicking it
+	// triggers the action and closes the toast.
+	Action *Action
+
+	// Corner is the corner of the Form the toast is shown in.
+	Corner ToastCorner
+}
+
+// Toast is a transient message panel that slides in over a corner of a Form.
+// It is created by ShowToast.
+//
+// A Toast never takes the keyboard focus. Clicking it closes it. Several
+// toasts in the same corner are stacked.
+type Toast struct {
+	WindowBase
+	form               Form
+	options            ToastOptions
+	slide              int // 0 is hidden, toastSlideSteps fully shown
+	closing            bool
+	trackingMouseEvent bool
+	panelSize          Size // in native pixels
+	iconBounds         Rectangle
+	textBounds         Rectangle
+	actionBounds       Rectangle
+	closedPublisher    EventPublisher
+}
+
+// toastHost tracks the toasts shown in a Form.
+type toastHost struct {
+	toasts            []*Toast
+	clippedSiblings   []win.HWND
+	sizeChangedHandle int
+}
+
+var form2ToastHost = make(map[Form]*toastHost)
+
+// ShowToast slides a toast with the specified options in over a corner of
+// form, without activating it or moving the keyboard focus.
+func ShowToast(form Form, options ToastOptions) (*Toast, error) {
+	if options.Duration == 0 {
+		options.Duration = DefaultToastDuration
+	}
+
+	t := &Toast{form: form, options: options}
+
+	if err := InitWindow(
+		t,
+		form,
+		toastWindowClass,
+		win.WS_CHILD|win.WS_CLIPSIBLINGS,
+		0); err != nil {
+		return nil, err
+	}
+
+	t.SetFont(form.Font())
+
+	host := form2ToastHost[form]
+	if host == nil {
+		host = new(toastHost)
+		form2ToastHost[form] = host
+
+		host.clippedSiblings = clipSiblings(t.hWnd)
+		host.sizeChangedHandle = form.SizeChanged().Attach(func() {
+			host.arrange()
+		})
+	}
+	host.toasts = append(host.toasts, t)
+
+	t.updatePanelSize()
+
+	win.SetWindowPos(t.hWnd, win.HWND_TOP, 0, 0, 0, 0, win.SWP_NOMOVE|win.SWP_NOSIZE|win.SWP_NOACTIVATE)
+
+	host.arrange()
+
+	t.startTimer(toastSlideTimerId, toastSlideInterval)
+	t.startDismissTimer()
+
+	return t, nil
+}
+
+// Options returns the options the Toast was shown with.
+func (t *Toast) Options() ToastOptions {
+	return t.options
+}
+
+// Close slides the Toast out and then removes it. Calling Close more than
+// once has no effect.
+func (t *Toast) Close() {
+	if t.closing || t.hWnd == 0 {
+		return
+	}
+
+	t.closing = true
+
+	win.KillTimer(t.hWnd, toastDismissTimerId)
+	t.startTimer(toastSlideTimerId, toastSlideInterval)
+}
+
+// Closed returns the event that is published when the Toast was removed.
+func (t *Toast) Closed() *Event {
+	return t.closedPublisher.Event()
+}
+
+func (t *Toast) startTimer(id uintptr, interval uint32) {
+	if 0 == win.SetTimer(t.hWnd, id, interval, 0) {
+		lastError("SetTimer")
+	}
+}
+
+func (t *Toast) startDismissTimer() {
+	if t.options.Duration < 0 || t.closing {
+		return
+	}
+
+	t.startTimer(toastDismissTimerId, uint32(t.options.Duration/time.Millisecond))
+}
+
+func (t *Toast) updatePanelSize() {
+	dpi := t.DPI()
+	padding := IntFrom96DPI(12, dpi)
+	gap := IntFrom96DPI(8, dpi)
+
+	canvas, err := t.CreateCanvas()
+	if err != nil {
+		return
+	}
+	defer canvas.Dispose()
+
+	font := t.Font()
+
+	t.iconBounds = Rectangle{}
+	t.actionBounds = Rectangle{}
+
+	x := padding
+	height := 0
+
+	if t.options.Icon != nil {
+		iconSize := IntFrom96DPI(16, dpi)
+
+		t.iconBounds = Rectangle{x, 0, iconSize, iconSize}
+		x += iconSize + gap
+		height = iconSize
+	}
+
+	t.textBounds, _, _ = canvas.MeasureTextPixels(t.options.Text, font, Rectangle{Width: IntFrom96DPI(320, dpi), Height: 9999999}, TextWordbreak)
+	t.textBounds.X = x
+	x += t.textBounds.Width
+	height = maxi(height, t.textBounds.Height)
+
+	if t.options.Action != nil {
+		t.actionBounds, _, _ = canvas.MeasureTextPixels(t.options.Action.Text(), font, Rectangle{Width: 9999999, Height: 9999999}, TextSingleLine)
+		t.actionBounds.X = x + 2*gap
+		x = t.actionBounds.X + t.actionBounds.Width
+		height = maxi(height, t.actionBounds.Height)
+	}
+
+	t.panelSize = Size{x + padding, height + 2*padding}
+
+	for _, bounds := range []*Rectangle{&t.iconBounds, &t.textBounds, &t.actionBounds} {
+		bounds.Y = (t.panelSize.Height - bounds.Height) / 2
+	}
+}
+
+func (t *Toast) isOverAction(pt Point) bool {
+	ab := t.actionBounds
+
+	return t.options.Action != nil && pt.X >= ab.X && pt.X < ab.X+ab.Width && pt.Y >= ab.Y && pt.Y < ab.Y+ab.Height
+}
+
+// arrange positions the toasts of the host, stacking each corner from its
+// oldest toast outwards.
+func (h *toastHost) arrange() {
+	if len(h.toasts) == 0 {
+		return
+	}
+
+	var cr win.RECT
+	if !win.GetClientRect(h.toasts[0].form.Handle(), &cr) {
+		return
+	}
+
+	dpi := h.toasts[0].form.DPI()
+	margin := IntFrom96DPI(12, dpi)
+	spacing := IntFrom96DPI(8, dpi)
+
+	offsets := make(map[ToastCorner]int)
+
+	for _, t := range h.toasts {
+		corner := t.options.Corner
+		size := t.panelSize
+
+		x := int(cr.Left) + margin
+		if corner == ToastBottomRight || corner == ToastTopRight {
+			x = int(cr.Right) - margin - size.Width
+		}
+
+		// While sliding, the toast is moved towards the nearest edge, by as
+		// much as it takes to hide it.
+		hidden := (size.Height + margin) * (toastSlideSteps - t.slide) / toastSlideSteps
+
+		var y int
+		if corner == ToastTopLeft || corner == ToastTopRight {
+			y = int(cr.Top) + margin + offsets[corner] - hidden
+		} else {
+			y = int(cr.Bottom) - margin - offsets[corner] - size.Height + hidden
+		}
+
+		offsets[corner] += size.Height + spacing
+
+		flags := uint32(win.SWP_NOZORDER | win.SWP_NOACTIVATE)
+		if t.slide > 0 {
+			flags |= win.SWP_SHOWWINDOW
+		}
+
+		win.SetWindowPos(t.hWnd, 0, int32(x), int32(y), int32(size.Width), int32(size.Height), flags)
+	}
+}
+
+func (h *toastHost) remove(t *Toast) {
+	for i, toast := range h.toasts {
+		if toast == t {
+			h.toasts = append(h.toasts[:i], h.toasts[i+1:]...)
+			break
+		}
+	}
+
+	if len(h.toasts) > 0 {
+		h.arrange()
+		return
+	}
+
+	unclipSiblings(h.clippedSiblings)
+	h.clippedSiblings = nil
+
+	t.form.SizeChanged().Detach(h.sizeChangedHandle)
+
+	delete(form2ToastHost, t.form)
+}
+
+func (t *Toast) step() {
+	if t.closing {
+		t.slide--
+	} else {
+		t.slide++
+	}
+
+	if t.closing && t.slide <= 0 {
+		t.Dispose()
+		return
+	}
+
+	if !t.closing && t.slide >= toastSlideSteps {
+		t.slide = toastSlideSteps
+		win.KillTimer(t.hWnd, toastSlideTimerId)
+	}
+
+	if host := form2ToastHost[t.form]; host != nil {
+		host.arrange()
+	}
+}
+
+func (t *Toast) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, t.DPI(), t.paint) {
+			return 0
+		}
+
+	case win.WM_TIMER:
+		switch wParam {
+		case toastSlideTimerId:
+			t.step()
+			return 0
+
+		case toastDismissTimerId:
+			t.Close()
+			return 0
+		}
+
+	case win.WM_MOUSEMOVE:
+		if !t.trackingMouseEvent {
+			var tme win.TRACKMOUSEEVENT
+			tme.CbSize = uint32(unsafe.Sizeof(tme))
+			tme.DwFlags = win.TME_LEAVE
+			tme.HwndTrack = hwnd
+
+			t.trackingMouseEvent = win.TrackMouseEvent(&tme)
+
+			// Don't dismiss the toast while the user is looking at it.
+			win.KillTimer(hwnd, toastDismissTimerId)
+		}
+
+	case win.WM_MOUSELEAVE:
+		t.trackingMouseEvent = false
+		t.startDismissTimer()
+
+	case win.WM_SETCURSOR:
+		var pt win.POINT
+		if win.GetCursorPos(&pt) && win.ScreenToClient(hwnd, &pt) && t.isOverAction(Point{int(pt.X), int(pt.Y)}) {
+			win.SetCursor(CursorHand().handle())
+			return 1
+		}
+
+	case win.WM_LBUTTONUP:
+		pt := Point{int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))}
+
+		if t.isOverAction(pt) {
+			t.options.Action.raiseTriggered()
+		}
+
+		t.Close()
+
+	case win.WM_DESTROY:
+		// Also reached if the form goes away before the toast.
+		win.KillTimer(hwnd, toastSlideTimerId)
+		win.KillTimer(hwnd, toastDismissTimerId)
+
+		if host := form2ToastHost[t.form]; host != nil {
+			host.remove(t)
+		}
+
+		t.closedPublisher.Publish()
+	}
+
+	return t.WindowBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (t *Toast) paint(canvas *Canvas, bounds Rectangle) error {
+	bgBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_INFOBK)))
+	if err != nil {
+		return err
+	}
+	defer bgBrush.Dispose()
+
+	if err := canvas.FillRectanglePixels(bgBrush, bounds); err != nil {
+		return err
+	}
+
+	borderPen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer borderPen.Dispose()
+
+	if err := canvas.DrawRectanglePixels(borderPen, bounds); err != nil {
+		return err
+	}
+
+	if t.options.Icon != nil {
+		if err := canvas.DrawImageStretchedPixels(t.options.Icon, t.iconBounds); err != nil {
+			return err
+		}
+	}
+
+	font := t.Font()
+
+	if err := canvas.DrawTextPixels(t.options.Text, font, Color(win.GetSysColor(win.COLOR_INFOTEXT)), t.textBounds, TextWordbreak); err != nil {
+		return err
+	}
+
+	if t.options.Action == nil {
+		return nil
+	}
+
+	return canvas.DrawTextPixels(t.options.Action.Text(), font, Color(win.GetSysColor(win.COLOR_HOTLIGHT)), t.actionBounds, TextSingleLine)
+}
@@ -0,0 +1,899 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/miu200521358/win"
+)
+
+const tokenEditWindowClass = `\o/ Walk_TokenEdit_Class \o/`
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClass(tokenEditWindowClass)
+	})
+}
+
+// tokenSeparators are the characters that commit the typed text as token.
+const tokenSeparators = ",;"
+
+// TokenEdit is a widget for entering a list of short strings, like tags or
+// labels, that are shown as removable chips in front of a text field.
+//
+// Typed text becomes a token when Enter, Tab, a comma or a semicolon is
+// pressed or when the TokenEdit loses focus. Tokens already present are
+// rejected, by default ignoring case. Backspace in the empty text field
+// removes the last token, clicking the cross of a chip removes that one.
+//
+// If a model is set, the text field completes typed text inline from the
+// model values, which can be cycled through with the KeyUp and KeyDown keys.
+type TokenEdit struct {
+	WidgetBase
+	edit                   *tokenLineEdit
+	tokens                 []string
+	chipWidths             []int       // in native pixels
+	chipBounds             []Rectangle // in native pixels
+	model                  ListModel
+	caseSensitive          bool
+	hotCloseIndex          int // -1 if no cross is hot
+	trackingMouseEvent     bool
+	completing             bool
+	suppressCompletion     bool
+	completionPrefix       string
+	completionIndex        int // -1 if not completing
+	tokensChangedPublisher EventPublisher
+}
+
+// NewTokenEdit creates and initializes a new TokenEdit.
+func NewTokenEdit(parent Container) (*TokenEdit, error) {
+	te := &TokenEdit{hotCloseIndex: -1, completionIndex: -1}
+
+	if err := InitWidget(
+		te,
+		parent,
+		tokenEditWindowClass,
+		win.WS_VISIBLE,
+		win.WS_EX_CONTROLPARENT); err != nil {
+		return nil, err
+	}
+
+	var succeeded bool
+	defer func() {
+		if !succeeded {
+			te.Dispose()
+		}
+	}()
+
+	var err error
+	if te.edit, err = newTokenLineEdit(te); err != nil {
+		return nil, err
+	}
+
+	te.edit.applyFont(te.Font())
+
+	te.edit.TextChanged().Attach(te.onTextChanged)
+
+	te.GraphicsEffects().Add(InteractionEffect)
+	te.GraphicsEffects().Add(FocusEffect)
+
+	te.MustRegisterProperty("Tokens", NewProperty(
+		func() interface{} {
+			return te.Tokens()
+		},
+		func(v interface{}) error {
+			tokens, _ := v.([]string)
+			return te.SetTokens(tokens)
+		},
+		te.tokensChangedPublisher.Event()))
+
+	te.MustRegisterProperty("ReadOnly", NewProperty(
+		func() interface{} {
+			return te.ReadOnly()
+		},
+		func(v interface{}) error {
+			return te.SetReadOnly(v.(bool))
+		},
+		te.edit.readOnlyChangedPublisher.Event()))
+
+	succeeded = true
+
+	return te, nil
+}
+
+func (te *TokenEdit) applyEnabled(enabled bool) {
+	te.WidgetBase.applyEnabled(enabled)
+
+	if te.edit == nil {
+		return
+	}
+
+	te.edit.applyEnabled(enabled)
+
+	te.Invalidate()
+}
+
+func (te *TokenEdit) applyFont(font *Font) {
+	te.WidgetBase.applyFont(font)
+
+	if te.edit == nil {
+		return
+	}
+
+	te.edit.applyFont(font)
+
+	te.updateChips()
+}
+
+// Tokens returns a copy of the tokens of the TokenEdit.
+func (te *TokenEdit) Tokens() []string {
+	return append([]string(nil), te.tokens...)
+}
+
+// SetTokens replaces the tokens of the TokenEdit.
+//
+// Blank entries and duplicates are dropped.
+func (te *TokenEdit) SetTokens(tokens []string) error {
+	var clean []string
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token == "" || indexOfToken(clean, token, te.caseSensitive) > -1 {
+			continue
+		}
+
+		clean = append(clean, token)
+	}
+
+	if len(clean) == len(te.tokens) {
+		equal := true
+		for i, token := range clean {
+			if token != te.tokens[i] {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			return nil
+		}
+	}
+
+	te.tokens = clean
+
+	te.tokensChanged()
+
+	return nil
+}
+
+// HasToken returns whether the TokenEdit contains token.
+func (te *TokenEdit) HasToken(token string) bool {
+	return indexOfToken(te.tokens, strings.TrimSpace(token), te.caseSensitive) > -1
+}
+
+// AddToken appends token to the tokens of the TokenEdit.
+//
+// An error is returned if token is blank or already present.
+func (te *TokenEdit) AddToken(token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return newError("token must not be blank")
+	}
+	if !te.addToken(token) {
+		return newError("duplicate token: " + token)
+	}
+
+	return nil
+}
+
+func (te *TokenEdit) addToken(token string) bool {
+	if indexOfToken(te.tokens, token, te.caseSensitive) > -1 {
+		return false
+	}
+
+	te.tokens = append(te.tokens, token)
+
+	te.tokensChanged()
+
+	return true
+}
+
+// RemoveToken removes token from the tokens of the TokenEdit.
+func (te *TokenEdit) RemoveToken(token string) error {
+	index := indexOfToken(te.tokens, strings.TrimSpace(token), te.caseSensitive)
+	if index == -1 {
+		return newError("token not found: " + token)
+	}
+
+	te.removeTokenAt(index)
+
+	return nil
+}
+
+func (te *TokenEdit) removeTokenAt(index int) {
+	te.tokens = append(te.tokens[:index:index], te.tokens[index+1:]...)
+
+	te.tokensChanged()
+}
+
+// TokensChanged returns the event that is published when tokens were added
+// or removed.
+func (te *TokenEdit) TokensChanged() *Event {
+	return te.tokensChangedPublisher.Event()
+}
+
+func (te *TokenEdit) tokensChanged() {
+	te.hotCloseIndex = -1
+
+	te.updateChips()
+
+	te.tokensChangedPublisher.Publish()
+}
+
+// CaseSensitive returns whether tokens that only differ in case are
+// considered distinct.
+func (te *TokenEdit) CaseSensitive() bool {
+	return te.caseSensitive
+}
+
+// SetCaseSensitive sets whether tokens that only differ in case are
+// considered distinct.
+//
+// Existing tokens are kept, even if they become duplicates.
+func (te *TokenEdit) SetCaseSensitive(caseSensitive bool) {
+	te.caseSensitive = caseSensitive
+}
+
+// Model returns the model the TokenEdit completes typed text from.
+func (te *TokenEdit) Model() ListModel {
+	return te.model
+}
+
+// SetModel sets the model the TokenEdit completes typed text from.
+//
+// The display values of the model items are formatted using fmt.Sprint. Pass
+// nil to disable completion.
+func (te *TokenEdit) SetModel(model ListModel) {
+	te.model = model
+	te.completionIndex = -1
+}
+
+// Text returns the text that was typed, but not yet committed as token.
+func (te *TokenEdit) Text() string {
+	return te.edit.Text()
+}
+
+// CueBanner returns the text that is displayed when no text has been typed.
+func (te *TokenEdit) CueBanner() string {
+	return te.edit.CueBanner()
+}
+
+// SetCueBanner sets the text that is displayed when no text has been typed.
+func (te *TokenEdit) SetCueBanner(value string) error {
+	return te.edit.SetCueBanner(value)
+}
+
+// ReadOnly returns whether the TokenEdit is in read-only mode.
+func (te *TokenEdit) ReadOnly() bool {
+	return te.edit.ReadOnly()
+}
+
+// SetReadOnly sets whether the TokenEdit is in read-only mode.
+//
+// In read-only mode, tokens can neither be added nor removed through the
+// user interface.
+func (te *TokenEdit) SetReadOnly(readOnly bool) error {
+	if readOnly == te.ReadOnly() {
+		return nil
+	}
+
+	if err := te.edit.SetReadOnly(readOnly); err != nil {
+		return err
+	}
+
+	te.updateChips()
+
+	return nil
+}
+
+// SetFocus sets the keyboard input focus to the text field of the TokenEdit.
+func (te *TokenEdit) SetFocus() error {
+	if win.SetFocus(te.edit.hWnd) == 0 {
+		return lastError("SetFocus")
+	}
+
+	return nil
+}
+
+func (te *TokenEdit) SetToolTipText(s string) error {
+	return te.edit.SetToolTipText(s)
+}
+
+// commit turns the typed text into tokens.
+//
+// If the text is a single duplicate, it is kept and selected, so it can be
+// corrected.
+func (te *TokenEdit) commit() {
+	text := te.edit.Text()
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+
+	te.completionIndex = -1
+
+	parts := strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(tokenSeparators, r) || r == '\r' || r == '\n'
+	})
+
+	var added bool
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" && te.addToken(part) {
+			added = true
+		}
+	}
+
+	if !added && len(parts) == 1 {
+		te.edit.SetTextSelection(0, -1)
+		return
+	}
+
+	te.edit.SetText("")
+}
+
+func (te *TokenEdit) onTextChanged() {
+	if te.completing {
+		return
+	}
+
+	if te.suppressCompletion {
+		te.suppressCompletion = false
+		te.completionIndex = -1
+		return
+	}
+
+	text := te.edit.Text()
+	if text == "" || te.model == nil {
+		te.completionIndex = -1
+		return
+	}
+
+	// Only complete if the caret sits at the end of what was typed.
+	n := utf16Len(text)
+	if start, end := te.edit.TextSelection(); start != n || end != n {
+		te.completionIndex = -1
+		return
+	}
+
+	te.complete(text, 0)
+}
+
+// completions returns the model values that start with prefix and are not
+// tokens yet.
+func (te *TokenEdit) completions(prefix string) []string {
+	if te.model == nil {
+		return nil
+	}
+
+	lowerPrefix := strings.ToLower(prefix)
+
+	var matches []string
+	for i, n := 0, te.model.ItemCount(); i < n; i++ {
+		value := fmt.Sprint(te.model.Value(i))
+
+		if !strings.HasPrefix(strings.ToLower(value), lowerPrefix) {
+			continue
+		}
+		if indexOfToken(te.tokens, value, te.caseSensitive) > -1 {
+			continue
+		}
+
+		matches = append(matches, value)
+	}
+
+	return matches
+}
+
+// complete replaces the text by the completion at index for prefix and
+// selects the completed part, so typing on overwrites it.
+func (te *TokenEdit) complete(prefix string, index int) {
+	matches := te.completions(prefix)
+	if len(matches) == 0 {
+		te.completionIndex = -1
+		return
+	}
+
+	index %= len(matches)
+	if index < 0 {
+		index += len(matches)
+	}
+
+	te.completionPrefix = prefix
+	te.completionIndex = index
+
+	te.completing = true
+	te.edit.SetText(matches[index])
+	te.completing = false
+
+	te.edit.SetTextSelection(utf16Len(prefix), -1)
+}
+
+// cycleCompletion moves to the next or previous completion of what was
+// typed.
+func (te *TokenEdit) cycleCompletion(delta int) {
+	if te.completionIndex == -1 {
+		start, _ := te.edit.TextSelection()
+		text := utf16.Encode([]rune(te.edit.Text()))
+		if start > len(text) {
+			start = len(text)
+		}
+
+		te.complete(string(utf16.Decode(text[:start])), 0)
+		return
+	}
+
+	te.complete(te.completionPrefix, te.completionIndex+delta)
+}
+
+// tokenEditMetrics holds the geometry of a TokenEdit in native pixels.
+type tokenEditMetrics struct {
+	padding      int
+	spacing      int
+	rowHeight    int
+	chipPadding  int
+	closeSize    int
+	editMinWidth int
+}
+
+func (te *TokenEdit) metrics() tokenEditMetrics {
+	dpi := te.DPI()
+
+	textHeight := calculateTextSize("X", te.Font(), dpi, 0, te.hWnd).Height
+
+	return tokenEditMetrics{
+		padding:      IntFrom96DPI(3, dpi),
+		spacing:      IntFrom96DPI(3, dpi),
+		rowHeight:    textHeight + IntFrom96DPI(6, dpi),
+		chipPadding:  IntFrom96DPI(6, dpi),
+		closeSize:    IntFrom96DPI(8, dpi),
+		editMinWidth: IntFrom96DPI(60, dpi),
+	}
+}
+
+// layoutTokens flows chips of the specified widths into rows of width and
+// returns their bounds, the bounds of the text field behind them and the
+// total height needed.
+func layoutTokens(chipWidths []int, width int, m tokenEditMetrics) (chips []Rectangle, edit Rectangle, height int) {
+	right := width - m.padding
+	x, y := m.padding, m.padding
+
+	place := func(w int) Rectangle {
+		if x > m.padding && x+w > right {
+			x = m.padding
+			y += m.rowHeight + m.spacing
+		}
+
+		bounds := Rectangle{x, y, w, m.rowHeight}
+
+		x += w + m.spacing
+
+		return bounds
+	}
+
+	chips = make([]Rectangle, len(chipWidths))
+	for i, w := range chipWidths {
+		chips[i] = place(w)
+	}
+
+	edit = place(m.editMinWidth)
+	edit.Width = maxi(m.editMinWidth, right-edit.X)
+
+	return chips, edit, edit.Y + edit.Height + m.padding
+}
+
+// closeBounds returns the bounds of the cross of the chip with bounds chip.
+func (m tokenEditMetrics) closeBounds(chip Rectangle) Rectangle {
+	return Rectangle{
+		chip.X + chip.Width - m.chipPadding - m.closeSize,
+		chip.Y + (chip.Height-m.closeSize)/2,
+		m.closeSize,
+		m.closeSize,
+	}
+}
+
+// updateChips measures the chips and repositions them and the text field.
+func (te *TokenEdit) updateChips() {
+	m := te.metrics()
+
+	te.chipWidths = te.chipWidths[:0]
+	for _, token := range te.tokens {
+		w := calculateTextSize(token, te.Font(), te.DPI(), 0, te.hWnd).Width + 2*m.chipPadding
+		if !te.ReadOnly() {
+			w += m.closeSize + m.chipPadding/2
+		}
+
+		te.chipWidths = append(te.chipWidths, w)
+	}
+
+	te.relayout()
+
+	te.RequestLayout()
+}
+
+func (te *TokenEdit) relayout() {
+	if te.edit == nil {
+		return
+	}
+
+	var edit Rectangle
+	te.chipBounds, edit, _ = layoutTokens(te.chipWidths, te.ClientBoundsPixels().Width, te.metrics())
+
+	te.edit.SetBoundsPixels(edit)
+
+	te.Invalidate()
+}
+
+func (te *TokenEdit) closeIndexAt(x, y int) int {
+	if te.ReadOnly() || !te.Enabled() {
+		return -1
+	}
+
+	m := te.metrics()
+	pad := IntFrom96DPI(2, te.DPI())
+
+	for i, chip := range te.chipBounds {
+		cb := m.closeBounds(chip)
+		if x >= cb.X-pad && x < cb.X+cb.Width+pad && y >= cb.Y-pad && y < cb.Y+cb.Height+pad {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (te *TokenEdit) setHotCloseIndex(index int) {
+	if index == te.hotCloseIndex {
+		return
+	}
+
+	te.hotCloseIndex = index
+
+	te.Invalidate()
+}
+
+func (*TokenEdit) NeedsWmSize() bool {
+	return true
+}
+
+// WndProc is the window procedure of the TokenEdit.
+//
+// When implementing your own WndProc to add or modify behavior, call the
+// WndProc of the embedded TokenEdit for messages you don't handle yourself.
+func (te *TokenEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_COMMAND:
+		if te.edit != nil && win.HWND(lParam) == te.edit.hWnd && win.HIWORD(uint32(wParam)) == win.EN_CHANGE {
+			te.edit.textChangedPublisher.Publish()
+		}
+
+	case win.WM_CTLCOLOREDIT, win.WM_CTLCOLORSTATIC:
+		if hBrush := te.handleWMCTLCOLOR(wParam, lParam); hBrush != 0 {
+			return hBrush
+		}
+
+	case win.WM_ERASEBKGND:
+		return 1
+
+	case win.WM_PAINT:
+		if paintBuffered(hwnd, te.DPI(), te.paint) {
+			return 0
+		}
+
+	case win.WM_WINDOWPOSCHANGED:
+		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
+
+		if wp.Flags&win.SWP_NOSIZE != 0 {
+			break
+		}
+
+		te.relayout()
+
+	case win.WM_MOUSEMOVE:
+		if !te.trackingMouseEvent {
+			var tme win.TRACKMOUSEEVENT
+			tme.CbSize = uint32(unsafe.Sizeof(tme))
+			tme.DwFlags = win.TME_LEAVE
+			tme.HwndTrack = hwnd
+
+			te.trackingMouseEvent = win.TrackMouseEvent(&tme)
+		}
+
+		te.setHotCloseIndex(te.closeIndexAt(int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))))
+
+	case win.WM_MOUSELEAVE:
+		te.trackingMouseEvent = false
+		te.setHotCloseIndex(-1)
+
+	case win.WM_LBUTTONDOWN:
+		if !te.Enabled() {
+			break
+		}
+
+		if index := te.closeIndexAt(int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))); index > -1 {
+			te.removeTokenAt(index)
+		}
+
+		te.SetFocus()
+	}
+
+	return te.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
+func (te *TokenEdit) paint(canvas *Canvas, bounds Rectangle) error {
+	bgColor := Color(win.GetSysColor(win.COLOR_WINDOW))
+	if te.ReadOnly() || !te.Enabled() {
+		bgColor = Color(win.GetSysColor(win.COLOR_BTNFACE))
+	}
+
+	bgBrush, err := NewSolidColorBrush(bgColor)
+	if err != nil {
+		return err
+	}
+	defer bgBrush.Dispose()
+
+	if err := canvas.FillRectanglePixels(bgBrush, bounds); err != nil {
+		return err
+	}
+
+	borderPen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_BTNSHADOW)))
+	if err != nil {
+		return err
+	}
+	defer borderPen.Dispose()
+
+	if err := canvas.DrawRectanglePixels(borderPen, bounds); err != nil {
+		return err
+	}
+
+	if len(te.chipBounds) == 0 {
+		return nil
+	}
+
+	dpi := te.DPI()
+	m := te.metrics()
+
+	chipBrush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_BTNFACE)))
+	if err != nil {
+		return err
+	}
+	defer chipBrush.Dispose()
+
+	textColor := Color(win.GetSysColor(win.COLOR_BTNTEXT))
+	if !te.Enabled() {
+		textColor = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	closePen, err := NewCosmeticPen(PenSolid, Color(win.GetSysColor(win.COLOR_GRAYTEXT)))
+	if err != nil {
+		return err
+	}
+	defer closePen.Dispose()
+
+	hotClosePen, err := NewCosmeticPen(PenSolid, textColor)
+	if err != nil {
+		return err
+	}
+	defer hotClosePen.Dispose()
+
+	corner := SizeFrom96DPI(Size{8, 8}, dpi)
+
+	for i, chip := range te.chipBounds {
+		if i >= len(te.tokens) {
+			break
+		}
+
+		if err := canvas.FillRoundedRectanglePixels(chipBrush, chip, corner); err != nil {
+			return err
+		}
+		if err := canvas.DrawRoundedRectanglePixels(borderPen, chip, corner); err != nil {
+			return err
+		}
+
+		textBounds := Rectangle{chip.X + m.chipPadding, chip.Y, chip.Width - 2*m.chipPadding, chip.Height}
+
+		if !te.ReadOnly() {
+			textBounds.Width -= m.closeSize + m.chipPadding/2
+
+			pen := closePen
+			if i == te.hotCloseIndex {
+				pen = hotClosePen
+			}
+
+			cb := m.closeBounds(chip)
+			if err := canvas.DrawLinePixels(pen, Point{cb.X, cb.Y}, Point{cb.X + cb.Width, cb.Y + cb.Height}); err != nil {
+				return err
+			}
+			if err := canvas.DrawLinePixels(pen, Point{cb.X, cb.Y + cb.Height - 1}, Point{cb.X + cb.Width, cb.Y - 1}); err != nil {
+				return err
+			}
+		}
+
+		if err := canvas.DrawTextPixels(te.tokens[i], te.Font(), textColor, textBounds, TextLeft|TextVCenter|TextSingleLine|TextEndEllipsis); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (te *TokenEdit) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	m := te.metrics()
+
+	return &tokenEditLayoutItem{
+		chipWidths: append([]int(nil), te.chipWidths...),
+		metrics:    m,
+		minWidth:   m.editMinWidth + 2*m.padding,
+	}
+}
+
+type tokenEditLayoutItem struct {
+	LayoutItemBase
+	chipWidths []int // in native pixels
+	metrics    tokenEditMetrics
+	minWidth   int // in native pixels
+}
+
+func (*tokenEditLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | GrowableHorz | GreedyHorz
+}
+
+func (li *tokenEditLayoutItem) IdealSize() Size {
+	width := li.metrics.editMinWidth + 2*li.metrics.padding
+	for _, w := range li.chipWidths {
+		width += w + li.metrics.spacing
+	}
+
+	return Size{width, li.HeightForWidth(width)}
+}
+
+func (li *tokenEditLayoutItem) MinSize() Size {
+	return Size{li.minWidth, li.HeightForWidth(li.minWidth)}
+}
+
+func (*tokenEditLayoutItem) HasHeightForWidth() bool {
+	return true
+}
+
+func (li *tokenEditLayoutItem) HeightForWidth(width int) int {
+	_, _, height := layoutTokens(li.chipWidths, width, li.metrics)
+
+	return height
+}
+
+type tokenLineEdit struct {
+	*LineEdit
+	owner *TokenEdit
+}
+
+func newTokenLineEdit(owner *TokenEdit) (*tokenLineEdit, error) {
+	tle := &tokenLineEdit{owner: owner}
+
+	var err error
+	if tle.LineEdit, err = newLineEdit(owner, 0); err != nil {
+		return nil, err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			tle.Dispose()
+		}
+	}()
+
+	if err := InitWrapperWindow(tle); err != nil {
+		return nil, err
+	}
+
+	succeeded = true
+
+	return tle, nil
+}
+
+func (tle *tokenLineEdit) TextColor() Color {
+	return tle.LineEdit.TextColor()
+}
+
+func (tle *tokenLineEdit) SetTextColor(c Color) {
+	tle.LineEdit.SetTextColor(c)
+}
+
+func (tle *tokenLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	te := tle.owner
+
+	switch msg {
+	case win.WM_GETDLGCODE:
+		// Enter and Tab commit pending text, instead of activating the
+		// default button or moving the focus.
+		if (wParam == win.VK_RETURN || wParam == win.VK_TAB) && !tle.ReadOnly() && tle.Text() != "" {
+			return win.DLGC_WANTALLKEYS
+		}
+
+	case win.WM_CHAR:
+		if tle.ReadOnly() {
+			break
+		}
+
+		switch r := rune(wParam); {
+		case strings.ContainsRune(tokenSeparators, r):
+			te.commit()
+			return 0
+
+		case r == '\r' || r == '\t':
+			return 0
+		}
+
+	case win.WM_KEYDOWN:
+		if tle.ReadOnly() {
+			break
+		}
+
+		switch Key(wParam) {
+		case KeyReturn, KeyTab:
+			te.commit()
+			return 0
+
+		case KeyBack:
+			if start, end := tle.TextSelection(); start == 0 && end == 0 && len(te.tokens) > 0 {
+				te.removeTokenAt(len(te.tokens) - 1)
+				return 0
+			}
+
+			te.suppressCompletion = true
+
+		case KeyDelete:
+			te.suppressCompletion = true
+
+		case KeyDown:
+			te.cycleCompletion(1)
+			return 0
+
+		case KeyUp:
+			te.cycleCompletion(-1)
+			return 0
+		}
+
+	case win.WM_KILLFOCUS:
+		if !tle.ReadOnly() {
+			te.commit()
+		}
+		invalidateWrapperBorderInParent(tle.hWnd)
+
+	case win.WM_SETFOCUS:
+		invalidateWrapperBorderInParent(tle.hWnd)
+	}
+
+	return tle.LineEdit.WndProc(hwnd, msg, wParam, lParam)
+}
+
+// indexOfToken returns the index of token in tokens or -1 if not found.
+func indexOfToken(tokens []string, token string, caseSensitive bool) int {
+	for i, t := range tokens {
+		if t == token || !caseSensitive && strings.EqualFold(t, token) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// utf16Len returns the length of s in UTF-16 code units, which is what edit
+// controls count selections in.
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
@@ -27,10 +27,14 @@ const (
 type ToolBar struct {
 	WidgetBase
 	imageList          *ImageList
+	disabledImageList  *ImageList
+	disabledImageCount int
 	actions            *ActionList
 	defaultButtonWidth int
 	maxTextRows        int
 	buttonStyle        ToolBarButtonStyle
+	wrapping           bool
+	columns            int
 }
 
 func NewToolBarWithOrientationAndButtonStyle(parent Container, orientation Orientation, buttonStyle ToolBarButtonStyle) (*ToolBar, error) {
@@ -47,6 +51,7 @@ func NewToolBarWithOrientationAndButtonStyle(parent Container, orientation Orien
 
 	tb := &ToolBar{
 		buttonStyle: buttonStyle,
+		columns:     1,
 	}
 	tb.actions = newActionList(tb)
 
@@ -87,6 +92,8 @@ func (tb *ToolBar) Dispose() {
 		tb.imageList.Dispose()
 		tb.imageList = nil
 	}
+
+	tb.disposeDisabledImageList()
 }
 
 func (tb *ToolBar) applyFont(font *Font) {
@@ -100,6 +107,25 @@ func (tb *ToolBar) applyFont(font *Font) {
 func (tb *ToolBar) ApplyDPI(dpi int) {
 	tb.WidgetBase.ApplyDPI(dpi)
 
+	tb.resetImageLists(dpi)
+
+	tb.hFont = tb.Font().handleForDPI(tb.DPI())
+	setWindowFont(tb.hWnd, tb.hFont)
+}
+
+func (tb *ToolBar) ApplySysColors() {
+	tb.WidgetBase.ApplySysColors()
+
+	// The disabled variants of the images differ between dark and light
+	// themes.
+	if tb.disabledImageList != nil {
+		tb.resetImageLists(tb.DPI())
+	}
+}
+
+// resetImageLists replaces the image lists with new ones for dpi and adds the
+// images of the actions again.
+func (tb *ToolBar) resetImageLists(dpi int) {
 	var maskColor Color
 	var size Size
 	if tb.imageList != nil {
@@ -122,14 +148,14 @@ func (tb *ToolBar) ApplyDPI(dpi int) {
 
 	tb.imageList = iml
 
+	tb.disposeDisabledImageList()
+	tb.createDisabledImageList()
+
 	for _, action := range tb.actions.actions {
-		if action.image != nil {
+		if action.displayImage() != nil {
 			tb.onActionChanged(action)
 		}
 	}
-
-	tb.hFont = tb.Font().handleForDPI(tb.DPI())
-	setWindowFont(tb.hWnd, tb.hFont)
 }
 
 func (tb *ToolBar) Orientation() Orientation {
@@ -146,6 +172,108 @@ func (tb *ToolBar) ButtonStyle() ToolBarButtonStyle {
 	return tb.buttonStyle
 }
 
+// Wrapping returns whether the buttons of a horizontal ToolBar wrap into
+// multiple rows when it is narrower than its buttons.
+func (tb *ToolBar) Wrapping() bool {
+	return tb.wrapping
+}
+
+// SetWrapping sets whether the buttons of a horizontal ToolBar wrap into
+// multiple rows when it is narrower than its buttons. A wrapping ToolBar can
+// shrink to the width of its widest button and grows in height as needed.
+func (tb *ToolBar) SetWrapping(wrapping bool) error {
+	if wrapping == tb.wrapping {
+		return nil
+	}
+
+	if wrapping && tb.Orientation() == Vertical {
+		return newError("only horizontal ToolBars can wrap, use SetColumns instead")
+	}
+
+	tb.wrapping = wrapping
+
+	tb.RequestLayout()
+
+	return nil
+}
+
+// Columns returns the number of buttons in each row of a vertical ToolBar.
+func (tb *ToolBar) Columns() int {
+	return tb.columns
+}
+
+// SetColumns sets the number of buttons in each row of a vertical ToolBar,
+// e.g. to build a tool palette with two columns of image only buttons. A
+// separator always starts a new row. The default is 1.
+func (tb *ToolBar) SetColumns(columns int) error {
+	if columns == tb.columns {
+		return nil
+	}
+
+	if columns < 1 {
+		return newError("columns must be >= 1")
+	}
+	if tb.Orientation() != Vertical {
+		return newError("only vertical ToolBars have columns")
+	}
+
+	tb.columns = columns
+
+	tb.updateRowWraps()
+
+	return nil
+}
+
+// updateRowWraps sets TBSTATE_WRAP on the last button of each row of a
+// vertical ToolBar, and on separators, which occupy a row of their own.
+func (tb *ToolBar) updateRowWraps() {
+	if tb.Orientation() != Vertical {
+		return
+	}
+
+	count := int(tb.SendMessage(win.TB_BUTTONCOUNT, 0, 0))
+
+	var column int
+	for i := 0; i < count; i++ {
+		var tbb win.TBBUTTON
+		if win.FALSE == tb.SendMessage(win.TB_GETBUTTON, uintptr(i), uintptr(unsafe.Pointer(&tbb))) {
+			continue
+		}
+
+		var wrap bool
+		if tbb.FsStyle&win.BTNS_SEP != 0 {
+			wrap = true
+			column = 0
+		} else {
+			column++
+
+			wrap = column == tb.columns || i == count-1
+			if !wrap && i+1 < count {
+				var next win.TBBUTTON
+				if win.FALSE != tb.SendMessage(win.TB_GETBUTTON, uintptr(i+1), uintptr(unsafe.Pointer(&next))) {
+					wrap = next.FsStyle&win.BTNS_SEP != 0
+				}
+			}
+			if wrap {
+				column = 0
+			}
+		}
+
+		state := tbb.FsState &^ win.TBSTATE_WRAP
+		if wrap {
+			state |= win.TBSTATE_WRAP
+		}
+
+		if state != tbb.FsState {
+			tb.SendMessage(win.TB_SETSTATE, uintptr(tbb.IdCommand), uintptr(win.MAKELONG(uint16(state), 0)))
+		}
+	}
+
+	tb.SendMessage(win.TB_AUTOSIZE, 0, 0)
+
+	tb.RequestLayout()
+}
+
 func (tb *ToolBar) applyDefaultButtonWidth() error {
 	if tb.defaultButtonWidth == 0 {
 		return nil
@@ -229,7 +357,12 @@ func (tb *ToolBar) ImageList() *ImageList {
 	return tb.imageList
 }
 
+// SetImageList sets the ImageList of the ToolBar. The ToolBar draws the images
+// of disabled buttons from it as the control does by default, unlike with its
+// own ImageList, for which it generates faded grayscale variants.
 func (tb *ToolBar) SetImageList(value *ImageList) {
+	tb.disposeDisabledImageList()
+
 	var hIml win.HIMAGELIST
 
 	if tb.buttonStyle != ToolBarButtonTextOnly && value != nil {
@@ -250,6 +383,7 @@ func (tb *ToolBar) imageIndex(image Image) (imageIndex int32, err error) {
 		}
 
 		tb.SetImageList(iml)
+		tb.createDisabledImageList()
 	}
 
 	imageIndex = -1
@@ -257,11 +391,65 @@ func (tb *ToolBar) imageIndex(image Image) (imageIndex int32, err error) {
 		if imageIndex, err = tb.imageList.AddImage(image); err != nil {
 			return
 		}
+
+		if tb.disabledImageList != nil && int(imageIndex) == tb.disabledImageCount {
+			tb.addDisabledImage(image)
+		}
 	}
 
 	return
 }
 
+// createDisabledImageList creates the ImageList for the images of disabled
+// buttons, which has a faded grayscale variant at the index of each image of
+// the ImageList of the ToolBar.
+func (tb *ToolBar) createDisabledImageList() {
+	if tb.buttonStyle == ToolBarButtonTextOnly || tb.imageList == nil {
+		return
+	}
+
+	size := SizeFrom96DPI(tb.imageList.imageSize96dpi, tb.imageList.dpi)
+
+	iml, err := NewImageListForDPI(size, 0, tb.imageList.dpi)
+	if err != nil {
+		return
+	}
+
+	tb.SendMessage(win.TB_SETDISABLEDIMAGELIST, 0, uintptr(iml.hIml))
+
+	tb.disabledImageList = iml
+	tb.disabledImageCount = 0
+}
+
+func (tb *ToolBar) disposeDisabledImageList() {
+	if tb.disabledImageList == nil {
+		return
+	}
+
+	if tb.hWnd != 0 {
+		tb.SendMessage(win.TB_SETDISABLEDIMAGELIST, 0, 0)
+	}
+
+	tb.disabledImageList.Dispose()
+	tb.disabledImageList = nil
+}
+
+// addDisabledImage adds the disabled variant of image, which was just added
+// to the ImageList of the ToolBar, to the disabled ImageList. If that fails,
+// the disabled ImageList is dropped, so the indices can't get out of step.
+func (tb *ToolBar) addDisabledImage(image Image) {
+	size := SizeFrom96DPI(tb.disabledImageList.imageSize96dpi, tb.disabledImageList.dpi)
+
+	if bmp, err := iconCache.disabledBitmap(image, size); err == nil {
+		if win.ImageList_Add(tb.disabledImageList.hIml, bmp.hBmp, 0) != -1 {
+			tb.disabledImageCount++
+			return
+		}
+	}
+
+	tb.disposeDisabledImageList()
+}
+
 func (tb *ToolBar) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	switch msg {
 	case win.WM_MOUSEMOVE, win.WM_MOUSELEAVE, win.WM_LBUTTONDOWN:
@@ -325,7 +513,9 @@ func (tb *ToolBar) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) ui
 
 func (tb *ToolBar) initButtonForAction(action *Action, state, style *byte, image *int32, text *uintptr) (err error) {
 	if tb.hasStyleBits(win.CCS_VERT) {
-		*state |= win.TBSTATE_WRAP
+		if tb.columns <= 1 {
+			*state |= win.TBSTATE_WRAP
+		}
 	} else if tb.defaultButtonWidth == 0 {
 		*style |= win.BTNS_AUTOSIZE
 	}
@@ -363,7 +553,7 @@ func (tb *ToolBar) initButtonForAction(action *Action, state, style *byte, image
 	}
 
 	if tb.buttonStyle != ToolBarButtonTextOnly {
-		if *image, err = tb.imageIndex(action.image); err != nil {
+		if *image, err = tb.imageIndex(action.displayImage()); err != nil {
 			return err
 		}
 	}
@@ -410,6 +600,10 @@ func (tb *ToolBar) onActionChanged(action *Action) error {
 		return newError("SendMessage(TB_SETBUTTONINFO) failed")
 	}
 
+	if tb.columns > 1 {
+		tb.updateRowWraps()
+	}
+
 	tb.RequestLayout()
 
 	return nil
@@ -469,6 +663,10 @@ func (tb *ToolBar) insertAction(action *Action, visibleChanged bool) (err error)
 		return
 	}
 
+	if tb.columns > 1 {
+		tb.updateRowWraps()
+	}
+
 	tb.SendMessage(win.TB_AUTOSIZE, 0, 0)
 
 	tb.RequestLayout()
@@ -487,6 +685,10 @@ func (tb *ToolBar) removeAction(action *Action, visibleChanged bool) error {
 		return newError("SendMessage(TB_DELETEBUTTON) failed")
 	}
 
+	if tb.columns > 1 {
+		tb.updateRowWraps()
+	}
+
 	tb.RequestLayout()
 
 	return nil
@@ -545,12 +747,47 @@ func (tb *ToolBar) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 		}
 	}
 
+	if tb.Orientation() == Vertical && tb.columns > 1 {
+		for _, r := range tb.itemRects() {
+			width = maxi(width, int(r.Right))
+		}
+	}
+
+	if tb.wrapping && tb.Orientation() == Horizontal {
+		var itemWidths []int
+		for _, r := range tb.itemRects() {
+			itemWidths = append(itemWidths, int(r.Right-r.Left))
+		}
+
+		return &wrappingToolBarLayoutItem{
+			idealSize:    Size{width, height},
+			itemWidths:   itemWidths,
+			buttonHeight: int(win.HIWORD(buttonSize)),
+		}
+	}
+
 	return &toolBarLayoutItem{
 		layoutFlags: layoutFlags,
 		idealSize:   Size{width, height},
 	}
 }
 
+// itemRects returns the bounds of the buttons and separators in native
+// pixels.
+func (tb *ToolBar) itemRects() []win.RECT {
+	count := int(tb.SendMessage(win.TB_BUTTONCOUNT, 0, 0))
+
+	rects := make([]win.RECT, 0, count)
+	for i := 0; i < count; i++ {
+		var r win.RECT
+		if win.FALSE != tb.SendMessage(win.TB_GETITEMRECT, uintptr(i), uintptr(unsafe.Pointer(&r))) {
+			rects = append(rects, r)
+		}
+	}
+
+	return rects
+}
+
 type toolBarLayoutItem struct {
 	LayoutItemBase
 	layoutFlags LayoutFlags
@@ -568,3 +805,52 @@ func (li *toolBarLayoutItem) IdealSize() Size {
 func (li *toolBarLayoutItem) MinSize() Size {
 	return li.idealSize
 }
+
+type wrappingToolBarLayoutItem struct {
+	LayoutItemBase
+	idealSize    Size  // in native pixels
+	itemWidths   []int // in native pixels
+	buttonHeight int   // in native pixels
+}
+
+func (*wrappingToolBarLayoutItem) LayoutFlags() LayoutFlags {
+	return ShrinkableHorz | GrowableHorz
+}
+
+func (li *wrappingToolBarLayoutItem) IdealSize() Size {
+	return li.idealSize
+}
+
+func (li *wrappingToolBarLayoutItem) MinSize() Size {
+	var width int
+	for _, w := range li.itemWidths {
+		width = maxi(width, w)
+	}
+
+	return Size{width, li.HeightForWidth(width)}
+}
+
+func (*wrappingToolBarLayoutItem) HasHeightForWidth() bool {
+	return true
+}
+
+// HeightForWidth returns the height of the rows the buttons wrap into, like
+// the tool bar control does with TBSTYLE_WRAPABLE.
+func (li *wrappingToolBarLayoutItem) HeightForWidth(width int) int {
+	if len(li.itemWidths) == 0 {
+		return li.idealSize.Height
+	}
+
+	rows := 1
+	var x int
+	for _, w := range li.itemWidths {
+		if x > 0 && x+w > width {
+			rows++
+			x = 0
+		}
+
+		x += w
+	}
+
+	return maxi(li.idealSize.Height, rows*li.buttonHeight)
+}
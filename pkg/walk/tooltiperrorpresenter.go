@@ -11,7 +11,9 @@ import (
 	"github.com/miu200521358/win"
 )
 
-var ValidationErrorEffect WidgetGraphicsEffect
+// ValidationErrorEffect marks widgets with invalid input. By default it is a
+// red ErrorAdornerEffect.
+var ValidationErrorEffect WidgetGraphicsEffect = &ErrorAdornerEffect{color: RGB(232, 17, 35)}
 
 type ToolTipErrorPresenter struct {
 	toolTip                     *ToolTip
@@ -0,0 +1,98 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+type treeItemRangeEventHandlerInfo struct {
+	handler TreeItemRangeEventHandler
+	once    bool
+}
+
+type TreeItemRangeEventHandler func(parent TreeItem, from, to int)
+
+type TreeItemRangeEvent struct {
+	handlers   []*treeItemRangeEventHandlerInfo
+	publishing int // Number of Publish calls in progress
+}
+
+func (e *TreeItemRangeEvent) Attach(handler TreeItemRangeEventHandler) int {
+	handlerInfo := &treeItemRangeEventHandlerInfo{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*treeItemRangeEventHandlerInfo(nil), e.handlers...)
+	}
+
+	for i, h := range e.handlers {
+		if h.handler == nil {
+			e.handlers[i] = handlerInfo
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handlerInfo)
+
+	return len(e.handlers) - 1
+}
+
+func (e *TreeItemRangeEvent) Detach(handle int) {
+	e.handlers[handle].handler = nil
+}
+
+func (e *TreeItemRangeEvent) Once(handler TreeItemRangeEventHandler) {
+	i := e.Attach(handler)
+	e.handlers[i].once = true
+}
+
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *TreeItemRangeEvent) Connect(handler TreeItemRangeEventHandler) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *TreeItemRangeEvent) AttachOwned(owner Window, handler TreeItemRangeEventHandler) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
+type TreeItemRangeEventPublisher struct {
+	event TreeItemRangeEvent
+}
+
+func (p *TreeItemRangeEventPublisher) Event() *TreeItemRangeEvent {
+	return &p.event
+}
+
+func (p *TreeItemRangeEventPublisher) Publish(parent TreeItem, from, to int) {
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
+
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
+			if h.once {
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
+			}
+
+			handler(parent, from, to)
+		}
+	}
+}
+
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+func (p *TreeItemRangeEventPublisher) PublishAsync(parent TreeItem, from, to int) {
+	publishAsync(func() {
+		p.Publish(parent, from, to)
+	})
+}
@@ -19,28 +19,39 @@ type treeViewItemInfo struct {
 	child2Handle map[TreeItem]win.HTREEITEM
 }
 
+const (
+	tvgnRoot  = 0x0000
+	tvgnNext  = 0x0001
+	tvgnChild = 0x0004
+)
+
 type TreeView struct {
 	WidgetBase
-	model                          TreeModel
-	lazyPopulation                 bool
-	itemsResetEventHandlerHandle   int
-	itemChangedEventHandlerHandle  int
-	itemInsertedEventHandlerHandle int
-	itemRemovedEventHandlerHandle  int
-	item2Info                      map[TreeItem]*treeViewItemInfo
-	handle2Item                    map[win.HTREEITEM]TreeItem
-	currItem                       TreeItem
-	hIml                           win.HIMAGELIST
-	usingSysIml                    bool
-	imageUintptr2Index             map[uintptr]int32
-	filePath2IconIndex             map[string]int32
-	expandedChangedPublisher       TreeItemEventPublisher
-	currentItemChangedPublisher    EventPublisher
-	itemActivatedPublisher         EventPublisher
+	model                           TreeModel
+	lazyPopulation                  bool
+	itemsResetEventHandlerHandle    int
+	itemChangedEventHandlerHandle   int
+	itemInsertedEventHandlerHandle  int
+	itemRemovedEventHandlerHandle   int
+	itemsInsertedEventHandlerHandle int
+	itemsRemovedEventHandlerHandle  int
+	updateBurstSuspender            *updateBurstSuspender
+	item2Info                       map[TreeItem]*treeViewItemInfo
+	handle2Item                     map[win.HTREEITEM]TreeItem
+	currItem                        TreeItem
+	hIml                            win.HIMAGELIST
+	usingSysIml                     bool
+	imageUintptr2Index              map[uintptr]int32
+	filePath2IconIndex              map[string]int32
+	itemHeight96dpi                 int
+	expandedChangedPublisher        TreeItemEventPublisher
+	currentItemChangedPublisher     EventPublisher
+	itemActivatedPublisher          EventPublisher
 }
 
 func NewTreeView(parent Container) (*TreeView, error) {
 	tv := new(TreeView)
+	tv.updateBurstSuspender = newUpdateBurstSuspender(tv, tv.SetSuspended)
 
 	if err := InitWidget(
 		tv,
@@ -66,6 +77,10 @@ func NewTreeView(parent Container) (*TreeView, error) {
 		return nil, err
 	}
 
+	if appTheme != nil {
+		tv.ApplySysColors()
+	}
+
 	tv.GraphicsEffects().Add(InteractionEffect)
 	tv.GraphicsEffects().Add(FocusEffect)
 
@@ -109,7 +124,12 @@ func (tv *TreeView) Dispose() {
 func (tv *TreeView) SetBackground(bg Brush) {
 	tv.WidgetBase.SetBackground(bg)
 
-	color := Color(win.GetSysColor(win.COLOR_WINDOW))
+	tv.applyBackgroundColor()
+}
+
+func (tv *TreeView) applyBackgroundColor() {
+	bg := tv.Background()
+	color := themeSysColor(win.COLOR_WINDOW)
 
 	if bg != nil {
 		type Colorer interface {
@@ -124,6 +144,20 @@ func (tv *TreeView) SetBackground(bg Brush) {
 	tv.SendMessage(win.TVM_SETBKCOLOR, 0, uintptr(color))
 }
 
+func (tv *TreeView) ApplySysColors() {
+	tv.WidgetBase.ApplySysColors()
+
+	// -1 reverts to the system color.
+	textColor := ^uintptr(0)
+	if appTheme != nil {
+		textColor = uintptr(appTheme.Color(ThemeRoleControlText))
+	}
+
+	tv.SendMessage(win.TVM_SETTEXTCOLOR, 0, textColor)
+
+	tv.applyBackgroundColor()
+}
+
 func (tv *TreeView) Model() TreeModel {
 	return tv.model
 }
@@ -135,6 +169,11 @@ func (tv *TreeView) SetModel(model TreeModel) error {
 		tv.model.ItemInserted().Detach(tv.itemInsertedEventHandlerHandle)
 		tv.model.ItemRemoved().Detach(tv.itemRemovedEventHandlerHandle)
 
+		if bm, ok := tv.model.(BatchTreeModel); ok {
+			bm.ItemsInserted().Detach(tv.itemsInsertedEventHandlerHandle)
+			bm.ItemsRemoved().Detach(tv.itemsRemovedEventHandlerHandle)
+		}
+
 		tv.disposeImageListAndCaches()
 	}
 
@@ -144,23 +183,24 @@ func (tv *TreeView) SetModel(model TreeModel) error {
 		tv.lazyPopulation = model.LazyPopulation()
 
 		tv.itemsResetEventHandlerHandle = model.ItemsReset().Attach(func(parent TreeItem) {
+			tv.updateBurstSuspender.notify()
+
 			if parent == nil {
 				tv.resetItems()
 			} else if tv.item2Info[parent] != nil {
-				tv.SetSuspended(true)
-				defer tv.SetSuspended(false)
-
-				if err := tv.removeDescendants(parent); err != nil {
-					return
-				}
+				SuspendUpdates(tv, func() {
+					if err := tv.removeDescendants(parent); err != nil {
+						return
+					}
 
-				if err := tv.insertChildren(parent); err != nil {
-					return
-				}
+					tv.insertChildren(parent)
+				})
 			}
 		})
 
 		tv.itemChangedEventHandlerHandle = model.ItemChanged().Attach(func(item TreeItem) {
+			tv.updateBurstSuspender.notify()
+
 			if item == nil || tv.item2Info[item] == nil {
 				return
 			}
@@ -171,8 +211,7 @@ func (tv *TreeView) SetModel(model TreeModel) error {
 		})
 
 		tv.itemInsertedEventHandlerHandle = model.ItemInserted().Attach(func(item TreeItem) {
-			tv.SetSuspended(true)
-			defer tv.SetSuspended(false)
+			tv.updateBurstSuspender.notify()
 
 			var hInsertAfter win.HTREEITEM
 			parent := item.Parent()
@@ -186,16 +225,32 @@ func (tv *TreeView) SetModel(model TreeModel) error {
 				}
 			}
 
-			if _, err := tv.insertItemAfter(item, hInsertAfter); err != nil {
-				return
-			}
+			SuspendUpdates(tv, func() {
+				tv.insertItemAfter(item, hInsertAfter)
+			})
 		})
 
 		tv.itemRemovedEventHandlerHandle = model.ItemRemoved().Attach(func(item TreeItem) {
+			tv.updateBurstSuspender.notify()
+
 			if err := tv.removeItem(item); err != nil {
 				return
 			}
 		})
+
+		if bm, ok := model.(BatchTreeModel); ok {
+			tv.itemsInsertedEventHandlerHandle = bm.ItemsInserted().Attach(func(parent TreeItem, from, to int) {
+				tv.updateBurstSuspender.notify()
+
+				tv.insertItems(parent, from, to)
+			})
+
+			tv.itemsRemovedEventHandlerHandle = bm.ItemsRemoved().Attach(func(parent TreeItem, from, to int) {
+				tv.updateBurstSuspender.notify()
+
+				tv.removeItems(parent, from, to)
+			})
+		}
 	}
 
 	return tv.resetItems()
@@ -272,13 +327,15 @@ func (tv *TreeView) ItemHeight() int {
 }
 
 // SetItemHeight sets the height of the tree-view items in native pixels.
+// The height is rescaled when the DPI changes.
 func (tv *TreeView) SetItemHeight(height int) {
+	tv.itemHeight96dpi = tv.IntTo96DPI(height)
+
 	tv.SendMessage(win.TVM_SETITEMHEIGHT, uintptr(height), 0)
 }
 
 func (tv *TreeView) resetItems() error {
-	tv.SetSuspended(true)
-	defer tv.SetSuspended(false)
+	defer suspendUpdates(tv)()
 
 	if err := tv.clearItems(); err != nil {
 		return err
@@ -319,6 +376,10 @@ func (tv *TreeView) insertRoots() error {
 func (tv *TreeView) ApplyDPI(dpi int) {
 	tv.WidgetBase.ApplyDPI(dpi)
 
+	if tv.itemHeight96dpi > 0 {
+		tv.SendMessage(win.TVM_SETITEMHEIGHT, uintptr(IntFrom96DPI(tv.itemHeight96dpi, dpi)), 0)
+	}
+
 	tv.disposeImageListAndCaches()
 }
 
@@ -423,6 +484,87 @@ func (tv *TreeView) insertChildren(parent TreeItem) error {
 	return nil
 }
 
+// insertItems inserts the children of parent, or the roots if parent is nil,
+// at the indexes from to to.
+func (tv *TreeView) insertItems(parent TreeItem, from, to int) error {
+	childAt := tv.model.RootAt
+
+	var info *treeViewItemInfo
+	if parent != nil {
+		if info = tv.item2Info[parent]; info == nil {
+			// The items will be inserted along with parent.
+			return nil
+		}
+
+		if tv.lazyPopulation && len(info.child2Handle) == 0 && !tv.Expanded(parent) {
+			// The items will be inserted when parent is expanded.
+			return nil
+		}
+
+		childAt = parent.ChildAt
+	}
+
+	hInsertAfter := win.TVI_FIRST
+	if from > 0 {
+		prevInfo := tv.item2Info[childAt(from-1)]
+		if prevInfo == nil {
+			return newError("invalid item")
+		}
+		hInsertAfter = prevInfo.handle
+	}
+
+	defer suspendUpdates(tv)()
+
+	for i := from; i <= to; i++ {
+		item := childAt(i)
+
+		handle, err := tv.insertItemAfter(item, hInsertAfter)
+		if err != nil {
+			return err
+		}
+
+		if info != nil {
+			info.child2Handle[item] = handle
+		}
+
+		hInsertAfter = handle
+	}
+
+	return nil
+}
+
+// removeItems removes the children of parent, or the roots if parent is nil,
+// that were at the indexes from to to.
+func (tv *TreeView) removeItems(parent TreeItem, from, to int) error {
+	var hItem win.HTREEITEM
+	if parent == nil {
+		hItem = win.HTREEITEM(tv.SendMessage(win.TVM_GETNEXTITEM, tvgnRoot, 0))
+	} else if info := tv.item2Info[parent]; info != nil {
+		hItem = win.HTREEITEM(tv.SendMessage(win.TVM_GETNEXTITEM, tvgnChild, uintptr(info.handle)))
+	}
+
+	// The model no longer knows the items, so they are looked up in the order
+	// of the tree view.
+	var items []TreeItem
+	for i := 0; hItem != 0 && i <= to; i++ {
+		if i >= from {
+			items = append(items, tv.handle2Item[hItem])
+		}
+
+		hItem = win.HTREEITEM(tv.SendMessage(win.TVM_GETNEXTITEM, tvgnNext, uintptr(hItem)))
+	}
+
+	defer suspendUpdates(tv)()
+
+	for _, item := range items {
+		if err := tv.removeItem(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (tv *TreeView) updateItem(item TreeItem) error {
 	tvi := &win.TVITEM{
 		Mask:    win.TVIF_TEXT,
@@ -477,8 +619,7 @@ func (tv *TreeView) ensureItemAndAncestorsInserted(item TreeItem) error {
 		return newError("invalid item")
 	}
 
-	tv.SetSuspended(true)
-	defer tv.SetSuspended(false)
+	defer suspendUpdates(tv)()
 
 	var hierarchy []TreeItem
 
@@ -0,0 +1,168 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"time"
+)
+
+// The events with a single argument are instantiations of TypedEvent.
+type (
+	CalendarViewSelectionEventHandler   = TypedEventHandler[[]time.Time]
+	CalendarViewSelectionEvent          = TypedEvent[[]time.Time]
+	CalendarViewSelectionEventPublisher = TypedEventPublisher[[]time.Time]
+
+	DragEventHandler   = TypedEventHandler[*DragEventArgs]
+	DragEvent          = TypedEvent[*DragEventArgs]
+	DragEventPublisher = TypedEventPublisher[*DragEventArgs]
+
+	ErrorEventHandler   = TypedEventHandler[error]
+	ErrorEvent          = TypedEvent[error]
+	ErrorEventPublisher = TypedEventPublisher[error]
+
+	IntEventHandler   = TypedEventHandler[int]
+	IntEvent          = TypedEvent[int]
+	IntEventPublisher = TypedEventPublisher[int]
+
+	KeyEventHandler   = TypedEventHandler[Key]
+	KeyEvent          = TypedEvent[Key]
+	KeyEventPublisher = TypedEventPublisher[Key]
+
+	LinkLabelLinkEventHandler   = TypedEventHandler[*LinkLabelLink]
+	LinkLabelLinkEvent          = TypedEvent[*LinkLabelLink]
+	LinkLabelLinkEventPublisher = TypedEventPublisher[*LinkLabelLink]
+
+	NodeGraphConnectionEventHandler   = TypedEventHandler[*NodeGraphConnection]
+	NodeGraphConnectionEvent          = TypedEvent[*NodeGraphConnection]
+	NodeGraphConnectionEventPublisher = TypedEventPublisher[*NodeGraphConnection]
+
+	StringEventHandler   = TypedEventHandler[string]
+	StringEvent          = TypedEvent[string]
+	StringEventPublisher = TypedEventPublisher[string]
+
+	TreeItemEventHandler   = TypedEventHandler[TreeItem]
+	TreeItemEvent          = TypedEvent[TreeItem]
+	TreeItemEventPublisher = TypedEventPublisher[TreeItem]
+
+	ValueChangedEventHandler   = TypedEventHandler[ValueChange]
+	ValueChangedEvent          = TypedEvent[ValueChange]
+	ValueChangedEventPublisher = TypedEventPublisher[ValueChange]
+
+	WebViewNavigatingEventHandler   = TypedEventHandler[*WebViewNavigatingEventData]
+	WebViewNavigatingEvent          = TypedEvent[*WebViewNavigatingEventData]
+	WebViewNavigatingEventPublisher = TypedEventPublisher[*WebViewNavigatingEventData]
+
+	WebViewNavigatedErrorEventHandler   = TypedEventHandler[*WebViewNavigatedErrorEventData]
+	WebViewNavigatedErrorEvent          = TypedEvent[*WebViewNavigatedErrorEventData]
+	WebViewNavigatedErrorEventPublisher = TypedEventPublisher[*WebViewNavigatedErrorEventData]
+
+	WebViewNewWindowEventHandler   = TypedEventHandler[*WebViewNewWindowEventData]
+	WebViewNewWindowEvent          = TypedEvent[*WebViewNewWindowEventData]
+	WebViewNewWindowEventPublisher = TypedEventPublisher[*WebViewNewWindowEventData]
+
+	WebViewWindowClosingEventHandler   = TypedEventHandler[*WebViewWindowClosingEventData]
+	WebViewWindowClosingEvent          = TypedEvent[*WebViewWindowClosingEventData]
+	WebViewWindowClosingEventPublisher = TypedEventPublisher[*WebViewWindowClosingEventData]
+)
+
+type typedEventHandlerInfo[T any] struct {
+	handler TypedEventHandler[T]
+	once    bool
+}
+
+// TypedEventHandler is the handler of a TypedEvent, called with the argument
+// of the event.
+type TypedEventHandler[T any] func(arg T)
+
+// TypedEvent is an event whose handlers are called with an argument of type T,
+// so custom widgets can define strongly typed events without writing their
+// own event type. Use a struct type for T to pass several values.
+type TypedEvent[T any] struct {
+	handlers   []*typedEventHandlerInfo[T]
+	publishing int // Number of Publish calls in progress
+}
+
+func (e *TypedEvent[T]) Attach(handler TypedEventHandler[T]) int {
+	handlerInfo := &typedEventHandlerInfo[T]{handler, false}
+
+	if e.publishing > 0 {
+		// Publish keeps iterating the handlers as they were before.
+		e.handlers = append([]*typedEventHandlerInfo[T](nil), e.handlers...)
+	}
+
+	for i, h := range e.handlers {
+		if h.handler == nil {
+			e.handlers[i] = handlerInfo
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handlerInfo)
+
+	return len(e.handlers) - 1
+}
+
+func (e *TypedEvent[T]) Detach(handle int) {
+	e.handlers[handle].handler = nil
+}
+
+func (e *TypedEvent[T]) Once(handler TypedEventHandler[T]) {
+	i := e.Attach(handler)
+	e.handlers[i].once = true
+}
+
+// Connect attaches handler like Attach, but returns a Connection to detach it.
+func (e *TypedEvent[T]) Connect(handler TypedEventHandler[T]) *Connection {
+	handle := e.Attach(handler)
+
+	return &Connection{detach: func() {
+		e.Detach(handle)
+	}}
+}
+
+// AttachOwned attaches handler until owner is disposed.
+func (e *TypedEvent[T]) AttachOwned(owner Window, handler TypedEventHandler[T]) *Connection {
+	return e.Connect(handler).BindTo(owner)
+}
+
+// TypedEventPublisher publishes a TypedEvent. Keep it in an unexported field
+// of the widget and return its Event from an exported method.
+type TypedEventPublisher[T any] struct {
+	event TypedEvent[T]
+}
+
+func (p *TypedEventPublisher[T]) Event() *TypedEvent[T] {
+	return &p.event
+}
+
+func (p *TypedEventPublisher[T]) Publish(arg T) {
+	p.event.publishing++
+	defer func() {
+		p.event.publishing--
+	}()
+
+	for _, h := range p.event.handlers {
+		if handler := h.handler; handler != nil {
+			if h.once {
+				// Detached first, so publishing again from handler doesn't
+				// call it twice.
+				h.handler = nil
+			}
+
+			handler(arg)
+		}
+	}
+}
+
+// PublishAsync publishes like Publish, but only after the message loop of the
+// calling thread finished processing the current message.
+func (p *TypedEventPublisher[T]) PublishAsync(arg T) {
+	publishAsync(func() {
+		p.Publish(arg)
+	})
+}
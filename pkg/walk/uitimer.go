@@ -0,0 +1,320 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/miu200521358/win"
+)
+
+const (
+	// uiTimerMinInterval is USER_TIMER_MINIMUM, the shortest time SetTimer
+	// waits.
+	uiTimerMinInterval = 10 * time.Millisecond
+
+	// uiTimerEarlyTolerance is how much earlier than due a tick is accepted,
+	// as WM_TIMER may arrive slightly early due to the timer resolution.
+	uiTimerEarlyTolerance = 2 * time.Millisecond
+)
+
+var (
+	setCoalescableTimer = syscall.NewLazyDLL("user32.dll").NewProc("SetCoalescableTimer")
+	uiTimerProcPtr      uintptr
+
+	uiTimersMutex sync.Mutex
+	uiTimers      = make(map[uintptr]*UITimer)
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		uiTimerProcPtr = syscall.NewCallback(uiTimerProc)
+	})
+}
+
+func uiTimerProc(hwnd win.HWND, msg uint32, idEvent uintptr, dwTime uint32) uintptr {
+	uiTimersMutex.Lock()
+	t := uiTimers[idEvent]
+	uiTimersMutex.Unlock()
+
+	if t != nil {
+		t.tick()
+	}
+
+	return 0
+}
+
+// UITimer calls a handler periodically on the UI thread that created it.
+//
+// It is driven by WM_TIMER messages of the message loop, so unlike a
+// time.Timer, it needs no Synchronize and never calls its handler after it was
+// disposed. Ticks that are missed while the thread is busy are coalesced into
+// a single call, and the interval is measured from when the timer was started
+// instead of from the last tick, so lateness doesn't accumulate.
+type UITimer struct {
+	handler              func()
+	interval             time.Duration
+	tolerance            time.Duration
+	id                   uintptr
+	due                  time.Time
+	remaining            time.Duration // while paused
+	paused               bool
+	disposed             bool
+	owner                Window
+	ownerDisposingHandle int
+}
+
+// NewUITimer creates and starts a UITimer that calls handler every interval.
+//
+// It must be called on a UI thread with a message loop.
+func NewUITimer(interval time.Duration, handler func()) (*UITimer, error) {
+	if interval <= 0 {
+		return nil, newError("interval must be positive")
+	}
+
+	t := &UITimer{handler: handler, interval: interval}
+
+	t.due = time.Now().Add(interval)
+	if err := t.arm(interval); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// afterUI calls f once after d, on the UI thread, unless owner was disposed
+// before.
+func afterUI(owner Window, d time.Duration, f func()) error {
+	var t *UITimer
+	t, err := NewUITimer(d, func() {
+		t.Dispose()
+		f()
+	})
+	if err != nil {
+		return err
+	}
+
+	t.SetOwner(owner)
+
+	return nil
+}
+
+// arm schedules the next tick after d.
+func (t *UITimer) arm(d time.Duration) error {
+	if d < uiTimerMinInterval {
+		d = uiTimerMinInterval
+	}
+
+	// Round up, so ticks don't arrive before due.
+	elapse := uintptr((d + time.Millisecond - 1) / time.Millisecond)
+
+	var id uintptr
+	if setCoalescableTimer.Find() == nil {
+		id, _, _ = setCoalescableTimer.Call(0, t.id, elapse, uiTimerProcPtr, uintptr(t.tolerance/time.Millisecond))
+	} else {
+		id = win.SetTimer(0, t.id, uint32(elapse), uiTimerProcPtr)
+	}
+	if id == 0 {
+		return lastError("SetTimer")
+	}
+
+	if id != t.id {
+		uiTimersMutex.Lock()
+		if t.id != 0 {
+			delete(uiTimers, t.id)
+		}
+		uiTimers[id] = t
+		uiTimersMutex.Unlock()
+
+		t.id = id
+	}
+
+	return nil
+}
+
+// kill stops the native timer.
+func (t *UITimer) kill() {
+	if t.id == 0 {
+		return
+	}
+
+	win.KillTimer(0, t.id)
+
+	uiTimersMutex.Lock()
+	delete(uiTimers, t.id)
+	uiTimersMutex.Unlock()
+
+	t.id = 0
+}
+
+func (t *UITimer) tick() {
+	if t.disposed || t.paused {
+		return
+	}
+
+	now := time.Now()
+
+	if early := t.due.Sub(now); early > uiTimerEarlyTolerance {
+		t.arm(early)
+		return
+	}
+
+	// Skip the ticks that were missed, rather than catching up on them.
+	missed := now.Sub(t.due) / t.interval
+	t.due = t.due.Add((missed + 1) * t.interval)
+
+	// Arm before calling the handler, so it may pause, restart or dispose
+	// the timer.
+	t.arm(t.due.Sub(now))
+
+	if t.handler != nil {
+		t.handler()
+	}
+}
+
+// Interval returns the time between the calls of the handler.
+func (t *UITimer) Interval() time.Duration {
+	return t.interval
+}
+
+// SetInterval sets the time between the calls of the handler and restarts the
+// current period with it.
+func (t *UITimer) SetInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return newError("interval must be positive")
+	}
+
+	t.interval = interval
+
+	if t.paused || t.disposed {
+		t.remaining = interval
+		return nil
+	}
+
+	t.due = time.Now().Add(interval)
+
+	return t.arm(interval)
+}
+
+// Tolerance returns how much the system may delay ticks to coalesce them with
+// other timers and save power. Zero means the system default.
+func (t *UITimer) Tolerance() time.Duration {
+	return t.tolerance
+}
+
+// SetTolerance sets how much the system may delay ticks to coalesce them with
+// other timers and save power. Zero means the system default.
+func (t *UITimer) SetTolerance(tolerance time.Duration) error {
+	t.tolerance = tolerance
+
+	if t.paused || t.disposed {
+		return nil
+	}
+
+	return t.arm(t.due.Sub(time.Now()))
+}
+
+// Restart restarts the current period, resuming the timer if it is paused.
+func (t *UITimer) Restart() error {
+	if t.disposed {
+		return newError("timer is disposed")
+	}
+
+	t.paused = false
+	t.due = time.Now().Add(t.interval)
+
+	return t.arm(t.interval)
+}
+
+// Paused returns if the timer is paused.
+func (t *UITimer) Paused() bool {
+	return t.paused
+}
+
+// Pause stops calling the handler until Resume is called.
+func (t *UITimer) Pause() {
+	if t.paused || t.disposed {
+		return
+	}
+
+	t.paused = true
+	t.remaining = maxDuration(t.due.Sub(time.Now()), 0)
+
+	t.kill()
+}
+
+// Resume continues calling the handler, with the rest of the period that was
+// left when the timer was paused.
+func (t *UITimer) Resume() error {
+	if !t.paused || t.disposed {
+		return nil
+	}
+
+	t.paused = false
+	t.due = time.Now().Add(t.remaining)
+
+	return t.arm(t.remaining)
+}
+
+// Owner returns the window the timer is disposed with.
+func (t *UITimer) Owner() Window {
+	return t.owner
+}
+
+// SetOwner sets a window the timer is disposed with, so it doesn't outlive
+// the UI it updates.
+func (t *UITimer) SetOwner(owner Window) {
+	if t.owner != nil {
+		t.owner.Disposing().Detach(t.ownerDisposingHandle)
+	}
+
+	t.owner = nil
+
+	if owner == nil {
+		return
+	}
+
+	if owner.IsDisposed() {
+		t.Dispose()
+		return
+	}
+
+	t.owner = owner
+	t.ownerDisposingHandle = owner.Disposing().Attach(t.Dispose)
+}
+
+// Dispose stops the timer for good.
+func (t *UITimer) Dispose() {
+	if t.disposed {
+		return
+	}
+
+	t.disposed = true
+
+	t.kill()
+
+	if t.owner != nil {
+		t.owner.Disposing().Detach(t.ownerDisposingHandle)
+		t.owner = nil
+	}
+}
+
+// IsDisposed returns if Dispose was called.
+func (t *UITimer) IsDisposed() bool {
+	return t.disposed
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -296,6 +296,7 @@ var seenInApplyDPIToDescendantsDuringDPIChange map[*WindowBase]bool
 func applyDPIToDescendants(window Window, dpi int) {
 	wb := window.AsWindowBase()
 	wb.ApplyDPI(dpi)
+	wb.dpiApplied(dpi)
 
 	walkDescendants(window, func(w Window) bool {
 		if w.Handle() == wb.hWnd {
@@ -311,6 +312,7 @@ func applyDPIToDescendants(window Window, dpi int) {
 		}
 
 		w.(ApplyDPIer).ApplyDPI(dpi)
+		w.AsWindowBase().dpiApplied(dpi)
 
 		return true
 	})
@@ -508,7 +510,7 @@ func less(a, b interface{}, order SortOrder) bool {
 
 func dpiForHDC(hdc win.HDC) int {
 	if hwnd := win.WindowFromDC(hdc); hwnd != 0 {
-		return int(win.GetDpiForWindow(hwnd))
+		return dpiForWindow(hwnd)
 	}
 
 	return int(win.GetDeviceCaps(hdc, win.LOGPIXELSX))
@@ -528,6 +530,23 @@ func scaleInt(value int, scale float64) int {
 	return int(math.Round(float64(value) * scale))
 }
 
+// wrapValue wraps value around into the range from min to max, as with
+// angles, where max is equivalent to min.
+func wrapValue(value, min, max float64) float64 {
+	span := max - min
+	if span <= 0 {
+		return value
+	}
+
+	if value > max {
+		return min + math.Mod(value-min, span)
+	} else if value < min {
+		return max - math.Mod(max-value, span)
+	}
+
+	return value
+}
+
 // MarginsFrom96DPI converts from 1/96" units to native pixels.
 func MarginsFrom96DPI(value Margins, dpi int) Margins {
 	return scaleMargins(value, float64(dpi)/96.0)
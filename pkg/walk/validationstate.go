@@ -0,0 +1,301 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// ValidationState aggregates the validation errors of the widgets of a Form,
+// whether they come from validators the widgets registered with AddValidator
+// or from the bindings of a DataBinder.
+//
+// Each error is presented by the ErrorPresenter of the ValidationState, by
+// default an ErrorAdornerPresenter.
+type ValidationState struct {
+	form                   Form
+	widget2Errors          map[Widget][]propertyError
+	widget2DisposingHandle map[Widget]int
+	errorPresenter         ErrorPresenter
+	defaultErrorPresenter  *ErrorAdornerPresenter
+	changedPublisher       EventPublisher
+}
+
+type propertyError struct {
+	prop Property
+	err  error
+}
+
+// ValidationState returns the ValidationState of the Form, which is created on
+// first use.
+func (fb *FormBase) ValidationState() *ValidationState {
+	if fb.validationState == nil {
+		vs := &ValidationState{
+			form:                   fb.window.(Form),
+			widget2Errors:          make(map[Widget][]propertyError),
+			widget2DisposingHandle: make(map[Widget]int),
+		}
+
+		if ep, err := NewErrorAdornerPresenter(); err == nil {
+			vs.errorPresenter = ep
+			vs.defaultErrorPresenter = ep
+		}
+
+		fb.validationState = vs
+	}
+
+	return fb.validationState
+}
+
+func (vs *ValidationState) dispose() {
+	if vs.defaultErrorPresenter != nil {
+		vs.defaultErrorPresenter.Dispose()
+		vs.defaultErrorPresenter = nil
+	}
+}
+
+// Valid returns whether none of the widgets of the Form has a validation
+// error.
+func (vs *ValidationState) Valid() bool {
+	return len(vs.widget2Errors) == 0
+}
+
+// Error returns the first validation error of widget, or nil.
+func (vs *ValidationState) Error(widget Widget) error {
+	if errs := vs.widget2Errors[widget]; len(errs) > 0 {
+		return errs[0].err
+	}
+
+	return nil
+}
+
+// InvalidWidgets returns the widgets that have a validation error, in the
+// order of the widget tree of the Form.
+func (vs *ValidationState) InvalidWidgets() []Widget {
+	if len(vs.widget2Errors) == 0 {
+		return nil
+	}
+
+	var widgets []Widget
+	walkDescendants(vs.form.AsFormBase().clientComposite, func(w Window) bool {
+		if widget, ok := w.(Widget); ok && vs.widget2Errors[widget] != nil {
+			widgets = append(widgets, widget)
+		}
+
+		return true
+	})
+
+	return widgets
+}
+
+// Validate validates all properties with a Validator of the widgets of the
+// Form, e.g. before an action that needs valid input, and returns whether all
+// are valid. The first invalid widget receives the keyboard focus.
+func (vs *ValidationState) Validate() bool {
+	walkDescendants(vs.form.AsFormBase().clientComposite, func(w Window) bool {
+		if widget, ok := w.(Widget); ok {
+			widget.AsWidgetBase().validate(vs)
+		}
+
+		return true
+	})
+
+	if widgets := vs.InvalidWidgets(); len(widgets) > 0 {
+		widgets[0].SetFocus()
+		return false
+	}
+
+	return true
+}
+
+// Changed returns the event that is published when a validation error was
+// added or removed.
+func (vs *ValidationState) Changed() *Event {
+	return vs.changedPublisher.Event()
+}
+
+// ErrorPresenter returns the ErrorPresenter that shows the validation errors.
+func (vs *ValidationState) ErrorPresenter() ErrorPresenter {
+	return vs.errorPresenter
+}
+
+// SetErrorPresenter sets the ErrorPresenter that shows the validation errors.
+// The ValidationState doesn't dispose of it. nil shows no errors.
+func (vs *ValidationState) SetErrorPresenter(ep ErrorPresenter) {
+	if ep == vs.errorPresenter {
+		return
+	}
+
+	for widget := range vs.widget2Errors {
+		if vs.errorPresenter != nil {
+			vs.errorPresenter.PresentError(nil, widget)
+		}
+		if ep != nil {
+			ep.PresentError(vs.Error(widget), widget)
+		}
+	}
+
+	if vs.errorPresenter == vs.defaultErrorPresenter {
+		vs.dispose()
+	}
+
+	vs.errorPresenter = ep
+}
+
+// setError sets or, if err is nil, clears the validation error of prop of
+// widget and, if present is true, presents the first error of widget.
+func (vs *ValidationState) setError(widget Widget, prop Property, err error, present bool) {
+	errs := vs.widget2Errors[widget]
+
+	index := -1
+	for i, pe := range errs {
+		if pe.prop == prop {
+			index = i
+			break
+		}
+	}
+
+	var changed bool
+	switch {
+	case err != nil && index == -1:
+		errs = append(errs, propertyError{prop, err})
+		changed = true
+
+	case err != nil:
+		changed = errs[index].err.Error() != err.Error()
+		errs[index].err = err
+
+	case index > -1:
+		errs = append(errs[:index], errs[index+1:]...)
+		changed = true
+	}
+
+	if len(errs) > 0 {
+		if vs.widget2Errors[widget] == nil {
+			vs.widget2DisposingHandle[widget] = widget.Disposing().Attach(func() {
+				vs.clear(widget)
+			})
+		}
+
+		vs.widget2Errors[widget] = errs
+	} else if vs.widget2Errors[widget] != nil {
+		widget.Disposing().Detach(vs.widget2DisposingHandle[widget])
+		delete(vs.widget2DisposingHandle, widget)
+		delete(vs.widget2Errors, widget)
+	}
+
+	if present && vs.errorPresenter != nil {
+		vs.errorPresenter.PresentError(vs.Error(widget), widget)
+	}
+
+	if changed {
+		vs.changedPublisher.Publish()
+	}
+}
+
+// clear removes the validation errors of widget.
+func (vs *ValidationState) clear(widget Widget) {
+	if vs.widget2Errors[widget] == nil {
+		return
+	}
+
+	widget.Disposing().Detach(vs.widget2DisposingHandle[widget])
+	delete(vs.widget2DisposingHandle, widget)
+	delete(vs.widget2Errors, widget)
+
+	if vs.errorPresenter != nil {
+		vs.errorPresenter.PresentError(nil, widget)
+	}
+
+	vs.changedPublisher.Publish()
+}
+
+// validationState returns the ValidationState of the Form of the widget, or
+// nil if it isn't in a Form.
+func (wb *WidgetBase) validationState() *ValidationState {
+	form := wb.Form()
+	if form == nil {
+		return nil
+	}
+
+	return form.AsFormBase().ValidationState()
+}
+
+// AddValidator adds validator to the validators of the property of the widget
+// named propertyName, e.g. "Text" or "Value". The property is validated
+// whenever it changes, and errors are reported to the ValidationState of the
+// Form.
+func (wb *WidgetBase) AddValidator(propertyName string, validator Validator) error {
+	prop := wb.Property(propertyName)
+	if prop == nil {
+		return newError("unknown property: " + propertyName)
+	}
+	if !prop.Validatable() {
+		return ErrPropertyNotValidatable
+	}
+
+	switch v := prop.Validator().(type) {
+	case nil:
+		// nop
+
+	case multiValidator:
+		validator = append(v[:len(v):len(v)], validator)
+
+	default:
+		validator = multiValidator{v, validator}
+	}
+
+	if err := prop.SetValidator(validator); err != nil {
+		return err
+	}
+
+	if wb.validatedProperties == nil {
+		wb.validatedProperties = make(map[Property]bool)
+	}
+
+	if !wb.validatedProperties[prop] {
+		wb.validatedProperties[prop] = true
+
+		if changed := prop.Changed(); changed != nil {
+			changed.Attach(func() {
+				if vs := wb.validationState(); vs != nil {
+					wb.validateProperty(vs, prop)
+				}
+			})
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the properties of the widget that have a Validator,
+// reports the errors to the ValidationState of the Form and returns the
+// first one.
+func (wb *WidgetBase) Validate() error {
+	vs := wb.validationState()
+	if vs == nil {
+		return nil
+	}
+
+	wb.validate(vs)
+
+	return vs.Error(wb.window.(Widget))
+}
+
+func (wb *WidgetBase) validate(vs *ValidationState) {
+	for _, prop := range wb.name2Property {
+		if prop.Validator() != nil {
+			wb.validateProperty(vs, prop)
+		}
+	}
+}
+
+func (wb *WidgetBase) validateProperty(vs *ValidationState, prop Property) {
+	var err error
+	if validator := prop.Validator(); validator != nil {
+		err = validator.Validate(prop.Get())
+	}
+
+	vs.setError(wb.window.(Widget), prop, err, true)
+}
@@ -11,7 +11,9 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"reflect"
 	"regexp"
+	"strings"
 )
 
 type Validator interface {
@@ -151,3 +153,68 @@ func (selectionRequiredValidator) Validate(v interface{}) error {
 
 	return nil
 }
+
+type requiredValidator struct {
+}
+
+var requiredValidatorSingleton Validator = requiredValidator{}
+
+// RequiredValidator returns a Validator that fails for nil, for text that is
+// empty or only white space and for empty slices and maps.
+func RequiredValidator() Validator {
+	return requiredValidatorSingleton
+}
+
+func (requiredValidator) Validate(v interface{}) error {
+	var empty bool
+
+	switch val := v.(type) {
+	case nil:
+		empty = true
+
+	case string:
+		empty = strings.TrimSpace(val) == ""
+
+	case fmt.Stringer:
+		empty = strings.TrimSpace(val.String()) == ""
+
+	default:
+		switch rv := reflect.ValueOf(v); rv.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Array:
+			empty = rv.Len() == 0
+
+		case reflect.Ptr, reflect.Interface:
+			empty = rv.IsNil()
+		}
+	}
+
+	if empty {
+		return NewValidationError(
+			tr("Value Required", "walk"),
+			tr("Please enter a value.", "walk"))
+	}
+
+	return nil
+}
+
+// ValidatorFunc adapts an ordinary function to a Validator, for custom
+// validation rules, e.g. to compare a value with others of the form.
+type ValidatorFunc func(v interface{}) error
+
+func (f ValidatorFunc) Validate(v interface{}) error {
+	return f(v)
+}
+
+// multiValidator fails with the error of the first of its validators that
+// fails.
+type multiValidator []Validator
+
+func (mv multiValidator) Validate(v interface{}) error {
+	for _, validator := range mv {
+		if err := validator.Validate(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+// ValueChangeSource tells how the value of a widget was changed.
+type ValueChangeSource int
+
+const (
+	// ValueChangeSourceProgrammatic is a change by calling a method like
+	// SetValue, including data binding.
+	ValueChangeSourceProgrammatic ValueChangeSource = iota
+
+	// ValueChangeSourceKeyboard is a change by typing or pressing arrow,
+	// page or home and end keys.
+	ValueChangeSourceKeyboard
+
+	// ValueChangeSourceWheel is a change by the mouse wheel.
+	ValueChangeSourceWheel
+
+	// ValueChangeSourceSpin is a change by the spin buttons of a NumberEdit.
+	ValueChangeSourceSpin
+
+	// ValueChangeSourceMouse is a change by clicking or dragging, e.g. the
+	// thumb of a Slider.
+	ValueChangeSourceMouse
+)
+
+// WheelValueMode specifies whether the mouse wheel changes the value of a
+// widget like NumberEdit, Slider or Knob under the cursor. When it doesn't, the wheel goes to
+// the parent, so a ScrollView around the widget scrolls instead.
+type WheelValueMode int
+
+const (
+	// WheelValueDefault uses App().WheelValueMode() for widgets, and for the
+	// application means WheelValueWhenFocused, so scrolling a form with the
+	// wheel doesn't change values the cursor happens to pass.
+	WheelValueDefault WheelValueMode = iota
+
+	// WheelValueAlways lets the wheel change the value, even while the widget
+	// doesn't have the keyboard focus.
+	WheelValueAlways
+
+	// WheelValueWithCtrl lets the wheel change the value only while Ctrl is
+	// held down.
+	WheelValueWithCtrl
+
+	// WheelValueNever keeps the wheel from changing the value.
+	WheelValueNever
+
+	// WheelValueWhenFocused lets the wheel change the value only while the
+	// widget has the keyboard focus.
+	WheelValueWhenFocused
+)
+
+// wheelChangesValue returns whether the mouse wheel changes the value of a
+// widget with mode at this moment, focused telling whether the widget has the
+// keyboard focus.
+func wheelChangesValue(mode WheelValueMode, focused bool) bool {
+	if mode == WheelValueDefault {
+		mode = App().WheelValueMode()
+	}
+
+	switch mode {
+	case WheelValueAlways:
+		return true
+
+	case WheelValueNever:
+		return false
+
+	case WheelValueWithCtrl:
+		return ControlDown()
+	}
+
+	return focused
+}
+
+// wheelNotches adds the rotation of the WM_MOUSEWHEEL message with wParam to
+// *accumulated and returns the whole notches it amounts to, keeping the rest,
+// so the small deltas of high-resolution wheels add up instead of being lost.
+func wheelNotches(accumulated *int, wParam uintptr) int {
+	*accumulated += int(int16(win.HIWORD(uint32(wParam))))
+	notches := *accumulated / 120
+	*accumulated -= notches * 120
+
+	return notches
+}
+
+// ValueChange describes a change of the value of a widget, as published by
+// the ValueChangedDetailed events.
+type ValueChange struct {
+	// OldValue and NewValue are of the type of the value of the widget, e.g.
+	// float64 for a NumberEdit.
+	OldValue interface{}
+	NewValue interface{}
+
+	Source ValueChangeSource
+}
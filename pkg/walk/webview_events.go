@@ -97,44 +97,6 @@ func (eventData *WebViewNavigatingEventData) SetCanceled(value bool) {
 	}
 }
 
-type WebViewNavigatingEventHandler func(eventData *WebViewNavigatingEventData)
-
-type WebViewNavigatingEvent struct {
-	handlers []WebViewNavigatingEventHandler
-}
-
-func (e *WebViewNavigatingEvent) Attach(handler WebViewNavigatingEventHandler) int {
-	for i, h := range e.handlers {
-		if h == nil {
-			e.handlers[i] = handler
-			return i
-		}
-	}
-
-	e.handlers = append(e.handlers, handler)
-	return len(e.handlers) - 1
-}
-
-func (e *WebViewNavigatingEvent) Detach(handle int) {
-	e.handlers[handle] = nil
-}
-
-type WebViewNavigatingEventPublisher struct {
-	event WebViewNavigatingEvent
-}
-
-func (p *WebViewNavigatingEventPublisher) Event() *WebViewNavigatingEvent {
-	return &p.event
-}
-
-func (p *WebViewNavigatingEventPublisher) Publish(eventData *WebViewNavigatingEventData) {
-	for _, handler := range p.event.handlers {
-		if handler != nil {
-			handler(eventData)
-		}
-	}
-}
-
 type WebViewNavigatedErrorEventData struct {
 	pDisp           *win.IDispatch
 	url             *win.VARIANT
@@ -190,44 +152,6 @@ func (eventData *WebViewNavigatedErrorEventData) SetCanceled(value bool) {
 	}
 }
 
-type WebViewNavigatedErrorEventHandler func(eventData *WebViewNavigatedErrorEventData)
-
-type WebViewNavigatedErrorEvent struct {
-	handlers []WebViewNavigatedErrorEventHandler
-}
-
-func (e *WebViewNavigatedErrorEvent) Attach(handler WebViewNavigatedErrorEventHandler) int {
-	for i, h := range e.handlers {
-		if h == nil {
-			e.handlers[i] = handler
-			return i
-		}
-	}
-
-	e.handlers = append(e.handlers, handler)
-	return len(e.handlers) - 1
-}
-
-func (e *WebViewNavigatedErrorEvent) Detach(handle int) {
-	e.handlers[handle] = nil
-}
-
-type WebViewNavigatedErrorEventPublisher struct {
-	event WebViewNavigatedErrorEvent
-}
-
-func (p *WebViewNavigatedErrorEventPublisher) Event() *WebViewNavigatedErrorEvent {
-	return &p.event
-}
-
-func (p *WebViewNavigatedErrorEventPublisher) Publish(eventData *WebViewNavigatedErrorEventData) {
-	for _, handler := range p.event.handlers {
-		if handler != nil {
-			handler(eventData)
-		}
-	}
-}
-
 type WebViewNewWindowEventData struct {
 	ppDisp         **win.IDispatch
 	cancel         *win.VARIANT_BOOL
@@ -279,44 +203,6 @@ func (eventData *WebViewNewWindowEventData) Url() string {
 	return ""
 }
 
-type WebViewNewWindowEventHandler func(eventData *WebViewNewWindowEventData)
-
-type WebViewNewWindowEvent struct {
-	handlers []WebViewNewWindowEventHandler
-}
-
-func (e *WebViewNewWindowEvent) Attach(handler WebViewNewWindowEventHandler) int {
-	for i, h := range e.handlers {
-		if h == nil {
-			e.handlers[i] = handler
-			return i
-		}
-	}
-
-	e.handlers = append(e.handlers, handler)
-	return len(e.handlers) - 1
-}
-
-func (e *WebViewNewWindowEvent) Detach(handle int) {
-	e.handlers[handle] = nil
-}
-
-type WebViewNewWindowEventPublisher struct {
-	event WebViewNewWindowEvent
-}
-
-func (p *WebViewNewWindowEventPublisher) Event() *WebViewNewWindowEvent {
-	return &p.event
-}
-
-func (p *WebViewNewWindowEventPublisher) Publish(eventData *WebViewNewWindowEventData) {
-	for _, handler := range p.event.handlers {
-		if handler != nil {
-			handler(eventData)
-		}
-	}
-}
-
 type WebViewWindowClosingEventData struct {
 	bIsChildWindow win.VARIANT_BOOL
 	cancel         *win.VARIANT_BOOL
@@ -354,41 +240,3 @@ func (eventData *WebViewWindowClosingEventData) SetCanceled(value bool) {
 		}
 	}
 }
-
-type WebViewWindowClosingEventHandler func(eventData *WebViewWindowClosingEventData)
-
-type WebViewWindowClosingEvent struct {
-	handlers []WebViewWindowClosingEventHandler
-}
-
-func (e *WebViewWindowClosingEvent) Attach(handler WebViewWindowClosingEventHandler) int {
-	for i, h := range e.handlers {
-		if h == nil {
-			e.handlers[i] = handler
-			return i
-		}
-	}
-
-	e.handlers = append(e.handlers, handler)
-	return len(e.handlers) - 1
-}
-
-func (e *WebViewWindowClosingEvent) Detach(handle int) {
-	e.handlers[handle] = nil
-}
-
-type WebViewWindowClosingEventPublisher struct {
-	event WebViewWindowClosingEvent
-}
-
-func (p *WebViewWindowClosingEventPublisher) Event() *WebViewWindowClosingEvent {
-	return &p.event
-}
-
-func (p *WebViewWindowClosingEventPublisher) Publish(eventData *WebViewWindowClosingEventData) {
-	for _, handler := range p.event.handlers {
-		if handler != nil {
-			handler(eventData)
-		}
-	}
-}
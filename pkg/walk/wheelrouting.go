@@ -0,0 +1,102 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+import (
+	"github.com/miu200521358/win"
+)
+
+// WheelRouting specifies which widget receives mouse wheel messages.
+type WheelRouting int
+
+const (
+	// WheelRoutingDefault uses the WheelRouting of the application for forms,
+	// and for the application lets Windows decide, which depends on whether
+	// inactive windows are scrolled when hovered in the mouse settings.
+	WheelRoutingDefault WheelRouting = iota
+
+	// WheelRoutingFocused sends the wheel to the focused widget.
+	WheelRoutingFocused
+
+	// WheelRoutingUnderCursor sends the wheel to the widget under the
+	// cursor.
+	WheelRoutingUnderCursor
+)
+
+// WheelRouting returns which widget of the form receives mouse wheel
+// messages.
+func (fb *FormBase) WheelRouting() WheelRouting {
+	return fb.wheelRouting
+}
+
+// SetWheelRouting sets which widget of the form receives mouse wheel
+// messages. WheelRoutingDefault uses App().WheelRouting().
+func (fb *FormBase) SetWheelRouting(routing WheelRouting) {
+	fb.wheelRouting = routing
+}
+
+// WheelRequiresFocus returns whether the Widget only receives mouse wheel
+// messages while it has the focus.
+func (wb *WidgetBase) WheelRequiresFocus() bool {
+	return wb.wheelRequiresFocus
+}
+
+// SetWheelRequiresFocus sets whether the Widget only receives mouse wheel
+// messages while it has the focus. Otherwise the wheel goes to its parent,
+// e.g. to scroll a ScrollView instead of changing the value of a NumberEdit
+// or Slider the cursor happens to pass over.
+//
+// It takes effect in forms run with Run and for the descendants of the
+// Widget as well.
+func (wb *WidgetBase) SetWheelRequiresFocus(requiresFocus bool) {
+	wb.wheelRequiresFocus = requiresFocus
+}
+
+// routeMouseWheel redirects the mouse wheel message msg according to the
+// WheelRouting of the form and the widgets that require the focus.
+func (fb *FormBase) routeMouseWheel(msg *win.MSG) {
+	routing := fb.wheelRouting
+	if routing == WheelRoutingDefault {
+		routing = App().WheelRouting()
+	}
+
+	target := msg.HWnd
+	tid := win.GetCurrentThreadId()
+	focus := win.GetFocus()
+
+	switch routing {
+	case WheelRoutingFocused:
+		if focus != 0 {
+			target = focus
+		}
+
+	case WheelRoutingUnderCursor:
+		if hwnd := win.WindowFromPoint(msg.Pt); hwnd != 0 && win.GetWindowThreadProcessId(hwnd, nil) == tid {
+			target = hwnd
+		}
+	}
+
+	// Widgets that require the focus pass the wheel on to their parent, which
+	// is where DefWindowProc would forward it anyway, if they ignored it.
+	for hwnd := target; hwnd != 0; hwnd = win.GetParent(hwnd) {
+		widget, ok := windowFromHandle(hwnd).(Widget)
+		if !ok {
+			continue
+		}
+
+		if !widget.AsWidgetBase().wheelRequiresFocus || hwnd == focus || win.IsChild(hwnd, focus) {
+			continue
+		}
+
+		target = win.GetParent(hwnd)
+	}
+
+	if target != 0 {
+		msg.HWnd = target
+	}
+}
@@ -8,6 +8,8 @@
 package walk
 
 import (
+	"time"
+
 	"github.com/miu200521358/win"
 )
 
@@ -100,6 +102,10 @@ type WidgetBase struct {
 	graphicsEffects             *WidgetGraphicsEffectList
 	alignment                   Alignment2D
 	alwaysConsumeSpace          bool
+	wheelRequiresFocus          bool
+	autoSubmit                  bool
+	autoSubmitDelay             time.Duration
+	validatedProperties         map[Property]bool
 }
 
 // InitWidget initializes a Widget.
@@ -8,6 +8,8 @@
 package walk
 
 import (
+	"reflect"
+
 	"github.com/miu200521358/win"
 )
 
@@ -170,3 +172,104 @@ func (l *WidgetList) RemoveAt(index int) error {
 
 	return nil
 }
+
+// ReplaceAll makes the list consist of widgets, in order, with as few changes
+// as possible: widgets already in the list are just moved and the remaining
+// ones are inserted. Items of the list that are not in widgets are removed and
+// disposed.
+//
+// If diffByName is true, an item of the list with the same non-empty name and
+// type as a widget in widgets is kept in its place and the widget is disposed
+// instead, so widgets can be recreated from data on each refresh without the
+// identical ones being replaced.
+//
+// Layout of the parent is suspended during the update.
+func (l *WidgetList) ReplaceAll(widgets []Widget, diffByName bool) error {
+	if w, ok := l.observer.(Window); ok {
+		w.SetSuspended(true)
+		defer w.SetSuspended(false)
+	}
+
+	final := make([]Widget, len(widgets))
+	kept := make(map[*WidgetBase]bool, len(widgets))
+
+	var byName map[string][]Widget
+	if diffByName {
+		byName = make(map[string][]Widget)
+		for _, item := range l.items {
+			widget := item.window.(Widget)
+			if name := widget.Name(); name != "" {
+				byName[name] = append(byName[name], widget)
+			}
+		}
+	}
+
+	for i, widget := range widgets {
+		final[i] = widget
+
+		if name := widget.Name(); diffByName && name != "" {
+			candidates := byName[name]
+			for j, candidate := range candidates {
+				if candidate == widget || !kept[candidate.AsWidgetBase()] && reflect.TypeOf(candidate) == reflect.TypeOf(widget) {
+					final[i] = candidate
+					byName[name] = append(candidates[:j:j], candidates[j+1:]...)
+					break
+				}
+			}
+		}
+
+		if kept[final[i].AsWidgetBase()] {
+			return newError("cannot insert same widget multiple times")
+		}
+		kept[final[i].AsWidgetBase()] = true
+	}
+
+	var obsolete []Widget
+	for _, item := range l.items {
+		if !kept[item] {
+			obsolete = append(obsolete, item.window.(Widget))
+		}
+	}
+	for _, widget := range widgets {
+		if !kept[widget.AsWidgetBase()] && !l.Contains(widget) {
+			obsolete = append(obsolete, widget)
+		}
+	}
+
+	for _, widget := range obsolete {
+		if err := l.Remove(widget); err != nil {
+			return err
+		}
+
+		widget.Dispose()
+	}
+
+	for i, widget := range final {
+		switch j := l.Index(widget); {
+		case j == i:
+
+		case j > i:
+			// Moving within the list doesn't need reparenting.
+			copy(l.items[i+1:j+1], l.items[i:j])
+			l.items[i] = widget.AsWidgetBase()
+
+		default:
+			if err := l.Insert(i, widget); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Keep the z-order, and so the tab order, in line with the list.
+	after := win.HWND_TOP
+	for _, item := range l.items {
+		win.SetWindowPos(item.hWnd, after, 0, 0, 0, 0, win.SWP_NOMOVE|win.SWP_NOSIZE|win.SWP_NOACTIVATE)
+		after = item.hWnd
+	}
+
+	if c, ok := l.observer.(Container); ok {
+		c.RequestLayout()
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package walk
+
+// WidgetPool recycles Widgets, typically Composites built for one item of a
+// dynamic list, so rebuilding the list rebinds existing windows to new data
+// instead of destroying and recreating them.
+//
+// Widgets obtained from a WidgetPool are handed back to it with Release,
+// rather than being disposed. Released Widgets are detached from their parent
+// and kept hidden until they are acquired again.
+type WidgetPool struct {
+	create  func(parent Container) (Widget, error)
+	bind    func(widget Widget, data interface{}) error
+	idle    []Widget
+	owned   map[Widget]bool
+	maxIdle int
+}
+
+// NewWidgetPool returns a new WidgetPool that creates new Widgets with create
+// and binds them to the data of an item with bind, each time they are
+// acquired.
+//
+// By default, at most 100 idle Widgets are kept.
+func NewWidgetPool(create func(parent Container) (Widget, error), bind func(widget Widget, data interface{}) error) *WidgetPool {
+	return &WidgetPool{
+		create:  create,
+		bind:    bind,
+		owned:   make(map[Widget]bool),
+		maxIdle: 100,
+	}
+}
+
+// MaxIdle returns the maximum number of idle Widgets the WidgetPool keeps.
+func (p *WidgetPool) MaxIdle() int {
+	return p.maxIdle
+}
+
+// SetMaxIdle sets the maximum number of idle Widgets the WidgetPool keeps.
+//
+// Widgets released while this many are idle are disposed.
+func (p *WidgetPool) SetMaxIdle(maxIdle int) {
+	if maxIdle < 0 {
+		maxIdle = 0
+	}
+
+	p.maxIdle = maxIdle
+
+	for len(p.idle) > maxIdle {
+		p.disposeIdle(len(p.idle) - 1)
+	}
+}
+
+// IdleCount returns the number of idle Widgets the WidgetPool keeps.
+func (p *WidgetPool) IdleCount() int {
+	return len(p.idle)
+}
+
+// Owns returns whether widget was created by the WidgetPool and is not
+// disposed yet.
+func (p *WidgetPool) Owns(widget Widget) bool {
+	return p.owned[widget]
+}
+
+// Acquire appends a Widget for data to the children of parent, reusing an
+// idle one if possible.
+func (p *WidgetPool) Acquire(parent Container, data interface{}) (Widget, error) {
+	return p.AcquireAt(parent, parent.Children().Len(), data)
+}
+
+// AcquireAt inserts a Widget for data at index into the children of parent,
+// reusing an idle one if possible.
+func (p *WidgetPool) AcquireAt(parent Container, index int, data interface{}) (Widget, error) {
+	var widget Widget
+
+	if n := len(p.idle); n > 0 {
+		widget = p.idle[n-1]
+		p.idle = p.idle[:n-1]
+
+		if err := parent.Children().Insert(index, widget); err != nil {
+			p.idle = append(p.idle, widget)
+			return nil, err
+		}
+
+		widget.SetVisible(true)
+	} else {
+		var err error
+		if widget, err = p.create(parent); err != nil {
+			return nil, err
+		}
+
+		// create adds the Widget to the end of the children of parent.
+		if children := parent.Children(); index < children.Len()-1 {
+			if err := children.Remove(widget); err != nil {
+				widget.Dispose()
+				return nil, err
+			}
+			if err := children.Insert(index, widget); err != nil {
+				widget.Dispose()
+				return nil, err
+			}
+		}
+
+		p.owned[widget] = true
+		widget.Disposing().Attach(func() {
+			p.forget(widget)
+		})
+	}
+
+	if p.bind != nil {
+		if err := p.bind(widget, data); err != nil {
+			p.Release(widget)
+			return nil, err
+		}
+	}
+
+	return widget, nil
+}
+
+// Release detaches widget from its parent and keeps it for reuse, or disposes
+// it if the WidgetPool already keeps MaxIdle Widgets.
+func (p *WidgetPool) Release(widget Widget) error {
+	if !p.Owns(widget) {
+		return newError("widget not owned by pool")
+	}
+
+	for _, w := range p.idle {
+		if w == widget {
+			return nil
+		}
+	}
+
+	if len(p.idle) >= p.maxIdle {
+		widget.Dispose()
+		return nil
+	}
+
+	if parent := widget.Parent(); parent != nil {
+		if err := parent.Children().Remove(widget); err != nil {
+			return err
+		}
+	}
+
+	p.idle = append(p.idle, widget)
+
+	return nil
+}
+
+// ReleaseChildren releases all children of parent that are owned by the
+// WidgetPool.
+func (p *WidgetPool) ReleaseChildren(parent Container) error {
+	children := parent.Children()
+
+	for i := children.Len() - 1; i >= 0; i-- {
+		if widget := children.At(i); p.Owns(widget) {
+			if err := p.Release(widget); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Rebind makes the children of parent that are owned by the WidgetPool show
+// items, in order, rebinding the existing ones and acquiring or releasing the
+// difference.
+//
+// Children of parent not owned by the WidgetPool are left alone, additional
+// pooled ones are appended. Layout of parent is suspended during the update.
+func (p *WidgetPool) Rebind(parent Container, items []interface{}) error {
+	parent.SetSuspended(true)
+	defer parent.SetSuspended(false)
+
+	var pooled []Widget
+	children := parent.Children()
+	for i := 0; i < children.Len(); i++ {
+		if widget := children.At(i); p.Owns(widget) {
+			pooled = append(pooled, widget)
+		}
+	}
+
+	for i := len(pooled) - 1; i >= len(items); i-- {
+		if err := p.Release(pooled[i]); err != nil {
+			return err
+		}
+	}
+
+	for i, data := range items {
+		if i < len(pooled) {
+			if p.bind != nil {
+				if err := p.bind(pooled[i], data); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if _, err := p.Acquire(parent, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dispose disposes the idle Widgets of the WidgetPool. Acquired Widgets are
+// disposed with their parents as usual.
+func (p *WidgetPool) Dispose() {
+	for len(p.idle) > 0 {
+		p.disposeIdle(len(p.idle) - 1)
+	}
+}
+
+func (p *WidgetPool) disposeIdle(index int) {
+	widget := p.idle[index]
+	p.idle = append(p.idle[:index], p.idle[index+1:]...)
+
+	widget.Dispose()
+}
+
+// forget drops a disposed Widget from the WidgetPool.
+func (p *WidgetPool) forget(widget Widget) {
+	delete(p.owned, widget)
+
+	for i, w := range p.idle {
+		if w == widget {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			break
+		}
+	}
+}
@@ -36,6 +36,9 @@ type Window interface {
 	// struct that implements most operations common to all windows.
 	AsWindowBase() *WindowBase
 
+	// AcceptsDrops returns whether the Window accepts data dragged onto it.
+	AcceptsDrops() bool
+
 	// Accessibility returns the accessibility object used to set Dynamic Annotation properties of the
 	// window.
 	Accessibility() *Accessibility
@@ -117,6 +120,22 @@ type Window interface {
 	// DPI returns the current DPI value of the Window.
 	DPI() int
 
+	// DragEnter returns a *DragEvent that is published when data is dragged
+	// into the Window. See SetAcceptsDrops.
+	DragEnter() *DragEvent
+
+	// DragLeave returns an *Event that is published when dragged data leaves
+	// the Window without being dropped.
+	DragLeave() *Event
+
+	// DragOver returns a *DragEvent that is published when dragged data moves
+	// over the Window.
+	DragOver() *DragEvent
+
+	// Drop returns a *DragEvent that is published when data is dropped onto
+	// the Window.
+	Drop() *DragEvent
+
 	// Enabled returns if the Window is enabled for user interaction.
 	Enabled() bool
 
@@ -218,6 +237,9 @@ type Window interface {
 	// SendMessage sends a message to the window and returns the result.
 	SendMessage(msg uint32, wParam, lParam uintptr) uintptr
 
+	// SetAcceptsDrops sets whether the Window accepts data dragged onto it.
+	SetAcceptsDrops(accepts bool) error
+
 	// SetBackground sets the background Brush of the Window.
 	SetBackground(value Brush)
 
@@ -417,6 +439,11 @@ type WindowBase struct {
 	disposables               []Disposable
 	disposingPublisher        EventPublisher
 	dropFilesPublisher        DropFilesEventPublisher
+	dropTarget                *dropTarget
+	dragEnterPublisher        DragEventPublisher
+	dragOverPublisher         DragEventPublisher
+	dragLeavePublisher        EventPublisher
+	dropPublisher             DragEventPublisher
 	keyDownPublisher          KeyEventPublisher
 	keyPressPublisher         KeyEventPublisher
 	keyUpPublisher            KeyEventPublisher
@@ -426,6 +453,8 @@ type WindowBase struct {
 	mouseWheelPublisher       MouseEventPublisher
 	boundsChangedPublisher    EventPublisher
 	sizeChangedPublisher      EventPublisher
+	dpiChangedPublisher       DPIChangedEventPublisher
+	dpi                       int // applied last
 	maxSize96dpi              Size
 	minSize96dpi              Size
 	background                Brush
@@ -441,6 +470,7 @@ type WindowBase struct {
 	suspended                 bool
 	visible                   bool
 	enabled                   bool
+	darkThemeApplied          bool
 	acc                       *Accessibility
 }
 
@@ -552,7 +582,7 @@ func initWindowWithCfg(cfg *windowCfg) error {
 
 		var initCtrls win.INITCOMMONCONTROLSEX
 		initCtrls.DwSize = uint32(unsafe.Sizeof(initCtrls))
-		initCtrls.DwICC = win.ICC_LINK_CLASS | win.ICC_LISTVIEW_CLASSES | win.ICC_PROGRESS_CLASS | win.ICC_TAB_CLASSES | win.ICC_TREEVIEW_CLASSES
+		initCtrls.DwICC = win.ICC_DATE_CLASSES | win.ICC_LINK_CLASS | win.ICC_LISTVIEW_CLASSES | win.ICC_PROGRESS_CLASS | win.ICC_TAB_CLASSES | win.ICC_TREEVIEW_CLASSES
 		win.InitCommonControlsEx(&initCtrls)
 
 		defaultWndProcPtr = syscall.NewCallback(defaultWndProc)
@@ -658,6 +688,10 @@ func initWindowWithCfg(cfg *windowCfg) error {
 
 	SetWindowFont(wb.hWnd, defaultFont)
 
+	wb.dpi = dpiForWindow(wb.hWnd)
+
+	wb.applyTheme()
+
 	if form, ok := cfg.Window.(Form); ok {
 		if fb := form.AsFormBase(); fb != nil {
 			if err := fb.init(form); err != nil {
@@ -822,6 +856,8 @@ func (wb *WindowBase) Handle() win.HWND {
 
 // SendMessage sends a message to the window and returns the result.
 func (wb *WindowBase) SendMessage(msg uint32, wParam, lParam uintptr) uintptr {
+	checkUIThread(wb)
+
 	return win.SendMessage(wb.hWnd, msg, wParam, lParam)
 }
 
@@ -886,10 +922,14 @@ func (wb *WindowBase) Dispose() {
 	if hWnd != 0 {
 		wb.disposingPublisher.Publish()
 
+		wb.revokeDropTarget()
+
 		wb.hWnd = 0
 		if _, ok := hwnd2WindowBase[hWnd]; ok {
 			win.DestroyWindow(hWnd)
 		}
+
+		wb.group.failSyncCalls(wb)
 	}
 
 	if cm := wb.contextMenu; cm != nil {
@@ -1015,12 +1055,14 @@ type ApplySysColorser interface {
 }
 
 func (wb *WindowBase) ApplySysColors() {
+	wb.applyTheme()
+
 	wb.Invalidate()
 }
 
 // DPI returns the current DPI value of the WindowBase.
 func (wb *WindowBase) DPI() int {
-	return int(win.GetDpiForWindow(wb.hWnd))
+	return dpiForWindow(wb.hWnd)
 }
 
 type ApplyDPIer interface {
@@ -1090,6 +1132,8 @@ func (wb *WindowBase) Enabled() bool {
 
 // SetEnabled sets if the *WindowBase is enabled for user interaction.
 func (wb *WindowBase) SetEnabled(enabled bool) {
+	checkUIThread(wb)
+
 	wb.enabled = enabled
 
 	wb.window.(applyEnableder).applyEnabled(wb.window.Enabled())
@@ -1128,6 +1172,8 @@ func (wb *WindowBase) Font() *Font {
 
 // SetFont sets the *Font of the *WindowBase.
 func (wb *WindowBase) SetFont(font *Font) {
+	checkUIThread(wb)
+
 	if font != wb.font {
 		wb.font = font
 
@@ -1156,7 +1202,7 @@ func (wb *WindowBase) applyFont(font *Font) {
 }
 
 func SetWindowFont(hwnd win.HWND, font *Font) {
-	dpi := int(win.GetDpiForWindow(hwnd))
+	dpi := dpiForWindow(hwnd)
 	setWindowFont(hwnd, font.handleForDPI(dpi))
 }
 
@@ -1343,6 +1389,8 @@ func (wb *WindowBase) Visible() bool {
 
 // SetVisible sets if the *WindowBase is visible.
 func (wb *WindowBase) SetVisible(visible bool) {
+	checkUIThread(wb)
+
 	old := wb.Visible()
 
 	setWindowVisible(wb.hWnd, visible)
@@ -1374,6 +1422,29 @@ func (wb *WindowBase) VisibleChanged() *Event {
 	return wb.visibleChangedPublisher.Event()
 }
 
+// OnceVisible calls f the first time window is actually visible, i.e. it and
+// all its ancestors are visible, as happens when its tab page is selected for
+// the first time. If window is visible already, f is called immediately.
+//
+// Use it to defer expensive initialization until window is needed.
+func OnceVisible(window Window, f func()) {
+	if window.Visible() {
+		f()
+		return
+	}
+
+	var handle int
+	handle = window.VisibleChanged().Attach(func() {
+		if !window.Visible() {
+			return
+		}
+
+		window.VisibleChanged().Detach(handle)
+
+		f()
+	})
+}
+
 func setWindowVisible(hwnd win.HWND, visible bool) {
 	var cmd int32
 	if visible {
@@ -1431,6 +1502,8 @@ func (wb *WindowBase) BoundsPixels() Rectangle {
 // For a Form, like *MainWindow or *Dialog, the rectangle is in screen
 // coordinates, for a child Window the coordinates are relative to its parent.
 func (wb *WindowBase) SetBoundsPixels(bounds Rectangle) error {
+	checkUIThread(wb)
+
 	if !win.MoveWindow(
 		wb.hWnd,
 		int32(bounds.X),
@@ -1533,6 +1606,12 @@ func (wb *WindowBase) dialogBaseUnits() Size {
 		return s
 	}
 
+	takeMeasurePrefetches(fi)
+
+	if s, ok := fontInfoAndDPI2DialogBaseUnits[fi]; ok {
+		return s
+	}
+
 	hdc := win.GetDC(wb.hWnd)
 	defer win.ReleaseDC(wb.hWnd, hdc)
 
@@ -1540,9 +1619,19 @@ func (wb *WindowBase) dialogBaseUnits() Size {
 	hFontOld := win.SelectObject(hdc, win.HGDIOBJ(hFont))
 	defer win.SelectObject(hdc, win.HGDIOBJ(hFontOld))
 
+	s := dialogBaseUnitsOfDC(hdc, newError)
+
+	fontInfoAndDPI2DialogBaseUnits[fi] = s
+
+	return s
+}
+
+// dialogBaseUnitsOfDC returns the dialog base units of the font selected into
+// hdc. Failures are reported by newErr.
+func dialogBaseUnitsOfDC(hdc win.HDC, newErr func(message string) error) Size {
 	var tm win.TEXTMETRIC
 	if !win.GetTextMetrics(hdc, &tm) {
-		newError("GetTextMetrics failed")
+		newErr("GetTextMetrics failed")
 	}
 
 	var size win.SIZE
@@ -1551,14 +1640,10 @@ func (wb *WindowBase) dialogBaseUnits() Size {
 		dialogBaseUnitsUTF16StringPtr,
 		52,
 		&size) {
-		newError("GetTextExtentPoint32 failed")
+		newErr("GetTextExtentPoint32 failed")
 	}
 
-	s := Size{int((size.CX/26 + 1) / 2), int(tm.TmHeight)}
-
-	fontInfoAndDPI2DialogBaseUnits[fi] = s
-
-	return s
+	return Size{int((size.CX/26 + 1) / 2), int(tm.TmHeight)}
 }
 
 // dialogBaseUnitsToPixels returns size in dialog based units in native pixels.
@@ -1602,7 +1687,13 @@ func (wb *WindowBase) calculateTextSizeImplForWidth(text string, width int) Size
 		return size
 	}
 
-	size := calculateTextSize(text, font, dpi, width, wb.hWnd)
+	size, ok := Size{}, false
+	if width == 0 {
+		size, ok = prefetchedTextSize(key.font, dpi, text)
+	}
+	if !ok {
+		size = calculateTextSize(text, font, dpi, width, wb.hWnd)
+	}
 
 	wb.calcTextSizeInfo2TextSize[key] = size
 
@@ -1646,20 +1737,30 @@ func calculateTextSize(text string, font *Font, dpi int, width int, hwnd win.HWN
 		hFontOld := win.SelectObject(hdc, win.HGDIOBJ(font.handleForDPI(dpi)))
 		defer win.SelectObject(hdc, hFontOld)
 
-		lines := strings.Split(text, "\n")
+		size = textExtentOfDC(hdc, text, newError)
+	}
 
-		for _, line := range lines {
-			var s win.SIZE
-			str := syscall.StringToUTF16(strings.TrimRight(line, "\r "))
+	return size
+}
 
-			if !win.GetTextExtentPoint32(hdc, &str[0], int32(len(str)-1), &s) {
-				newError("GetTextExtentPoint32 failed")
-				return Size{}
-			}
+// textExtentOfDC returns the size of text, which may have several lines,
+// drawn with the font selected into hdc. Failures are reported by newErr.
+func textExtentOfDC(hdc win.HDC, text string, newErr func(message string) error) Size {
+	var size Size
+
+	lines := strings.Split(text, "\n")
 
-			size.Width = maxi(size.Width, int(s.CX))
-			size.Height += int(s.CY)
+	for _, line := range lines {
+		var s win.SIZE
+		str := syscall.StringToUTF16(strings.TrimRight(line, "\r "))
+
+		if !win.GetTextExtentPoint32(hdc, &str[0], int32(len(str)-1), &s) {
+			newErr("GetTextExtentPoint32 failed")
+			return Size{}
 		}
+
+		size.Width = maxi(size.Width, int(s.CX))
+		size.Height += int(s.CY)
 	}
 
 	return size
@@ -1980,7 +2081,7 @@ func (wb *WindowBase) CreateCanvas() (*Canvas, error) {
 }
 
 func (wb *WindowBase) setTheme(appName string) error {
-	if hr := win.SetWindowTheme(wb.hWnd, syscall.StringToUTF16Ptr(appName), nil); win.FAILED(hr) {
+	if hr := setWindowTheme(wb.hWnd, appName); win.FAILED(hr) {
 		return errorFromHRESULT("SetWindowTheme", hr)
 	}
 
@@ -2281,9 +2382,11 @@ func (wb *WindowBase) handleWMCTLCOLOR(wParam, lParam uintptr) uintptr {
 	} else if tc, ok := wnd.(TextColorer); ok {
 		color := tc.TextColor()
 		if color == 0 {
-			color = Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+			color = themeSysColor(win.COLOR_WINDOWTEXT)
 		}
 		win.SetTextColor(hdc, win.COLORREF(color))
+	} else if appTheme != nil {
+		win.SetTextColor(hdc, win.COLORREF(themeSysColor(win.COLOR_BTNTEXT)))
 	}
 
 	if bg, wnd := wnd.AsWindowBase().backgroundEffective(); bg != nil {
@@ -2301,7 +2404,7 @@ func (wb *WindowBase) handleWMCTLCOLOR(wParam, lParam uintptr) uintptr {
 	}
 
 	switch wnd.(type) {
-	case *LineEdit, *numberLineEdit, *TextEdit:
+	case *LineEdit, *numberLineEdit, *timecodeLineEdit, *tokenLineEdit, *TextEdit, *ListBox:
 		type ReadOnlyer interface {
 			ReadOnly() bool
 		}
@@ -2313,9 +2416,15 @@ func (wb *WindowBase) handleWMCTLCOLOR(wParam, lParam uintptr) uintptr {
 			sysColor = win.COLOR_WINDOW
 		}
 
-		win.SetBkColor(hdc, win.COLORREF(win.GetSysColor(sysColor)))
+		win.SetBkColor(hdc, win.COLORREF(themeSysColor(sysColor)))
 
-		return uintptr(win.GetSysColorBrush(sysColor))
+		return uintptr(themeSysColorBrush(sysColor))
+	}
+
+	if appTheme != nil {
+		win.SetBkColor(hdc, win.COLORREF(themeSysColor(win.COLOR_BTNFACE)))
+
+		return uintptr(themeSysColorBrush(win.COLOR_BTNFACE))
 	}
 
 	return 0
@@ -2335,6 +2444,12 @@ func (wb *WindowBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr)
 		}
 
 		bg, wnd := wb.backgroundEffective()
+		if bg == nil && appTheme != nil && wb.origWndProcPtr == 0 {
+			// Instead of the system color of the window class.
+			if brush := appTheme.brush(ThemeRoleWindow); brush != nil {
+				bg = brush
+			}
+		}
 		if bg == nil {
 			break
 		}
@@ -97,9 +97,16 @@ type WindowGroup struct {
 	activeForm      Form
 	oleInit         bool
 	accPropServices *win.IAccPropServices
+	modelessDialogs []win.HWND    // Dialogs not owned by a Form, e.g. the find and replace dialog
+	hangDetector    *HangDetector // Watches the message loops of the thread, see Application.SetWatchdogThreshold
+
+	animationFrameClock *animationFrameClock // Steps the running animations of the thread
 
 	syncMutex           sync.Mutex
 	syncFuncs           []func()                   // Functions queued to run on the group's thread
+	syncCalls           map[*syncCall]bool         // Calls of SynchronizeContext waited for
+	syncClosed          bool                       // Set once the group is disposed, to fail new calls
+	loops               int                        // Number of message loops running on the group's thread
 	layoutResultsByForm map[Form]*formLayoutResult // Layout computations queued for application on the group's thread
 }
 
@@ -165,6 +172,34 @@ func (g *WindowGroup) accClearHwndProps(hwnd win.HWND) {
 	}
 }
 
+// addModelessDialog makes the message loops of the group's thread handle
+// keyboard navigation in the modeless dialog hwnd.
+func (g *WindowGroup) addModelessDialog(hwnd win.HWND) {
+	g.modelessDialogs = append(g.modelessDialogs, hwnd)
+}
+
+// removeModelessDialog reverts addModelessDialog.
+func (g *WindowGroup) removeModelessDialog(hwnd win.HWND) {
+	for i, h := range g.modelessDialogs {
+		if h == hwnd {
+			g.modelessDialogs = append(g.modelessDialogs[:i], g.modelessDialogs[i+1:]...)
+			return
+		}
+	}
+}
+
+// isModelessDialogMessage processes msg if it is for one of the modeless
+// dialogs of the group and returns whether it did.
+func (g *WindowGroup) isModelessDialogMessage(msg *win.MSG) bool {
+	for _, hwnd := range g.modelessDialogs {
+		if win.IsDialogMessage(hwnd, msg) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Add changes the group's reference counter by delta, which may be negative.
 //
 // If the reference counter becomes zero the group will be disposed of.
@@ -199,6 +234,21 @@ func (g *WindowGroup) Synchronize(f func()) {
 	g.syncFuncs = append(g.syncFuncs, f)
 }
 
+// wake makes the message loop of the group's thread run the functions queued
+// by Synchronize soon, even if no other messages arrive.
+func (g *WindowGroup) wake() {
+	var hwnd win.HWND
+	if g.activeForm != nil {
+		hwnd = g.activeForm.Handle()
+	} else if g.toolTip != nil {
+		hwnd = g.toolTip.Handle()
+	}
+
+	if hwnd != 0 {
+		win.PostMessage(hwnd, syncMsgId, 0, 0)
+	}
+}
+
 // synchronizeLayout causes the given layout computations to be applied
 // later by the message loop running on the group's thread.
 //
@@ -318,6 +368,11 @@ func (g *WindowGroup) dispose() {
 		g.toolTip.Dispose()
 		g.toolTip = nil
 	}
+	g.syncMutex.Lock()
+	g.syncClosed = true
+	g.syncMutex.Unlock()
+	g.failSyncCalls(nil)
+
 	g.removed = true // race detection only
 	g.completion(g.threadID)
 }